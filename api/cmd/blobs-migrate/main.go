@@ -0,0 +1,84 @@
+// blobs-migrate moves the content of all blobs from one blob store to another.
+//
+// For example, to move the blobs from Postgres to a MinIO server:
+//
+//	blobs-migrate --db.url=postgres://... \
+//		--from.backend=postgres \
+//		--to.backend=s3 --to.s3.endpoint=http://minio:9000 --to.s3.bucket=blobs --to.s3.path-style
+//
+// Start the API with the new backend after the migration has finished.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	db_blobs "getsturdy.com/api/pkg/blobs/db"
+	service_blobs "getsturdy.com/api/pkg/blobs/service"
+	"getsturdy.com/api/pkg/blobs/store"
+	blobs "getsturdy.com/api/pkg/blobs/store/configuration"
+	"getsturdy.com/api/pkg/db"
+	configuration_db "getsturdy.com/api/pkg/db/configuration"
+
+	"github.com/jessevdk/go-flags"
+	"go.uber.org/zap"
+)
+
+type options struct {
+	DB           *configuration_db.Configuration `flags-group:"db" namespace:"db"`
+	From         *blobs.Configuration            `flags-group:"from" namespace:"from" env-namespace:"STURDY_BLOBS_FROM"`
+	To           *blobs.Configuration            `flags-group:"to" namespace:"to" env-namespace:"STURDY_BLOBS_TO"`
+	DeleteSource bool                            `long:"delete-source" description:"Delete the content of the blobs from the source after they have been copied"`
+}
+
+func main() {
+	var opts options
+	parser := flags.NewParser(&opts, flags.HelpFlag)
+	var flagsErr *flags.Error
+	if _, err := parser.Parse(); errors.As(err, &flagsErr) && flagsErr.Type == flags.ErrHelp {
+		fmt.Fprintln(os.Stdout, err.Error())
+		os.Exit(0)
+	} else if err != nil {
+		log.Fatalf("failed to parse flags: %+v", err)
+	}
+
+	if opts.From.Overlaps(opts.To) {
+		log.Fatalf("source and destination are the same")
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("failed to create logger: %+v", err)
+	}
+
+	database, err := db.FromConfiguration(opts.DB)
+	if err != nil {
+		logger.Fatal("failed to connect to the database", zap.Error(err))
+	}
+
+	from, err := store.New(opts.From, database)
+	if err != nil {
+		logger.Fatal("failed to create source store", zap.Error(err))
+	}
+
+	to, err := store.New(opts.To, database)
+	if err != nil {
+		logger.Fatal("failed to create destination store", zap.Error(err))
+	}
+
+	blobsService := service_blobs.New(logger, db_blobs.NewDatabase(database), from)
+	result, err := blobsService.MigrateTo(context.Background(), to, opts.DeleteSource)
+	if err != nil {
+		logger.Fatal("failed to migrate blobs", zap.Error(err))
+	}
+
+	logger.Info("migrated blobs",
+		zap.String("from", opts.From.Backend),
+		zap.String("to", opts.To.Backend),
+		zap.Int("copied", result.Copied),
+		zap.Int("missing", result.Missing),
+	)
+}
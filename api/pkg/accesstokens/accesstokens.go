@@ -0,0 +1,93 @@
+package accesstokens
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"time"
+
+	"getsturdy.com/api/pkg/users"
+
+	"github.com/lib/pq"
+)
+
+type ID string
+
+func (id ID) String() string {
+	return string(id)
+}
+
+type Scope string
+
+const (
+	ScopeUndefined Scope = ""
+	// ScopeReadCodebase allows reading codebases, workspaces and changes, and to clone and fetch them.
+	ScopeReadCodebase Scope = "read_codebase"
+	// ScopeWriteWorkspace allows creating and modifying workspaces, it implies ScopeReadCodebase.
+	ScopeWriteWorkspace Scope = "write_workspace"
+	// ScopeLand allows landing workspaces, and pushing to trunk, it implies ScopeReadCodebase.
+	ScopeLand Scope = "land"
+	// ScopeAdmin allows everything, including managing organizations, and other access tokens.
+	ScopeAdmin Scope = "admin"
+)
+
+func (s Scope) Valid() bool {
+	switch s {
+	case ScopeReadCodebase, ScopeWriteWorkspace, ScopeLand, ScopeAdmin:
+		return true
+	default:
+		return false
+	}
+}
+
+// Token is a personal access token, that a user can use to authenticate instead of a session.
+type Token struct {
+	ID     ID       `db:"id"`
+	UserID users.ID `db:"user_id"`
+	Name   string   `db:"name"`
+	// Hash is the sha256 of the secret part of the token. Secrets are random and long enough that they
+	// don't need a slow hash.
+	Hash       []byte         `db:"hash"`
+	Scopes     pq.StringArray `db:"scopes"`
+	CreatedAt  time.Time      `db:"created_at"`
+	ExpiresAt  *time.Time     `db:"expires_at"`
+	LastUsedAt *time.Time     `db:"last_used_at"`
+	RevokedAt  *time.Time     `db:"revoked_at"`
+}
+
+func Hash(secret string) []byte {
+	h := sha256.Sum256([]byte(secret))
+	return h[:]
+}
+
+func (t *Token) Verify(secret string) bool {
+	return subtle.ConstantTimeCompare(t.Hash, Hash(secret)) == 1
+}
+
+func (t *Token) IsExpired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
+
+func (t *Token) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
+func (t *Token) ScopeList() []Scope {
+	scopes := make([]Scope, 0, len(t.Scopes))
+	for _, s := range t.Scopes {
+		scopes = append(scopes, Scope(s))
+	}
+	return scopes
+}
+
+// HasScope returns true if the token has been granted the scope, directly or implied by another scope.
+func HasScope(granted []Scope, scope Scope) bool {
+	for _, g := range granted {
+		switch {
+		case g == scope, g == ScopeAdmin:
+			return true
+		case (g == ScopeWriteWorkspace || g == ScopeLand) && scope == ScopeReadCodebase:
+			return true
+		}
+	}
+	return false
+}
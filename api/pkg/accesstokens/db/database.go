@@ -0,0 +1,71 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"getsturdy.com/api/pkg/accesstokens"
+	"getsturdy.com/api/pkg/users"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var _ Repository = &database{}
+
+type database struct {
+	db *sqlx.DB
+}
+
+func New(db *sqlx.DB) Repository {
+	return &database{db: db}
+}
+
+func (d *database) Create(ctx context.Context, token *accesstokens.Token) error {
+	if _, err := d.db.NamedExecContext(ctx, `INSERT INTO access_tokens
+		(id, user_id, name, hash, scopes, created_at, expires_at, last_used_at, revoked_at)
+		VALUES
+		(:id, :user_id, :name, :hash, :scopes, :created_at, :expires_at, :last_used_at, :revoked_at)`, token); err != nil {
+		return fmt.Errorf("failed to insert: %w", err)
+	}
+	return nil
+}
+
+func (d *database) Get(ctx context.Context, id accesstokens.ID) (*accesstokens.Token, error) {
+	token := &accesstokens.Token{}
+	if err := d.db.GetContext(ctx, token, `SELECT id, user_id, name, hash, scopes, created_at, expires_at, last_used_at, revoked_at
+		FROM access_tokens
+		WHERE id = $1`, id); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+	return token, nil
+}
+
+func (d *database) ListByUserID(ctx context.Context, userID users.ID) ([]*accesstokens.Token, error) {
+	var tokens []*accesstokens.Token
+	if err := d.db.SelectContext(ctx, &tokens, `SELECT id, user_id, name, hash, scopes, created_at, expires_at, last_used_at, revoked_at
+		FROM access_tokens
+		WHERE user_id = $1
+		ORDER BY created_at DESC`, userID); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+	return tokens, nil
+}
+
+func (d *database) Revoke(ctx context.Context, id accesstokens.ID, at time.Time) error {
+	if _, err := d.db.ExecContext(ctx, `UPDATE access_tokens
+		SET revoked_at = $2
+		WHERE id = $1 AND revoked_at IS NULL`, id, at); err != nil {
+		return fmt.Errorf("failed to update: %w", err)
+	}
+	return nil
+}
+
+func (d *database) SetLastUsedAt(ctx context.Context, id accesstokens.ID, at time.Time) error {
+	if _, err := d.db.ExecContext(ctx, `UPDATE access_tokens
+		SET last_used_at = $2
+		WHERE id = $1`, id, at); err != nil {
+		return fmt.Errorf("failed to update: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+	"time"
+
+	"getsturdy.com/api/pkg/accesstokens"
+	"getsturdy.com/api/pkg/users"
+)
+
+var _ Repository = &memory{}
+
+type memory struct {
+	mu   sync.RWMutex
+	byID map[accesstokens.ID]accesstokens.Token
+}
+
+func NewMemory() Repository {
+	return &memory{
+		byID: make(map[accesstokens.ID]accesstokens.Token),
+	}
+}
+
+func (m *memory) Create(_ context.Context, token *accesstokens.Token) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.byID[token.ID] = *token
+	return nil
+}
+
+func (m *memory) Get(_ context.Context, id accesstokens.ID) (*accesstokens.Token, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	token, ok := m.byID[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &token, nil
+}
+
+func (m *memory) ListByUserID(_ context.Context, userID users.ID) ([]*accesstokens.Token, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var tokens []*accesstokens.Token
+	for _, token := range m.byID {
+		if token.UserID == userID {
+			token := token
+			tokens = append(tokens, &token)
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].CreatedAt.After(tokens[j].CreatedAt)
+	})
+	return tokens, nil
+}
+
+func (m *memory) Revoke(_ context.Context, id accesstokens.ID, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	token, ok := m.byID[id]
+	if !ok || token.RevokedAt != nil {
+		return nil
+	}
+	token.RevokedAt = &at
+	m.byID[id] = token
+	return nil
+}
+
+func (m *memory) SetLastUsedAt(_ context.Context, id accesstokens.ID, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	token, ok := m.byID[id]
+	if !ok {
+		return nil
+	}
+	token.LastUsedAt = &at
+	m.byID[id] = token
+	return nil
+}
@@ -0,0 +1,15 @@
+package db
+
+import (
+	"getsturdy.com/api/pkg/db"
+	"getsturdy.com/api/pkg/di"
+)
+
+func Module(c *di.Container) {
+	c.Import(db.Module)
+	c.Register(New)
+}
+
+func TestModule(c *di.Container) {
+	c.Register(NewMemory)
+}
@@ -0,0 +1,17 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"getsturdy.com/api/pkg/accesstokens"
+	"getsturdy.com/api/pkg/users"
+)
+
+type Repository interface {
+	Create(context.Context, *accesstokens.Token) error
+	Get(context.Context, accesstokens.ID) (*accesstokens.Token, error)
+	ListByUserID(context.Context, users.ID) ([]*accesstokens.Token, error)
+	Revoke(context.Context, accesstokens.ID, time.Time) error
+	SetLastUsedAt(context.Context, accesstokens.ID, time.Time) error
+}
@@ -0,0 +1,194 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"getsturdy.com/api/pkg/accesstokens"
+	service_accesstokens "getsturdy.com/api/pkg/accesstokens/service"
+	"getsturdy.com/api/pkg/auth"
+	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
+	"getsturdy.com/api/pkg/graphql/resolvers"
+	"getsturdy.com/api/pkg/users"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+type rootResolver struct {
+	accessTokensService *service_accesstokens.Service
+}
+
+func New(
+	accessTokensService *service_accesstokens.Service,
+) resolvers.AccessTokensRootResolver {
+	return &rootResolver{
+		accessTokensService: accessTokensService,
+	}
+}
+
+var (
+	toScope = map[resolvers.AccessTokenScope]accesstokens.Scope{
+		resolvers.AccessTokenScopeReadCodebase:   accesstokens.ScopeReadCodebase,
+		resolvers.AccessTokenScopeWriteWorkspace: accesstokens.ScopeWriteWorkspace,
+		resolvers.AccessTokenScopeLand:           accesstokens.ScopeLand,
+		resolvers.AccessTokenScopeAdmin:          accesstokens.ScopeAdmin,
+	}
+
+	fromScope = map[accesstokens.Scope]resolvers.AccessTokenScope{
+		accesstokens.ScopeReadCodebase:   resolvers.AccessTokenScopeReadCodebase,
+		accesstokens.ScopeWriteWorkspace: resolvers.AccessTokenScopeWriteWorkspace,
+		accesstokens.ScopeLand:           resolvers.AccessTokenScopeLand,
+		accesstokens.ScopeAdmin:          resolvers.AccessTokenScopeAdmin,
+	}
+)
+
+func (r *rootResolver) AccessTokens(ctx context.Context) ([]resolvers.AccessTokenResolver, error) {
+	userID, err := r.authorize(ctx)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	tokens, err := r.accessTokensService.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, gqlerrors.Error(fmt.Errorf("failed to list tokens: %w", err))
+	}
+
+	res := make([]resolvers.AccessTokenResolver, 0, len(tokens))
+	for _, token := range tokens {
+		res = append(res, &resolver{token: token})
+	}
+	return res, nil
+}
+
+func (r *rootResolver) CreateAccessToken(ctx context.Context, args resolvers.CreateAccessTokenArgs) (resolvers.AccessTokenResolver, error) {
+	userID, err := r.authorize(ctx)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	scopes := make([]accesstokens.Scope, 0, len(args.Input.Scopes))
+	for _, s := range args.Input.Scopes {
+		scope, ok := toScope[s]
+		if !ok {
+			return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", fmt.Sprintf("unknown scope: %s", s))
+		}
+		scopes = append(scopes, scope)
+	}
+
+	var expiresAt *time.Time
+	if args.Input.ExpiresAt != nil {
+		t := time.Unix(int64(*args.Input.ExpiresAt), 0)
+		if t.Before(time.Now()) {
+			return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "expiresAt must be in the future")
+		}
+		expiresAt = &t
+	}
+
+	plainTextToken, token, err := r.accessTokensService.Create(ctx, userID, args.Input.Name, scopes, expiresAt)
+	switch {
+	case errors.Is(err, service_accesstokens.ErrNoScopes):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "at least one scope is required")
+	case err != nil:
+		return nil, gqlerrors.Error(fmt.Errorf("failed to create token: %w", err))
+	}
+
+	return &resolver{
+		token:          token,
+		plainTextToken: &plainTextToken,
+	}, nil
+}
+
+func (r *rootResolver) RevokeAccessToken(ctx context.Context, args resolvers.RevokeAccessTokenArgs) (resolvers.AccessTokenResolver, error) {
+	userID, err := r.authorize(ctx)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	token, err := r.accessTokensService.Get(ctx, accesstokens.ID(args.ID))
+	if err != nil {
+		return nil, gqlerrors.Error(fmt.Errorf("failed to get token: %w", err))
+	}
+
+	if token.UserID != userID {
+		return nil, gqlerrors.Error(auth.ErrForbidden)
+	}
+
+	if token.IsRevoked() {
+		return &resolver{token: token}, nil
+	}
+
+	revoked, err := r.accessTokensService.Revoke(ctx, token)
+	if err != nil {
+		return nil, gqlerrors.Error(fmt.Errorf("failed to revoke token: %w", err))
+	}
+
+	return &resolver{token: revoked}, nil
+}
+
+// authorize returns the id of the authenticated user, if they are allowed to manage their access tokens. Access
+// tokens can only be managed by other access tokens if they have the admin scope.
+func (r *rootResolver) authorize(ctx context.Context) (users.ID, error) {
+	userID, err := auth.UserID(ctx)
+	if err != nil {
+		return "", err
+	}
+	if err := auth.RequireScope(ctx, accesstokens.ScopeAdmin); err != nil {
+		return "", err
+	}
+	return userID, nil
+}
+
+type resolver struct {
+	plainTextToken *string
+	token          *accesstokens.Token
+}
+
+func (r *resolver) ID() graphql.ID {
+	return graphql.ID(r.token.ID)
+}
+
+func (r *resolver) Name() string {
+	return r.token.Name
+}
+
+func (r *resolver) Scopes() ([]resolvers.AccessTokenScope, error) {
+	scopes := make([]resolvers.AccessTokenScope, 0, len(r.token.Scopes))
+	for _, s := range r.token.ScopeList() {
+		scope, ok := fromScope[s]
+		if !ok {
+			return nil, gqlerrors.Error(fmt.Errorf("unknown scope: %s", s))
+		}
+		scopes = append(scopes, scope)
+	}
+	return scopes, nil
+}
+
+func (r *resolver) CreatedAt() int32 {
+	return int32(r.token.CreatedAt.Unix())
+}
+
+func (r *resolver) ExpiresAt() *int32 {
+	return toUnix(r.token.ExpiresAt)
+}
+
+func (r *resolver) LastUsedAt() *int32 {
+	return toUnix(r.token.LastUsedAt)
+}
+
+func (r *resolver) RevokedAt() *int32 {
+	return toUnix(r.token.RevokedAt)
+}
+
+func (r *resolver) Token() *string {
+	return r.plainTextToken
+}
+
+func toUnix(t *time.Time) *int32 {
+	if t == nil {
+		return nil
+	}
+	unix := int32(t.Unix())
+	return &unix
+}
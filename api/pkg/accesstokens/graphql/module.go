@@ -0,0 +1,11 @@
+package graphql
+
+import (
+	service_accesstokens "getsturdy.com/api/pkg/accesstokens/service"
+	"getsturdy.com/api/pkg/di"
+)
+
+func Module(c *di.Container) {
+	c.Import(service_accesstokens.Module)
+	c.Register(New)
+}
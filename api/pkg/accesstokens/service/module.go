@@ -0,0 +1,13 @@
+package service
+
+import (
+	db_accesstokens "getsturdy.com/api/pkg/accesstokens/db"
+	"getsturdy.com/api/pkg/di"
+	"getsturdy.com/api/pkg/logger"
+)
+
+func Module(c *di.Container) {
+	c.Import(logger.Module)
+	c.Import(db_accesstokens.Module)
+	c.Register(New)
+}
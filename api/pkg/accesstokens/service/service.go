@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"getsturdy.com/api/pkg/accesstokens"
+	db_accesstokens "getsturdy.com/api/pkg/accesstokens/db"
+	"getsturdy.com/api/pkg/users"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// tokenPrefix makes access tokens easy to tell apart from jwts, and easy to find by secret scanners.
+	tokenPrefix = "sturdy_pat_"
+
+	// lastUsedPrecision is how often last used at is updated for a token that is used continuously.
+	lastUsedPrecision = time.Minute
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrExpired      = errors.New("token expired")
+	ErrRevoked      = errors.New("token revoked")
+	ErrNoScopes     = errors.New("at least one scope is required")
+	ErrInvalidScope = errors.New("invalid scope")
+)
+
+type Service struct {
+	logger *zap.Logger
+	repo   db_accesstokens.Repository
+}
+
+func New(
+	logger *zap.Logger,
+	repo db_accesstokens.Repository,
+) *Service {
+	return &Service{
+		logger: logger.Named("accessTokensService"),
+		repo:   repo,
+	}
+}
+
+// IsAccessToken returns true if s looks like an access token, and not like a jwt or anything else.
+func IsAccessToken(s string) bool {
+	return strings.HasPrefix(s, tokenPrefix)
+}
+
+// Create creates a new access token for the user. It returns the token in plaintext (not stored), and the token
+// in hashed form (as stored in the database).
+func (s *Service) Create(ctx context.Context, userID users.ID, name string, scopes []accesstokens.Scope, expiresAt *time.Time) (string, *accesstokens.Token, error) {
+	if len(scopes) == 0 {
+		return "", nil, ErrNoScopes
+	}
+	for _, scope := range scopes {
+		if !scope.Valid() {
+			return "", nil, fmt.Errorf("%w: %s", ErrInvalidScope, scope)
+		}
+	}
+
+	secretBytes := make([]byte, 32)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", nil, fmt.Errorf("failed to generate secret: %w", err)
+	}
+	secret := hex.EncodeToString(secretBytes)
+
+	token := &accesstokens.Token{
+		ID:        accesstokens.ID(uuid.NewString()),
+		UserID:    userID,
+		Name:      name,
+		Hash:      accesstokens.Hash(secret),
+		CreatedAt: time.Now(),
+		ExpiresAt: expiresAt,
+	}
+	for _, scope := range scopes {
+		token.Scopes = append(token.Scopes, string(scope))
+	}
+
+	if err := s.repo.Create(ctx, token); err != nil {
+		return "", nil, fmt.Errorf("failed to create: %w", err)
+	}
+
+	return tokenPrefix + token.ID.String() + "_" + secret, token, nil
+}
+
+func (s *Service) Get(ctx context.Context, id accesstokens.ID) (*accesstokens.Token, error) {
+	return s.repo.Get(ctx, id)
+}
+
+func (s *Service) ListByUserID(ctx context.Context, userID users.ID) ([]*accesstokens.Token, error) {
+	return s.repo.ListByUserID(ctx, userID)
+}
+
+func (s *Service) Revoke(ctx context.Context, token *accesstokens.Token) (*accesstokens.Token, error) {
+	if err := s.repo.Revoke(ctx, token.ID, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to revoke: %w", err)
+	}
+	return s.repo.Get(ctx, token.ID)
+}
+
+// Verify returns the token that plainTextToken belongs to, if it is valid, not expired and not revoked.
+func (s *Service) Verify(ctx context.Context, plainTextToken string) (*accesstokens.Token, error) {
+	if !IsAccessToken(plainTextToken) {
+		return nil, ErrInvalidToken
+	}
+
+	id, secret, ok := strings.Cut(strings.TrimPrefix(plainTextToken, tokenPrefix), "_")
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	token, err := s.repo.Get(ctx, accesstokens.ID(id))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, ErrInvalidToken
+	case err != nil:
+		return nil, fmt.Errorf("failed to get token: %w", err)
+	}
+
+	if !token.Verify(secret) {
+		return nil, ErrInvalidToken
+	}
+
+	now := time.Now()
+	if token.IsRevoked() {
+		return nil, ErrRevoked
+	}
+	if token.IsExpired(now) {
+		return nil, ErrExpired
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) > lastUsedPrecision {
+		if err := s.repo.SetLastUsedAt(ctx, token.ID, now); err != nil {
+			// not critical, the token is still valid
+			s.logger.Error("failed to set last used at", zap.Error(err), zap.Stringer("token_id", token.ID))
+		} else {
+			token.LastUsedAt = &now
+		}
+	}
+
+	return token, nil
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"getsturdy.com/api/pkg/accesstokens"
+	db_accesstokens "getsturdy.com/api/pkg/accesstokens/db"
+	"getsturdy.com/api/pkg/users"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestVerify(t *testing.T) {
+	ctx := context.Background()
+	svc := New(zap.NewNop(), db_accesstokens.NewMemory())
+	userID := users.ID("user-1")
+
+	plainText, token, err := svc.Create(ctx, userID, "laptop", []accesstokens.Scope{accesstokens.ScopeReadCodebase}, nil)
+	require.NoError(t, err)
+	assert.True(t, IsAccessToken(plainText))
+
+	verified, err := svc.Verify(ctx, plainText)
+	require.NoError(t, err)
+	assert.Equal(t, token.ID, verified.ID)
+	assert.Equal(t, userID, verified.UserID)
+	assert.NotNil(t, verified.LastUsedAt)
+
+	_, err = svc.Verify(ctx, plainText+"0")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = svc.Verify(ctx, "not-a-token")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	revoked, err := svc.Revoke(ctx, token)
+	require.NoError(t, err)
+	assert.True(t, revoked.IsRevoked())
+
+	_, err = svc.Verify(ctx, plainText)
+	assert.ErrorIs(t, err, ErrRevoked)
+}
+
+func TestVerify_expired(t *testing.T) {
+	ctx := context.Background()
+	svc := New(zap.NewNop(), db_accesstokens.NewMemory())
+
+	expiresAt := time.Now().Add(-time.Minute)
+	plainText, _, err := svc.Create(ctx, "user-1", "ci", []accesstokens.Scope{accesstokens.ScopeLand}, &expiresAt)
+	require.NoError(t, err)
+
+	_, err = svc.Verify(ctx, plainText)
+	assert.ErrorIs(t, err, ErrExpired)
+}
+
+func TestCreate_scopes(t *testing.T) {
+	ctx := context.Background()
+	svc := New(zap.NewNop(), db_accesstokens.NewMemory())
+
+	_, _, err := svc.Create(ctx, "user-1", "none", nil, nil)
+	assert.ErrorIs(t, err, ErrNoScopes)
+
+	_, _, err = svc.Create(ctx, "user-1", "invalid", []accesstokens.Scope{"everything"}, nil)
+	assert.ErrorIs(t, err, ErrInvalidScope)
+}
@@ -8,9 +8,12 @@ import (
 	worker_gc "getsturdy.com/api/pkg/gc/worker"
 	"getsturdy.com/api/pkg/gitserver"
 	httpx "getsturdy.com/api/pkg/http"
+	worker_mergequeue "getsturdy.com/api/pkg/mergequeue/worker"
 	"getsturdy.com/api/pkg/metrics"
+	worker_chat "getsturdy.com/api/pkg/notification/chat/worker"
 	"getsturdy.com/api/pkg/pprof"
 	worker_snapshots "getsturdy.com/api/pkg/snapshots/worker"
+	worker_webhooks "getsturdy.com/api/pkg/webhooks/worker"
 
 	"golang.org/x/sync/errgroup"
 )
@@ -24,6 +27,9 @@ type API struct {
 	snapshotterQueue worker_snapshots.Queue
 	ciBuildQueue     *workers_ci.BuildQueue
 	gcQueue          *worker_gc.Queue
+	mergeQueue       *worker_mergequeue.Queue
+	webhooksQueue    *worker_webhooks.Queue
+	chatQueue        *worker_chat.Queue
 	gitsrv           *gitserver.Server
 	pprof            *pprof.Server
 	metrics          *metrics.Server
@@ -34,6 +40,9 @@ func ProvideAPI(
 	snapshotterQueue worker_snapshots.Queue,
 	ciBuildQueue *workers_ci.BuildQueue,
 	gcQueue *worker_gc.Queue,
+	mergeQueue *worker_mergequeue.Queue,
+	webhooksQueue *worker_webhooks.Queue,
+	chatQueue *worker_chat.Queue,
 	gitsrv *gitserver.Server,
 	pprof *pprof.Server,
 	metrics *metrics.Server,
@@ -43,6 +52,9 @@ func ProvideAPI(
 		snapshotterQueue: snapshotterQueue,
 		ciBuildQueue:     ciBuildQueue,
 		gcQueue:          gcQueue,
+		mergeQueue:       mergeQueue,
+		webhooksQueue:    webhooksQueue,
+		chatQueue:        chatQueue,
 		gitsrv:           gitsrv,
 		pprof:            pprof,
 		metrics:          metrics,
@@ -72,6 +84,27 @@ func (a *API) Start(ctx context.Context) error {
 		}
 		return nil
 	})
+	// merge queue
+	wg.Go(func() error {
+		if err := a.mergeQueue.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start merge queue: %w", err)
+		}
+		return nil
+	})
+	// webhooks queue
+	wg.Go(func() error {
+		if err := a.webhooksQueue.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start webhooks queue: %w", err)
+		}
+		return nil
+	})
+	// chat queue
+	wg.Go(func() error {
+		if err := a.chatQueue.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start chat queue: %w", err)
+		}
+		return nil
+	})
 	// Start the git HTTP server
 	wg.Go(func() error {
 		if err := a.gitsrv.Start(); err != nil {
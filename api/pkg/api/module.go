@@ -6,9 +6,12 @@ import (
 	worker_gc "getsturdy.com/api/pkg/gc/worker"
 	"getsturdy.com/api/pkg/gitserver"
 	"getsturdy.com/api/pkg/http"
+	worker_mergequeue "getsturdy.com/api/pkg/mergequeue/worker"
 	"getsturdy.com/api/pkg/metrics"
+	worker_chat "getsturdy.com/api/pkg/notification/chat/worker"
 	"getsturdy.com/api/pkg/pprof"
 	worker_snapshots "getsturdy.com/api/pkg/snapshots/worker"
+	worker_webhooks "getsturdy.com/api/pkg/webhooks/worker"
 )
 
 func Module(c *di.Container) {
@@ -16,6 +19,9 @@ func Module(c *di.Container) {
 	c.Import(worker_snapshots.Module)
 	c.Import(workers_ci.Module)
 	c.Import(worker_gc.Module)
+	c.Import(worker_mergequeue.Module)
+	c.Import(worker_webhooks.Module)
+	c.Import(worker_chat.Module)
 	c.Import(gitserver.Module)
 	c.Import(pprof.Module)
 	c.Import(metrics.Module)
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 
+	service_accesstokens "getsturdy.com/api/pkg/accesstokens/service"
 	"getsturdy.com/api/pkg/ctxlog"
 	"getsturdy.com/api/pkg/jwt"
 	service_jwt "getsturdy.com/api/pkg/jwt/service"
@@ -18,8 +19,20 @@ const (
 	ginContextKey = "auth.subject"
 )
 
-func GinMiddleware(logger *zap.Logger, jwtService *service_jwt.Service) gin.HandlerFunc {
+func GinMiddleware(logger *zap.Logger, jwtService *service_jwt.Service, accessTokensService *service_accesstokens.Service) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// Invalid access tokens are treated in the same way as invalid jwts, and the request continues unauthenticated
+		if subject, ok, err := subjectFromAccessToken(c.Request, accessTokensService); err != nil && !errors.Is(err, ErrUnauthenticated) {
+			ctxlog.ErrorOrWarn(logger, "failed to authenticate access token", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		} else if ok {
+			c.Set(ginContextKey, subject)
+			c.Request = c.Request.WithContext(NewContext(c.Request.Context(), subject))
+			c.Next()
+			return
+		}
+
 		token, shouldRefresh, err := jwtFromRequest(c.Request, jwtService)
 		if err != nil && !errors.Is(err, ErrUnauthenticated) {
 			ctxlog.ErrorOrWarn(logger, "failed to authenticate user", err)
@@ -43,7 +56,7 @@ func GinMiddleware(logger *zap.Logger, jwtService *service_jwt.Service) gin.Hand
 }
 
 func refreshToken(c *gin.Context, token *jwt.Token, jwtService *service_jwt.Service) error {
-	token, err := jwtService.IssueToken(c.Request.Context(), token.Subject, oneMonth, token.Type)
+	token, err := jwtService.IssueToken(c.Request.Context(), token.Subject, oneMonth, token.Type, service_jwt.WithSSO(token.SSO))
 	if err != nil {
 		return fmt.Errorf("failed to issue new token: %w", err)
 	}
@@ -53,8 +66,10 @@ func refreshToken(c *gin.Context, token *jwt.Token, jwtService *service_jwt.Serv
 	return nil
 }
 
-func SetAuthCookieForUser(c *gin.Context, userID users.ID, jwtService *service_jwt.Service) error {
-	token, err := jwtService.IssueToken(c.Request.Context(), userID.String(), oneMonth, jwt.TokenTypeAuth)
+// SetAuthCookieForUser starts a new session for the user. Sessions started with single sign-on should pass
+// service_jwt.WithSSO(true), to be allowed in organizations that enforce it.
+func SetAuthCookieForUser(c *gin.Context, userID users.ID, jwtService *service_jwt.Service, opts ...service_jwt.IssueOption) error {
+	token, err := jwtService.IssueToken(c.Request.Context(), userID.String(), oneMonth, jwt.TokenTypeAuth, opts...)
 	if err != nil {
 		return fmt.Errorf("failed to issue new token: %w", err)
 	}
@@ -9,6 +9,9 @@ import (
 	"testing"
 	"time"
 
+	"getsturdy.com/api/pkg/accesstokens"
+	db_accesstokens "getsturdy.com/api/pkg/accesstokens/db"
+	service_accesstokens "getsturdy.com/api/pkg/accesstokens/service"
 	"getsturdy.com/api/pkg/auth"
 	"getsturdy.com/api/pkg/jwt"
 	db_jwt_keys "getsturdy.com/api/pkg/jwt/keys/db"
@@ -31,7 +34,7 @@ func TestGinMiddleware__shouldAllowCIAuthInHeader(t *testing.T) {
 	assert.NoError(t, err)
 
 	router := gin.New()
-	router.Use(auth.GinMiddleware(zap.NewNop(), jwtTokenService))
+	router.Use(auth.GinMiddleware(zap.NewNop(), jwtTokenService, nil))
 	router.GET("/ping", func(c *gin.Context) {
 		subject, found := auth.SubjectFromGinContext(c)
 		if assert.True(t, found) {
@@ -64,7 +67,7 @@ func TestGinMiddleware__shouldAllowUserAuthInHeader(t *testing.T) {
 	assert.NoError(t, err)
 
 	router := gin.New()
-	router.Use(auth.GinMiddleware(zap.NewNop(), jwtTokenService))
+	router.Use(auth.GinMiddleware(zap.NewNop(), jwtTokenService, nil))
 	router.GET("/ping", func(c *gin.Context) {
 		subject, found := auth.SubjectFromGinContext(c)
 		if assert.True(t, found) {
@@ -97,7 +100,7 @@ func TestGinMiddleware__shouldNotRefreshExpiringHeaderToken(t *testing.T) {
 	assert.NoError(t, err)
 
 	router := gin.New()
-	router.Use(auth.GinMiddleware(zap.NewNop(), jwtTokenService))
+	router.Use(auth.GinMiddleware(zap.NewNop(), jwtTokenService, nil))
 	router.GET("/ping", func(c *gin.Context) {
 		subject, found := auth.SubjectFromGinContext(c)
 		if assert.True(t, found) {
@@ -130,7 +133,7 @@ func TestGinMiddleware__shouldRefreshExpiringCookie(t *testing.T) {
 	assert.NoError(t, err)
 
 	router := gin.New()
-	router.Use(auth.GinMiddleware(zap.NewNop(), jwtTokenService))
+	router.Use(auth.GinMiddleware(zap.NewNop(), jwtTokenService, nil))
 	router.GET("/ping", func(c *gin.Context) {
 		subject, found := auth.SubjectFromGinContext(c)
 		if assert.True(t, found) {
@@ -170,7 +173,7 @@ func TestGinMiddleware__shouldAllowUserAuthInCookie(t *testing.T) {
 	assert.NoError(t, err)
 
 	router := gin.New()
-	router.Use(auth.GinMiddleware(zap.NewNop(), jwtTokenService))
+	router.Use(auth.GinMiddleware(zap.NewNop(), jwtTokenService, nil))
 	router.GET("/ping", func(c *gin.Context) {
 		subject, found := auth.SubjectFromGinContext(c)
 		if assert.True(t, found) {
@@ -203,7 +206,7 @@ func TestGinMiddleware__shouldAllowNoAuth(t *testing.T) {
 	jwtTokenService := service_jwt.NewService(zap.NewNop(), db_jwt_keys.NewInMemory())
 
 	router := gin.New()
-	router.Use(auth.GinMiddleware(zap.NewNop(), jwtTokenService))
+	router.Use(auth.GinMiddleware(zap.NewNop(), jwtTokenService, nil))
 	router.GET("/ping", func(c *gin.Context) {
 		c.String(http.StatusOK, "pong")
 	})
@@ -218,3 +221,33 @@ func TestGinMiddleware__shouldAllowNoAuth(t *testing.T) {
 	assert.Len(t, w.Result().Cookies(), 0)
 	assert.Equal(t, "pong", w.Body.String())
 }
+
+func TestGinMiddleware__shouldAllowAccessTokenInHeader(t *testing.T) {
+	jwtTokenService := service_jwt.NewService(zap.NewNop(), db_jwt_keys.NewInMemory())
+	accessTokensService := service_accesstokens.New(zap.NewNop(), db_accesstokens.NewMemory())
+
+	token, _, err := accessTokensService.Create(context.Background(), "id", "test", []accesstokens.Scope{accesstokens.ScopeReadCodebase}, nil)
+	assert.NoError(t, err)
+
+	router := gin.New()
+	router.Use(auth.GinMiddleware(zap.NewNop(), jwtTokenService, accessTokensService))
+	router.GET("/ping", func(c *gin.Context) {
+		subject, found := auth.FromContext(c.Request.Context())
+		if assert.True(t, found) {
+			assert.Equal(t, subject.ID, "id")
+			assert.Equal(t, subject.Type, auth.SubjectUser)
+			assert.True(t, subject.HasScope(accesstokens.ScopeReadCodebase))
+			assert.False(t, subject.HasScope(accesstokens.ScopeLand))
+		}
+	})
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/ping", nil)
+	assert.NoError(t, err)
+	req.Header.Add("Authorization", fmt.Sprintf("bearer %s", token))
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, w.Result().Cookies(), 0)
+}
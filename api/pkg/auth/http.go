@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	service_accesstokens "getsturdy.com/api/pkg/accesstokens/service"
 	"getsturdy.com/api/pkg/jwt"
 	service_jwt "getsturdy.com/api/pkg/jwt/service"
 
@@ -40,7 +41,13 @@ var (
 	})
 )
 
-func SubjectFromRequest(r *http.Request, jwtService *service_jwt.Service) (*Subject, error) {
+func SubjectFromRequest(r *http.Request, jwtService *service_jwt.Service, accessTokensService *service_accesstokens.Service) (*Subject, error) {
+	if subject, ok, err := subjectFromAccessToken(r, accessTokensService); err != nil {
+		return nil, err
+	} else if ok {
+		return subject, nil
+	}
+
 	jwt, _, err := jwtFromRequest(r, jwtService)
 	if err != nil {
 		return nil, err
@@ -48,6 +55,30 @@ func SubjectFromRequest(r *http.Request, jwtService *service_jwt.Service) (*Subj
 	return subjectFromToken(jwt), nil
 }
 
+// subjectFromAccessToken returns the subject of the personal access token in the request headers, if there is one.
+func subjectFromAccessToken(r *http.Request, accessTokensService *service_accesstokens.Service) (*Subject, bool, error) {
+	if accessTokensService == nil {
+		return nil, false, nil
+	}
+
+	token, fromHeader := tokenFromHeaders(r.Header)
+	if !fromHeader || !service_accesstokens.IsAccessToken(token) {
+		return nil, false, nil
+	}
+
+	accessToken, err := accessTokensService.Verify(r.Context(), token)
+	switch {
+	case errors.Is(err, service_accesstokens.ErrInvalidToken),
+		errors.Is(err, service_accesstokens.ErrExpired),
+		errors.Is(err, service_accesstokens.ErrRevoked):
+		return nil, false, ErrUnauthenticated
+	case err != nil:
+		return nil, false, fmt.Errorf("failed to verify access token: %w", err)
+	}
+
+	return SubjectFromAccessToken(accessToken), true, nil
+}
+
 func jwtFromRequest(r *http.Request, jwtService *service_jwt.Service) (*jwt.Token, bool, error) {
 	token, fromHeader := tokenFromHeaders(r.Header)
 	var fromCookies bool
@@ -13,6 +13,7 @@ import (
 	"getsturdy.com/api/pkg/suggestions"
 	"getsturdy.com/api/pkg/unidiff"
 	"getsturdy.com/api/pkg/users"
+	"getsturdy.com/api/pkg/views"
 	"getsturdy.com/api/pkg/workspaces"
 )
 
@@ -61,6 +62,10 @@ func (s *Service) GetAllower(ctx context.Context, obj any) (*unidiff.Allower, er
 			return s.getUserSuggestionAllower(ctx, subjectID, &object)
 		case *suggestions.Suggestion:
 			return s.getUserSuggestionAllower(ctx, subjectID, object)
+		case views.View:
+			return s.getUserViewAllower(ctx, subjectID, &object)
+		case *views.View:
+			return s.getUserViewAllower(ctx, subjectID, object)
 		}
 
 	case auth.SubjectCI:
@@ -119,6 +124,14 @@ func (s *Service) getUserSuggestionAllower(ctx context.Context, userID users.ID,
 	return s.getUserCodebaseAllower(ctx, userID, cb)
 }
 
+func (s *Service) getUserViewAllower(ctx context.Context, userID users.ID, view *views.View) (*unidiff.Allower, error) {
+	cb, err := s.codebaseService.GetByID(ctx, view.CodebaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get codebase: %w", err)
+	}
+	return s.getUserCodebaseAllower(ctx, userID, cb)
+}
+
 func (s *Service) getUserCodebaseAllower(ctx context.Context, userID users.ID, codebase *codebases.Codebase) (*unidiff.Allower, error) {
 	aclPolicy, err := s.aclProvider.GetByCodebaseID(ctx, codebase.ID)
 	if errors.Is(err, sql.ErrNoRows) {
@@ -134,19 +147,7 @@ func (s *Service) getUserCodebaseAllower(ctx context.Context, userID users.ID, c
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
-	allowedByEmail := aclPolicy.Policy.List(
-		acl.Identity{Type: acl.Users, ID: user.Email},
-		acl.ActionWrite,
-		acl.Files,
-	)
-
-	allowedByID := aclPolicy.Policy.List(
-		acl.Identity{Type: acl.Users, ID: user.ID.String()},
-		acl.ActionWrite,
-		acl.Files,
-	)
-
-	return unidiff.NewAllower(append(allowedByEmail, allowedByID...)...)
+	return unidiff.NewAllower(aclPolicy.Policy.AllowedFiles(user)...)
 }
 
 func (s *Service) getCIWorkspaceAllower(ctx context.Context, workspaceID string, workspace *workspaces.Workspace) (*unidiff.Allower, error) {
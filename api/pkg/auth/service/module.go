@@ -6,6 +6,7 @@ import (
 	service_codebases "getsturdy.com/api/pkg/codebases/service"
 	"getsturdy.com/api/pkg/di"
 	service_organizations "getsturdy.com/api/pkg/organization/service"
+	db_sso "getsturdy.com/api/pkg/sso/db"
 	service_users "getsturdy.com/api/pkg/users/service"
 	service_workspaces "getsturdy.com/api/pkg/workspaces/service"
 )
@@ -17,5 +18,6 @@ func Module(c *di.Container) {
 	c.Import(service_workspaces.Module)
 	c.Import(service_organizations.Module)
 	c.Import(provider_acl.Module)
+	c.Import(db_sso.Module)
 	c.Register(New)
 }
@@ -2,8 +2,11 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 
+	"getsturdy.com/api/pkg/accesstokens"
 	"getsturdy.com/api/pkg/activity"
 	"getsturdy.com/api/pkg/auth"
 	"getsturdy.com/api/pkg/changes"
@@ -16,6 +19,7 @@ import (
 	"getsturdy.com/api/pkg/organization"
 	service_organization "getsturdy.com/api/pkg/organization/service"
 	"getsturdy.com/api/pkg/review"
+	db_sso "getsturdy.com/api/pkg/sso/db"
 	"getsturdy.com/api/pkg/suggestions"
 	"getsturdy.com/api/pkg/users"
 	service_user "getsturdy.com/api/pkg/users/service"
@@ -31,6 +35,7 @@ type Service struct {
 	workspaceService    *service_workspace.Service
 	aclProvider         *provider_acl.Provider
 	organizationService *service_organization.Service
+	ssoSettingsRepo     db_sso.SettingsRepository
 }
 
 func New(
@@ -40,6 +45,7 @@ func New(
 	workspaceService *service_workspace.Service,
 	aclProvider *provider_acl.Provider,
 	organizationService *service_organization.Service,
+	ssoSettingsRepo db_sso.SettingsRepository,
 ) *Service {
 	return &Service{
 		codebaseService:     codebaseService,
@@ -48,6 +54,7 @@ func New(
 		workspaceService:    workspaceService,
 		aclProvider:         aclProvider,
 		organizationService: organizationService,
+		ssoSettingsRepo:     ssoSettingsRepo,
 	}
 }
 
@@ -58,18 +65,58 @@ const (
 	accessTypeUnknown accessType = iota
 	accessTypeRead
 	accessTypeWrite
+	accessTypeAdmin
 )
 
 // CanRead checks if the user has the read permission on the given object.
 func (s *Service) CanRead(ctx context.Context, obj any) error {
+	if err := auth.RequireScope(ctx, accesstokens.ScopeReadCodebase); err != nil {
+		return err
+	}
 	return s.hasAccess(ctx, accessTypeRead, obj)
 }
 
 // CanWrite checks if the user has the write permission on the given object.
 func (s *Service) CanWrite(ctx context.Context, obj any) error {
+	if err := auth.RequireScope(ctx, writeScope(obj)); err != nil {
+		return err
+	}
+	return s.hasAccess(ctx, accessTypeWrite, obj)
+}
+
+// CanLand checks if the user has the permission to land the given workspace to the trunk.
+func (s *Service) CanLand(ctx context.Context, obj any) error {
+	if err := auth.RequireScope(ctx, accesstokens.ScopeLand); err != nil {
+		return err
+	}
 	return s.hasAccess(ctx, accessTypeWrite, obj)
 }
 
+// CanAdmin checks if the user can administrate the given organization or codebase, for example to manage the members
+// of the organization, or the settings and integrations of the codebase.
+func (s *Service) CanAdmin(ctx context.Context, obj any) error {
+	switch obj.(type) {
+	case organization.Organization, *organization.Organization:
+	case codebases.Codebase, *codebases.Codebase:
+	default:
+		return fmt.Errorf("unsupported object type '%T' for admin: %w", obj, auth.ErrForbidden)
+	}
+	if err := auth.RequireScope(ctx, accesstokens.ScopeAdmin); err != nil {
+		return err
+	}
+	return s.hasAccess(ctx, accessTypeAdmin, obj)
+}
+
+// writeScope returns the access token scope that is needed to modify the object.
+func writeScope(obj any) accesstokens.Scope {
+	switch obj.(type) {
+	case organization.Organization, *organization.Organization:
+		return accesstokens.ScopeAdmin
+	default:
+		return accesstokens.ScopeWriteWorkspace
+	}
+}
+
 // hasAccess checks if the user has the given permission on the given object.
 //nolint:cyclop
 func (s *Service) hasAccess(ctx context.Context, at accessType, obj any) error {
@@ -241,6 +288,16 @@ func (s *Service) canUserAccessCodebase(ctx context.Context, userID users.ID, at
 		return nil
 	}
 
+	if at == accessTypeAdmin {
+		return s.canUserAdminCodebase(ctx, userID, codebase)
+	}
+
+	if codebase.OrganizationID != nil {
+		if err := s.checkSSO(ctx, *codebase.OrganizationID); err != nil {
+			return err
+		}
+	}
+
 	accessAllowed, err := s.codebaseService.CanAccess(ctx, userID, codebase.ID)
 	if err != nil {
 		return fmt.Errorf("failed to check if user can access codebase: %w", err)
@@ -250,13 +307,16 @@ func (s *Service) canUserAccessCodebase(ctx context.Context, userID users.ID, at
 		return nil
 	}
 
+	// members of the organization can access its codebases, depending on their role
 	if codebase.OrganizationID != nil {
-		org, err := s.organizationService.GetByID(ctx, *codebase.OrganizationID)
-		if err != nil {
+		member, err := s.organizationService.GetMemberByUserIDAndOrganizationID(ctx, userID, *codebase.OrganizationID)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+		case err != nil:
 			return fmt.Errorf("failed to check if user can access codebase: %w", err)
-		}
-
-		if err := s.canUserAccessOrganization(ctx, userID, accessTypeWrite, org); err == nil {
+		case at == accessTypeRead && member.EffectiveRole().CanReadCodebases():
+			return nil
+		case member.EffectiveRole().CanWriteCodebases():
 			return nil
 		}
 	}
@@ -264,6 +324,27 @@ func (s *Service) canUserAccessCodebase(ctx context.Context, userID users.ID, at
 	return fmt.Errorf("user doesn't have acces to the codebase: %w", auth.ErrForbidden)
 }
 
+// canUserAdminCodebase checks if the user can administrate the codebase. Codebases in an organization are
+// administrated by the administrators of the organization, other codebases by their members.
+func (s *Service) canUserAdminCodebase(ctx context.Context, userID users.ID, codebase *codebases.Codebase) error {
+	if codebase.OrganizationID != nil {
+		org, err := s.organizationService.GetByID(ctx, *codebase.OrganizationID)
+		if err != nil {
+			return fmt.Errorf("failed to get organization: %w", err)
+		}
+		return s.canUserAccessOrganization(ctx, userID, accessTypeAdmin, org)
+	}
+
+	accessAllowed, err := s.codebaseService.CanAccess(ctx, userID, codebase.ID)
+	if err != nil {
+		return fmt.Errorf("failed to check if user can access codebase: %w", err)
+	}
+	if !accessAllowed {
+		return fmt.Errorf("user doesn't have acces to the codebase: %w", auth.ErrForbidden)
+	}
+	return nil
+}
+
 func (s *Service) canAnonymousAccessCodebase(ctx context.Context, at accessType, codebase *codebases.Codebase) error {
 	if at == accessTypeRead && codebase.IsPublic {
 		return nil
@@ -466,10 +547,24 @@ func (s *Service) canAnonymousAccessReview(ctx context.Context, at accessType, r
 }
 
 func (s *Service) canUserAccessOrganization(ctx context.Context, userID users.ID, at accessType, org *organization.Organization) error {
-	// user can access a organization if they are a member of it
-	_, err := s.organizationService.GetMemberByUserIDAndOrganizationID(ctx, userID, org.ID)
+	if err := s.checkSSO(ctx, org.ID); err != nil {
+		return err
+	}
+
+	// user can access a organization if they are a member of it, and their role allows it
+	member, err := s.organizationService.GetMemberByUserIDAndOrganizationID(ctx, userID, org.ID)
 	if err == nil {
-		return nil
+		role := member.EffectiveRole()
+		switch {
+		case at == accessTypeRead:
+			return nil
+		case at == accessTypeWrite && role.CanWriteCodebases():
+			return nil
+		case at == accessTypeAdmin && role.CanAdministrate():
+			return nil
+		default:
+			return fmt.Errorf("the role %q does not allow to access the organization: %w", role, auth.ErrForbidden)
+		}
 	}
 
 	// user can read (but not write) a organization if they are a member of any of it's codebases
@@ -489,3 +584,30 @@ func (s *Service) canUserAccessOrganization(ctx context.Context, userID users.ID
 func (s *Service) canAnonymousAccessOrganization(ctx context.Context, at accessType, org *organization.Organization) error {
 	return fmt.Errorf("anonymous users can't access organizations: %w", auth.ErrForbidden)
 }
+
+// checkSSO returns ErrForbidden if the organization enforces single sign-on, and the subject authenticated with a
+// password, a magic link or a personal access token. Subjects that are created by Sturdy itself are not checked.
+func (s *Service) checkSSO(ctx context.Context, organizationID string) error {
+	subject, found := auth.FromContext(ctx)
+	if !found {
+		return fmt.Errorf("subject is not found in the context: %w", auth.ErrUnauthenticated)
+	}
+
+	switch subject.Authentication {
+	case auth.AuthenticationSession, auth.AuthenticationAccessToken:
+	default:
+		return nil
+	}
+
+	settings, err := s.ssoSettingsRepo.Get(ctx, organizationID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to get sso settings: %w", err)
+	case settings.EnforceSSO:
+		return fmt.Errorf("the organization requires single sign-on: %w", auth.ErrForbidden)
+	default:
+		return nil
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"go.uber.org/zap"
 
+	"getsturdy.com/api/pkg/accesstokens"
 	"getsturdy.com/api/pkg/analytics/disabled"
 	service_analytics "getsturdy.com/api/pkg/analytics/service"
 	"getsturdy.com/api/pkg/auth"
@@ -16,6 +17,8 @@ import (
 	"getsturdy.com/api/pkg/organization"
 	db_organization "getsturdy.com/api/pkg/organization/db"
 	service_organization "getsturdy.com/api/pkg/organization/service"
+	"getsturdy.com/api/pkg/sso"
+	db_sso "getsturdy.com/api/pkg/sso/db"
 	"getsturdy.com/api/pkg/users"
 
 	"github.com/google/uuid"
@@ -75,6 +78,7 @@ func TestCanWrite_codebase(t *testing.T) {
 		nil,
 		nil,
 		nil,
+		nil,
 	)
 
 	for _, tc := range cases {
@@ -175,6 +179,7 @@ func TestCanRead_codebase(t *testing.T) {
 		nil,
 		nil,
 		organizationService,
+		nil,
 	)
 
 	for _, tc := range cases {
@@ -279,6 +284,7 @@ func TestCanReadWrite_organization(t *testing.T) {
 		nil,
 		nil,
 		organizationService,
+		nil,
 	)
 
 	for _, tc := range cases {
@@ -327,3 +333,220 @@ func TestCanReadWrite_organization(t *testing.T) {
 		})
 	}
 }
+
+func TestRoles_organization(t *testing.T) {
+	cases := []struct {
+		role organization.Role
+
+		expectedCanReadOrganization  bool
+		expectedCanWriteOrganization bool
+		expectedCanAdminOrganization bool
+		expectedCanReadCodebase      bool
+		expectedCanWriteCodebase     bool
+	}{
+		{
+			role:                        organization.RoleOwner,
+			expectedCanReadOrganization: true, expectedCanWriteOrganization: true, expectedCanAdminOrganization: true,
+			expectedCanReadCodebase: true, expectedCanWriteCodebase: true,
+		},
+		{
+			role:                        organization.RoleAdmin,
+			expectedCanReadOrganization: true, expectedCanWriteOrganization: true, expectedCanAdminOrganization: true,
+			expectedCanReadCodebase: true, expectedCanWriteCodebase: true,
+		},
+		{
+			role:                        organization.RoleMember,
+			expectedCanReadOrganization: true, expectedCanWriteOrganization: true, expectedCanAdminOrganization: false,
+			expectedCanReadCodebase: true, expectedCanWriteCodebase: true,
+		},
+		{
+			role:                        organization.RoleGuest,
+			expectedCanReadOrganization: true, expectedCanWriteOrganization: false, expectedCanAdminOrganization: false,
+			expectedCanReadCodebase: true, expectedCanWriteCodebase: false,
+		},
+		{
+			role:                        organization.RoleBilling,
+			expectedCanReadOrganization: true, expectedCanWriteOrganization: false, expectedCanAdminOrganization: false,
+			expectedCanReadCodebase: false, expectedCanWriteCodebase: false,
+		},
+	}
+
+	codebaseRepo := db_codebase.NewMemory()
+	codebaseUserRepo := db_codebase.NewInMemoryCodebaseUserRepo()
+	analyticsService := service_analytics.New(zap.NewNop(), disabled.NewClient(zap.NewNop()))
+	codebaseService := service_codebase.New(codebaseRepo, codebaseUserRepo, nil, nil, nil, nil, nil, analyticsService, nil, nil)
+
+	organizationRepo := db_organization.NewInMemoryOrganizationRepo()
+	organizationMemberRepo := db_organization.NewInMemoryOrganizationMemberRepository()
+	organizationService := service_organization.New(zap.NewNop(), nil, organizationRepo, organizationMemberRepo, analyticsService, nil)
+
+	authService := service_auth.New(
+		codebaseService,
+		nil,
+		nil,
+		nil,
+		nil,
+		organizationService,
+		nil,
+	)
+
+	check := func(t *testing.T, expected bool, err error) {
+		if expected {
+			assert.NoError(t, err)
+		} else {
+			assert.Error(t, err)
+		}
+	}
+
+	for _, tc := range cases {
+		t.Run(string(tc.role), func(t *testing.T) {
+			bgCtx := context.Background()
+
+			org := organization.Organization{ID: uuid.NewString()}
+			assert.NoError(t, organizationRepo.Create(bgCtx, org))
+
+			cb := codebases.Codebase{ID: codebases.ID(uuid.NewString()), OrganizationID: &org.ID}
+			assert.NoError(t, codebaseRepo.Create(cb))
+
+			userID := users.ID(uuid.NewString())
+			orgmember := &organization.Member{ID: uuid.NewString(), OrganizationID: org.ID, UserID: userID, Role: tc.role}
+			assert.NoError(t, organizationMemberRepo.Create(bgCtx, orgmember))
+
+			ctx := auth.NewContext(bgCtx, &auth.Subject{ID: userID.String(), Type: auth.SubjectUser})
+
+			check(t, tc.expectedCanReadOrganization, authService.CanRead(ctx, org))
+			check(t, tc.expectedCanWriteOrganization, authService.CanWrite(ctx, org))
+			check(t, tc.expectedCanAdminOrganization, authService.CanAdmin(ctx, org))
+			check(t, tc.expectedCanReadCodebase, authService.CanRead(ctx, cb))
+			check(t, tc.expectedCanWriteCodebase, authService.CanWrite(ctx, cb))
+		})
+	}
+}
+
+func TestCanRead_enforcedSSO(t *testing.T) {
+	cases := []struct {
+		name           string
+		authentication auth.Authentication
+		expected       bool
+	}{
+		{name: "sso", authentication: auth.AuthenticationSSO, expected: true},
+		{name: "session", authentication: auth.AuthenticationSession, expected: false},
+		{name: "access-token", authentication: auth.AuthenticationAccessToken, expected: false},
+		{name: "internal", authentication: auth.AuthenticationUndefined, expected: true},
+	}
+
+	codebaseRepo := db_codebase.NewMemory()
+	codebaseUserRepo := db_codebase.NewInMemoryCodebaseUserRepo()
+	analyticsService := service_analytics.New(zap.NewNop(), disabled.NewClient(zap.NewNop()))
+	codebaseService := service_codebase.New(codebaseRepo, codebaseUserRepo, nil, nil, nil, nil, nil, analyticsService, nil, nil)
+
+	organizationRepo := db_organization.NewInMemoryOrganizationRepo()
+	organizationMemberRepo := db_organization.NewInMemoryOrganizationMemberRepository()
+	organizationService := service_organization.New(zap.NewNop(), nil, organizationRepo, organizationMemberRepo, analyticsService, nil)
+
+	ssoSettingsRepo := db_sso.NewSettingsMemory()
+
+	authService := service_auth.New(
+		codebaseService,
+		nil,
+		nil,
+		nil,
+		nil,
+		organizationService,
+		ssoSettingsRepo,
+	)
+
+	ctx := context.Background()
+	userID := users.ID(uuid.NewString())
+	org := organization.Organization{ID: uuid.NewString()}
+	assert.NoError(t, organizationRepo.Create(ctx, org))
+	assert.NoError(t, organizationMemberRepo.Create(ctx, &organization.Member{ID: uuid.NewString(), OrganizationID: org.ID, UserID: userID}))
+	assert.NoError(t, ssoSettingsRepo.Upsert(ctx, &sso.Settings{OrganizationID: org.ID, EnforceSSO: true}))
+
+	cb := codebases.Codebase{ID: codebases.ID(uuid.NewString()), OrganizationID: &org.ID}
+	assert.NoError(t, codebaseRepo.Create(cb))
+	assert.NoError(t, codebaseUserRepo.Create(codebases.CodebaseUser{ID: uuid.NewString(), CodebaseID: cb.ID, UserID: userID}))
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := auth.NewContext(ctx, &auth.Subject{ID: userID.String(), Type: auth.SubjectUser, Authentication: tc.authentication})
+
+			for _, obj := range []any{cb, org} {
+				if err := authService.CanRead(ctx, obj); tc.expected {
+					assert.NoError(t, err)
+				} else {
+					assert.ErrorIs(t, err, auth.ErrForbidden)
+				}
+			}
+		})
+	}
+}
+
+func TestCanAdmin_codebase(t *testing.T) {
+	codebaseRepo := db_codebase.NewMemory()
+	codebaseUserRepo := db_codebase.NewInMemoryCodebaseUserRepo()
+	analyticsService := service_analytics.New(zap.NewNop(), disabled.NewClient(zap.NewNop()))
+	codebaseService := service_codebase.New(codebaseRepo, codebaseUserRepo, nil, nil, nil, nil, nil, analyticsService, nil, nil)
+
+	organizationRepo := db_organization.NewInMemoryOrganizationRepo()
+	organizationMemberRepo := db_organization.NewInMemoryOrganizationMemberRepository()
+	organizationService := service_organization.New(zap.NewNop(), nil, organizationRepo, organizationMemberRepo, analyticsService, nil)
+
+	authService := service_auth.New(
+		codebaseService,
+		nil,
+		nil,
+		nil,
+		nil,
+		organizationService,
+		db_sso.NewSettingsMemory(),
+	)
+
+	ctx := context.Background()
+	userID := users.ID(uuid.NewString())
+
+	personal := codebases.Codebase{ID: codebases.ID(uuid.NewString())}
+	assert.NoError(t, codebaseRepo.Create(personal))
+	assert.NoError(t, codebaseUserRepo.Create(codebases.CodebaseUser{ID: uuid.NewString(), CodebaseID: personal.ID, UserID: userID}))
+
+	org := organization.Organization{ID: uuid.NewString()}
+	assert.NoError(t, organizationRepo.Create(ctx, org))
+	assert.NoError(t, organizationMemberRepo.Create(ctx, &organization.Member{ID: uuid.NewString(), OrganizationID: org.ID, UserID: userID, Role: organization.RoleMember}))
+	inOrganization := codebases.Codebase{ID: codebases.ID(uuid.NewString()), OrganizationID: &org.ID}
+	assert.NoError(t, codebaseRepo.Create(inOrganization))
+	assert.NoError(t, codebaseUserRepo.Create(codebases.CodebaseUser{ID: uuid.NewString(), CodebaseID: inOrganization.ID, UserID: userID}))
+
+	// administrators of the organization administrate its codebases, also if they are not members of them
+	adminID := users.ID(uuid.NewString())
+	assert.NoError(t, organizationMemberRepo.Create(ctx, &organization.Member{ID: uuid.NewString(), OrganizationID: org.ID, UserID: adminID, Role: organization.RoleAdmin}))
+
+	other := codebases.Codebase{ID: codebases.ID(uuid.NewString())}
+	assert.NoError(t, codebaseRepo.Create(other))
+
+	cases := []struct {
+		name     string
+		userID   users.ID
+		scopes   []accesstokens.Scope
+		codebase codebases.Codebase
+		expected bool
+	}{
+		{name: "member-of-personal-codebase", userID: userID, codebase: personal, expected: true},
+		{name: "member-of-personal-codebase-admin-token", userID: userID, scopes: []accesstokens.Scope{accesstokens.ScopeAdmin}, codebase: personal, expected: true},
+		{name: "member-of-personal-codebase-write-token", userID: userID, scopes: []accesstokens.Scope{accesstokens.ScopeWriteWorkspace}, codebase: personal, expected: false},
+		{name: "not-member-of-codebase", userID: userID, codebase: other, expected: false},
+		{name: "member-of-organization", userID: userID, codebase: inOrganization, expected: false},
+		{name: "admin-of-organization", userID: adminID, codebase: inOrganization, expected: true},
+		{name: "admin-of-organization-write-token", userID: adminID, scopes: []accesstokens.Scope{accesstokens.ScopeWriteWorkspace}, codebase: inOrganization, expected: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := auth.NewContext(ctx, &auth.Subject{ID: tc.userID.String(), Type: auth.SubjectUser, Scopes: tc.scopes})
+			if err := authService.CanAdmin(ctx, tc.codebase); tc.expected {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, auth.ErrForbidden)
+			}
+		})
+	}
+}
@@ -2,7 +2,9 @@ package auth
 
 import (
 	"context"
+	"fmt"
 
+	"getsturdy.com/api/pkg/accesstokens"
 	"getsturdy.com/api/pkg/jwt"
 	"getsturdy.com/api/pkg/users"
 )
@@ -21,9 +23,46 @@ func (st SubjectType) String() string {
 	return string(st)
 }
 
+// Authentication is how a subject has authenticated.
+type Authentication string
+
+const (
+	// AuthenticationUndefined is used by subjects that are created by Sturdy itself, for example to act on behalf of
+	// a user in the background.
+	AuthenticationUndefined   Authentication = ""
+	AuthenticationSession     Authentication = "session"
+	AuthenticationSSO         Authentication = "sso"
+	AuthenticationAccessToken Authentication = "access_token"
+)
+
 type Subject struct {
 	ID   string
 	Type SubjectType
+
+	// Scopes limits what the subject is allowed to do, if it's authenticated with a personal access token.
+	// Subjects authenticated in any other way have no scopes, and are not limited.
+	Scopes []accesstokens.Scope
+
+	Authentication Authentication
+}
+
+// HasScope returns true if the subject is allowed to act within the scope.
+func (s *Subject) HasScope(scope accesstokens.Scope) bool {
+	if s.Scopes == nil {
+		return true
+	}
+	return accesstokens.HasScope(s.Scopes, scope)
+}
+
+// SubjectFromAccessToken returns the subject that is authenticated by the personal access token.
+func SubjectFromAccessToken(token *accesstokens.Token) *Subject {
+	return &Subject{
+		ID:     token.UserID.String(),
+		Type:   SubjectUser,
+		Scopes: token.ScopeList(),
+
+		Authentication: AuthenticationAccessToken,
+	}
 }
 
 var (
@@ -38,9 +77,16 @@ func subjectFromToken(token *jwt.Token) *Subject {
 		return &Subject{Type: SubjectAnonymous}
 	}
 
+	authentication := AuthenticationSession
+	if token.SSO {
+		authentication = AuthenticationSSO
+	}
+
 	return &Subject{
 		ID:   token.Subject,
 		Type: convertType[token.Type],
+
+		Authentication: authentication,
 	}
 }
 
@@ -64,6 +110,18 @@ func FromContext(ctx context.Context) (*Subject, bool) {
 	return s, ok
 }
 
+// RequireScope returns ErrForbidden if the subject in the context is not allowed to act within the scope.
+func RequireScope(ctx context.Context, scope accesstokens.Scope) error {
+	s, ok := FromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+	if !s.HasScope(scope) {
+		return fmt.Errorf("token is missing the %s scope: %w", scope, ErrForbidden)
+	}
+	return nil
+}
+
 // UserID returns authenticated user's id from the context.
 //
 // If context if unauthenticated or not user, returns an ErrUnauthenticated.
@@ -77,3 +135,9 @@ func UserID(ctx context.Context) (users.ID, error) {
 	}
 	return users.ID(s.ID), nil
 }
+
+// IsSSO returns true if the subject in the context authenticated with single sign-on.
+func IsSSO(ctx context.Context) bool {
+	s, ok := FromContext(ctx)
+	return ok && s.Authentication == AuthenticationSSO
+}
@@ -1,8 +1,13 @@
 package blobs
 
+import "time"
+
 type ID string
 
+// Blob is the metadata of a stored blob. The content is kept by a store.BlobStore.
 type Blob struct {
-	ID   ID     `db:"id"`
-	Data []byte `db:"data"`
+	ID          ID        `db:"id"`
+	ContentType string    `db:"content_type"`
+	Size        int64     `db:"size"`
+	CreatedAt   time.Time `db:"created_at"`
 }
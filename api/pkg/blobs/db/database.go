@@ -0,0 +1,63 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"getsturdy.com/api/pkg/blobs"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var _ Repository = &database{}
+
+type database struct {
+	db *sqlx.DB
+}
+
+func NewDatabase(db *sqlx.DB) Repository {
+	return &database{db: db}
+}
+
+func (d *database) Get(ctx context.Context, id blobs.ID) (*blobs.Blob, error) {
+	var blob blobs.Blob
+	if err := d.db.GetContext(ctx, &blob, `
+		SELECT id, content_type, size, created_at
+		FROM blobs
+		WHERE id = $1
+	`, id); err != nil {
+		return nil, fmt.Errorf("failed to get blob: %w", err)
+	}
+	return &blob, nil
+}
+
+func (d *database) Upsert(ctx context.Context, blob *blobs.Blob) error {
+	if _, err := d.db.NamedExecContext(ctx, `
+		INSERT INTO blobs
+			(id, content_type, size, created_at)
+		VALUES
+			(:id, :content_type, :size, :created_at)
+		ON CONFLICT (id) DO UPDATE
+		SET
+			content_type = :content_type,
+			size = :size,
+			created_at = :created_at
+	`, blob); err != nil {
+		return fmt.Errorf("failed to upsert blob: %w", err)
+	}
+	return nil
+}
+
+func (d *database) List(ctx context.Context, after blobs.ID, limit int) ([]*blobs.Blob, error) {
+	var res []*blobs.Blob
+	if err := d.db.SelectContext(ctx, &res, `
+		SELECT id, content_type, size, created_at
+		FROM blobs
+		WHERE id > $1
+		ORDER BY id
+		LIMIT $2
+	`, after, limit); err != nil {
+		return nil, fmt.Errorf("failed to list blobs: %w", err)
+	}
+	return res, nil
+}
@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+
+	"getsturdy.com/api/pkg/blobs"
+)
+
+var _ Repository = &memory{}
+
+type memory struct {
+	mu   sync.RWMutex
+	byID map[blobs.ID]blobs.Blob
+}
+
+func NewMemory() Repository {
+	return &memory{
+		byID: make(map[blobs.ID]blobs.Blob),
+	}
+}
+
+func (m *memory) Get(_ context.Context, id blobs.ID) (*blobs.Blob, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	blob, found := m.byID[id]
+	if !found {
+		return nil, sql.ErrNoRows
+	}
+	return &blob, nil
+}
+
+func (m *memory) Upsert(_ context.Context, blob *blobs.Blob) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.byID[blob.ID] = *blob
+	return nil
+}
+
+func (m *memory) List(_ context.Context, after blobs.ID, limit int) ([]*blobs.Blob, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var res []*blobs.Blob
+	for id := range m.byID {
+		if id > after {
+			blob := m.byID[id]
+			res = append(res, &blob)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].ID < res[j].ID })
+	if len(res) > limit {
+		res = res[:limit]
+	}
+	return res, nil
+}
@@ -0,0 +1,15 @@
+package db
+
+import (
+	"context"
+
+	"getsturdy.com/api/pkg/blobs"
+)
+
+type Repository interface {
+	// Get returns sql.ErrNoRows if there is no blob with the id.
+	Get(context.Context, blobs.ID) (*blobs.Blob, error)
+	Upsert(context.Context, *blobs.Blob) error
+	// List returns up to limit blobs ordered by id, starting after the given id.
+	List(ctx context.Context, after blobs.ID, limit int) ([]*blobs.Blob, error)
+}
@@ -2,8 +2,10 @@ package routes
 
 import (
 	"errors"
+	"io"
 	"net/http"
 	"path"
+	"strconv"
 
 	"getsturdy.com/api/pkg/blobs"
 	service_blob "getsturdy.com/api/pkg/blobs/service"
@@ -20,18 +22,21 @@ func Get(logger *zap.Logger, blobService *service_blob.Service) http.HandlerFunc
 	return func(w http.ResponseWriter, r *http.Request) {
 		key := path.Base(r.URL.Path)
 
-		blob, err := blobService.Fetch(r.Context(), blobs.ID(key))
+		blob, content, err := blobService.Fetch(r.Context(), blobs.ID(key))
 		if errors.Is(err, service_blob.ErrNotFound) {
 			w.WriteHeader(http.StatusNotFound)
 			return
 		} else if err != nil {
+			logger.Error("failed to fetch blob", zap.Error(err))
 			w.WriteHeader(http.StatusInternalServerError)
 			return
-		} else {
-			w.Header().Set("Content-Type", http.DetectContentType(blob.Data))
-			if _, err := w.Write(blob.Data); err != nil {
-				logger.Error("failed to write blob", zap.Error(err))
-			}
+		}
+		defer content.Close()
+
+		w.Header().Set("Content-Type", blob.ContentType)
+		w.Header().Set("Content-Length", strconv.FormatInt(blob.Size, 10))
+		if _, err := io.Copy(w, content); err != nil {
+			logger.Error("failed to write blob", zap.Error(err))
 		}
 	}
 }
@@ -1,11 +1,15 @@
 package service
 
 import (
-	"getsturdy.com/api/pkg/db"
+	db_blobs "getsturdy.com/api/pkg/blobs/db"
+	"getsturdy.com/api/pkg/blobs/store"
 	"getsturdy.com/api/pkg/di"
+	"getsturdy.com/api/pkg/logger"
 )
 
 func Module(c *di.Container) {
-	c.Import(db.Module)
+	c.Import(logger.Module)
+	c.Import(db_blobs.Module)
+	c.Import(store.Module)
 	c.Register(New)
 }
@@ -1,52 +1,156 @@
 package service
 
 import (
+	"bufio"
 	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"time"
 
 	"getsturdy.com/api/pkg/blobs"
+	db_blobs "getsturdy.com/api/pkg/blobs/db"
+	"getsturdy.com/api/pkg/blobs/store"
 
-	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
 )
 
 type Service struct {
-	db *sqlx.DB
+	logger *zap.Logger
+	repo   db_blobs.Repository
+	store  store.BlobStore
 }
 
-func New(db *sqlx.DB) *Service {
-	return &Service{db: db}
+func New(logger *zap.Logger, repo db_blobs.Repository, store store.BlobStore) *Service {
+	return &Service{
+		logger: logger.Named("blobsService"),
+		repo:   repo,
+		store:  store,
+	}
 }
 
 var ErrNotFound = fmt.Errorf("not found: %w", sql.ErrNoRows)
 
-func (s *Service) Fetch(ctx context.Context, id blobs.ID) (*blobs.Blob, error) {
-	var blob blobs.Blob
-	if err := s.db.GetContext(ctx, &blob, "SELECT * FROM blobs WHERE id = $1", id); errors.Is(err, sql.ErrNoRows) {
-		return nil, ErrNotFound
+// Fetch returns the metadata and the content of the blob. The caller must close the reader.
+func (s *Service) Fetch(ctx context.Context, id blobs.ID) (*blobs.Blob, io.ReadCloser, error) {
+	blob, err := s.repo.Get(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, ErrNotFound
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch blob: %w", err)
+	}
+
+	content, err := s.store.Read(ctx, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, nil, ErrNotFound
 	} else if err != nil {
-		return nil, fmt.Errorf("failed to fetch blob: %w", err)
-	} else {
-		return &blob, nil
+		return nil, nil, fmt.Errorf("failed to fetch blob: %w", err)
 	}
+
+	return blob, content, nil
 }
 
-func (s *Service) Store(ctx context.Context, id blobs.ID, reader io.Reader) error {
-	data, err := io.ReadAll(reader)
-	if err != nil {
-		return fmt.Errorf("failed to read file: %w", err)
+// Store streams the content of the reader to the blob store. If contentType is empty, it's detected from the
+// content.
+func (s *Service) Store(ctx context.Context, id blobs.ID, contentType string, reader io.Reader) error {
+	if contentType == "" {
+		buffered := bufio.NewReader(reader)
+		head, err := buffered.Peek(512)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("failed to read file: %w", err)
+		}
+		contentType = http.DetectContentType(head)
+		reader = buffered
 	}
-	blob := &blobs.Blob{
-		ID:   id,
-		Data: data,
+
+	counter := &countingReader{r: reader}
+	if err := s.store.Write(ctx, id, counter); err != nil {
+		return fmt.Errorf("failed to store blob: %w", err)
 	}
-	if _, err := s.db.NamedExecContext(ctx, `
-		INSERT INTO blobs (id, data)
-		VALUES (:id, :data)
-	`, blob); err != nil {
+
+	// the metadata is written last, so that blobs are never found without content
+	if err := s.repo.Upsert(ctx, &blobs.Blob{
+		ID:          id,
+		ContentType: contentType,
+		Size:        counter.n,
+		CreatedAt:   time.Now(),
+	}); err != nil {
 		return fmt.Errorf("failed to store blob: %w", err)
 	}
+
 	return nil
 }
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// MigrateResult is the outcome of a migration between two blob stores.
+type MigrateResult struct {
+	Copied  int
+	Missing int
+}
+
+// MigrateTo copies the content of all blobs from the store of the service to the destination. Blobs that have no
+// content in the source are skipped. If deleteSource is true, the content is deleted from the source after it has
+// been copied. Migrating again after a failure is safe.
+func (s *Service) MigrateTo(ctx context.Context, destination store.BlobStore, deleteSource bool) (*MigrateResult, error) {
+	const pageSize = 100
+
+	result := &MigrateResult{}
+	var after blobs.ID
+	for {
+		page, err := s.repo.List(ctx, after, pageSize)
+		if err != nil {
+			return result, fmt.Errorf("failed to list blobs: %w", err)
+		}
+		if len(page) == 0 {
+			return result, nil
+		}
+
+		for _, blob := range page {
+			after = blob.ID
+
+			copied, err := s.copy(ctx, blob.ID, destination)
+			if err != nil {
+				return result, err
+			}
+			if !copied {
+				s.logger.Warn("blob has no content in the source store, skipping", zap.String("blob_id", string(blob.ID)))
+				result.Missing++
+				continue
+			}
+			result.Copied++
+
+			if deleteSource {
+				if err := s.store.Delete(ctx, blob.ID); err != nil {
+					return result, fmt.Errorf("failed to delete %s from the source: %w", blob.ID, err)
+				}
+			}
+		}
+	}
+}
+
+func (s *Service) copy(ctx context.Context, id blobs.ID, destination store.BlobStore) (bool, error) {
+	content, err := s.store.Read(ctx, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", id, err)
+	}
+	defer content.Close()
+
+	if err := destination.Write(ctx, id, content); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", id, err)
+	}
+	return true, nil
+}
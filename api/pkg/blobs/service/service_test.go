@@ -0,0 +1,81 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"getsturdy.com/api/pkg/blobs"
+	db_blobs "getsturdy.com/api/pkg/blobs/db"
+	service_blobs "getsturdy.com/api/pkg/blobs/service"
+	"getsturdy.com/api/pkg/blobs/store"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newFilesystem(t *testing.T) store.BlobStore {
+	fs, err := store.NewFilesystem(t.TempDir())
+	require.NoError(t, err)
+	return fs
+}
+
+func fetch(t *testing.T, svc *service_blobs.Service, id blobs.ID) (*blobs.Blob, string) {
+	blob, content, err := svc.Fetch(context.Background(), id)
+	require.NoError(t, err)
+	defer content.Close()
+	data, err := io.ReadAll(content)
+	require.NoError(t, err)
+	return blob, string(data)
+}
+
+func TestStoreFetch(t *testing.T) {
+	ctx := context.Background()
+	svc := service_blobs.New(zap.NewNop(), db_blobs.NewMemory(), newFilesystem(t))
+
+	require.NoError(t, svc.Store(ctx, "a.txt", "text/plain", strings.NewReader("hello")))
+	blob, data := fetch(t, svc, "a.txt")
+	assert.Equal(t, "hello", data)
+	assert.Equal(t, "text/plain", blob.ContentType)
+	assert.Equal(t, int64(5), blob.Size)
+
+	// the content type is detected if it's not known
+	png := []byte("\x89PNG\x0D\x0A\x1A\x0A" + strings.Repeat("x", 1000))
+	require.NoError(t, svc.Store(ctx, "b", "", bytes.NewReader(png)))
+	blob, data = fetch(t, svc, "b")
+	assert.Equal(t, string(png), data)
+	assert.Equal(t, "image/png", blob.ContentType)
+	assert.Equal(t, int64(len(png)), blob.Size)
+
+	_, _, err := svc.Fetch(ctx, "c")
+	assert.ErrorIs(t, err, service_blobs.ErrNotFound)
+
+	assert.ErrorIs(t, svc.Store(ctx, "..", "", strings.NewReader("")), store.ErrInvalidID)
+}
+
+func TestMigrateTo(t *testing.T) {
+	ctx := context.Background()
+	repo := db_blobs.NewMemory()
+	source, destination := newFilesystem(t), newFilesystem(t)
+
+	svc := service_blobs.New(zap.NewNop(), repo, source)
+	for _, id := range []blobs.ID{"a", "b", "c"} {
+		require.NoError(t, svc.Store(ctx, id, "text/plain", strings.NewReader(string(id))))
+	}
+	require.NoError(t, source.Delete(ctx, "c"))
+
+	result, err := svc.MigrateTo(ctx, destination, true)
+	require.NoError(t, err)
+	assert.Equal(t, &service_blobs.MigrateResult{Copied: 2, Missing: 1}, result)
+
+	_, err = source.Read(ctx, "a")
+	assert.ErrorIs(t, err, store.ErrNotFound)
+
+	migrated := service_blobs.New(zap.NewNop(), repo, destination)
+	blob, data := fetch(t, migrated, "b")
+	assert.Equal(t, "b", data)
+	assert.Equal(t, "text/plain", blob.ContentType)
+}
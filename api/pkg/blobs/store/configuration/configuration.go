@@ -0,0 +1,65 @@
+package configuration
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+type Configuration struct {
+	Backend    string                   `long:"backend" description:"Where the content of blobs is stored" choice:"postgres" choice:"filesystem" choice:"s3" default:"postgres" env:"BACKEND"`
+	Filesystem *FilesystemConfiguration `flags-group:"filesystem" namespace:"filesystem" env-namespace:"FILESYSTEM"`
+	S3         *S3Configuration         `flags-group:"s3" namespace:"s3" env-namespace:"S3"`
+}
+
+type FilesystemConfiguration struct {
+	Path string `long:"path" description:"Directory to store blobs in" default:"blobs" env:"PATH"`
+}
+
+type S3Configuration struct {
+	Endpoint        string `long:"endpoint" description:"Endpoint of an S3 compatible service, for example a MinIO server. Uses AWS if empty" env:"ENDPOINT"`
+	Region          string `long:"region" description:"Region of the bucket" default:"us-east-1" env:"REGION"`
+	Bucket          string `long:"bucket" description:"Bucket to store blobs in" env:"BUCKET"`
+	Prefix          string `long:"prefix" description:"Prefix of the keys of the blobs in the bucket" env:"PREFIX"`
+	AccessKeyID     string `long:"access-key-id" description:"Access key to authenticate with, the default AWS credentials are used if empty" env:"ACCESS_KEY_ID"`
+	SecretAccessKey string `long:"secret-access-key" description:"Secret key to authenticate with" env:"SECRET_ACCESS_KEY"`
+	PathStyle       bool   `long:"path-style" description:"Use path style addressing of the bucket, required by most S3 compatible services" env:"PATH_STYLE"`
+}
+
+// Overlaps returns true if blobs stored with the two configurations could end up in the same place, so that moving
+// blobs from one to the other would overwrite, or delete, the blobs that are being moved.
+func (c *Configuration) Overlaps(other *Configuration) bool {
+	if c.Backend != other.Backend {
+		return false
+	}
+
+	switch c.Backend {
+	case "filesystem":
+		if c.Filesystem == nil || other.Filesystem == nil {
+			return true
+		}
+		a, errA := filepath.Abs(c.Filesystem.Path)
+		b, errB := filepath.Abs(other.Filesystem.Path)
+		if errA != nil || errB != nil {
+			return true
+		}
+		return within(a, b, string(filepath.Separator)) || within(b, a, string(filepath.Separator))
+	case "s3":
+		if c.S3 == nil || other.S3 == nil {
+			return true
+		}
+		if strings.TrimSuffix(c.S3.Endpoint, "/") != strings.TrimSuffix(other.S3.Endpoint, "/") || c.S3.Bucket != other.S3.Bucket {
+			return false
+		}
+		a, b := path.Clean("/"+c.S3.Prefix), path.Clean("/"+other.S3.Prefix)
+		return within(a, b, "/") || within(b, a, "/")
+	default:
+		// there is only one postgres store, the one of the database
+		return true
+	}
+}
+
+// within returns true if p is the same as, or inside of, dir.
+func within(p, dir, separator string) bool {
+	return p == dir || strings.HasPrefix(p, strings.TrimSuffix(dir, separator)+separator)
+}
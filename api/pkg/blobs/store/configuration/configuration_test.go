@@ -0,0 +1,41 @@
+package configuration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverlaps(t *testing.T) {
+	postgres := &Configuration{Backend: "postgres"}
+	fs := func(p string) *Configuration {
+		return &Configuration{Backend: "filesystem", Filesystem: &FilesystemConfiguration{Path: p}}
+	}
+	s3 := func(endpoint, bucket, prefix string) *Configuration {
+		return &Configuration{Backend: "s3", S3: &S3Configuration{Endpoint: endpoint, Bucket: bucket, Prefix: prefix}}
+	}
+
+	cases := []struct {
+		name     string
+		a, b     *Configuration
+		expected bool
+	}{
+		{"postgres", postgres, postgres, true},
+		{"different backends", postgres, fs("blobs"), false},
+		{"same directory", fs("blobs"), fs("./blobs/"), true},
+		{"nested directory", fs("/data/blobs"), fs("/data/blobs/new"), true},
+		{"different directories", fs("/data/blobs"), fs("/data/blobs-new"), false},
+		{"same bucket", s3("", "blobs", ""), s3("", "blobs", "/"), true},
+		{"nested prefix", s3("", "blobs", "a"), s3("", "blobs", "a/b"), true},
+		{"different prefixes", s3("", "blobs", "a"), s3("", "blobs", "ab"), false},
+		{"different buckets", s3("", "blobs", ""), s3("", "blobs-new", ""), false},
+		{"different endpoints", s3("http://minio:9000", "blobs", ""), s3("", "blobs", ""), false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.a.Overlaps(tc.b))
+			assert.Equal(t, tc.expected, tc.b.Overlaps(tc.a))
+		})
+	}
+}
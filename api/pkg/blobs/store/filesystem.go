@@ -0,0 +1,95 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"getsturdy.com/api/pkg/blobs"
+)
+
+var _ BlobStore = &Filesystem{}
+
+// Filesystem keeps the content of each blob in a file in a directory.
+type Filesystem struct {
+	root string
+}
+
+func NewFilesystem(root string) (*Filesystem, error) {
+	if root == "" {
+		return nil, fmt.Errorf("path of the blobs directory is not set")
+	}
+	if err := os.MkdirAll(root, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create blobs directory: %w", err)
+	}
+	return &Filesystem{root: root}, nil
+}
+
+func (f *Filesystem) path(id blobs.ID) (string, error) {
+	name := url.PathEscape(string(id))
+	if name == "" || name == "." || name == ".." {
+		return "", ErrInvalidID
+	}
+	return filepath.Join(f.root, name), nil
+}
+
+// Write writes the content to a temporary file first, so that readers never see a partially written blob.
+func (f *Filesystem) Write(_ context.Context, id blobs.ID, r io.Reader) error {
+	p, err := f.path(id)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(f.root, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write blob: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to sync blob: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close blob: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		return fmt.Errorf("failed to write blob: %w", err)
+	}
+	return nil
+}
+
+func (f *Filesystem) Read(_ context.Context, id blobs.ID) (io.ReadCloser, error) {
+	p, err := f.path(id)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to open blob: %w", err)
+	}
+	return file, nil
+}
+
+func (f *Filesystem) Delete(_ context.Context, id blobs.ID) error {
+	p, err := f.path(id)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+package store
+
+import (
+	configuration "getsturdy.com/api/pkg/configuration/module"
+	"getsturdy.com/api/pkg/db"
+	"getsturdy.com/api/pkg/di"
+)
+
+func Module(c *di.Container) {
+	c.Import(configuration.Module)
+	c.Import(db.Module)
+	c.Register(New)
+}
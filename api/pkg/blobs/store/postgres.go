@@ -0,0 +1,64 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+
+	"getsturdy.com/api/pkg/blobs"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var _ BlobStore = &Postgres{}
+
+// Postgres keeps the content of blobs in the blobs table. Postgres can't stream bytea values, so the content is
+// buffered in memory. Prefer another backend for large blobs.
+type Postgres struct {
+	db *sqlx.DB
+}
+
+func NewPostgres(db *sqlx.DB) *Postgres {
+	return &Postgres{db: db}
+}
+
+func (p *Postgres) Write(ctx context.Context, id blobs.ID, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to read blob: %w", err)
+	}
+	if _, err := p.db.ExecContext(ctx, `
+		INSERT INTO blobs (id, data)
+		VALUES ($1, $2)
+		ON CONFLICT (id) DO UPDATE
+		SET data = EXCLUDED.data
+	`, id, data); err != nil {
+		return fmt.Errorf("failed to write blob: %w", err)
+	}
+	return nil
+}
+
+func (p *Postgres) Read(ctx context.Context, id blobs.ID) (io.ReadCloser, error) {
+	var data []byte
+	if err := p.db.GetContext(ctx, &data, `SELECT data FROM blobs WHERE id = $1`, id); errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read blob: %w", err)
+	}
+	// the content of the blob is kept by another backend
+	if data == nil {
+		return nil, ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// Delete removes the content, but keeps the metadata of the blob that is stored in the same row.
+func (p *Postgres) Delete(ctx context.Context, id blobs.ID) error {
+	if _, err := p.db.ExecContext(ctx, `UPDATE blobs SET data = NULL WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+
+	"getsturdy.com/api/pkg/blobs"
+	"getsturdy.com/api/pkg/blobs/store/configuration"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrNotFound  = fmt.Errorf("blob not found: %w", sql.ErrNoRows)
+	ErrInvalidID = errors.New("invalid blob id")
+)
+
+// BlobStore keeps the content of blobs.
+type BlobStore interface {
+	// Write stores the content of the blob, replacing any previous content.
+	Write(context.Context, blobs.ID, io.Reader) error
+	// Read returns ErrNotFound if the store has no content for the blob. The caller must close the reader.
+	Read(context.Context, blobs.ID) (io.ReadCloser, error)
+	// Delete removes the content of the blob. Deleting a blob that does not exist is not an error.
+	Delete(context.Context, blobs.ID) error
+}
+
+// New returns the store that is selected by the configuration.
+func New(cfg *configuration.Configuration, db *sqlx.DB) (BlobStore, error) {
+	switch cfg.Backend {
+	case "", "postgres":
+		return NewPostgres(db), nil
+	case "filesystem":
+		return NewFilesystem(cfg.Filesystem.Path)
+	case "s3":
+		return NewS3(cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown blob store backend: %q", cfg.Backend)
+	}
+}
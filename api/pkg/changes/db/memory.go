@@ -35,6 +35,16 @@ func (r *inMemoryChangeRepo) ListByIDs(_ context.Context, ids ...changes.ID) ([]
 	return res, nil
 }
 
+func (r *inMemoryChangeRepo) ListByCodebaseID(_ context.Context, codebaseID codebases.ID) ([]*changes.Change, error) {
+	var res []*changes.Change
+	for _, c := range r.changes {
+		if c.CodebaseID == codebaseID {
+			res = append(res, c)
+		}
+	}
+	return res, nil
+}
+
 func (r *inMemoryChangeRepo) GetByCommitID(_ context.Context, commitID string, codebaseID codebases.ID) (*changes.Change, error) {
 	for _, c := range r.changes {
 		if c.CodebaseID == codebaseID && c.CommitID == nil && *c.CommitID == commitID {
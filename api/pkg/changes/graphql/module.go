@@ -33,4 +33,8 @@ func Module(c *di.Container) {
 		*rp = rv
 		return &rv
 	})
+	c.Decorate(func(rp *resolvers.FileDiffRootResolver, rv resolvers.FileDiffRootResolver) *resolvers.FileDiffRootResolver {
+		*rp = rv
+		return &rv
+	})
 }
@@ -3,18 +3,43 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 	git "github.com/libgit2/git2go/v33"
 	"go.uber.org/zap"
 
+	"getsturdy.com/api/pkg/changes"
+	"getsturdy.com/api/pkg/changes/message"
 	vcs_changes "getsturdy.com/api/pkg/changes/vcs"
 	"getsturdy.com/api/pkg/codebases"
 	"getsturdy.com/api/pkg/snapshots"
 	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
+	"getsturdy.com/api/pkg/workspaces"
 	"getsturdy.com/api/vcs"
 )
 
+var ErrSnapshotNotOnBase = fmt.Errorf("snapshot is not based on the current base of the workspace")
+
+// Part is one of the commits that a workspace is landed as, see CreateSeriesAndLandFromView.
+type Part struct {
+	// PatchIDs are the hunks that are added by the part. The hunks of all parts before it are included as well.
+	PatchIDs []string
+	// Snapshot, if set, makes the part contain the files as they were in the snapshot. PatchIDs are ignored.
+	Snapshot *snapshots.Snapshot
+	// Description is the description of the change that the part is landed as. If it's empty, the description of the
+	// workspace is used, with the title numbered by the position of the part in the series.
+	Description string
+}
+
+// SeriesCommit is one of the commits that a workspace has been landed as, see CreateSeriesAndLandFromView.
+type SeriesCommit struct {
+	CommitID string
+	// Title and Description are what the change of the commit is created with.
+	Title       string
+	Description string
+}
+
 func (s *Service) CreateAndLandFromView(
 	ctx context.Context,
 	viewRepo vcs.RepoWriter,
@@ -24,14 +49,229 @@ func (s *Service) CreateAndLandFromView(
 	signature git.Signature,
 	diffOpts ...vcs.DiffOption,
 ) (commitID string, pushFunc func(vcs.RepoGitWriter) error, retErr error) {
+	create := func(viewRepo vcs.RepoWriter) ([]string, error) {
+		createdCommitID, err := vcs_changes.CreateChangeFromPatchesOnRepo(ctx, s.logger, viewRepo, codebaseID, nil, message, signature, diffOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the new change: %w", err)
+		}
+		return []string{createdCommitID}, nil
+	}
+
+	commitIDs, pushFunc, err := s.landFromView(ctx, viewRepo, codebaseID, workspaceID, create)
+	if err != nil {
+		return "", nil, err
+	}
+	return commitIDs[len(commitIDs)-1], pushFunc, nil
+}
+
+// CreateSeriesAndLandFromView is like CreateAndLandFromView, but lands the workspace as a series of commits, one
+// for each part. The parts are followed by a commit with the rest of the changes in the view, so that nothing is left
+// behind. Parts that don't change anything compared to the commit before them are skipped.
+//
+// Each commit gets the message of its part, see Part.Description. The landed commits are returned in order, the last
+// one is the new head of the trunk.
+func (s *Service) CreateSeriesAndLandFromView(
+	ctx context.Context,
+	viewRepo vcs.RepoWriter,
+	codebaseID codebases.ID,
+	workspaceID string,
+	description string,
+	signature git.Signature,
+	parts []Part,
+	diffOpts ...vcs.DiffOption,
+) ([]SeriesCommit, func(vcs.RepoGitWriter) error, error) {
+	var created []SeriesCommit
+	create := func(viewRepo vcs.RepoWriter) ([]string, error) {
+		var err error
+		created, err = s.createSeries(ctx, viewRepo, codebaseID, description, signature, parts, diffOpts...)
+		if err != nil {
+			return nil, err
+		}
+		commitIDs := make([]string, 0, len(created))
+		for _, commit := range created {
+			commitIDs = append(commitIDs, commit.CommitID)
+		}
+		return commitIDs, nil
+	}
+
+	landedCommitIDs, pushFunc, err := s.landFromView(ctx, viewRepo, codebaseID, workspaceID, create)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the commits have been cherry-picked onto the trunk
+	for i := range created {
+		created[i].CommitID = landedCommitIDs[i]
+	}
+	return created, pushFunc, nil
+}
+
+// CreateSeries creates a change for each of the landed commits, in order. The first change is created on top of the
+// change of parentCommitID, and every other change on top of the one before it.
+func (s *Service) CreateSeries(ctx context.Context, ws *workspaces.Workspace, commits []SeriesCommit, parentCommitID string) ([]*changes.Change, error) {
+	parentChangeID, err := s.parentChangeID(ctx, ws.CodebaseID, parentCommitID)
+	if err != nil {
+		return nil, err
+	}
+
+	created := make([]*changes.Change, 0, len(commits))
+	for _, commit := range commits {
+		change, err := s.create(ctx, ws, commit.CommitID, parentChangeID, commit.Title, commit.Description)
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, change)
+		parentChangeID = &change.ID
+	}
+	return created, nil
+}
+
+func (s *Service) createSeries(
+	ctx context.Context,
+	viewRepo vcs.RepoWriter,
+	codebaseID codebases.ID,
+	description string,
+	signature git.Signature,
+	parts []Part,
+	diffOpts ...vcs.DiffOption,
+) ([]SeriesCommit, error) {
+	base, err := viewRepo.HeadCommit()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get head commit: %w", err)
+	}
+	baseCommitID := base.Id().String()
+	baseTreeID := base.TreeId()
+	base.Free()
+
+	type tree struct {
+		id          *git.Oid
+		description string
+	}
+
+	// all trees are created while HEAD is at the base, the ids of the hunks depend on it
+	var trees []tree
+	patchIDs := []string{}
+	for _, part := range parts {
+		if part.Snapshot != nil {
+			treeID, err := snapshotTree(viewRepo, part.Snapshot, baseCommitID)
+			if err != nil {
+				return nil, err
+			}
+			trees = append(trees, tree{id: treeID, description: part.Description})
+			continue
+		}
+
+		patchIDs = append(patchIDs, part.PatchIDs...)
+		treeID, err := vcs_changes.CreateChangesTreeFromPatches(ctx, s.logger, viewRepo, codebaseID, patchIDs, diffOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create tree: %w", err)
+		}
+		trees = append(trees, tree{id: treeID, description: part.Description})
+	}
+
+	// the rest
+	treeID, err := vcs_changes.CreateChangesTreeFromPatches(ctx, s.logger, viewRepo, codebaseID, nil, diffOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tree: %w", err)
+	}
+	trees = append(trees, tree{id: treeID})
+
+	var series []tree
+	previous := baseTreeID
+	for _, t := range trees {
+		// no hunks were selected
+		if t.id == nil {
+			continue
+		}
+		if t.id.Equal(previous) {
+			continue
+		}
+		series = append(series, t)
+		previous = t.id
+	}
+
+	if len(series) == 0 {
+		return nil, fmt.Errorf("no changes to add")
+	}
+
+	commits := make([]SeriesCommit, 0, len(series))
+	for i, t := range series {
+		partDescription := t.description
+		commitMessage := message.CommitMessage(partDescription)
+		if partDescription == "" {
+			partDescription = description
+			commitMessage = partMessage(message.CommitMessage(description), i, len(series))
+		}
+
+		// every commit is created on top of the one before it
+		commitID, err := viewRepo.CommitIndexTree(t.id, commitMessage, signature)
+		if err != nil {
+			return nil, fmt.Errorf("failed save change: %w", err)
+		}
+		commits = append(commits, SeriesCommit{
+			CommitID:    commitID,
+			Title:       message.Title(commitMessage),
+			Description: partDescription,
+		})
+	}
+
+	return commits, nil
+}
+
+// snapshotTree returns the tree of the snapshot. The snapshot must be based on the base commit, otherwise it would
+// revert everything that has happened on the trunk since it was taken.
+func snapshotTree(viewRepo vcs.RepoWriter, snapshot *snapshots.Snapshot, baseCommitID string) (*git.Oid, error) {
+	if err := viewRepo.FetchBranch(snapshot.BranchName()); err != nil {
+		return nil, fmt.Errorf("failed to fetch snapshot: %w", err)
+	}
+
+	parents, err := viewRepo.GetCommitParents(snapshot.CommitSHA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshot parents: %w", err)
+	}
+	if len(parents) != 1 || parents[0] != baseCommitID {
+		return nil, fmt.Errorf("%w: %s", ErrSnapshotNotOnBase, snapshot.ID)
+	}
+
+	commit, err := viewRepo.Commit(snapshot.CommitSHA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshot commit: %w", err)
+	}
+	defer commit.Free()
+
+	return commit.TreeId(), nil
+}
+
+// partMessage numbers the title of the message, if the workspace is landed as more than one commit.
+func partMessage(message string, i, n int) string {
+	if n == 1 {
+		return message
+	}
+	title, rest, found := strings.Cut(message, "\r\n")
+	numbered := fmt.Sprintf("%s (%d/%d)", title, i+1, n)
+	if !found {
+		return numbered
+	}
+	return numbered + "\r\n" + rest
+}
+
+// landFromView creates commits on the workspace branch with create, and lands them on top of the trunk. If anything
+// goes wrong, the view is restored to how it was before.
+func (s *Service) landFromView(
+	ctx context.Context,
+	viewRepo vcs.RepoWriter,
+	codebaseID codebases.ID,
+	workspaceID string,
+	create func(vcs.RepoWriter) ([]string, error),
+) (landedCommitIDs []string, pushFunc func(vcs.RepoGitWriter) error, retErr error) {
 	viewID := viewRepo.ViewID()
 	if viewID == nil {
-		return "", nil, fmt.Errorf("can not create on a non view")
+		return nil, nil, fmt.Errorf("can not create on a non view")
 	}
 
 	snapshot, err := s.snap.Snapshot(ctx, codebaseID, workspaceID, snapshots.ActionPreChangeLand, service_snapshots.WithOnRepo(viewRepo), service_snapshots.WithOnView(*viewID))
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to snapshot: %w", err)
+		return nil, nil, fmt.Errorf("failed to snapshot: %w", err)
 	}
 
 	defer func() {
@@ -54,22 +294,23 @@ func (s *Service) CreateAndLandFromView(
 		s.logger.Info("successfully restored view after failed landing")
 	}()
 
-	createdCommitID, err := vcs_changes.CreateChangeFromPatchesOnRepo(ctx, s.logger, viewRepo, codebaseID, nil, message, signature, diffOpts...)
+	createdCommitIDs, err := create(viewRepo)
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to create the new change: %w", err)
+		return nil, nil, err
 	}
 
-	if err = fastLand(viewRepo, createdCommitID); err != nil {
-		return "", nil, fmt.Errorf("landing failed: %w", err)
+	landedCommitIDs, err = fastLand(viewRepo, createdCommitIDs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("landing failed: %w", err)
 	}
 
 	// move the workspace branch to be the same as the new sturdytrunk
 	if err := viewRepo.MoveBranch(workspaceID, "sturdytrunk"); err != nil {
-		return "", nil, fmt.Errorf("failed to move workspace to new trunk: %w", err)
+		return nil, nil, fmt.Errorf("failed to move workspace to new trunk: %w", err)
 	}
 
 	if err := viewRepo.CheckoutBranchWithForce(workspaceID); err != nil {
-		return "", nil, fmt.Errorf("failed to checkout workspace branch: %w", err)
+		return nil, nil, fmt.Errorf("failed to checkout workspace branch: %w", err)
 	}
 
 	// LFS Pull
@@ -78,11 +319,6 @@ func (s *Service) CreateAndLandFromView(
 		s.logger.Warn("failed to pull large files", zap.Error(err))
 	}
 
-	newBranchCommit, err := viewRepo.BranchCommitID(workspaceID)
-	if err != nil {
-		return "", nil, fmt.Errorf("failed to get commit after land: %w", err)
-	}
-
 	// will be executed once the new state has been recorded in the databases
 	resPushFunc := func(viewRepo vcs.RepoGitWriter) error {
 		if err := viewRepo.Push(s.logger, "sturdytrunk"); err != nil {
@@ -96,50 +332,59 @@ func (s *Service) CreateAndLandFromView(
 		return nil
 	}
 
-	return newBranchCommit, resPushFunc, nil
+	return landedCommitIDs, resPushFunc, nil
 }
 
-func fastLand(viewRepo vcs.RepoWriter, commitID string) (err error) {
-	if err = viewRepo.FetchBranch("sturdytrunk"); err != nil {
-		return fmt.Errorf("failed to fetch before fastland: %w", err)
+func fastLand(viewRepo vcs.RepoWriter, commitIDs []string) ([]string, error) {
+	if err := viewRepo.FetchBranch("sturdytrunk"); err != nil {
+		return nil, fmt.Errorf("failed to fetch before fastland: %w", err)
 	}
 
-	if err := syncSingleCommitOnBranch(viewRepo, commitID, "origin", "sturdytrunk"); err != nil {
-		return fmt.Errorf("failed to land: %w", err)
+	landedCommitIDs, err := syncCommitsOnBranch(viewRepo, commitIDs, "origin", "sturdytrunk")
+	if err != nil {
+		return nil, fmt.Errorf("failed to land: %w", err)
 	}
 
-	return nil
+	return landedCommitIDs, nil
 }
 
-func syncSingleCommitOnBranch(repo vcs.RepoWriter, syncCommit, remoteName, branchName string) error {
+// syncCommitsOnBranch cherry-picks the commits, in order, on top of the remote branch, and moves the local branch to
+// the result. The ids of the picked commits are returned.
+func syncCommitsOnBranch(repo vcs.RepoWriter, syncCommits []string, remoteName, branchName string) ([]string, error) {
 	err := repo.FetchBranch(branchName)
 	if err != nil {
-		return fmt.Errorf("fetch origin failed: %w", err)
+		return nil, fmt.Errorf("fetch origin failed: %w", err)
 	}
 
 	onto, err := repo.RemoteBranchCommit(remoteName, branchName)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer onto.Free()
 
 	syncingBranchName := fmt.Sprintf("syncing-%s", uuid.NewString())
 
 	if err := repo.CreateAndCheckoutBranchAtCommit(onto.Id().String(), syncingBranchName); err != nil {
-		return fmt.Errorf("create and checkout branch failed: %w", err)
+		return nil, fmt.Errorf("create and checkout branch failed: %w", err)
 	}
 
-	_, conflicted, _, err := repo.CherryPickOnto(syncCommit, onto.Id().String())
-	if conflicted {
-		return fmt.Errorf("could not sync, had conflicts")
-	}
-	if err != nil {
-		return fmt.Errorf("cherry pick failed: %w", err)
+	ontoCommitID := onto.Id().String()
+	syncedCommitIDs := make([]string, 0, len(syncCommits))
+	for _, syncCommit := range syncCommits {
+		newCommitID, conflicted, _, err := repo.CherryPickOnto(syncCommit, ontoCommitID)
+		if conflicted {
+			return nil, fmt.Errorf("could not sync, had conflicts")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cherry pick failed: %w", err)
+		}
+		syncedCommitIDs = append(syncedCommitIDs, newCommitID)
+		ontoCommitID = newCommitID
 	}
 
 	if err := repo.MoveBranchToHEAD(branchName); err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return syncedCommitIDs, nil
 }
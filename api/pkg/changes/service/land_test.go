@@ -0,0 +1,16 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartMessage(t *testing.T) {
+	message := "Add feature\r\n\r\nCreated with Sturdy"
+
+	assert.Equal(t, message, partMessage(message, 0, 1))
+	assert.Equal(t, "Add feature (1/3)\r\n\r\nCreated with Sturdy", partMessage(message, 0, 3))
+	assert.Equal(t, "Add feature (3/3)\r\n\r\nCreated with Sturdy", partMessage(message, 2, 3))
+	assert.Equal(t, "Add feature (2/2)", partMessage("Add feature", 1, 2))
+}
@@ -0,0 +1,207 @@
+package service_test
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	git "github.com/libgit2/git2go/v33"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	db_changes "getsturdy.com/api/pkg/changes/db"
+	service_changes "getsturdy.com/api/pkg/changes/service"
+	db_codebases "getsturdy.com/api/pkg/codebases/db"
+	service_codebases "getsturdy.com/api/pkg/codebases/service"
+	"getsturdy.com/api/pkg/configuration"
+	"getsturdy.com/api/pkg/di"
+	db_installations "getsturdy.com/api/pkg/installations/db"
+	"getsturdy.com/api/pkg/logger"
+	module_queue "getsturdy.com/api/pkg/queue/module"
+	"getsturdy.com/api/pkg/snapshots"
+	db_snapshots "getsturdy.com/api/pkg/snapshots/db"
+	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
+	db_statuses "getsturdy.com/api/pkg/statuses/db"
+	db_suggestions "getsturdy.com/api/pkg/suggestions/db"
+	"getsturdy.com/api/pkg/users"
+	"getsturdy.com/api/pkg/views"
+	db_view "getsturdy.com/api/pkg/views/db"
+	vcs_view "getsturdy.com/api/pkg/views/vcs"
+	db_webhooks "getsturdy.com/api/pkg/webhooks/db"
+	"getsturdy.com/api/pkg/workspaces"
+	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
+	service_workspaces "getsturdy.com/api/pkg/workspaces/service"
+	"getsturdy.com/api/vcs"
+	"getsturdy.com/api/vcs/executor"
+	"getsturdy.com/api/vcs/provider"
+	"getsturdy.com/api/vcs/testutil"
+)
+
+type seriesTest struct {
+	repoProvider     provider.RepoProvider
+	executorProvider executor.Provider
+	viewDB           db_view.Repository
+	workspaceDB      db_workspaces.Repository
+	snapshotter      *service_snapshots.Service
+	workspaceService *service_workspaces.Service
+	codebasesService *service_codebases.Service
+	changeService    *service_changes.Service
+
+	ws     *workspaces.Workspace
+	viewID string
+}
+
+func seriesTestModule(t *testing.T) di.Module {
+	return func(c *di.Container) {
+		c.Import(service_changes.Module)
+		c.Import(service_codebases.Module)
+		c.Import(service_workspaces.Module)
+		c.ImportWithForce(db_suggestions.TestModule)
+		c.ImportWithForce(db_view.TestModule)
+		c.ImportWithForce(db_workspaces.TestModule)
+		c.ImportWithForce(db_snapshots.TestModule)
+		c.ImportWithForce(db_changes.TestModule)
+		c.ImportWithForce(db_statuses.TestModule)
+		c.ImportWithForce(db_webhooks.TestModule)
+		c.ImportWithForce(db_codebases.TestModule)
+		c.ImportWithForce(module_queue.TestModule(t))
+		c.ImportWithForce(configuration.TestModule)
+		c.ImportWithForce(db_installations.TestModule)
+
+		c.RegisterWithForce(func() *sqlx.DB { return nil })
+		c.RegisterWithForce(testutil.TestingRepoProvider)
+		c.RegisterWithForce(logger.NewTest)
+		c.Register(func() *testing.T { return t })
+	}
+}
+
+func newSeriesTest(t *testing.T) *seriesTest {
+	test := &seriesTest{}
+	require.NoError(t, di.Init(seriesTestModule(t)).To(
+		&test.repoProvider,
+		&test.executorProvider,
+		&test.viewDB,
+		&test.workspaceDB,
+		&test.snapshotter,
+		&test.workspaceService,
+		&test.codebasesService,
+		&test.changeService,
+	))
+
+	ctx := context.Background()
+	userID := users.ID("user1")
+
+	cb, err := test.codebasesService.Create(ctx, userID, "series-test", nil)
+	require.NoError(t, err)
+
+	test.ws, err = test.workspaceService.Create(ctx, service_workspaces.CreateWorkspaceRequest{
+		UserID:           userID,
+		CodebaseID:       cb.ID,
+		Name:             "series",
+		DraftDescription: "<p>Add files</p>",
+	})
+	require.NoError(t, err)
+
+	test.viewID = "series-view"
+	require.NoError(t, test.viewDB.Create(views.View{
+		ID:         test.viewID,
+		UserID:     userID,
+		CodebaseID: cb.ID,
+	}))
+	test.ws.ViewID = &test.viewID
+	require.NoError(t, test.workspaceDB.UpdateFields(ctx, test.ws.ID, db_workspaces.SetViewID(&test.viewID)))
+	require.NoError(t, vcs_view.Create(cb.ID, test.ws.ID, test.viewID)(test.repoProvider))
+
+	return test
+}
+
+// write writes a file to the view, and snapshots the workspace.
+func (test *seriesTest) write(t *testing.T, name string) *snapshots.Snapshot {
+	viewPath := test.repoProvider.ViewPath(test.ws.CodebaseID, test.viewID)
+	require.NoError(t, os.WriteFile(path.Join(viewPath, name), []byte(name), 0o644))
+
+	snapshot, err := test.snapshotter.Snapshot(context.Background(), test.ws.CodebaseID, test.ws.ID, snapshots.ActionViewSync, service_snapshots.WithOnView(test.viewID))
+	require.NoError(t, err)
+	return snapshot
+}
+
+func (test *seriesTest) land(t *testing.T, parts []service_changes.Part) ([]service_changes.SeriesCommit, string, error) {
+	signature := git.Signature{Name: "test", Email: "test@getsturdy.com", When: time.Now()}
+
+	var commits []service_changes.SeriesCommit
+	var parentCommitID string
+	err := test.executorProvider.New().Write(func(repo vcs.RepoWriter) error {
+		var err error
+		commits, _, err = test.changeService.CreateSeriesAndLandFromView(context.Background(), repo, test.ws.CodebaseID, test.ws.ID, test.ws.DraftDescription, signature, parts)
+		if err != nil {
+			return err
+		}
+		parents, err := repo.GetCommitParents(commits[0].CommitID)
+		if err != nil {
+			return err
+		}
+		parentCommitID = parents[0]
+		return nil
+	}).ExecView(test.ws.CodebaseID, test.viewID, "testLandSeries")
+	return commits, parentCommitID, err
+}
+
+func TestCreateSeries(t *testing.T) {
+	test := newSeriesTest(t)
+	ctx := context.Background()
+
+	first := test.write(t, "a.txt")
+	second := test.write(t, "b.txt")
+	test.write(t, "c.txt")
+
+	commits, parentCommitID, err := test.land(t, []service_changes.Part{
+		{Snapshot: first, Description: "<p>Add a</p>"},
+		{Snapshot: second},
+	})
+	require.NoError(t, err)
+
+	// every part gets its own message, and the rest of the view is landed last
+	if assert.Len(t, commits, 3) {
+		assert.Equal(t, "Add a", commits[0].Title)
+		assert.Equal(t, "<p>Add a</p>", commits[0].Description)
+		assert.Equal(t, "Add files (2/3)", commits[1].Title)
+		assert.Equal(t, "<p>Add files</p>", commits[1].Description)
+		assert.Equal(t, "Add files (3/3)", commits[2].Title)
+	}
+
+	created, err := test.changeService.CreateSeries(ctx, test.ws, commits, parentCommitID)
+	require.NoError(t, err)
+	require.Len(t, created, 3)
+
+	// the changes are chained on top of each other
+	for i, change := range created {
+		assert.Equal(t, commits[i].CommitID, *change.CommitID)
+		assert.Equal(t, commits[i].Title, *change.Title)
+		assert.Equal(t, commits[i].Description, change.UpdatedDescription)
+		if i > 0 {
+			if assert.NotNil(t, change.ParentChangeID) {
+				assert.Equal(t, created[i-1].ID, *change.ParentChangeID)
+			}
+		}
+	}
+
+	child, err := test.changeService.ChildChange(ctx, created[0])
+	require.NoError(t, err)
+	assert.Equal(t, created[1].ID, child.ID)
+}
+
+func TestCreateSeries_snapshot_not_on_base(t *testing.T) {
+	test := newSeriesTest(t)
+
+	outdated := test.write(t, "a.txt")
+	_, _, err := test.land(t, []service_changes.Part{{Snapshot: outdated}})
+	require.NoError(t, err)
+
+	// the trunk has moved on since the snapshot was taken
+	test.write(t, "b.txt")
+	_, _, err = test.land(t, []service_changes.Part{{Snapshot: outdated}})
+	assert.ErrorIs(t, err, service_changes.ErrSnapshotNotOnBase)
+}
@@ -53,6 +53,11 @@ func (svc *Service) ListChanges(ctx context.Context, ids ...changes.ID) ([]*chan
 	return svc.changeRepo.ListByIDs(ctx, ids...)
 }
 
+// ListByCodebaseID returns all changes in the codebase, in no particular order.
+func (svc *Service) ListByCodebaseID(ctx context.Context, codebaseID codebases.ID) ([]*changes.Change, error) {
+	return svc.changeRepo.ListByCodebaseID(ctx, codebaseID)
+}
+
 func (svc *Service) GetChangeByID(ctx context.Context, id changes.ID) (*changes.Change, error) {
 	ch, err := svc.changeRepo.Get(ctx, id)
 	if err != nil {
@@ -87,24 +92,37 @@ func (svc *Service) CreateOnTop(ctx context.Context, ws *workspaces.Workspace, c
 }
 
 func (svc *Service) CreateWithCommitAsParent(ctx context.Context, ws *workspaces.Workspace, commitID, parentCommitID string) (*changes.Change, error) {
-	var parentChangeID *changes.ID
+	parentChangeID, err := svc.parentChangeID(ctx, ws.CodebaseID, parentCommitID)
+	if err != nil {
+		return nil, err
+	}
 
-	parent, err := svc.getChangeFromCommit(ctx, ws.CodebaseID, parentCommitID)
+	return svc.CreateWithChangeAsParent(ctx, ws, commitID, parentChangeID)
+}
+
+// parentChangeID returns the id of the change of the parent commit, or nil if the commit is not a change.
+func (svc *Service) parentChangeID(ctx context.Context, codebaseID codebases.ID, parentCommitID string) (*changes.ID, error) {
+	parent, err := svc.getChangeFromCommit(ctx, codebaseID, parentCommitID)
 	switch {
 	case err == nil:
-		parentChangeID = &parent.ID
+		return &parent.ID, nil
 	case errors.Is(err, ErrNotFound):
-		// nothing
+		return nil, nil
 	default:
 		return nil, fmt.Errorf("failed to get change from parent commit: %w", err)
 	}
-
-	return svc.CreateWithChangeAsParent(ctx, ws, commitID, parentChangeID)
 }
 
 var ErrAlreadyExists = fmt.Errorf("change already exists")
 
 func (svc *Service) CreateWithChangeAsParent(ctx context.Context, ws *workspaces.Workspace, commitID string, parentChangeID *changes.ID) (*changes.Change, error) {
+	cleanCommitMessage := message.CommitMessage(ws.DraftDescription)
+	title := message.Title(cleanCommitMessage)
+
+	return svc.create(ctx, ws, commitID, parentChangeID, title, ws.DraftDescription)
+}
+
+func (svc *Service) create(ctx context.Context, ws *workspaces.Workspace, commitID string, parentChangeID *changes.ID, title, description string) (*changes.Change, error) {
 	if _, err := svc.changeRepo.GetByCommitID(ctx, commitID, ws.CodebaseID); errors.Is(err, sql.ErrNoRows) {
 		// not found, go on and create
 	} else if err != nil {
@@ -116,14 +134,11 @@ func (svc *Service) CreateWithChangeAsParent(ctx context.Context, ws *workspaces
 	changeID := changes.ID(uuid.NewString())
 	t := time.Now()
 
-	cleanCommitMessage := message.CommitMessage(ws.DraftDescription)
-	title := message.Title(cleanCommitMessage)
-
 	changeChange := changes.Change{
 		ID:                 changeID,
 		CodebaseID:         ws.CodebaseID,
 		Title:              &title,
-		UpdatedDescription: ws.DraftDescription,
+		UpdatedDescription: description,
 		UserID:             &ws.UserID,
 		CreatedAt:          &t,
 		CommitID:           &commitID,
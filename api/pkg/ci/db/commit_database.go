@@ -3,6 +3,7 @@ package db
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"getsturdy.com/api/pkg/ci"
 	"getsturdy.com/api/pkg/codebases"
@@ -36,3 +37,23 @@ func (r *database) Create(ctx context.Context, c *ci.Commit) error {
 	}
 	return nil
 }
+
+func (r *database) CountCreatedBefore(ctx context.Context, codebaseID codebases.ID, before time.Time) (int, error) {
+	var count int
+	if err := r.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM ci_commits WHERE codebase_id = $1 AND created_at < $2`, codebaseID, before); err != nil {
+		return 0, fmt.Errorf("failed to count: %w", err)
+	}
+	return count, nil
+}
+
+func (r *database) DeleteCreatedBefore(ctx context.Context, codebaseID codebases.ID, before time.Time) (int, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM ci_commits WHERE codebase_id = $1 AND created_at < $2`, codebaseID, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete: %w", err)
+	}
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete: %w", err)
+	}
+	return int(deleted), nil
+}
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"getsturdy.com/api/pkg/ci"
+	"getsturdy.com/api/pkg/codebases"
+)
+
+type memory struct {
+	mu      sync.Mutex
+	commits []*ci.Commit
+}
+
+func NewMemoryCommitRepository() CommitRepository {
+	return &memory{}
+}
+
+func (m *memory) Create(_ context.Context, c *ci.Commit) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.commits = append(m.commits, c)
+	return nil
+}
+
+func (m *memory) GetByCodebaseAndCiRepoCommitID(_ context.Context, codebaseID codebases.ID, ciRepoCommitID string) (*ci.Commit, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, c := range m.commits {
+		if c.CodebaseID == codebaseID && c.CiRepoCommitSHA == ciRepoCommitID {
+			return c, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (m *memory) CountCreatedBefore(_ context.Context, codebaseID codebases.ID, before time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var count int
+	for _, c := range m.commits {
+		if c.CodebaseID == codebaseID && c.CreatedAt.Before(before) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *memory) DeleteCreatedBefore(_ context.Context, codebaseID codebases.ID, before time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var kept []*ci.Commit
+	for _, c := range m.commits {
+		if c.CodebaseID != codebaseID || !c.CreatedAt.Before(before) {
+			kept = append(kept, c)
+		}
+	}
+	deleted := len(m.commits) - len(kept)
+	m.commits = kept
+	return deleted, nil
+}
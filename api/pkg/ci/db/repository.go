@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"getsturdy.com/api/pkg/ci"
 	"getsturdy.com/api/pkg/codebases"
@@ -10,4 +11,7 @@ import (
 type CommitRepository interface {
 	Create(context.Context, *ci.Commit) error
 	GetByCodebaseAndCiRepoCommitID(ctx context.Context, codebaseID codebases.ID, ciRepoCommitID string) (*ci.Commit, error)
+	CountCreatedBefore(ctx context.Context, codebaseID codebases.ID, before time.Time) (int, error)
+	// DeleteCreatedBefore returns the number of deleted commits.
+	DeleteCreatedBefore(ctx context.Context, codebaseID codebases.ID, before time.Time) (int, error)
 }
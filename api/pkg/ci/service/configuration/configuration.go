@@ -1,5 +1,8 @@
 package configuration
 
+import "getsturdy.com/api/pkg/configuration/flags"
+
 type Configuration struct {
-	PublicAPIHostname string `long:"public-api-hostname" description:"Public API hostname. Used to fetch codebases from CI"`
+	PublicAPIHostname string    `long:"public-api-hostname" description:"Public API hostname. Used to fetch codebases from CI"`
+	PublicAPIURL      flags.URL `long:"public-api-url" description:"Public URL of the API, builds report their statuses to it. Defaults to https://<public-api-hostname>"`
 }
@@ -11,6 +11,7 @@ import (
 	"getsturdy.com/api/pkg/logger"
 	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
 	service_statuses "getsturdy.com/api/pkg/statuses/service"
+	service_webhookci "getsturdy.com/api/pkg/webhookci/service"
 	"getsturdy.com/api/vcs/executor"
 )
 
@@ -25,5 +26,6 @@ func Module(c *di.Container) {
 	c.Import(service_snapshots.Module)
 	c.Import(service_buildkite.Module)
 	c.Import(service_github.Module)
+	c.Import(service_webhookci.Module)
 	c.Register(New)
 }
@@ -27,6 +27,7 @@ import (
 	service_snaphsotter "getsturdy.com/api/pkg/snapshots/service"
 	"getsturdy.com/api/pkg/statuses"
 	svc_statuses "getsturdy.com/api/pkg/statuses/service"
+	service_webhookci "getsturdy.com/api/pkg/webhookci/service"
 	"getsturdy.com/api/pkg/workspaces"
 	"getsturdy.com/api/vcs"
 	"getsturdy.com/api/vcs/executor"
@@ -50,8 +51,10 @@ type Service struct {
 
 	buildkiteService service_buildkite.Service
 	githubService    service_github.Service
+	webhookCIService *service_webhookci.Service
 
 	publicApiHostname string
+	publicApiURL      string
 	statusService     *svc_statuses.Service
 	jwtService        *service_jwt.Service
 	snapshotter       *service_snaphsotter.Service
@@ -66,6 +69,7 @@ func New(
 
 	buildkiteService service_buildkite.Service,
 	githubService service_github.Service,
+	webhookCIService *service_webhookci.Service,
 
 	cfg *configuration.Configuration,
 	statusService *svc_statuses.Service,
@@ -81,14 +85,24 @@ func New(
 
 		buildkiteService: buildkiteService,
 		githubService:    githubService,
+		webhookCIService: webhookCIService,
 
 		publicApiHostname: cfg.PublicAPIHostname,
+		publicApiURL:      publicAPIURL(cfg),
 		statusService:     statusService,
 		jwtService:        jwtService,
 		snapshotter:       snapshotter,
 	}
 }
 
+// publicAPIURL returns the configured public url of the api, without a trailing slash.
+func publicAPIURL(cfg *configuration.Configuration) string {
+	if cfg.PublicAPIURL.Host != "" {
+		return strings.TrimSuffix(cfg.PublicAPIURL.String(), "/")
+	}
+	return "https://" + cfg.PublicAPIHostname
+}
+
 type sturdyJsonData struct {
 	CodebaseID  codebases.ID  `json:"codebase_id"`
 	ChangeID    *string       `json:"change_id,omitempty"`
@@ -424,6 +438,35 @@ func (svc *Service) TriggerWorkspace(ctx context.Context, workspace *workspaces.
 
 			ss = append(ss, status)
 
+		case providers.ProviderNameWebhook:
+			build, err := svc.webhookCIService.CreateBuild(ctx, config.ID, &service_webhookci.BuildRequest{
+				CodebaseID:    snapshot.CodebaseID,
+				CommitID:      commitID,
+				TrunkCommitID: snapshot.CommitSHA,
+				Title:         workspace.NameOrFallback(),
+				WorkspaceID:   &workspace.ID,
+				StatusesURL:   svc.WebhookStatusesURL(config.ID),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to trigger webhook build: %w", err)
+			}
+
+			status := &statuses.Status{
+				ID:         uuid.NewString(),
+				CommitSHA:  snapshot.CommitSHA,
+				CodebaseID: snapshot.CodebaseID,
+				Type:       statuses.TypePending,
+				Title:      build.Name,
+				DetailsURL: build.URL,
+				Timestamp:  time.Now(),
+			}
+
+			if err := svc.statusService.Set(ctx, status); err != nil {
+				return nil, fmt.Errorf("failed to set status: %w", err)
+			}
+
+			ss = append(ss, status)
+
 		default:
 			return nil, fmt.Errorf("unsupported provider: %s", config.Provider)
 		}
@@ -498,6 +541,35 @@ func (svc *Service) TriggerChange(ctx context.Context, ch *changes.Change, opts
 				return nil, fmt.Errorf("failed to set status: %w", err)
 			}
 
+			ss = append(ss, status)
+
+		case providers.ProviderNameWebhook:
+			build, err := svc.webhookCIService.CreateBuild(ctx, config.ID, &service_webhookci.BuildRequest{
+				CodebaseID:    ch.CodebaseID,
+				CommitID:      commitID,
+				TrunkCommitID: *ch.CommitID,
+				Title:         title,
+				ChangeID:      &ch.ID,
+				StatusesURL:   svc.WebhookStatusesURL(config.ID),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to trigger webhook build: %w", err)
+			}
+
+			status := &statuses.Status{
+				ID:         uuid.NewString(),
+				CommitSHA:  *ch.CommitID,
+				CodebaseID: ch.CodebaseID,
+				Type:       statuses.TypePending,
+				Title:      build.Name,
+				DetailsURL: build.URL,
+				Timestamp:  time.Now(),
+			}
+
+			if err := svc.statusService.Set(ctx, status); err != nil {
+				return nil, fmt.Errorf("failed to set status: %w", err)
+			}
+
 			ss = append(ss, status)
 		default:
 			return nil, fmt.Errorf("unsupported provider: %s", config.Provider)
@@ -507,6 +579,11 @@ func (svc *Service) TriggerChange(ctx context.Context, ch *changes.Change, opts
 	return ss, nil
 }
 
+// WebhookStatusesURL is where builds that are started by the webhook integration report their statuses.
+func (svc *Service) WebhookStatusesURL(integrationID string) string {
+	return fmt.Sprintf("%s/v3/integrations/webhook/%s/statuses", svc.publicApiURL, integrationID)
+}
+
 func (svc *Service) GetTrunkCommitSHA(ctx context.Context, codebaseID codebases.ID, ciRepoCommitID string) (string, error) {
 	c, err := svc.ciCommitRepo.GetByCodebaseAndCiRepoCommitID(ctx, codebaseID, ciRepoCommitID)
 	if err != nil {
@@ -1,9 +1,13 @@
 package acl
 
 import (
+	"fmt"
 	"time"
 
 	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/users"
+
+	"github.com/tailscale/hujson"
 )
 
 type ID string
@@ -18,3 +22,28 @@ type ACL struct {
 	// Note that changes from this field won't be persisted in the database
 	Policy Policy `json:"-" db:"-"`
 }
+
+// ParsePolicy parses RawPolicy into Policy.
+func (a *ACL) ParsePolicy() error {
+	if err := hujson.Unmarshal([]byte(a.RawPolicy), &a.Policy); err != nil {
+		return fmt.Errorf("failed to unmarshal policy: %w", err)
+	}
+	return nil
+}
+
+// AllowedFiles returns the patterns of the files that the user is allowed to access.
+func (p Policy) AllowedFiles(user *users.User) []string {
+	allowedByEmail := p.List(
+		Identity{Type: Users, ID: user.Email},
+		ActionWrite,
+		Files,
+	)
+
+	allowedByID := p.List(
+		Identity{Type: Users, ID: user.ID.String()},
+		ActionWrite,
+		Files,
+	)
+
+	return append(allowedByEmail, allowedByID...)
+}
@@ -8,6 +8,7 @@ type Policy struct {
 	Rules  []*Rule  `json:"rules,omitempty"`
 	Groups []*Group `json:"groups,omitempty"`
 	Tests  []*Test  `json:"tests,omitempty"`
+	Owners []*Owner `json:"owners,omitempty"`
 }
 
 // List return a list of _typ_ resources that _principal_ can _action_ on.
@@ -61,6 +62,8 @@ var (
 	ErrUnsupportedIdentityType = fmt.Errorf("unsupported identity type")
 	ErrTestMustHaveCondition   = fmt.Errorf("test must have either 'allow' or 'deny' condition")
 	ErrUnsupportedActionType   = fmt.Errorf("unsupported action type")
+	ErrUnsupportedOwnerType    = fmt.Errorf("owners can only be users or groups")
+	ErrOwnerMustHaveFiles      = fmt.Errorf("owner must have at least one file pattern")
 	ErrACLTestMissing          = func(id string) error {
 		return fmt.Errorf("at least one 'allow write' test must exist for 'acls::%s' resource", id)
 	}
@@ -82,7 +85,7 @@ func (p Policy) Errors(aclID string) map[string]error {
 		}
 
 		// tests must pass
-		if test.Allow != nil && *test.Allow == ActionWrite {
+		if test.Allow != nil && (*test.Allow == ActionWrite || *test.Allow == ActionLand) {
 			if !p.Assert(test.Principal, *test.Allow, test.Resource) {
 				errs[fmt.Sprintf("tests[\"%s\"]", test.ID)] = ErrTestFails
 			}
@@ -133,6 +136,19 @@ func (p Policy) Errors(aclID string) map[string]error {
 		}
 	}
 
+	for _, owner := range p.Owners {
+		for _, p := range owner.Principals {
+			if p.Type != Users && p.Type != Groups {
+				bytes, _ := p.MarshalJSON()
+				errs[fmt.Sprintf("owners[\"%s\"].principals[%s]", owner.ID, string(bytes))] = ErrUnsupportedOwnerType
+			}
+		}
+
+		if len(owner.Files) == 0 {
+			errs[fmt.Sprintf("owners[\"%s\"].files", owner.ID)] = ErrOwnerMustHaveFiles
+		}
+	}
+
 	return errs
 }
 
@@ -197,9 +213,31 @@ func (a *Rule) assertResource(resource Identity, groups []*Group) bool {
 	return false
 }
 
+// Owner makes users and groups owners of a set of files. Owners are requested to review workspaces that change
+// the files they own.
+type Owner struct {
+	ID         string        `json:"id,omitempty"`
+	Principals []*Identifier `json:"principals,omitempty"`
+	// Files are patterns of the owned files, in the same format as the patterns of "files" resources.
+	Files []string `json:"files,omitempty"`
+	// RequireApproval prevents workspaces that change the owned files from being landed, until one of the owners
+	// has approved them.
+	RequireApproval bool `json:"require_approval,omitempty"`
+}
+
+// Includes returns true if the principal is one of the owners.
+func (o *Owner) Includes(principal Identity, groups []*Group) bool {
+	for _, p := range resolveGroups(o.Principals, groups) {
+		if p.Matches(principal) {
+			return true
+		}
+	}
+	return false
+}
+
 type Action string
 
-var supportedActions = map[Action]bool{ActionWrite: true}
+var supportedActions = map[Action]bool{ActionWrite: true, ActionLand: true}
 
 func (a Action) IsValid() bool {
 	return supportedActions[a]
@@ -207,4 +245,6 @@ func (a Action) IsValid() bool {
 
 const (
 	ActionWrite Action = "write"
+	// ActionLand is only enforced on codebases that restrict landing.
+	ActionLand Action = "land"
 )
@@ -398,3 +398,100 @@ func Test_Policy_Errors_test_group_contains_invalid_resource(t *testing.T) {
 		assert.ErrorIs(t, errs["groups[\"test\"].members[\"invalid\"]"], ErrUnsupportedIdentityType)
 	}
 }
+
+func Test_Policy_Errors_owners(t *testing.T) {
+	p := Policy{
+		Rules:  []*Rule{adminsCanWriteACLsRule},
+		Groups: []*Group{adminsGroup},
+		Tests:  []*Test{adminsCanWriteACLsTest},
+		Owners: []*Owner{
+			{
+				ID:         "admins own everything",
+				Principals: []*Identifier{{Type: Groups, Pattern: "admins"}},
+				Files:      []string{"*"},
+			},
+			{
+				ID:         "codebases can't own files",
+				Principals: []*Identifier{{Type: Codebases, Pattern: "codebase-1"}},
+				Files:      []string{"*"},
+			},
+			{
+				ID:         "user-1 owns nothing",
+				Principals: []*Identifier{{Type: Users, Pattern: "user-1"}},
+			},
+		},
+	}
+
+	if errs := p.Errors(aclID); assert.Len(t, errs, 2) {
+		assert.ErrorIs(t, errs["owners[\"codebases can't own files\"].principals[\"codebases::codebase-1\"]"], ErrUnsupportedOwnerType)
+		assert.ErrorIs(t, errs["owners[\"user-1 owns nothing\"].files"], ErrOwnerMustHaveFiles)
+	}
+}
+
+func Test_Owner_Includes(t *testing.T) {
+	owner := &Owner{
+		ID:         "admins and user-3",
+		Principals: []*Identifier{{Type: Groups, Pattern: "admins"}, {Type: Users, Pattern: "user-3"}},
+		Files:      []string{"*"},
+	}
+
+	groups := []*Group{adminsGroup}
+
+	assert.True(t, owner.Includes(Identity{Type: Users, ID: "user-1"}, groups))
+	assert.True(t, owner.Includes(Identity{Type: Users, ID: "user-3"}, groups))
+	assert.False(t, owner.Includes(Identity{Type: Users, ID: "user-4"}, groups))
+}
+
+func Test_Policy_land(t *testing.T) {
+	p := Policy{
+		Rules: []*Rule{
+			{
+				ID:         "admins can land",
+				Action:     ActionLand,
+				Principals: []*Identifier{{Type: Groups, Pattern: "admins"}},
+				Resources:  []*Identifier{{Type: Codebases, Pattern: "*"}},
+			},
+		},
+		Groups: []*Group{adminsGroup},
+	}
+
+	assert.True(t, p.Assert(Identity{Type: Users, ID: "user-1"}, ActionLand, Identity{Type: Codebases, ID: "codebase-1"}))
+	assert.False(t, p.Assert(Identity{Type: Users, ID: "user-2"}, ActionLand, Identity{Type: Codebases, ID: "codebase-1"}))
+	assert.False(t, p.Assert(Identity{Type: Users, ID: "user-1"}, ActionWrite, Identity{Type: Codebases, ID: "codebase-1"}))
+}
+
+func Test_Policy_Errors_land_tests(t *testing.T) {
+	actionLand := ActionLand
+	p := Policy{
+		Rules: []*Rule{
+			adminsCanWriteACLsRule,
+			{
+				ID:         "admins can land",
+				Action:     ActionLand,
+				Principals: []*Identifier{{Type: Groups, Pattern: "admins"}},
+				Resources:  []*Identifier{{Type: Codebases, Pattern: "*"}},
+			},
+		},
+		Groups: []*Group{adminsGroup},
+		Tests: []*Test{
+			adminsCanWriteACLsTest,
+			{
+				ID:        "user-1 can land codebase-1",
+				Principal: Identity{Type: Users, ID: "user-1"},
+				Allow:     &actionLand,
+				Resource:  Identity{Type: Codebases, ID: "codebase-1"},
+			},
+		},
+	}
+	assert.Len(t, p.Errors(aclID), 0)
+
+	p.Tests = append(p.Tests, &Test{
+		ID:        "user-2 can land codebase-1",
+		Principal: Identity{Type: Users, ID: "user-2"},
+		Allow:     &actionLand,
+		Resource:  Identity{Type: Codebases, ID: "codebase-1"},
+	})
+	if errs := p.Errors(aclID); assert.Len(t, errs, 1) {
+		assert.ErrorIs(t, errs["tests[\"user-2 can land codebase-1\"]"], ErrTestFails)
+	}
+}
@@ -15,7 +15,6 @@ import (
 	service_users "getsturdy.com/api/pkg/users/service"
 
 	"github.com/google/uuid"
-	"github.com/tailscale/hujson"
 )
 
 type Provider struct {
@@ -49,8 +48,8 @@ func (p *Provider) GetByCodebaseID(ctx context.Context, codebaseID codebases.ID)
 		return acl.ACL{}, err
 	}
 
-	if err := hujson.Unmarshal([]byte(entity.RawPolicy), &entity.Policy); err != nil {
-		return acl.ACL{}, fmt.Errorf("failed to unmarshal policy: %w", err)
+	if err := entity.ParsePolicy(); err != nil {
+		return acl.ACL{}, err
 	}
 
 	return entity, nil
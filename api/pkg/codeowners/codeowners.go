@@ -0,0 +1,96 @@
+package codeowners
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"getsturdy.com/api/pkg/codebases/acl"
+	"getsturdy.com/api/pkg/unidiff"
+	"getsturdy.com/api/pkg/users"
+)
+
+// Owner is an entry of the owners section of an acl policy that owns some of the changed files.
+type Owner struct {
+	ID              string
+	RequireApproval bool
+	// UserIDs are the codebase members that are owners.
+	UserIDs []users.ID
+	// Paths are the changed files that are owned.
+	Paths []string
+}
+
+// Paths returns the files that are changed by the diffs. Both names of moved files are included.
+func Paths(diffs []unidiff.FileDiff) []string {
+	paths := make([]string, 0, len(diffs))
+	for _, diff := range diffs {
+		if diff.IsHidden {
+			continue
+		}
+		if !diff.IsNew {
+			paths = append(paths, diff.OrigName)
+		}
+		if !diff.IsDeleted && (diff.IsNew || diff.NewName != diff.OrigName) {
+			paths = append(paths, diff.NewName)
+		}
+	}
+	return paths
+}
+
+// Resolve returns the owners of the paths according to the policy. Members are users of the codebase, only they
+// can be owners.
+func Resolve(policy acl.Policy, paths []string, members []*users.User) ([]*Owner, error) {
+	var owners []*Owner
+	for _, o := range policy.Owners {
+		allower, err := unidiff.NewAllower(o.Files...)
+		if err != nil {
+			return nil, fmt.Errorf("invalid files of owner %q: %w", o.ID, err)
+		}
+
+		var owned []string
+		for _, path := range paths {
+			if allower.IsAllowed(path, false) {
+				owned = append(owned, path)
+			}
+		}
+		if len(owned) == 0 {
+			continue
+		}
+
+		var userIDs []users.ID
+		for _, member := range members {
+			if o.Includes(acl.Identity{Type: acl.Users, ID: member.Email}, policy.Groups) ||
+				o.Includes(acl.Identity{Type: acl.Users, ID: member.ID.String()}, policy.Groups) {
+				userIDs = append(userIDs, member.ID)
+			}
+		}
+
+		owners = append(owners, &Owner{
+			ID:              o.ID,
+			RequireApproval: o.RequireApproval,
+			UserIDs:         userIDs,
+			Paths:           owned,
+		})
+	}
+	return owners, nil
+}
+
+// Digest returns a digest of the files that are owned by each of the owners. It changes when a file becomes owned
+// or stops being owned, but not when the contents of the owned files change.
+func Digest(owners []*Owner) string {
+	var entries []string
+	for _, owner := range owners {
+		for _, path := range owner.Paths {
+			entries = append(entries, owner.ID+"\x00"+path)
+		}
+	}
+	sort.Strings(entries)
+
+	h := sha256.New()
+	for _, entry := range entries {
+		h.Write([]byte(entry))
+		h.Write([]byte("\n"))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,111 @@
+package codeowners_test
+
+import (
+	"testing"
+
+	"getsturdy.com/api/pkg/codebases/acl"
+	"getsturdy.com/api/pkg/codeowners"
+	"getsturdy.com/api/pkg/unidiff"
+	"getsturdy.com/api/pkg/users"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaths(t *testing.T) {
+	paths := codeowners.Paths([]unidiff.FileDiff{
+		{OrigName: "changed.go", NewName: "changed.go"},
+		{OrigName: "/dev/null", NewName: "new.go", IsNew: true},
+		{OrigName: "deleted.go", NewName: "/dev/null", IsDeleted: true},
+		{OrigName: "from.go", NewName: "to.go", IsMoved: true},
+		{IsHidden: true},
+	})
+
+	assert.Equal(t, []string{"changed.go", "new.go", "deleted.go", "from.go", "to.go"}, paths)
+}
+
+func TestResolve(t *testing.T) {
+	policy := acl.Policy{
+		Groups: []*acl.Group{
+			{ID: "frontend", Members: []*acl.Identifier{{Type: acl.Users, Pattern: "alice@example.com"}}},
+		},
+		Owners: []*acl.Owner{
+			{
+				ID:              "frontend owns web",
+				Principals:      []*acl.Identifier{{Type: acl.Groups, Pattern: "frontend"}},
+				Files:           []string{"web/**"},
+				RequireApproval: true,
+			},
+			{
+				ID:         "bob owns go files",
+				Principals: []*acl.Identifier{{Type: acl.Users, Pattern: "bob-id"}},
+				Files:      []string{"*.go"},
+			},
+			{
+				ID:         "bob owns docs",
+				Principals: []*acl.Identifier{{Type: acl.Users, Pattern: "bob-id"}},
+				Files:      []string{"docs/**"},
+			},
+		},
+	}
+
+	members := []*users.User{
+		{ID: "alice-id", Email: "alice@example.com"},
+		{ID: "bob-id", Email: "bob@example.com"},
+	}
+
+	owners, err := codeowners.Resolve(policy, []string{"web/src/main.ts", "api/main.go", "README.md"}, members)
+	require.NoError(t, err)
+
+	assert.Equal(t, []*codeowners.Owner{
+		{
+			ID:              "frontend owns web",
+			RequireApproval: true,
+			UserIDs:         []users.ID{"alice-id"},
+			Paths:           []string{"web/src/main.ts"},
+		},
+		{
+			ID:      "bob owns go files",
+			UserIDs: []users.ID{"bob-id"},
+			Paths:   []string{"api/main.go"},
+		},
+	}, owners)
+}
+
+func TestResolve_invalidPattern(t *testing.T) {
+	policy := acl.Policy{
+		Owners: []*acl.Owner{
+			{ID: "invalid", Principals: []*acl.Identifier{{Type: acl.Users, Pattern: "*"}}, Files: []string{"/"}},
+		},
+	}
+
+	_, err := codeowners.Resolve(policy, []string{"main.go"}, nil)
+	assert.Error(t, err)
+}
+
+func TestDigest(t *testing.T) {
+	owners := []*codeowners.Owner{
+		{ID: "frontend", Paths: []string{"web/a.vue", "web/b.vue"}},
+		{ID: "backend", Paths: []string{"api/a.go"}},
+	}
+
+	// the order of owners and paths doesn't matter
+	reordered := []*codeowners.Owner{
+		{ID: "backend", Paths: []string{"api/a.go"}},
+		{ID: "frontend", Paths: []string{"web/b.vue", "web/a.vue"}},
+	}
+	assert.Equal(t, codeowners.Digest(owners), codeowners.Digest(reordered))
+
+	moreFiles := []*codeowners.Owner{
+		{ID: "frontend", Paths: []string{"web/a.vue", "web/b.vue", "web/c.vue"}},
+		{ID: "backend", Paths: []string{"api/a.go"}},
+	}
+	assert.NotEqual(t, codeowners.Digest(owners), codeowners.Digest(moreFiles))
+
+	otherOwner := []*codeowners.Owner{
+		{ID: "frontend", Paths: []string{"web/a.vue", "web/b.vue", "api/a.go"}},
+	}
+	assert.NotEqual(t, codeowners.Digest(owners), codeowners.Digest(otherOwner))
+
+	assert.Equal(t, codeowners.Digest(nil), codeowners.Digest([]*codeowners.Owner{}))
+}
@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Repository keeps track of the files that were owned in a workspace when reviews were last requested from
+// their owners.
+type Repository interface {
+	// GetOwnedPathsDigest returns sql.ErrNoRows if reviews have never been requested for the workspace.
+	GetOwnedPathsDigest(ctx context.Context, workspaceID string) (string, error)
+	SetOwnedPathsDigest(ctx context.Context, workspaceID, digest string) error
+}
+
+var _ Repository = &database{}
+
+type database struct {
+	db *sqlx.DB
+}
+
+func NewDatabase(db *sqlx.DB) Repository {
+	return &database{db: db}
+}
+
+func (d *database) GetOwnedPathsDigest(ctx context.Context, workspaceID string) (string, error) {
+	var digest string
+	if err := d.db.GetContext(ctx, &digest, `
+		SELECT owned_paths_digest
+		FROM codeowners_workspace_paths
+		WHERE workspace_id = $1
+	`, workspaceID); err != nil {
+		return "", fmt.Errorf("failed to get owned paths: %w", err)
+	}
+	return digest, nil
+}
+
+func (d *database) SetOwnedPathsDigest(ctx context.Context, workspaceID, digest string) error {
+	if _, err := d.db.ExecContext(ctx, `
+		INSERT INTO codeowners_workspace_paths
+			(workspace_id, owned_paths_digest, updated_at)
+		VALUES
+			($1, $2, $3)
+		ON CONFLICT (workspace_id) DO UPDATE
+		SET
+			owned_paths_digest = $2,
+			updated_at = $3
+	`, workspaceID, digest, time.Now()); err != nil {
+		return fmt.Errorf("failed to set owned paths: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
+
+var _ Repository = &memory{}
+
+type memory struct {
+	sync.Mutex
+	digests map[string]string
+}
+
+func NewMemory() Repository {
+	return &memory{digests: map[string]string{}}
+}
+
+func (m *memory) GetOwnedPathsDigest(_ context.Context, workspaceID string) (string, error) {
+	m.Lock()
+	defer m.Unlock()
+	digest, ok := m.digests[workspaceID]
+	if !ok {
+		return "", sql.ErrNoRows
+	}
+	return digest, nil
+}
+
+func (m *memory) SetOwnedPathsDigest(_ context.Context, workspaceID, digest string) error {
+	m.Lock()
+	defer m.Unlock()
+	m.digests[workspaceID] = digest
+	return nil
+}
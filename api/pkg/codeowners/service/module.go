@@ -0,0 +1,33 @@
+package service
+
+import (
+	activity_sender "getsturdy.com/api/pkg/activity/sender"
+	provider_acl "getsturdy.com/api/pkg/codebases/acl/provider"
+	db_codebases "getsturdy.com/api/pkg/codebases/db"
+	db_codeowners "getsturdy.com/api/pkg/codeowners/db"
+	"getsturdy.com/api/pkg/di"
+	"getsturdy.com/api/pkg/events"
+	eventsv2 "getsturdy.com/api/pkg/events/v2"
+	"getsturdy.com/api/pkg/logger"
+	service_chat "getsturdy.com/api/pkg/notification/chat/service"
+	"getsturdy.com/api/pkg/notification/sender"
+	db_review "getsturdy.com/api/pkg/review/db"
+	service_users "getsturdy.com/api/pkg/users/service/module"
+	service_workspaces "getsturdy.com/api/pkg/workspaces/service"
+)
+
+func Module(c *di.Container) {
+	c.Import(logger.Module)
+	c.Import(provider_acl.Module)
+	c.Import(db_codebases.Module)
+	c.Import(db_codeowners.Module)
+	c.Import(db_review.Module)
+	c.Import(service_users.Module)
+	c.Import(service_workspaces.Module)
+	c.Import(sender.Module)
+	c.Import(service_chat.Module)
+	c.Import(activity_sender.Module)
+	c.Import(events.Module)
+	c.Import(eventsv2.Module)
+	c.Register(New)
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"getsturdy.com/api/pkg/activity"
+	activity_sender "getsturdy.com/api/pkg/activity/sender"
+	provider_acl "getsturdy.com/api/pkg/codebases/acl/provider"
+	db_codebases "getsturdy.com/api/pkg/codebases/db"
+	"getsturdy.com/api/pkg/codeowners"
+	db_codeowners "getsturdy.com/api/pkg/codeowners/db"
+	"getsturdy.com/api/pkg/events"
+	eventsv2 "getsturdy.com/api/pkg/events/v2"
+	"getsturdy.com/api/pkg/notification"
+	service_chat "getsturdy.com/api/pkg/notification/chat/service"
+	"getsturdy.com/api/pkg/notification/sender"
+	"getsturdy.com/api/pkg/review"
+	db_review "getsturdy.com/api/pkg/review/db"
+	"getsturdy.com/api/pkg/users"
+	service_users "getsturdy.com/api/pkg/users/service"
+	"getsturdy.com/api/pkg/workspaces"
+	service_workspaces "getsturdy.com/api/pkg/workspaces/service"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type Service struct {
+	logger *zap.Logger
+
+	aclProvider      *provider_acl.Provider
+	codeownersRepo   db_codeowners.Repository
+	codebaseUserRepo db_codebases.CodebaseUserRepository
+	reviewRepo       db_review.ReviewRepository
+	usersService     service_users.Service
+	workspaceService *service_workspaces.Service
+
+	notificationSender sender.NotificationSender
+	chatService        *service_chat.Service
+	activitySender     activity_sender.ActivitySender
+	eventsSender       events.EventSender
+	eventsPublisher    *eventsv2.Publisher
+}
+
+func New(
+	logger *zap.Logger,
+
+	aclProvider *provider_acl.Provider,
+	codeownersRepo db_codeowners.Repository,
+	codebaseUserRepo db_codebases.CodebaseUserRepository,
+	reviewRepo db_review.ReviewRepository,
+	usersService service_users.Service,
+	workspaceService *service_workspaces.Service,
+
+	notificationSender sender.NotificationSender,
+	chatService *service_chat.Service,
+	activitySender activity_sender.ActivitySender,
+	eventsSender events.EventSender,
+	eventsPublisher *eventsv2.Publisher,
+) *Service {
+	return &Service{
+		logger: logger.Named("codeownersService"),
+
+		aclProvider:      aclProvider,
+		codeownersRepo:   codeownersRepo,
+		codebaseUserRepo: codebaseUserRepo,
+		reviewRepo:       reviewRepo,
+		usersService:     usersService,
+		workspaceService: workspaceService,
+
+		notificationSender: notificationSender,
+		chatService:        chatService,
+		activitySender:     activitySender,
+		eventsSender:       eventsSender,
+		eventsPublisher:    eventsPublisher,
+	}
+}
+
+// Owners returns the owners of the files that are changed in the workspace.
+func (s *Service) Owners(ctx context.Context, ws *workspaces.Workspace) ([]*codeowners.Owner, error) {
+	policy, err := s.aclProvider.GetByCodebaseID(ctx, ws.CodebaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get acl policy: %w", err)
+	}
+
+	// most codebases don't have owners, don't bother with the diffs
+	if len(policy.Policy.Owners) == 0 {
+		return nil, nil
+	}
+
+	diffs, _, err := s.workspaceService.Diffs(ctx, ws.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get diffs: %w", err)
+	}
+
+	members, err := s.members(ctx, ws)
+	if err != nil {
+		return nil, err
+	}
+
+	return codeowners.Resolve(policy.Policy, codeowners.Paths(diffs), members)
+}
+
+func (s *Service) members(ctx context.Context, ws *workspaces.Workspace) ([]*users.User, error) {
+	codebaseUsers, err := s.codebaseUserRepo.GetByCodebase(ws.CodebaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get codebase users: %w", err)
+	}
+
+	userIDs := make([]users.ID, 0, len(codebaseUsers))
+	for _, cu := range codebaseUsers {
+		userIDs = append(userIDs, cu.UserID)
+	}
+
+	members, err := s.usersService.GetByIDs(ctx, userIDs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+	return members, nil
+}
+
+// RequestReviews requests a review of the workspace from the owners of the files it changes. Owners that have
+// already been requested, or that have reviewed the workspace, are left alone. Reviews are requested on behalf
+// of the author of the workspace.
+//
+// Nothing is requested if the owned files are the same as the last time reviews were requested.
+func (s *Service) RequestReviews(ctx context.Context, ws *workspaces.Workspace) error {
+	owners, err := s.Owners(ctx, ws)
+	if err != nil {
+		return err
+	}
+
+	digest := codeowners.Digest(owners)
+	previous, err := s.codeownersRepo.GetOwnedPathsDigest(ctx, ws.ID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		previous = codeowners.Digest(nil)
+	case err != nil:
+		return fmt.Errorf("failed to get owned paths: %w", err)
+	}
+	if digest == previous {
+		return nil
+	}
+
+	requested := map[users.ID]bool{ws.UserID: true}
+	for _, owner := range owners {
+		for _, userID := range owner.UserIDs {
+			if requested[userID] {
+				continue
+			}
+			requested[userID] = true
+
+			if err := s.requestReview(ctx, ws, userID); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := s.codeownersRepo.SetOwnedPathsDigest(ctx, ws.ID, digest); err != nil {
+		return fmt.Errorf("failed to set owned paths: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) requestReview(ctx context.Context, ws *workspaces.Workspace, userID users.ID) error {
+	// users that have been asked before, or have reviewed, are not asked again, even if the review was dismissed
+	if _, err := s.reviewRepo.GetLatestByUserAndWorkspace(ctx, userID, ws.ID); err == nil {
+		return nil
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to get review: %w", err)
+	}
+
+	requestedBy := ws.UserID
+	rev := review.Review{
+		ID:          uuid.NewString(),
+		UserID:      userID,
+		CodebaseID:  ws.CodebaseID,
+		WorkspaceID: ws.ID,
+		Grade:       review.ReviewGradeRequested,
+		CreatedAt:   time.Now(),
+		RequestedBy: &requestedBy,
+	}
+
+	if err := s.reviewRepo.Create(ctx, rev); err != nil {
+		return fmt.Errorf("failed to create review: %w", err)
+	}
+
+	if err := s.activitySender.Codebase(ctx, ws.CodebaseID, ws.ID, ws.UserID, activity.TypeRequestedReview, rev.ID); err != nil {
+		return fmt.Errorf("failed to create activity: %w", err)
+	}
+
+	if err := s.notificationSender.User(ctx, userID, notification.RequestedReviewNotificationType, rev.ID); err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+
+	if err := s.chatService.ReviewRequested(ctx, &rev); err != nil {
+		s.logger.Error("failed to send review requested chat notification", zap.Error(err))
+		// do not fail
+	}
+
+	if err := s.eventsSender.Codebase(ws.CodebaseID, events.WorkspaceUpdatedReviews, ws.ID); err != nil {
+		s.logger.Error("failed to send codebase event", zap.Error(err))
+		// do not fail
+	}
+
+	if err := s.eventsPublisher.ReviewUpdated(ctx, eventsv2.Workspace(ws.ID), &rev); err != nil {
+		s.logger.Error("failed to send workspace event", zap.Error(err))
+		// do not fail
+	}
+
+	return nil
+}
+
+// MissingApprovals returns the owners of the files changed in the workspace that require an approval, and
+// haven't approved it. Only reviews count, and the author of the workspace can't approve it, so an owner that
+// authored the workspace still needs an approval from another owner.
+func (s *Service) MissingApprovals(ctx context.Context, ws *workspaces.Workspace) ([]*codeowners.Owner, error) {
+	owners, err := s.Owners(ctx, ws)
+	if err != nil {
+		return nil, err
+	}
+
+	var requireApproval []*codeowners.Owner
+	for _, owner := range owners {
+		if owner.RequireApproval {
+			requireApproval = append(requireApproval, owner)
+		}
+	}
+	if len(requireApproval) == 0 {
+		return nil, nil
+	}
+
+	reviews, err := s.reviewRepo.ListLatestByWorkspace(ctx, ws.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reviews: %w", err)
+	}
+
+	approved := map[users.ID]bool{}
+	for _, rev := range reviews {
+		if rev.Grade == review.ReviewGradeApprove && rev.UserID != ws.UserID {
+			approved[rev.UserID] = true
+		}
+	}
+
+	var missing []*codeowners.Owner
+	for _, owner := range requireApproval {
+		if !approvedByAny(approved, owner.UserIDs) {
+			missing = append(missing, owner)
+		}
+	}
+	return missing, nil
+}
+
+func approvedByAny(approved map[users.ID]bool, userIDs []users.ID) bool {
+	for _, userID := range userIDs {
+		if approved[userID] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+
+	"getsturdy.com/api/pkg/changes"
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/comments"
+)
+
+var _ Repository = &memory{}
+
+type memory struct {
+	byID map[comments.ID]comments.Comment
+}
+
+func NewMemory() Repository {
+	return &memory{
+		byID: make(map[comments.ID]comments.Comment),
+	}
+}
+
+func (m *memory) Create(comment comments.Comment) error {
+	m.byID[comment.ID] = comment
+	return nil
+}
+
+func (m *memory) Get(id comments.ID) (comments.Comment, error) {
+	comment, ok := m.byID[id]
+	if !ok {
+		return comments.Comment{}, sql.ErrNoRows
+	}
+	return comment, nil
+}
+
+func (m *memory) Update(comment comments.Comment) error {
+	if _, ok := m.byID[comment.ID]; !ok {
+		return sql.ErrNoRows
+	}
+	m.byID[comment.ID] = comment
+	return nil
+}
+
+func (m *memory) list(filter func(comments.Comment) bool, newestFirst bool) []comments.Comment {
+	var res []comments.Comment
+	for _, comment := range m.byID {
+		if comment.DeletedAt == nil && filter(comment) {
+			res = append(res, comment)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if newestFirst {
+			return res[i].CreatedAt.After(res[j].CreatedAt)
+		}
+		return res[i].CreatedAt.Before(res[j].CreatedAt)
+	})
+	return res
+}
+
+func (m *memory) GetByCodebaseAndChange(codebaseID codebases.ID, changeID changes.ID) ([]comments.Comment, error) {
+	return m.list(func(comment comments.Comment) bool {
+		return comment.CodebaseID == codebaseID &&
+			comment.ChangeID != nil && *comment.ChangeID == changeID &&
+			comment.ParentComment == nil
+	}, true), nil
+}
+
+func (m *memory) GetByWorkspace(workspaceID string) ([]comments.Comment, error) {
+	return m.list(func(comment comments.Comment) bool {
+		return comment.WorkspaceID != nil && *comment.WorkspaceID == workspaceID &&
+			comment.ParentComment == nil
+	}, true), nil
+}
+
+func (m *memory) GetByParent(id comments.ID) ([]comments.Comment, error) {
+	return m.list(func(comment comments.Comment) bool {
+		return comment.ParentComment != nil && *comment.ParentComment == id
+	}, false), nil
+}
+
+func (m *memory) CountByWorkspaceID(_ context.Context, workspaceID string) (int32, error) {
+	return int32(len(m.list(func(comment comments.Comment) bool {
+		return comment.WorkspaceID != nil && *comment.WorkspaceID == workspaceID
+	}, false))), nil
+}
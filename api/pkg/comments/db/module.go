@@ -8,4 +8,10 @@ import (
 func Module(c *di.Container) {
 	c.Import(db.Module)
 	c.Register(NewRepo)
+	c.Register(NewReactionRepo)
+}
+
+func TestModule(c *di.Container) {
+	c.Register(NewMemory)
+	c.Register(NewReactionMemory)
 }
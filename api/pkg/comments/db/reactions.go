@@ -0,0 +1,97 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"getsturdy.com/api/pkg/comments"
+	"getsturdy.com/api/pkg/users"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type ReactionRepository interface {
+	Create(context.Context, *comments.Reaction) error
+	Delete(ctx context.Context, commentID comments.ID, userID users.ID, emoji string) error
+	ListByCommentID(context.Context, comments.ID) ([]*comments.Reaction, error)
+}
+
+type reactionRepo struct {
+	db *sqlx.DB
+}
+
+func NewReactionRepo(db *sqlx.DB) ReactionRepository {
+	return &reactionRepo{db: db}
+}
+
+func (r *reactionRepo) Create(ctx context.Context, reaction *comments.Reaction) error {
+	if _, err := r.db.NamedExecContext(ctx, `INSERT INTO comment_reactions (comment_id, user_id, emoji, created_at)
+		VALUES (:comment_id, :user_id, :emoji, :created_at)
+		ON CONFLICT (comment_id, user_id, emoji) DO NOTHING`, reaction); err != nil {
+		return fmt.Errorf("failed to perform insert: %w", err)
+	}
+	return nil
+}
+
+func (r *reactionRepo) Delete(ctx context.Context, commentID comments.ID, userID users.ID, emoji string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM comment_reactions
+		WHERE comment_id = $1
+		  AND user_id = $2
+		  AND emoji = $3`, commentID, userID, emoji); err != nil {
+		return fmt.Errorf("failed to perform delete: %w", err)
+	}
+	return nil
+}
+
+func (r *reactionRepo) ListByCommentID(ctx context.Context, commentID comments.ID) ([]*comments.Reaction, error) {
+	var res []*comments.Reaction
+	if err := r.db.SelectContext(ctx, &res, `SELECT comment_id, user_id, emoji, created_at
+		FROM comment_reactions
+		WHERE comment_id = $1
+		ORDER BY created_at ASC`, commentID); err != nil {
+		return nil, fmt.Errorf("failed to query table: %w", err)
+	}
+	return res, nil
+}
+
+var _ ReactionRepository = &reactionMemory{}
+
+type reactionMemory struct {
+	reactions []*comments.Reaction
+}
+
+func NewReactionMemory() ReactionRepository {
+	return &reactionMemory{}
+}
+
+func (m *reactionMemory) Create(_ context.Context, reaction *comments.Reaction) error {
+	for _, r := range m.reactions {
+		if r.CommentID == reaction.CommentID && r.UserID == reaction.UserID && r.Emoji == reaction.Emoji {
+			return nil
+		}
+	}
+	m.reactions = append(m.reactions, reaction)
+	return nil
+}
+
+func (m *reactionMemory) Delete(_ context.Context, commentID comments.ID, userID users.ID, emoji string) error {
+	kept := m.reactions[:0]
+	for _, r := range m.reactions {
+		if r.CommentID == commentID && r.UserID == userID && r.Emoji == emoji {
+			continue
+		}
+		kept = append(kept, r)
+	}
+	m.reactions = kept
+	return nil
+}
+
+func (m *reactionMemory) ListByCommentID(_ context.Context, commentID comments.ID) ([]*comments.Reaction, error) {
+	var res []*comments.Reaction
+	for _, r := range m.reactions {
+		if r.CommentID == commentID {
+			res = append(res, r)
+		}
+	}
+	return res, nil
+}
@@ -9,16 +9,20 @@ import (
 	service_change "getsturdy.com/api/pkg/changes/service"
 	db_codebases "getsturdy.com/api/pkg/codebases/db"
 	graphql_codebases "getsturdy.com/api/pkg/codebases/graphql"
+	service_codebase "getsturdy.com/api/pkg/codebases/service"
 	db_comments "getsturdy.com/api/pkg/comments/db"
+	service_comments "getsturdy.com/api/pkg/comments/service"
 	"getsturdy.com/api/pkg/di"
 	"getsturdy.com/api/pkg/events"
 	eventsv2 "getsturdy.com/api/pkg/events/v2"
 	"getsturdy.com/api/pkg/graphql/resolvers"
 	"getsturdy.com/api/pkg/logger"
+	service_chat "getsturdy.com/api/pkg/notification/chat/service"
 	notification_sender "getsturdy.com/api/pkg/notification/sender"
 	db_snapshots "getsturdy.com/api/pkg/snapshots/db"
 	service_users "getsturdy.com/api/pkg/users/service/module"
 	db_view "getsturdy.com/api/pkg/views/db"
+	service_webhooks "getsturdy.com/api/pkg/webhooks/service"
 	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
 	service_workspace_watchers "getsturdy.com/api/pkg/workspaces/watchers/service"
 	"getsturdy.com/api/vcs/executor"
@@ -26,6 +30,8 @@ import (
 
 func Module(c *di.Container) {
 	c.Import(db_comments.Module)
+	c.Import(service_comments.Module)
+	c.Import(service_codebase.Module)
 	c.Import(db_snapshots.Module)
 	c.Import(db_workspaces.Module)
 	c.Import(db_view.Module)
@@ -33,6 +39,8 @@ func Module(c *di.Container) {
 	c.Import(service_workspace_watchers.Module)
 	c.Import(service_auth.Module)
 	c.Import(service_change.Module)
+	c.Import(service_webhooks.Module)
+	c.Import(service_chat.Module)
 	c.Import(events.Module)
 	c.Import(eventsv2.Module)
 	c.Import(notification_sender.Module)
@@ -0,0 +1,36 @@
+package graphql
+
+import (
+	"context"
+
+	"getsturdy.com/api/pkg/graphql/resolvers"
+	"getsturdy.com/api/pkg/users"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+type CommentReactionResolver struct {
+	root    *CommentRootResolver
+	emoji   string
+	userIDs []users.ID
+}
+
+func (r *CommentReactionResolver) Emoji() string {
+	return r.emoji
+}
+
+func (r *CommentReactionResolver) Count() int32 {
+	return int32(len(r.userIDs))
+}
+
+func (r *CommentReactionResolver) Authors(ctx context.Context) ([]resolvers.AuthorResolver, error) {
+	res := make([]resolvers.AuthorResolver, 0, len(r.userIDs))
+	for _, userID := range r.userIDs {
+		author, err := r.root.authorResolver.Author(ctx, graphql.ID(userID))
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, author)
+	}
+	return res, nil
+}
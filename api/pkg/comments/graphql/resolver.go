@@ -17,22 +17,26 @@ import (
 	"getsturdy.com/api/pkg/codebases"
 	"getsturdy.com/api/pkg/codebases/access"
 	db_codebases "getsturdy.com/api/pkg/codebases/db"
+	service_codebase "getsturdy.com/api/pkg/codebases/service"
 	"getsturdy.com/api/pkg/comments"
 	db_comments "getsturdy.com/api/pkg/comments/db"
 	decorate_comment "getsturdy.com/api/pkg/comments/decorate"
 	"getsturdy.com/api/pkg/comments/live"
+	service_comments "getsturdy.com/api/pkg/comments/service"
 	"getsturdy.com/api/pkg/comments/vcs"
 	"getsturdy.com/api/pkg/events"
 	eventsv2 "getsturdy.com/api/pkg/events/v2"
 	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
 	"getsturdy.com/api/pkg/graphql/resolvers"
 	"getsturdy.com/api/pkg/notification"
+	service_chat "getsturdy.com/api/pkg/notification/chat/service"
 	notification_sender "getsturdy.com/api/pkg/notification/sender"
 	db_snapshots "getsturdy.com/api/pkg/snapshots/db"
 	"getsturdy.com/api/pkg/users"
 	service_users "getsturdy.com/api/pkg/users/service"
 	"getsturdy.com/api/pkg/views"
 	db_view "getsturdy.com/api/pkg/views/db"
+	service_webhooks "getsturdy.com/api/pkg/webhooks/service"
 	"getsturdy.com/api/pkg/workspaces"
 	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
 	service_workspace_watchers "getsturdy.com/api/pkg/workspaces/watchers/service"
@@ -56,6 +60,8 @@ type CommentRootResolver struct {
 	executorProvider executor.Provider
 
 	commentsRepo             db_comments.Repository
+	commentsService          *service_comments.Service
+	codebaseService          *service_codebase.Service
 	snapshotRepo             db_snapshots.Repository
 	workspaceReader          db_workspaces.WorkspaceReader
 	viewRepo                 db_view.Repository
@@ -64,6 +70,8 @@ type CommentRootResolver struct {
 	authService              *service_auth.Service
 	changeService            *service_change.Service
 	userService              service_users.Service
+	webhooksService          *service_webhooks.Service
+	chatService              *service_chat.Service
 
 	eventsReader       events.EventReader
 	eventsSubscriber   *eventsv2.Subscriber
@@ -82,6 +90,8 @@ type CommentRootResolver struct {
 
 func NewResolver(
 	commentsRepo db_comments.Repository,
+	commentsService *service_comments.Service,
+	codebaseService *service_codebase.Service,
 	snapshotRepo db_snapshots.Repository,
 	workspaceReader db_workspaces.WorkspaceReader,
 	viewRepo db_view.Repository,
@@ -89,6 +99,8 @@ func NewResolver(
 	workspaceWatchersService *service_workspace_watchers.Service,
 	authService *service_auth.Service,
 	changeService *service_change.Service,
+	webhooksService *service_webhooks.Service,
+	chatService *service_chat.Service,
 
 	eventsSender events.EventSender,
 	eventsSubscriber *eventsv2.Subscriber,
@@ -110,6 +122,8 @@ func NewResolver(
 		executorProvider: executroProvider,
 
 		commentsRepo:             commentsRepo,
+		commentsService:          commentsService,
+		codebaseService:          codebaseService,
 		snapshotRepo:             snapshotRepo,
 		workspaceReader:          workspaceReader,
 		viewRepo:                 viewRepo,
@@ -118,6 +132,8 @@ func NewResolver(
 		authService:              authService,
 		changeService:            changeService,
 		userService:              userService,
+		webhooksService:          webhooksService,
+		chatService:              chatService,
 
 		eventsSender:       eventsSender,
 		eventsSubscriber:   eventsSubscriber,
@@ -320,6 +336,11 @@ func (r *CommentRootResolver) UpdateComment(ctx context.Context, args resolvers.
 		return nil, gqlerrors.Error(err)
 	}
 
+	previouslyMentioned := map[users.ID]bool{}
+	for _, user := range decorate_comment.ExtractIDMentions(comment.Message, codebaseUsers) {
+		previouslyMentioned[user.ID] = true
+	}
+
 	comment.Message = args.Input.Message
 
 	mentions := decorate_comment.ExtractNameMentions(comment.Message, codebaseUsers)
@@ -332,6 +353,18 @@ func (r *CommentRootResolver) UpdateComment(ctx context.Context, args resolvers.
 		return nil, gqlerrors.Error(err)
 	}
 
+	// only notify users that were not mentioned before the update
+	newlyMentioned := map[users.ID]bool{}
+	for _, mentionedUser := range mentions {
+		if previouslyMentioned[mentionedUser.ID] || mentionedUser.ID == comment.UserID {
+			continue
+		}
+		newlyMentioned[mentionedUser.ID] = true
+	}
+	if err := r.commentsService.NotifyMentioned(ctx, comment, newlyMentioned); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
 	if comment.WorkspaceID != nil {
 		if err := r.eventsSender.Codebase(comment.CodebaseID, events.WorkspaceUpdatedComments, *comment.WorkspaceID); err != nil {
 			r.logger.Error("failed to send workspace updated comments event", zap.Error(err))
@@ -416,6 +449,54 @@ func (r *CommentRootResolver) ResolveComment(ctx context.Context, args resolvers
 	return &CommentResolver{root: r, comment: comm}, nil
 }
 
+func (r *CommentRootResolver) AddCommentReaction(ctx context.Context, args resolvers.CommentReactionArgs) (resolvers.CommentResolver, error) {
+	return r.updateReaction(ctx, args, r.commentsService.AddReaction)
+}
+
+func (r *CommentRootResolver) RemoveCommentReaction(ctx context.Context, args resolvers.CommentReactionArgs) (resolvers.CommentResolver, error) {
+	return r.updateReaction(ctx, args, r.commentsService.RemoveReaction)
+}
+
+func (r *CommentRootResolver) updateReaction(
+	ctx context.Context,
+	args resolvers.CommentReactionArgs,
+	update func(context.Context, *comments.Comment, users.ID, string) error,
+) (resolvers.CommentResolver, error) {
+	userID, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	comm, err := r.commentsRepo.Get(comments.ID(args.Input.CommentID))
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	// everyone that can write to the codebase can react, not only the author of the comment
+	cb, err := r.codebaseService.GetByID(ctx, comm.CodebaseID)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+	if err := r.authService.CanWrite(ctx, cb); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	if err := update(ctx, &comm, userID, args.Input.Emoji); errors.Is(err, service_comments.ErrInvalidReaction) {
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "emoji", err.Error())
+	} else if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	// send events
+	if comm.WorkspaceID != nil {
+		if err := r.eventsSender.Codebase(comm.CodebaseID, events.WorkspaceUpdatedComments, *comm.WorkspaceID); err != nil {
+			r.logger.Error("failed to send event for updated comment", zap.Error(err))
+		}
+	}
+
+	return &CommentResolver{root: r, comment: comm}, nil
+}
+
 func (r *CommentRootResolver) getUsersByCodebaseID(ctx context.Context, codebaseID codebases.ID) ([]*users.User, error) {
 	codebaseUsers, err := r.codebaseUserRepo.GetByCodebase(codebaseID)
 	if err != nil {
@@ -469,27 +550,36 @@ func (r *CommentRootResolver) CreateComment(ctx context.Context, args resolvers.
 		return nil, gqlerrors.Error(err)
 	}
 
-	if comment.ChangeID != nil {
-		sendNotificationsTo := map[users.ID]struct{}{}
+	if err := r.webhooksService.CommentCreated(ctx, comment); err != nil {
+		r.logger.Error("failed to send comment created webhook", zap.Error(err))
+		// do not fail
+	}
+
+	// mentioned users get a mention notification, and no other notifications about the same comment
+	mentioned := map[users.ID]bool{}
+	for _, mentionedUser := range mentions {
+		if mentionedUser.ID == comment.UserID {
+			continue
+		}
+		mentioned[mentionedUser.ID] = true
+	}
+	if err := r.commentsService.NotifyMentioned(ctx, comment, mentioned); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
 
+	if err := r.chatService.CommentCreated(ctx, comment, mentioned); err != nil {
+		r.logger.Error("failed to send comment created chat notification", zap.Error(err))
+		// do not fail
+	}
+
+	if comment.ChangeID != nil {
 		// Notify change author
 		change, err := r.changeService.GetChangeByID(ctx, *comment.ChangeID)
 		if err != nil {
 			return nil, gqlerrors.Error(err)
 		}
-		if change.UserID != nil && comment.UserID != *change.UserID {
-			sendNotificationsTo[*change.UserID] = struct{}{}
-		}
-
-		// Notify mentioned users
-		for _, mentionedUser := range mentions {
-			if mentionedUser.ID == comment.UserID {
-				continue
-			}
-			sendNotificationsTo[mentionedUser.ID] = struct{}{}
-		}
-		for userID := range sendNotificationsTo {
-			if err := r.notificationSender.User(ctx, userID, notification.CommentNotificationType, string(comment.ID)); err != nil {
+		if change.UserID != nil && comment.UserID != *change.UserID && !mentioned[*change.UserID] {
+			if err := r.notificationSender.User(ctx, *change.UserID, notification.CommentNotificationType, string(comment.ID)); err != nil {
 				r.logger.Error("failed to send comment notification", zap.Error(err))
 				// do not fail
 			}
@@ -500,13 +590,6 @@ func (r *CommentRootResolver) CreateComment(ctx context.Context, args resolvers.
 		return &CommentResolver{root: r, comment: *comment}, nil
 	}
 
-	// all mentioned users start watching the workspace
-	for _, mentionedUser := range mentions {
-		if _, err := r.workspaceWatchersService.Watch(ctx, mentionedUser.ID, *comment.WorkspaceID); err != nil {
-			return nil, gqlerrors.Error(fmt.Errorf("failed to watch workspace: %w", err))
-		}
-	}
-
 	// comment author starts watching the workspace
 	if _, err := r.workspaceWatchersService.Watch(ctx, comment.UserID, *comment.WorkspaceID); err != nil {
 		return nil, gqlerrors.Error(fmt.Errorf("failed to watch workspace: %w", err))
@@ -523,8 +606,8 @@ func (r *CommentRootResolver) CreateComment(ctx context.Context, args resolvers.
 		return nil, gqlerrors.Error(fmt.Errorf("failed to list workspace watchers: %w", err))
 	}
 	for _, watcher := range watchers {
-		// Skip sending notification to the user who created the comment
-		if watcher.UserID == comment.UserID {
+		// Skip sending notification to the user who created the comment, and to the mentioned users
+		if watcher.UserID == comment.UserID || mentioned[watcher.UserID] {
 			continue
 		}
 		if err := r.notificationSender.User(ctx, watcher.UserID, notification.CommentNotificationType, string(comment.ID)); err != nil {
@@ -741,6 +824,27 @@ func (r *CommentResolver) Message() string {
 	return r.comment.Message
 }
 
+func (r *CommentResolver) Reactions(ctx context.Context) ([]resolvers.CommentReactionResolver, error) {
+	reactions, err := r.root.commentsService.ListReactions(ctx, r.comment.ID)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	// group by emoji, in the order of the first reaction
+	byEmoji := map[string]*CommentReactionResolver{}
+	res := []resolvers.CommentReactionResolver{}
+	for _, reaction := range reactions {
+		if rr, ok := byEmoji[reaction.Emoji]; ok {
+			rr.userIDs = append(rr.userIDs, reaction.UserID)
+			continue
+		}
+		rr := &CommentReactionResolver{root: r.root, emoji: reaction.Emoji, userIDs: []users.ID{reaction.UserID}}
+		byEmoji[reaction.Emoji] = rr
+		res = append(res, rr)
+	}
+	return res, nil
+}
+
 func allAreEqual(a ...bool) bool {
 	if len(a) == 0 {
 		return true
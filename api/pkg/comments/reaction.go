@@ -0,0 +1,26 @@
+package comments
+
+import (
+	"time"
+
+	"getsturdy.com/api/pkg/users"
+)
+
+// Reactions are the emojis that users can react to comments with.
+var Reactions = []string{"👍", "👎", "😄", "🎉", "😕", "❤️", "🚀", "👀"}
+
+func IsValidReaction(emoji string) bool {
+	for _, r := range Reactions {
+		if r == emoji {
+			return true
+		}
+	}
+	return false
+}
+
+type Reaction struct {
+	CommentID ID        `db:"comment_id"`
+	UserID    users.ID  `db:"user_id"`
+	Emoji     string    `db:"emoji"`
+	CreatedAt time.Time `db:"created_at"`
+}
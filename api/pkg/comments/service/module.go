@@ -3,9 +3,15 @@ package service
 import (
 	db_comments "getsturdy.com/api/pkg/comments/db"
 	"getsturdy.com/api/pkg/di"
+	"getsturdy.com/api/pkg/logger"
+	sender_notification "getsturdy.com/api/pkg/notification/sender"
+	service_workspace_watchers "getsturdy.com/api/pkg/workspaces/watchers/service"
 )
 
 func Module(c *di.Container) {
+	c.Import(logger.Module)
 	c.Import(db_comments.Module)
+	c.Import(service_workspace_watchers.Module)
+	c.Import(sender_notification.Module)
 	c.Register(New)
 }
@@ -2,19 +2,44 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"getsturdy.com/api/pkg/changes"
+	"getsturdy.com/api/pkg/comments"
 	db_comments "getsturdy.com/api/pkg/comments/db"
+	"getsturdy.com/api/pkg/notification"
+	sender_notification "getsturdy.com/api/pkg/notification/sender"
+	"getsturdy.com/api/pkg/users"
+	service_workspace_watchers "getsturdy.com/api/pkg/workspaces/watchers/service"
+
+	"go.uber.org/zap"
 )
 
+var ErrInvalidReaction = errors.New("unsupported reaction")
+
 type Service struct {
-	commentRepo db_comments.Repository
+	logger                   *zap.Logger
+	commentRepo              db_comments.Repository
+	reactionRepo             db_comments.ReactionRepository
+	workspaceWatchersService *service_workspace_watchers.Service
+	notificationSender       sender_notification.NotificationSender
 }
 
-func New(commentRepo db_comments.Repository) *Service {
+func New(
+	logger *zap.Logger,
+	commentRepo db_comments.Repository,
+	reactionRepo db_comments.ReactionRepository,
+	workspaceWatchersService *service_workspace_watchers.Service,
+	notificationSender sender_notification.NotificationSender,
+) *Service {
 	return &Service{
-		commentRepo: commentRepo,
+		logger:                   logger.Named("commentsService"),
+		commentRepo:              commentRepo,
+		reactionRepo:             reactionRepo,
+		workspaceWatchersService: workspaceWatchersService,
+		notificationSender:       notificationSender,
 	}
 }
 
@@ -33,3 +58,75 @@ func (s *Service) MoveCommentsFromWorkspaceToChange(ctx context.Context, workspa
 	}
 	return nil
 }
+
+// HasUnresolved returns true if the workspace has comments that are not resolved.
+func (s *Service) HasUnresolved(ctx context.Context, workspaceID string) (bool, error) {
+	comments, err := s.commentRepo.GetByWorkspace(workspaceID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get comments in workspace: %w", err)
+	}
+	for _, comment := range comments {
+		if comment.ResolvedAt == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// NotifyMentioned sends mention notifications to the users that are mentioned in the comment. The author of the
+// comment is never notified. If the comment is in a workspace, the mentioned users also start watching it.
+func (s *Service) NotifyMentioned(ctx context.Context, comment *comments.Comment, userIDs map[users.ID]bool) error {
+	for userID := range userIDs {
+		if userID == comment.UserID {
+			continue
+		}
+
+		if comment.WorkspaceID != nil {
+			if _, err := s.workspaceWatchersService.Watch(ctx, userID, *comment.WorkspaceID); err != nil {
+				return fmt.Errorf("failed to watch workspace: %w", err)
+			}
+		}
+
+		if err := s.notificationSender.User(ctx, userID, notification.MentionNotificationType, string(comment.ID)); err != nil {
+			s.logger.Error("failed to send mention notification", zap.Error(err))
+			// do not fail
+		}
+	}
+	return nil
+}
+
+// AddReaction adds the reaction of the user to the comment. Adding the same reaction twice has no effect.
+func (s *Service) AddReaction(ctx context.Context, comment *comments.Comment, userID users.ID, emoji string) error {
+	if !comments.IsValidReaction(emoji) {
+		return ErrInvalidReaction
+	}
+	if err := s.reactionRepo.Create(ctx, &comments.Reaction{
+		CommentID: comment.ID,
+		UserID:    userID,
+		Emoji:     emoji,
+		CreatedAt: time.Now(),
+	}); err != nil {
+		return fmt.Errorf("failed to create reaction: %w", err)
+	}
+	return nil
+}
+
+// RemoveReaction removes the reaction of the user from the comment, if the user has reacted with it.
+func (s *Service) RemoveReaction(ctx context.Context, comment *comments.Comment, userID users.ID, emoji string) error {
+	if !comments.IsValidReaction(emoji) {
+		return ErrInvalidReaction
+	}
+	if err := s.reactionRepo.Delete(ctx, comment.ID, userID, emoji); err != nil {
+		return fmt.Errorf("failed to delete reaction: %w", err)
+	}
+	return nil
+}
+
+// ListReactions returns the reactions to the comment, oldest first.
+func (s *Service) ListReactions(ctx context.Context, commentID comments.ID) ([]*comments.Reaction, error) {
+	reactions, err := s.reactionRepo.ListByCommentID(ctx, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reactions: %w", err)
+	}
+	return reactions, nil
+}
@@ -0,0 +1,127 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"getsturdy.com/api/pkg/codebases"
+	db_codebases "getsturdy.com/api/pkg/codebases/db"
+	"getsturdy.com/api/pkg/comments"
+	db_comments "getsturdy.com/api/pkg/comments/db"
+	service_comments "getsturdy.com/api/pkg/comments/service"
+	"getsturdy.com/api/pkg/di"
+	"getsturdy.com/api/pkg/logger"
+	"getsturdy.com/api/pkg/notification"
+	sender_notification "getsturdy.com/api/pkg/notification/sender"
+	"getsturdy.com/api/pkg/users"
+	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
+	db_watchers "getsturdy.com/api/pkg/workspaces/watchers/db"
+	service_workspace_watchers "getsturdy.com/api/pkg/workspaces/watchers/service"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sentNotification struct {
+	userID           users.ID
+	notificationType notification.NotificationType
+	referenceID      string
+}
+
+// notificationSender records the notifications that are sent to users.
+type notificationSender struct {
+	sent []sentNotification
+}
+
+func (s *notificationSender) Codebase(context.Context, codebases.ID, notification.NotificationType, string, users.ID) error {
+	return nil
+}
+
+func (s *notificationSender) User(_ context.Context, userID users.ID, notificationType notification.NotificationType, referenceID string) error {
+	s.sent = append(s.sent, sentNotification{userID: userID, notificationType: notificationType, referenceID: referenceID})
+	return nil
+}
+
+type test struct {
+	sender          *notificationSender
+	commentsService *service_comments.Service
+	watchersService *service_workspace_watchers.Service
+}
+
+func newTest(t *testing.T) *test {
+	test := &test{sender: &notificationSender{}}
+	module := func(c *di.Container) {
+		c.Import(service_comments.Module)
+		c.ImportWithForce(db_comments.TestModule)
+		c.ImportWithForce(db_codebases.TestModule)
+		c.ImportWithForce(db_workspaces.TestModule)
+
+		c.RegisterWithForce(func() db_watchers.Repository { return db_watchers.NewInMemory() })
+		c.RegisterWithForce(func() sender_notification.NotificationSender { return test.sender })
+		c.RegisterWithForce(func() *sqlx.DB { return nil })
+		c.RegisterWithForce(logger.NewTest)
+		c.Register(func() *testing.T { return t })
+	}
+	require.NoError(t, di.Init(module).To(&test.commentsService, &test.watchersService))
+	return test
+}
+
+func TestNotifyMentioned(t *testing.T) {
+	test := newTest(t)
+	ctx := context.Background()
+
+	workspaceID := "workspace"
+	comment := &comments.Comment{ID: "comment", UserID: "author", WorkspaceID: &workspaceID}
+
+	require.NoError(t, test.commentsService.NotifyMentioned(ctx, comment, map[users.ID]bool{
+		"author":    true,
+		"mentioned": true,
+	}))
+
+	// the author is never notified about their own comment
+	assert.Equal(t, []sentNotification{
+		{userID: "mentioned", notificationType: notification.MentionNotificationType, referenceID: "comment"},
+	}, test.sender.sent)
+
+	// mentioned users start watching the workspace
+	watchers, err := test.watchersService.ListWatchers(ctx, workspaceID)
+	require.NoError(t, err)
+	if assert.Len(t, watchers, 1) {
+		assert.Equal(t, users.ID("mentioned"), watchers[0].UserID)
+	}
+}
+
+func TestReactions(t *testing.T) {
+	test := newTest(t)
+	ctx := context.Background()
+
+	comment := &comments.Comment{ID: "comment", UserID: "author"}
+
+	require.NoError(t, test.commentsService.AddReaction(ctx, comment, "user1", "👍"))
+	require.NoError(t, test.commentsService.AddReaction(ctx, comment, "user2", "🎉"))
+	// reacting twice has no effect
+	require.NoError(t, test.commentsService.AddReaction(ctx, comment, "user1", "👍"))
+
+	reactions, err := test.commentsService.ListReactions(ctx, comment.ID)
+	require.NoError(t, err)
+	if assert.Len(t, reactions, 2) {
+		assert.Equal(t, users.ID("user1"), reactions[0].UserID)
+		assert.Equal(t, "👍", reactions[0].Emoji)
+		assert.Equal(t, users.ID("user2"), reactions[1].UserID)
+		assert.Equal(t, "🎉", reactions[1].Emoji)
+	}
+
+	// users can only remove their own reactions
+	require.NoError(t, test.commentsService.RemoveReaction(ctx, comment, "user2", "👍"))
+	require.NoError(t, test.commentsService.RemoveReaction(ctx, comment, "user1", "👍"))
+
+	reactions, err = test.commentsService.ListReactions(ctx, comment.ID)
+	require.NoError(t, err)
+	if assert.Len(t, reactions, 1) {
+		assert.Equal(t, users.ID("user2"), reactions[0].UserID)
+	}
+
+	assert.ErrorIs(t, test.commentsService.AddReaction(ctx, comment, "user1", "not an emoji"), service_comments.ErrInvalidReaction)
+	assert.ErrorIs(t, test.commentsService.RemoveReaction(ctx, comment, "user1", "not an emoji"), service_comments.ErrInvalidReaction)
+}
@@ -6,14 +6,18 @@ import (
 	"os"
 
 	proxy "getsturdy.com/api/pkg/analytics/proxy/configuration"
+	blobs "getsturdy.com/api/pkg/blobs/store/configuration"
 	service_ci "getsturdy.com/api/pkg/ci/service/configuration"
 	db "getsturdy.com/api/pkg/db/configuration"
 	"getsturdy.com/api/pkg/di"
+	emails "getsturdy.com/api/pkg/emails/smtp/configuration"
 	gitserver "getsturdy.com/api/pkg/gitserver/configuration"
 	http "getsturdy.com/api/pkg/http/configuration"
 	logger "getsturdy.com/api/pkg/logger/configuration"
 	metrics "getsturdy.com/api/pkg/metrics/configuration"
+	chat "getsturdy.com/api/pkg/notification/chat/configuration"
 	pprof "getsturdy.com/api/pkg/pprof/configuration"
+	oidc "getsturdy.com/api/pkg/sso/oidc/configuration"
 	uploader "getsturdy.com/api/pkg/users/avatars/uploader/configuration"
 	provider "getsturdy.com/api/vcs/provider/configuration"
 
@@ -31,6 +35,7 @@ type Base struct {
 	Pprof    *pprof.Configuration      `flags-group:"pprof" namespace:"pprof"`
 	Metrics  *metrics.Configuration    `flags-group:"metrics" namespace:"metrics"`
 	Logger   *logger.Configuration     `flags-group:"logger" namespace:"logger"`
+	Chat     *chat.Configuration       `flags-group:"chat" namespace:"notification.chat"`
 }
 
 type Configuration struct {
@@ -38,6 +43,9 @@ type Configuration struct {
 
 	Analytics *proxy.Configuration    `flags-group:"analytics" namespace:"analytics"`
 	Avatars   *uploader.Configuration `flags-group:"avatars" namespace:"users.avatars"`
+	Blobs     *blobs.Configuration    `flags-group:"blobs" namespace:"blobs" env-namespace:"STURDY_BLOBS"`
+	Emails    *emails.Configuration   `flags-group:"emails" namespace:"emails"`
+	OIDC      *oidc.Configuration     `flags-group:"oidc" namespace:"oidc" env-namespace:"STURDY_OIDC"`
 }
 
 func New() (Configuration, error) {
@@ -7,11 +7,13 @@ import (
 
 	posthog "getsturdy.com/api/pkg/analytics/enterprise/cloud/posthog/configuration"
 	aws "getsturdy.com/api/pkg/aws/enterprise/cloud/configuration"
+	blobs "getsturdy.com/api/pkg/blobs/store/configuration"
 	"getsturdy.com/api/pkg/configuration"
 	service_change_downloads "getsturdy.com/api/pkg/downloads/enterprise/cloud/service/configuration"
 	emails "getsturdy.com/api/pkg/emails/enterprise/cloud/configuration"
 	"getsturdy.com/api/pkg/github/enterprise/config"
 	queue "getsturdy.com/api/pkg/queue/enterprise/cloud/configuration"
+	oidc "getsturdy.com/api/pkg/sso/oidc/configuration"
 
 	"github.com/jessevdk/go-flags"
 )
@@ -25,6 +27,8 @@ type Configuration struct {
 	Emails           *emails.Configuration                   `flags-group:"emails" namespace:"emails"`
 	Queue            *queue.Configuration                    `flags-group:"queue" namespace:"queue"`
 	ChangesDownloads *service_change_downloads.Configuration `flags-group:"downloads" namespace:"downloads"`
+	OIDC             *oidc.Configuration                     `flags-group:"oidc" namespace:"oidc" env-namespace:"STURDY_OIDC"`
+	Blobs            *blobs.Configuration                    `flags-group:"blobs" namespace:"blobs" env-namespace:"STURDY_BLOBS"`
 }
 
 func New() (Configuration, error) {
@@ -6,8 +6,11 @@ import (
 	"os"
 
 	proxy "getsturdy.com/api/pkg/analytics/proxy/configuration"
+	blobs "getsturdy.com/api/pkg/blobs/store/configuration"
 	"getsturdy.com/api/pkg/configuration"
+	emails "getsturdy.com/api/pkg/emails/smtp/configuration"
 	"getsturdy.com/api/pkg/github/enterprise/config"
+	oidc "getsturdy.com/api/pkg/sso/oidc/configuration"
 	uploader "getsturdy.com/api/pkg/users/avatars/uploader/configuration"
 
 	"github.com/jessevdk/go-flags"
@@ -19,6 +22,9 @@ type Configuration struct {
 	GitHub    *config.GitHubAppConfig `flags-group:"github-app" namespace:"github-app" env-namespace:"STURDY_GITHUB_APP"`
 	Analytics *proxy.Configuration    `flags-group:"analytics" namespace:"analytics"`
 	Avatars   *uploader.Configuration `flags-group:"avatars" namespace:"users.avatars"`
+	Blobs     *blobs.Configuration    `flags-group:"blobs" namespace:"blobs" env-namespace:"STURDY_BLOBS"`
+	Emails    *emails.Configuration   `flags-group:"emails" namespace:"emails"`
+	OIDC      *oidc.Configuration     `flags-group:"oidc" namespace:"oidc" env-namespace:"STURDY_OIDC"`
 }
 
 func New() (Configuration, error) {
@@ -5,16 +5,20 @@ import (
 	"time"
 
 	proxy "getsturdy.com/api/pkg/analytics/proxy/configuration"
+	blobs "getsturdy.com/api/pkg/blobs/store/configuration"
 	service_ci "getsturdy.com/api/pkg/ci/service/configuration"
 	"getsturdy.com/api/pkg/configuration/flags"
 	db "getsturdy.com/api/pkg/db/configuration"
 	"getsturdy.com/api/pkg/di"
+	emails "getsturdy.com/api/pkg/emails/smtp/configuration"
 	gitserver "getsturdy.com/api/pkg/gitserver/configuration"
 	http "getsturdy.com/api/pkg/http/configuration"
 	"getsturdy.com/api/pkg/internal/sturdytest"
 	logger "getsturdy.com/api/pkg/logger/configuration"
 	metrics "getsturdy.com/api/pkg/metrics/configuration"
+	chat "getsturdy.com/api/pkg/notification/chat/configuration"
 	pprof "getsturdy.com/api/pkg/pprof/configuration"
+	oidc "getsturdy.com/api/pkg/sso/oidc/configuration"
 	uploader "getsturdy.com/api/pkg/users/avatars/uploader/configuration"
 	provider "getsturdy.com/api/vcs/provider/configuration"
 )
@@ -73,10 +77,14 @@ func TestModule(c *di.Container) {
 				Logger: &logger.Configuration{
 					Level: "INFO",
 				},
+				Chat: &chat.Configuration{},
 			},
 
 			Analytics: &proxy.Configuration{Disable: true},
 			Avatars:   &uploader.Configuration{},
+			Emails:    &emails.Configuration{},
+			OIDC:      &oidc.Configuration{},
+			Blobs:     &blobs.Configuration{Backend: "postgres"},
 		}, nil
 	})
 }
@@ -0,0 +1,2 @@
+ALTER TABLE workspaces
+    DROP COLUMN base_workspace_id;
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE workspaces
+    ADD COLUMN base_workspace_id TEXT NULL;
+
+CREATE INDEX workspaces_base_workspace_id_idx ON workspaces (base_workspace_id);
\ No newline at end of file
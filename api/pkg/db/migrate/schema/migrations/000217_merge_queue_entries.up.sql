@@ -0,0 +1,18 @@
+CREATE TABLE merge_queue_entries
+(
+    id               TEXT PRIMARY KEY,
+    codebase_id      TEXT                     NOT NULL,
+    workspace_id     TEXT                     NOT NULL,
+    user_id          TEXT                     NOT NULL,
+    status           TEXT                     NOT NULL,
+    snapshot_id      TEXT                     NULL,
+    trunk_commit_sha TEXT                     NULL,
+    change_id        TEXT                     NULL,
+    eject_reason     TEXT                     NULL,
+    created_at       TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at       TIMESTAMP WITH TIME ZONE NOT NULL,
+    completed_at     TIMESTAMP WITH TIME ZONE NULL
+);
+
+CREATE INDEX merge_queue_entries_codebase_id_idx ON merge_queue_entries (codebase_id);
+CREATE INDEX merge_queue_entries_workspace_id_idx ON merge_queue_entries (workspace_id);
@@ -0,0 +1,14 @@
+CREATE TABLE access_tokens
+(
+    id           TEXT PRIMARY KEY,
+    user_id      TEXT                     NOT NULL,
+    name         TEXT                     NOT NULL,
+    hash         BYTEA                    NOT NULL,
+    scopes       TEXT[]                   NOT NULL,
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at   TIMESTAMP WITH TIME ZONE NULL,
+    last_used_at TIMESTAMP WITH TIME ZONE NULL,
+    revoked_at   TIMESTAMP WITH TIME ZONE NULL
+);
+
+CREATE INDEX access_tokens_user_id_idx ON access_tokens (user_id);
@@ -0,0 +1 @@
+DROP TABLE ci_configurations_webhook;
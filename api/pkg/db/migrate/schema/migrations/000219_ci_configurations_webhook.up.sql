@@ -0,0 +1,13 @@
+CREATE TABLE ci_configurations_webhook
+(
+    id             TEXT PRIMARY KEY,
+    codebase_id    TEXT                     NOT NULL,
+    integration_id TEXT                     NOT NULL UNIQUE,
+    name           TEXT                     NOT NULL,
+    url            TEXT                     NOT NULL,
+    secret         TEXT                     NOT NULL,
+    created_at     TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at     TIMESTAMP WITH TIME ZONE NULL
+);
+
+CREATE INDEX ci_configurations_webhook_codebase_id_idx ON ci_configurations_webhook (codebase_id);
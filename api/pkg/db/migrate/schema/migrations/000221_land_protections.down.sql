@@ -0,0 +1 @@
+DROP TABLE land_protections;
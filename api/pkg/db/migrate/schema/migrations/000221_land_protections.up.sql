@@ -0,0 +1,11 @@
+CREATE TABLE land_protections
+(
+    codebase_id               TEXT PRIMARY KEY,
+    min_approvals             INTEGER                  NOT NULL DEFAULT 0,
+    block_on_rejection        BOOLEAN                  NOT NULL DEFAULT FALSE,
+    require_resolved_comments BOOLEAN                  NOT NULL DEFAULT FALSE,
+    require_up_to_date        BOOLEAN                  NOT NULL DEFAULT FALSE,
+    restrict_landing          BOOLEAN                  NOT NULL DEFAULT FALSE,
+    updated_by                TEXT                     NOT NULL,
+    updated_at                TIMESTAMP WITH TIME ZONE NOT NULL
+);
@@ -0,0 +1,2 @@
+DROP TABLE sso_identities;
+DROP TABLE organization_sso_settings;
@@ -0,0 +1,20 @@
+CREATE TABLE organization_sso_settings
+(
+    organization_id TEXT PRIMARY KEY,
+    enforce_sso     BOOLEAN                  NOT NULL DEFAULT FALSE,
+    jit_membership  BOOLEAN                  NOT NULL DEFAULT FALSE,
+    email_domain    TEXT,
+    updated_by      TEXT                     NOT NULL,
+    updated_at      TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX organization_sso_settings_email_domain_idx ON organization_sso_settings (email_domain) WHERE jit_membership;
+
+CREATE TABLE sso_identities
+(
+    issuer     TEXT                     NOT NULL,
+    subject    TEXT                     NOT NULL,
+    user_id    TEXT                     NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (issuer, subject)
+);
@@ -0,0 +1,23 @@
+ALTER TABLE organization_members
+    ADD COLUMN role TEXT NOT NULL DEFAULT 'member';
+
+-- creators of organizations are owners
+UPDATE organization_members
+SET role = 'owner'
+FROM organizations
+WHERE organizations.id = organization_members.organization_id
+  AND organizations.created_by = organization_members.user_id
+  AND organization_members.deleted_at IS NULL;
+
+-- organizations where the creator has left are owned by the oldest member
+UPDATE organization_members
+SET role = 'owner'
+WHERE id IN (SELECT DISTINCT ON (organization_id) id
+             FROM organization_members m
+             WHERE m.deleted_at IS NULL
+               AND NOT EXISTS(SELECT 1
+                              FROM organization_members o
+                              WHERE o.organization_id = m.organization_id
+                                AND o.role = 'owner'
+                                AND o.deleted_at IS NULL)
+             ORDER BY organization_id, created_at);
@@ -0,0 +1,4 @@
+ALTER TABLE blobs
+    DROP COLUMN content_type,
+    DROP COLUMN size,
+    DROP COLUMN created_at;
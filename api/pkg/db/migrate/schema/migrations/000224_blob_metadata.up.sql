@@ -0,0 +1,12 @@
+ALTER TABLE blobs
+    ADD COLUMN content_type TEXT NOT NULL DEFAULT 'application/octet-stream',
+    ADD COLUMN size BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN created_at TIMESTAMP NOT NULL DEFAULT NOW();
+
+UPDATE blobs
+SET size = COALESCE(OCTET_LENGTH(data), 0);
+
+-- all blobs that exist so far are avatars
+UPDATE blobs
+SET content_type = 'image/png'
+WHERE id LIKE '%.png';
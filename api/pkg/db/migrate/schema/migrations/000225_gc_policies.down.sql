@@ -0,0 +1,10 @@
+DROP INDEX ci_commits_codebase_id_created_at_idx;
+
+DROP INDEX codebases_garbage_collection_status_codebase_id_completed_at_idx;
+
+ALTER TABLE codebases_garbage_collection_status
+    DROP COLUMN collected_snapshots,
+    DROP COLUMN collected_ci_commits,
+    DROP COLUMN triggered_by;
+
+DROP TABLE gc_policies;
@@ -0,0 +1,20 @@
+CREATE TABLE gc_policies (
+    codebase_id                         TEXT PRIMARY KEY NOT NULL,
+    snapshot_retention_hours            INT              NOT NULL,
+    archived_workspace_retention_hours  INT              NOT NULL,
+    suggestion_snapshot_retention_hours INT,
+    ci_commit_retention_hours           INT,
+    updated_by                          TEXT,
+    updated_at                          TIMESTAMP WITH TIME ZONE
+);
+
+ALTER TABLE codebases_garbage_collection_status
+    ADD COLUMN collected_snapshots INT NOT NULL DEFAULT 0,
+    ADD COLUMN collected_ci_commits INT NOT NULL DEFAULT 0,
+    ADD COLUMN triggered_by TEXT;
+
+CREATE INDEX codebases_garbage_collection_status_codebase_id_completed_at_idx
+    ON codebases_garbage_collection_status (codebase_id, completed_at);
+
+CREATE INDEX ci_commits_codebase_id_created_at_idx
+    ON ci_commits (codebase_id, created_at);
@@ -0,0 +1 @@
+DROP INDEX snapshots_workspace_id_created_at_idx;
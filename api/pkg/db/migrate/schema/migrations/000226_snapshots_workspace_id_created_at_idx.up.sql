@@ -0,0 +1 @@
+CREATE INDEX snapshots_workspace_id_created_at_idx ON snapshots (workspace_id, created_at);
@@ -0,0 +1,4 @@
+DROP INDEX workspace_reviews_snapshot_id_idx;
+
+ALTER TABLE workspace_reviews
+    DROP COLUMN snapshot_id;
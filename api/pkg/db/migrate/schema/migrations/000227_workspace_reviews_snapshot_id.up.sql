@@ -0,0 +1,4 @@
+ALTER TABLE workspace_reviews
+    ADD COLUMN snapshot_id TEXT;
+
+CREATE INDEX workspace_reviews_snapshot_id_idx ON workspace_reviews (snapshot_id);
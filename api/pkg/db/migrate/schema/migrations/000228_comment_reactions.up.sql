@@ -0,0 +1,8 @@
+CREATE TABLE comment_reactions
+(
+    comment_id TEXT                     NOT NULL,
+    user_id    TEXT                     NOT NULL,
+    emoji      TEXT                     NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (comment_id, user_id, emoji)
+);
@@ -0,0 +1,7 @@
+CREATE TABLE chat_integrations
+(
+    codebase_id TEXT PRIMARY KEY,
+    url         TEXT                     NOT NULL,
+    updated_by  TEXT                     NOT NULL,
+    updated_at  TIMESTAMP WITH TIME ZONE NOT NULL
+);
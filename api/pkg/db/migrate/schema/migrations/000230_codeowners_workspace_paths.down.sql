@@ -0,0 +1 @@
+DROP TABLE codeowners_workspace_paths;
@@ -0,0 +1,6 @@
+CREATE TABLE codeowners_workspace_paths
+(
+    workspace_id       TEXT PRIMARY KEY,
+    owned_paths_digest TEXT                     NOT NULL,
+    updated_at         TIMESTAMP WITH TIME ZONE NOT NULL
+);
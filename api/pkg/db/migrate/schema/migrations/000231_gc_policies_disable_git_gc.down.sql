@@ -0,0 +1,2 @@
+ALTER TABLE gc_policies
+    DROP COLUMN disable_git_gc;
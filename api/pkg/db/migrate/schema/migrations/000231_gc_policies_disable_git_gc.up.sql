@@ -0,0 +1,2 @@
+ALTER TABLE gc_policies
+    ADD COLUMN disable_git_gc BOOLEAN NOT NULL DEFAULT FALSE;
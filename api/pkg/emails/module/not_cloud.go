@@ -4,10 +4,14 @@
 package module
 
 import (
+	configuration "getsturdy.com/api/pkg/configuration/module"
 	"getsturdy.com/api/pkg/di"
-	"getsturdy.com/api/pkg/emails"
+	"getsturdy.com/api/pkg/emails/smtp"
+	"getsturdy.com/api/pkg/logger"
 )
 
 func Module(c *di.Container) {
-	c.Register(emails.NewDisabled)
+	c.Import(configuration.Module)
+	c.Import(logger.Module)
+	c.Register(smtp.New)
 }
@@ -0,0 +1,13 @@
+package configuration
+
+type Configuration struct {
+	Enable      bool    `long:"enable" description:"Send emails over SMTP"`
+	Host        string  `long:"host" description:"Hostname of the SMTP server" default:"localhost"`
+	Port        int     `long:"port" description:"Port of the SMTP server" default:"587"`
+	TLS         string  `long:"tls" description:"How to secure the connection to the SMTP server" choice:"starttls" choice:"implicit" choice:"none" default:"starttls"`
+	Username    string  `long:"username" description:"Username to authenticate with, authentication is disabled if empty"`
+	Password    string  `long:"password" description:"Password to authenticate with"`
+	From        string  `long:"from" description:"Address that emails are sent from, for example 'Sturdy <no-reply@example.com>'"`
+	RateLimit   float64 `long:"rate-limit" description:"Maximum number of emails to send per second, 0 for no limit" default:"5"`
+	MaxAttempts int     `long:"max-attempts" description:"Maximum number of attempts to send an email, if the server fails temporarily" default:"3"`
+}
@@ -0,0 +1,300 @@
+package smtp
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"sync"
+	"time"
+
+	"getsturdy.com/api/pkg/emails"
+	"getsturdy.com/api/pkg/emails/smtp/configuration"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	TLSStartTLS = "starttls"
+	TLSImplicit = "implicit"
+	TLSNone     = "none"
+)
+
+// timeout is the maximum time to connect to the server and send an email.
+const timeout = 30 * time.Second
+
+var (
+	ErrNoFrom       = errors.New("from address is not configured")
+	ErrInvalidTLS   = errors.New("invalid tls mode")
+	ErrInsecureAuth = errors.New("authentication without tls is only allowed to localhost")
+)
+
+var _ emails.Sender = &Sender{}
+
+// Sender sends emails over SMTP. A new connection is made for every email.
+type Sender struct {
+	logger *zap.Logger
+
+	addr     string
+	host     string
+	tlsMode  string
+	auth     smtp.Auth
+	from     *mail.Address
+	attempts int
+	backoff  time.Duration
+	timeout  time.Duration
+
+	// interval is the minimum time between two emails
+	interval time.Duration
+	mu       sync.Mutex
+	next     time.Time
+}
+
+// New returns a SMTP sender, or a disabled sender if SMTP is not enabled.
+func New(cfg *configuration.Configuration, logger *zap.Logger) (emails.Sender, error) {
+	if cfg == nil || !cfg.Enable {
+		return emails.NewDisabled(), nil
+	}
+	return NewSender(cfg, logger)
+}
+
+func NewSender(cfg *configuration.Configuration, logger *zap.Logger) (*Sender, error) {
+	if cfg.From == "" {
+		return nil, ErrNoFrom
+	}
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from address: %w", err)
+	}
+
+	switch cfg.TLS {
+	case TLSStartTLS, TLSImplicit, TLSNone:
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTLS, cfg.TLS)
+	}
+
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		// smtp.PlainAuth refuses to send the password over an unencrypted connection, except to localhost
+		if cfg.TLS == TLSNone && !isLocalhost(cfg.Host) {
+			return nil, fmt.Errorf("%w: %q", ErrInsecureAuth, cfg.Host)
+		}
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+
+	var interval time.Duration
+	if cfg.RateLimit > 0 {
+		interval = time.Duration(float64(time.Second) / cfg.RateLimit)
+	}
+
+	attempts := cfg.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	return &Sender{
+		logger: logger.Named("smtpSender"),
+
+		addr:     net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		host:     cfg.Host,
+		tlsMode:  cfg.TLS,
+		auth:     auth,
+		from:     from,
+		attempts: attempts,
+		backoff:  time.Second,
+		timeout:  timeout,
+		interval: interval,
+	}, nil
+}
+
+func (s *Sender) Send(ctx context.Context, msg *emails.Email) error {
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("invalid recipient: %w", err)
+	}
+
+	body, err := s.message(to, msg)
+	if err != nil {
+		return fmt.Errorf("failed to build message: %w", err)
+	}
+
+	for attempt := 1; ; attempt++ {
+		if err := s.wait(ctx); err != nil {
+			return err
+		}
+
+		err := s.send(ctx, to, body)
+		switch {
+		case err == nil:
+			return nil
+		case attempt >= s.attempts || !isTemporary(err):
+			return fmt.Errorf("failed to send email via smtp: %w", err)
+		}
+
+		s.logger.Warn("failed to send email, retrying", zap.Int("attempt", attempt), zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(s.backoff * time.Duration(attempt)):
+		}
+	}
+}
+
+// wait blocks until the rate limit allows another email to be sent.
+func (s *Sender) wait(ctx context.Context) error {
+	if s.interval == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	now := time.Now()
+	at := s.next
+	if at.Before(now) {
+		at = now
+	}
+	s.next = at.Add(s.interval)
+	s.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(time.Until(at)):
+		return nil
+	}
+}
+
+func (s *Sender) send(ctx context.Context, to *mail.Address, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	client, err := s.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if s.tlsMode == TLSStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("server does not support STARTTLS")
+		}
+		if err := client.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			return fmt.Errorf("failed to start tls: %w", err)
+		}
+	}
+
+	if s.auth != nil {
+		if err := client.Auth(s.auth); err != nil {
+			return fmt.Errorf("failed to authenticate: %w", err)
+		}
+	}
+
+	if err := client.Mail(s.from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return err
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// dial connects to the server. The connection fails when ctx is done, so that a server that stops responding can not
+// block the sender.
+func (s *Sender) dial(ctx context.Context) (*smtp.Client, error) {
+	dialer := &net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	go func() {
+		// unblocks any pending reads and writes when the context is cancelled
+		<-ctx.Done()
+		_ = conn.SetDeadline(time.Now())
+	}()
+
+	if s.tlsMode == TLSImplicit {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: s.host})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		conn = tlsConn
+	}
+
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+func (s *Sender) message(to *mail.Address, msg *emails.Email) ([]byte, error) {
+	var buf bytes.Buffer
+	headers := []struct{ key, value string }{
+		{"From", s.from.String()},
+		{"To", to.String()},
+		{"Subject", mime.QEncoding.Encode("utf-8", msg.Subject)},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"Message-ID", fmt.Sprintf("<%s@%s>", uuid.NewString(), s.host)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", `text/html; charset="utf-8"`},
+		{"Content-Transfer-Encoding", "quoted-printable"},
+	}
+	for _, h := range headers {
+		fmt.Fprintf(&buf, "%s: %s\r\n", h.key, h.value)
+	}
+	buf.WriteString("\r\n")
+
+	w := quotedprintable.NewWriter(&buf)
+	if _, err := w.Write([]byte(msg.Html)); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func isLocalhost(host string) bool {
+	return host == "localhost" || host == "127.0.0.1" || host == "::1"
+}
+
+// isTemporary returns true if sending might succeed if it's retried, that is if the server replied with a
+// 4xx code, or if the connection failed.
+func isTemporary(err error) bool {
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) {
+		return protoErr.Code >= 400 && protoErr.Code < 500
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,271 @@
+package smtp
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"getsturdy.com/api/pkg/emails"
+	"getsturdy.com/api/pkg/emails/smtp/configuration"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type received struct {
+	auth string
+	from string
+	to   []string
+	data string
+}
+
+// stub is a minimal SMTP server. failMail is a list of reply codes to return to MAIL commands before accepting them.
+type stub struct {
+	listener net.Listener
+
+	mu       sync.Mutex
+	failMail []int
+	received []*received
+}
+
+func newStub(t *testing.T, failMail ...int) *stub {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := &stub{listener: listener, failMail: failMail}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+
+	return s
+}
+
+func (s *stub) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *stub) messages() []*received {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.received
+}
+
+func (s *stub) serve(conn net.Conn) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+
+	msg := &received{}
+	_ = tp.PrintfLine("220 localhost ESMTP stub")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(cmd) {
+		case "EHLO", "HELO":
+			_ = tp.PrintfLine("250-localhost")
+			_ = tp.PrintfLine("250 AUTH PLAIN")
+		case "AUTH":
+			msg.auth = arg
+			_ = tp.PrintfLine("235 authenticated")
+		case "MAIL":
+			s.mu.Lock()
+			var code int
+			if len(s.failMail) > 0 {
+				code, s.failMail = s.failMail[0], s.failMail[1:]
+			}
+			s.mu.Unlock()
+			if code != 0 {
+				_ = tp.PrintfLine("%d failed", code)
+				continue
+			}
+			msg.from = arg
+			_ = tp.PrintfLine("250 ok")
+		case "RCPT":
+			msg.to = append(msg.to, arg)
+			_ = tp.PrintfLine("250 ok")
+		case "DATA":
+			_ = tp.PrintfLine("354 go ahead")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			msg.data = string(data)
+			s.mu.Lock()
+			s.received = append(s.received, msg)
+			s.mu.Unlock()
+			msg = &received{}
+			_ = tp.PrintfLine("250 queued")
+		case "QUIT":
+			_ = tp.PrintfLine("221 bye")
+			return
+		default:
+			_ = tp.PrintfLine("250 ok")
+		}
+	}
+}
+
+func newTestSender(t *testing.T, port int, cfg configuration.Configuration) *Sender {
+	cfg.Enable = true
+	cfg.Host = "127.0.0.1"
+	cfg.Port = port
+	cfg.TLS = TLSNone
+	if cfg.From == "" {
+		cfg.From = "Sturdy <no-reply@example.com>"
+	}
+	sender, err := NewSender(&cfg, zap.NewNop())
+	require.NoError(t, err)
+	sender.backoff = time.Millisecond
+	return sender
+}
+
+func TestSend(t *testing.T) {
+	stub := newStub(t)
+	sender := newTestSender(t, stub.port(), configuration.Configuration{Username: "user", Password: "pass", MaxAttempts: 1})
+
+	err := sender.Send(context.Background(), &emails.Email{
+		To:      "alice@example.com",
+		Subject: "Review requested ✨",
+		Html:    "<p>Hello Alice</p>",
+	})
+	require.NoError(t, err)
+
+	messages := stub.messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "PLAIN AHVzZXIAcGFzcw==", messages[0].auth)
+	assert.Equal(t, "FROM:<no-reply@example.com>", messages[0].from)
+	assert.Equal(t, []string{"TO:<alice@example.com>"}, messages[0].to)
+
+	parsed, err := mail.ReadMessage(bufio.NewReader(strings.NewReader(messages[0].data)))
+	require.NoError(t, err)
+	assert.Equal(t, `"Sturdy" <no-reply@example.com>`, parsed.Header.Get("From"))
+	assert.Equal(t, "<alice@example.com>", parsed.Header.Get("To"))
+	assert.Contains(t, parsed.Header.Get("Content-Type"), "text/html")
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Review requested ✨", subject)
+
+	body, err := io.ReadAll(quotedprintable.NewReader(parsed.Body))
+	require.NoError(t, err)
+	assert.Equal(t, "<p>Hello Alice</p>", strings.TrimSpace(string(body)))
+}
+
+func TestSend_retriesTemporaryFailures(t *testing.T) {
+	stub := newStub(t, 451, 451)
+	sender := newTestSender(t, stub.port(), configuration.Configuration{MaxAttempts: 3})
+
+	require.NoError(t, sender.Send(context.Background(), &emails.Email{To: "alice@example.com", Subject: "subject", Html: "html"}))
+	assert.Len(t, stub.messages(), 1)
+}
+
+func TestSend_givesUp(t *testing.T) {
+	stub := newStub(t, 451, 451)
+	sender := newTestSender(t, stub.port(), configuration.Configuration{MaxAttempts: 2})
+
+	err := sender.Send(context.Background(), &emails.Email{To: "alice@example.com", Subject: "subject", Html: "html"})
+	assert.Error(t, err)
+	assert.Empty(t, stub.messages())
+}
+
+func TestSend_permanentFailure(t *testing.T) {
+	stub := newStub(t, 550)
+	sender := newTestSender(t, stub.port(), configuration.Configuration{MaxAttempts: 3})
+
+	err := sender.Send(context.Background(), &emails.Email{To: "alice@example.com", Subject: "subject", Html: "html"})
+	var protoErr *textproto.Error
+	if assert.ErrorAs(t, err, &protoErr) {
+		assert.Equal(t, 550, protoErr.Code)
+	}
+
+	// the failure is not retried, the next email is sent
+	require.NoError(t, sender.Send(context.Background(), &emails.Email{To: "alice@example.com", Subject: "subject", Html: "html"}))
+	assert.Len(t, stub.messages(), 1)
+}
+
+func TestSend_rateLimit(t *testing.T) {
+	stub := newStub(t)
+	sender := newTestSender(t, stub.port(), configuration.Configuration{RateLimit: 20})
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		require.NoError(t, sender.Send(context.Background(), &emails.Email{To: fmt.Sprintf("user-%d@example.com", i), Subject: "subject", Html: "html"}))
+	}
+
+	// the first email is sent right away, the next ones 50ms apart
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	assert.Len(t, stub.messages(), 3)
+}
+
+func TestNew(t *testing.T) {
+	sender, err := New(&configuration.Configuration{}, zap.NewNop())
+	require.NoError(t, err)
+	assert.Error(t, sender.Send(context.Background(), &emails.Email{To: "alice@example.com"}), "emails are disabled by default")
+
+	_, err = New(&configuration.Configuration{Enable: true, TLS: TLSStartTLS}, zap.NewNop())
+	assert.ErrorIs(t, err, ErrNoFrom)
+
+	_, err = New(&configuration.Configuration{Enable: true, From: "no-reply@example.com", TLS: "ssl"}, zap.NewNop())
+	assert.ErrorIs(t, err, ErrInvalidTLS)
+
+	_, err = New(&configuration.Configuration{Enable: true, From: "no-reply@example.com", TLS: TLSNone, Host: "smtp.example.com", Username: "user"}, zap.NewNop())
+	assert.ErrorIs(t, err, ErrInsecureAuth)
+
+	_, err = New(&configuration.Configuration{Enable: true, From: "no-reply@example.com", TLS: TLSNone, Host: "localhost", Username: "user"}, zap.NewNop())
+	assert.NoError(t, err)
+}
+
+func TestSend_timeout(t *testing.T) {
+	// a server that accepts connections, but never replies
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_, _ = io.Copy(io.Discard, conn)
+				_ = conn.Close()
+			}()
+		}
+	}()
+
+	sender := newTestSender(t, listener.Addr().(*net.TCPAddr).Port, configuration.Configuration{MaxAttempts: 1})
+	sender.timeout = 50 * time.Millisecond
+
+	start := time.Now()
+	err = sender.Send(context.Background(), &emails.Email{To: "alice@example.com", Subject: "subject", Html: "html"})
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+
+	// the context is respected as well
+	sender.timeout = time.Minute
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	assert.Error(t, sender.Send(ctx, &emails.Email{To: "alice@example.com", Subject: "subject", Html: "html"}))
+	assert.Less(t, time.Since(start), time.Second)
+}
@@ -9,6 +9,7 @@ import (
 	emails "getsturdy.com/api/pkg/emails/module"
 	service_jwt "getsturdy.com/api/pkg/jwt/service"
 	"getsturdy.com/api/pkg/logger"
+	db_mergequeue "getsturdy.com/api/pkg/mergequeue/db"
 	db_newsletter "getsturdy.com/api/pkg/newsletter/db"
 	service_notification "getsturdy.com/api/pkg/notification/service"
 	db_organizations "getsturdy.com/api/pkg/organization/db"
@@ -33,5 +34,6 @@ func Module(c *di.Container) {
 	c.Import(service_notification.Module)
 	c.Import(service_analytics.Module)
 	c.Import(db_organizations.Module)
+	c.Import(db_mergequeue.Module)
 	c.Register(New)
 }
@@ -20,6 +20,8 @@ import (
 	"getsturdy.com/api/pkg/emails/transactional/templates"
 	"getsturdy.com/api/pkg/jwt"
 	service_jwt "getsturdy.com/api/pkg/jwt/service"
+	"getsturdy.com/api/pkg/mergequeue"
+	db_mergequeue "getsturdy.com/api/pkg/mergequeue/db"
 	db_newsletter "getsturdy.com/api/pkg/newsletter/db"
 	"getsturdy.com/api/pkg/notification"
 	service_notification "getsturdy.com/api/pkg/notification/service"
@@ -59,6 +61,7 @@ type Sender struct {
 	notificationSettingsRepository db_newsletter.NotificationSettingsRepository
 	organizationUserRepo           db_organizations.MemberRepository
 	organizationRepo               db_organizations.Repository
+	mergeQueueRepo                 db_mergequeue.Repository
 
 	jwtService    *service_jwt.Service
 	changeService *service_change.Service
@@ -81,6 +84,7 @@ func New(
 	notificationSettingsRepository db_newsletter.NotificationSettingsRepository,
 	organizationUserRepo db_organizations.MemberRepository,
 	organizationRepo db_organizations.Repository,
+	mergeQueueRepo db_mergequeue.Repository,
 
 	jwtService *service_jwt.Service,
 	changeService *service_change.Service,
@@ -103,6 +107,7 @@ func New(
 		notificationSettingsRepository: notificationSettingsRepository,
 		organizationUserRepo:           organizationUserRepo,
 		organizationRepo:               organizationRepo,
+		mergeQueueRepo:                 mergeQueueRepo,
 
 		jwtService:    jwtService,
 		changeService: changeService,
@@ -178,6 +183,11 @@ func (e *Sender) SendNotification(ctx context.Context, usr *users.User, notif *n
 			return fmt.Errorf("failed to send comment notification: %w", err)
 		}
 		return nil
+	case notification.MentionNotificationType:
+		if err := e.sendMentionNotification(ctx, usr, comments.ID(notif.ReferenceID)); err != nil {
+			return fmt.Errorf("failed to send mention notification: %w", err)
+		}
+		return nil
 	case notification.NewSuggestionNotificationType:
 		if err := e.sendNewSuggestionNotification(ctx, usr, suggestions.ID(notif.ReferenceID)); err != nil {
 			return fmt.Errorf("failed to send new suggestion notification: %w", err)
@@ -193,6 +203,11 @@ func (e *Sender) SendNotification(ctx context.Context, usr *users.User, notif *n
 			return fmt.Errorf("failed to send review notification: %w", err)
 		}
 		return nil
+	case notification.MergeQueueNotificationType:
+		if err := e.sendMergeQueueNotification(ctx, usr, mergequeue.ID(notif.ReferenceID)); err != nil {
+			return fmt.Errorf("failed to send merge queue notification: %w", err)
+		}
+		return nil
 	case notification.InvitedToCodebase:
 		if err := e.sendInviteToCodebase(ctx, usr, notif.ReferenceID); err != nil {
 			return fmt.Errorf("failed to send invite to codebase notification: %w", err)
@@ -321,6 +336,43 @@ func (e *Sender) sendRequestedReviewNotification(ctx context.Context, usr *users
 	return e.Send(ctx, usr, title, templates.NotificationRequestedReviewTemplate, data)
 }
 
+func (e *Sender) sendMergeQueueNotification(ctx context.Context, usr *users.User, entryID mergequeue.ID) error {
+	entry, err := e.mergeQueueRepo.Get(ctx, entryID)
+	if err != nil {
+		return fmt.Errorf("failed to find merge queue entry: %w", err)
+	}
+
+	w, err := e.workspaceRepo.Get(entry.WorkspaceID)
+	if err != nil {
+		return fmt.Errorf("failed to find workspace: %w", err)
+	}
+
+	c, err := e.codebaseRepo.Get(entry.CodebaseID)
+	if err != nil {
+		return fmt.Errorf("failed to find codebase: %w", err)
+	}
+
+	var title string
+	switch entry.Status {
+	case mergequeue.StatusLanded:
+		title = fmt.Sprintf("[Sturdy] %s was landed", w.NameOrFallback())
+	case mergequeue.StatusEjected:
+		title = fmt.Sprintf("[Sturdy] %s was ejected from the merge queue", w.NameOrFallback())
+	default:
+		// the entry is still in the queue, or was cancelled by a user
+		return nil
+	}
+
+	data := &templates.NotificationMergeQueueTemplateData{
+		User: usr,
+
+		Entry:     entry,
+		Workspace: w,
+		Codebase:  c,
+	}
+	return e.Send(ctx, usr, title, templates.NotificationMergeQueueTemplate, data)
+}
+
 func (e *Sender) sendNewSuggestionNotification(ctx context.Context, usr *users.User, suggestionID suggestions.ID) error {
 	s, err := e.suggestionRepo.GetByID(ctx, suggestionID)
 	if err != nil {
@@ -370,7 +422,21 @@ func (e *Sender) getUsersByCodebaseID(ctx context.Context, codebaseID codebases.
 	return users, nil
 }
 
-func (e *Sender) sendCommentNotification(ctx context.Context, usr *users.User, commentID comments.ID) error {
+// replaceMentions replaces all @id mentions in the comment message with @name.
+func (e *Sender) replaceMentions(ctx context.Context, comment *comments.Comment) error {
+	codebaseUsers, err := e.getUsersByCodebaseID(ctx, comment.CodebaseID)
+	if err != nil {
+		return fmt.Errorf("failed to get users: %w", err)
+	}
+
+	mentions := decorate_comments.ExtractIDMentions(comment.Message, codebaseUsers)
+	for mention, user := range mentions {
+		comment.Message = strings.ReplaceAll(comment.Message, mention, fmt.Sprintf("@%s", user.Name))
+	}
+	return nil
+}
+
+func (e *Sender) sendMentionNotification(ctx context.Context, usr *users.User, commentID comments.ID) error {
 	comment, err := e.commentsRepo.Get(commentID)
 	if err != nil {
 		return fmt.Errorf("failed to find comment: %w", err)
@@ -380,15 +446,59 @@ func (e *Sender) sendCommentNotification(ctx context.Context, usr *users.User, c
 		return fmt.Errorf("failed to get user: %w", err)
 	}
 
-	codebaseUsers, err := e.getUsersByCodebaseID(ctx, comment.CodebaseID)
+	if err := e.replaceMentions(ctx, &comment); err != nil {
+		return err
+	}
+
+	codebase, err := e.codebaseRepo.Get(comment.CodebaseID)
 	if err != nil {
-		return fmt.Errorf("failed to get users: %w", err)
+		return fmt.Errorf("failed to get codebase: %w", err)
 	}
 
-	mentions := decorate_comments.ExtractIDMentions(comment.Message, codebaseUsers)
-	// replace all mentions with names
-	for mention, user := range mentions {
-		comment.Message = strings.ReplaceAll(comment.Message, mention, fmt.Sprintf("@%s", user.Name))
+	data := &templates.NotificationMentionTemplateData{
+		User: usr,
+
+		Comment:  &comment,
+		Author:   author,
+		Codebase: codebase,
+	}
+
+	// replies have the same change or workspace as their parent
+	switch {
+	case comment.ChangeID != nil:
+		change, err := e.changeService.GetChangeByID(ctx, *comment.ChangeID)
+		if err != nil {
+			return fmt.Errorf("failed to get change: %w", err)
+		}
+		data.Change = change
+		title := fmt.Sprintf("[Sturdy] %s mentioned you on %s", author.Name, *change.Title)
+		return e.Send(ctx, usr, title, templates.NotificationMentionTemplate, data)
+	case comment.WorkspaceID != nil:
+		workspace, err := e.workspaceRepo.Get(*comment.WorkspaceID)
+		if err != nil {
+			return fmt.Errorf("failed to get comment workspace: %w", err)
+		}
+		data.Workspace = workspace
+		title := fmt.Sprintf("[Sturdy] %s mentioned you on %s", author.Name, workspace.NameOrFallback())
+		return e.Send(ctx, usr, title, templates.NotificationMentionTemplate, data)
+	default:
+		title := fmt.Sprintf("[Sturdy] %s mentioned you", author.Name)
+		return e.Send(ctx, usr, title, templates.NotificationMentionTemplate, data)
+	}
+}
+
+func (e *Sender) sendCommentNotification(ctx context.Context, usr *users.User, commentID comments.ID) error {
+	comment, err := e.commentsRepo.Get(commentID)
+	if err != nil {
+		return fmt.Errorf("failed to find comment: %w", err)
+	}
+	author, err := e.userRepo.Get(comment.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if err := e.replaceMentions(ctx, &comment); err != nil {
+		return err
 	}
 
 	codebase, err := e.codebaseRepo.Get(comment.CodebaseID)
@@ -10,7 +10,9 @@ yarn install
 yarn run mjml "${CWD}/welcome.template.mjml" -o "${CWD}/output/welcome.template.html"
 yarn run mjml "${CWD}/notification/github_repository_imported.template.mjml" -o "${CWD}/output/notification/github_repository_imported.template.html"
 yarn run mjml "${CWD}/notification/comment.template.mjml" -o "${CWD}/output/notification/comment.template.html"
+yarn run mjml "${CWD}/notification/mention.template.mjml" -o "${CWD}/output/notification/mention.template.html"
 yarn run mjml "${CWD}/notification/new_suggestion.template.mjml" -o "${CWD}/output/notification/new_suggestion.template.html"
+yarn run mjml "${CWD}/notification/merge_queue.template.mjml" -o "${CWD}/output/notification/merge_queue.template.html"
 yarn run mjml "${CWD}/notification/requested_review.template.mjml" -o "${CWD}/output/notification/requested_review.template.html"
 yarn run mjml "${CWD}/notification/review.template.mjml" -o "${CWD}/output/notification/review.template.html"
 yarn run mjml "${CWD}/verify_email.template.mjml" -o "${CWD}/output/verify_email.template.html"
@@ -12,6 +12,7 @@ import (
 	"getsturdy.com/api/pkg/comments"
 	"getsturdy.com/api/pkg/github"
 	"getsturdy.com/api/pkg/jwt"
+	"getsturdy.com/api/pkg/mergequeue"
 	"getsturdy.com/api/pkg/organization"
 	"getsturdy.com/api/pkg/review"
 	"getsturdy.com/api/pkg/users"
@@ -46,7 +47,9 @@ const (
 	WelcomeTemplate                              Template = "welcome.template.html"
 	NotificationGitHubRepositoryImportedTemplate Template = "github_repository_imported.template.html"
 	NotificationCommentTemplate                  Template = "comment.template.html"
+	NotificationMentionTemplate                  Template = "mention.template.html"
 	NotificationNewSuggestionTemplate            Template = "new_suggestion.template.html"
+	NotificationMergeQueueTemplate               Template = "merge_queue.template.html"
 	NotificationRequestedReviewTemplate          Template = "requested_review.template.html"
 	NotificationReviewTemplate                   Template = "review.template.html"
 	VerifyEmailTemplate                          Template = "verify_email.template.html"
@@ -77,6 +80,16 @@ type NotificationCommentTemplateData struct {
 	Parent *NotificationCommentTemplateData
 }
 
+type NotificationMentionTemplateData struct {
+	User *users.User
+
+	Comment   *comments.Comment
+	Author    *users.User
+	Codebase  *codebases.Codebase
+	Workspace *workspaces.Workspace
+	Change    *changes.Change
+}
+
 type NotificationNewSuggestionTemplateData struct {
 	User *users.User
 
@@ -85,6 +98,14 @@ type NotificationNewSuggestionTemplateData struct {
 	Codebase  *codebases.Codebase
 }
 
+type NotificationMergeQueueTemplateData struct {
+	User *users.User
+
+	Entry     *mergequeue.Entry
+	Workspace *workspaces.Workspace
+	Codebase  *codebases.Codebase
+}
+
 type NotificationRequestedReviewTemplateData struct {
 	User *users.User
 
@@ -9,6 +9,7 @@ import (
 	"getsturdy.com/api/pkg/comments"
 	"getsturdy.com/api/pkg/github"
 	"getsturdy.com/api/pkg/jwt"
+	"getsturdy.com/api/pkg/mergequeue"
 	"getsturdy.com/api/pkg/organization"
 	"getsturdy.com/api/pkg/review"
 	"getsturdy.com/api/pkg/users"
@@ -176,6 +177,34 @@ func TestRenderNotificationComment_commented_on_workspace(t *testing.T) {
 	assert.Equal(t, mustReadFile(t, "testdata/notification/comment_commented_on_workspace.html"), output)
 }
 
+func TestRenderNotificationMention_on_workspace(t *testing.T) {
+	output, err := Render(NotificationMentionTemplate, NotificationMentionTemplateData{
+		User: &users.User{
+			Email: "test@email.com",
+		},
+		Comment: &comments.Comment{
+			Message: "@User Two, this is my comment message",
+		},
+		Workspace: &workspaces.Workspace{
+			ID:   "workspace-id",
+			Name: strPointer("workspace"),
+		},
+		Author: &users.User{
+			Name: "User One",
+		},
+		Codebase: &codebases.Codebase{
+			ShortCodebaseID: "short-id",
+			Name:            "codebase",
+		},
+	})
+
+	// uncomment to make a snapshot
+	// os.WriteFile("testdata/notification/mention_on_workspace.html", []byte(output), 0666)
+
+	assert.NoError(t, err)
+	assert.Equal(t, mustReadFile(t, "testdata/notification/mention_on_workspace.html"), output)
+}
+
 func TestRenderNotificationComment_replied_your(t *testing.T) {
 	usr := &users.User{
 		ID:    "id",
@@ -411,6 +440,67 @@ func TestRenderNotificationNewSuggestion(t *testing.T) {
 	assert.Equal(t, mustReadFile(t, "testdata/notification/new_suggestion.html"), output)
 }
 
+func TestRenderNotificationMergeQueue_landed(t *testing.T) {
+	usr := &users.User{
+		Name:  "me",
+		ID:    "0",
+		Email: "me@test.com",
+	}
+
+	output, err := Render(NotificationMergeQueueTemplate, NotificationMergeQueueTemplateData{
+		User: usr,
+		Entry: &mergequeue.Entry{
+			ID:     "entry-id",
+			Status: mergequeue.StatusLanded,
+		},
+		Codebase: &codebases.Codebase{
+			ShortCodebaseID: "short-id",
+			Name:            "codebase",
+		},
+		Workspace: &workspaces.Workspace{
+			ID:   "workspace-id",
+			Name: strPointer("Workspace"),
+		},
+	})
+
+	// uncomment to make a snapshot
+	// os.WriteFile("testdata/notification/merge_queue_landed.html", []byte(output), 0666)
+
+	assert.NoError(t, err)
+	assert.Equal(t, mustReadFile(t, "testdata/notification/merge_queue_landed.html"), output)
+}
+
+func TestRenderNotificationMergeQueue_ejected(t *testing.T) {
+	usr := &users.User{
+		Name:  "me",
+		ID:    "0",
+		Email: "me@test.com",
+	}
+
+	output, err := Render(NotificationMergeQueueTemplate, NotificationMergeQueueTemplateData{
+		User: usr,
+		Entry: &mergequeue.Entry{
+			ID:          "entry-id",
+			Status:      mergequeue.StatusEjected,
+			EjectReason: strPointer("ci failed"),
+		},
+		Codebase: &codebases.Codebase{
+			ShortCodebaseID: "short-id",
+			Name:            "codebase",
+		},
+		Workspace: &workspaces.Workspace{
+			ID:   "workspace-id",
+			Name: strPointer("Workspace"),
+		},
+	})
+
+	// uncomment to make a snapshot
+	// os.WriteFile("testdata/notification/merge_queue_ejected.html", []byte(output), 0666)
+
+	assert.NoError(t, err)
+	assert.Equal(t, mustReadFile(t, "testdata/notification/merge_queue_ejected.html"), output)
+}
+
 func TestRenderNotificationRequestedReview(t *testing.T) {
 	usr := &users.User{
 		Name:  "me",
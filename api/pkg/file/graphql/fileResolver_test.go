@@ -67,6 +67,7 @@ func TestFileResolver(t *testing.T) {
 		nil,
 		aclProvider,
 		nil,
+		nil,
 	)
 
 	fileService := service_file.New(executorProvider, nil, nil)
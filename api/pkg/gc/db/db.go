@@ -13,6 +13,8 @@ import (
 
 type Repository interface {
 	ListSince(ctx context.Context, codebaseID codebases.ID, since time.Time) ([]*gc.CodebaseGarbageStatus, error)
+	// ListByCodebaseID returns the latest runs of the codebase, newest first.
+	ListByCodebaseID(ctx context.Context, codebaseID codebases.ID, limit int) ([]*gc.CodebaseGarbageStatus, error)
 	Create(context.Context, *gc.CodebaseGarbageStatus) error
 }
 
@@ -30,7 +32,10 @@ func (r *repo) ListSince(ctx context.Context, codebaseID codebases.ID, since tim
 		SELECT
 			codebase_id,
 			completed_at,
-			duration_millis
+			duration_millis,
+			collected_snapshots,
+			collected_ci_commits,
+			triggered_by
 		FROM
 			codebases_garbage_collection_status
 		WHERE
@@ -42,12 +47,35 @@ func (r *repo) ListSince(ctx context.Context, codebaseID codebases.ID, since tim
 	return res, nil
 }
 
+func (r *repo) ListByCodebaseID(ctx context.Context, codebaseID codebases.ID, limit int) ([]*gc.CodebaseGarbageStatus, error) {
+	var res []*gc.CodebaseGarbageStatus
+	if err := r.db.SelectContext(ctx, &res, `
+		SELECT
+			codebase_id,
+			completed_at,
+			duration_millis,
+			collected_snapshots,
+			collected_ci_commits,
+			triggered_by
+		FROM
+			codebases_garbage_collection_status
+		WHERE
+			codebase_id = $1
+		ORDER BY
+			completed_at DESC
+		LIMIT $2
+	`, codebaseID, limit); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+	return res, nil
+}
+
 func (r *repo) Create(ctx context.Context, status *gc.CodebaseGarbageStatus) error {
 	if _, err := r.db.NamedExecContext(ctx, `
 		INSERT INTO codebases_garbage_collection_status 
-			(codebase_id, completed_at, duration_millis)
+			(codebase_id, completed_at, duration_millis, collected_snapshots, collected_ci_commits, triggered_by)
 		VALUES
-			(:codebase_id, :completed_at, :duration_millis)
+			(:codebase_id, :completed_at, :duration_millis, :collected_snapshots, :collected_ci_commits, :triggered_by)
 	`, status); err != nil {
 		return fmt.Errorf("failed to insert: %w", err)
 	}
@@ -60,7 +88,10 @@ func (r *repo) GetByCodebaseID(ctx context.Context, codebaseID string) (*gc.Code
 		SELECT 
 			codebase_id,
 			completed_at,
-			duration_millis
+			duration_millis,
+			collected_snapshots,
+			collected_ci_commits,
+			triggered_by
 		FROM 
 			codebases_garbage_collection_status
 		WHERE 
@@ -8,4 +8,5 @@ import (
 func Module(c *di.Container) {
 	c.Import(db.Module)
 	c.Register(NewRepository)
+	c.Register(NewPolicyDatabase)
 }
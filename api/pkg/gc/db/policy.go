@@ -0,0 +1,61 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/gc"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type PolicyRepository interface {
+	// Get returns sql.ErrNoRows if the codebase has no policy.
+	Get(context.Context, codebases.ID) (*gc.Policy, error)
+	Upsert(context.Context, *gc.Policy) error
+}
+
+var _ PolicyRepository = &policyDatabase{}
+
+type policyDatabase struct {
+	db *sqlx.DB
+}
+
+func NewPolicyDatabase(db *sqlx.DB) PolicyRepository {
+	return &policyDatabase{db: db}
+}
+
+func (d *policyDatabase) Get(ctx context.Context, codebaseID codebases.ID) (*gc.Policy, error) {
+	var p gc.Policy
+	if err := d.db.GetContext(ctx, &p, `
+		SELECT
+			codebase_id, snapshot_retention_hours, archived_workspace_retention_hours, suggestion_snapshot_retention_hours, ci_commit_retention_hours, disable_git_gc, updated_by, updated_at
+		FROM gc_policies
+		WHERE codebase_id = $1
+	`, codebaseID); err != nil {
+		return nil, fmt.Errorf("failed to get gc policy: %w", err)
+	}
+	return &p, nil
+}
+
+func (d *policyDatabase) Upsert(ctx context.Context, p *gc.Policy) error {
+	if _, err := d.db.NamedExecContext(ctx, `
+		INSERT INTO gc_policies
+			(codebase_id, snapshot_retention_hours, archived_workspace_retention_hours, suggestion_snapshot_retention_hours, ci_commit_retention_hours, disable_git_gc, updated_by, updated_at)
+		VALUES
+			(:codebase_id, :snapshot_retention_hours, :archived_workspace_retention_hours, :suggestion_snapshot_retention_hours, :ci_commit_retention_hours, :disable_git_gc, :updated_by, :updated_at)
+		ON CONFLICT (codebase_id) DO UPDATE
+		SET
+			snapshot_retention_hours = :snapshot_retention_hours,
+			archived_workspace_retention_hours = :archived_workspace_retention_hours,
+			suggestion_snapshot_retention_hours = :suggestion_snapshot_retention_hours,
+			ci_commit_retention_hours = :ci_commit_retention_hours,
+			disable_git_gc = :disable_git_gc,
+			updated_by = :updated_by,
+			updated_at = :updated_at
+	`, p); err != nil {
+		return fmt.Errorf("failed to upsert gc policy: %w", err)
+	}
+	return nil
+}
@@ -4,10 +4,93 @@ import (
 	"time"
 
 	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/snapshots"
+	"getsturdy.com/api/pkg/users"
 )
 
 type CodebaseGarbageStatus struct {
 	CodebaseID     codebases.ID `db:"codebase_id"`
 	CompletedAt    time.Time    `db:"completed_at"`
 	DurationMillis int64        `db:"duration_millis"`
+
+	CollectedSnapshots int       `db:"collected_snapshots"`
+	CollectedCICommits int       `db:"collected_ci_commits"`
+	TriggeredBy        *users.ID `db:"triggered_by"`
+}
+
+// Policy decides for how long the garbage of a codebase is kept before it's collected. Retentions that are nil
+// keep the objects forever.
+type Policy struct {
+	CodebaseID codebases.ID `db:"codebase_id"`
+
+	// SnapshotRetentionHours is how long snapshots that are not used by any workspace are kept.
+	SnapshotRetentionHours int `db:"snapshot_retention_hours"`
+	// ArchivedWorkspaceRetentionHours is how long the snapshots of archived workspaces are kept after the
+	// workspace has been archived.
+	ArchivedWorkspaceRetentionHours int `db:"archived_workspace_retention_hours"`
+	// SuggestionSnapshotRetentionHours is how long snapshots that are used by suggestions are kept.
+	SuggestionSnapshotRetentionHours *int `db:"suggestion_snapshot_retention_hours"`
+	// CICommitRetentionHours is how long the commits that have been created to trigger CI builds are kept. Statuses
+	// that are reported for commits that have been collected are rejected.
+	CICommitRetentionHours *int `db:"ci_commit_retention_hours"`
+	// DisableGitGC stops git gc from running on the trunk and the views of the codebase.
+	DisableGitGC bool `db:"disable_git_gc"`
+
+	UpdatedBy *users.ID  `db:"updated_by"`
+	UpdatedAt *time.Time `db:"updated_at"`
+}
+
+// DefaultPolicy is the policy of codebases that have not configured their own.
+func DefaultPolicy(codebaseID codebases.ID) *Policy {
+	return &Policy{
+		CodebaseID:             codebaseID,
+		SnapshotRetentionHours: 3,
+	}
+}
+
+func (p *Policy) SnapshotRetention() time.Duration {
+	return time.Duration(p.SnapshotRetentionHours) * time.Hour
 }
+
+func (p *Policy) ArchivedWorkspaceRetention() time.Duration {
+	return time.Duration(p.ArchivedWorkspaceRetentionHours) * time.Hour
+}
+
+// SuggestionSnapshotRetention returns false if snapshots of suggestions are kept forever.
+func (p *Policy) SuggestionSnapshotRetention() (time.Duration, bool) {
+	if p.SuggestionSnapshotRetentionHours == nil {
+		return 0, false
+	}
+	return time.Duration(*p.SuggestionSnapshotRetentionHours) * time.Hour, true
+}
+
+// CICommitRetention returns false if CI commits are kept forever.
+func (p *Policy) CICommitRetention() (time.Duration, bool) {
+	if p.CICommitRetentionHours == nil {
+		return 0, false
+	}
+	return time.Duration(*p.CICommitRetentionHours) * time.Hour, true
+}
+
+// Report lists what would be collected if the garbage collection ran now.
+type Report struct {
+	CodebaseID codebases.ID
+
+	Snapshots []*ReportedSnapshot
+	CICommits int
+}
+
+// ReportedSnapshot is a snapshot that would be collected, and the reason why.
+type ReportedSnapshot struct {
+	SnapshotID snapshots.ID
+	ViewID     string
+	Reason     Reason
+}
+
+type Reason string
+
+const (
+	ReasonUnused            Reason = "unused"
+	ReasonArchivedWorkspace Reason = "archived_workspace"
+	ReasonSuggestion        Reason = "suggestion"
+)
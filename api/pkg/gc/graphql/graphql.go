@@ -0,0 +1,302 @@
+package graphql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"getsturdy.com/api/pkg/auth"
+	service_auth "getsturdy.com/api/pkg/auth/service"
+	"getsturdy.com/api/pkg/codebases"
+	service_codebase "getsturdy.com/api/pkg/codebases/service"
+	"getsturdy.com/api/pkg/gc"
+	service_gc "getsturdy.com/api/pkg/gc/service"
+	worker_gc "getsturdy.com/api/pkg/gc/worker"
+	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
+	"getsturdy.com/api/pkg/graphql/resolvers"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+const (
+	defaultRuns = 10
+	maxRuns     = 100
+)
+
+type rootResolver struct {
+	gcService       *service_gc.Service
+	gcQueue         *worker_gc.Queue
+	codebaseService *service_codebase.Service
+	authService     *service_auth.Service
+
+	authorRootResolver   resolvers.AuthorRootResolver
+	codebaseRootResolver *resolvers.CodebaseRootResolver
+}
+
+func New(
+	gcService *service_gc.Service,
+	gcQueue *worker_gc.Queue,
+	codebaseService *service_codebase.Service,
+	authService *service_auth.Service,
+
+	authorRootResolver resolvers.AuthorRootResolver,
+	codebaseRootResolver *resolvers.CodebaseRootResolver,
+) resolvers.GarbageCollectionRootResolver {
+	return &rootResolver{
+		gcService:       gcService,
+		gcQueue:         gcQueue,
+		codebaseService: codebaseService,
+		authService:     authService,
+
+		authorRootResolver:   authorRootResolver,
+		codebaseRootResolver: codebaseRootResolver,
+	}
+}
+
+func (r *rootResolver) GarbageCollection(ctx context.Context, args resolvers.GarbageCollectionArgs) (resolvers.GarbageCollectionResolver, error) {
+	cb, err := r.codebaseService.GetByID(ctx, codebases.ID(args.CodebaseID))
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	if err := r.authService.CanRead(ctx, cb); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	return r.resolver(ctx, cb)
+}
+
+func (r *rootResolver) resolver(ctx context.Context, cb *codebases.Codebase) (resolvers.GarbageCollectionResolver, error) {
+	policy, err := r.gcService.GetPolicy(ctx, cb.ID)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+	return &resolver{root: r, codebase: cb, policy: policy}, nil
+}
+
+func (r *rootResolver) UpdateGarbageCollectionPolicy(ctx context.Context, args resolvers.UpdateGarbageCollectionPolicyArgs) (resolvers.GarbageCollectionResolver, error) {
+	userID, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	cb, err := r.codebaseService.GetByID(ctx, codebases.ID(args.Input.CodebaseID))
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	if err := r.authService.CanAdmin(ctx, cb); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	policy := &gc.Policy{
+		CodebaseID:                       cb.ID,
+		SnapshotRetentionHours:           int(args.Input.SnapshotRetentionHours),
+		ArchivedWorkspaceRetentionHours:  int(args.Input.ArchivedWorkspaceRetentionHours),
+		SuggestionSnapshotRetentionHours: intPtr(args.Input.SuggestionSnapshotRetentionHours),
+		CICommitRetentionHours:           intPtr(args.Input.CICommitRetentionHours),
+		DisableGitGC:                     args.Input.GitGC != nil && !*args.Input.GitGC,
+	}
+
+	if err := r.gcService.UpdatePolicy(ctx, policy, userID); errors.Is(err, service_gc.ErrInvalidRetention) {
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", err.Error())
+	} else if err != nil {
+		return nil, gqlerrors.Error(fmt.Errorf("failed to update gc policy: %w", err))
+	}
+
+	return &resolver{root: r, codebase: cb, policy: policy}, nil
+}
+
+func (r *rootResolver) RunGarbageCollection(ctx context.Context, args resolvers.RunGarbageCollectionArgs) (resolvers.GarbageCollectionResolver, error) {
+	userID, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	cb, err := r.codebaseService.GetByID(ctx, codebases.ID(args.Input.CodebaseID))
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	if err := r.authService.CanAdmin(ctx, cb); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	if err := r.gcQueue.EnqueueByUser(ctx, cb.ID, userID); err != nil {
+		return nil, gqlerrors.Error(fmt.Errorf("failed to enqueue gc: %w", err))
+	}
+
+	return r.resolver(ctx, cb)
+}
+
+func intPtr(i *int32) *int {
+	if i == nil {
+		return nil
+	}
+	v := int(*i)
+	return &v
+}
+
+func int32Ptr(i *int) *int32 {
+	if i == nil {
+		return nil
+	}
+	v := int32(*i)
+	return &v
+}
+
+type resolver struct {
+	root     *rootResolver
+	codebase *codebases.Codebase
+	policy   *gc.Policy
+}
+
+func (r *resolver) Codebase(ctx context.Context) (resolvers.CodebaseResolver, error) {
+	id := graphql.ID(r.codebase.ID)
+	return (*r.root.codebaseRootResolver).Codebase(ctx, resolvers.CodebaseArgs{ID: &id})
+}
+
+func (r *resolver) Policy() resolvers.GarbageCollectionPolicyResolver {
+	return &policyResolver{policy: r.policy}
+}
+
+func (r *resolver) Runs(ctx context.Context, args resolvers.GarbageCollectionRunsArgs) ([]resolvers.GarbageCollectionRunResolver, error) {
+	limit := defaultRuns
+	if args.Last != nil {
+		limit = int(*args.Last)
+	}
+	if limit < 1 || limit > maxRuns {
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "last", fmt.Sprintf("must be between 1 and %d", maxRuns))
+	}
+
+	runs, err := r.root.gcService.ListRuns(ctx, r.codebase.ID, limit)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	res := make([]resolvers.GarbageCollectionRunResolver, 0, len(runs))
+	for _, run := range runs {
+		res = append(res, &runResolver{root: r.root, run: run})
+	}
+	return res, nil
+}
+
+func (r *resolver) DryRun(ctx context.Context) (resolvers.GarbageCollectionReportResolver, error) {
+	if err := r.root.authService.CanAdmin(ctx, r.codebase); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	report, err := r.root.gcService.Report(ctx, r.codebase.ID)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+	return &reportResolver{report: report}, nil
+}
+
+type policyResolver struct {
+	policy *gc.Policy
+}
+
+func (r *policyResolver) SnapshotRetentionHours() int32 {
+	return int32(r.policy.SnapshotRetentionHours)
+}
+
+func (r *policyResolver) ArchivedWorkspaceRetentionHours() int32 {
+	return int32(r.policy.ArchivedWorkspaceRetentionHours)
+}
+
+func (r *policyResolver) SuggestionSnapshotRetentionHours() *int32 {
+	return int32Ptr(r.policy.SuggestionSnapshotRetentionHours)
+}
+
+func (r *policyResolver) CICommitRetentionHours() *int32 {
+	return int32Ptr(r.policy.CICommitRetentionHours)
+}
+
+func (r *policyResolver) GitGC() bool {
+	return !r.policy.DisableGitGC
+}
+
+func (r *policyResolver) UpdatedAt() *int32 {
+	if r.policy.UpdatedAt == nil {
+		return nil
+	}
+	t := int32(r.policy.UpdatedAt.Unix())
+	return &t
+}
+
+type runResolver struct {
+	root *rootResolver
+	run  *gc.CodebaseGarbageStatus
+}
+
+func (r *runResolver) CompletedAt() int32 {
+	return int32(r.run.CompletedAt.Unix())
+}
+
+func (r *runResolver) DurationMillis() int32 {
+	return int32(r.run.DurationMillis)
+}
+
+func (r *runResolver) CollectedSnapshots() int32 {
+	return int32(r.run.CollectedSnapshots)
+}
+
+func (r *runResolver) CollectedCICommits() int32 {
+	return int32(r.run.CollectedCICommits)
+}
+
+func (r *runResolver) TriggeredBy(ctx context.Context) (resolvers.AuthorResolver, error) {
+	if r.run.TriggeredBy == nil {
+		return nil, nil
+	}
+	author, err := r.root.authorRootResolver.Author(ctx, graphql.ID(*r.run.TriggeredBy))
+	switch {
+	case err == nil:
+		return author, nil
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, nil
+	default:
+		return nil, gqlerrors.Error(err)
+	}
+}
+
+type reportResolver struct {
+	report *gc.Report
+}
+
+func (r *reportResolver) Snapshots() []resolvers.GarbageCollectionReportedSnapshotResolver {
+	res := make([]resolvers.GarbageCollectionReportedSnapshotResolver, 0, len(r.report.Snapshots))
+	for _, s := range r.report.Snapshots {
+		res = append(res, &reportedSnapshotResolver{snapshot: s})
+	}
+	return res
+}
+
+func (r *reportResolver) CICommits() int32 {
+	return int32(r.report.CICommits)
+}
+
+type reportedSnapshotResolver struct {
+	snapshot *gc.ReportedSnapshot
+}
+
+func (r *reportedSnapshotResolver) SnapshotID() graphql.ID {
+	return graphql.ID(r.snapshot.SnapshotID)
+}
+
+func (r *reportedSnapshotResolver) ViewID() graphql.ID {
+	return graphql.ID(r.snapshot.ViewID)
+}
+
+func (r *reportedSnapshotResolver) Reason() resolvers.GarbageCollectionReason {
+	switch r.snapshot.Reason {
+	case gc.ReasonArchivedWorkspace:
+		return resolvers.GarbageCollectionReasonArchivedWorkspace
+	case gc.ReasonSuggestion:
+		return resolvers.GarbageCollectionReasonSuggestion
+	default:
+		return resolvers.GarbageCollectionReasonUnused
+	}
+}
@@ -0,0 +1,21 @@
+package graphql
+
+import (
+	service_auth "getsturdy.com/api/pkg/auth/service"
+	graphql_author "getsturdy.com/api/pkg/author/graphql"
+	service_codebase "getsturdy.com/api/pkg/codebases/service"
+	"getsturdy.com/api/pkg/di"
+	service_gc "getsturdy.com/api/pkg/gc/service"
+	worker_gc "getsturdy.com/api/pkg/gc/worker"
+	"getsturdy.com/api/pkg/graphql/resolvers"
+)
+
+func Module(c *di.Container) {
+	c.Import(service_gc.Module)
+	c.Import(worker_gc.Module)
+	c.Import(service_codebase.Module)
+	c.Import(service_auth.Module)
+	c.Import(graphql_author.Module)
+	c.Import(resolvers.Module)
+	c.Register(New)
+}
@@ -1,9 +1,11 @@
 package service
 
 import (
+	db_ci "getsturdy.com/api/pkg/ci/db"
 	"getsturdy.com/api/pkg/di"
 	db_gc "getsturdy.com/api/pkg/gc/db"
 	"getsturdy.com/api/pkg/logger"
+	db_review "getsturdy.com/api/pkg/review/db"
 	db_snapshots "getsturdy.com/api/pkg/snapshots/db"
 	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
 	service_suggestions "getsturdy.com/api/pkg/suggestions/service"
@@ -15,9 +17,11 @@ import (
 func Module(c *di.Container) {
 	c.Import(logger.Module)
 	c.Import(db_gc.Module)
+	c.Import(db_ci.Module)
 	c.Import(db_view.Module)
 	c.Import(db_snapshots.Module)
 	c.Import(db_workspaces.Module)
+	c.Import(db_review.Module)
 	c.Import(service_suggestions.Module)
 	c.Import(service_snapshots.Module)
 	c.Import(executor.Module)
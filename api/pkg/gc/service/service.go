@@ -5,55 +5,75 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
 	"getsturdy.com/api/vcs"
 
+	db_ci "getsturdy.com/api/pkg/ci/db"
 	"getsturdy.com/api/pkg/codebases"
 	"getsturdy.com/api/pkg/gc"
 	"getsturdy.com/api/pkg/gc/db"
+	db_review "getsturdy.com/api/pkg/review/db"
 	"getsturdy.com/api/pkg/snapshots"
 	db_snapshots "getsturdy.com/api/pkg/snapshots/db"
 	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
 	service_suggestion "getsturdy.com/api/pkg/suggestions/service"
+	"getsturdy.com/api/pkg/users"
 	"getsturdy.com/api/pkg/views"
 	db_view "getsturdy.com/api/pkg/views/db"
+	"getsturdy.com/api/pkg/workspaces"
 	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
 	"getsturdy.com/api/vcs/executor"
+	"getsturdy.com/api/vcs/provider"
 
 	"go.uber.org/zap"
 )
 
+var ErrInvalidRetention = errors.New("retention can't be negative")
+
+// ciRepo is the view that the commits that trigger CI builds are created in.
+const ciRepo = "ci"
+
 type Service struct {
 	logger            *zap.Logger
 	gcRepo            db.Repository
+	policyRepo        db.PolicyRepository
+	ciCommitRepo      db_ci.CommitRepository
 	viewRepo          db_view.Repository
 	snapshotsRepo     db_snapshots.Repository
 	snapshotsService  *service_snapshots.Service
 	workspaceReader   db_workspaces.WorkspaceReader
 	suggestionService *service_suggestion.Service
+	reviewRepo        db_review.ReviewRepository
 	executorProvider  executor.Provider
 }
 
 func New(
 	logger *zap.Logger,
 	gcRepo db.Repository,
+	policyRepo db.PolicyRepository,
+	ciCommitRepo db_ci.CommitRepository,
 	viewRepo db_view.Repository,
 	snapshotsRepo db_snapshots.Repository,
 	workspaceReader db_workspaces.WorkspaceReader,
 	snapshotsService *service_snapshots.Service,
 	suggestionService *service_suggestion.Service,
+	reviewRepo db_review.ReviewRepository,
 	executorProvider executor.Provider,
 ) *Service {
 	return &Service{
 		logger:            logger.Named("gcService"),
 		gcRepo:            gcRepo,
+		policyRepo:        policyRepo,
+		ciCommitRepo:      ciCommitRepo,
 		viewRepo:          viewRepo,
 		snapshotsRepo:     snapshotsRepo,
 		snapshotsService:  snapshotsService,
 		workspaceReader:   workspaceReader,
 		suggestionService: suggestionService,
+		reviewRepo:        reviewRepo,
 		executorProvider:  executorProvider,
 	}
 }
@@ -76,7 +96,15 @@ func filterSlice[T any](slice []T, filter func(T) bool) []T {
 	return out
 }
 
-func (svc *Service) gcSnapshotsInView(ctx context.Context, view *views.View, snapshotThreshold time.Duration) error {
+// collectable is a snapshot that can be collected, and the reason why.
+type collectable struct {
+	snapshot *snapshots.Snapshot
+	reason   gc.Reason
+}
+
+// collectableSnapshotsInView returns the snapshots with branches in the view that can be collected according to
+// the policy.
+func (svc *Service) collectableSnapshotsInView(ctx context.Context, view *views.View, policy *gc.Policy, now time.Time) ([]collectable, error) {
 	allBranches := []string{}
 	if err := svc.executorProvider.New().GitRead(func(repo vcs.RepoGitReader) error {
 		branches, err := repo.Branches()
@@ -86,7 +114,7 @@ func (svc *Service) gcSnapshotsInView(ctx context.Context, view *views.View, sna
 		allBranches = branches
 		return nil
 	}).ExecView(view.CodebaseID, view.ID, "listBranches"); err != nil {
-		return fmt.Errorf("failed to execute view: %w", err)
+		return nil, fmt.Errorf("failed to execute view: %w", err)
 	}
 
 	isSnapshotBranch := func(branch string) bool {
@@ -95,7 +123,7 @@ func (svc *Service) gcSnapshotsInView(ctx context.Context, view *views.View, sna
 
 	snapshotBranches := filterSlice(allBranches, isSnapshotBranch)
 	if len(snapshotBranches) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	branchSnapshotID := func(branch string) snapshots.ID {
@@ -106,43 +134,54 @@ func (svc *Service) gcSnapshotsInView(ctx context.Context, view *views.View, sna
 	snapshotIDs := mapSlice(snapshotBranches, branchSnapshotID)
 	snapshots, err := svc.snapshotsRepo.ListByIDs(ctx, snapshotIDs)
 	if err != nil {
-		return fmt.Errorf("failed to list snapshots: %w", err)
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
 	}
 
-	// Delete snapshots older than
-	threshold := time.Now().Add(snapshotThreshold)
+	var res []collectable
 	for _, snapshot := range snapshots {
-		logger := svc.logger.With(zap.Stringer("snapshot_id", snapshot.ID), zap.String("view_id", view.ID))
-
-		if err := svc.gcSnapshot(
-			ctx,
-			snapshot,
-			view,
-			threshold,
-			logger,
-		); err != nil {
-			logger.Error("failed to gc snapshot", zap.Error(err))
+		reason, ok, err := svc.collectReason(ctx, snapshot, policy, now)
+		if err != nil {
+			svc.logger.Error("failed to check if snapshot can be collected", zap.Stringer("snapshot_id", snapshot.ID), zap.Error(err))
 			// do not fail
+			continue
+		}
+		if ok {
+			res = append(res, collectable{snapshot: snapshot, reason: reason})
 		}
 	}
 
-	return nil
+	return res, nil
 }
 
-func (svc *Service) gcSnapshots(ctx context.Context, codebaseID codebases.ID, snapshotThreshold time.Duration) error {
+func (svc *Service) gcSnapshots(ctx context.Context, codebaseID codebases.ID, policy *gc.Policy) (int, error) {
 	views, err := svc.viewRepo.ListByCodebase(codebaseID)
 	if err != nil {
-		return fmt.Errorf("failed to list views: %w", err)
+		return 0, fmt.Errorf("failed to list views: %w", err)
 	}
 
+	var collected int
+	now := time.Now()
 	for _, view := range views {
 		logger := svc.logger.With(zap.String("view_id", view.ID), zap.Stringer("codebase_id", view.CodebaseID))
-		if err := svc.gcSnapshotsInView(ctx, view, snapshotThreshold); err != nil {
+
+		cc, err := svc.collectableSnapshotsInView(ctx, view, policy, now)
+		if err != nil {
 			logger.Error("failed to gc snapshots in view", zap.Error(err))
+			continue
+		}
+
+		for _, c := range cc {
+			logger := logger.With(zap.Stringer("snapshot_id", c.snapshot.ID), zap.String("reason", string(c.reason)))
+			if err := svc.gcSnapshot(ctx, c.snapshot, view, logger); err != nil {
+				logger.Error("failed to gc snapshot", zap.Error(err))
+				// do not fail
+				continue
+			}
+			collected++
 		}
 	}
 
-	return nil
+	return collected, nil
 }
 
 func (svc *Service) isSnapshotUsedAsSuggestion(ctx context.Context, snapshot *snapshots.Snapshot) (bool, error) {
@@ -166,46 +205,91 @@ func (svc *Service) isSnapshotUsedAsSuggestion(ctx context.Context, snapshot *sn
 	}
 }
 
-func (svc *Service) gcSnapshot(
-	ctx context.Context,
-	snapshot *snapshots.Snapshot,
-	view *views.View,
-	threshold time.Time,
-	logger *zap.Logger,
-) error {
-	if snapshot.CreatedAt.After(threshold) {
-		logger.Info(
-			"snapshot too new, skipping",
-			zap.Time("threshold", threshold),
-			zap.Time("created_at", snapshot.CreatedAt),
-		)
-		return nil
+func (svc *Service) isSnapshotUsedByReview(ctx context.Context, snapshot *snapshots.Snapshot) (bool, error) {
+	rr, err := svc.reviewRepo.ListBySnapshotID(ctx, snapshot.ID)
+	if err != nil {
+		return false, fmt.Errorf("could not get reviews: %w", err)
+	}
+	if len(rr) == 0 {
+		return false, nil
+	}
+
+	// reviews of archived workspaces are not needed anymore
+	ws, err := svc.workspaceReader.Get(snapshot.WorkspaceID)
+	switch {
+	case err == nil:
+		return !ws.IsArchived(), nil
+	case errors.Is(err, sql.ErrNoRows):
+		return false, nil
+	default:
+		return false, fmt.Errorf("could not get workspace: %w", err)
 	}
+}
 
+func (svc *Service) collectReason(ctx context.Context, snapshot *snapshots.Snapshot, policy *gc.Policy, now time.Time) (gc.Reason, bool, error) {
+	// cheap checks first
 	if snapshot.DeletedAt != nil {
-		logger.Info("snapshot is deleted, skipping")
-		return nil
+		return "", false, nil
 	}
 
+	var workspace *workspaces.Workspace
 	if ws, err := svc.workspaceReader.GetBySnapshotID(snapshot.ID); errors.Is(err, sql.ErrNoRows) {
 		// continue
 	} else if err != nil {
-		return fmt.Errorf("could not get workspace: %w", err)
-	} else if !ws.IsArchived() {
-		logger.Info("snapshot is used by a workspace, skipping", zap.String("workspace_id", ws.ID))
-		return nil
+		return "", false, fmt.Errorf("could not get workspace: %w", err)
+	} else {
+		workspace = ws
 	}
 
 	partOfSuggestion, err := svc.isSnapshotUsedAsSuggestion(ctx, snapshot)
 	if err != nil {
-		return fmt.Errorf("failed to calculate if snapshot is a part of suggestion: %w", err)
+		return "", false, fmt.Errorf("failed to calculate if snapshot is a part of suggestion: %w", err)
 	}
 
-	if partOfSuggestion {
-		logger.Info("snapshot is a part of an open suggestion, skipping")
-		return nil
+	usedByReview, err := svc.isSnapshotUsedByReview(ctx, snapshot)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to calculate if snapshot is used by a review: %w", err)
 	}
 
+	reason, ok := collectReason(snapshot, workspace, partOfSuggestion, usedByReview, policy, now)
+	return reason, ok, nil
+}
+
+// collectReason returns the reason why the snapshot can be collected, or false if it must be kept. workspace is
+// the workspace that the snapshot is the latest snapshot of, if any. Snapshots that are used by a review are kept, so
+// that the review can be compared to the current state of the workspace.
+func collectReason(snapshot *snapshots.Snapshot, workspace *workspaces.Workspace, usedBySuggestion, usedByReview bool, policy *gc.Policy, now time.Time) (gc.Reason, bool) {
+	switch {
+	case snapshot.DeletedAt != nil:
+		return "", false
+	case workspace != nil && !workspace.IsArchived():
+		return "", false
+	case usedByReview:
+		return "", false
+	case usedBySuggestion:
+		retention, ok := policy.SuggestionSnapshotRetention()
+		if !ok || snapshot.CreatedAt.After(now.Add(-retention)) {
+			return "", false
+		}
+		return gc.ReasonSuggestion, true
+	case snapshot.CreatedAt.After(now.Add(-policy.SnapshotRetention())):
+		return "", false
+	case workspace != nil:
+		if workspace.ArchivedAt.After(now.Add(-policy.ArchivedWorkspaceRetention())) {
+			return "", false
+		}
+		return gc.ReasonArchivedWorkspace, true
+	default:
+		return gc.ReasonUnused, true
+	}
+}
+
+func (svc *Service) gcSnapshot(
+	ctx context.Context,
+	snapshot *snapshots.Snapshot,
+	view *views.View,
+	logger *zap.Logger,
+) error {
 	// Throttle heavy operations
 	time.Sleep(time.Second / 2)
 
@@ -272,16 +356,125 @@ func getGCInterval() time.Duration {
 	return time.Hour
 }
 
-func getSnapshotThreshold() time.Duration {
-	return -3 * time.Hour
+// GetPolicy returns the policy of the codebase. If the codebase has no policy, the default policy is returned.
+func (svc *Service) GetPolicy(ctx context.Context, codebaseID codebases.ID) (*gc.Policy, error) {
+	p, err := svc.policyRepo.Get(ctx, codebaseID)
+	switch {
+	case err == nil:
+		return p, nil
+	case errors.Is(err, sql.ErrNoRows):
+		return gc.DefaultPolicy(codebaseID), nil
+	default:
+		return nil, fmt.Errorf("failed to get gc policy: %w", err)
+	}
+}
+
+func (svc *Service) UpdatePolicy(ctx context.Context, p *gc.Policy, userID users.ID) error {
+	if p.SnapshotRetentionHours < 0 || p.ArchivedWorkspaceRetentionHours < 0 ||
+		(p.SuggestionSnapshotRetentionHours != nil && *p.SuggestionSnapshotRetentionHours < 0) ||
+		(p.CICommitRetentionHours != nil && *p.CICommitRetentionHours < 0) {
+		return ErrInvalidRetention
+	}
+
+	now := time.Now()
+	p.UpdatedBy = &userID
+	p.UpdatedAt = &now
+	if err := svc.policyRepo.Upsert(ctx, p); err != nil {
+		return fmt.Errorf("failed to update gc policy: %w", err)
+	}
+	return nil
+}
+
+// ListRuns returns the latest garbage collection runs of the codebase, newest first.
+func (svc *Service) ListRuns(ctx context.Context, codebaseID codebases.ID, limit int) ([]*gc.CodebaseGarbageStatus, error) {
+	runs, err := svc.gcRepo.ListByCodebaseID(ctx, codebaseID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list gc runs: %w", err)
+	}
+	return runs, nil
+}
+
+// Report returns what would be collected if the garbage collection of the codebase ran now, without collecting
+// anything.
+func (svc *Service) Report(ctx context.Context, codebaseID codebases.ID) (*gc.Report, error) {
+	policy, err := svc.GetPolicy(ctx, codebaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	views, err := svc.viewRepo.ListByCodebase(codebaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list views: %w", err)
+	}
+
+	report := &gc.Report{CodebaseID: codebaseID}
+	now := time.Now()
+	for _, view := range views {
+		cc, err := svc.collectableSnapshotsInView(ctx, view, policy, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list snapshots in view: %w", err)
+		}
+		for _, c := range cc {
+			report.Snapshots = append(report.Snapshots, &gc.ReportedSnapshot{
+				SnapshotID: c.snapshot.ID,
+				ViewID:     view.ID,
+				Reason:     c.reason,
+			})
+		}
+	}
+
+	if retention, ok := policy.CICommitRetention(); ok {
+		if report.CICommits, err = svc.ciCommitRepo.CountCreatedBefore(ctx, codebaseID, now.Add(-retention)); err != nil {
+			return nil, fmt.Errorf("failed to count ci commits: %w", err)
+		}
+	}
+
+	return report, nil
+}
+
+type workOptions struct {
+	force       bool
+	triggeredBy *users.ID
+}
+
+type WorkOption func(*workOptions)
+
+// Force runs the garbage collection even if it has run recently.
+func Force() WorkOption {
+	return func(o *workOptions) {
+		o.force = true
+	}
+}
+
+// TriggeredBy records the user that started the garbage collection.
+func TriggeredBy(userID users.ID) WorkOption {
+	return func(o *workOptions) {
+		o.triggeredBy = &userID
+	}
 }
 
 func (svc *Service) Work(
 	ctx context.Context,
 	logger *zap.Logger,
 	codebaseID codebases.ID,
+	opts ...WorkOption,
 ) error {
-	return svc.WorkWithOptions(ctx, logger, codebaseID, getGCInterval(), getSnapshotThreshold())
+	options := &workOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	policy, err := svc.GetPolicy(ctx, codebaseID)
+	if err != nil {
+		return err
+	}
+
+	gcInterval := getGCInterval()
+	if options.force {
+		gcInterval = 0
+	}
+
+	return svc.work(ctx, logger, codebaseID, gcInterval, policy, options.triggeredBy)
 }
 
 func (svc *Service) WorkWithOptions(
@@ -289,28 +482,73 @@ func (svc *Service) WorkWithOptions(
 	logger *zap.Logger,
 	codebaseID codebases.ID,
 	gcInterval time.Duration,
-	gcSnapshotsThreshold time.Duration,
+	policy *gc.Policy,
+) error {
+	return svc.work(ctx, logger, codebaseID, gcInterval, policy, nil)
+}
+
+func (svc *Service) work(
+	ctx context.Context,
+	logger *zap.Logger,
+	codebaseID codebases.ID,
+	gcInterval time.Duration,
+	policy *gc.Policy,
+	triggeredBy *users.ID,
 ) error {
 	t0 := time.Now()
 
 	// Skip if recently run
-	entries, err := svc.gcRepo.ListSince(ctx, codebaseID, t0.Add(-1*gcInterval))
-	if err != nil {
-		return fmt.Errorf("failed to get last runs: %w", err)
+	if gcInterval > 0 {
+		entries, err := svc.gcRepo.ListSince(ctx, codebaseID, t0.Add(-1*gcInterval))
+		if err != nil {
+			return fmt.Errorf("failed to get last runs: %w", err)
 
-	}
-	if len(entries) > 0 {
-		logger.Sugar().Infof("skipping gc ran in the last %s", gcInterval)
-		return nil
+		}
+		if len(entries) > 0 {
+			logger.Sugar().Infof("skipping gc ran in the last %s", gcInterval)
+			return nil
+		}
 	}
 
 	logger.Info("starting gc")
 
-	if err := svc.gcSnapshots(ctx, codebaseID, gcSnapshotsThreshold); err != nil {
+	collectedSnapshots, err := svc.gcSnapshots(ctx, codebaseID, policy)
+	if err != nil {
 		logger.Error("failed to gc snapshots", zap.Error(err))
 		// do not fail
 	}
 
+	var collectedCICommits int
+	if retention, ok := policy.CICommitRetention(); ok {
+		if collectedCICommits, err = svc.gcCICommits(ctx, logger, codebaseID, t0.Add(-retention)); err != nil {
+			logger.Error("failed to gc ci commits", zap.Error(err))
+			// do not fail
+		}
+	}
+
+	if !policy.DisableGitGC {
+		if err := svc.gitGC(logger, codebaseID); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	if err := svc.gcRepo.Create(ctx, &gc.CodebaseGarbageStatus{
+		CodebaseID:         codebaseID,
+		CompletedAt:        now,
+		DurationMillis:     now.Sub(t0).Milliseconds(),
+		CollectedSnapshots: collectedSnapshots,
+		CollectedCICommits: collectedCICommits,
+		TriggeredBy:        triggeredBy,
+	}); err != nil {
+		return fmt.Errorf("failed to record gc run stats: %w", err)
+	}
+
+	return nil
+}
+
+// gitGC runs git gc on the trunk and all views of the codebase.
+func (svc *Service) gitGC(logger *zap.Logger, codebaseID codebases.ID) error {
 	if err := svc.executorProvider.New().GitWrite(func(trunkRepo vcs.RepoGitWriter) error {
 		if err := trunkRepo.GitReflogExpire(); err != nil {
 			logger.Error("failed to run git-reflog expire on trunk", zap.Error(err))
@@ -367,14 +605,53 @@ func (svc *Service) WorkWithOptions(
 		}
 	}
 
-	now := time.Now()
-	if err := svc.gcRepo.Create(ctx, &gc.CodebaseGarbageStatus{
-		CodebaseID:     codebaseID,
-		CompletedAt:    now,
-		DurationMillis: now.Sub(t0).Milliseconds(),
-	}); err != nil {
-		return fmt.Errorf("failed to record gc run stats: %w", err)
+	return nil
+}
+
+// gcCICommits collects the commits that have been created to trigger CI builds before the given time. The history of
+// the CI repository is cut off before them, so that git gc can collect their objects, and they are only forgotten
+// once that has succeeded. Statuses that are reported for commits that are still in the CI repository can always be
+// resolved to the trunk.
+func (svc *Service) gcCICommits(ctx context.Context, logger *zap.Logger, codebaseID codebases.ID, before time.Time) (int, error) {
+	if err := svc.executorProvider.New().
+		AllowRebasingState(). // the CI repository is never rebased
+		Schedule(func(repoProvider provider.RepoProvider) error {
+			if _, err := os.Stat(repoProvider.ViewPath(codebaseID, ciRepo)); errors.Is(err, os.ErrNotExist) {
+				// no commits have been created
+				return nil
+			} else if err != nil {
+				return fmt.Errorf("failed to open ci repo: %w", err)
+			}
+
+			repo, err := repoProvider.ViewRepo(codebaseID, ciRepo)
+			if err != nil {
+				return fmt.Errorf("failed to open ci repo: %w", err)
+			}
+
+			truncated, err := repo.GitTruncateHistory(before)
+			if err != nil {
+				return fmt.Errorf("failed to truncate ci repo: %w", err)
+			}
+			if !truncated {
+				return nil
+			}
+
+			if err := repo.GitReflogExpire(); err != nil {
+				return fmt.Errorf("failed to run git-reflog expire on ci repo: %w", err)
+			}
+			if err := repo.GitGC(); err != nil {
+				return fmt.Errorf("failed to run git-gc on ci repo: %w", err)
+			}
+
+			logger.Info("ci repo cleaned up")
+			return nil
+		}).ExecView(codebaseID, ciRepo, "gcCI"); err != nil {
+		return 0, err
 	}
 
-	return nil
+	deleted, err := svc.ciCommitRepo.DeleteCreatedBefore(ctx, codebaseID, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete ci commits: %w", err)
+	}
+	return deleted, nil
 }
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"getsturdy.com/api/pkg/gc"
+	"getsturdy.com/api/pkg/snapshots"
+	"getsturdy.com/api/pkg/workspaces"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectReason(t *testing.T) {
+	now := time.Now()
+	hoursAgo := func(h int) time.Time { return now.Add(-time.Duration(h) * time.Hour) }
+	intPtr := func(i int) *int { return &i }
+	archived := hoursAgo(5)
+
+	defaultPolicy := gc.DefaultPolicy("codebase-id")
+	keepArchived := &gc.Policy{SnapshotRetentionHours: 3, ArchivedWorkspaceRetentionHours: 24}
+	collectSuggestions := &gc.Policy{SnapshotRetentionHours: 3, SuggestionSnapshotRetentionHours: intPtr(48)}
+
+	cases := []struct {
+		name             string
+		snapshot         *snapshots.Snapshot
+		workspace        *workspaces.Workspace
+		usedBySuggestion bool
+		usedByReview     bool
+		policy           *gc.Policy
+
+		expectedReason gc.Reason
+		expectedOK     bool
+	}{
+		{
+			name:     "unused-old",
+			snapshot: &snapshots.Snapshot{CreatedAt: hoursAgo(4)},
+			policy:   defaultPolicy,
+
+			expectedReason: gc.ReasonUnused, expectedOK: true,
+		},
+		{
+			name:     "unused-new",
+			snapshot: &snapshots.Snapshot{CreatedAt: hoursAgo(1)},
+			policy:   defaultPolicy,
+		},
+		{
+			name:     "deleted",
+			snapshot: &snapshots.Snapshot{CreatedAt: hoursAgo(4), DeletedAt: &archived},
+			policy:   defaultPolicy,
+		},
+		{
+			name:      "used-by-workspace",
+			snapshot:  &snapshots.Snapshot{CreatedAt: hoursAgo(100)},
+			workspace: &workspaces.Workspace{},
+			policy:    defaultPolicy,
+		},
+		{
+			name:      "archived-workspace",
+			snapshot:  &snapshots.Snapshot{CreatedAt: hoursAgo(100)},
+			workspace: &workspaces.Workspace{ArchivedAt: &archived},
+			policy:    defaultPolicy,
+
+			expectedReason: gc.ReasonArchivedWorkspace, expectedOK: true,
+		},
+		{
+			name:      "archived-workspace-kept",
+			snapshot:  &snapshots.Snapshot{CreatedAt: hoursAgo(100)},
+			workspace: &workspaces.Workspace{ArchivedAt: &archived},
+			policy:    keepArchived,
+		},
+		{
+			name:             "suggestion-kept-forever",
+			snapshot:         &snapshots.Snapshot{CreatedAt: hoursAgo(1000)},
+			usedBySuggestion: true,
+			policy:           defaultPolicy,
+		},
+		{
+			name:             "suggestion-new",
+			snapshot:         &snapshots.Snapshot{CreatedAt: hoursAgo(24)},
+			usedBySuggestion: true,
+			policy:           collectSuggestions,
+		},
+		{
+			name:             "suggestion-old",
+			snapshot:         &snapshots.Snapshot{CreatedAt: hoursAgo(49)},
+			usedBySuggestion: true,
+			policy:           collectSuggestions,
+
+			expectedReason: gc.ReasonSuggestion, expectedOK: true,
+		},
+		{
+			name:         "used-by-review",
+			snapshot:     &snapshots.Snapshot{CreatedAt: hoursAgo(100)},
+			usedByReview: true,
+			policy:       defaultPolicy,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reason, ok := collectReason(tc.snapshot, tc.workspace, tc.usedBySuggestion, tc.usedByReview, tc.policy, now)
+			assert.Equal(t, tc.expectedOK, ok)
+			assert.Equal(t, tc.expectedReason, reason)
+		})
+	}
+}
@@ -11,10 +11,12 @@ import (
 	"getsturdy.com/api/pkg/gc/service"
 	"getsturdy.com/api/pkg/queue"
 	"getsturdy.com/api/pkg/queue/names"
+	"getsturdy.com/api/pkg/users"
 )
 
 type CodebaseGarbageCollectionQueueEntry struct {
-	CodebaseID codebases.ID `json:"codebase_id"`
+	CodebaseID  codebases.ID `json:"codebase_id"`
+	TriggeredBy *users.ID    `json:"triggered_by,omitempty"`
 }
 
 type Queue struct {
@@ -47,6 +49,17 @@ func (q *Queue) Enqueue(ctx context.Context, codebaseID codebases.ID) error {
 	return nil
 }
 
+// EnqueueByUser schedules a garbage collection that runs even if the codebase has been collected recently.
+func (q *Queue) EnqueueByUser(ctx context.Context, codebaseID codebases.ID, userID users.ID) error {
+	if err := q.queue.Publish(ctx, q.name, &CodebaseGarbageCollectionQueueEntry{
+		CodebaseID:  codebaseID,
+		TriggeredBy: &userID,
+	}); err != nil {
+		return fmt.Errorf("could not publish to queue: %w", err)
+	}
+	return nil
+}
+
 func (q *Queue) Start(ctx context.Context) error {
 	messages := make(chan queue.Message)
 	go func() {
@@ -66,7 +79,12 @@ func (q *Queue) Start(ctx context.Context) error {
 			}
 			logger := q.logger.With(zap.Stringer("codebase_id", m.CodebaseID))
 
-			if err := q.service.Work(context.Background(), logger, m.CodebaseID); err != nil {
+			var opts []service.WorkOption
+			if m.TriggeredBy != nil {
+				opts = append(opts, service.Force(), service.TriggeredBy(*m.TriggeredBy))
+			}
+
+			if err := q.service.Work(context.Background(), logger, m.CodebaseID, opts...); err != nil {
 				logger.Error("failed to gc codebase", zap.Error(err))
 				continue
 			}
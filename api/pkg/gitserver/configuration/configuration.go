@@ -3,5 +3,6 @@ package configuration
 import "getsturdy.com/api/pkg/configuration/flags"
 
 type Configuration struct {
-	Addr flags.Addr `long:"addr" description:"listen address" default:"127.0.0.1:3002"`
+	Addr        flags.Addr `long:"addr" description:"listen address" default:"127.0.0.1:3002"`
+	MaxPushSize int64      `long:"max-push-size" description:"Maximum size of a single push in bytes, 0 means unlimited" default:"2147483648"`
 }
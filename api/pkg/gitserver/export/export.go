@@ -0,0 +1,269 @@
+package export
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"getsturdy.com/api/pkg/auth"
+	service_auth "getsturdy.com/api/pkg/auth/service"
+	service_changes "getsturdy.com/api/pkg/changes/service"
+	"getsturdy.com/api/pkg/codebases"
+	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
+	"getsturdy.com/api/pkg/users"
+	service_workspaces "getsturdy.com/api/pkg/workspaces/service"
+	"getsturdy.com/api/vcs"
+	"getsturdy.com/api/vcs/executor"
+	"getsturdy.com/api/vcs/provider"
+
+	"go.uber.org/zap"
+)
+
+const (
+	trunkRef           = "refs/heads/sturdytrunk"
+	changeRefPrefix    = "refs/sturdy/changes/"
+	workspaceRefPrefix = "refs/sturdy/workspaces/"
+
+	// rewrittenFileName is the file in the export repository where the ids of filtered commits are stored
+	// between fetches.
+	rewrittenFileName = "sturdy-rewritten"
+)
+
+// Service maintains the repositories that users clone and fetch codebases from over http.
+//
+// Each user gets their own bare repository per codebase, with copies of trunk, all changes, and the latest snapshot
+// of all workspaces, where all files that the user is not allowed to access by the codebase ACL are removed. The
+// repository borrows objects from trunk through git alternates, so only filtered trees and commits are stored in it.
+type Service struct {
+	logger           *zap.Logger
+	executorProvider executor.Provider
+	authService      *service_auth.Service
+	changeService    *service_changes.Service
+	workspaceService *service_workspaces.Service
+	snapshotsService *service_snapshots.Service
+}
+
+func New(
+	logger *zap.Logger,
+	executorProvider executor.Provider,
+	authService *service_auth.Service,
+	changeService *service_changes.Service,
+	workspaceService *service_workspaces.Service,
+	snapshotsService *service_snapshots.Service,
+) *Service {
+	return &Service{
+		logger:           logger.Named("gitserverExport"),
+		executorProvider: executorProvider,
+		authService:      authService,
+		changeService:    changeService,
+		workspaceService: workspaceService,
+		snapshotsService: snapshotsService,
+	}
+}
+
+// ViewID returns the id of the view that contains the export repository of the user.
+func ViewID(userID users.ID) string {
+	return fmt.Sprintf("export-%s", userID)
+}
+
+// Prepare brings the export repository of the user up to date with the codebase, and returns the id of the view
+// to serve it from.
+func (s *Service) Prepare(ctx context.Context, codebase *codebases.Codebase, userID users.ID) (string, error) {
+	allower, err := s.authService.GetAllower(auth.NewUserContext(ctx, userID), codebase)
+	if err != nil {
+		return "", fmt.Errorf("failed to get allower: %w", err)
+	}
+
+	refs, err := s.refs(ctx, codebase.ID)
+	if err != nil {
+		return "", err
+	}
+
+	fingerprint := fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(allower.Patterns, "\n"))))
+	allowed := func(filePath string) bool {
+		return allower.IsAllowed(filePath, false)
+	}
+
+	viewID := ViewID(userID)
+	if err := s.executorProvider.New().
+		AllowRebasingState(). // allowed because the repo might not exist yet
+		Schedule(func(repoProvider provider.RepoProvider) error {
+			exportPath := repoProvider.ViewPath(codebase.ID, viewID)
+			if _, err := os.Stat(exportPath); errors.Is(err, os.ErrNotExist) {
+				if _, err := vcs.CreateEmptyBareRepo(exportPath); err != nil {
+					return fmt.Errorf("failed to create export repo: %w", err)
+				}
+			} else if err != nil {
+				return fmt.Errorf("failed to stat export repo: %w", err)
+			}
+
+			// Read objects from trunk, instead of copying them
+			alternates := path.Join(repoProvider.TrunkPath(codebase.ID), "objects") + "\n"
+			if err := os.WriteFile(path.Join(exportPath, "objects", "info", "alternates"), []byte(alternates), 0o644); err != nil {
+				return fmt.Errorf("failed to write alternates: %w", err)
+			}
+
+			repo, err := repoProvider.ViewRepo(codebase.ID, viewID)
+			if err != nil {
+				return fmt.Errorf("failed to open export repo: %w", err)
+			}
+
+			rewrittenPath := path.Join(exportPath, rewrittenFileName)
+			rewritten, err := readRewritten(rewrittenPath, fingerprint)
+			if err != nil {
+				return err
+			}
+
+			filteredRefs := make(map[string]string, len(refs))
+			for name, commitID := range refs {
+				filteredID, err := repo.FilterCommit(commitID, allowed, rewritten)
+				if errors.Is(err, vcs.ErrNotFound) {
+					s.logger.Warn("commit not found, skipping", zap.String("ref", name), zap.String("commit_id", commitID))
+					continue
+				} else if err != nil {
+					return fmt.Errorf("failed to filter %s: %w", name, err)
+				}
+				filteredRefs[name] = filteredID
+			}
+
+			if err := writeRewritten(rewrittenPath, fingerprint, rewritten); err != nil {
+				return err
+			}
+
+			if err := repo.SetRefs("refs/", filteredRefs); err != nil {
+				return fmt.Errorf("failed to set refs: %w", err)
+			}
+
+			if err := repo.SetDefaultBranch("sturdytrunk"); err != nil {
+				return fmt.Errorf("failed to set default branch: %w", err)
+			}
+
+			return nil
+		}).ExecView(codebase.ID, viewID, "gitserverPrepareExport"); err != nil {
+		return "", err
+	}
+
+	return viewID, nil
+}
+
+// Ensure makes sure that the export repository of the user exists, and returns the id of the view to serve it from.
+// Unlike Prepare, an existing export is not updated, so that the refs that have been advertised to a client don't
+// change before it fetches them.
+func (s *Service) Ensure(ctx context.Context, codebase *codebases.Codebase, userID users.ID) (string, error) {
+	viewID := ViewID(userID)
+
+	var exists bool
+	if err := s.executorProvider.New().
+		AllowRebasingState(). // allowed because the repo might not exist yet
+		Schedule(func(repoProvider provider.RepoProvider) error {
+			_, err := os.Stat(repoProvider.ViewPath(codebase.ID, viewID))
+			switch {
+			case err == nil:
+				exists = true
+				return nil
+			case errors.Is(err, os.ErrNotExist):
+				return nil
+			default:
+				return fmt.Errorf("failed to stat export repo: %w", err)
+			}
+		}).ExecView(codebase.ID, viewID, "gitserverEnsureExport"); err != nil {
+		return "", err
+	}
+
+	if exists {
+		return viewID, nil
+	}
+	return s.Prepare(ctx, codebase, userID)
+}
+
+// refs returns the commits that should be exported by the name of their refs.
+func (s *Service) refs(ctx context.Context, codebaseID codebases.ID) (map[string]string, error) {
+	refs := make(map[string]string)
+
+	if err := s.executorProvider.New().GitRead(func(repo vcs.RepoGitReader) error {
+		trunkCommitID, err := repo.BranchCommitID("sturdytrunk")
+		if err != nil {
+			return fmt.Errorf("failed to get trunk head: %w", err)
+		}
+		refs[trunkRef] = trunkCommitID
+		return nil
+	}).ExecTrunk(codebaseID, "gitserverExportRefs"); err != nil {
+		return nil, err
+	}
+
+	changes, err := s.changeService.ListByCodebaseID(ctx, codebaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changes: %w", err)
+	}
+	for _, ch := range changes {
+		if ch.CommitID == nil {
+			continue
+		}
+		refs[changeRefPrefix+ch.ID.String()] = *ch.CommitID
+	}
+
+	workspaces, err := s.workspaceService.ListByCodebaseID(ctx, codebaseID, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	for _, ws := range workspaces {
+		if ws.LatestSnapshotID == nil {
+			continue
+		}
+		snapshot, err := s.snapshotsService.GetByID(ctx, *ws.LatestSnapshotID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get snapshot: %w", err)
+		}
+		refs[workspaceRefPrefix+ws.ID] = snapshot.CommitSHA
+	}
+
+	return refs, nil
+}
+
+// readRewritten reads the ids of commits that have already been filtered. If the commits were filtered for
+// a different set of allowed files, the previous result is discarded.
+func readRewritten(name, fingerprint string) (map[string]string, error) {
+	rewritten := make(map[string]string)
+
+	f, err := os.Open(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return rewritten, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() || scanner.Text() != fingerprint {
+		return rewritten, nil
+	}
+	for scanner.Scan() {
+		parts := strings.Fields(scanner.Text())
+		if len(parts) != 2 {
+			continue
+		}
+		rewritten[parts[0]] = parts[1]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	return rewritten, nil
+}
+
+func writeRewritten(name, fingerprint string, rewritten map[string]string) error {
+	var b strings.Builder
+	b.WriteString(fingerprint + "\n")
+	for commitID, filteredID := range rewritten {
+		b.WriteString(commitID + " " + filteredID + "\n")
+	}
+	if err := os.WriteFile(name, []byte(b.String()), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package export
+
+import (
+	service_auth "getsturdy.com/api/pkg/auth/service"
+	service_changes "getsturdy.com/api/pkg/changes/service"
+	"getsturdy.com/api/pkg/di"
+	"getsturdy.com/api/pkg/logger"
+	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
+	service_workspaces "getsturdy.com/api/pkg/workspaces/service"
+	"getsturdy.com/api/vcs/executor"
+)
+
+func Module(c *di.Container) {
+	c.Import(logger.Module)
+	c.Import(executor.Module)
+	c.Import(service_auth.Module)
+	c.Import(service_changes.Module)
+	c.Import(service_workspaces.Module)
+	c.Import(service_snapshots.Module)
+	c.Register(New)
+}
@@ -1,9 +1,11 @@
 package gitserver
 
 import (
+	service_accesstokens "getsturdy.com/api/pkg/accesstokens/service"
 	service_codebase "getsturdy.com/api/pkg/codebases/service"
 	configuration "getsturdy.com/api/pkg/configuration/module"
 	"getsturdy.com/api/pkg/di"
+	"getsturdy.com/api/pkg/gitserver/export"
 	service_jwt "getsturdy.com/api/pkg/jwt/service"
 	"getsturdy.com/api/pkg/logger"
 	service_servicetokens "getsturdy.com/api/pkg/servicetokens/service"
@@ -15,7 +17,9 @@ func Module(c *di.Container) {
 	c.Import(configuration.Module)
 	c.Import(service_servicetokens.Module)
 	c.Import(service_jwt.Module)
+	c.Import(service_accesstokens.Module)
 	c.Import(service_codebase.Module)
+	c.Import(export.Module)
 	c.Import(executor.Module)
 	c.Register(New)
 }
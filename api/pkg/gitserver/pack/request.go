@@ -0,0 +1,193 @@
+package pack
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxPktLen is the maximum length of a pkt-line, including the four byte length prefix.
+	maxPktLen = 65520
+
+	// maxCommands is the maximum number of ref updates accepted in a single request.
+	maxCommands = 1024
+)
+
+var (
+	ErrInvalidPktLine  = errors.New("invalid pkt-line")
+	ErrInvalidCommand  = errors.New("invalid command")
+	ErrTooManyCommands = errors.New("too many commands")
+)
+
+// Command is a single ref update sent by the client in a receive-pack request.
+type Command struct {
+	OldID string
+	NewID string
+	Ref   string
+}
+
+// IsCreate returns true if the command creates the ref.
+func (c Command) IsCreate() bool {
+	return isZeroID(c.OldID)
+}
+
+// IsDelete returns true if the command deletes the ref.
+func (c Command) IsDelete() bool {
+	return isZeroID(c.NewID)
+}
+
+// Branch returns the branch name the command updates, or false if the command
+// updates a ref that is not a branch.
+func (c Command) Branch() (string, bool) {
+	if !strings.HasPrefix(c.Ref, "refs/heads/") {
+		return "", false
+	}
+	return strings.TrimPrefix(c.Ref, "refs/heads/"), true
+}
+
+// Request is the command list that starts a receive-pack request.
+type Request struct {
+	Commands     []Command
+	Capabilities []string
+}
+
+// ReadRequest reads the command list of a receive-pack request from r, up to and
+// including the flush-pkt that terminates it.
+//
+// ReadRequest never reads past the flush-pkt, the packfile that follows the command
+// list can be read from r afterwards.
+func ReadRequest(r io.Reader) (*Request, error) {
+	req := &Request{}
+	for {
+		line, flush, err := readPktLine(r)
+		if err != nil {
+			return nil, err
+		}
+		if flush {
+			return req, nil
+		}
+
+		if len(req.Commands) == maxCommands {
+			return nil, ErrTooManyCommands
+		}
+
+		// The first command carries the capabilities after a NUL byte
+		// 0000000000000000000000000000000000000000 2ab8b0433111e6d5602a71049e40902c1e5a556c refs/heads/sturdytrunk\x00 report-status side-band-64k
+		if idx := bytes.IndexByte(line, 0); idx >= 0 {
+			if len(req.Commands) == 0 {
+				req.Capabilities = strings.Fields(string(line[idx+1:]))
+			}
+			line = line[:idx]
+		}
+
+		parts := strings.Fields(string(line))
+		if len(parts) != 3 {
+			return nil, ErrInvalidCommand
+		}
+		req.Commands = append(req.Commands, Command{
+			OldID: parts[0],
+			NewID: parts[1],
+			Ref:   parts[2],
+		})
+	}
+}
+
+// HasCapability returns true if the client requested the capability.
+func (req *Request) HasCapability(name string) bool {
+	for _, c := range req.Capabilities {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteRejection writes a receive-pack result to w that rejects all commands in the
+// request with the given reason, in a way that the git client will show to the user.
+func (req *Request) WriteRejection(w io.Writer, reason string) error {
+	reportStatus := req.HasCapability("report-status") || req.HasCapability("report-status-v2")
+
+	var band byte = 1
+	var result bytes.Buffer
+	if reportStatus {
+		writePktLine(&result, []byte("unpack "+reason+"\n"))
+		for _, cmd := range req.Commands {
+			writePktLine(&result, []byte("ng "+cmd.Ref+" "+reason+"\n"))
+		}
+		result.WriteString("0000")
+	} else {
+		// Without a status report, the only way to tell the client is to fail the request.
+		band = 3
+		result.WriteString(reason + "\n")
+	}
+
+	var sidebandLen int
+	switch {
+	case req.HasCapability("side-band-64k"):
+		sidebandLen = maxPktLen - 5
+	case req.HasCapability("side-band"):
+		sidebandLen = 1000 - 5
+	}
+
+	var out bytes.Buffer
+	switch {
+	case sidebandLen > 0:
+		payload := result.Bytes()
+		for len(payload) > 0 {
+			n := sidebandLen
+			if n > len(payload) {
+				n = len(payload)
+			}
+			writePktLine(&out, append([]byte{band}, payload[:n]...))
+			payload = payload[n:]
+		}
+		out.WriteString("0000")
+	case reportStatus:
+		out.Write(result.Bytes())
+	default:
+		writePktLine(&out, []byte("ERR "+reason))
+	}
+
+	if _, err := w.Write(out.Bytes()); err != nil {
+		return fmt.Errorf("failed to write rejection: %w", err)
+	}
+	return nil
+}
+
+// readPktLine reads exactly one pkt-line from r, and returns its payload.
+func readPktLine(r io.Reader) ([]byte, bool, error) {
+	var prefix [4]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		return nil, false, fmt.Errorf("failed to read pkt-line length: %w", err)
+	}
+
+	length, err := strconv.ParseUint(string(prefix[:]), 16, 16)
+	if err != nil {
+		return nil, false, ErrInvalidPktLine
+	}
+	if length == 0 {
+		return nil, true, nil
+	}
+	if length < 4 || length > maxPktLen {
+		return nil, false, ErrInvalidPktLine
+	}
+
+	line := make([]byte, length-4)
+	if _, err := io.ReadFull(r, line); err != nil {
+		return nil, false, fmt.Errorf("failed to read pkt-line: %w", err)
+	}
+	return bytes.TrimSuffix(line, []byte("\n")), false, nil
+}
+
+func isZeroID(id string) bool {
+	return strings.Trim(id, "0") == ""
+}
+
+func writePktLine(buf *bytes.Buffer, payload []byte) {
+	fmt.Fprintf(buf, "%04x", len(payload)+4)
+	buf.Write(payload)
+}
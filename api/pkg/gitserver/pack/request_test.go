@@ -0,0 +1,100 @@
+package pack
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestReadRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		expected Command
+	}{
+		{
+			name: "libgit2-import",
+			file: "testdata/libgit2.bin",
+			expected: Command{
+				OldID: "3d04fb9e040baffdce3c20dc1335a9c9c90ae77e",
+				NewID: "487f2a8287c4d1d41d39e05ac53d0e50c679d7e9",
+				Ref:   "refs/heads/sturdytrunk",
+			},
+		},
+		{
+			name: "libgit2-fresh-import",
+			file: "testdata/libgit2-fresh.bin",
+			expected: Command{
+				OldID: "85b7d40ad0a7a14b47d48c6ee2286dfaa96584d8",
+				NewID: "487f2a8287c4d1d41d39e05ac53d0e50c679d7e9",
+				Ref:   "refs/heads/sturdytrunk",
+			},
+		},
+		{
+			name: "kube-score-import",
+			file: "testdata/kube-score.bin",
+			expected: Command{
+				OldID: "487f2a8287c4d1d41d39e05ac53d0e50c679d7e9",
+				NewID: "3d04fb9e040baffdce3c20dc1335a9c9c90ae77e",
+				Ref:   "refs/heads/sturdytrunk",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.file)
+			assert.Equal(t, nil, err)
+			defer f.Close()
+
+			r := bufio.NewReader(f)
+			req, err := ReadRequest(r)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, []Command{tt.expected}, req.Commands)
+			assert.Equal(t, true, req.HasCapability("report-status"))
+			assert.Equal(t, true, req.HasCapability("side-band-64k"))
+
+			branch, ok := req.Commands[0].Branch()
+			assert.Equal(t, true, ok)
+			assert.Equal(t, "sturdytrunk", branch)
+
+			// the packfile is left unread
+			rest, err := io.ReadAll(r)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, true, bytes.HasPrefix(rest, []byte("PACK")))
+		})
+	}
+}
+
+func TestReadRequest_empty(t *testing.T) {
+	req, err := ReadRequest(bytes.NewReader([]byte("0000")))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(req.Commands))
+}
+
+func TestReadRequest_invalid(t *testing.T) {
+	_, err := ReadRequest(bytes.NewReader([]byte("zzzzPACK")))
+	assert.Equal(t, ErrInvalidPktLine, err)
+
+	_, err = ReadRequest(bytes.NewReader([]byte("000cfoo bar\n")))
+	assert.Equal(t, ErrInvalidCommand, err)
+}
+
+func TestWriteRejection(t *testing.T) {
+	req := &Request{
+		Commands:     []Command{{OldID: "a", NewID: "b", Ref: "refs/heads/main"}},
+		Capabilities: []string{"report-status"},
+	}
+
+	var buf bytes.Buffer
+	assert.Equal(t, nil, req.WriteRejection(&buf, "denied"))
+	assert.Equal(t, "0012unpack denied\n001eng refs/heads/main denied\n0000", buf.String())
+
+	req.Capabilities = append(req.Capabilities, "side-band-64k")
+	buf.Reset()
+	assert.Equal(t, nil, req.WriteRejection(&buf, "denied"))
+	assert.Equal(t, "0039\x010012unpack denied\n001eng refs/heads/main denied\n00000000", buf.String())
+}
@@ -0,0 +1,225 @@
+package gitserver
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"getsturdy.com/api/pkg/gitserver/pack"
+)
+
+var errStaleRef = errors.New("ref has been updated since the push started")
+
+// quarantine is a temporary repository that a push is received into, before any of it is added to the trunk.
+//
+// The quarantine borrows all objects of the trunk as alternates, so the client only has to send the objects that
+// the trunk is missing, and the refs of the trunk are copied to it, so that git receive-pack can verify the old
+// values of the pushed refs.
+type quarantine struct {
+	path      string
+	trunkPath string
+}
+
+func newQuarantine(trunkPath string, refs map[string]string) (*quarantine, error) {
+	path, err := os.MkdirTemp("", "sturdy-receive-pack-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create quarantine: %w", err)
+	}
+	q := &quarantine{path: path, trunkPath: trunkPath}
+
+	if err := q.git(nil, nil, "init", "--quiet", "--bare", path); err != nil {
+		q.Close()
+		return nil, err
+	}
+
+	alternates := filepath.Join(path, "objects", "info", "alternates")
+	if err := os.WriteFile(alternates, []byte(filepath.Join(trunkPath, "objects")+"\n"), 0o644); err != nil {
+		q.Close()
+		return nil, fmt.Errorf("failed to write alternates: %w", err)
+	}
+
+	for ref, id := range refs {
+		if err := q.git(nil, nil, "update-ref", ref, id); err != nil {
+			q.Close()
+			return nil, err
+		}
+	}
+
+	return q, nil
+}
+
+// Close removes the quarantine.
+func (q *quarantine) Close() {
+	_ = os.RemoveAll(q.path)
+}
+
+// ReceivePack runs git receive-pack in the quarantine, and writes its output to w.
+func (q *quarantine) ReceivePack(r io.Reader, w io.Writer) error {
+	if err := q.git(r, w, "receive-pack", "--stateless-rpc", q.path); err != nil {
+		return fmt.Errorf("receive-pack failed: %w", err)
+	}
+	return nil
+}
+
+// Accepted returns true if git receive-pack has applied all commands to the quarantine.
+func (q *quarantine) Accepted(commands []pack.Command) (bool, error) {
+	for _, cmd := range commands {
+		id, err := revParse(q.path, cmd.Ref)
+		if err != nil {
+			return false, err
+		}
+		if cmd.IsDelete() && id != "" || !cmd.IsDelete() && id != cmd.NewID {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// MigrateObjects copies all objects that were received into the quarantine to the trunk.
+//
+// Objects are immutable, so this is safe to do without holding the lock of the trunk. Pack indexes are copied
+// after the packs, and every file is moved into place atomically, so that concurrent readers never see a
+// partial object.
+func (q *quarantine) MigrateObjects() error {
+	objectsPath := filepath.Join(q.path, "objects")
+
+	var files []string
+	if err := filepath.WalkDir(objectsPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == "info" {
+				// the alternates of the quarantine
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(objectsPath, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, rel)
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to list received objects: %w", err)
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		return !strings.HasSuffix(files[i], ".idx") && strings.HasSuffix(files[j], ".idx")
+	})
+
+	for _, rel := range files {
+		if err := copyObjectFile(filepath.Join(objectsPath, rel), filepath.Join(q.trunkPath, "objects", rel)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyObjectFile(from, to string) error {
+	if _, err := os.Stat(to); err == nil {
+		// the trunk already has the object
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(to), 0o755); err != nil {
+		return fmt.Errorf("failed to create objects directory: %w", err)
+	}
+
+	src, err := os.Open(from)
+	if err != nil {
+		return fmt.Errorf("failed to open object: %w", err)
+	}
+	defer src.Close()
+
+	// tmp_ files are cleaned up by git gc, if they are left behind
+	dst, err := os.CreateTemp(filepath.Dir(to), "tmp_receive_")
+	if err != nil {
+		return fmt.Errorf("failed to create object: %w", err)
+	}
+	defer os.Remove(dst.Name())
+
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return fmt.Errorf("failed to copy object: %w", err)
+	}
+	if err := dst.Close(); err != nil {
+		return fmt.Errorf("failed to copy object: %w", err)
+	}
+	if err := os.Chmod(dst.Name(), 0o444); err != nil {
+		return fmt.Errorf("failed to copy object: %w", err)
+	}
+	if err := os.Rename(dst.Name(), to); err != nil {
+		return fmt.Errorf("failed to move object: %w", err)
+	}
+	return nil
+}
+
+// updateRefs applies the commands to the repository at path, in a single transaction. It returns errStaleRef if
+// any of the refs no longer has the value that the client based its push on.
+func updateRefs(path string, commands []pack.Command) error {
+	var stdin bytes.Buffer
+	for _, cmd := range commands {
+		id, err := revParse(path, cmd.Ref)
+		if err != nil {
+			return err
+		}
+		expected := cmd.OldID
+		if cmd.IsCreate() {
+			expected = ""
+		}
+		if id != expected {
+			return fmt.Errorf("%s: %w", cmd.Ref, errStaleRef)
+		}
+
+		if cmd.IsDelete() {
+			fmt.Fprintf(&stdin, "delete %s %s\n", cmd.Ref, cmd.OldID)
+		} else {
+			fmt.Fprintf(&stdin, "update %s %s %s\n", cmd.Ref, cmd.NewID, cmd.OldID)
+		}
+	}
+
+	cmd := exec.Command("git", "update-ref", "--stdin")
+	cmd.Dir = path
+	cmd.Stdin = &stdin
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to update refs: %w, %s", err, string(output))
+	}
+	return nil
+}
+
+// revParse returns the id that the ref points to, or an empty string if the ref does not exist.
+func revParse(path, ref string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", ref)
+	cmd.Dir = path
+	output, err := cmd.Output()
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr) && exitErr.ExitCode() == 1:
+		return "", nil
+	case err != nil:
+		return "", fmt.Errorf("failed to resolve %s: %w", ref, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+func (q *quarantine) git(stdin io.Reader, stdout io.Writer, args ...string) error {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Dir = q.path
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git %s failed: %w, %s", args[0], err, stderr.String())
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package gitserver
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"getsturdy.com/api/pkg/gitserver/pack"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func git(t *testing.T, dir string, args ...string) string {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@getsturdy.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@getsturdy.com",
+	)
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(output))
+	return strings.TrimSpace(string(output))
+}
+
+func commit(t *testing.T, dir, name string) string {
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(name), 0o644))
+	git(t, dir, "add", name)
+	git(t, dir, "commit", "--quiet", "-m", name)
+	return git(t, dir, "rev-parse", "HEAD")
+}
+
+func setupTrunk(t *testing.T) (string, string, string) {
+	base := t.TempDir()
+	trunkPath := filepath.Join(base, "trunk")
+	clonePath := filepath.Join(base, "clone")
+
+	git(t, base, "init", "--quiet", "--bare", trunkPath)
+	git(t, base, "init", "--quiet", clonePath)
+	git(t, clonePath, "checkout", "--quiet", "-b", "sturdytrunk")
+	head := commit(t, clonePath, "a.txt")
+	git(t, clonePath, "push", "--quiet", trunkPath, "sturdytrunk")
+
+	return trunkPath, clonePath, head
+}
+
+func TestQuarantine(t *testing.T) {
+	trunkPath, clonePath, head := setupTrunk(t)
+	newHead := commit(t, clonePath, "b.txt")
+
+	q, err := newQuarantine(trunkPath, map[string]string{"refs/heads/sturdytrunk": head})
+	require.NoError(t, err)
+	defer q.Close()
+
+	git(t, clonePath, "push", "--quiet", q.path, "sturdytrunk")
+
+	commands := []pack.Command{{OldID: head, NewID: newHead, Ref: "refs/heads/sturdytrunk"}}
+	accepted, err := q.Accepted(commands)
+	require.NoError(t, err)
+	assert.True(t, accepted)
+
+	// nothing is added to the trunk before the objects are migrated
+	assert.Equal(t, head, git(t, trunkPath, "rev-parse", "sturdytrunk"))
+	_, err = exec.Command("git", "-C", trunkPath, "cat-file", "-e", newHead).Output()
+	assert.Error(t, err)
+
+	require.NoError(t, q.MigrateObjects())
+	require.NoError(t, updateRefs(trunkPath, commands))
+
+	assert.Equal(t, newHead, git(t, trunkPath, "rev-parse", "sturdytrunk"))
+	git(t, trunkPath, "fsck", "--no-progress")
+}
+
+func TestQuarantine_not_accepted(t *testing.T) {
+	trunkPath, clonePath, head := setupTrunk(t)
+	newHead := commit(t, clonePath, "b.txt")
+
+	q, err := newQuarantine(trunkPath, map[string]string{"refs/heads/sturdytrunk": head})
+	require.NoError(t, err)
+	defer q.Close()
+
+	// the push was never received
+	accepted, err := q.Accepted([]pack.Command{{OldID: head, NewID: newHead, Ref: "refs/heads/sturdytrunk"}})
+	require.NoError(t, err)
+	assert.False(t, accepted)
+}
+
+func TestUpdateRefs_stale(t *testing.T) {
+	trunkPath, clonePath, head := setupTrunk(t)
+	first := commit(t, clonePath, "b.txt")
+	git(t, clonePath, "push", "--quiet", trunkPath, "sturdytrunk")
+
+	// the client pushed based on the old head, while the trunk moved on
+	second := commit(t, clonePath, "c.txt")
+	err := updateRefs(trunkPath, []pack.Command{{OldID: head, NewID: second, Ref: "refs/heads/sturdytrunk"}})
+	assert.ErrorIs(t, err, errStaleRef)
+	assert.Equal(t, first, git(t, trunkPath, "rev-parse", "sturdytrunk"))
+
+	// creating a ref that exists is also stale
+	err = updateRefs(trunkPath, []pack.Command{{OldID: strings.Repeat("0", 40), NewID: second, Ref: "refs/heads/sturdytrunk"}})
+	assert.ErrorIs(t, err, errStaleRef)
+}
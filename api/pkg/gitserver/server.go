@@ -1,7 +1,9 @@
 package gitserver
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -11,9 +13,12 @@ import (
 	"strings"
 	"time"
 
+	"getsturdy.com/api/pkg/accesstokens"
+	service_accesstokens "getsturdy.com/api/pkg/accesstokens/service"
 	"getsturdy.com/api/pkg/codebases"
 	service_codebase "getsturdy.com/api/pkg/codebases/service"
 	"getsturdy.com/api/pkg/gitserver/configuration"
+	"getsturdy.com/api/pkg/gitserver/export"
 	"getsturdy.com/api/pkg/gitserver/pack"
 	"getsturdy.com/api/pkg/jwt"
 	service_jwt "getsturdy.com/api/pkg/jwt/service"
@@ -35,7 +40,9 @@ type Server struct {
 
 	serviceTokensService *service_servicetokens.Service
 	jwtTokensService     *service_jwt.Service
+	accessTokensService  *service_accesstokens.Service
 	codebaseService      *service_codebase.Service
+	exportService        *export.Service
 	executorProvider     executor.Provider
 
 	router *gin.Engine
@@ -53,7 +60,9 @@ func New(
 	cfg *configuration.Configuration,
 	serviceTokensService *service_servicetokens.Service,
 	jwtTokensService *service_jwt.Service,
+	accessTokensService *service_accesstokens.Service,
 	codebaeService *service_codebase.Service,
+	exportService *export.Service,
 	executorProvider executor.Provider,
 ) *Server {
 	gin.SetMode(ginMode())
@@ -65,7 +74,9 @@ func New(
 
 		serviceTokensService: serviceTokensService,
 		jwtTokensService:     jwtTokensService,
+		accessTokensService:  accessTokensService,
 		codebaseService:      codebaeService,
+		exportService:        exportService,
 		executorProvider:     executorProvider,
 
 		router: ginRouter,
@@ -80,9 +91,10 @@ func (h *Server) Start() error {
 	ciIntegrationGroup.GET("/info/refs", h.handleInfoRefs)
 	ciIntegrationGroup.POST("/git-upload-pack", h.handleGitUploadPack)
 
-	importGroup := h.router.Group("/:codebaseId").Use(h.jwtTokenAuth)
-	importGroup.GET("/info/refs", h.handleInfoRefs)
-	importGroup.POST("/git-receive-pack", h.handleGitReceivePack)
+	userGroup := h.router.Group("/:codebaseId").Use(h.jwtTokenAuth)
+	userGroup.GET("/info/refs", h.requireServiceScope, h.handleInfoRefs)
+	userGroup.POST("/git-upload-pack", h.requireScope(accesstokens.ScopeReadCodebase), h.handleUserGitUploadPack)
+	userGroup.POST("/git-receive-pack", h.requireScope(accesstokens.ScopeLand), h.handleGitReceivePack)
 
 	h.logger.Info("starting gitserver", zap.Stringer("addr", h.cfg.Addr))
 
@@ -96,6 +108,7 @@ func (h *Server) Start() error {
 const (
 	tokenKey  = "token"
 	userIDKey = "user_id"
+	scopesKey = "scopes"
 	ciRepo    = "ci"
 )
 
@@ -107,19 +120,36 @@ func (h *Server) jwtTokenAuth(c *gin.Context) {
 		return
 	}
 
-	if username != "import" {
-		c.AbortWithStatus(http.StatusUnauthorized)
-		return
-	}
-
-	userToken, err := h.jwtTokensService.Verify(c.Request.Context(), password, jwt.TokenTypeAuth)
-	if err != nil {
-		c.AbortWithStatus(http.StatusUnauthorized)
-		return
+	// The username is not used, imports use "import", and users can use anything they like
+	_ = username
+
+	var userID users.ID
+	if service_accesstokens.IsAccessToken(password) {
+		accessToken, err := h.accessTokensService.Verify(c.Request.Context(), password)
+		switch {
+		case errors.Is(err, service_accesstokens.ErrInvalidToken),
+			errors.Is(err, service_accesstokens.ErrExpired),
+			errors.Is(err, service_accesstokens.ErrRevoked):
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		case err != nil:
+			h.logger.Error("failed to verify access token", zap.Error(err))
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+		userID = accessToken.UserID
+		c.Set(scopesKey, accessToken.ScopeList())
+	} else {
+		userToken, err := h.jwtTokensService.Verify(c.Request.Context(), password, jwt.TokenTypeAuth)
+		if err != nil {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		userID = users.ID(userToken.Subject)
 	}
 
 	codebaseID := codebases.ID(c.Param("codebaseId"))
-	accessAllowed, err := h.codebaseService.CanAccess(c.Request.Context(), users.ID(userToken.Subject), codebaseID)
+	accessAllowed, err := h.codebaseService.CanAccess(c.Request.Context(), userID, codebaseID)
 	if err != nil {
 		h.logger.Error("failed to check access", zap.Error(err))
 		c.AbortWithStatus(http.StatusInternalServerError)
@@ -131,7 +161,35 @@ func (h *Server) jwtTokenAuth(c *gin.Context) {
 		return
 	}
 
-	c.Set(userIDKey, userToken.Subject)
+	c.Set(userIDKey, userID.String())
+}
+
+// requireScope aborts the request if the user authenticated with an access token that does not have the scope.
+func (h *Server) requireScope(scope accesstokens.Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scopes, ok := c.Get(scopesKey)
+		if !ok {
+			// authenticated with a session, not limited by scopes
+			return
+		}
+		if !accesstokens.HasScope(scopes.([]accesstokens.Scope), scope) {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+	}
+}
+
+// requireServiceScope is requireScope for the scope that is needed by the requested service, fetching needs
+// ScopeReadCodebase and pushing needs ScopeLand.
+func (h *Server) requireServiceScope(c *gin.Context) {
+	switch getServiceName(c.Request) {
+	case "upload-pack":
+		h.requireScope(accesstokens.ScopeReadCodebase)(c)
+	case "receive-pack":
+		h.requireScope(accesstokens.ScopeLand)(c)
+	default:
+		c.AbortWithStatus(http.StatusBadRequest)
+	}
 }
 
 func (h *Server) serviceTokenAuth(c *gin.Context) {
@@ -168,6 +226,15 @@ func getToken(c *gin.Context) *servicetokens.Token {
 	return token.(*servicetokens.Token)
 }
 
+func getUserID(c *gin.Context) users.ID {
+	userID, ok := c.Get(userIDKey)
+	if !ok {
+		return ""
+	}
+
+	return users.ID(userID.(string))
+}
+
 func getServiceName(r *http.Request) string {
 	if service, fromQuery := r.URL.Query()["service"]; fromQuery {
 		return strings.Replace(service[0], "git-", "", 1)
@@ -185,52 +252,171 @@ func (h *Server) handleGitReceivePack(c *gin.Context) {
 
 	c.Writer.Header().Set("Content-Type", "application/x-git-receive-pack-result")
 
-	// TODO: This buffers the whole request (including _all_ object data etc), in memory.
-	// Rewrite so that the header can be parsed of a stream (r.Body) as it's read by ProcInput?
-	requestBody, err := io.ReadAll(c.Request.Body)
+	body := io.Reader(c.Request.Body)
+	var limited *limitedReader
+	if h.cfg.MaxPushSize > 0 {
+		limited = &limitedReader{r: body, n: h.cfg.MaxPushSize}
+		body = limited
+	}
+
+	// Only the command list is parsed here, everything that is read while parsing it is replayed
+	// to git receive-pack together with the rest of the request, that is streamed as is.
+	var consumed bytes.Buffer
+	bodyReader := bufio.NewReader(body)
+	request, err := pack.ReadRequest(io.TeeReader(bodyReader, &consumed))
 	if err != nil {
-		h.logger.Error("receive-pack failed to read request", zap.Error(err))
-		c.AbortWithStatus(http.StatusInternalServerError)
+		h.logger.Error("receive-pack failed to read request", zap.Error(err), zap.Stringer("codebase_id", codebaseID))
+		c.AbortWithStatus(http.StatusBadRequest)
 		return
 	}
 
-	var firstRow []byte
-	if idx := bytes.Index(requestBody, []byte("\n")); idx > 0 {
-		firstRow = requestBody[0:idx]
-	} else {
-		firstRow = requestBody
+	if h.cfg.MaxPushSize > 0 && c.Request.ContentLength > h.cfg.MaxPushSize {
+		h.logger.Warn("receive-pack request too large",
+			zap.Int64("content_length", c.Request.ContentLength),
+			zap.Stringer("codebase_id", codebaseID),
+		)
+		h.rejectReceivePack(c, request, fmt.Sprintf("push exceeds the maximum size of %d bytes", h.cfg.MaxPushSize))
+		return
 	}
 
-	// TODO: Learn more about git-receive-pack and the protocol to figure out why the headers are not always sent
-	//       and how to keep doing this in a safe way.
-
-	if string(firstRow) != "0000" {
-		// If we have a header, parse it!
-		header, err := pack.ParseHeader(firstRow)
-		if err != nil {
-			c.AbortWithStatus(http.StatusBadRequest)
-			h.logger.Error("receive-pack failed to parse header", zap.Error(err))
-			return
-		}
-
-		if header.Branch != "sturdytrunk" {
-			c.AbortWithStatus(http.StatusBadRequest)
+	for _, cmd := range request.Commands {
+		if branch, ok := cmd.Branch(); !ok || branch != "sturdytrunk" {
 			h.logger.Error("receive-pack request to non sturdytrunk branch",
-				zap.String("branch", header.Branch),
+				zap.String("ref", cmd.Ref),
 				zap.Stringer("codebase_id", codebaseID),
 			)
+			h.rejectReceivePack(c, request, "only sturdytrunk can be pushed to")
 			return
 		}
 	}
 
+	// The trunk is only locked to read its head, and to update its refs. The push itself is received into a
+	// quarantine, so that slow or large pushes do not block everything else that uses the trunk.
+	var trunkPath string
+	trunkRefs := map[string]string{}
+	if err := h.executorProvider.New().Read(func(repo vcs.RepoReader) error {
+		trunkPath = repo.Path()
+		// If sturdytrunk doesn't exist (such as when an empty repository has been imported), there is no head
+		if id, err := repo.BranchCommitID("sturdytrunk"); err == nil {
+			trunkRefs["refs/heads/sturdytrunk"] = id
+		}
+		return nil
+	}).ExecTrunk(codebaseID, "gitserverGitReceivePackPrepare"); err != nil {
+		h.logger.Error("failed to read trunk", zap.Error(err), zap.Stringer("codebase_id", codebaseID))
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	q, err := newQuarantine(trunkPath, trunkRefs)
+	if err != nil {
+		h.logger.Error("failed to create quarantine", zap.Error(err), zap.Stringer("codebase_id", codebaseID))
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+	defer q.Close()
+
+	// git receive-pack reports the result of the push to the client on stdout. The result is held back until the
+	// trunk has been updated, as the update can still be rejected.
+	var result bytes.Buffer
+	if err := q.ReceivePack(io.MultiReader(&consumed, bodyReader), &result); limited != nil && limited.exceeded {
+		// git receive-pack has already reported the truncated pack to the client
+		h.logger.Warn("receive-pack request too large", zap.Stringer("codebase_id", codebaseID), zap.Error(err))
+		h.writeReceivePackResult(c, &result)
+		return
+	} else if err != nil {
+		h.logger.Error("failed to handle git receive pack", zap.Error(err))
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if accepted, err := q.Accepted(request.Commands); err != nil {
+		h.logger.Error("failed to check receive-pack result", zap.Error(err))
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	} else if !accepted {
+		// git receive-pack has already reported why the push was refused
+		h.writeReceivePackResult(c, &result)
+		return
+	}
+
+	if err := q.MigrateObjects(); err != nil {
+		h.logger.Error("failed to migrate received objects", zap.Error(err), zap.Stringer("codebase_id", codebaseID))
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
 	if err := h.executorProvider.New().Write(func(repo vcs.RepoWriter) error {
-		args := []string{"receive-pack", "--stateless-rpc", repo.Path()}
+		return updateRefs(repo.Path(), request.Commands)
+	}).ExecTrunk(codebaseID, "gitserverGitReceivePack"); errors.Is(err, errStaleRef) {
+		h.rejectReceivePack(c, request, "sturdytrunk was updated during the push, fetch and try again")
+		return
+	} else if err != nil {
+		h.logger.Error("failed to update trunk", zap.Error(err), zap.Stringer("codebase_id", codebaseID))
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	h.writeReceivePackResult(c, &result)
+}
+
+func (h *Server) writeReceivePackResult(c *gin.Context, result io.Reader) {
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, result); err != nil {
+		h.logger.Error("failed to write receive-pack result", zap.Error(err))
+	}
+}
+
+// rejectReceivePack responds to a receive-pack request without running git receive-pack, the git
+// client shows the reason to the user.
+func (h *Server) rejectReceivePack(c *gin.Context, request *pack.Request, reason string) {
+	c.Status(http.StatusOK)
+	if err := request.WriteRejection(c.Writer, reason); err != nil {
+		h.logger.Error("failed to reject receive-pack request", zap.Error(err))
+	}
+	c.Abort()
+}
+
+var errPushTooLarge = errors.New("push is too large")
+
+// limitedReader is like io.LimitedReader, but returns errPushTooLarge if there is more data than
+// allowed to read, instead of io.EOF.
+type limitedReader struct {
+	r        io.Reader
+	n        int64
+	exceeded bool
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n <= 0 {
+		// Check if there is anything left to read
+		var b [1]byte
+		if n, _ := l.r.Read(b[:]); n > 0 {
+			l.exceeded = true
+			return 0, errPushTooLarge
+		}
+		return 0, io.EOF
+	}
+	if int64(len(p)) > l.n {
+		p = p[:l.n]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	return n, err
+}
+
+func (h *Server) handleGitUploadPack(c *gin.Context) {
+	token := getToken(c)
+
+	c.Writer.Header().Set("Content-Type", "application/x-git-upload-pack-result")
+
+	if err := h.executorProvider.New().Read(func(repo vcs.RepoReader) error {
+		args := []string{"upload-pack", "--stateless-rpc", repo.Path()}
 		cmd := exec.Command("git", args...)
+		cmd.Stdin = c.Request.Body
 		stdout, err := cmd.StdoutPipe()
 		if err != nil {
 			return fmt.Errorf("failed to get stdout: %w", err)
 		}
-		cmd.Stdin = bytes.NewReader(requestBody)
 
 		if err := cmd.Start(); err != nil {
 			return fmt.Errorf("failed to start command: %w", err)
@@ -241,15 +427,53 @@ func (h *Server) handleGitReceivePack(c *gin.Context) {
 		}
 
 		return nil
-	}).ExecTrunk(codebaseID, "gitserverGitReceivePack"); err != nil {
-		h.logger.Error("failed to handle git receive pack", zap.Error(err))
+	}).ExecView(token.CodebaseID, ciRepo, "gitserverGitUploadPack"); err != nil {
+		h.logger.Error("failed to handle git upload pack", zap.Error(err))
 		c.AbortWithError(http.StatusInternalServerError, err)
 		return
 	}
 }
 
-func (h *Server) handleGitUploadPack(c *gin.Context) {
-	token := getToken(c)
+// prepareExport updates the export repository of the authenticated user, and returns the id of its view.
+func (h *Server) prepareExport(c *gin.Context) (string, bool) {
+	return h.getExport(c, h.exportService.Prepare)
+}
+
+// ensureExport makes sure that the export repository of the authenticated user exists, without updating it, and
+// returns the id of its view.
+func (h *Server) ensureExport(c *gin.Context) (string, bool) {
+	return h.getExport(c, h.exportService.Ensure)
+}
+
+func (h *Server) getExport(c *gin.Context, fn func(context.Context, *codebases.Codebase, users.ID) (string, error)) (string, bool) {
+	codebaseID := codebases.ID(c.Param("codebaseId"))
+
+	cb, err := h.codebaseService.GetByID(c.Request.Context(), codebaseID)
+	if err != nil {
+		h.logger.Error("failed to get codebase", zap.Error(err))
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return "", false
+	}
+
+	viewID, err := fn(c.Request.Context(), cb, getUserID(c))
+	if err != nil {
+		h.logger.Error("failed to prepare export", zap.Error(err), zap.Stringer("codebase_id", codebaseID))
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return "", false
+	}
+
+	return viewID, true
+}
+
+func (h *Server) handleUserGitUploadPack(c *gin.Context) {
+	codebaseID := codebases.ID(c.Param("codebaseId"))
+
+	// The export is not updated, the refs that the client wants are the ones that were advertised to it by
+	// handleInfoRefs. It is only prepared if it does not exist, for example if the client never requested the refs.
+	viewID, ok := h.ensureExport(c)
+	if !ok {
+		return
+	}
 
 	c.Writer.Header().Set("Content-Type", "application/x-git-upload-pack-result")
 
@@ -270,8 +494,8 @@ func (h *Server) handleGitUploadPack(c *gin.Context) {
 			return fmt.Errorf("failed to copy stdout: %w", err)
 		}
 
-		return nil
-	}).ExecView(token.CodebaseID, ciRepo, "gitserverGitUploadPack"); err != nil {
+		return cmd.Wait()
+	}).ExecView(codebaseID, viewID, "gitserverUserGitUploadPack"); err != nil {
 		h.logger.Error("failed to handle git upload pack", zap.Error(err))
 		c.AbortWithError(http.StatusInternalServerError, err)
 		return
@@ -282,6 +506,16 @@ func (h *Server) handleInfoRefs(c *gin.Context) {
 	// todo: what is this?
 	serviceName := getServiceName(c.Request)
 
+	// Users fetch from their own export of the codebase, imports push to trunk
+	var exportViewID string
+	if getToken(c) == nil && serviceName == "upload-pack" {
+		viewID, ok := h.prepareExport(c)
+		if !ok {
+			return
+		}
+		exportViewID = viewID
+	}
+
 	c.Writer.WriteHeader(200)
 	c.Header("Content-Type", fmt.Sprintf("application/x-git-%s-advertisement", serviceName))
 
@@ -315,6 +549,12 @@ func (h *Server) handleInfoRefs(c *gin.Context) {
 			c.AbortWithError(http.StatusInternalServerError, err)
 			return
 		}
+	} else if exportViewID != "" { // this is user fetch flow
+		if err := executor.ExecView(codebases.ID(c.Param("codebaseId")), exportViewID, "gitserverInfoRefs"); err != nil {
+			h.logger.Error("failed to handle info refs", zap.Error(err))
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
 	} else { // this is import flow
 		if err := executor.ExecTrunk(codebases.ID(c.Param("codebaseId")), "gitserverInfoRefs"); err != nil {
 			h.logger.Error("failed to handle info refs", zap.Error(err))
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"time"
 
+	service_accesstokens "getsturdy.com/api/pkg/accesstokens/service"
 	"getsturdy.com/api/pkg/auth"
 	"getsturdy.com/api/pkg/ctxlog"
 	"getsturdy.com/api/pkg/graphql/dataloader"
@@ -30,6 +31,7 @@ import (
 )
 
 type RootResolver struct {
+	resolvers.AccessTokensRootResolver
 	resolvers.ACLRootResolver
 	resolvers.ActivityRootResolver
 	resolvers.AuthorRootResolver
@@ -62,16 +64,26 @@ type RootResolver struct {
 	resolvers.WorkspaceWatcherRootResolver
 	resolvers.LandRootResovler
 	resolvers.SnapshotsRootResolver
-
-	schema     *graphql.Schema
-	jwtService *service_jwt.Service
-	logger     *zap.Logger
+	resolvers.MergeQueueRootResolver
+	resolvers.WebhookInstantIntegrationRootResolver
+	resolvers.WebhooksRootResolver
+	resolvers.LandProtectionRootResolver
+	resolvers.OrganizationSSOSettingsRootResolver
+	resolvers.GarbageCollectionRootResolver
+	resolvers.ChatIntegrationRootResolver
+
+	schema              *graphql.Schema
+	jwtService          *service_jwt.Service
+	accessTokensService *service_accesstokens.Service
+	logger              *zap.Logger
 }
 
 func NewRootResolver(
 	logger *zap.Logger,
 	jwtService *service_jwt.Service,
+	accessTokensService *service_accesstokens.Service,
 
+	accessTokensRootResolver resolvers.AccessTokensRootResolver,
 	aclRootResolver resolvers.ACLRootResolver,
 	activityRootResolver resolvers.ActivityRootResolver,
 	authorRootResolver resolvers.AuthorRootResolver,
@@ -104,11 +116,20 @@ func NewRootResolver(
 	workspaceWatcherRootResolver resolvers.WorkspaceWatcherRootResolver,
 	landRootResolver resolvers.LandRootResovler,
 	snapshotsRootResolver resolvers.SnapshotsRootResolver,
+	mergeQueueRootResolver resolvers.MergeQueueRootResolver,
+	webhookRootResolver resolvers.WebhookInstantIntegrationRootResolver,
+	webhooksRootResolver resolvers.WebhooksRootResolver,
+	landProtectionRootResolver resolvers.LandProtectionRootResolver,
+	organizationSSOSettingsRootResolver resolvers.OrganizationSSOSettingsRootResolver,
+	garbageCollectionRootResolver resolvers.GarbageCollectionRootResolver,
+	chatIntegrationRootResolver resolvers.ChatIntegrationRootResolver,
 ) *RootResolver {
 	r := &RootResolver{
-		jwtService: jwtService,
-		logger:     logger,
+		jwtService:          jwtService,
+		accessTokensService: accessTokensService,
+		logger:              logger,
 
+		AccessTokensRootResolver:                accessTokensRootResolver,
 		ACLRootResolver:                         aclRootResolver,
 		ActivityRootResolver:                    activityRootResolver,
 		AuthorRootResolver:                      authorRootResolver,
@@ -141,6 +162,13 @@ func NewRootResolver(
 		WorkspaceWatcherRootResolver:            workspaceWatcherRootResolver,
 		LandRootResovler:                        landRootResolver,
 		SnapshotsRootResolver:                   snapshotsRootResolver,
+		MergeQueueRootResolver:                  mergeQueueRootResolver,
+		WebhookInstantIntegrationRootResolver:   webhookRootResolver,
+		WebhooksRootResolver:                    webhooksRootResolver,
+		LandProtectionRootResolver:              landProtectionRootResolver,
+		OrganizationSSOSettingsRootResolver:     organizationSSOSettingsRootResolver,
+		GarbageCollectionRootResolver:           garbageCollectionRootResolver,
+		ChatIntegrationRootResolver:             chatIntegrationRootResolver,
 	}
 
 	logger = logger.Named("graphql")
@@ -181,11 +209,12 @@ func (r *RootResolver) UnauthenticatedHttpHandler(logger *zap.Logger) gin.Handle
 }
 
 type websocketContextBuilder struct {
-	jwtService *service_jwt.Service
+	jwtService          *service_jwt.Service
+	accessTokensService *service_accesstokens.Service
 }
 
 func (c *websocketContextBuilder) BuildContext(ctx context.Context, r *http.Request) (context.Context, error) {
-	subject, err := auth.SubjectFromRequest(r, c.jwtService)
+	subject, err := auth.SubjectFromRequest(r, c.jwtService, c.accessTokensService)
 	if err != nil {
 		return nil, err
 	}
@@ -200,7 +229,8 @@ func (r *RootResolver) WebsocketHandler() gin.HandlerFunc {
 	h := graphqlws.NewHandlerFunc(r.schema, &relay.Handler{
 		Schema: r.schema,
 	}, graphqlws.WithContextGenerator(&websocketContextBuilder{
-		jwtService: r.jwtService,
+		jwtService:          r.jwtService,
+		accessTokensService: r.accessTokensService,
 	}))
 
 	return func(c *gin.Context) {
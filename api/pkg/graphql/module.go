@@ -1,6 +1,8 @@
 package graphql
 
 import (
+	graphql_accesstokens "getsturdy.com/api/pkg/accesstokens/graphql"
+	service_accesstokens "getsturdy.com/api/pkg/accesstokens/service"
 	graphql_activity "getsturdy.com/api/pkg/activity/graphql"
 	graphql_buildkite "getsturdy.com/api/pkg/buildkite/graphql/module"
 	graphql_changes "getsturdy.com/api/pkg/changes/graphql"
@@ -10,23 +12,32 @@ import (
 	graphql_crypto "getsturdy.com/api/pkg/crypto/graphql"
 	"getsturdy.com/api/pkg/di"
 	graphql_features "getsturdy.com/api/pkg/features/graphql"
+	graphql_gc "getsturdy.com/api/pkg/gc/graphql"
 	graphql_github "getsturdy.com/api/pkg/github/graphql"
 	graphql_installations "getsturdy.com/api/pkg/installations/graphql/module"
 	service_jwt "getsturdy.com/api/pkg/jwt/service"
-	graphql_land "getsturdy.com/api/pkg/land/graphql"
+	graphql_land "getsturdy.com/api/pkg/land/graphql/module"
+	graphql_land_protection "getsturdy.com/api/pkg/land/protection/graphql"
 	graphql_licenses "getsturdy.com/api/pkg/licenses/graphql"
 	"getsturdy.com/api/pkg/logger"
+	graphql_mergequeue "getsturdy.com/api/pkg/mergequeue/graphql"
+	graphql_chat "getsturdy.com/api/pkg/notification/chat/graphql"
 	graphql_notification "getsturdy.com/api/pkg/notification/graphql"
 	graphql_onboarding "getsturdy.com/api/pkg/onboarding/graphql"
 	graphql_organizations "getsturdy.com/api/pkg/organization/graphql"
 	graphql_pki "getsturdy.com/api/pkg/pki/graphql"
 	graphql_servicetokens "getsturdy.com/api/pkg/servicetokens/graphql"
 	graphql_snapshots "getsturdy.com/api/pkg/snapshots/graphql"
+	graphql_sso "getsturdy.com/api/pkg/sso/graphql"
+	graphql_webhookci "getsturdy.com/api/pkg/webhookci/graphql"
+	graphql_webhooks "getsturdy.com/api/pkg/webhooks/graphql"
 )
 
 func Module(c *di.Container) {
 	c.Import(logger.Module)
 	c.Import(service_jwt.Module)
+	c.Import(service_accesstokens.Module)
+	c.Import(graphql_accesstokens.Module)
 	c.Import(graphql_acl.Module)
 	c.Import(graphql_activity.Module)
 	c.Import(graphql_buildkite.Module)
@@ -38,12 +49,19 @@ func Module(c *di.Container) {
 	c.Import(graphql_features.Module)
 	c.Import(graphql_licenses.Module)
 	c.Import(graphql_notification.Module)
+	c.Import(graphql_chat.Module)
 	c.Import(graphql_onboarding.Module)
 	c.Import(graphql_organizations.Module)
 	c.Import(graphql_pki.Module)
 	c.Import(graphql_installations.Module)
 	c.Import(graphql_servicetokens.Module)
 	c.Import(graphql_land.Module)
+	c.Import(graphql_land_protection.Module)
+	c.Import(graphql_sso.Module)
+	c.Import(graphql_gc.Module)
 	c.Import(graphql_snapshots.Module)
+	c.Import(graphql_mergequeue.Module)
+	c.Import(graphql_webhookci.Module)
+	c.Import(graphql_webhooks.Module)
 	c.Register(NewRootResolver)
 }
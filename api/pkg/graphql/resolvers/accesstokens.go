@@ -0,0 +1,52 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+type AccessTokensRootResolver interface {
+	// Queries
+	AccessTokens(context.Context) ([]AccessTokenResolver, error)
+
+	// Mutations
+	CreateAccessToken(context.Context, CreateAccessTokenArgs) (AccessTokenResolver, error)
+	RevokeAccessToken(context.Context, RevokeAccessTokenArgs) (AccessTokenResolver, error)
+}
+
+type CreateAccessTokenArgs struct {
+	Input CreateAccessTokenInput
+}
+
+type CreateAccessTokenInput struct {
+	Name      string
+	Scopes    []AccessTokenScope
+	ExpiresAt *int32
+}
+
+type RevokeAccessTokenArgs struct {
+	ID graphql.ID
+}
+
+type AccessTokenResolver interface {
+	ID() graphql.ID
+	Name() string
+	Scopes() ([]AccessTokenScope, error)
+	CreatedAt() int32
+	ExpiresAt() *int32
+	LastUsedAt() *int32
+	RevokedAt() *int32
+
+	Token() *string
+}
+
+type AccessTokenScope string
+
+const (
+	AccessTokenScopeUndefined      AccessTokenScope = ""
+	AccessTokenScopeReadCodebase   AccessTokenScope = "ReadCodebase"
+	AccessTokenScopeWriteWorkspace AccessTokenScope = "WriteWorkspace"
+	AccessTokenScopeLand           AccessTokenScope = "Land"
+	AccessTokenScopeAdmin          AccessTokenScope = "Admin"
+)
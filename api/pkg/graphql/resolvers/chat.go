@@ -0,0 +1,44 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+type ChatIntegrationRootResolver interface {
+	// Queries
+	ChatIntegration(context.Context, ChatIntegrationArgs) (ChatIntegrationResolver, error)
+
+	// Mutations
+	UpdateChatIntegration(context.Context, UpdateChatIntegrationArgs) (ChatIntegrationResolver, error)
+	DeleteChatIntegration(context.Context, DeleteChatIntegrationArgs) (CodebaseResolver, error)
+	SendTestChatMessage(context.Context, SendTestChatMessageArgs) (ChatIntegrationResolver, error)
+}
+
+type ChatIntegrationArgs struct {
+	CodebaseID graphql.ID
+}
+
+type UpdateChatIntegrationArgs struct {
+	Input UpdateChatIntegrationInput
+}
+
+type UpdateChatIntegrationInput struct {
+	CodebaseID graphql.ID
+	URL        string
+}
+
+type DeleteChatIntegrationArgs struct {
+	CodebaseID graphql.ID
+}
+
+type SendTestChatMessageArgs struct {
+	CodebaseID graphql.ID
+}
+
+type ChatIntegrationResolver interface {
+	Codebase(context.Context) (CodebaseResolver, error)
+	URL() string
+	UpdatedAt() int32
+}
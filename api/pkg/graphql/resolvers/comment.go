@@ -19,6 +19,8 @@ type CommentRootResolver interface {
 	UpdateComment(ctx context.Context, args UpdateCommentArgs) (CommentResolver, error)
 	CreateComment(ctx context.Context, args CreateCommentArgs) (CommentResolver, error)
 	ResolveComment(ctx context.Context, args ResolveCommentArgs) (CommentResolver, error)
+	AddCommentReaction(ctx context.Context, args CommentReactionArgs) (CommentResolver, error)
+	RemoveCommentReaction(ctx context.Context, args CommentReactionArgs) (CommentResolver, error)
 
 	// Subscriptions
 	UpdatedComment(ctx context.Context, args UpdatedCommentArgs) (<-chan CommentResolver, error)
@@ -44,6 +46,15 @@ type ResolveCommentArgs struct {
 	ID graphql.ID
 }
 
+type CommentReactionArgs struct {
+	Input CommentReactionInput
+}
+
+type CommentReactionInput struct {
+	CommentID graphql.ID
+	Emoji     string
+}
+
 type UpdateCommentArgs struct {
 	Input UpdateCommentInput
 }
@@ -81,6 +92,7 @@ type CommentResolver interface {
 	DeletedAt() *int32
 	Message() string
 	Codebase(context.Context) (CodebaseResolver, error)
+	Reactions(context.Context) ([]CommentReactionResolver, error)
 }
 
 type TopCommentResolver interface {
@@ -90,6 +102,7 @@ type TopCommentResolver interface {
 	DeletedAt() *int32
 	Message() string
 	Codebase(context.Context) (CodebaseResolver, error)
+	Reactions(context.Context) ([]CommentReactionResolver, error)
 	Workspace(ctx context.Context) (WorkspaceResolver, error)
 	Change(ctx context.Context) (ChangeResolver, error)
 	Replies() ([]ReplyCommentResolver, error)
@@ -105,9 +118,16 @@ type ReplyCommentResolver interface {
 	DeletedAt() *int32
 	Message() string
 	Codebase(context.Context) (CodebaseResolver, error)
+	Reactions(context.Context) ([]CommentReactionResolver, error)
 	Parent(context.Context) (TopCommentResolver, error)
 }
 
+type CommentReactionResolver interface {
+	Emoji() string
+	Count() int32
+	Authors(context.Context) ([]AuthorResolver, error)
+}
+
 type CommentCodeContext interface {
 	ID() graphql.ID
 	Path() string
@@ -0,0 +1,88 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+type GarbageCollectionRootResolver interface {
+	// Queries
+	GarbageCollection(context.Context, GarbageCollectionArgs) (GarbageCollectionResolver, error)
+
+	// Mutations
+	UpdateGarbageCollectionPolicy(context.Context, UpdateGarbageCollectionPolicyArgs) (GarbageCollectionResolver, error)
+	RunGarbageCollection(context.Context, RunGarbageCollectionArgs) (GarbageCollectionResolver, error)
+}
+
+type GarbageCollectionArgs struct {
+	CodebaseID graphql.ID
+}
+
+type UpdateGarbageCollectionPolicyArgs struct {
+	Input UpdateGarbageCollectionPolicyInput
+}
+
+type UpdateGarbageCollectionPolicyInput struct {
+	CodebaseID                       graphql.ID
+	SnapshotRetentionHours           int32
+	ArchivedWorkspaceRetentionHours  int32
+	SuggestionSnapshotRetentionHours *int32
+	CICommitRetentionHours           *int32
+	GitGC                            *bool
+}
+
+type RunGarbageCollectionArgs struct {
+	Input RunGarbageCollectionInput
+}
+
+type RunGarbageCollectionInput struct {
+	CodebaseID graphql.ID
+}
+
+type GarbageCollectionRunsArgs struct {
+	Last *int32
+}
+
+type GarbageCollectionResolver interface {
+	Codebase(context.Context) (CodebaseResolver, error)
+	Policy() GarbageCollectionPolicyResolver
+	Runs(context.Context, GarbageCollectionRunsArgs) ([]GarbageCollectionRunResolver, error)
+	DryRun(context.Context) (GarbageCollectionReportResolver, error)
+}
+
+type GarbageCollectionPolicyResolver interface {
+	SnapshotRetentionHours() int32
+	ArchivedWorkspaceRetentionHours() int32
+	SuggestionSnapshotRetentionHours() *int32
+	CICommitRetentionHours() *int32
+	GitGC() bool
+	UpdatedAt() *int32
+}
+
+type GarbageCollectionRunResolver interface {
+	CompletedAt() int32
+	DurationMillis() int32
+	CollectedSnapshots() int32
+	CollectedCICommits() int32
+	TriggeredBy(context.Context) (AuthorResolver, error)
+}
+
+type GarbageCollectionReportResolver interface {
+	Snapshots() []GarbageCollectionReportedSnapshotResolver
+	CICommits() int32
+}
+
+type GarbageCollectionReason string
+
+const (
+	GarbageCollectionReasonUnused            GarbageCollectionReason = "Unused"
+	GarbageCollectionReasonArchivedWorkspace GarbageCollectionReason = "ArchivedWorkspace"
+	GarbageCollectionReasonSuggestion        GarbageCollectionReason = "Suggestion"
+)
+
+type GarbageCollectionReportedSnapshotResolver interface {
+	SnapshotID() graphql.ID
+	ViewID() graphql.ID
+	Reason() GarbageCollectionReason
+}
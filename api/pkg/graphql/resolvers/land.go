@@ -21,11 +21,25 @@ type LandWorkspaceInput struct {
 	// PatchIDs is deprecated and is not used
 	PatchIDs *[]string
 
+	Mode        *LandingMode
+	SnapshotIDs *[]graphql.ID
+	HunkGroups  *[][]string
+	// Descriptions are the descriptions of the changes, one for each snapshot or hunk group
+	Descriptions *[]string
+
 	// DiffMaxSize is not on the public API
 	// TODO: move this to a more appropriate place
 	DiffMaxSize int
 }
 
+type LandingMode string
+
+const (
+	LandingModeSquash     LandingMode = "Squash"
+	LandingModeSnapshots  LandingMode = "Snapshots"
+	LandingModeHunkGroups LandingMode = "HunkGroups"
+)
+
 type PushWorkspaceArgs struct {
 	Input PushWorkspaceInput
 }
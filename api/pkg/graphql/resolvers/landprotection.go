@@ -0,0 +1,42 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+type LandProtectionRootResolver interface {
+	// Queries
+	LandProtection(context.Context, LandProtectionArgs) (LandProtectionResolver, error)
+
+	// Mutations
+	UpdateLandProtection(context.Context, UpdateLandProtectionArgs) (LandProtectionResolver, error)
+}
+
+type LandProtectionArgs struct {
+	CodebaseID graphql.ID
+}
+
+type UpdateLandProtectionArgs struct {
+	Input UpdateLandProtectionInput
+}
+
+type UpdateLandProtectionInput struct {
+	CodebaseID              graphql.ID
+	MinApprovals            *int32
+	BlockOnRejection        *bool
+	RequireResolvedComments *bool
+	RequireUpToDate         *bool
+	RestrictLanding         *bool
+}
+
+type LandProtectionResolver interface {
+	Codebase(context.Context) (CodebaseResolver, error)
+	MinApprovals() int32
+	BlockOnRejection() bool
+	RequireResolvedComments() bool
+	RequireUpToDate() bool
+	RestrictLanding() bool
+	UpdatedAt() *int32
+}
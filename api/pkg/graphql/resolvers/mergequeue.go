@@ -0,0 +1,64 @@
+package resolvers
+
+import (
+	"context"
+
+	"getsturdy.com/api/pkg/mergequeue"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+type MergeQueueRootResolver interface {
+	// Internal
+	InternalMergeQueueEntry(context.Context, mergequeue.ID) (MergeQueueEntryResolver, error)
+	InternalMergeQueueEntryByWorkspaceID(ctx context.Context, workspaceID string) (MergeQueueEntryResolver, error)
+
+	// Queries
+	MergeQueue(context.Context, MergeQueueArgs) ([]MergeQueueEntryResolver, error)
+
+	// Mutations
+	EnqueueWorkspaceForLanding(context.Context, EnqueueWorkspaceForLandingArgs) (MergeQueueEntryResolver, error)
+	DequeueWorkspaceFromLanding(context.Context, DequeueWorkspaceFromLandingArgs) (MergeQueueEntryResolver, error)
+}
+
+type MergeQueueEntryResolver interface {
+	ID() graphql.ID
+	Workspace(context.Context) (WorkspaceResolver, error)
+	Author(context.Context) (AuthorResolver, error)
+	Status() (MergeQueueEntryStatus, error)
+	EjectReason() *string
+	Change(context.Context) (ChangeResolver, error)
+	CreatedAt() int32
+	CompletedAt() *int32
+}
+
+type MergeQueueEntryStatus string
+
+const (
+	MergeQueueEntryStatusUndefined MergeQueueEntryStatus = ""
+	MergeQueueEntryStatusQueued    MergeQueueEntryStatus = "Queued"
+	MergeQueueEntryStatusTesting   MergeQueueEntryStatus = "Testing"
+	MergeQueueEntryStatusLanded    MergeQueueEntryStatus = "Landed"
+	MergeQueueEntryStatusEjected   MergeQueueEntryStatus = "Ejected"
+	MergeQueueEntryStatusCancelled MergeQueueEntryStatus = "Cancelled"
+)
+
+type MergeQueueArgs struct {
+	CodebaseID graphql.ID
+}
+
+type EnqueueWorkspaceForLandingArgs struct {
+	Input EnqueueWorkspaceForLandingInput
+}
+
+type EnqueueWorkspaceForLandingInput struct {
+	WorkspaceID graphql.ID
+}
+
+type DequeueWorkspaceFromLandingArgs struct {
+	Input DequeueWorkspaceFromLandingInput
+}
+
+type DequeueWorkspaceFromLandingInput struct {
+	WorkspaceID graphql.ID
+}
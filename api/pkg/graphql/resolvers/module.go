@@ -16,4 +16,5 @@ func Module(c *di.Container) {
 	c.Register(func() *ViewRootResolver { return new(ViewRootResolver) })
 	c.Register(func() *UserRootResolver { return new(UserRootResolver) })
 	c.Register(func() *IntegrationRootResolver { return new(IntegrationRootResolver) })
+	c.Register(func() *FileDiffRootResolver { return new(FileDiffRootResolver) })
 }
@@ -36,6 +36,8 @@ type NotificationResolver interface {
 	ToGitHubRepositoryImported() (GitHubRepositoryImportedNotificationResovler, bool)
 	ToInvitedToOrganizationNotification() (InvitedToOrganizationNotificationResolver, bool)
 	ToInvitedToCodebaseNotification() (InvitedToCodebaseNotificationResolver, bool)
+	ToMergeQueueNotification() (MergeQueueNotificationResolver, bool)
+	ToMentionNotification() (MentionNotificationResolver, bool)
 
 	commonNotificationResolver
 }
@@ -45,6 +47,11 @@ type CommentNotificationResolver interface {
 	Comment(ctx context.Context) (CommentResolver, error)
 }
 
+type MentionNotificationResolver interface {
+	commonNotificationResolver
+	Comment(ctx context.Context) (CommentResolver, error)
+}
+
 type RequestedReviewNotificationResolver interface {
 	commonNotificationResolver
 	Review(ctx context.Context) (ReviewResolver, error)
@@ -75,6 +82,11 @@ type InvitedToOrganizationNotificationResolver interface {
 	Organization(context.Context) (OrganizationResolver, error)
 }
 
+type MergeQueueNotificationResolver interface {
+	commonNotificationResolver
+	Entry(context.Context) (MergeQueueEntryResolver, error)
+}
+
 type ArchiveNotificationsArgs struct {
 	Input ArchiveNotificationsInput
 }
@@ -104,6 +116,10 @@ const (
 	NotificationGitHubRepositoryImported  NotificationType = "GitHubRepositoryImported"
 	NotificationTypeInvitedToCodebase     NotificationType = "InvitedToCodebase"
 	NotificationTypeInvitedToOrganization NotificationType = "InvitedToOrganization"
+	NotificationTypeMergeQueue            NotificationType = "MergeQueue"
+	NotificationTypeMention               NotificationType = "Mention"
+	NotificationTypeChangeLanded          NotificationType = "ChangeLanded"
+	NotificationTypeStatusFailed          NotificationType = "StatusFailed"
 )
 
 type NotificationChannel string
@@ -112,6 +128,7 @@ const (
 	NotificationChannelUndefined NotificationChannel = ""
 	NotificationChannelWeb       NotificationChannel = "Web"
 	NotificationChannelEmail     NotificationChannel = "Email"
+	NotificationChannelChat      NotificationChannel = "Chat"
 )
 
 type NotificationPreferenceResolver interface {
@@ -15,6 +15,7 @@ type OrganizationRootResolver interface {
 	UpdateOrganization(context.Context, UpdateOrganizationArgs) (OrganizationResolver, error)
 	AddUserToOrganization(context.Context, AddUserToOrganizationArgs) (OrganizationResolver, error)
 	RemoveUserFromOrganization(context.Context, RemoveUserFromOrganizationArgs) (OrganizationResolver, error)
+	UpdateOrganizationMemberRole(context.Context, UpdateOrganizationMemberRoleArgs) (OrganizationResolver, error)
 
 	// Subscription
 	UpdatedOrganization(context.Context, UpdatedOrganizationArgs) (<-chan OrganizationResolver, error)
@@ -25,13 +26,30 @@ type OrganizationResolver interface {
 	ShortID() graphql.ID
 	Name() string
 	Members(context.Context) ([]AuthorResolver, error)
+	Memberships(context.Context) ([]OrganizationMemberResolver, error)
 	Codebases(context.Context) ([]CodebaseResolver, error)
 
 	Licenses(context.Context) ([]LicenseResolver, error)
 
 	Writeable(context.Context) bool
+	ViewerRole(context.Context) (*OrganizationRole, error)
 }
 
+type OrganizationMemberResolver interface {
+	User(context.Context) (AuthorResolver, error)
+	Role() OrganizationRole
+}
+
+type OrganizationRole string
+
+const (
+	OrganizationRoleOwner   OrganizationRole = "Owner"
+	OrganizationRoleAdmin   OrganizationRole = "Admin"
+	OrganizationRoleMember  OrganizationRole = "Member"
+	OrganizationRoleBilling OrganizationRole = "Billing"
+	OrganizationRoleGuest   OrganizationRole = "Guest"
+)
+
 type CreateOrganizationArgs struct {
 	Input CreateOrganizationInput
 }
@@ -47,6 +65,7 @@ type AddUserToOrganizationArgs struct {
 type AddUserToOrganizationInput struct {
 	OrganizationID graphql.ID
 	Email          string
+	Role           *OrganizationRole
 }
 
 type UpdateOrganizationArgs struct {
@@ -75,3 +94,13 @@ type RemoveUserFromOrganizationInput struct {
 	OrganizationID graphql.ID
 	UserID         graphql.ID
 }
+
+type UpdateOrganizationMemberRoleArgs struct {
+	Input UpdateOrganizationMemberRoleInput
+}
+
+type UpdateOrganizationMemberRoleInput struct {
+	OrganizationID graphql.ID
+	UserID         graphql.ID
+	Role           OrganizationRole
+}
@@ -29,6 +29,7 @@ type ReviewResolver interface {
 	IsReplaced() bool
 	Workspace(context.Context) (WorkspaceResolver, error)
 	RequestedBy(context.Context) (AuthorResolver, error)
+	Interdiff(context.Context) ([]FileDiffResolver, error)
 }
 
 type CreateReviewArgs struct {
@@ -10,6 +10,8 @@ import (
 
 type SnapshotsRootResolver interface {
 	InternalByID(context.Context, snapshots.ID) (SnapshotResolver, error)
+	InternalSnapshot(*snapshots.Snapshot) SnapshotResolver
+	InternalSnapshots([]*snapshots.Snapshot) []SnapshotResolver
 }
 
 type SnapshotResolver interface {
@@ -18,4 +20,20 @@ type SnapshotResolver interface {
 	Next(context.Context) (SnapshotResolver, error)
 	CreatedAt() int32
 	Description(context.Context) (*string, error)
+	DiffStats(context.Context) (DiffStatsResolver, error)
+	Diffs(context.Context, SnapshotDiffsArgs) ([]FileDiffResolver, error)
+}
+
+type SnapshotDiffsArgs struct {
+	Input *SnapshotDiffsInput
+}
+
+type SnapshotDiffsInput struct {
+	Against *graphql.ID
+}
+
+type DiffStatsResolver interface {
+	FilesChanged() int32
+	Additions() int32
+	Deletions() int32
 }
@@ -0,0 +1,38 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+type OrganizationSSOSettingsRootResolver interface {
+	// Queries
+	OrganizationSSOSettings(context.Context, OrganizationSSOSettingsArgs) (OrganizationSSOSettingsResolver, error)
+
+	// Mutations
+	UpdateOrganizationSSOSettings(context.Context, UpdateOrganizationSSOSettingsArgs) (OrganizationSSOSettingsResolver, error)
+}
+
+type OrganizationSSOSettingsArgs struct {
+	OrganizationID graphql.ID
+}
+
+type UpdateOrganizationSSOSettingsArgs struct {
+	Input UpdateOrganizationSSOSettingsInput
+}
+
+type UpdateOrganizationSSOSettingsInput struct {
+	OrganizationID graphql.ID
+	EnforceSSO     *bool
+	JITMembership  *bool
+	EmailDomain    *string
+}
+
+type OrganizationSSOSettingsResolver interface {
+	Organization(context.Context) (OrganizationResolver, error)
+	EnforceSSO() bool
+	JITMembership() bool
+	EmailDomain() *string
+	UpdatedAt() *int32
+}
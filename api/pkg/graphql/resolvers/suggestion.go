@@ -15,6 +15,7 @@ type SuggestionRootResolver interface {
 
 	// Mutations
 	CreateSuggestion(context.Context, CreateSuggestionArgs) (SuggestionResolver, error)
+	CreateSuggestionForLines(context.Context, CreateSuggestionForLinesArgs) (SuggestionResolver, error)
 	DismissSuggestion(context.Context, DismissSuggestionArgs) (SuggestionResolver, error)
 	ApplySuggestionHunks(context.Context, ApplySuggestionHunksArgs) (SuggestionResolver, error)
 	DismissSuggestionHunks(context.Context, DismissSuggestionHunksArgs) (SuggestionResolver, error)
@@ -49,6 +50,18 @@ type CreateSuggestionInput struct {
 	WorkspaceID graphql.ID
 }
 
+type CreateSuggestionForLinesArgs struct {
+	Input CreateSuggestionForLinesInput
+}
+
+type CreateSuggestionForLinesInput struct {
+	WorkspaceID graphql.ID
+	Path        string
+	StartLine   int32
+	EndLine     int32
+	Replacement string
+}
+
 type UpdatedSuggestionArgs struct {
 	WorkspaceID graphql.ID
 }
@@ -0,0 +1,90 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+type WebhooksRootResolver interface {
+	// Queries
+	Webhooks(context.Context, WebhooksArgs) ([]WebhookResolver, error)
+
+	// Mutations
+	CreateWebhook(context.Context, CreateWebhookArgs) (WebhookResolver, error)
+	UpdateWebhook(context.Context, UpdateWebhookArgs) (WebhookResolver, error)
+	DeleteWebhook(context.Context, DeleteWebhookArgs) (WebhookResolver, error)
+	RedeliverWebhookDelivery(context.Context, RedeliverWebhookDeliveryArgs) (WebhookDeliveryResolver, error)
+}
+
+type WebhooksArgs struct {
+	CodebaseID graphql.ID
+}
+
+type CreateWebhookArgs struct {
+	Input CreateWebhookInput
+}
+
+type CreateWebhookInput struct {
+	CodebaseID graphql.ID
+	URL        string
+	Secret     string
+	Events     []WebhookEvent
+}
+
+type UpdateWebhookArgs struct {
+	Input UpdateWebhookInput
+}
+
+type UpdateWebhookInput struct {
+	ID     graphql.ID
+	URL    string
+	Secret *string
+	Events []WebhookEvent
+}
+
+type DeleteWebhookArgs struct {
+	ID graphql.ID
+}
+
+type RedeliverWebhookDeliveryArgs struct {
+	ID graphql.ID
+}
+
+type WebhookDeliveriesArgs struct {
+	Last *int32
+}
+
+type WebhookResolver interface {
+	ID() graphql.ID
+	Codebase(context.Context) (CodebaseResolver, error)
+	URL() string
+	Events() ([]WebhookEvent, error)
+	CreatedAt() int32
+	UpdatedAt() *int32
+	Deliveries(context.Context, WebhookDeliveriesArgs) ([]WebhookDeliveryResolver, error)
+}
+
+type WebhookDeliveryResolver interface {
+	ID() graphql.ID
+	EventID() graphql.ID
+	Event() (WebhookEvent, error)
+	Payload() string
+	Attempts() int32
+	StatusCode() *int32
+	Error() *string
+	CreatedAt() int32
+	LastAttemptAt() *int32
+	DeliveredAt() *int32
+}
+
+type WebhookEvent string
+
+const (
+	WebhookEventUndefined        WebhookEvent = ""
+	WebhookEventChangeLanded     WebhookEvent = "ChangeLanded"
+	WebhookEventWorkspaceCreated WebhookEvent = "WorkspaceCreated"
+	WebhookEventReviewSubmitted  WebhookEvent = "ReviewSubmitted"
+	WebhookEventCommentCreated   WebhookEvent = "CommentCreated"
+	WebhookEventStatusUpdated    WebhookEvent = "StatusUpdated"
+)
@@ -58,6 +58,7 @@ type CreateWorkspaceInput struct {
 	CodebaseID              graphql.ID
 	OnTopOfChange           *graphql.ID
 	OnTopOfChangeWithRevert *graphql.ID
+	OnTopOfWorkspace        *graphql.ID
 }
 
 type RemovePatchesArgs struct {
@@ -82,6 +83,24 @@ type SetWorkspaceSnapshotInput struct {
 	SnapshotID  graphql.ID
 }
 
+type RestoreWorkspaceSnapshotArgs struct {
+	Input RestoreWorkspaceSnapshotInput
+}
+
+type RestoreWorkspaceSnapshotInput struct {
+	WorkspaceID graphql.ID
+	SnapshotID  graphql.ID
+}
+
+type WorkspaceSnapshotsArgs struct {
+	Input *WorkspaceSnapshotsInput
+}
+
+type WorkspaceSnapshotsInput struct {
+	Before *graphql.ID
+	Limit  *int32
+}
+
 type WorkspaceRootResolver interface {
 	// internal
 	InternalWorkspace(*workspaces.Workspace) WorkspaceResolver
@@ -97,6 +116,7 @@ type WorkspaceRootResolver interface {
 	ExtractWorkspace(ctx context.Context, args ExtractWorkspaceArgs) (WorkspaceResolver, error)
 	RemovePatches(context.Context, RemovePatchesArgs) (WorkspaceResolver, error)
 	SetWorkspaceSnapshot(context.Context, SetWorkspaceSnapshotArgs) (WorkspaceResolver, error)
+	RestoreWorkspaceSnapshot(context.Context, RestoreWorkspaceSnapshotArgs) (WorkspaceResolver, error)
 
 	// Subscriptions
 	UpdatedWorkspace(ctx context.Context, args UpdatedWorkspaceArgs) (<-chan WorkspaceResolver, error)
@@ -137,6 +157,10 @@ type WorkspaceResolver interface {
 	DownloadTarGz(context.Context, DownloadArchiveArgs) (ContentsDownloadUrlResolver, error)
 	DownloadZip(context.Context, DownloadArchiveArgs) (ContentsDownloadUrlResolver, error)
 	Snapshot(context.Context) (SnapshotResolver, error)
+	Snapshots(context.Context, WorkspaceSnapshotsArgs) ([]SnapshotResolver, error)
+	BaseWorkspace(context.Context) (WorkspaceResolver, error)
+	StackedWorkspaces(context.Context) ([]WorkspaceResolver, error)
+	MergeQueueEntry(context.Context) (MergeQueueEntryResolver, error)
 }
 
 type DownloadArchiveArgs struct {
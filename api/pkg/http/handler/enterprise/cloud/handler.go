@@ -1,6 +1,7 @@
 package cloud
 
 import (
+	service_accesstokens "getsturdy.com/api/pkg/accesstokens/service"
 	routes_v3_analytics "getsturdy.com/api/pkg/analytics/enterprise/cloud/routes"
 	authz "getsturdy.com/api/pkg/auth"
 	"getsturdy.com/api/pkg/http/handler/enterprise/selfhosted"
@@ -11,6 +12,7 @@ import (
 	service_licenses "getsturdy.com/api/pkg/licenses/enterprise/cloud/service"
 	service_validations "getsturdy.com/api/pkg/licenses/enterprise/cloud/validations/service"
 	routes_v3_logger "getsturdy.com/api/pkg/logger/enterprise/cloud/routes"
+	service_sso "getsturdy.com/api/pkg/sso/service"
 	routes_v3_user "getsturdy.com/api/pkg/users/enterprise/cloud/routes"
 	service_user "getsturdy.com/api/pkg/users/enterprise/cloud/service"
 
@@ -29,10 +31,12 @@ func ProvideHandler(
 	serviceStatistics *service_statistics.Service,
 	sentryClient *sentry.Client,
 	jwtService *service_jwt.Service,
+	accessTokensService *service_accesstokens.Service,
 	userService *service_user.Service,
+	ssoService *service_sso.Service,
 ) *gin.Engine {
 	auth := enterpriseEngine.Group("")
-	auth.Use(authz.GinMiddleware(logger, jwtService))
+	auth.Use(authz.GinMiddleware(logger, jwtService, accessTokensService))
 	auth.POST("/v3/users/verify-email", routes_v3_user.SendEmailVerification(logger, userService)) // Used by the web (2021-11-14)
 
 	publ := enterpriseEngine.Group("")
@@ -41,6 +45,6 @@ func ProvideHandler(
 	publ.POST("/v3/statistics", gin.WrapF(routes_v3_statistics.Create(logger, serviceStatistics)))
 	publ.POST("v3/sentry/store/", gin.WrapF(routes_v3_logger.Store(logger, sentryClient)))
 	publ.POST("/v3/auth/magic-link/send", routes_v3_user.SendMagicLink(logger, userService))
-	publ.POST("/v3/auth/magic-link/verify", routes_v3_user.VerifyMagicLink(logger, userService, jwtService))
+	publ.POST("/v3/auth/magic-link/verify", routes_v3_user.VerifyMagicLink(logger, userService, jwtService, ssoService))
 	return (*gin.Engine)(enterpriseEngine)
 }
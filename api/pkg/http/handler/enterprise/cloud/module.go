@@ -3,6 +3,7 @@ package cloud
 import (
 	"net/http"
 
+	service_accesstokens "getsturdy.com/api/pkg/accesstokens/service"
 	analytics "getsturdy.com/api/pkg/analytics/enterprise/cloud/posthog"
 	"getsturdy.com/api/pkg/di"
 	"getsturdy.com/api/pkg/http/handler/enterprise/selfhosted"
@@ -11,6 +12,7 @@ import (
 	service_licenses "getsturdy.com/api/pkg/licenses/enterprise/cloud/service"
 	service_validations "getsturdy.com/api/pkg/licenses/enterprise/cloud/validations/service"
 	"getsturdy.com/api/pkg/logger"
+	service_sso "getsturdy.com/api/pkg/sso/service"
 	service_user "getsturdy.com/api/pkg/users/service/module"
 )
 
@@ -22,6 +24,8 @@ func Module(c *di.Container) {
 	c.Import(service_validations.Module)
 	c.Import(service_statistics.Module)
 	c.Import(service_jwt.Module)
+	c.Import(service_accesstokens.Module)
 	c.Import(service_user.Module)
+	c.Import(service_sso.Module)
 	c.Register(ProvideHandler, new(http.Handler))
 }
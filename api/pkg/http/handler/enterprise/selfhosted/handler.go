@@ -4,6 +4,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 
+	service_accesstokens "getsturdy.com/api/pkg/accesstokens/service"
 	authz "getsturdy.com/api/pkg/auth"
 	service_buildkite_enterprise "getsturdy.com/api/pkg/buildkite/enterprise/service"
 	service_ci "getsturdy.com/api/pkg/ci/service"
@@ -30,6 +31,7 @@ func ProvideHandler(
 	gitHubAppConfig *config.GitHubAppConfig,
 	statusesService *service_statuses.Service,
 	jwtService *service_jwt.Service,
+	accessTokensService *service_accesstokens.Service,
 	gitHubService *service_github.Service,
 	ciService *service_ci.Service,
 	serviceTokensService *service_servicetokens.Service,
@@ -39,7 +41,7 @@ func ProvideHandler(
 	triggerSyncCodebaseWebhookHandler routes_remote.TriggerSyncCodebaseWebhookHandler,
 ) *Engine {
 	auth := ossEngine.Group("")
-	auth.Use(authz.GinMiddleware(logger, jwtService))
+	auth.Use(authz.GinMiddleware(logger, jwtService, accessTokensService))
 	auth.POST("/v3/github/oauth", routes_v3_ghapp.Oauth(logger, gitHubAppConfig, userRepo, gitHubUserRepo, gitHubService))
 
 	publ := ossEngine.Group("")
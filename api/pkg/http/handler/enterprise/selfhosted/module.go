@@ -1,6 +1,7 @@
 package selfhosted
 
 import (
+	service_accesstokens "getsturdy.com/api/pkg/accesstokens/service"
 	service_buildkite "getsturdy.com/api/pkg/buildkite/enterprise/service"
 	"getsturdy.com/api/pkg/di"
 	db_github "getsturdy.com/api/pkg/github/enterprise/db"
@@ -21,6 +22,7 @@ func Module(c *di.Container) {
 	c.Import(db_github.Module)
 	c.Import(service_statuses.Module)
 	c.Import(service_jwt.Module)
+	c.Import(service_accesstokens.Module)
 	c.Import(service_github.Module)
 	c.Import(service_servicetokens.Module)
 	c.Import(service_buildkite.Module)
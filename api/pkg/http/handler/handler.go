@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	service_accesstokens "getsturdy.com/api/pkg/accesstokens/service"
 	service_analytics "getsturdy.com/api/pkg/analytics/service"
 	authz "getsturdy.com/api/pkg/auth"
 	service_auth "getsturdy.com/api/pkg/auth/service"
@@ -13,6 +14,7 @@ import (
 	service_blobs "getsturdy.com/api/pkg/blobs/service"
 	db_change "getsturdy.com/api/pkg/changes/db"
 	routes_v3_change "getsturdy.com/api/pkg/changes/routes"
+	service_ci "getsturdy.com/api/pkg/ci/service"
 	db_codebases "getsturdy.com/api/pkg/codebases/db"
 	routes_v3_codebase "getsturdy.com/api/pkg/codebases/routes"
 	service_codebase "getsturdy.com/api/pkg/codebases/service"
@@ -34,6 +36,11 @@ import (
 	routes_v3_pki "getsturdy.com/api/pkg/pki/routes"
 	service_presence "getsturdy.com/api/pkg/presence/service"
 	worker_snapshots "getsturdy.com/api/pkg/snapshots/worker"
+	"getsturdy.com/api/pkg/sso/oidc"
+	oidc_configuration "getsturdy.com/api/pkg/sso/oidc/configuration"
+	routes_sso "getsturdy.com/api/pkg/sso/routes"
+	service_sso "getsturdy.com/api/pkg/sso/service"
+	service_statuses "getsturdy.com/api/pkg/statuses/service"
 	service_suggestion "getsturdy.com/api/pkg/suggestions/service"
 	routes_v3_sync "getsturdy.com/api/pkg/sync/routes"
 	service_sync "getsturdy.com/api/pkg/sync/service"
@@ -50,6 +57,8 @@ import (
 	"getsturdy.com/api/pkg/waitinglist"
 	"getsturdy.com/api/pkg/waitinglist/acl"
 	"getsturdy.com/api/pkg/waitinglist/instantintegration"
+	routes_webhookci "getsturdy.com/api/pkg/webhookci/routes"
+	service_webhookci "getsturdy.com/api/pkg/webhookci/service"
 	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
 	routes_v3_workspace "getsturdy.com/api/pkg/workspaces/routes"
 	service_workspace "getsturdy.com/api/pkg/workspaces/service"
@@ -98,6 +107,7 @@ func ProvideHandler(
 	userService service_user.Service,
 	syncService *service_sync.Service,
 	jwtService *service_jwt.Service,
+	accessTokensService *service_accesstokens.Service,
 	codebaseService *service_codebase.Service,
 	authService *service_auth.Service,
 	grapqhlResolver *sturdygrapql.RootResolver,
@@ -105,6 +115,12 @@ func ProvideHandler(
 	uploader uploader.Uploader,
 	viewService *service_view.Service,
 	getFileRoute routes_file.GetFileRoute,
+	ciService *service_ci.Service,
+	statusesService *service_statuses.Service,
+	webhookCIService *service_webhookci.Service,
+	ssoService *service_sso.Service,
+	oidcProvider *oidc.Provider,
+	oidcConfig *oidc_configuration.Configuration,
 ) *Engine {
 	logger = logger.With(zap.String("component", "http"))
 	allowOrigins := []string{
@@ -144,7 +160,7 @@ func ProvideHandler(
 	ginprom := ginprometheus.NewPrometheus("gin", logger)
 	ginprom.ReqCntURLLabelMappingFn = metricsMapper
 	ginprom.Use(r)
-	graphql := r.Group("/graphql", sturdygrapql.CorsMiddleware(allowOrigins), authz.GinMiddleware(logger, jwtService))
+	graphql := r.Group("/graphql", sturdygrapql.CorsMiddleware(allowOrigins), authz.GinMiddleware(logger, jwtService, accessTokensService))
 	graphql.OPTIONS("", func(c *gin.Context) { c.Status(http.StatusOK) })
 	graphql.OPTIONS("ws", func(c *gin.Context) { c.Status(http.StatusOK) })
 	graphql.POST("", grapqhlResolver.HttpHandler())
@@ -154,9 +170,12 @@ func ProvideHandler(
 	publ := r.Group("")
 	// Private endpoints, requires a valid auth cookie
 	auth := r.Group("")
-	auth.Use(authz.GinMiddleware(logger, jwtService))
-	publ.POST("/v3/auth", routes_v3_user.Login(logger, userService, analyticsService, jwtService))
-	publ.POST("/v3/users", routes_v3_user.Signup(logger, userService, jwtService, analyticsService))
+	auth.Use(authz.GinMiddleware(logger, jwtService, accessTokensService))
+	publ.POST("/v3/auth", routes_v3_user.Login(logger, userService, analyticsService, jwtService, ssoService))
+	publ.POST("/v3/users", routes_v3_user.Signup(logger, userService, jwtService, analyticsService, ssoService))
+	publ.GET("/v3/auth/oidc", routes_sso.Get(oidcProvider))
+	publ.GET("/v3/auth/oidc/login", routes_sso.Login(logger, oidcConfig, oidcProvider))
+	publ.GET("/v3/auth/oidc/callback", routes_sso.Callback(logger, oidcConfig, oidcProvider, ssoService, jwtService, analyticsService))
 	publ.POST("/v3/auth/destroy", routes_v3_user.AuthDestroy)
 	auth.POST("/v3/auth/client-token", routes_v3_user.ClientToken(userRepo, jwtService))
 	auth.POST("/v3/auth/renew-token", routes_v3_user.RenewToken(logger, userRepo, jwtService))
@@ -172,6 +191,7 @@ func ProvideHandler(
 	authedViews.GET("", routes_v3_view.Get(viewRepo, workspaceReader, logger, userService))                                              // Used by the command line client
 	authedViews.POST("/ignore-file", routes_v3_change.IgnoreFile(logger, viewRepo, codebaseUserRepo, executorProvider, viewUpdatedFunc)) // Used by the web (2021-10-04)
 	authedViews.GET("/ignores", routes_v3_view.Ignores(logger, executorProvider, viewRepo))                                              // Called from client-side sturdy-cli
+	authedViews.POST("/files", routes_v3_view.WriteFiles(logger, authService, viewRepo, viewService, viewUpdatedFunc))                   // Called from client-side sturdy-cli, when syncing without mutagen
 	rebase := auth.Group("/v3/rebase/")
 	rebase.Use(view_auth.ValidateViewAccessMiddleware(authService, viewRepo))
 	rebase.GET(":viewID", routes_v3_sync.Status(viewRepo, executorProvider, logger))                                     // Used by the web (2021-10-04)
@@ -195,6 +215,8 @@ func ProvideHandler(
 
 	auth.GET("/v3/file", gin.HandlerFunc(getFileRoute))
 
+	publ.POST("/v3/integrations/webhook/:id/statuses", routes_webhookci.StatusesHandler(logger, webhookCIService, ciService, statusesService)) // Called by webhook ci builds
+
 	routes_blobs.Register(publ.Group("/v3/blobs"), logger, blobsService)
 	return (*Engine)(r)
 }
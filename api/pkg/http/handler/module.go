@@ -1,12 +1,14 @@
 package handler
 
 import (
+	service_accesstokens "getsturdy.com/api/pkg/accesstokens/service"
 	sender_activity "getsturdy.com/api/pkg/activity/sender"
 	service_activity "getsturdy.com/api/pkg/activity/service"
 	service_analytics "getsturdy.com/api/pkg/analytics/service"
 	service_auth "getsturdy.com/api/pkg/auth/service"
 	service_blobs "getsturdy.com/api/pkg/blobs/service"
 	db_changes "getsturdy.com/api/pkg/changes/db"
+	service_ci "getsturdy.com/api/pkg/ci/service"
 	workers_ci "getsturdy.com/api/pkg/ci/workers"
 	db_codebases "getsturdy.com/api/pkg/codebases/db"
 	service_codebases "getsturdy.com/api/pkg/codebases/service"
@@ -21,6 +23,9 @@ import (
 	db_pki "getsturdy.com/api/pkg/pki/db"
 	service_presence "getsturdy.com/api/pkg/presence/service"
 	worker_snapshots "getsturdy.com/api/pkg/snapshots/worker"
+	"getsturdy.com/api/pkg/sso/oidc"
+	service_sso "getsturdy.com/api/pkg/sso/service"
+	service_statuses "getsturdy.com/api/pkg/statuses/service"
 	service_sync "getsturdy.com/api/pkg/sync/service"
 	uploader_avatars "getsturdy.com/api/pkg/users/avatars/uploader"
 	db_users "getsturdy.com/api/pkg/users/db"
@@ -29,6 +34,7 @@ import (
 	meta_view "getsturdy.com/api/pkg/views/meta"
 	service_view "getsturdy.com/api/pkg/views/service"
 	db_waitinglist "getsturdy.com/api/pkg/waitinglist"
+	service_webhookci "getsturdy.com/api/pkg/webhookci/service"
 	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
 	service_workspaces "getsturdy.com/api/pkg/workspaces/service"
 )
@@ -51,6 +57,9 @@ func Module(c *di.Container) {
 	c.Import(service_users.Module)
 	c.Import(sender_activity.Module)
 	c.Import(workers_ci.Module)
+	c.Import(service_ci.Module)
+	c.Import(service_statuses.Module)
+	c.Import(service_webhookci.Module)
 	c.Import(service_notifications.Module)
 	c.Import(service_workspaces.Module)
 	c.Import(meta_view.Module)
@@ -59,9 +68,12 @@ func Module(c *di.Container) {
 	c.Import(service_sync.Module)
 	c.Import(service_codebases.Module)
 	c.Import(service_auth.Module)
+	c.Import(service_accesstokens.Module)
 	c.Import(service_blobs.Module)
 	c.Import(uploader_avatars.Module)
 	c.Import(routes_file.Module)
+	c.Import(service_sso.Module)
+	c.Import(oidc.Module)
 	c.Import(graphql.Module)
 
 	c.Register(ProvideHandler)
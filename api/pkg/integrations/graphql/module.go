@@ -8,6 +8,7 @@ import (
 	"getsturdy.com/api/pkg/di"
 	"getsturdy.com/api/pkg/graphql/resolvers"
 	graphql_statuses "getsturdy.com/api/pkg/statuses/graphql/module"
+	graphql_webhookci "getsturdy.com/api/pkg/webhookci/graphql"
 	service_workspaces "getsturdy.com/api/pkg/workspaces/service"
 )
 
@@ -18,6 +19,7 @@ func Module(c *di.Container) {
 	c.Import(service_workspaces.Module)
 	c.Import(graphql_statuses.Module)
 	c.Import(graphql_buildkite.Module)
+	c.Import(graphql_webhookci.Module)
 	c.Register(NewRootResolver)
 
 	// populate cyclic resolver
@@ -49,6 +49,8 @@ func (ir *instantIntegrationProvider) Provider() (resolvers.InstantIntegrationPr
 	switch ir.integration.Provider {
 	case providers.ProviderNameBuildkite:
 		return resolvers.InstantIntegrationProviderBuildkite, nil
+	case providers.ProviderNameWebhook:
+		return resolvers.InstantIntegrationProviderWebhook, nil
 	default:
 		return resolvers.InstantIntegrationProviderUndefined, fmt.Errorf("invalid provider: %s", ir.integration.Provider)
 	}
@@ -68,3 +70,18 @@ type buildkiteProviderResolver struct {
 func (br *buildkiteProviderResolver) Configuration(ctx context.Context) (resolvers.BuildkiteConfigurationResolver, error) {
 	return br.root.buildkiteRootResolver.InternalBuildkiteConfigurationByIntegrationID(ctx, br.integration.ID)
 }
+
+func (ir *instantIntegrationProvider) ToWebhookIntegration() (resolvers.WebhookIntegration, bool) {
+	if ir.integration.Provider != providers.ProviderNameWebhook {
+		return nil, false
+	}
+	return &webhookProviderResolver{ir}, true
+}
+
+type webhookProviderResolver struct {
+	*instantIntegrationProvider
+}
+
+func (wr *webhookProviderResolver) Configuration(ctx context.Context) (resolvers.WebhookConfigurationResolver, error) {
+	return wr.root.webhookRootResolver.InternalWebhookConfigurationByIntegrationID(ctx, wr.integration.ID)
+}
@@ -23,6 +23,7 @@ type rootResolver struct {
 	workspaceService *service_workspaces.Service
 
 	buildkiteRootResolver resolvers.BuildkiteInstantIntegrationRootResolver
+	webhookRootResolver   resolvers.WebhookInstantIntegrationRootResolver
 	statusesRootResolver  resolvers.StatusesRootResolver
 }
 
@@ -33,6 +34,7 @@ func NewRootResolver(
 	workspaceService *service_workspaces.Service,
 
 	buildkiteRootResolver resolvers.BuildkiteInstantIntegrationRootResolver,
+	webhookRootResolver resolvers.WebhookInstantIntegrationRootResolver,
 	statusesRootResolver resolvers.StatusesRootResolver,
 ) resolvers.IntegrationRootResolver {
 	return &rootResolver{
@@ -42,6 +44,7 @@ func NewRootResolver(
 		workspaceService: workspaceService,
 
 		buildkiteRootResolver: buildkiteRootResolver,
+		webhookRootResolver:   webhookRootResolver,
 		statusesRootResolver:  statusesRootResolver,
 	}
 }
@@ -144,6 +147,8 @@ func convertProvider(in resolvers.InstantIntegrationProviderType) (providers.Pro
 	switch in {
 	case resolvers.InstantIntegrationProviderBuildkite:
 		return providers.ProviderNameBuildkite, nil
+	case resolvers.InstantIntegrationProviderWebhook:
+		return providers.ProviderNameWebhook, nil
 	default:
 		return providers.ProviderNameUndefined, fmt.Errorf("invalid provider: %s", in)
 	}
@@ -13,4 +13,5 @@ const (
 	ProviderNameUndefined ProviderName = ""
 	ProviderNameBuildkite ProviderName = "buildkite"
 	ProviderNameGithub    ProviderName = "github"
+	ProviderNameWebhook   ProviderName = "webhook"
 )
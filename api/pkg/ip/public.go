@@ -0,0 +1,79 @@
+package ip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// ErrNotAllowed is returned when connecting to an address that is not allowed by a Filter.
+var ErrNotAllowed = errors.New("address is not allowed")
+
+// Filter decides which addresses outgoing requests to user provided urls, such as webhooks, can connect to.
+type Filter func(net.IP) bool
+
+// Public only allows addresses on the public internet, so that user provided urls can not be used to reach the
+// internal network of the installation.
+var Public Filter = func(ip net.IP) bool {
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified()
+}
+
+// CheckHost returns ErrNotAllowed if the host is, or resolves to, an address that is not allowed. Hosts that can
+// not be resolved are not rejected, the addresses are checked again when connecting.
+func (f Filter) CheckHost(ctx context.Context, host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		if !f(ip) {
+			return fmt.Errorf("%s: %w", host, ErrNotAllowed)
+		}
+		return nil
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil
+	}
+	for _, addr := range addrs {
+		if !f(addr.IP) {
+			return fmt.Errorf("%s: %w", host, ErrNotAllowed)
+		}
+	}
+	return nil
+}
+
+// Client returns an http client that refuses to connect to addresses that are not allowed. The addresses are
+// checked after they have been resolved, so changing the dns records of a host after it has been validated, or
+// redirecting to another host, does not get around the filter.
+func (f Filter) Client(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !f(ip) {
+				return fmt.Errorf("%s: %w", host, ErrNotAllowed)
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// a proxy would connect to the target on our behalf, without the filter
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 type jwtClaims struct {
 	Type jwt.TokenType `json:"type,omitempty"`
+	SSO  bool          `json:"sso,omitempty"`
 }
 
 type deprecatedClaims struct {
@@ -95,7 +95,16 @@ func (s *Service) init(ctx context.Context) error {
 	return nil
 }
 
-func (s *Service) IssueToken(ctx context.Context, subject string, validFor time.Duration, tokenType jwt.TokenType) (*jwt.Token, error) {
+type IssueOption func(*jwtClaims)
+
+// WithSSO marks the token as issued to a user that authenticated with single sign-on.
+func WithSSO(sso bool) IssueOption {
+	return func(claims *jwtClaims) {
+		claims.SSO = sso
+	}
+}
+
+func (s *Service) IssueToken(ctx context.Context, subject string, validFor time.Duration, tokenType jwt.TokenType, opts ...IssueOption) (*jwt.Token, error) {
 	if err := s.initOnce(ctx); err != nil {
 		return nil, err
 	}
@@ -111,6 +120,9 @@ func (s *Service) IssueToken(ctx context.Context, subject string, validFor time.
 	sturdyClaims := jwtClaims{
 		Type: tokenType,
 	}
+	for _, opt := range opts {
+		opt(&sturdyClaims)
+	}
 
 	token, err := jose_jwt.Signed(s.signer).
 		Claims(stdClaims).
@@ -124,6 +136,7 @@ func (s *Service) IssueToken(ctx context.Context, subject string, validFor time.
 		Subject:   stdClaims.Subject,
 		ExpiresAt: stdClaims.Expiry.Time(),
 		Type:      sturdyClaims.Type,
+		SSO:       sturdyClaims.SSO,
 	}, nil
 }
 
@@ -208,6 +221,7 @@ func (s *Service) verify(ctx context.Context, rawToken string, jwtoken *jose_jwt
 			Subject:   stdClaims.Subject,
 			ExpiresAt: stdClaims.Expiry.Time(),
 			Type:      sturdyClaims.Type,
+			SSO:       sturdyClaims.SSO,
 		}, nil
 	case errors.Is(validateErr, jose_jwt.ErrExpired):
 		return nil, ErrTokenExpired
@@ -24,3 +24,15 @@ func TestVerify_shouldVerifyIssuedKey(t *testing.T) {
 		assert.Equal(t, token, verifiedToken)
 	}
 }
+
+func TestVerify_sso(t *testing.T) {
+	svc := service.NewService(zap.NewNop(), db_keys.NewInMemory())
+
+	token, err := svc.IssueToken(context.Background(), "user-id", time.Hour, jwt.TokenTypeAuth, service.WithSSO(true))
+	assert.NoError(t, err)
+
+	verifiedToken, err := svc.Verify(context.Background(), token.Token, jwt.TokenTypeAuth)
+	if assert.NoError(t, err) {
+		assert.True(t, verifiedToken.SSO)
+	}
+}
@@ -19,4 +19,6 @@ type Token struct {
 	Type      TokenType
 	Subject   string
 	ExpiresAt time.Time
+	// SSO is true if the user authenticated with single sign-on.
+	SSO bool
 }
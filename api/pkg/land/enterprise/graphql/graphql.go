@@ -7,9 +7,11 @@ import (
 
 	"getsturdy.com/api/pkg/auth"
 	services_auth "getsturdy.com/api/pkg/auth/service"
+	service_changes "getsturdy.com/api/pkg/changes/service"
 	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
 	"getsturdy.com/api/pkg/graphql/resolvers"
 	service_land_enterprise "getsturdy.com/api/pkg/land/enterprise/service"
+	graphql_land "getsturdy.com/api/pkg/land/graphql"
 	service_land_oss "getsturdy.com/api/pkg/land/service"
 	service_users "getsturdy.com/api/pkg/users/service"
 	service_workspaces "getsturdy.com/api/pkg/workspaces/service"
@@ -47,7 +49,7 @@ func (r *LandRootResolver) LandWorkspaceChange(ctx context.Context, args resolve
 		return nil, gqlerrors.Error(fmt.Errorf("failed to get workspace: %w", err))
 	}
 
-	if err := r.authService.CanWrite(ctx, ws); err != nil {
+	if err := r.authService.CanLand(ctx, ws); err != nil {
 		return nil, gqlerrors.Error(err)
 	}
 
@@ -56,10 +58,31 @@ func (r *LandRootResolver) LandWorkspaceChange(ctx context.Context, args resolve
 		diffOpts = append(diffOpts, vcs.WithGitMaxSize(args.Input.DiffMaxSize))
 	}
 
-	_, err = r.landService.LandChange(ctx, ws, diffOpts...)
+	series, err := graphql_land.SeriesFromInput(args.Input)
+	if err != nil {
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", err.Error(), "reason", "InvalidSeries")
+	}
+
+	_, err = r.landService.LandChangeSeries(ctx, ws, series, diffOpts...)
 	switch {
 	case errors.Is(err, service_land_oss.ErrNotAllowedUnhealthyWorkspace):
-		return nil, gqlerrors.Error(fmt.Errorf("failed to land change: %w", err), "message", "This draft has unhealthy statuses and cannot be merged")
+		return nil, gqlerrors.Error(fmt.Errorf("failed to land change: %w", err), "message", "This draft has unhealthy statuses and cannot be merged", "reason", "UnhealthyWorkspace")
+	case errors.Is(err, service_land_oss.ErrNotAllowedStackedWorkspace):
+		return nil, gqlerrors.Error(fmt.Errorf("failed to land change: %w", err), "message", "This draft is based on another draft that has not been merged yet", "reason", "StackedWorkspace")
+	case errors.Is(err, service_land_oss.ErrNotAllowedMissingApproval):
+		return nil, gqlerrors.Error(fmt.Errorf("failed to land change: %w", err), "message", "This draft changes owned files and must be approved by their owners before it can be merged", "reason", "MissingOwnerApproval")
+	case errors.Is(err, service_land_oss.ErrNotAllowedTooFewApprovals):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "This draft does not have enough approving reviews", "reason", "TooFewApprovals")
+	case errors.Is(err, service_land_oss.ErrNotAllowedRejected):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "This draft has been rejected", "reason", "Rejected")
+	case errors.Is(err, service_land_oss.ErrNotAllowedUnresolvedComments):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "This draft has unresolved comments", "reason", "UnresolvedComments")
+	case errors.Is(err, service_land_oss.ErrNotAllowedOutdatedWorkspace):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "This draft is not up to date with the trunk", "reason", "OutdatedWorkspace")
+	case errors.Is(err, service_land_oss.ErrNotAllowedRestrictedLanding):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "You are not allowed to merge drafts in this codebase", "reason", "RestrictedLanding")
+	case errors.Is(err, service_land_oss.ErrInvalidSeries), errors.Is(err, service_changes.ErrSnapshotNotOnBase):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", err.Error(), "reason", "InvalidSeries")
 	case err != nil:
 		return nil, gqlerrors.Error(fmt.Errorf("failed to land change: %w", err))
 	}
@@ -73,7 +96,7 @@ func (r *LandRootResolver) PushWorkspace(ctx context.Context, args resolvers.Pus
 		return nil, gqlerrors.Error(err)
 	}
 
-	if err := r.authService.CanWrite(ctx, ws); err != nil {
+	if err := r.authService.CanLand(ctx, ws); err != nil {
 		return nil, gqlerrors.Error(err)
 	}
 
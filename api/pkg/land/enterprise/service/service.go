@@ -36,6 +36,14 @@ func New(
 }
 
 func (s *Service) LandChange(ctx context.Context, ws *workspaces.Workspace, diffOpts ...vcs.DiffOption) (*changes.Change, error) {
+	landed, err := s.LandChangeSeries(ctx, ws, service_land.Series{Mode: service_land.ModeSquash}, diffOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return landed[len(landed)-1], nil
+}
+
+func (s *Service) LandChangeSeries(ctx context.Context, ws *workspaces.Workspace, series service_land.Series, diffOpts ...vcs.DiffOption) ([]*changes.Change, error) {
 	gitHubRepository, err := s.gitHubService.GetRepositoryByCodebaseID(ctx, ws.CodebaseID)
 	switch {
 	case err == nil, errors.Is(err, sql.ErrNoRows):
@@ -47,7 +55,7 @@ func (s *Service) LandChange(ctx context.Context, ws *workspaces.Workspace, diff
 		return nil, fmt.Errorf("landing disallowed when a github integration exists for codebase (github is source of truth)")
 	}
 
-	change, err := s.oss.LandChange(ctx, ws, diffOpts...)
+	landed, err := s.oss.LandChangeSeries(ctx, ws, series, diffOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -57,10 +65,10 @@ func (s *Service) LandChange(ctx context.Context, ws *workspaces.Workspace, diff
 		if err := s.gitHubService.Push(ctx, gitHubRepository, ws.CodebaseID); err != nil {
 			return nil, fmt.Errorf("failed to push to github: %w", err)
 		}
-		return change, nil
+		return landed, nil
 	}
 
-	return change, nil
+	return landed, nil
 }
 
 func (s *Service) Push(ctx context.Context, user *users.User, ws *workspaces.Workspace) error {
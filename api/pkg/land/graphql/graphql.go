@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	services_auth "getsturdy.com/api/pkg/auth/service"
+	service_changes "getsturdy.com/api/pkg/changes/service"
 	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
 	"getsturdy.com/api/pkg/graphql/resolvers"
 	service_land "getsturdy.com/api/pkg/land/service"
@@ -42,7 +43,7 @@ func (r *LandRootResolver) LandWorkspaceChange(ctx context.Context, args resolve
 		return nil, gqlerrors.Error(fmt.Errorf("failed to get workspace: %w", err))
 	}
 
-	if err := r.authService.CanWrite(ctx, ws); err != nil {
+	if err := r.authService.CanLand(ctx, ws); err != nil {
 		return nil, gqlerrors.Error(err)
 	}
 
@@ -51,10 +52,31 @@ func (r *LandRootResolver) LandWorkspaceChange(ctx context.Context, args resolve
 		diffOpts = append(diffOpts, vcs.WithGitMaxSize(args.Input.DiffMaxSize))
 	}
 
-	_, err = r.landService.LandChange(ctx, ws, diffOpts...)
+	series, err := SeriesFromInput(args.Input)
+	if err != nil {
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", err.Error(), "reason", "InvalidSeries")
+	}
+
+	_, err = r.landService.LandChangeSeries(ctx, ws, series, diffOpts...)
 	switch {
 	case errors.Is(err, service_land.ErrNotAllowedUnhealthyWorkspace):
-		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "This draft has unhealthy statuses and cannot be merged")
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "This draft has unhealthy statuses and cannot be merged", "reason", "UnhealthyWorkspace")
+	case errors.Is(err, service_land.ErrNotAllowedStackedWorkspace):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "This draft is based on another draft that has not been merged yet", "reason", "StackedWorkspace")
+	case errors.Is(err, service_land.ErrNotAllowedMissingApproval):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "This draft changes owned files and must be approved by their owners before it can be merged", "reason", "MissingOwnerApproval")
+	case errors.Is(err, service_land.ErrNotAllowedTooFewApprovals):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "This draft does not have enough approving reviews", "reason", "TooFewApprovals")
+	case errors.Is(err, service_land.ErrNotAllowedRejected):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "This draft has been rejected", "reason", "Rejected")
+	case errors.Is(err, service_land.ErrNotAllowedUnresolvedComments):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "This draft has unresolved comments", "reason", "UnresolvedComments")
+	case errors.Is(err, service_land.ErrNotAllowedOutdatedWorkspace):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "This draft is not up to date with the trunk", "reason", "OutdatedWorkspace")
+	case errors.Is(err, service_land.ErrNotAllowedRestrictedLanding):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "You are not allowed to merge drafts in this codebase", "reason", "RestrictedLanding")
+	case errors.Is(err, service_land.ErrInvalidSeries), errors.Is(err, service_changes.ErrSnapshotNotOnBase):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", err.Error(), "reason", "InvalidSeries")
 	case err != nil:
 		return nil, gqlerrors.Error(fmt.Errorf("failed to land change: %w", err))
 	}
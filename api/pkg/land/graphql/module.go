@@ -1,6 +1,3 @@
-//go:build !cloud && !enterprise
-// +build !cloud,!enterprise
-
 package grapqhl
 
 import (
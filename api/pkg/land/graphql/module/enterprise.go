@@ -1,7 +1,7 @@
 //go:build cloud || enterprise
 // +build cloud enterprise
 
-package grapqhl
+package module
 
 import (
 	"getsturdy.com/api/pkg/di"
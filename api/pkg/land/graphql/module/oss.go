@@ -0,0 +1,13 @@
+//go:build !cloud && !enterprise
+// +build !cloud,!enterprise
+
+package module
+
+import (
+	"getsturdy.com/api/pkg/di"
+	graphql_land "getsturdy.com/api/pkg/land/graphql"
+)
+
+func Module(c *di.Container) {
+	c.Import(graphql_land.Module)
+}
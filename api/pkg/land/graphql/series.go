@@ -0,0 +1,43 @@
+package grapqhl
+
+import (
+	"fmt"
+
+	"getsturdy.com/api/pkg/graphql/resolvers"
+	service_land "getsturdy.com/api/pkg/land/service"
+	"getsturdy.com/api/pkg/snapshots"
+)
+
+// SeriesFromInput returns how the workspace should be landed according to the input.
+func SeriesFromInput(input resolvers.LandWorkspaceInput) (service_land.Series, error) {
+	mode := resolvers.LandingModeSquash
+	if input.Mode != nil {
+		mode = *input.Mode
+	}
+
+	var descriptions []string
+	if input.Descriptions != nil {
+		descriptions = *input.Descriptions
+	}
+
+	switch mode {
+	case resolvers.LandingModeSquash:
+		return service_land.Series{Mode: service_land.ModeSquash}, nil
+	case resolvers.LandingModeSnapshots:
+		if input.SnapshotIDs == nil {
+			return service_land.Series{}, fmt.Errorf("%w: snapshotIDs are required", service_land.ErrInvalidSeries)
+		}
+		snapshotIDs := make([]snapshots.ID, 0, len(*input.SnapshotIDs))
+		for _, id := range *input.SnapshotIDs {
+			snapshotIDs = append(snapshotIDs, snapshots.ID(id))
+		}
+		return service_land.Series{Mode: service_land.ModeSnapshots, SnapshotIDs: snapshotIDs, Descriptions: descriptions}, nil
+	case resolvers.LandingModeHunkGroups:
+		if input.HunkGroups == nil {
+			return service_land.Series{}, fmt.Errorf("%w: hunkGroups are required", service_land.ErrInvalidSeries)
+		}
+		return service_land.Series{Mode: service_land.ModeHunkGroups, HunkGroups: *input.HunkGroups, Descriptions: descriptions}, nil
+	default:
+		return service_land.Series{}, fmt.Errorf("%w: unknown mode %q", service_land.ErrInvalidSeries, mode)
+	}
+}
@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/land/protection"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var _ Repository = &database{}
+
+type database struct {
+	db *sqlx.DB
+}
+
+func NewDatabase(db *sqlx.DB) Repository {
+	return &database{db: db}
+}
+
+func (d *database) Get(ctx context.Context, codebaseID codebases.ID) (*protection.Protection, error) {
+	var p protection.Protection
+	if err := d.db.GetContext(ctx, &p, `
+		SELECT
+			codebase_id, min_approvals, block_on_rejection, require_resolved_comments, require_up_to_date, restrict_landing, updated_by, updated_at
+		FROM land_protections
+		WHERE codebase_id = $1
+	`, codebaseID); err != nil {
+		return nil, fmt.Errorf("failed to get land protection: %w", err)
+	}
+	return &p, nil
+}
+
+func (d *database) Upsert(ctx context.Context, p *protection.Protection) error {
+	if _, err := d.db.NamedExecContext(ctx, `
+		INSERT INTO land_protections
+			(codebase_id, min_approvals, block_on_rejection, require_resolved_comments, require_up_to_date, restrict_landing, updated_by, updated_at)
+		VALUES
+			(:codebase_id, :min_approvals, :block_on_rejection, :require_resolved_comments, :require_up_to_date, :restrict_landing, :updated_by, :updated_at)
+		ON CONFLICT (codebase_id) DO UPDATE
+		SET
+			min_approvals = :min_approvals,
+			block_on_rejection = :block_on_rejection,
+			require_resolved_comments = :require_resolved_comments,
+			require_up_to_date = :require_up_to_date,
+			restrict_landing = :restrict_landing,
+			updated_by = :updated_by,
+			updated_at = :updated_at
+	`, p); err != nil {
+		return fmt.Errorf("failed to upsert land protection: %w", err)
+	}
+	return nil
+}
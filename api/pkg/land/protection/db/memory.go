@@ -0,0 +1,40 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/land/protection"
+)
+
+var _ Repository = &memory{}
+
+type memory struct {
+	mu           sync.RWMutex
+	byCodebaseID map[codebases.ID]protection.Protection
+}
+
+func NewMemory() Repository {
+	return &memory{
+		byCodebaseID: make(map[codebases.ID]protection.Protection),
+	}
+}
+
+func (m *memory) Get(_ context.Context, codebaseID codebases.ID) (*protection.Protection, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	p, found := m.byCodebaseID[codebaseID]
+	if !found {
+		return nil, sql.ErrNoRows
+	}
+	return &p, nil
+}
+
+func (m *memory) Upsert(_ context.Context, p *protection.Protection) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.byCodebaseID[p.CodebaseID] = *p
+	return nil
+}
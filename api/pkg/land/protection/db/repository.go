@@ -0,0 +1,14 @@
+package db
+
+import (
+	"context"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/land/protection"
+)
+
+type Repository interface {
+	// Get returns sql.ErrNoRows if the codebase is not protected.
+	Get(context.Context, codebases.ID) (*protection.Protection, error)
+	Upsert(context.Context, *protection.Protection) error
+}
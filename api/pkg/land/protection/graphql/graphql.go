@@ -0,0 +1,143 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"getsturdy.com/api/pkg/auth"
+	service_auth "getsturdy.com/api/pkg/auth/service"
+	"getsturdy.com/api/pkg/codebases"
+	service_codebase "getsturdy.com/api/pkg/codebases/service"
+	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
+	"getsturdy.com/api/pkg/graphql/resolvers"
+	"getsturdy.com/api/pkg/land/protection"
+	service_protection "getsturdy.com/api/pkg/land/protection/service"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+type rootResolver struct {
+	protectionService *service_protection.Service
+	codebaseService   *service_codebase.Service
+	authService       *service_auth.Service
+
+	codebaseRootResolver *resolvers.CodebaseRootResolver
+}
+
+func New(
+	protectionService *service_protection.Service,
+	codebaseService *service_codebase.Service,
+	authService *service_auth.Service,
+
+	codebaseRootResolver *resolvers.CodebaseRootResolver,
+) resolvers.LandProtectionRootResolver {
+	return &rootResolver{
+		protectionService: protectionService,
+		codebaseService:   codebaseService,
+		authService:       authService,
+
+		codebaseRootResolver: codebaseRootResolver,
+	}
+}
+
+func (r *rootResolver) LandProtection(ctx context.Context, args resolvers.LandProtectionArgs) (resolvers.LandProtectionResolver, error) {
+	cb, err := r.codebaseService.GetByID(ctx, codebases.ID(args.CodebaseID))
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	if err := r.authService.CanRead(ctx, cb); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	p, err := r.protectionService.Get(ctx, cb.ID)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	return &resolver{root: r, protection: p}, nil
+}
+
+func (r *rootResolver) UpdateLandProtection(ctx context.Context, args resolvers.UpdateLandProtectionArgs) (resolvers.LandProtectionResolver, error) {
+	userID, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	cb, err := r.codebaseService.GetByID(ctx, codebases.ID(args.Input.CodebaseID))
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	if err := r.authService.CanAdmin(ctx, cb); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	p, err := r.protectionService.Get(ctx, cb.ID)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	if args.Input.MinApprovals != nil {
+		p.MinApprovals = int(*args.Input.MinApprovals)
+	}
+	if args.Input.BlockOnRejection != nil {
+		p.BlockOnRejection = *args.Input.BlockOnRejection
+	}
+	if args.Input.RequireResolvedComments != nil {
+		p.RequireResolvedComments = *args.Input.RequireResolvedComments
+	}
+	if args.Input.RequireUpToDate != nil {
+		p.RequireUpToDate = *args.Input.RequireUpToDate
+	}
+	if args.Input.RestrictLanding != nil {
+		p.RestrictLanding = *args.Input.RestrictLanding
+	}
+
+	if err := r.protectionService.Update(ctx, p, userID); errors.Is(err, service_protection.ErrInvalidMinApprovals) {
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", err.Error())
+	} else if err != nil {
+		return nil, gqlerrors.Error(fmt.Errorf("failed to update land protection: %w", err))
+	}
+
+	return &resolver{root: r, protection: p}, nil
+}
+
+type resolver struct {
+	root       *rootResolver
+	protection *protection.Protection
+}
+
+func (r *resolver) Codebase(ctx context.Context) (resolvers.CodebaseResolver, error) {
+	id := graphql.ID(r.protection.CodebaseID)
+	return (*r.root.codebaseRootResolver).Codebase(ctx, resolvers.CodebaseArgs{ID: &id})
+}
+
+func (r *resolver) MinApprovals() int32 {
+	return int32(r.protection.MinApprovals)
+}
+
+func (r *resolver) BlockOnRejection() bool {
+	return r.protection.BlockOnRejection
+}
+
+func (r *resolver) RequireResolvedComments() bool {
+	return r.protection.RequireResolvedComments
+}
+
+func (r *resolver) RequireUpToDate() bool {
+	return r.protection.RequireUpToDate
+}
+
+func (r *resolver) RestrictLanding() bool {
+	return r.protection.RestrictLanding
+}
+
+func (r *resolver) UpdatedAt() *int32 {
+	if r.protection.UpdatedAt.IsZero() {
+		return nil
+	}
+	t := int32(r.protection.UpdatedAt.Unix())
+	return &t
+}
@@ -0,0 +1,28 @@
+package protection
+
+import (
+	"time"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/users"
+)
+
+// Protection is a set of rules that a workspace must follow to be landed in a codebase. The zero value of every
+// rule disables it.
+type Protection struct {
+	CodebaseID codebases.ID `db:"codebase_id"`
+
+	// MinApprovals is the number of approving reviews a workspace must have.
+	MinApprovals int `db:"min_approvals"`
+	// BlockOnRejection prevents landing workspaces that have a rejecting review that is not dismissed.
+	BlockOnRejection bool `db:"block_on_rejection"`
+	// RequireResolvedComments prevents landing workspaces with unresolved comments.
+	RequireResolvedComments bool `db:"require_resolved_comments"`
+	// RequireUpToDate prevents landing workspaces that are not up to date with the trunk.
+	RequireUpToDate bool `db:"require_up_to_date"`
+	// RestrictLanding only allows users that have the "land" action on the codebase in the acl policy to land.
+	RestrictLanding bool `db:"restrict_landing"`
+
+	UpdatedBy users.ID  `db:"updated_by"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
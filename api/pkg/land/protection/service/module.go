@@ -0,0 +1,11 @@
+package service
+
+import (
+	"getsturdy.com/api/pkg/di"
+	db_protection "getsturdy.com/api/pkg/land/protection/db"
+)
+
+func Module(c *di.Container) {
+	c.Import(db_protection.Module)
+	c.Register(New)
+}
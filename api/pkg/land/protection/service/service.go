@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/land/protection"
+	db_protection "getsturdy.com/api/pkg/land/protection/db"
+	"getsturdy.com/api/pkg/users"
+)
+
+var ErrInvalidMinApprovals = errors.New("minimum number of approvals can't be negative")
+
+type Service struct {
+	repo db_protection.Repository
+}
+
+func New(repo db_protection.Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Get returns the protection of the codebase. If the codebase is not protected, a protection with all rules
+// disabled is returned.
+func (s *Service) Get(ctx context.Context, codebaseID codebases.ID) (*protection.Protection, error) {
+	p, err := s.repo.Get(ctx, codebaseID)
+	switch {
+	case err == nil:
+		return p, nil
+	case errors.Is(err, sql.ErrNoRows):
+		return &protection.Protection{CodebaseID: codebaseID}, nil
+	default:
+		return nil, fmt.Errorf("failed to get land protection: %w", err)
+	}
+}
+
+func (s *Service) Update(ctx context.Context, p *protection.Protection, userID users.ID) error {
+	if p.MinApprovals < 0 {
+		return ErrInvalidMinApprovals
+	}
+
+	p.UpdatedBy = userID
+	p.UpdatedAt = time.Now()
+	if err := s.repo.Upsert(ctx, p); err != nil {
+		return fmt.Errorf("failed to update land protection: %w", err)
+	}
+	return nil
+}
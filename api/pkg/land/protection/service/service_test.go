@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"getsturdy.com/api/pkg/codebases"
+	db_protection "getsturdy.com/api/pkg/land/protection/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAndUpdate(t *testing.T) {
+	ctx := context.Background()
+	svc := New(db_protection.NewMemory())
+	codebaseID := codebases.ID("codebase-id")
+
+	p, err := svc.Get(ctx, codebaseID)
+	require.NoError(t, err)
+	assert.Equal(t, codebaseID, p.CodebaseID)
+	assert.Zero(t, p.MinApprovals)
+	assert.False(t, p.RestrictLanding)
+
+	p.MinApprovals = -1
+	assert.ErrorIs(t, svc.Update(ctx, p, "user-id"), ErrInvalidMinApprovals)
+
+	p.MinApprovals = 2
+	p.BlockOnRejection = true
+	require.NoError(t, svc.Update(ctx, p, "user-id"))
+
+	updated, err := svc.Get(ctx, codebaseID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, updated.MinApprovals)
+	assert.True(t, updated.BlockOnRejection)
+	assert.Equal(t, "user-id", updated.UpdatedBy.String())
+	assert.False(t, updated.UpdatedAt.IsZero())
+}
@@ -6,16 +6,23 @@ import (
 	service_analytics "getsturdy.com/api/pkg/analytics/service"
 	service_changes "getsturdy.com/api/pkg/changes/service"
 	workers_ci "getsturdy.com/api/pkg/ci/workers"
+	provider_acl "getsturdy.com/api/pkg/codebases/acl/provider"
 	service_codebase "getsturdy.com/api/pkg/codebases/service"
+	service_codeowners "getsturdy.com/api/pkg/codeowners/service"
 	service_comments "getsturdy.com/api/pkg/comments/service"
 	"getsturdy.com/api/pkg/di"
 	"getsturdy.com/api/pkg/events"
 	eventsv2 "getsturdy.com/api/pkg/events/v2"
+	service_protection "getsturdy.com/api/pkg/land/protection/service"
 	"getsturdy.com/api/pkg/logger"
+	service_chat "getsturdy.com/api/pkg/notification/chat/service"
+	db_review "getsturdy.com/api/pkg/review/db"
 	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
 	worker_snapshots "getsturdy.com/api/pkg/snapshots/worker"
+	service_sync "getsturdy.com/api/pkg/sync/service"
 	service_users "getsturdy.com/api/pkg/users/service/module"
 	service_view "getsturdy.com/api/pkg/views/service"
+	service_webhooks "getsturdy.com/api/pkg/webhooks/service"
 	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
 	service_workspaces "getsturdy.com/api/pkg/workspaces/service"
 	service_workspace_statuses "getsturdy.com/api/pkg/workspaces/statuses/service"
@@ -41,5 +48,12 @@ func Module(c *di.Container) {
 	c.Import(workers_ci.Module)
 	c.Import(sender.Module)
 	c.Import(service_workspace_statuses.Module)
+	c.Import(service_sync.Module)
+	c.Import(service_webhooks.Module)
+	c.Import(service_chat.Module)
+	c.Import(service_codeowners.Module)
+	c.Import(service_protection.Module)
+	c.Import(provider_acl.Module)
+	c.Import(db_review.Module)
 	c.Register(New)
 }
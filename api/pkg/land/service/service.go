@@ -2,7 +2,11 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	service_workspace_statuses "getsturdy.com/api/pkg/workspaces/statuses/service"
@@ -12,23 +16,34 @@ import (
 	service_activity "getsturdy.com/api/pkg/activity/service"
 	"getsturdy.com/api/pkg/analytics"
 	service_analytics "getsturdy.com/api/pkg/analytics/service"
+	"getsturdy.com/api/pkg/auth"
 	"getsturdy.com/api/pkg/changes"
 	"getsturdy.com/api/pkg/changes/message"
 	service_changes "getsturdy.com/api/pkg/changes/service"
 	workers_ci "getsturdy.com/api/pkg/ci/workers"
+	"getsturdy.com/api/pkg/codebases/acl"
+	provider_acl "getsturdy.com/api/pkg/codebases/acl/provider"
 	service_codebase "getsturdy.com/api/pkg/codebases/service"
+	service_codeowners "getsturdy.com/api/pkg/codeowners/service"
 	service_comments "getsturdy.com/api/pkg/comments/service"
 	"getsturdy.com/api/pkg/events"
 	eventsv2 "getsturdy.com/api/pkg/events/v2"
+	service_protection "getsturdy.com/api/pkg/land/protection/service"
+	service_chat "getsturdy.com/api/pkg/notification/chat/service"
+	"getsturdy.com/api/pkg/review"
+	db_review "getsturdy.com/api/pkg/review/db"
 	"getsturdy.com/api/pkg/snapshots"
 	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
 	worker_snapshots "getsturdy.com/api/pkg/snapshots/worker"
+	service_sync "getsturdy.com/api/pkg/sync/service"
 	service_users "getsturdy.com/api/pkg/users/service"
 	service_view "getsturdy.com/api/pkg/views/service"
 	vcs_view "getsturdy.com/api/pkg/views/vcs"
+	service_webhooks "getsturdy.com/api/pkg/webhooks/service"
 	"getsturdy.com/api/pkg/workspaces"
 	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
 	service_workspaces "getsturdy.com/api/pkg/workspaces/service"
+	vcs_workspace "getsturdy.com/api/pkg/workspaces/vcs"
 	"getsturdy.com/api/vcs"
 	"getsturdy.com/api/vcs/executor"
 
@@ -38,12 +53,55 @@ import (
 
 var (
 	ErrNotAllowedUnhealthyWorkspace = fmt.Errorf("not allowed to land workspace, it has unhealthy statuses")
+	ErrNotAllowedStackedWorkspace   = fmt.Errorf("not allowed to land workspace, its base workspace has not been landed")
+	ErrNotAllowedMissingApproval    = fmt.Errorf("not allowed to land workspace, it has not been approved by the owners")
+	ErrNotAllowedTooFewApprovals    = fmt.Errorf("not allowed to land workspace, it does not have enough approving reviews")
+	ErrNotAllowedRejected           = fmt.Errorf("not allowed to land workspace, it has been rejected")
+	ErrNotAllowedUnresolvedComments = fmt.Errorf("not allowed to land workspace, it has unresolved comments")
+	ErrNotAllowedOutdatedWorkspace  = fmt.Errorf("not allowed to land workspace, it is not up to date with the trunk")
+	ErrNotAllowedRestrictedLanding  = fmt.Errorf("not allowed to land workspace, the user is not allowed to land in the codebase")
+
+	ErrInvalidSeries = fmt.Errorf("invalid series")
 )
 
+// Mode is how the changes of a workspace are landed.
+type Mode string
+
+const (
+	// ModeSquash lands all changes of the workspace as a single change.
+	ModeSquash Mode = "squash"
+	// ModeSnapshots lands each of the selected snapshots of the workspace as a change, in the order they were taken.
+	ModeSnapshots Mode = "snapshots"
+	// ModeHunkGroups lands each group of hunks as a change, in order.
+	ModeHunkGroups Mode = "hunk_groups"
+)
+
+// Series describes how a workspace is landed. In all modes but ModeSquash, the changes of the workspace that are not
+// a part of the series are landed as a last change, and the changes are chained by their parents.
+type Series struct {
+	Mode Mode
+	// SnapshotIDs are the snapshots that are landed with ModeSnapshots.
+	SnapshotIDs []snapshots.ID
+	// HunkGroups are the groups of hunk ids that are landed with ModeHunkGroups.
+	HunkGroups [][]string
+	// Descriptions are the descriptions of the changes that the snapshots or hunk groups are landed as, by position.
+	// Changes without a description get the description of the workspace, with a numbered title.
+	Descriptions []string
+}
+
+// description returns the description of the i:th snapshot or hunk group of the series, if it has one.
+func (s Series) description(i int) string {
+	if i < len(s.Descriptions) {
+		return s.Descriptions[i]
+	}
+	return ""
+}
+
 type Service struct {
 	logger *zap.Logger
 
 	workspaceWriter db_workspaces.WorkspaceWriter
+	reviewRepo      db_review.ReviewRepository
 
 	usersService             service_users.Service
 	workspaceService         *service_workspaces.Service
@@ -55,6 +113,12 @@ type Service struct {
 	activityService          *service_activity.Service
 	codebaseService          *service_codebase.Service
 	workspaceStatusesService *service_workspace_statuses.Service
+	syncService              *service_sync.Service
+	webhooksService          *service_webhooks.Service
+	chatService              *service_chat.Service
+	codeownersService        *service_codeowners.Service
+	protectionService        *service_protection.Service
+	aclProvider              *provider_acl.Provider
 
 	activitySender   sender.ActivitySender
 	snapshotterQueue worker_snapshots.Queue
@@ -68,6 +132,7 @@ func New(
 	logger *zap.Logger,
 
 	workspaceWriter db_workspaces.WorkspaceWriter,
+	reviewRepo db_review.ReviewRepository,
 
 	usersService service_users.Service,
 	workspaceService *service_workspaces.Service,
@@ -79,6 +144,12 @@ func New(
 	activityService *service_activity.Service,
 	codebaseService *service_codebase.Service,
 	workspaceStatusesService *service_workspace_statuses.Service,
+	syncService *service_sync.Service,
+	webhooksService *service_webhooks.Service,
+	chatService *service_chat.Service,
+	codeownersService *service_codeowners.Service,
+	protectionService *service_protection.Service,
+	aclProvider *provider_acl.Provider,
 
 	activitySender sender.ActivitySender,
 	snapshotterQueue worker_snapshots.Queue,
@@ -91,6 +162,7 @@ func New(
 		logger: logger,
 
 		workspaceWriter: workspaceWriter,
+		reviewRepo:      reviewRepo,
 
 		usersService:             usersService,
 		workspaceService:         workspaceService,
@@ -102,6 +174,12 @@ func New(
 		activityService:          activityService,
 		codebaseService:          codebaseService,
 		workspaceStatusesService: workspaceStatusesService,
+		syncService:              syncService,
+		webhooksService:          webhooksService,
+		chatService:              chatService,
+		codeownersService:        codeownersService,
+		protectionService:        protectionService,
+		aclProvider:              aclProvider,
 
 		activitySender:   activitySender,
 		snapshotterQueue: snapshotterQueue,
@@ -113,6 +191,21 @@ func New(
 }
 
 func (s *Service) LandChange(ctx context.Context, ws *workspaces.Workspace, diffOpts ...vcs.DiffOption) (*changes.Change, error) {
+	landed, err := s.LandChangeSeries(ctx, ws, Series{Mode: ModeSquash}, diffOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return landed[len(landed)-1], nil
+}
+
+// LandChangeSeries lands the workspace as one or more changes, as described by the series. The landed changes are
+// returned in order, the last one is the new head of the codebase.
+func (s *Service) LandChangeSeries(ctx context.Context, ws *workspaces.Workspace, series Series, diffOpts ...vcs.DiffOption) ([]*changes.Change, error) {
+	parts, err := s.parts(ctx, ws, series)
+	if err != nil {
+		return nil, err
+	}
+
 	user, err := s.usersService.GetByID(ctx, ws.UserID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user: %w", err)
@@ -124,6 +217,11 @@ func (s *Service) LandChange(ctx context.Context, ws *workspaces.Workspace, diff
 		return nil, fmt.Errorf("failed to get codebase: %w", err)
 	}
 
+	// stacked workspaces can only be landed after their base workspace has been landed
+	if ws.IsStacked() {
+		return nil, ErrNotAllowedStackedWorkspace
+	}
+
 	// make sure that all statuses are healthy and not stale
 	if cb.RequireHealthyStatus {
 		healthy, err := s.workspaceStatusesService.HealthyStatus(ctx, ws)
@@ -135,7 +233,22 @@ func (s *Service) LandChange(ctx context.Context, ws *workspaces.Workspace, diff
 		}
 	}
 
-	gitCommitMessage := message.CommitMessage(ws.DraftDescription)
+	// make sure that the owners of the changed files have approved, if they require it
+	missingApprovals, err := s.codeownersService.MissingApprovals(ctx, ws)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get missing approvals: %w", err)
+	}
+	if len(missingApprovals) > 0 {
+		ownerIDs := make([]string, 0, len(missingApprovals))
+		for _, owner := range missingApprovals {
+			ownerIDs = append(ownerIDs, owner.ID)
+		}
+		return nil, fmt.Errorf("%w: %s", ErrNotAllowedMissingApproval, strings.Join(ownerIDs, ", "))
+	}
+
+	if err := s.checkProtection(ctx, ws); err != nil {
+		return nil, err
+	}
 
 	signature := git.Signature{
 		Name:  user.Name,
@@ -143,32 +256,60 @@ func (s *Service) LandChange(ctx context.Context, ws *workspaces.Workspace, diff
 		When:  time.Now(),
 	}
 
-	var change *changes.Change
+	var landed []*changes.Change
 	creteAndLand := func(viewRepo vcs.RepoWriter) error {
-		createdCommitID, fromViewPushFunc, err := s.changeService.CreateAndLandFromView(
-			context.Background(),
-			viewRepo,
-			ws.CodebaseID,
-			ws.ID,
-			gitCommitMessage,
-			signature,
-			diffOpts...,
-		)
-		if err != nil {
-			return fmt.Errorf("failed to create and land from view: %w", err)
-		}
-
-		parents, err := viewRepo.GetCommitParents(createdCommitID)
-		if err != nil {
-			return fmt.Errorf("failed get parents of new commit: %w", err)
-		}
-		if len(parents) != 1 {
-			return fmt.Errorf("commit has an unexpected number of parents n=%d", len(parents))
-		}
-
-		change, err = s.changeService.CreateWithCommitAsParent(ctx, ws, createdCommitID, parents[0])
-		if err != nil {
-			return fmt.Errorf("failed to create change: %w", err)
+		var fromViewPushFunc func(vcs.RepoGitWriter) error
+		if parts == nil {
+			createdCommitID, pushFunc, err := s.changeService.CreateAndLandFromView(
+				context.Background(),
+				viewRepo,
+				ws.CodebaseID,
+				ws.ID,
+				message.CommitMessage(ws.DraftDescription),
+				signature,
+				diffOpts...,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to create and land from view: %w", err)
+			}
+			fromViewPushFunc = pushFunc
+
+			parentCommitID, err := parentCommitID(viewRepo, createdCommitID)
+			if err != nil {
+				return err
+			}
+
+			change, err := s.changeService.CreateWithCommitAsParent(ctx, ws, createdCommitID, parentCommitID)
+			if err != nil {
+				return fmt.Errorf("failed to create change: %w", err)
+			}
+			landed = append(landed, change)
+		} else {
+			createdCommits, pushFunc, err := s.changeService.CreateSeriesAndLandFromView(
+				context.Background(),
+				viewRepo,
+				ws.CodebaseID,
+				ws.ID,
+				ws.DraftDescription,
+				signature,
+				parts,
+				diffOpts...,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to create and land series from view: %w", err)
+			}
+			fromViewPushFunc = pushFunc
+
+			parentCommitID, err := parentCommitID(viewRepo, createdCommits[0].CommitID)
+			if err != nil {
+				return err
+			}
+
+			// the changes of the series are chained on top of each other
+			landed, err = s.changeService.CreateSeries(ctx, ws, createdCommits, parentCommitID)
+			if err != nil {
+				return fmt.Errorf("failed to create changes: %w", err)
+			}
 		}
 
 		if err := fromViewPushFunc(viewRepo); err != nil {
@@ -203,6 +344,9 @@ func (s *Service) LandChange(ctx context.Context, ws *workspaces.Workspace, diff
 		ws.SetSnapshot(nil)
 	}
 
+	// the workspace, and everything that belonged to it, is moved to the last change of the series
+	change := landed[len(landed)-1]
+
 	s.analyticsService.Capture(ctx, "create change",
 		analytics.CodebaseID(ws.CodebaseID),
 		analytics.Property("workspace_id", ws.ID),
@@ -285,9 +429,217 @@ func (s *Service) LandChange(ctx context.Context, ws *workspaces.Workspace, diff
 		s.logger.Error("failed to enqueue change", zap.Error(err))
 	}
 
+	for _, ch := range landed {
+		if err := s.webhooksService.ChangeLanded(ctx, ch); err != nil {
+			s.logger.Error("failed to send change landed webhook", zap.Error(err))
+		}
+		if err := s.chatService.ChangeLanded(ctx, ch); err != nil {
+			s.logger.Error("failed to send change landed chat notification", zap.Error(err))
+		}
+	}
+
 	if err := s.workspaceService.ArchiveWithChange(ctx, ws, change); err != nil {
 		return nil, fmt.Errorf("failed to archive workspace: %w", err)
 	}
 
-	return change, nil
+	if err := s.restackWorkspacesOnTrunk(ctx, ws); err != nil {
+		s.logger.Error("failed to restack workspaces", zap.Error(err))
+		// do not fail
+	}
+
+	return landed, nil
+}
+
+// parentCommitID returns the parent of a newly created commit.
+func parentCommitID(repo vcs.RepoWriter, commitID string) (string, error) {
+	parents, err := repo.GetCommitParents(commitID)
+	if err != nil {
+		return "", fmt.Errorf("failed get parents of new commit: %w", err)
+	}
+	if len(parents) != 1 {
+		return "", fmt.Errorf("commit has an unexpected number of parents n=%d", len(parents))
+	}
+	return parents[0], nil
+}
+
+// parts returns the parts that the workspace is landed as, or nil if it's squashed into a single change.
+func (s *Service) parts(ctx context.Context, ws *workspaces.Workspace, series Series) ([]service_changes.Part, error) {
+	switch series.Mode {
+	case ModeSquash, "":
+		return nil, nil
+	case ModeSnapshots:
+		if len(series.SnapshotIDs) == 0 {
+			return nil, fmt.Errorf("%w: no snapshots selected", ErrInvalidSeries)
+		}
+
+		if len(series.Descriptions) > len(series.SnapshotIDs) {
+			return nil, fmt.Errorf("%w: more descriptions than snapshots", ErrInvalidSeries)
+		}
+
+		// the parts are sorted, so the descriptions are kept with their snapshots
+		parts := make([]service_changes.Part, 0, len(series.SnapshotIDs))
+		for i, id := range series.SnapshotIDs {
+			snapshot, err := s.snapshotter.GetByID(ctx, id)
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				return nil, fmt.Errorf("%w: snapshot %s not found", ErrInvalidSeries, id)
+			case err != nil:
+				return nil, fmt.Errorf("failed to get snapshot: %w", err)
+			case snapshot.WorkspaceID != ws.ID:
+				return nil, fmt.Errorf("%w: snapshot %s is not a snapshot of the workspace", ErrInvalidSeries, id)
+			}
+			parts = append(parts, service_changes.Part{Snapshot: snapshot, Description: series.description(i)})
+		}
+
+		sort.SliceStable(parts, func(i, j int) bool {
+			return parts[i].Snapshot.CreatedAt.Before(parts[j].Snapshot.CreatedAt)
+		})
+		return parts, nil
+	case ModeHunkGroups:
+		if len(series.HunkGroups) == 0 {
+			return nil, fmt.Errorf("%w: no hunk groups selected", ErrInvalidSeries)
+		}
+
+		if len(series.Descriptions) > len(series.HunkGroups) {
+			return nil, fmt.Errorf("%w: more descriptions than hunk groups", ErrInvalidSeries)
+		}
+
+		parts := make([]service_changes.Part, 0, len(series.HunkGroups))
+		for i, group := range series.HunkGroups {
+			parts = append(parts, service_changes.Part{PatchIDs: group, Description: series.description(i)})
+		}
+		return parts, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown mode %q", ErrInvalidSeries, series.Mode)
+	}
+}
+
+// checkProtection returns an error if the workspace is not allowed to be landed by the land protection of
+// the codebase.
+func (s *Service) checkProtection(ctx context.Context, ws *workspaces.Workspace) error {
+	protection, err := s.protectionService.Get(ctx, ws.CodebaseID)
+	if err != nil {
+		return fmt.Errorf("failed to get land protection: %w", err)
+	}
+
+	if protection.RestrictLanding {
+		if err := s.checkCanLand(ctx, ws); err != nil {
+			return err
+		}
+	}
+
+	if protection.MinApprovals > 0 || protection.BlockOnRejection {
+		reviews, err := s.reviewRepo.ListLatestByWorkspace(ctx, ws.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list reviews: %w", err)
+		}
+
+		approvals := 0
+		for _, rev := range reviews {
+			if rev.UserID == ws.UserID {
+				continue
+			}
+			switch rev.Grade {
+			case review.ReviewGradeApprove:
+				approvals++
+			case review.ReviewGradeReject:
+				if protection.BlockOnRejection {
+					return ErrNotAllowedRejected
+				}
+			}
+		}
+
+		if approvals < protection.MinApprovals {
+			return fmt.Errorf("%w: %d of %d", ErrNotAllowedTooFewApprovals, approvals, protection.MinApprovals)
+		}
+	}
+
+	if protection.RequireResolvedComments {
+		hasUnresolved, err := s.commentService.HasUnresolved(ctx, ws.ID)
+		switch {
+		case err != nil:
+			return fmt.Errorf("failed to get comments: %w", err)
+		case hasUnresolved:
+			return ErrNotAllowedUnresolvedComments
+		}
+	}
+
+	if protection.RequireUpToDate {
+		var upToDate bool
+		if err := s.executorProvider.New().GitRead(func(repo vcs.RepoGitReader) error {
+			var err error
+			upToDate, err = vcs_workspace.UpToDateWithTrunk(repo, ws.ID)
+			return err
+		}).ExecTrunk(ws.CodebaseID, "landCheckUpToDateWithTrunk"); err != nil {
+			return fmt.Errorf("failed to check if workspace is up to date with trunk: %w", err)
+		}
+		if !upToDate {
+			return ErrNotAllowedOutdatedWorkspace
+		}
+	}
+
+	return nil
+}
+
+// checkCanLand returns an error if the user that lands the workspace is not allowed to land by the acl policy.
+// Workspaces that are landed without an authenticated user are never allowed.
+func (s *Service) checkCanLand(ctx context.Context, ws *workspaces.Workspace) error {
+	userID, err := auth.UserID(ctx)
+	if err != nil {
+		return ErrNotAllowedRestrictedLanding
+	}
+
+	user, err := s.usersService.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	policy, err := s.aclProvider.GetByCodebaseID(ctx, ws.CodebaseID)
+	if err != nil {
+		return fmt.Errorf("failed to get acl policy: %w", err)
+	}
+
+	resource := acl.Identity{Type: acl.Codebases, ID: ws.CodebaseID.String()}
+	if policy.Policy.Assert(acl.Identity{Type: acl.Users, ID: user.Email}, acl.ActionLand, resource) ||
+		policy.Policy.Assert(acl.Identity{Type: acl.Users, ID: user.ID.String()}, acl.ActionLand, resource) {
+		return nil
+	}
+
+	return ErrNotAllowedRestrictedLanding
+}
+
+// restackWorkspacesOnTrunk moves all workspaces that are stacked on top of the landed workspace ws to the trunk.
+//
+// The changes of ws are now a part of the trunk, so the stacked workspaces are synced on top of it. If a sync results
+// in conflicts, the workspace is left in the conflicting state for the user to resolve. A workspace that fails to be
+// moved is logged, and does not stop the others from being moved.
+func (s *Service) restackWorkspacesOnTrunk(ctx context.Context, ws *workspaces.Workspace) error {
+	stacked, err := s.workspaceService.ListStackedOn(ctx, ws)
+	if err != nil {
+		return fmt.Errorf("failed to list stacked workspaces: %w", err)
+	}
+
+	for _, child := range stacked {
+		logger := s.logger.With(zap.String("workspace_id", child.ID), zap.String("base_workspace_id", ws.ID))
+
+		if err := s.workspaceService.Unstack(ctx, child); err != nil {
+			logger.Error("failed to unstack workspace", zap.Error(err))
+			continue
+		}
+
+		status, err := s.syncService.OnTrunk(ctx, child)
+		switch {
+		case err != nil:
+			logger.Error("failed to sync stacked workspace on trunk", zap.Error(err))
+			continue
+		case status.HaveConflicts:
+			logger.Info("stacked workspace has conflicts with trunk")
+		}
+
+		if err := s.eventsSender.Workspace(child.ID, events.WorkspaceUpdated, child.ID); err != nil {
+			logger.Error("failed to send workspace event", zap.Error(err))
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"getsturdy.com/api/pkg/auth"
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/codebases/acl"
+	db_acl "getsturdy.com/api/pkg/codebases/acl/db"
+	provider_acl "getsturdy.com/api/pkg/codebases/acl/provider"
+	db_codebases "getsturdy.com/api/pkg/codebases/db"
+	"getsturdy.com/api/pkg/comments"
+	db_comments "getsturdy.com/api/pkg/comments/db"
+	service_comments "getsturdy.com/api/pkg/comments/service"
+	"getsturdy.com/api/pkg/land/protection"
+	db_protection "getsturdy.com/api/pkg/land/protection/db"
+	service_protection "getsturdy.com/api/pkg/land/protection/service"
+	"getsturdy.com/api/pkg/review"
+	db_review "getsturdy.com/api/pkg/review/db"
+	"getsturdy.com/api/pkg/users"
+	db_users "getsturdy.com/api/pkg/users/db"
+	service_users "getsturdy.com/api/pkg/users/service"
+	"getsturdy.com/api/pkg/workspaces"
+	"getsturdy.com/api/vcs"
+	"getsturdy.com/api/vcs/executor"
+	"getsturdy.com/api/vcs/testutil"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type protectionTest struct {
+	service *Service
+
+	protectionRepo db_protection.Repository
+	reviewRepo     db_review.ReviewRepository
+	commentRepo    db_comments.Repository
+	aclRepo        db_acl.ACLRepository
+	trunk          vcs.RepoGitWriter
+
+	author   *users.User
+	reviewer *users.User
+	ws       *workspaces.Workspace
+}
+
+func setupProtectionTest(t *testing.T) *protectionTest {
+	logger := zap.NewNop()
+	repoProvider := testutil.TestingRepoProvider(t)
+
+	userRepo := db_users.NewMemory()
+	usersService := service_users.New(logger, userRepo, nil)
+	author := &users.User{ID: users.ID(uuid.NewString()), Email: "author@getsturdy.com"}
+	reviewer := &users.User{ID: users.ID(uuid.NewString()), Email: "reviewer@getsturdy.com"}
+	require.NoError(t, userRepo.Create(author))
+	require.NoError(t, userRepo.Create(reviewer))
+
+	codebaseID := codebases.ID(uuid.NewString())
+	trunk, err := vcs.CreateBareRepoWithRootCommit(repoProvider.TrunkPath(codebaseID))
+	require.NoError(t, err)
+
+	ws := &workspaces.Workspace{ID: uuid.NewString(), CodebaseID: codebaseID, UserID: author.ID}
+	rootID, err := trunk.BranchCommitID("sturdytrunk")
+	require.NoError(t, err)
+	require.NoError(t, trunk.CreateNewBranchAt(ws.ID, rootID))
+
+	tc := &protectionTest{
+		protectionRepo: db_protection.NewMemory(),
+		reviewRepo:     db_review.NewMemory(),
+		commentRepo:    db_comments.NewMemory(),
+		aclRepo:        db_acl.NewInMemoryAclRepo(),
+		trunk:          trunk,
+		author:         author,
+		reviewer:       reviewer,
+		ws:             ws,
+	}
+	tc.service = &Service{
+		logger:            logger,
+		reviewRepo:        tc.reviewRepo,
+		usersService:      usersService,
+		commentService:    service_comments.New(logger, tc.commentRepo, nil, nil, nil),
+		protectionService: service_protection.New(tc.protectionRepo),
+		aclProvider:       provider_acl.New(tc.aclRepo, db_codebases.NewInMemoryCodebaseUserRepo(), usersService),
+		executorProvider:  executor.NewProvider(logger, repoProvider),
+	}
+	return tc
+}
+
+func (tc *protectionTest) protect(t *testing.T, p protection.Protection) {
+	p.CodebaseID = tc.ws.CodebaseID
+	require.NoError(t, tc.protectionRepo.Upsert(context.Background(), &p))
+}
+
+func (tc *protectionTest) review(t *testing.T, userID users.ID, grade review.ReviewGrade, dismissed bool) {
+	r := review.Review{
+		ID:          uuid.NewString(),
+		UserID:      userID,
+		CodebaseID:  tc.ws.CodebaseID,
+		WorkspaceID: tc.ws.ID,
+		Grade:       grade,
+		CreatedAt:   time.Now(),
+	}
+	if dismissed {
+		now := time.Now()
+		r.DismissedAt = &now
+	}
+	require.NoError(t, tc.reviewRepo.Create(context.Background(), r))
+}
+
+func (tc *protectionTest) check(userID users.ID) error {
+	ctx := auth.NewContext(context.Background(), &auth.Subject{ID: userID.String(), Type: auth.SubjectUser})
+	return tc.service.checkProtection(ctx, tc.ws)
+}
+
+func TestCheckProtection_unprotected(t *testing.T) {
+	tc := setupProtectionTest(t)
+	tc.review(t, tc.reviewer.ID, review.ReviewGradeReject, false)
+	assert.NoError(t, tc.check(tc.author.ID))
+}
+
+func TestCheckProtection_minApprovals(t *testing.T) {
+	tc := setupProtectionTest(t)
+	tc.protect(t, protection.Protection{MinApprovals: 1})
+
+	// the author can't approve their own workspace
+	tc.review(t, tc.author.ID, review.ReviewGradeApprove, false)
+	assert.ErrorIs(t, tc.check(tc.author.ID), ErrNotAllowedTooFewApprovals)
+
+	// dismissed reviews don't count
+	tc.review(t, tc.reviewer.ID, review.ReviewGradeApprove, true)
+	assert.ErrorIs(t, tc.check(tc.author.ID), ErrNotAllowedTooFewApprovals)
+
+	tc.review(t, tc.reviewer.ID, review.ReviewGradeApprove, false)
+	assert.NoError(t, tc.check(tc.author.ID))
+}
+
+func TestCheckProtection_blockOnRejection(t *testing.T) {
+	tc := setupProtectionTest(t)
+	tc.review(t, tc.reviewer.ID, review.ReviewGradeReject, false)
+
+	tc.protect(t, protection.Protection{BlockOnRejection: false})
+	assert.NoError(t, tc.check(tc.author.ID))
+
+	tc.protect(t, protection.Protection{BlockOnRejection: true})
+	assert.ErrorIs(t, tc.check(tc.author.ID), ErrNotAllowedRejected)
+}
+
+func TestCheckProtection_requireResolvedComments(t *testing.T) {
+	tc := setupProtectionTest(t)
+	tc.protect(t, protection.Protection{RequireResolvedComments: true})
+	assert.NoError(t, tc.check(tc.author.ID))
+
+	comment := comments.Comment{
+		ID:          comments.ID(uuid.NewString()),
+		CodebaseID:  tc.ws.CodebaseID,
+		WorkspaceID: &tc.ws.ID,
+		UserID:      tc.reviewer.ID,
+		CreatedAt:   time.Now(),
+		Message:     "please fix",
+	}
+	require.NoError(t, tc.commentRepo.Create(comment))
+	assert.ErrorIs(t, tc.check(tc.author.ID), ErrNotAllowedUnresolvedComments)
+
+	now := time.Now()
+	comment.ResolvedAt = &now
+	comment.ResolvedBy = &tc.author.ID
+	require.NoError(t, tc.commentRepo.Update(comment))
+	assert.NoError(t, tc.check(tc.author.ID))
+}
+
+func TestCheckProtection_requireUpToDate(t *testing.T) {
+	tc := setupProtectionTest(t)
+	tc.protect(t, protection.Protection{RequireUpToDate: true})
+	assert.NoError(t, tc.check(tc.author.ID))
+
+	// something else is landed on the trunk
+	_, err := tc.trunk.CreateCommitWithFiles([]vcs.FileContents{{Path: "a.txt", Contents: []byte("a")}}, "sturdytrunk")
+	require.NoError(t, err)
+	assert.ErrorIs(t, tc.check(tc.author.ID), ErrNotAllowedOutdatedWorkspace)
+}
+
+func TestCheckProtection_restrictLanding(t *testing.T) {
+	tc := setupProtectionTest(t)
+	tc.protect(t, protection.Protection{RestrictLanding: true})
+
+	a := acl.ACL{
+		ID:         acl.ID(uuid.NewString()),
+		CodebaseID: tc.ws.CodebaseID,
+		CreatedAt:  time.Now(),
+		RawPolicy: `{
+			"rules": [
+				{
+					"id": "the reviewer can land",
+					"principals": ["reviewer@getsturdy.com"],
+					"action": "land",
+					"resources": ["codebases::*"],
+				},
+			],
+		}`,
+	}
+	require.NoError(t, tc.aclRepo.Create(context.Background(), a))
+
+	assert.ErrorIs(t, tc.check(tc.author.ID), ErrNotAllowedRestrictedLanding)
+	assert.NoError(t, tc.check(tc.reviewer.ID))
+
+	// landing without an authenticated user is never allowed
+	assert.ErrorIs(t, tc.service.checkProtection(context.Background(), tc.ws), ErrNotAllowedRestrictedLanding)
+}
+
+func TestCheckProtection_restrictLanding_revoked(t *testing.T) {
+	tc := setupProtectionTest(t)
+	tc.protect(t, protection.Protection{RestrictLanding: true})
+
+	a := acl.ACL{
+		ID:         acl.ID(uuid.NewString()),
+		CodebaseID: tc.ws.CodebaseID,
+		CreatedAt:  time.Now(),
+		RawPolicy: `{
+			"rules": [
+				{
+					"id": "the reviewer can land",
+					"principals": ["reviewer@getsturdy.com"],
+					"action": "land",
+					"resources": ["codebases::*"],
+				},
+			],
+		}`,
+	}
+	require.NoError(t, tc.aclRepo.Create(context.Background(), a))
+
+	// the merge queue lands on behalf of the user that enqueued the workspace
+	ctx := auth.NewUserContext(context.Background(), tc.reviewer.ID)
+	assert.NoError(t, tc.service.checkProtection(ctx, tc.ws))
+
+	// the permission is revoked while the workspace is in the queue
+	a.RawPolicy = `{"rules": []}`
+	require.NoError(t, tc.aclRepo.Update(context.Background(), a))
+	assert.ErrorIs(t, tc.service.checkProtection(ctx, tc.ws), ErrNotAllowedRestrictedLanding)
+}
@@ -0,0 +1,110 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/mergequeue"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type database struct {
+	db *sqlx.DB
+}
+
+func New(db *sqlx.DB) Repository {
+	return &database{db: db}
+}
+
+func (r *database) Create(ctx context.Context, entry *mergequeue.Entry) error {
+	if _, err := r.db.NamedExecContext(ctx, `INSERT INTO merge_queue_entries
+		(id, codebase_id, workspace_id, user_id, status, snapshot_id, trunk_commit_sha, change_id, eject_reason, created_at, updated_at, completed_at)
+		VALUES
+		(:id, :codebase_id, :workspace_id, :user_id, :status, :snapshot_id, :trunk_commit_sha, :change_id, :eject_reason, :created_at, :updated_at, :completed_at)`, entry); err != nil {
+		return fmt.Errorf("failed to insert merge queue entry: %w", err)
+	}
+	return nil
+}
+
+func (r *database) Update(ctx context.Context, entry *mergequeue.Entry) error {
+	if _, err := r.db.NamedExecContext(ctx, `UPDATE merge_queue_entries
+		SET status = :status,
+		    snapshot_id = :snapshot_id,
+		    trunk_commit_sha = :trunk_commit_sha,
+		    change_id = :change_id,
+		    eject_reason = :eject_reason,
+		    updated_at = :updated_at,
+		    completed_at = :completed_at
+		WHERE id = :id`, entry); err != nil {
+		return fmt.Errorf("failed to update merge queue entry: %w", err)
+	}
+	return nil
+}
+
+func (r *database) Get(ctx context.Context, id mergequeue.ID) (*mergequeue.Entry, error) {
+	var entry mergequeue.Entry
+	if err := r.db.GetContext(ctx, &entry, `SELECT id, codebase_id, workspace_id, user_id, status, snapshot_id, trunk_commit_sha, change_id, eject_reason, created_at, updated_at, completed_at
+		FROM merge_queue_entries
+		WHERE id = $1`, id); err != nil {
+		return nil, fmt.Errorf("failed to get merge queue entry: %w", err)
+	}
+	return &entry, nil
+}
+
+func (r *database) GetActiveByWorkspaceID(ctx context.Context, workspaceID string) (*mergequeue.Entry, error) {
+	var entry mergequeue.Entry
+	if err := r.db.GetContext(ctx, &entry, `SELECT id, codebase_id, workspace_id, user_id, status, snapshot_id, trunk_commit_sha, change_id, eject_reason, created_at, updated_at, completed_at
+		FROM merge_queue_entries
+		WHERE workspace_id = $1
+		  AND status IN ($2, $3)
+		ORDER BY created_at DESC
+		LIMIT 1`, workspaceID, mergequeue.StatusQueued, mergequeue.StatusTesting); err != nil {
+		return nil, fmt.Errorf("failed to get merge queue entry: %w", err)
+	}
+	return &entry, nil
+}
+
+func (r *database) ListActiveByCodebaseID(ctx context.Context, codebaseID codebases.ID) ([]*mergequeue.Entry, error) {
+	var entries []*mergequeue.Entry
+	if err := r.db.SelectContext(ctx, &entries, `SELECT id, codebase_id, workspace_id, user_id, status, snapshot_id, trunk_commit_sha, change_id, eject_reason, created_at, updated_at, completed_at
+		FROM merge_queue_entries
+		WHERE codebase_id = $1
+		  AND status IN ($2, $3)
+		ORDER BY created_at ASC`, codebaseID, mergequeue.StatusQueued, mergequeue.StatusTesting); err != nil {
+		return nil, fmt.Errorf("failed to list merge queue entries: %w", err)
+	}
+	return entries, nil
+}
+
+func (r *database) ListActiveCodebaseIDs(ctx context.Context) ([]codebases.ID, error) {
+	var ids []codebases.ID
+	if err := r.db.SelectContext(ctx, &ids, `SELECT DISTINCT codebase_id
+		FROM merge_queue_entries
+		WHERE status IN ($1, $2)`, mergequeue.StatusQueued, mergequeue.StatusTesting); err != nil {
+		return nil, fmt.Errorf("failed to list codebases: %w", err)
+	}
+	return ids, nil
+}
+
+func (r *database) LockCodebase(ctx context.Context, codebaseID codebases.ID) (func(), error) {
+	// advisory locks are held by the session, so the lock and the unlock must run on the same connection
+	conn, err := r.db.Connx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock(hashtext('merge_queue'), hashtext($1))`, codebaseID); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to lock merge queue: %w", err)
+	}
+	return func() {
+		// the lock must be released even if ctx is cancelled
+		if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock(hashtext('merge_queue'), hashtext($1))`, codebaseID); err != nil {
+			// discard the connection instead of returning it to the pool, closing the session releases the lock
+			_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+		_ = conn.Close()
+	}, nil
+}
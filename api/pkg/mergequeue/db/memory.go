@@ -0,0 +1,111 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/mergequeue"
+)
+
+type memory struct {
+	sync.RWMutex
+	byID map[mergequeue.ID]*mergequeue.Entry
+
+	codebaseLocksGuard sync.Mutex
+	codebaseLocks      map[codebases.ID]*sync.Mutex
+}
+
+func NewMemory() Repository {
+	return &memory{
+		byID:          make(map[mergequeue.ID]*mergequeue.Entry),
+		codebaseLocks: make(map[codebases.ID]*sync.Mutex),
+	}
+}
+
+func (m *memory) Create(_ context.Context, entry *mergequeue.Entry) error {
+	m.Lock()
+	defer m.Unlock()
+	cp := *entry
+	m.byID[entry.ID] = &cp
+	return nil
+}
+
+func (m *memory) Update(_ context.Context, entry *mergequeue.Entry) error {
+	m.Lock()
+	defer m.Unlock()
+	if _, found := m.byID[entry.ID]; !found {
+		return sql.ErrNoRows
+	}
+	cp := *entry
+	m.byID[entry.ID] = &cp
+	return nil
+}
+
+func (m *memory) Get(_ context.Context, id mergequeue.ID) (*mergequeue.Entry, error) {
+	m.RLock()
+	defer m.RUnlock()
+	if entry, found := m.byID[id]; found {
+		cp := *entry
+		return &cp, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (m *memory) GetActiveByWorkspaceID(_ context.Context, workspaceID string) (*mergequeue.Entry, error) {
+	m.RLock()
+	defer m.RUnlock()
+	for _, entry := range m.byID {
+		if entry.WorkspaceID == workspaceID && entry.Status.IsActive() {
+			cp := *entry
+			return &cp, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (m *memory) ListActiveByCodebaseID(_ context.Context, codebaseID codebases.ID) ([]*mergequeue.Entry, error) {
+	m.RLock()
+	defer m.RUnlock()
+	entries := []*mergequeue.Entry{}
+	for _, entry := range m.byID {
+		if entry.CodebaseID == codebaseID && entry.Status.IsActive() {
+			cp := *entry
+			entries = append(entries, &cp)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+	})
+	return entries, nil
+}
+
+func (m *memory) ListActiveCodebaseIDs(_ context.Context) ([]codebases.ID, error) {
+	m.RLock()
+	defer m.RUnlock()
+	seen := map[codebases.ID]bool{}
+	ids := []codebases.ID{}
+	for _, entry := range m.byID {
+		if !entry.Status.IsActive() || seen[entry.CodebaseID] {
+			continue
+		}
+		seen[entry.CodebaseID] = true
+		ids = append(ids, entry.CodebaseID)
+	}
+	return ids, nil
+}
+
+func (m *memory) LockCodebase(_ context.Context, codebaseID codebases.ID) (func(), error) {
+	m.codebaseLocksGuard.Lock()
+	lock, found := m.codebaseLocks[codebaseID]
+	if !found {
+		lock = &sync.Mutex{}
+		m.codebaseLocks[codebaseID] = lock
+	}
+	m.codebaseLocksGuard.Unlock()
+
+	lock.Lock()
+	return lock.Unlock, nil
+}
@@ -0,0 +1,23 @@
+package db
+
+import (
+	"context"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/mergequeue"
+)
+
+type Repository interface {
+	Create(context.Context, *mergequeue.Entry) error
+	Update(context.Context, *mergequeue.Entry) error
+	Get(context.Context, mergequeue.ID) (*mergequeue.Entry, error)
+	// GetActiveByWorkspaceID returns the queued or testing entry of the workspace.
+	GetActiveByWorkspaceID(ctx context.Context, workspaceID string) (*mergequeue.Entry, error)
+	// ListActiveByCodebaseID returns all queued or testing entries of the codebase, oldest first.
+	ListActiveByCodebaseID(context.Context, codebases.ID) ([]*mergequeue.Entry, error)
+	// ListActiveCodebaseIDs returns ids of all codebases that have at least one queued or testing entry.
+	ListActiveCodebaseIDs(context.Context) ([]codebases.ID, error)
+	// LockCodebase blocks until it holds the lock of the merge queue of the codebase. The lock is shared
+	// between all replicas of the api, and is held until unlock is called.
+	LockCodebase(context.Context, codebases.ID) (unlock func(), err error)
+}
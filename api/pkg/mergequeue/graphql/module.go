@@ -0,0 +1,25 @@
+package graphql
+
+import (
+	service_auth "getsturdy.com/api/pkg/auth/service"
+	graphql_author "getsturdy.com/api/pkg/author/graphql"
+	service_codebase "getsturdy.com/api/pkg/codebases/service"
+	"getsturdy.com/api/pkg/di"
+	"getsturdy.com/api/pkg/graphql/resolvers"
+	"getsturdy.com/api/pkg/logger"
+	service_mergequeue "getsturdy.com/api/pkg/mergequeue/service"
+	worker_mergequeue "getsturdy.com/api/pkg/mergequeue/worker"
+	service_workspaces "getsturdy.com/api/pkg/workspaces/service"
+)
+
+func Module(c *di.Container) {
+	c.Import(logger.Module)
+	c.Import(service_mergequeue.Module)
+	c.Import(worker_mergequeue.Module)
+	c.Import(service_workspaces.Module)
+	c.Import(service_codebase.Module)
+	c.Import(service_auth.Module)
+	c.Import(graphql_author.Module)
+	c.Import(resolvers.Module)
+	c.Register(New)
+}
@@ -0,0 +1,84 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
+	"getsturdy.com/api/pkg/graphql/resolvers"
+	"getsturdy.com/api/pkg/mergequeue"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+var _ resolvers.MergeQueueEntryResolver = (*entryResolver)(nil)
+
+type entryResolver struct {
+	root  *rootResolver
+	entry *mergequeue.Entry
+}
+
+func (r *entryResolver) ID() graphql.ID {
+	return graphql.ID(r.entry.ID)
+}
+
+func (r *entryResolver) Workspace(ctx context.Context) (resolvers.WorkspaceResolver, error) {
+	yes := true
+	resolver, err := (*r.root.workspaceRootResolver).Workspace(ctx, resolvers.WorkspaceArgs{
+		ID:            graphql.ID(r.entry.WorkspaceID),
+		AllowArchived: &yes,
+	})
+	if errors.Is(err, gqlerrors.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resolver, nil
+}
+
+func (r *entryResolver) Author(ctx context.Context) (resolvers.AuthorResolver, error) {
+	return r.root.authorRootResolver.Author(ctx, graphql.ID(r.entry.UserID))
+}
+
+func (r *entryResolver) Status() (resolvers.MergeQueueEntryStatus, error) {
+	switch r.entry.Status {
+	case mergequeue.StatusQueued:
+		return resolvers.MergeQueueEntryStatusQueued, nil
+	case mergequeue.StatusTesting:
+		return resolvers.MergeQueueEntryStatusTesting, nil
+	case mergequeue.StatusLanded:
+		return resolvers.MergeQueueEntryStatusLanded, nil
+	case mergequeue.StatusEjected:
+		return resolvers.MergeQueueEntryStatusEjected, nil
+	case mergequeue.StatusCancelled:
+		return resolvers.MergeQueueEntryStatusCancelled, nil
+	default:
+		return resolvers.MergeQueueEntryStatusUndefined, fmt.Errorf("unknown merge queue entry status: %s", r.entry.Status)
+	}
+}
+
+func (r *entryResolver) EjectReason() *string {
+	return r.entry.EjectReason
+}
+
+func (r *entryResolver) Change(ctx context.Context) (resolvers.ChangeResolver, error) {
+	if r.entry.ChangeID == nil {
+		return nil, nil
+	}
+	id := graphql.ID(*r.entry.ChangeID)
+	return (*r.root.changeRootResolver).Change(ctx, resolvers.ChangeArgs{ID: &id})
+}
+
+func (r *entryResolver) CreatedAt() int32 {
+	return int32(r.entry.CreatedAt.Unix())
+}
+
+func (r *entryResolver) CompletedAt() *int32 {
+	if r.entry.CompletedAt == nil {
+		return nil
+	}
+	t := int32(r.entry.CompletedAt.Unix())
+	return &t
+}
@@ -0,0 +1,168 @@
+package graphql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"getsturdy.com/api/pkg/auth"
+	service_auth "getsturdy.com/api/pkg/auth/service"
+	"getsturdy.com/api/pkg/codebases"
+	service_codebase "getsturdy.com/api/pkg/codebases/service"
+	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
+	"getsturdy.com/api/pkg/graphql/resolvers"
+	"getsturdy.com/api/pkg/mergequeue"
+	service_mergequeue "getsturdy.com/api/pkg/mergequeue/service"
+	worker_mergequeue "getsturdy.com/api/pkg/mergequeue/worker"
+	service_workspaces "getsturdy.com/api/pkg/workspaces/service"
+
+	"go.uber.org/zap"
+)
+
+type rootResolver struct {
+	logger *zap.Logger
+
+	mergeQueueService *service_mergequeue.Service
+	mergeQueue        *worker_mergequeue.Queue
+	workspaceService  *service_workspaces.Service
+	codebaseService   *service_codebase.Service
+	authService       *service_auth.Service
+
+	authorRootResolver    resolvers.AuthorRootResolver
+	workspaceRootResolver *resolvers.WorkspaceRootResolver
+	changeRootResolver    *resolvers.ChangeRootResolver
+}
+
+func New(
+	logger *zap.Logger,
+
+	mergeQueueService *service_mergequeue.Service,
+	mergeQueue *worker_mergequeue.Queue,
+	workspaceService *service_workspaces.Service,
+	codebaseService *service_codebase.Service,
+	authService *service_auth.Service,
+
+	authorRootResolver resolvers.AuthorRootResolver,
+	workspaceRootResolver *resolvers.WorkspaceRootResolver,
+	changeRootResolver *resolvers.ChangeRootResolver,
+) resolvers.MergeQueueRootResolver {
+	return &rootResolver{
+		logger: logger.Named("mergeQueueRootResolver"),
+
+		mergeQueueService: mergeQueueService,
+		mergeQueue:        mergeQueue,
+		workspaceService:  workspaceService,
+		codebaseService:   codebaseService,
+		authService:       authService,
+
+		authorRootResolver:    authorRootResolver,
+		workspaceRootResolver: workspaceRootResolver,
+		changeRootResolver:    changeRootResolver,
+	}
+}
+
+func (r *rootResolver) InternalMergeQueueEntry(ctx context.Context, id mergequeue.ID) (resolvers.MergeQueueEntryResolver, error) {
+	entry, err := r.mergeQueueService.GetByID(ctx, id)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+	return &entryResolver{root: r, entry: entry}, nil
+}
+
+func (r *rootResolver) InternalMergeQueueEntryByWorkspaceID(ctx context.Context, workspaceID string) (resolvers.MergeQueueEntryResolver, error) {
+	entry, err := r.mergeQueueService.GetActiveByWorkspaceID(ctx, workspaceID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, nil
+	case err != nil:
+		return nil, gqlerrors.Error(err)
+	}
+	return &entryResolver{root: r, entry: entry}, nil
+}
+
+func (r *rootResolver) MergeQueue(ctx context.Context, args resolvers.MergeQueueArgs) ([]resolvers.MergeQueueEntryResolver, error) {
+	cb, err := r.codebaseService.GetByID(ctx, codebases.ID(args.CodebaseID))
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	if err := r.authService.CanRead(ctx, cb); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	entries, err := r.mergeQueueService.ListByCodebaseID(ctx, cb.ID)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	res := make([]resolvers.MergeQueueEntryResolver, 0, len(entries))
+	for _, entry := range entries {
+		res = append(res, &entryResolver{root: r, entry: entry})
+	}
+	return res, nil
+}
+
+func (r *rootResolver) EnqueueWorkspaceForLanding(ctx context.Context, args resolvers.EnqueueWorkspaceForLandingArgs) (resolvers.MergeQueueEntryResolver, error) {
+	userID, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	ws, err := r.workspaceService.GetByID(ctx, string(args.Input.WorkspaceID))
+	if err != nil {
+		return nil, gqlerrors.Error(fmt.Errorf("failed to get workspace: %w", err))
+	}
+
+	if err := r.authService.CanLand(ctx, ws); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	entry, err := r.mergeQueueService.Enqueue(ctx, ws, userID)
+	switch {
+	case errors.Is(err, service_mergequeue.ErrAlreadyEnqueued):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "This draft is already in the merge queue")
+	case errors.Is(err, service_mergequeue.ErrArchived):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "This draft is archived")
+	case errors.Is(err, service_mergequeue.ErrStacked):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "This draft is based on another draft that has not been merged yet")
+	case err != nil:
+		return nil, gqlerrors.Error(fmt.Errorf("failed to enqueue workspace: %w", err))
+	}
+
+	if err := r.mergeQueue.Enqueue(ctx, entry.CodebaseID); err != nil {
+		r.logger.Error("failed to enqueue codebase", zap.Error(err))
+		// do not fail, the queue will be picked up later
+	}
+
+	return &entryResolver{root: r, entry: entry}, nil
+}
+
+func (r *rootResolver) DequeueWorkspaceFromLanding(ctx context.Context, args resolvers.DequeueWorkspaceFromLandingArgs) (resolvers.MergeQueueEntryResolver, error) {
+	ws, err := r.workspaceService.GetByID(ctx, string(args.Input.WorkspaceID))
+	if err != nil {
+		return nil, gqlerrors.Error(fmt.Errorf("failed to get workspace: %w", err))
+	}
+
+	if err := r.authService.CanWrite(ctx, ws); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	entry, err := r.mergeQueueService.GetActiveByWorkspaceID(ctx, ws.ID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "This draft is not in the merge queue")
+	case err != nil:
+		return nil, gqlerrors.Error(err)
+	}
+
+	entry, err = r.mergeQueueService.Cancel(ctx, entry)
+	switch {
+	case errors.Is(err, service_mergequeue.ErrNotActive):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "This draft is not in the merge queue")
+	case err != nil:
+		return nil, gqlerrors.Error(err)
+	}
+
+	return &entryResolver{root: r, entry: entry}, nil
+}
@@ -0,0 +1,61 @@
+package mergequeue
+
+import (
+	"time"
+
+	"getsturdy.com/api/pkg/changes"
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/snapshots"
+	"getsturdy.com/api/pkg/users"
+)
+
+type ID string
+
+func (id ID) String() string {
+	return string(id)
+}
+
+type Status string
+
+const (
+	StatusUndefined Status = ""
+	// StatusQueued is set when the workspace is waiting to be rebased on top of the trunk.
+	StatusQueued Status = "queued"
+	// StatusTesting is set when the workspace has been rebased, and ci has been triggered for it.
+	StatusTesting Status = "testing"
+	// StatusLanded is set when the workspace has been landed.
+	StatusLanded Status = "landed"
+	// StatusEjected is set when the workspace could not be landed, see EjectReason for details.
+	StatusEjected Status = "ejected"
+	// StatusCancelled is set when the workspace was removed from the queue by a user.
+	StatusCancelled Status = "cancelled"
+)
+
+// IsActive returns true if the entry is still in the queue.
+func (s Status) IsActive() bool {
+	return s == StatusQueued || s == StatusTesting
+}
+
+// Entry is a workspace waiting to be landed by the merge queue.
+type Entry struct {
+	ID          ID           `db:"id"`
+	CodebaseID  codebases.ID `db:"codebase_id"`
+	WorkspaceID string       `db:"workspace_id"`
+	Status      Status       `db:"status"`
+
+	// UserID is the user that added the workspace to the queue. The workspace is landed on their behalf.
+	UserID users.ID `db:"user_id"`
+
+	// SnapshotID is the snapshot of the workspace that is being tested.
+	SnapshotID *snapshots.ID `db:"snapshot_id"`
+	// TrunkCommitSHA is the trunk commit that the workspace was rebased on.
+	TrunkCommitSHA *string `db:"trunk_commit_sha"`
+	// ChangeID is set when the workspace has been landed.
+	ChangeID *changes.ID `db:"change_id"`
+	// EjectReason is set when the workspace has been ejected from the queue.
+	EjectReason *string `db:"eject_reason"`
+
+	CreatedAt   time.Time  `db:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at"`
+	CompletedAt *time.Time `db:"completed_at"`
+}
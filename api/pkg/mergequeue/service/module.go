@@ -0,0 +1,33 @@
+package service
+
+import (
+	service_ci "getsturdy.com/api/pkg/ci/service"
+	"getsturdy.com/api/pkg/di"
+	"getsturdy.com/api/pkg/logger"
+	db_mergequeue "getsturdy.com/api/pkg/mergequeue/db"
+	sender_notification "getsturdy.com/api/pkg/notification/sender"
+	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
+	service_statuses "getsturdy.com/api/pkg/statuses/service"
+	service_sync "getsturdy.com/api/pkg/sync/service"
+	service_workspaces "getsturdy.com/api/pkg/workspaces/service"
+	"getsturdy.com/api/vcs/executor"
+)
+
+func Module(c *di.Container) {
+	c.Import(logger.Module)
+	c.Import(db_mergequeue.Module)
+	c.Import(service_workspaces.Module)
+	c.Import(service_sync.Module)
+	c.Import(service_ci.Module)
+	c.Import(service_statuses.Module)
+	c.Import(service_snapshots.Module)
+	c.Import(sender_notification.Module)
+	c.Import(executor.Module)
+	c.Import(landerModule)
+	c.Register(func(s *service_workspaces.Service) WorkspaceService { return s })
+	c.Register(func(s *service_sync.Service) SyncService { return s })
+	c.Register(func(s *service_ci.Service) CIService { return s })
+	c.Register(func(s *service_statuses.Service) StatusesService { return s })
+	c.Register(func(s *service_snapshots.Service) SnapshotsService { return s })
+	c.Register(New)
+}
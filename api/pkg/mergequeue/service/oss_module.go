@@ -0,0 +1,16 @@
+//go:build !cloud && !enterprise
+// +build !cloud,!enterprise
+
+package service
+
+import (
+	"getsturdy.com/api/pkg/di"
+	service_land "getsturdy.com/api/pkg/land/service"
+)
+
+func landerModule(c *di.Container) {
+	c.Import(service_land.Module)
+	c.Register(func(landService *service_land.Service) Lander {
+		return landService
+	})
+}
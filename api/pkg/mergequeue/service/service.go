@@ -0,0 +1,436 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"getsturdy.com/api/pkg/auth"
+	"getsturdy.com/api/pkg/changes"
+	service_ci "getsturdy.com/api/pkg/ci/service"
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/integrations"
+	"getsturdy.com/api/pkg/mergequeue"
+	db_mergequeue "getsturdy.com/api/pkg/mergequeue/db"
+	"getsturdy.com/api/pkg/notification"
+	sender_notification "getsturdy.com/api/pkg/notification/sender"
+	"getsturdy.com/api/pkg/snapshots"
+	"getsturdy.com/api/pkg/statuses"
+	"getsturdy.com/api/pkg/sync"
+	"getsturdy.com/api/pkg/users"
+	"getsturdy.com/api/pkg/workspaces"
+	"getsturdy.com/api/vcs"
+	"getsturdy.com/api/vcs/executor"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrAlreadyEnqueued = errors.New("workspace is already in the merge queue")
+	ErrArchived        = errors.New("workspace is archived")
+	ErrStacked         = errors.New("workspace is based on another workspace")
+	ErrNotActive       = errors.New("merge queue entry is not active")
+)
+
+var (
+	// testingTimeout is for how long the queue waits for the statuses of a workspace to become healthy.
+	testingTimeout = 2 * time.Hour
+)
+
+// Lander lands workspaces on the trunk.
+type Lander interface {
+	LandChange(context.Context, *workspaces.Workspace, ...vcs.DiffOption) (*changes.Change, error)
+}
+
+// WorkspaceService is the subset of the workspaces service that the queue depends on.
+type WorkspaceService interface {
+	GetByID(context.Context, string) (*workspaces.Workspace, error)
+	HasConflicts(context.Context, *workspaces.Workspace) (bool, error)
+}
+
+// SyncService syncs workspaces on top of the trunk.
+type SyncService interface {
+	OnTrunk(context.Context, *workspaces.Workspace) (*sync.RebaseStatusResponse, error)
+}
+
+// CIService triggers continuous integration for workspaces.
+type CIService interface {
+	ListByCodebaseID(context.Context, codebases.ID) ([]*integrations.Integration, error)
+	TriggerWorkspace(context.Context, *workspaces.Workspace, ...service_ci.TriggerOption) ([]*statuses.Status, error)
+}
+
+// StatusesService lists statuses of commits.
+type StatusesService interface {
+	List(context.Context, codebases.ID, string) ([]*statuses.Status, error)
+}
+
+// SnapshotsService gets snapshots by id.
+type SnapshotsService interface {
+	GetByID(context.Context, snapshots.ID) (*snapshots.Snapshot, error)
+}
+
+type Service struct {
+	logger *zap.Logger
+	repo   db_mergequeue.Repository
+
+	workspaceService   WorkspaceService
+	syncService        SyncService
+	ciService          CIService
+	statusesService    StatusesService
+	snapshotsService   SnapshotsService
+	lander             Lander
+	notificationSender sender_notification.NotificationSender
+	executorProvider   executor.Provider
+}
+
+func New(
+	logger *zap.Logger,
+	repo db_mergequeue.Repository,
+
+	workspaceService WorkspaceService,
+	syncService SyncService,
+	ciService CIService,
+	statusesService StatusesService,
+	snapshotsService SnapshotsService,
+	lander Lander,
+	notificationSender sender_notification.NotificationSender,
+	executorProvider executor.Provider,
+) *Service {
+	return &Service{
+		logger: logger.Named("mergeQueueService"),
+		repo:   repo,
+
+		workspaceService:   workspaceService,
+		syncService:        syncService,
+		ciService:          ciService,
+		statusesService:    statusesService,
+		snapshotsService:   snapshotsService,
+		lander:             lander,
+		notificationSender: notificationSender,
+		executorProvider:   executorProvider,
+	}
+}
+
+func (s *Service) GetByID(ctx context.Context, id mergequeue.ID) (*mergequeue.Entry, error) {
+	return s.repo.Get(ctx, id)
+}
+
+// GetActiveByWorkspaceID returns the entry of the workspace, if it's currently in the queue.
+func (s *Service) GetActiveByWorkspaceID(ctx context.Context, workspaceID string) (*mergequeue.Entry, error) {
+	return s.repo.GetActiveByWorkspaceID(ctx, workspaceID)
+}
+
+// ListByCodebaseID returns all entries that are currently in the queue of the codebase, in the order
+// that they will be landed.
+func (s *Service) ListByCodebaseID(ctx context.Context, codebaseID codebases.ID) ([]*mergequeue.Entry, error) {
+	return s.repo.ListActiveByCodebaseID(ctx, codebaseID)
+}
+
+// ListActiveCodebaseIDs returns ids of all codebases that have a non-empty merge queue.
+func (s *Service) ListActiveCodebaseIDs(ctx context.Context) ([]codebases.ID, error) {
+	return s.repo.ListActiveCodebaseIDs(ctx)
+}
+
+// Enqueue adds the workspace to the end of the merge queue of its codebase.
+func (s *Service) Enqueue(ctx context.Context, ws *workspaces.Workspace, userID users.ID) (*mergequeue.Entry, error) {
+	if ws.ArchivedAt != nil {
+		return nil, ErrArchived
+	}
+	if ws.IsStacked() {
+		return nil, ErrStacked
+	}
+
+	switch _, err := s.repo.GetActiveByWorkspaceID(ctx, ws.ID); {
+	case err == nil:
+		return nil, ErrAlreadyEnqueued
+	case errors.Is(err, sql.ErrNoRows):
+	default:
+		return nil, fmt.Errorf("failed to get entry: %w", err)
+	}
+
+	now := time.Now()
+	entry := &mergequeue.Entry{
+		ID:          mergequeue.ID(uuid.NewString()),
+		CodebaseID:  ws.CodebaseID,
+		WorkspaceID: ws.ID,
+		UserID:      userID,
+		Status:      mergequeue.StatusQueued,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.repo.Create(ctx, entry); err != nil {
+		return nil, fmt.Errorf("failed to create entry: %w", err)
+	}
+	return entry, nil
+}
+
+// Cancel removes the entry from the queue.
+func (s *Service) Cancel(ctx context.Context, entry *mergequeue.Entry) (*mergequeue.Entry, error) {
+	unlock, err := s.repo.LockCodebase(ctx, entry.CodebaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock merge queue: %w", err)
+	}
+	defer unlock()
+
+	// re-read the entry, it might have been landed while we were waiting for the lock
+	entry, err = s.repo.Get(ctx, entry.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entry: %w", err)
+	}
+
+	if !entry.Status.IsActive() {
+		return nil, ErrNotActive
+	}
+
+	if err := s.complete(ctx, entry, mergequeue.StatusCancelled); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Process moves the merge queue of the codebase forward.
+//
+// Workspaces are landed one at a time, in the order that they were enqueued. The first workspace in the queue is
+// synced on top of the current trunk, and ci is triggered for the result. Once all statuses of the
+// synced workspace are healthy, it's landed, and the next workspace in the queue is processed. If the workspace
+// conflicts with the trunk, or if any of the statuses is failing, the workspace is ejected from the queue.
+//
+// Each workspace is synced and landed on behalf of the user that added it to the queue.
+//
+// Process returns when the queue is empty, or when the first workspace in the queue is waiting for ci.
+func (s *Service) Process(ctx context.Context, codebaseID codebases.ID) error {
+	unlock, err := s.repo.LockCodebase(ctx, codebaseID)
+	if err != nil {
+		return fmt.Errorf("failed to lock merge queue: %w", err)
+	}
+	defer unlock()
+
+	for {
+		entries, err := s.repo.ListActiveByCodebaseID(ctx, codebaseID)
+		if err != nil {
+			return fmt.Errorf("failed to list entries: %w", err)
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+
+		waiting, err := s.process(auth.NewUserContext(ctx, entries[0].UserID), entries[0])
+		if err != nil {
+			return fmt.Errorf("failed to process entry %s: %w", entries[0].ID, err)
+		}
+		if waiting {
+			return nil
+		}
+	}
+}
+
+// process makes a single step forward for the entry. It returns true if the entry is waiting for ci.
+func (s *Service) process(ctx context.Context, entry *mergequeue.Entry) (bool, error) {
+	ws, err := s.workspaceService.GetByID(ctx, entry.WorkspaceID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get workspace: %w", err)
+	}
+
+	if ws.ArchivedAt != nil {
+		// the workspace was landed outside of the queue
+		if ws.ChangeID != nil {
+			entry.ChangeID = ws.ChangeID
+			return false, s.complete(ctx, entry, mergequeue.StatusLanded)
+		}
+		return false, s.eject(ctx, entry, "The draft was archived")
+	}
+
+	switch entry.Status {
+	case mergequeue.StatusQueued:
+		return s.start(ctx, entry, ws)
+	case mergequeue.StatusTesting:
+		return s.check(ctx, entry, ws)
+	default:
+		return false, fmt.Errorf("unexpected status: %s", entry.Status)
+	}
+}
+
+// start syncs the workspace on top of the trunk, and triggers ci for it.
+func (s *Service) start(ctx context.Context, entry *mergequeue.Entry, ws *workspaces.Workspace) (bool, error) {
+	logger := s.logger.With(zap.Stringer("entry_id", entry.ID), zap.String("workspace_id", ws.ID))
+
+	if ws.IsStacked() {
+		return false, s.eject(ctx, entry, "The draft is based on another draft that has not been merged yet")
+	}
+
+	// check for conflicts first, to not leave the workspace in a conflicting state
+	hasConflicts, err := s.workspaceService.HasConflicts(ctx, ws)
+	if err != nil {
+		return false, fmt.Errorf("failed to check for conflicts: %w", err)
+	}
+	if hasConflicts {
+		return false, s.eject(ctx, entry, "The draft conflicts with the trunk")
+	}
+
+	trunkCommitSHA, err := s.trunkHeadCommitSHA(ws.CodebaseID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get trunk head: %w", err)
+	}
+
+	// the trunk is empty, there is nothing to sync with
+	if trunkCommitSHA != "" {
+		rebaseStatus, err := s.syncService.OnTrunk(ctx, ws)
+		if err != nil {
+			logger.Error("failed to sync workspace", zap.Error(err))
+			return false, s.eject(ctx, entry, "Failed to sync the draft with the trunk")
+		}
+		if rebaseStatus.HaveConflicts {
+			return false, s.eject(ctx, entry, "The draft conflicts with the trunk")
+		}
+	}
+
+	// get the workspace again, syncing creates a new snapshot
+	ws, err = s.workspaceService.GetByID(ctx, ws.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get workspace: %w", err)
+	}
+	if ws.LatestSnapshotID == nil {
+		return false, s.eject(ctx, entry, "The draft has no changes")
+	}
+
+	integrations, err := s.ciService.ListByCodebaseID(ctx, ws.CodebaseID)
+	if err != nil {
+		return false, fmt.Errorf("failed to list integrations: %w", err)
+	}
+
+	// no ci configured, land right away
+	if len(integrations) == 0 {
+		logger.Info("no integrations configured, landing")
+		return false, s.land(ctx, entry, ws)
+	}
+
+	triggerOptions := make([]service_ci.TriggerOption, 0, len(integrations))
+	for _, integration := range integrations {
+		triggerOptions = append(triggerOptions, service_ci.WithProvider(integration.Provider))
+	}
+
+	if _, err := s.ciService.TriggerWorkspace(ctx, ws, triggerOptions...); err != nil {
+		logger.Error("failed to trigger ci", zap.Error(err))
+		return false, s.eject(ctx, entry, "Failed to trigger continuous integration")
+	}
+
+	entry.Status = mergequeue.StatusTesting
+	entry.SnapshotID = ws.LatestSnapshotID
+	entry.TrunkCommitSHA = &trunkCommitSHA
+	entry.UpdatedAt = time.Now()
+	if err := s.repo.Update(ctx, entry); err != nil {
+		return false, fmt.Errorf("failed to update entry: %w", err)
+	}
+
+	logger.Info("waiting for statuses")
+
+	return true, nil
+}
+
+// check lands the workspace if all of the statuses are healthy.
+func (s *Service) check(ctx context.Context, entry *mergequeue.Entry, ws *workspaces.Workspace) (bool, error) {
+	logger := s.logger.With(zap.Stringer("entry_id", entry.ID), zap.String("workspace_id", ws.ID))
+
+	trunkCommitSHA, err := s.trunkHeadCommitSHA(ws.CodebaseID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get trunk head: %w", err)
+	}
+
+	// the workspace, or the trunk has changed since the workspace was tested, start over
+	if entry.SnapshotID == nil || ws.LatestSnapshotID == nil || *entry.SnapshotID != *ws.LatestSnapshotID ||
+		entry.TrunkCommitSHA == nil || *entry.TrunkCommitSHA != trunkCommitSHA {
+		logger.Info("workspace or trunk changed during testing, restarting")
+		entry.Status = mergequeue.StatusQueued
+		entry.SnapshotID = nil
+		entry.TrunkCommitSHA = nil
+		entry.UpdatedAt = time.Now()
+		if err := s.repo.Update(ctx, entry); err != nil {
+			return false, fmt.Errorf("failed to update entry: %w", err)
+		}
+		return false, nil
+	}
+
+	snapshot, err := s.snapshotsService.GetByID(ctx, *entry.SnapshotID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get snapshot: %w", err)
+	}
+
+	statusList, err := s.statusesService.List(ctx, ws.CodebaseID, snapshot.CommitSHA)
+	if err != nil {
+		return false, fmt.Errorf("failed to list statuses: %w", err)
+	}
+
+	pending := len(statusList) == 0
+	for _, status := range statusList {
+		switch status.Type {
+		case statuses.TypeFailing:
+			return false, s.eject(ctx, entry, fmt.Sprintf("%s is failing", status.Title))
+		case statuses.TypePending:
+			pending = true
+		}
+	}
+
+	if pending {
+		if time.Since(entry.UpdatedAt) > testingTimeout {
+			return false, s.eject(ctx, entry, "Timed out waiting for statuses")
+		}
+		return true, nil
+	}
+
+	return false, s.land(ctx, entry, ws)
+}
+
+func (s *Service) land(ctx context.Context, entry *mergequeue.Entry, ws *workspaces.Workspace) error {
+	change, err := s.lander.LandChange(ctx, ws)
+	if err != nil {
+		s.logger.Error("failed to land workspace", zap.Stringer("entry_id", entry.ID), zap.Error(err))
+		return s.eject(ctx, entry, "Failed to merge the draft")
+	}
+
+	entry.ChangeID = &change.ID
+	return s.complete(ctx, entry, mergequeue.StatusLanded)
+}
+
+func (s *Service) eject(ctx context.Context, entry *mergequeue.Entry, reason string) error {
+	entry.EjectReason = &reason
+	return s.complete(ctx, entry, mergequeue.StatusEjected)
+}
+
+func (s *Service) complete(ctx context.Context, entry *mergequeue.Entry, status mergequeue.Status) error {
+	now := time.Now()
+	entry.Status = status
+	entry.UpdatedAt = now
+	entry.CompletedAt = &now
+	if err := s.repo.Update(ctx, entry); err != nil {
+		return fmt.Errorf("failed to update entry: %w", err)
+	}
+
+	if status == mergequeue.StatusCancelled {
+		return nil
+	}
+
+	if err := s.notificationSender.User(ctx, entry.UserID, notification.MergeQueueNotificationType, entry.ID.String()); err != nil {
+		s.logger.Error("failed to send notification", zap.Stringer("entry_id", entry.ID), zap.Error(err))
+		// do not fail
+	}
+
+	return nil
+}
+
+// trunkHeadCommitSHA returns the current head of the trunk, or an empty string if the trunk has no commits.
+func (s *Service) trunkHeadCommitSHA(codebaseID codebases.ID) (string, error) {
+	var commitSHA string
+	if err := s.executorProvider.New().GitRead(func(repo vcs.RepoGitReader) error {
+		// If sturdytrunk doesn't exist (such as when an empty repository has been imported), there is no head
+		if id, err := repo.BranchCommitID("sturdytrunk"); err == nil {
+			commitSHA = id
+		}
+		return nil
+	}).ExecTrunk(codebaseID, "mergeQueueTrunkHead"); err != nil {
+		return "", err
+	}
+	return commitSHA, nil
+}
@@ -0,0 +1,355 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"getsturdy.com/api/pkg/auth"
+	"getsturdy.com/api/pkg/changes"
+	service_ci "getsturdy.com/api/pkg/ci/service"
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/integrations"
+	"getsturdy.com/api/pkg/integrations/providers"
+	"getsturdy.com/api/pkg/mergequeue"
+	db_mergequeue "getsturdy.com/api/pkg/mergequeue/db"
+	"getsturdy.com/api/pkg/notification/sender"
+	"getsturdy.com/api/pkg/snapshots"
+	"getsturdy.com/api/pkg/statuses"
+	"getsturdy.com/api/pkg/sync"
+	"getsturdy.com/api/pkg/users"
+	"getsturdy.com/api/pkg/workspaces"
+	"getsturdy.com/api/vcs"
+	"getsturdy.com/api/vcs/executor"
+	"getsturdy.com/api/vcs/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeWorkspaces struct {
+	byID      map[string]*workspaces.Workspace
+	conflicts map[string]bool
+}
+
+func (f *fakeWorkspaces) GetByID(_ context.Context, id string) (*workspaces.Workspace, error) {
+	ws, found := f.byID[id]
+	if !found {
+		return nil, fmt.Errorf("workspace %s not found", id)
+	}
+	cp := *ws
+	return &cp, nil
+}
+
+func (f *fakeWorkspaces) HasConflicts(_ context.Context, ws *workspaces.Workspace) (bool, error) {
+	return f.conflicts[ws.ID], nil
+}
+
+type fakeSync struct {
+	synced []string
+}
+
+func (f *fakeSync) OnTrunk(_ context.Context, ws *workspaces.Workspace) (*sync.RebaseStatusResponse, error) {
+	f.synced = append(f.synced, ws.ID)
+	return &sync.RebaseStatusResponse{}, nil
+}
+
+type fakeCI struct {
+	integrations []*integrations.Integration
+	triggered    []string
+}
+
+func (f *fakeCI) ListByCodebaseID(context.Context, codebases.ID) ([]*integrations.Integration, error) {
+	return f.integrations, nil
+}
+
+func (f *fakeCI) TriggerWorkspace(_ context.Context, ws *workspaces.Workspace, _ ...service_ci.TriggerOption) ([]*statuses.Status, error) {
+	f.triggered = append(f.triggered, ws.ID)
+	return nil, nil
+}
+
+type fakeStatuses struct {
+	byCommitSHA map[string][]*statuses.Status
+}
+
+func (f *fakeStatuses) List(_ context.Context, _ codebases.ID, commitSHA string) ([]*statuses.Status, error) {
+	return f.byCommitSHA[commitSHA], nil
+}
+
+type fakeSnapshots struct{}
+
+func (fakeSnapshots) GetByID(_ context.Context, id snapshots.ID) (*snapshots.Snapshot, error) {
+	return &snapshots.Snapshot{ID: id, CommitSHA: "commit-" + string(id)}, nil
+}
+
+type landed struct {
+	workspaceID string
+	userID      users.ID
+}
+
+type fakeLander struct {
+	landed []landed
+	err    error
+	// notAllowed are the users that are not allowed to land
+	notAllowed map[users.ID]bool
+}
+
+func (f *fakeLander) LandChange(ctx context.Context, ws *workspaces.Workspace, _ ...vcs.DiffOption) (*changes.Change, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	userID, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if f.notAllowed[userID] {
+		return nil, errors.New("not allowed to land")
+	}
+	f.landed = append(f.landed, landed{workspaceID: ws.ID, userID: userID})
+	return &changes.Change{ID: changes.ID("change-" + ws.ID)}, nil
+}
+
+type testCase struct {
+	service *Service
+
+	workspaces *fakeWorkspaces
+	sync       *fakeSync
+	ci         *fakeCI
+	statuses   *fakeStatuses
+	lander     *fakeLander
+
+	codebaseID codebases.ID
+}
+
+func newTestService() *Service {
+	return New(zap.NewNop(), db_mergequeue.NewMemory(), nil, nil, nil, nil, nil, nil, sender.NewNoopNotificationSender(), nil)
+}
+
+func setup(t *testing.T) *testCase {
+	repoProvider := testutil.TestingRepoProvider(t)
+	codebaseID := codebases.ID("cb-1")
+	_, err := vcs.CreateBareRepoWithRootCommit(repoProvider.TrunkPath(codebaseID))
+	require.NoError(t, err)
+
+	tc := &testCase{
+		workspaces: &fakeWorkspaces{byID: map[string]*workspaces.Workspace{}, conflicts: map[string]bool{}},
+		sync:       &fakeSync{},
+		ci:         &fakeCI{},
+		statuses:   &fakeStatuses{byCommitSHA: map[string][]*statuses.Status{}},
+		lander:     &fakeLander{},
+		codebaseID: codebaseID,
+	}
+	tc.service = New(
+		zap.NewNop(),
+		db_mergequeue.NewMemory(),
+		tc.workspaces,
+		tc.sync,
+		tc.ci,
+		tc.statuses,
+		fakeSnapshots{},
+		tc.lander,
+		sender.NewNoopNotificationSender(),
+		executor.NewProvider(zap.NewNop(), repoProvider),
+	)
+	return tc
+}
+
+// enqueue creates a workspace with a snapshot, and adds it to the queue as the user.
+func (tc *testCase) enqueue(t *testing.T, workspaceID string, userID users.ID) *mergequeue.Entry {
+	snapshotID := snapshots.ID("snapshot-" + workspaceID)
+	ws := &workspaces.Workspace{ID: workspaceID, CodebaseID: tc.codebaseID, UserID: "author", LatestSnapshotID: &snapshotID}
+	tc.workspaces.byID[workspaceID] = ws
+
+	entry, err := tc.service.Enqueue(context.Background(), ws, userID)
+	require.NoError(t, err)
+	return entry
+}
+
+func (tc *testCase) get(t *testing.T, entry *mergequeue.Entry) *mergequeue.Entry {
+	entry, err := tc.service.GetByID(context.Background(), entry.ID)
+	require.NoError(t, err)
+	return entry
+}
+
+func TestEnqueue(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService()
+
+	ws := &workspaces.Workspace{ID: "ws-1", CodebaseID: codebases.ID("cb-1")}
+	userID := users.ID("user-1")
+
+	entry, err := svc.Enqueue(ctx, ws, userID)
+	require.NoError(t, err)
+	assert.Equal(t, mergequeue.StatusQueued, entry.Status)
+
+	_, err = svc.Enqueue(ctx, ws, userID)
+	assert.ErrorIs(t, err, ErrAlreadyEnqueued)
+
+	entries, err := svc.ListByCodebaseID(ctx, ws.CodebaseID)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, entry.ID, entries[0].ID)
+
+	cancelled, err := svc.Cancel(ctx, entry)
+	require.NoError(t, err)
+	assert.Equal(t, mergequeue.StatusCancelled, cancelled.Status)
+	assert.NotNil(t, cancelled.CompletedAt)
+
+	_, err = svc.Cancel(ctx, entry)
+	assert.ErrorIs(t, err, ErrNotActive)
+
+	// can be enqueued again after being cancelled
+	_, err = svc.Enqueue(ctx, ws, userID)
+	assert.NoError(t, err)
+}
+
+func TestEnqueue_stacked(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService()
+
+	baseID := "ws-base"
+	ws := &workspaces.Workspace{ID: "ws-1", CodebaseID: codebases.ID("cb-1"), BaseWorkspaceID: &baseID}
+
+	_, err := svc.Enqueue(ctx, ws, users.ID("user-1"))
+	assert.ErrorIs(t, err, ErrStacked)
+}
+
+func TestProcess_lands_in_order(t *testing.T) {
+	ctx := context.Background()
+	tc := setup(t)
+
+	first := tc.enqueue(t, "ws-1", "user-1")
+	second := tc.enqueue(t, "ws-2", "user-2")
+
+	require.NoError(t, tc.service.Process(ctx, tc.codebaseID))
+
+	// each workspace is landed as the user that enqueued it, not as the author of the workspace
+	assert.Equal(t, []landed{
+		{workspaceID: "ws-1", userID: "user-1"},
+		{workspaceID: "ws-2", userID: "user-2"},
+	}, tc.lander.landed)
+	assert.Equal(t, []string{"ws-1", "ws-2"}, tc.sync.synced)
+
+	for _, entry := range []*mergequeue.Entry{first, second} {
+		entry = tc.get(t, entry)
+		assert.Equal(t, mergequeue.StatusLanded, entry.Status)
+		if assert.NotNil(t, entry.ChangeID) {
+			assert.Equal(t, changes.ID("change-"+entry.WorkspaceID), *entry.ChangeID)
+		}
+		assert.NotNil(t, entry.CompletedAt)
+	}
+}
+
+func TestProcess_waits_for_statuses(t *testing.T) {
+	ctx := context.Background()
+	tc := setup(t)
+	tc.ci.integrations = []*integrations.Integration{{ID: "integration-1", Provider: providers.ProviderNameBuildkite}}
+
+	first := tc.enqueue(t, "ws-1", "user-1")
+	second := tc.enqueue(t, "ws-2", "user-1")
+
+	// ci is triggered for the first workspace only
+	require.NoError(t, tc.service.Process(ctx, tc.codebaseID))
+	assert.Equal(t, []string{"ws-1"}, tc.ci.triggered)
+	assert.Equal(t, mergequeue.StatusTesting, tc.get(t, first).Status)
+	assert.Equal(t, mergequeue.StatusQueued, tc.get(t, second).Status)
+
+	// the status is pending, nothing is landed
+	tc.statuses.byCommitSHA["commit-snapshot-ws-1"] = []*statuses.Status{{Type: statuses.TypePending, Title: "build"}}
+	require.NoError(t, tc.service.Process(ctx, tc.codebaseID))
+	assert.Empty(t, tc.lander.landed)
+	assert.Equal(t, mergequeue.StatusTesting, tc.get(t, first).Status)
+
+	// the status is healthy, the first workspace is landed, and the second is tested
+	tc.statuses.byCommitSHA["commit-snapshot-ws-1"] = []*statuses.Status{{Type: statuses.TypeHealthy, Title: "build"}}
+	require.NoError(t, tc.service.Process(ctx, tc.codebaseID))
+	assert.Equal(t, []landed{{workspaceID: "ws-1", userID: "user-1"}}, tc.lander.landed)
+	assert.Equal(t, mergequeue.StatusLanded, tc.get(t, first).Status)
+	assert.Equal(t, []string{"ws-1", "ws-2"}, tc.ci.triggered)
+	assert.Equal(t, mergequeue.StatusTesting, tc.get(t, second).Status)
+}
+
+func TestProcess_ejects_failing(t *testing.T) {
+	ctx := context.Background()
+	tc := setup(t)
+	tc.ci.integrations = []*integrations.Integration{{ID: "integration-1", Provider: providers.ProviderNameBuildkite}}
+
+	first := tc.enqueue(t, "ws-1", "user-1")
+	second := tc.enqueue(t, "ws-2", "user-1")
+
+	require.NoError(t, tc.service.Process(ctx, tc.codebaseID))
+
+	tc.statuses.byCommitSHA["commit-snapshot-ws-1"] = []*statuses.Status{
+		{Type: statuses.TypeHealthy, Title: "lint"},
+		{Type: statuses.TypeFailing, Title: "build"},
+	}
+	require.NoError(t, tc.service.Process(ctx, tc.codebaseID))
+
+	first = tc.get(t, first)
+	assert.Equal(t, mergequeue.StatusEjected, first.Status)
+	if assert.NotNil(t, first.EjectReason) {
+		assert.Equal(t, "build is failing", *first.EjectReason)
+	}
+	assert.Empty(t, tc.lander.landed)
+
+	// the next workspace in the queue is tested
+	assert.Equal(t, mergequeue.StatusTesting, tc.get(t, second).Status)
+}
+
+func TestProcess_ejects_conflicting(t *testing.T) {
+	ctx := context.Background()
+	tc := setup(t)
+
+	first := tc.enqueue(t, "ws-1", "user-1")
+	second := tc.enqueue(t, "ws-2", "user-1")
+	tc.workspaces.conflicts["ws-1"] = true
+
+	require.NoError(t, tc.service.Process(ctx, tc.codebaseID))
+
+	first = tc.get(t, first)
+	assert.Equal(t, mergequeue.StatusEjected, first.Status)
+	if assert.NotNil(t, first.EjectReason) {
+		assert.Equal(t, "The draft conflicts with the trunk", *first.EjectReason)
+	}
+	assert.Equal(t, []string{"ws-2"}, tc.sync.synced)
+	assert.Equal(t, mergequeue.StatusLanded, tc.get(t, second).Status)
+}
+
+func TestProcess_ejects_when_land_fails(t *testing.T) {
+	ctx := context.Background()
+	tc := setup(t)
+	tc.lander.err = errors.New("not allowed")
+
+	entry := tc.enqueue(t, "ws-1", "user-1")
+
+	require.NoError(t, tc.service.Process(ctx, tc.codebaseID))
+
+	entry = tc.get(t, entry)
+	assert.Equal(t, mergequeue.StatusEjected, entry.Status)
+	if assert.NotNil(t, entry.EjectReason) {
+		assert.Equal(t, "Failed to merge the draft", *entry.EjectReason)
+	}
+}
+
+func TestProcess_ejects_when_enqueuer_is_not_allowed_to_land(t *testing.T) {
+	ctx := context.Background()
+	tc := setup(t)
+
+	first := tc.enqueue(t, "ws-1", "user-1")
+	second := tc.enqueue(t, "ws-2", "user-2")
+
+	// user-1 is no longer allowed to land once the workspace is at the front of the queue
+	tc.lander.notAllowed = map[users.ID]bool{"user-1": true}
+
+	require.NoError(t, tc.service.Process(ctx, tc.codebaseID))
+
+	first = tc.get(t, first)
+	assert.Equal(t, mergequeue.StatusEjected, first.Status)
+	if assert.NotNil(t, first.EjectReason) {
+		assert.Equal(t, "Failed to merge the draft", *first.EjectReason)
+	}
+	assert.Equal(t, []landed{{workspaceID: "ws-2", userID: "user-2"}}, tc.lander.landed)
+	assert.Equal(t, mergequeue.StatusLanded, tc.get(t, second).Status)
+}
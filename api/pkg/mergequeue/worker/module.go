@@ -0,0 +1,15 @@
+package worker
+
+import (
+	"getsturdy.com/api/pkg/di"
+	"getsturdy.com/api/pkg/logger"
+	service_mergequeue "getsturdy.com/api/pkg/mergequeue/service"
+	queue "getsturdy.com/api/pkg/queue/module"
+)
+
+func Module(c *di.Container) {
+	c.Import(logger.Module)
+	c.Import(queue.Module)
+	c.Import(service_mergequeue.Module)
+	c.Register(New)
+}
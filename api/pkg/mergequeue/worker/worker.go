@@ -0,0 +1,131 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/mergequeue/service"
+	"getsturdy.com/api/pkg/queue"
+	"getsturdy.com/api/pkg/queue/names"
+)
+
+var (
+	// pollEvery is how often the queues that are waiting for statuses are checked.
+	pollEvery = 30 * time.Second
+)
+
+type MergeQueueEntry struct {
+	CodebaseID codebases.ID `json:"codebase_id"`
+}
+
+type Queue struct {
+	logger *zap.Logger
+	queue  queue.Queue
+	name   names.IncompleteQueueName
+
+	service *service.Service
+}
+
+func New(
+	logger *zap.Logger,
+	queue queue.Queue,
+	service *service.Service,
+) *Queue {
+	return &Queue{
+		logger:  logger.Named("mergeQueue"),
+		queue:   queue,
+		name:    names.CodebaseMergeQueue,
+		service: service,
+	}
+}
+
+// Enqueue schedules processing of the merge queue of the codebase.
+func (q *Queue) Enqueue(ctx context.Context, codebaseID codebases.ID) error {
+	if err := q.queue.Publish(ctx, q.name, &MergeQueueEntry{
+		CodebaseID: codebaseID,
+	}); err != nil {
+		return fmt.Errorf("could not publish to queue: %w", err)
+	}
+	return nil
+}
+
+func (q *Queue) Start(ctx context.Context) error {
+	wg, ctx := errgroup.WithContext(ctx)
+	wg.Go(func() error {
+		return q.subscribe(ctx)
+	})
+	wg.Go(func() error {
+		return q.poll(ctx)
+	})
+	return wg.Wait()
+}
+
+// poll periodically enqueues all codebases with a non-empty merge queue, to pick up status changes.
+func (q *Queue) poll(ctx context.Context) error {
+	ticker := time.NewTicker(pollEvery)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			codebaseIDs, err := q.service.ListActiveCodebaseIDs(ctx)
+			if err != nil {
+				q.logger.Error("failed to list codebases", zap.Error(err))
+				continue
+			}
+			for _, codebaseID := range codebaseIDs {
+				if err := q.Enqueue(ctx, codebaseID); err != nil {
+					q.logger.Error("failed to enqueue codebase", zap.Stringer("codebase_id", codebaseID), zap.Error(err))
+				}
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (q *Queue) subscribe(ctx context.Context) error {
+	messages := make(chan queue.Message)
+	go func() {
+		defer func() {
+			if rec := recover(); rec != nil {
+				q.logger.Error("panic in runner", zap.String("panic", fmt.Sprintf("%v", rec)))
+			}
+		}()
+
+		for msg := range messages {
+			t0 := time.Now()
+
+			m := &MergeQueueEntry{}
+			if err := msg.As(m); err != nil {
+				q.logger.Error("failed to decode message", zap.Error(err))
+				continue
+			}
+			logger := q.logger.With(zap.Stringer("codebase_id", m.CodebaseID))
+
+			if err := q.service.Process(context.Background(), m.CodebaseID); err != nil {
+				logger.Error("failed to process merge queue", zap.Error(err))
+				continue
+			}
+
+			if err := msg.Ack(); err != nil {
+				logger.Error("failed to ack message", zap.Error(err))
+				continue
+			}
+
+			logger.Info("merge queue processed", zap.Duration("duration", time.Since(t0)))
+		}
+	}()
+
+	q.logger.Info("starting queue", zap.Stringer("queue_name", q.name))
+	if err := q.queue.Subscribe(ctx, q.name, messages); err != nil {
+		return fmt.Errorf("could not subscribe to queue: %w", err)
+	}
+	q.logger.Info("queue stoped", zap.Stringer("queue_name", q.name))
+
+	return nil
+}
@@ -54,6 +54,7 @@ func TestListAllows(t *testing.T) {
 		nil,
 		aclProvider,
 		nil,
+		nil,
 	)
 
 	type listAllowsResponse struct {
@@ -0,0 +1,51 @@
+package chat
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/users"
+)
+
+// Integration is an incoming webhook of a Slack or Mattermost channel that notifications about a codebase are
+// posted to.
+type Integration struct {
+	CodebaseID codebases.ID `db:"codebase_id"`
+	URL        string       `db:"url"`
+	UpdatedBy  users.ID     `db:"updated_by"`
+	UpdatedAt  time.Time    `db:"updated_at"`
+}
+
+// Message is the body that is posted to an incoming webhook. Slack and Mattermost both format the text as markdown,
+// and Mattermost translates Slack style links.
+type Message struct {
+	Text string `json:"text"`
+}
+
+var escaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// Escape escapes the characters that have a special meaning in messages.
+func Escape(s string) string {
+	return escaper.Replace(s)
+}
+
+// Link returns a link to url with the text.
+func Link(url, text string) string {
+	return fmt.Sprintf("<%s|%s>", url, Escape(text))
+}
+
+// Bold returns the text in bold.
+func Bold(text string) string {
+	return fmt.Sprintf("*%s*", Escape(text))
+}
+
+// Quote returns the text as a quote, on its own lines.
+func Quote(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	for i, line := range lines {
+		lines[i] = "> " + Escape(line)
+	}
+	return strings.Join(lines, "\n")
+}
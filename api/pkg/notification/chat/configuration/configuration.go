@@ -0,0 +1,7 @@
+package configuration
+
+import "getsturdy.com/api/pkg/configuration/flags"
+
+type Configuration struct {
+	AppURL flags.URL `long:"app-url" description:"Public URL of the web app, chat notifications link to it" default:"https://getsturdy.com"`
+}
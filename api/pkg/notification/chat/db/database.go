@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/notification/chat"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var _ Repository = &database{}
+
+type database struct {
+	db *sqlx.DB
+}
+
+func NewDatabase(db *sqlx.DB) Repository {
+	return &database{db: db}
+}
+
+func (d *database) Get(ctx context.Context, codebaseID codebases.ID) (*chat.Integration, error) {
+	var i chat.Integration
+	if err := d.db.GetContext(ctx, &i, `
+		SELECT codebase_id, url, updated_by, updated_at
+		FROM chat_integrations
+		WHERE codebase_id = $1
+	`, codebaseID); err != nil {
+		return nil, fmt.Errorf("failed to get chat integration: %w", err)
+	}
+	return &i, nil
+}
+
+func (d *database) Upsert(ctx context.Context, i *chat.Integration) error {
+	if _, err := d.db.NamedExecContext(ctx, `
+		INSERT INTO chat_integrations
+			(codebase_id, url, updated_by, updated_at)
+		VALUES
+			(:codebase_id, :url, :updated_by, :updated_at)
+		ON CONFLICT (codebase_id) DO UPDATE
+		SET
+			url = :url,
+			updated_by = :updated_by,
+			updated_at = :updated_at
+	`, i); err != nil {
+		return fmt.Errorf("failed to upsert chat integration: %w", err)
+	}
+	return nil
+}
+
+func (d *database) Delete(ctx context.Context, codebaseID codebases.ID) error {
+	if _, err := d.db.ExecContext(ctx, `DELETE FROM chat_integrations WHERE codebase_id = $1`, codebaseID); err != nil {
+		return fmt.Errorf("failed to delete chat integration: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/notification/chat"
+)
+
+var _ Repository = &memory{}
+
+type memory struct {
+	mu           sync.RWMutex
+	byCodebaseID map[codebases.ID]chat.Integration
+}
+
+func NewMemory() Repository {
+	return &memory{
+		byCodebaseID: make(map[codebases.ID]chat.Integration),
+	}
+}
+
+func (m *memory) Get(_ context.Context, codebaseID codebases.ID) (*chat.Integration, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	i, found := m.byCodebaseID[codebaseID]
+	if !found {
+		return nil, sql.ErrNoRows
+	}
+	return &i, nil
+}
+
+func (m *memory) Upsert(_ context.Context, i *chat.Integration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.byCodebaseID[i.CodebaseID] = *i
+	return nil
+}
+
+func (m *memory) Delete(_ context.Context, codebaseID codebases.ID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.byCodebaseID, codebaseID)
+	return nil
+}
@@ -0,0 +1,15 @@
+package db
+
+import (
+	"context"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/notification/chat"
+)
+
+type Repository interface {
+	// Get returns sql.ErrNoRows if the codebase has no chat integration.
+	Get(context.Context, codebases.ID) (*chat.Integration, error)
+	Upsert(context.Context, *chat.Integration) error
+	Delete(context.Context, codebases.ID) error
+}
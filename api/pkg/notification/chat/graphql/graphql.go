@@ -0,0 +1,150 @@
+package graphql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"getsturdy.com/api/pkg/auth"
+	service_auth "getsturdy.com/api/pkg/auth/service"
+	"getsturdy.com/api/pkg/codebases"
+	service_codebase "getsturdy.com/api/pkg/codebases/service"
+	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
+	"getsturdy.com/api/pkg/graphql/resolvers"
+	"getsturdy.com/api/pkg/notification/chat"
+	service_chat "getsturdy.com/api/pkg/notification/chat/service"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+type rootResolver struct {
+	chatService     *service_chat.Service
+	codebaseService *service_codebase.Service
+	authService     *service_auth.Service
+
+	codebaseRootResolver *resolvers.CodebaseRootResolver
+}
+
+func New(
+	chatService *service_chat.Service,
+	codebaseService *service_codebase.Service,
+	authService *service_auth.Service,
+
+	codebaseRootResolver *resolvers.CodebaseRootResolver,
+) resolvers.ChatIntegrationRootResolver {
+	return &rootResolver{
+		chatService:     chatService,
+		codebaseService: codebaseService,
+		authService:     authService,
+
+		codebaseRootResolver: codebaseRootResolver,
+	}
+}
+
+// authorize checks that the user is allowed to manage the chat integration of the codebase. The url of the integration
+// is a secret that allows anyone to post to the channel, so it can only be seen by users who can administrate the
+// codebase.
+func (r *rootResolver) authorize(ctx context.Context, codebaseID codebases.ID) (*codebases.Codebase, error) {
+	if _, err := auth.UserID(ctx); err != nil {
+		return nil, err
+	}
+	cb, err := r.codebaseService.GetByID(ctx, codebaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get codebase: %w", err)
+	}
+	if err := r.authService.CanAdmin(ctx, cb); err != nil {
+		return nil, err
+	}
+	return cb, nil
+}
+
+func (r *rootResolver) ChatIntegration(ctx context.Context, args resolvers.ChatIntegrationArgs) (resolvers.ChatIntegrationResolver, error) {
+	cb, err := r.authorize(ctx, codebases.ID(args.CodebaseID))
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	integration, err := r.chatService.Get(ctx, cb.ID)
+	switch {
+	case err == nil:
+		return &resolver{root: r, integration: integration}, nil
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, nil
+	default:
+		return nil, gqlerrors.Error(fmt.Errorf("failed to get chat integration: %w", err))
+	}
+}
+
+func (r *rootResolver) UpdateChatIntegration(ctx context.Context, args resolvers.UpdateChatIntegrationArgs) (resolvers.ChatIntegrationResolver, error) {
+	userID, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	cb, err := r.authorize(ctx, codebases.ID(args.Input.CodebaseID))
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	integration, err := r.chatService.Update(ctx, cb.ID, userID, args.Input.URL)
+	if errors.Is(err, service_chat.ErrInvalidURL) || errors.Is(err, service_chat.ErrPrivateURL) {
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "url", err.Error())
+	} else if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	return &resolver{root: r, integration: integration}, nil
+}
+
+func (r *rootResolver) DeleteChatIntegration(ctx context.Context, args resolvers.DeleteChatIntegrationArgs) (resolvers.CodebaseResolver, error) {
+	cb, err := r.authorize(ctx, codebases.ID(args.CodebaseID))
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	if err := r.chatService.Delete(ctx, cb.ID); err != nil {
+		return nil, gqlerrors.Error(fmt.Errorf("failed to delete chat integration: %w", err))
+	}
+
+	id := graphql.ID(cb.ID)
+	return (*r.codebaseRootResolver).Codebase(ctx, resolvers.CodebaseArgs{ID: &id})
+}
+
+func (r *rootResolver) SendTestChatMessage(ctx context.Context, args resolvers.SendTestChatMessageArgs) (resolvers.ChatIntegrationResolver, error) {
+	cb, err := r.authorize(ctx, codebases.ID(args.CodebaseID))
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	integration, err := r.chatService.Get(ctx, cb.ID)
+	if err != nil {
+		return nil, gqlerrors.Error(fmt.Errorf("failed to get chat integration: %w", err))
+	}
+
+	if err := r.chatService.SendTest(ctx, cb.ID); errors.Is(err, service_chat.ErrSendFailed) {
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", err.Error())
+	} else if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	return &resolver{root: r, integration: integration}, nil
+}
+
+type resolver struct {
+	root        *rootResolver
+	integration *chat.Integration
+}
+
+func (r *resolver) Codebase(ctx context.Context) (resolvers.CodebaseResolver, error) {
+	id := graphql.ID(r.integration.CodebaseID)
+	return (*r.root.codebaseRootResolver).Codebase(ctx, resolvers.CodebaseArgs{ID: &id})
+}
+
+func (r *resolver) URL() string {
+	return r.integration.URL
+}
+
+func (r *resolver) UpdatedAt() int32 {
+	return int32(r.integration.UpdatedAt.Unix())
+}
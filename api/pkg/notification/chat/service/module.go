@@ -0,0 +1,31 @@
+package service
+
+import (
+	db_changes "getsturdy.com/api/pkg/changes/db"
+	db_codebases "getsturdy.com/api/pkg/codebases/db"
+	db_comments "getsturdy.com/api/pkg/comments/db"
+	configuration "getsturdy.com/api/pkg/configuration/module"
+	"getsturdy.com/api/pkg/di"
+	"getsturdy.com/api/pkg/logger"
+	db_chat "getsturdy.com/api/pkg/notification/chat/db"
+	service_notification "getsturdy.com/api/pkg/notification/service"
+	queue "getsturdy.com/api/pkg/queue/module"
+	db_snapshots "getsturdy.com/api/pkg/snapshots/db"
+	db_users "getsturdy.com/api/pkg/users/db"
+	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
+)
+
+func Module(c *di.Container) {
+	c.Import(logger.Module)
+	c.Import(configuration.Module)
+	c.Import(db_chat.Module)
+	c.Import(queue.Module)
+	c.Import(service_notification.Module)
+	c.Import(db_users.Module)
+	c.Import(db_codebases.Module)
+	c.Import(db_workspaces.Module)
+	c.Import(db_changes.Module)
+	c.Import(db_comments.Module)
+	c.Import(db_snapshots.Module)
+	c.Register(New)
+}
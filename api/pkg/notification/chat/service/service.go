@@ -0,0 +1,544 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"getsturdy.com/api/pkg/changes"
+	db_changes "getsturdy.com/api/pkg/changes/db"
+	"getsturdy.com/api/pkg/codebases"
+	db_codebases "getsturdy.com/api/pkg/codebases/db"
+	"getsturdy.com/api/pkg/comments"
+	db_comments "getsturdy.com/api/pkg/comments/db"
+	decorate_comments "getsturdy.com/api/pkg/comments/decorate"
+	"getsturdy.com/api/pkg/ip"
+	"getsturdy.com/api/pkg/notification"
+	"getsturdy.com/api/pkg/notification/chat"
+	"getsturdy.com/api/pkg/notification/chat/configuration"
+	db_chat "getsturdy.com/api/pkg/notification/chat/db"
+	service_notification "getsturdy.com/api/pkg/notification/service"
+	"getsturdy.com/api/pkg/queue"
+	"getsturdy.com/api/pkg/queue/names"
+	"getsturdy.com/api/pkg/review"
+	db_snapshots "getsturdy.com/api/pkg/snapshots/db"
+	"getsturdy.com/api/pkg/statuses"
+	"getsturdy.com/api/pkg/users"
+	db_users "getsturdy.com/api/pkg/users/db"
+	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
+
+	"go.uber.org/zap"
+)
+
+// defaultAppURL is linked to if the public url of the web app is not configured.
+const defaultAppURL = "https://getsturdy.com"
+
+var (
+	ErrInvalidURL         = errors.New("url must be an absolute http or https url")
+	ErrPrivateURL         = errors.New("url must not point to a private, loopback or link-local address")
+	ErrSendFailed         = errors.New("failed to send message")
+	ErrIntegrationDeleted = errors.New("chat integration is deleted")
+)
+
+// PostMessage is published to the queue for every message that is posted to the chat integration of a codebase.
+type PostMessage struct {
+	CodebaseID codebases.ID `json:"codebase_id"`
+	Text       string       `json:"text"`
+	Attempts   int          `json:"attempts"`
+}
+
+type Service struct {
+	logger *zap.Logger
+	repo   db_chat.Repository
+	queue  queue.Queue
+
+	preferences *service_notification.Preferences
+
+	userRepo         db_users.Repository
+	codebaseRepo     db_codebases.CodebaseRepository
+	codebaseUserRepo db_codebases.CodebaseUserRepository
+	workspaceRepo    db_workspaces.Repository
+	changeRepo       db_changes.Repository
+	commentsRepo     db_comments.Repository
+	snapshotRepo     db_snapshots.Repository
+
+	appURL string
+	filter ip.Filter
+	client *http.Client
+}
+
+func New(
+	logger *zap.Logger,
+	cfg *configuration.Configuration,
+	repo db_chat.Repository,
+	queue queue.Queue,
+	preferences *service_notification.Preferences,
+	userRepo db_users.Repository,
+	codebaseRepo db_codebases.CodebaseRepository,
+	codebaseUserRepo db_codebases.CodebaseUserRepository,
+	workspaceRepo db_workspaces.Repository,
+	changeRepo db_changes.Repository,
+	commentsRepo db_comments.Repository,
+	snapshotRepo db_snapshots.Repository,
+) *Service {
+	return newService(
+		logger,
+		appURL(cfg),
+		repo,
+		queue,
+		preferences,
+		userRepo,
+		codebaseRepo,
+		codebaseUserRepo,
+		workspaceRepo,
+		changeRepo,
+		commentsRepo,
+		snapshotRepo,
+		ip.Public,
+	)
+}
+
+func newService(
+	logger *zap.Logger,
+	appURL string,
+	repo db_chat.Repository,
+	queue queue.Queue,
+	preferences *service_notification.Preferences,
+	userRepo db_users.Repository,
+	codebaseRepo db_codebases.CodebaseRepository,
+	codebaseUserRepo db_codebases.CodebaseUserRepository,
+	workspaceRepo db_workspaces.Repository,
+	changeRepo db_changes.Repository,
+	commentsRepo db_comments.Repository,
+	snapshotRepo db_snapshots.Repository,
+	filter ip.Filter,
+) *Service {
+	return &Service{
+		logger: logger.Named("chatService"),
+		repo:   repo,
+		queue:  queue,
+
+		preferences: preferences,
+
+		userRepo:         userRepo,
+		codebaseRepo:     codebaseRepo,
+		codebaseUserRepo: codebaseUserRepo,
+		workspaceRepo:    workspaceRepo,
+		changeRepo:       changeRepo,
+		commentsRepo:     commentsRepo,
+		snapshotRepo:     snapshotRepo,
+
+		appURL: appURL,
+		filter: filter,
+		client: filter.Client(10 * time.Second),
+	}
+}
+
+// appURL returns the configured public url of the web app, without a trailing slash.
+func appURL(cfg *configuration.Configuration) string {
+	if cfg != nil && cfg.AppURL.Host != "" {
+		return strings.TrimSuffix(cfg.AppURL.String(), "/")
+	}
+	return defaultAppURL
+}
+
+// Get returns sql.ErrNoRows if the codebase has no chat integration.
+func (s *Service) Get(ctx context.Context, codebaseID codebases.ID) (*chat.Integration, error) {
+	return s.repo.Get(ctx, codebaseID)
+}
+
+// Update sets the incoming webhook that notifications about the codebase are posted to.
+func (s *Service) Update(ctx context.Context, codebaseID codebases.ID, userID users.ID, webhookURL string) (*chat.Integration, error) {
+	u, err := url.Parse(webhookURL)
+	if err != nil || !u.IsAbs() || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, ErrInvalidURL
+	}
+	if err := s.filter.CheckHost(ctx, u.Hostname()); err != nil {
+		return nil, ErrPrivateURL
+	}
+
+	integration := &chat.Integration{
+		CodebaseID: codebaseID,
+		URL:        webhookURL,
+		UpdatedBy:  userID,
+		UpdatedAt:  time.Now(),
+	}
+	if err := s.repo.Upsert(ctx, integration); err != nil {
+		return nil, fmt.Errorf("failed to update chat integration: %w", err)
+	}
+	return integration, nil
+}
+
+func (s *Service) Delete(ctx context.Context, codebaseID codebases.ID) error {
+	return s.repo.Delete(ctx, codebaseID)
+}
+
+// SendTest posts a message to the chat integration of the codebase, so that users can verify that it's set up
+// correctly.
+func (s *Service) SendTest(ctx context.Context, codebaseID codebases.ID) error {
+	integration, err := s.repo.Get(ctx, codebaseID)
+	if err != nil {
+		return fmt.Errorf("failed to get chat integration: %w", err)
+	}
+
+	cb, err := s.codebaseRepo.Get(codebaseID)
+	if err != nil {
+		return fmt.Errorf("failed to get codebase: %w", err)
+	}
+
+	text := fmt.Sprintf("Notifications from %s will be posted here", chat.Bold(cb.Name))
+	if err := s.post(ctx, integration.URL, &chat.Message{Text: text}); err != nil {
+		return fmt.Errorf("%w: %s", ErrSendFailed, err)
+	}
+	return nil
+}
+
+// recipient is a user that an event is about, and the type of notifications that the user must have enabled in
+// chat for the event to be posted.
+type recipient struct {
+	userID users.ID
+	typ    notification.NotificationType
+}
+
+// ChangeLanded posts that the change has landed, if its author has enabled it.
+func (s *Service) ChangeLanded(ctx context.Context, change *changes.Change) error {
+	if change.UserID == nil {
+		return nil
+	}
+	recipients := []recipient{{userID: *change.UserID, typ: notification.ChangeLandedNotificationType}}
+	return s.notify(ctx, change.CodebaseID, recipients, func(cb *codebases.Codebase) (string, error) {
+		author, err := s.userRepo.Get(*change.UserID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get author: %w", err)
+		}
+		return fmt.Sprintf("%s landed %s in %s", chat.Bold(author.Name), s.changeLink(cb, change), chat.Bold(cb.Name)), nil
+	})
+}
+
+// StatusUpdated posts that the status is failing, if the author of the workspace or the change that the status is
+// reported for has enabled it.
+func (s *Service) StatusUpdated(ctx context.Context, status *statuses.Status) error {
+	if status.Type != statuses.TypeFailing {
+		return nil
+	}
+
+	userID, link, err := s.statusOwner(ctx, status)
+	if errors.Is(err, sql.ErrNoRows) {
+		// the status is not for a workspace or a change that anyone owns
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	recipients := []recipient{{userID: userID, typ: notification.StatusFailedNotificationType}}
+	return s.notify(ctx, status.CodebaseID, recipients, func(cb *codebases.Codebase) (string, error) {
+		author, err := s.userRepo.Get(userID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get author: %w", err)
+		}
+		text := fmt.Sprintf("%s is failing on %s by %s", chat.Bold(status.Title), link(cb), chat.Bold(author.Name))
+		if status.DetailsURL != nil {
+			text += fmt.Sprintf(" (%s)", chat.Link(*status.DetailsURL, "details"))
+		}
+		if status.Description != nil && *status.Description != "" {
+			text += "\n" + chat.Quote(*status.Description)
+		}
+		return text, nil
+	})
+}
+
+// CommentCreated posts the comment, if the author of the workspace or the change that it's on has enabled comments,
+// or if any of the mentioned users has enabled mentions.
+func (s *Service) CommentCreated(ctx context.Context, comment *comments.Comment, mentioned map[users.ID]bool) error {
+	ownerID, link, err := s.commentedOn(ctx, comment)
+	if err != nil {
+		return err
+	}
+
+	var recipients []recipient
+	for userID := range mentioned {
+		if userID != comment.UserID {
+			recipients = append(recipients, recipient{userID: userID, typ: notification.MentionNotificationType})
+		}
+	}
+	if ownerID != nil && *ownerID != comment.UserID && !mentioned[*ownerID] {
+		recipients = append(recipients, recipient{userID: *ownerID, typ: notification.CommentNotificationType})
+	}
+
+	return s.notify(ctx, comment.CodebaseID, recipients, func(cb *codebases.Codebase) (string, error) {
+		author, err := s.userRepo.Get(comment.UserID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get author: %w", err)
+		}
+		message, err := s.replaceMentions(ctx, comment)
+		if err != nil {
+			return "", err
+		}
+		verb := "commented on"
+		if comment.ParentComment != nil {
+			verb = "replied on"
+		}
+		return fmt.Sprintf("%s %s %s", chat.Bold(author.Name), verb, link(cb)) + "\n" + chat.Quote(message), nil
+	})
+}
+
+// ReviewSubmitted posts the review, if the author of the reviewed workspace has enabled reviews.
+func (s *Service) ReviewSubmitted(ctx context.Context, rev *review.Review) error {
+	ws, err := s.workspaceRepo.Get(rev.WorkspaceID)
+	if err != nil {
+		return fmt.Errorf("failed to get workspace: %w", err)
+	}
+
+	recipients := []recipient{{userID: ws.UserID, typ: notification.ReviewNotificationType}}
+	return s.notify(ctx, rev.CodebaseID, recipients, func(cb *codebases.Codebase) (string, error) {
+		reviewer, err := s.userRepo.Get(rev.UserID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get reviewer: %w", err)
+		}
+		author, err := s.userRepo.Get(ws.UserID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get author: %w", err)
+		}
+		verb := "reviewed"
+		switch rev.Grade {
+		case review.ReviewGradeApprove:
+			verb = "approved"
+		case review.ReviewGradeReject:
+			verb = "rejected"
+		}
+		link := s.workspaceLink(cb, ws.ID, ws.NameOrFallback())
+		return fmt.Sprintf("%s %s %s by %s", chat.Bold(reviewer.Name), verb, link, chat.Bold(author.Name)), nil
+	})
+}
+
+// ReviewRequested posts that a review has been requested, if the user that it's requested from has enabled review
+// requests.
+func (s *Service) ReviewRequested(ctx context.Context, rev *review.Review) error {
+	recipients := []recipient{{userID: rev.UserID, typ: notification.RequestedReviewNotificationType}}
+	return s.notify(ctx, rev.CodebaseID, recipients, func(cb *codebases.Codebase) (string, error) {
+		ws, err := s.workspaceRepo.Get(rev.WorkspaceID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get workspace: %w", err)
+		}
+		reviewer, err := s.userRepo.Get(rev.UserID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get reviewer: %w", err)
+		}
+		link := s.workspaceLink(cb, ws.ID, ws.NameOrFallback())
+
+		if rev.RequestedBy == nil {
+			return fmt.Sprintf("%s was asked to review %s", chat.Bold(reviewer.Name), link), nil
+		}
+		requestedBy, err := s.userRepo.Get(*rev.RequestedBy)
+		if err != nil {
+			return "", fmt.Errorf("failed to get requester: %w", err)
+		}
+		return fmt.Sprintf("%s requested a review from %s on %s", chat.Bold(requestedBy.Name), chat.Bold(reviewer.Name), link), nil
+	})
+}
+
+// statusOwner returns the author of the workspace or the change that the status is reported for, and a function that
+// links to it. sql.ErrNoRows is returned if there is no such workspace or change.
+func (s *Service) statusOwner(ctx context.Context, status *statuses.Status) (users.ID, func(*codebases.Codebase) string, error) {
+	snapshot, err := s.snapshotRepo.GetByCommitSHA(ctx, status.CommitSHA)
+	switch {
+	case err == nil:
+		ws, err := s.workspaceRepo.Get(snapshot.WorkspaceID)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to get workspace: %w", err)
+		}
+		return ws.UserID, func(cb *codebases.Codebase) string {
+			return s.workspaceLink(cb, ws.ID, ws.NameOrFallback())
+		}, nil
+	case !errors.Is(err, sql.ErrNoRows):
+		return "", nil, fmt.Errorf("failed to get snapshot: %w", err)
+	}
+
+	change, err := s.changeRepo.GetByCommitID(ctx, status.CommitSHA, status.CodebaseID)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get change: %w", err)
+	}
+	if change.UserID == nil {
+		return "", nil, sql.ErrNoRows
+	}
+	return *change.UserID, func(cb *codebases.Codebase) string {
+		return s.changeLink(cb, change)
+	}, nil
+}
+
+// commentedOn returns the author of the workspace or the change that the comment is on, if any, and a function that
+// links to it.
+func (s *Service) commentedOn(ctx context.Context, comment *comments.Comment) (*users.ID, func(*codebases.Codebase) string, error) {
+	// replies are not connected to the workspace or change, their parents are
+	on := comment
+	if comment.ParentComment != nil {
+		parent, err := s.commentsRepo.Get(*comment.ParentComment)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get parent comment: %w", err)
+		}
+		on = &parent
+	}
+
+	switch {
+	case on.ChangeID != nil:
+		change, err := s.changeRepo.Get(ctx, *on.ChangeID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get change: %w", err)
+		}
+		return change.UserID, func(cb *codebases.Codebase) string {
+			return s.changeLink(cb, change)
+		}, nil
+	case on.WorkspaceID != nil:
+		ws, err := s.workspaceRepo.Get(*on.WorkspaceID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get workspace: %w", err)
+		}
+		return &ws.UserID, func(cb *codebases.Codebase) string {
+			return s.workspaceLink(cb, ws.ID, ws.NameOrFallback())
+		}, nil
+	default:
+		return nil, func(cb *codebases.Codebase) string {
+			return chat.Bold(cb.Name)
+		}, nil
+	}
+}
+
+// notify publishes a single message about an event to the chat integration of the codebase, if it has one and any
+// of the recipients has enabled chat notifications of their type. The message is only built if it's going to be
+// sent, and is posted by the chat queue.
+func (s *Service) notify(ctx context.Context, codebaseID codebases.ID, recipients []recipient, message func(*codebases.Codebase) (string, error)) error {
+	if _, err := s.repo.Get(ctx, codebaseID); errors.Is(err, sql.ErrNoRows) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get chat integration: %w", err)
+	}
+
+	enabled, err := s.anyEnabled(ctx, recipients)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return nil
+	}
+
+	cb, err := s.codebaseRepo.Get(codebaseID)
+	if err != nil {
+		return fmt.Errorf("failed to get codebase: %w", err)
+	}
+
+	text, err := message(cb)
+	if err != nil {
+		return err
+	}
+
+	return s.Enqueue(ctx, &PostMessage{CodebaseID: codebaseID, Text: text})
+}
+
+func (s *Service) anyEnabled(ctx context.Context, recipients []recipient) (bool, error) {
+	for _, r := range recipients {
+		enabled, err := s.preferences.IsEnabled(ctx, r.userID, r.typ, notification.ChannelChat)
+		if err != nil {
+			return false, fmt.Errorf("failed to get notification preferences: %w", err)
+		}
+		if enabled {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Enqueue schedules the message to be posted.
+func (s *Service) Enqueue(ctx context.Context, msg *PostMessage) error {
+	if err := s.queue.Publish(ctx, names.CodebaseChat, msg); err != nil {
+		return fmt.Errorf("could not publish to queue: %w", err)
+	}
+	return nil
+}
+
+// EnqueueRetry schedules another attempt to post the message, once the backoff has passed.
+func (s *Service) EnqueueRetry(ctx context.Context, msg *PostMessage, backoff time.Duration) error {
+	if err := s.queue.PublishDelayed(ctx, names.CodebaseChat, msg, backoff); err != nil {
+		return fmt.Errorf("could not publish to queue: %w", err)
+	}
+	return nil
+}
+
+// Post makes one attempt to post the message to the chat integration of its codebase. ErrIntegrationDeleted is
+// returned if the codebase no longer has an integration.
+func (s *Service) Post(ctx context.Context, msg *PostMessage) error {
+	integration, err := s.repo.Get(ctx, msg.CodebaseID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrIntegrationDeleted
+	} else if err != nil {
+		return fmt.Errorf("failed to get chat integration: %w", err)
+	}
+	return s.post(ctx, integration.URL, &chat.Message{Text: msg.Text})
+}
+
+// replaceMentions returns the message of the comment, with all @id mentions replaced with @name.
+func (s *Service) replaceMentions(ctx context.Context, comment *comments.Comment) (string, error) {
+	codebaseUsers, err := s.codebaseUserRepo.GetByCodebase(comment.CodebaseID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get codebase users: %w", err)
+	}
+	userIDs := make([]users.ID, 0, len(codebaseUsers))
+	for _, codebaseUser := range codebaseUsers {
+		userIDs = append(userIDs, codebaseUser.UserID)
+	}
+	uu, err := s.userRepo.GetByIDs(ctx, userIDs...)
+	if err != nil {
+		return "", fmt.Errorf("failed to get users: %w", err)
+	}
+
+	message := comment.Message
+	for mention, user := range decorate_comments.ExtractIDMentions(comment.Message, uu) {
+		message = strings.ReplaceAll(message, mention, fmt.Sprintf("@%s", user.Name))
+	}
+	return message, nil
+}
+
+func (s *Service) workspaceLink(cb *codebases.Codebase, workspaceID, name string) string {
+	return chat.Link(fmt.Sprintf("%s/%s/%s", s.appURL, cb.GenerateSlug(), workspaceID), name)
+}
+
+func (s *Service) changeLink(cb *codebases.Codebase, change *changes.Change) string {
+	title := "a change"
+	if change.Title != nil && *change.Title != "" {
+		title = *change.Title
+	}
+	return chat.Link(fmt.Sprintf("%s/%s/%s", s.appURL, cb.GenerateSlug(), change.ID), title)
+}
+
+func (s *Service) post(ctx context.Context, webhookURL string, message *chat.Message) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Sturdy-Chat")
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer res.Body.Close()
+
+	// the body of the response is never read, errors are returned to users, so that the integration can not be used
+	// to read the responses of other services
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status %d", res.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"getsturdy.com/api/pkg/changes"
+	db_changes "getsturdy.com/api/pkg/changes/db"
+	"getsturdy.com/api/pkg/codebases"
+	db_codebases "getsturdy.com/api/pkg/codebases/db"
+	"getsturdy.com/api/pkg/comments"
+	db_comments "getsturdy.com/api/pkg/comments/db"
+	"getsturdy.com/api/pkg/internal/dbtest"
+	"getsturdy.com/api/pkg/ip"
+	"getsturdy.com/api/pkg/notification"
+	"getsturdy.com/api/pkg/notification/chat"
+	db_chat "getsturdy.com/api/pkg/notification/chat/db"
+	db_notification "getsturdy.com/api/pkg/notification/db"
+	service_notification "getsturdy.com/api/pkg/notification/service"
+	"getsturdy.com/api/pkg/queue"
+	"getsturdy.com/api/pkg/queue/names"
+	"getsturdy.com/api/pkg/snapshots"
+	db_snapshots "getsturdy.com/api/pkg/snapshots/db"
+	"getsturdy.com/api/pkg/statuses"
+	"getsturdy.com/api/pkg/users"
+	db_users "getsturdy.com/api/pkg/users/db"
+	"getsturdy.com/api/pkg/workspaces"
+	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var allowAll ip.Filter = func(net.IP) bool { return true }
+
+// recordingQueue records the published messages, so that the tests can post them.
+type recordingQueue struct {
+	queue.Queue
+	published []*PostMessage
+}
+
+func (q *recordingQueue) Publish(_ context.Context, _ names.IncompleteQueueName, msg any) error {
+	q.published = append(q.published, msg.(*PostMessage))
+	return nil
+}
+
+// postAll posts all messages that have been published to the queue.
+func postAll(t *testing.T, svc *Service, q *recordingQueue) {
+	for _, msg := range q.published {
+		require.NoError(t, svc.Post(context.Background(), msg))
+	}
+	q.published = nil
+}
+
+func TestNotifications(t *testing.T) {
+	ctx := context.Background()
+
+	var received []chat.Message
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg chat.Message
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+		received = append(received, msg)
+	}))
+	defer stub.Close()
+
+	q := &recordingQueue{}
+	preferences := service_notification.NewPreferences(db_notification.NewPeferenceRepository(dbtest.DB(t)))
+	userRepo := db_users.NewMemory()
+	codebaseRepo := db_codebases.NewMemory()
+	workspaceRepo := db_workspaces.NewMemory()
+	commentsRepo := db_comments.NewMemory()
+	snapshotRepo := db_snapshots.NewInMemorySnapshotRepo()
+
+	svc := newService(
+		zap.NewNop(),
+		"https://sturdy.example.com",
+		db_chat.NewMemory(),
+		q,
+		preferences,
+		userRepo,
+		codebaseRepo,
+		db_codebases.NewInMemoryCodebaseUserRepo(),
+		workspaceRepo,
+		db_changes.NewInMemoryRepo(),
+		commentsRepo,
+		snapshotRepo,
+		allowAll,
+	)
+
+	userID := users.ID(uuid.NewString())
+	require.NoError(t, userRepo.Create(&users.User{ID: userID, Name: "Alice & Bob"}))
+	otherUserID := users.ID(uuid.NewString())
+	require.NoError(t, userRepo.Create(&users.User{ID: otherUserID, Name: "Carol"}))
+	codebaseID := codebases.ID(uuid.NewString())
+	require.NoError(t, codebaseRepo.Create(codebases.Codebase{ID: codebaseID, ShortCodebaseID: "short", Name: "Sturdy"}))
+	workspaceName := "Fix <the> bug"
+	require.NoError(t, workspaceRepo.Create(workspaces.Workspace{ID: "workspace-id", CodebaseID: codebaseID, UserID: userID, Name: &workspaceName}))
+	require.NoError(t, snapshotRepo.Create(&snapshots.Snapshot{ID: "snapshot-id", CodebaseID: codebaseID, WorkspaceID: "workspace-id", CommitSHA: "abc"}))
+
+	title := "Add chat"
+	change := &changes.Change{ID: "change-id", CodebaseID: codebaseID, UserID: &userID, Title: &title}
+	failing := &statuses.Status{ID: "status-id", CodebaseID: codebaseID, CommitSHA: "abc", Type: statuses.TypeFailing, Title: "tests"}
+	workspaceID := "workspace-id"
+	comment := &comments.Comment{ID: "comment-id", CodebaseID: codebaseID, WorkspaceID: &workspaceID, UserID: otherUserID, Message: "Looks good"}
+	require.NoError(t, commentsRepo.Create(*comment))
+
+	// the codebase has no chat integration
+	require.NoError(t, svc.ChangeLanded(ctx, change))
+	assert.Empty(t, q.published)
+
+	_, err := svc.Update(ctx, codebaseID, userID, "not a url")
+	assert.ErrorIs(t, err, ErrInvalidURL)
+	_, err = svc.Update(ctx, codebaseID, userID, stub.URL)
+	require.NoError(t, err)
+
+	require.NoError(t, svc.SendTest(ctx, codebaseID))
+	require.Len(t, received, 1)
+	assert.Equal(t, "Notifications from *Sturdy* will be posted here", received[0].Text)
+
+	// chat notifications are opt-in
+	require.NoError(t, svc.ChangeLanded(ctx, change))
+	require.NoError(t, svc.StatusUpdated(ctx, failing))
+	require.NoError(t, svc.CommentCreated(ctx, comment, map[users.ID]bool{userID: true}))
+	assert.Empty(t, q.published)
+
+	for _, typ := range []notification.NotificationType{
+		notification.ChangeLandedNotificationType,
+		notification.StatusFailedNotificationType,
+		notification.CommentNotificationType,
+		notification.MentionNotificationType,
+	} {
+		_, err = preferences.Update(ctx, userID, typ, notification.ChannelChat, true)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, svc.ChangeLanded(ctx, change))
+	postAll(t, svc, q)
+	require.Len(t, received, 2)
+	assert.Equal(t, "*Alice &amp; Bob* landed <https://sturdy.example.com/sturdy-short/change-id|Add chat> in *Sturdy*", received[1].Text)
+
+	// only failing statuses are sent
+	healthy := *failing
+	healthy.Type = statuses.TypeHealthy
+	require.NoError(t, svc.StatusUpdated(ctx, &healthy))
+	assert.Empty(t, q.published)
+
+	require.NoError(t, svc.StatusUpdated(ctx, failing))
+	postAll(t, svc, q)
+	require.Len(t, received, 3)
+	assert.Equal(t, "*tests* is failing on <https://sturdy.example.com/sturdy-short/workspace-id|Fix &lt;the&gt; bug> by *Alice &amp; Bob*", received[2].Text)
+
+	// the comment is posted once, even if it's both on the workspace of the user and mentions them
+	require.NoError(t, svc.CommentCreated(ctx, comment, map[users.ID]bool{userID: true, otherUserID: true}))
+	postAll(t, svc, q)
+	require.Len(t, received, 4)
+	assert.Equal(t, "*Carol* commented on <https://sturdy.example.com/sturdy-short/workspace-id|Fix &lt;the&gt; bug>\n> Looks good", received[3].Text)
+
+	// messages that are queued when the integration is deleted are not posted
+	require.NoError(t, svc.ChangeLanded(ctx, change))
+	require.NoError(t, svc.Delete(ctx, codebaseID))
+	require.Len(t, q.published, 1)
+	assert.ErrorIs(t, svc.Post(ctx, q.published[0]), ErrIntegrationDeleted)
+	assert.Len(t, received, 4)
+}
+
+func TestSendTest_failed(t *testing.T) {
+	ctx := context.Background()
+
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("secret"))
+	}))
+	defer stub.Close()
+
+	codebaseRepo := db_codebases.NewMemory()
+	codebaseID := codebases.ID(uuid.NewString())
+	require.NoError(t, codebaseRepo.Create(codebases.Codebase{ID: codebaseID, ShortCodebaseID: "short", Name: "Sturdy"}))
+
+	svc := newService(zap.NewNop(), defaultAppURL, db_chat.NewMemory(), &recordingQueue{}, nil, nil, codebaseRepo, nil, nil, nil, nil, nil, allowAll)
+	_, err := svc.Update(ctx, codebaseID, "user-id", stub.URL)
+	require.NoError(t, err)
+
+	err = svc.SendTest(ctx, codebaseID)
+	assert.ErrorIs(t, err, ErrSendFailed)
+	assert.Contains(t, err.Error(), "500")
+	// the response is not returned
+	assert.NotContains(t, err.Error(), "secret")
+}
+
+func TestUpdate_private(t *testing.T) {
+	ctx := context.Background()
+	svc := newService(zap.NewNop(), defaultAppURL, db_chat.NewMemory(), &recordingQueue{}, nil, nil, nil, nil, nil, nil, nil, nil, ip.Public)
+
+	for _, u := range []string{"http://127.0.0.1:8080/hook", "http://10.0.0.1/hook", "http://[::1]/hook", "http://169.254.169.254/latest"} {
+		_, err := svc.Update(ctx, "codebase-id", "user-id", u)
+		assert.ErrorIs(t, err, ErrPrivateURL, u)
+	}
+
+	// the addresses are checked again when posting
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer stub.Close()
+	assert.ErrorIs(t, svc.post(ctx, stub.URL, &chat.Message{Text: "hello"}), ip.ErrNotAllowed)
+}
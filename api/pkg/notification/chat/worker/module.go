@@ -0,0 +1,15 @@
+package worker
+
+import (
+	"getsturdy.com/api/pkg/di"
+	"getsturdy.com/api/pkg/logger"
+	service_chat "getsturdy.com/api/pkg/notification/chat/service"
+	queue "getsturdy.com/api/pkg/queue/module"
+)
+
+func Module(c *di.Container) {
+	c.Import(logger.Module)
+	c.Import(queue.Module)
+	c.Import(service_chat.Module)
+	c.Register(New)
+}
@@ -0,0 +1,98 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"getsturdy.com/api/pkg/notification/chat/service"
+	"getsturdy.com/api/pkg/queue"
+	"getsturdy.com/api/pkg/queue/names"
+	"getsturdy.com/api/pkg/queue/retry"
+)
+
+type Queue struct {
+	logger *zap.Logger
+	queue  queue.Queue
+	name   names.IncompleteQueueName
+
+	service *service.Service
+	backoff retry.Backoff
+}
+
+func New(
+	logger *zap.Logger,
+	queue queue.Queue,
+	service *service.Service,
+) *Queue {
+	return &Queue{
+		logger:  logger.Named("chatQueue"),
+		queue:   queue,
+		name:    names.CodebaseChat,
+		service: service,
+		backoff: retry.Default,
+	}
+}
+
+func (q *Queue) Start(ctx context.Context) error {
+	messages := make(chan queue.Message)
+	go func() {
+		defer func() {
+			if rec := recover(); rec != nil {
+				q.logger.Error("panic in runner", zap.String("panic", fmt.Sprintf("%v", rec)))
+			}
+		}()
+
+		for msg := range messages {
+			m := &service.PostMessage{}
+			if err := msg.As(m); err != nil {
+				q.logger.Error("failed to decode message", zap.Error(err))
+				continue
+			}
+
+			q.post(ctx, m)
+
+			if err := msg.Ack(); err != nil {
+				q.logger.Error("failed to ack message", zap.Error(err), zap.Stringer("codebase_id", m.CodebaseID))
+				continue
+			}
+		}
+	}()
+
+	q.logger.Info("starting queue", zap.Stringer("queue_name", q.name))
+	if err := q.queue.Subscribe(ctx, q.name, messages); err != nil {
+		return fmt.Errorf("could not subscribe to queue: %w", err)
+	}
+	q.logger.Info("queue stoped", zap.Stringer("queue_name", q.name))
+
+	return nil
+}
+
+// post attempts to post the message, and schedules a retry on the queue if it failed and has attempts left.
+func (q *Queue) post(ctx context.Context, msg *service.PostMessage) {
+	logger := q.logger.With(zap.Stringer("codebase_id", msg.CodebaseID))
+
+	err := q.service.Post(context.Background(), msg)
+	msg.Attempts++
+	switch {
+	case err == nil:
+		logger.Info("posted")
+		return
+	case errors.Is(err, service.ErrIntegrationDeleted):
+		logger.Info("skipping message", zap.Error(err))
+		return
+	}
+
+	wait, ok := q.backoff.Next(msg.Attempts)
+	if !ok {
+		logger.Warn("failed to post, giving up", zap.Error(err), zap.Int("attempts", msg.Attempts))
+		return
+	}
+	logger.Info("failed to post, retrying", zap.Error(err), zap.Int("attempts", msg.Attempts), zap.Duration("backoff", wait))
+
+	if err := q.service.EnqueueRetry(ctx, msg, wait); err != nil {
+		logger.Error("failed to enqueue retry", zap.Error(err))
+	}
+}
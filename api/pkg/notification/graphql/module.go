@@ -10,6 +10,7 @@ import (
 	"getsturdy.com/api/pkg/events"
 	graphql_github "getsturdy.com/api/pkg/github/graphql"
 	"getsturdy.com/api/pkg/logger"
+	graphql_mergequeue "getsturdy.com/api/pkg/mergequeue/graphql"
 	db_notification "getsturdy.com/api/pkg/notification/db"
 	service_notification "getsturdy.com/api/pkg/notification/service"
 	db_organizations "getsturdy.com/api/pkg/organization/db"
@@ -35,5 +36,6 @@ func Module(c *di.Container) {
 	c.Import(graphql_github.Module)
 	c.Import(graphql_organizations.Module)
 	c.Import(db_organizations.Module)
+	c.Import(graphql_mergequeue.Module)
 	c.Register(NewResolver)
 }
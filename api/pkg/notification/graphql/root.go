@@ -12,6 +12,7 @@ import (
 	"getsturdy.com/api/pkg/events"
 	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
 	"getsturdy.com/api/pkg/graphql/resolvers"
+	"getsturdy.com/api/pkg/mergequeue"
 	"getsturdy.com/api/pkg/notification"
 	db_notification "getsturdy.com/api/pkg/notification/db"
 	service_notification "getsturdy.com/api/pkg/notification/service"
@@ -49,6 +50,7 @@ type notificationRootResolver struct {
 	suggestionRootResolver                resolvers.SuggestionRootResolver
 	codebaseGitHubIntegrationRootResolver resolvers.CodebaseGitHubIntegrationRootResolver
 	organizationResolver                  resolvers.OrganizationRootResolver
+	mergeQueueRootResolver                resolvers.MergeQueueRootResolver
 
 	eventsReader events.EventReader
 	eventSender  events.EventSender
@@ -72,6 +74,7 @@ func NewResolver(
 	suggestionRootResolver resolvers.SuggestionRootResolver,
 	codebaseGitHubIntegrationRootResolver resolvers.CodebaseGitHubIntegrationRootResolver,
 	organizationResolver resolvers.OrganizationRootResolver,
+	mergeQueueRootResolver resolvers.MergeQueueRootResolver,
 
 	eventsReader events.EventReader,
 	eventSender events.EventSender,
@@ -94,6 +97,7 @@ func NewResolver(
 		suggestionRootResolver:                suggestionRootResolver,
 		codebaseGitHubIntegrationRootResolver: codebaseGitHubIntegrationRootResolver,
 		organizationResolver:                  organizationResolver,
+		mergeQueueRootResolver:                mergeQueueRootResolver,
 
 		eventsReader: eventsReader,
 		eventSender:  eventSender,
@@ -142,6 +146,8 @@ func convertChannelType(in resolvers.NotificationChannel) (notification.Channel,
 		return notification.ChannelEmail, nil
 	case resolvers.NotificationChannelWeb:
 		return notification.ChannelWeb, nil
+	case resolvers.NotificationChannelChat:
+		return notification.ChannelChat, nil
 	default:
 		return notification.ChannelUndefined, fmt.Errorf("unknown notification channel: %s", in)
 	}
@@ -163,6 +169,14 @@ func convertNotificationType(in resolvers.NotificationType) (notification.Notifi
 		return notification.InvitedToCodebase, nil
 	case resolvers.NotificationTypeInvitedToOrganization:
 		return notification.InvitedToOrganization, nil
+	case resolvers.NotificationTypeMergeQueue:
+		return notification.MergeQueueNotificationType, nil
+	case resolvers.NotificationTypeMention:
+		return notification.MentionNotificationType, nil
+	case resolvers.NotificationTypeChangeLanded:
+		return notification.ChangeLandedNotificationType, nil
+	case resolvers.NotificationTypeStatusFailed:
+		return notification.StatusFailedNotificationType, nil
 	default:
 		return notification.NotificationTypeUndefined, fmt.Errorf("unknown notification type: %s", in)
 	}
@@ -322,6 +336,8 @@ func (r *notificationPreferenceResolver) Channel() (resolvers.NotificationChanne
 		return resolvers.NotificationChannelEmail, nil
 	case notification.ChannelWeb:
 		return resolvers.NotificationChannelWeb, nil
+	case notification.ChannelChat:
+		return resolvers.NotificationChannelChat, nil
 	default:
 		return resolvers.NotificationChannelUndefined, fmt.Errorf("unkown notification channel")
 	}
@@ -361,6 +377,14 @@ func resolveNotificationType(typ notification.NotificationType) (resolvers.Notif
 		return resolvers.NotificationTypeInvitedToOrganization, nil
 	case notification.InvitedToCodebase:
 		return resolvers.NotificationTypeInvitedToCodebase, nil
+	case notification.MergeQueueNotificationType:
+		return resolvers.NotificationTypeMergeQueue, nil
+	case notification.MentionNotificationType:
+		return resolvers.NotificationTypeMention, nil
+	case notification.ChangeLandedNotificationType:
+		return resolvers.NotificationTypeChangeLanded, nil
+	case notification.StatusFailedNotificationType:
+		return resolvers.NotificationTypeStatusFailed, nil
 	default:
 		return resolvers.NotificationTypeUndefined, fmt.Errorf("unknown notification type")
 	}
@@ -382,7 +406,7 @@ var ErrUnknownNotificationType = fmt.Errorf("unknown notification type")
 
 func (r *notificationResolver) sub(ctx context.Context) (any, error) {
 	switch r.notif.NotificationType {
-	case notification.CommentNotificationType:
+	case notification.CommentNotificationType, notification.MentionNotificationType:
 		return r.root.commentResolver.Comment(ctx, resolvers.CommentArgs{ID: graphql.ID(r.notif.ReferenceID)})
 	case notification.ReviewNotificationType:
 		return r.root.reviewRootResolver.InternalReview(ctx, r.notif.ReferenceID)
@@ -406,6 +430,8 @@ func (r *notificationResolver) sub(ctx context.Context) (any, error) {
 		}
 		id := graphql.ID(member.OrganizationID)
 		return r.root.organizationResolver.Organization(ctx, resolvers.OrganizationArgs{ID: &id})
+	case notification.MergeQueueNotificationType:
+		return r.root.mergeQueueRootResolver.InternalMergeQueueEntry(ctx, mergequeue.ID(r.notif.ReferenceID))
 	default:
 		return resolvers.NotificationTypeUndefined, ErrUnknownNotificationType
 	}
@@ -425,6 +451,13 @@ func (r *notificationResolver) ToInvitedToCodebaseNotification() (resolvers.Invi
 	return &invitedToCodebaseNotificationResolver{notificationResolver: r}, true
 }
 
+func (r *notificationResolver) ToMergeQueueNotification() (resolvers.MergeQueueNotificationResolver, bool) {
+	if r.notif.NotificationType != notification.MergeQueueNotificationType {
+		return nil, false
+	}
+	return &mergeQueueNotificationResolver{r}, true
+}
+
 func (r *notificationResolver) ToCommentNotification() (resolvers.CommentNotificationResolver, bool) {
 	if r.notif.NotificationType != notification.CommentNotificationType {
 		return nil, false
@@ -433,6 +466,14 @@ func (r *notificationResolver) ToCommentNotification() (resolvers.CommentNotific
 	return &commentNotificationResolver{r}, true
 }
 
+func (r *notificationResolver) ToMentionNotification() (resolvers.MentionNotificationResolver, bool) {
+	if r.notif.NotificationType != notification.MentionNotificationType {
+		return nil, false
+	}
+
+	return &mentionNotificationResolver{r}, true
+}
+
 func (r *notificationResolver) ToRequestedReviewNotification() (resolvers.RequestedReviewNotificationResolver, bool) {
 	if r.notif.NotificationType != notification.RequestedReviewNotificationType {
 		return nil, false
@@ -474,6 +515,17 @@ func (r *commentNotificationResolver) Comment(ctx context.Context) (resolvers.Co
 	return nil, fmt.Errorf("failed to get CommentResolver")
 }
 
+type mentionNotificationResolver struct {
+	*notificationResolver
+}
+
+func (r *mentionNotificationResolver) Comment(ctx context.Context) (resolvers.CommentResolver, error) {
+	if v, ok := r.subItem.(resolvers.CommentResolver); ok {
+		return v, nil
+	}
+	return nil, fmt.Errorf("failed to get CommentResolver")
+}
+
 type requestedReviewNotificationResolver struct {
 	*notificationResolver
 }
@@ -539,3 +591,14 @@ func (r *invitedToOrganizationNotificationResolver) Organization(ctx context.Con
 	}
 	return nil, fmt.Errorf("failed to get OrganizationResolver")
 }
+
+type mergeQueueNotificationResolver struct {
+	*notificationResolver
+}
+
+func (r *mergeQueueNotificationResolver) Entry(ctx context.Context) (resolvers.MergeQueueEntryResolver, error) {
+	if v, ok := r.subItem.(resolvers.MergeQueueEntryResolver); ok {
+		return v, nil
+	}
+	return nil, fmt.Errorf("failed to get MergeQueueEntryResolver")
+}
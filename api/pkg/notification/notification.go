@@ -26,4 +26,9 @@ const (
 	GitHubRepositoryImported        NotificationType = "github_repository_imported"
 	InvitedToCodebase               NotificationType = "invited_to_codebase"
 	InvitedToOrganization           NotificationType = "invited_to_organization"
+	MergeQueueNotificationType      NotificationType = "merge_queue"
+	MentionNotificationType         NotificationType = "mention"
+	// ChangeLandedNotificationType and StatusFailedNotificationType are only sent to chat.
+	ChangeLandedNotificationType NotificationType = "change_landed"
+	StatusFailedNotificationType NotificationType = "status_failed"
 )
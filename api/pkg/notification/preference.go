@@ -8,6 +8,8 @@ const (
 	ChannelUndefined Channel = ""
 	ChannelWeb       Channel = "web"
 	ChannelEmail     Channel = "email"
+	// ChannelChat posts notifications to the chat integration of the codebase that they are about.
+	ChannelChat Channel = "chat"
 )
 
 // Preference is used to determine if user with _UserID_ wants to receive notifications of type _Type_ via _Channel_.
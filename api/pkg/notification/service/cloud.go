@@ -14,9 +14,12 @@ var (
 		notification.GitHubRepositoryImported:        true,
 		notification.InvitedToCodebase:               true,
 		notification.InvitedToOrganization:           true,
+		notification.MergeQueueNotificationType:      true,
+		notification.MentionNotificationType:         true,
 	}
 	supportedChannels = map[notification.Channel]bool{
 		notification.ChannelEmail: true,
 		notification.ChannelWeb:   true,
+		notification.ChannelChat:  true,
 	}
 )
@@ -14,8 +14,11 @@ var (
 		notification.GitHubRepositoryImported:        true,
 		notification.InvitedToCodebase:               true,
 		notification.InvitedToOrganization:           true,
+		notification.MergeQueueNotificationType:      true,
+		notification.MentionNotificationType:         true,
 	}
 	supportedChannels = map[notification.Channel]bool{
-		notification.ChannelWeb: true,
+		notification.ChannelWeb:  true,
+		notification.ChannelChat: true,
 	}
 )
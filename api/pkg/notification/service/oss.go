@@ -13,8 +13,11 @@ var (
 		notification.NewSuggestionNotificationType:   true,
 		notification.InvitedToCodebase:               true,
 		notification.InvitedToOrganization:           true,
+		notification.MergeQueueNotificationType:      true,
+		notification.MentionNotificationType:         true,
 	}
 	supportedChannels = map[notification.Channel]bool{
-		notification.ChannelWeb: true,
+		notification.ChannelWeb:  true,
+		notification.ChannelChat: true,
 	}
 )
@@ -9,6 +9,17 @@ import (
 	"getsturdy.com/api/pkg/users"
 )
 
+// chatTypes are the notification types that can be sent to chat. Unlike the other channels, chat notifications are
+// opt-in, as they are posted to a channel that is shared by everyone in the codebase.
+var chatTypes = map[notification.NotificationType]bool{
+	notification.CommentNotificationType:         true,
+	notification.MentionNotificationType:         true,
+	notification.ReviewNotificationType:          true,
+	notification.RequestedReviewNotificationType: true,
+	notification.ChangeLandedNotificationType:    true,
+	notification.StatusFailedNotificationType:    true,
+}
+
 type Preferences struct {
 	preferencesRepo *db_notification.PreferenceRepository
 }
@@ -52,6 +63,7 @@ func (s *Preferences) ListByUserID(ctx context.Context, userID users.ID) ([]*not
 	existing := map[notification.Channel]map[notification.NotificationType]*notification.Preference{
 		notification.ChannelEmail: {},
 		notification.ChannelWeb:   {},
+		notification.ChannelChat:  {},
 	}
 	for _, p := range pp {
 		existing[p.Channel][p.Type] = p
@@ -64,7 +76,12 @@ func (s *Preferences) ListByUserID(ctx context.Context, userID users.ID) ([]*not
 			continue
 		}
 
-		for typ, supported := range supportedTypes {
+		types := supportedTypes
+		if channel == notification.ChannelChat {
+			types = chatTypes
+		}
+
+		for typ, supported := range types {
 			if !supported {
 				continue
 			}
@@ -76,7 +93,7 @@ func (s *Preferences) ListByUserID(ctx context.Context, userID users.ID) ([]*not
 					Channel: channel,
 					Type:    typ,
 					UserID:  userID,
-					Enabled: true,
+					Enabled: channel != notification.ChannelChat,
 				})
 			}
 		}
@@ -84,3 +101,17 @@ func (s *Preferences) ListByUserID(ctx context.Context, userID users.ID) ([]*not
 
 	return result, nil
 }
+
+// IsEnabled returns true if the user wants to receive notifications of the type via the channel.
+func (s *Preferences) IsEnabled(ctx context.Context, userID users.ID, typ notification.NotificationType, channel notification.Channel) (bool, error) {
+	pp, err := s.ListByUserID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	for _, p := range pp {
+		if p.Channel == channel && p.Type == typ {
+			return p.Enabled, nil
+		}
+	}
+	return false, nil
+}
@@ -27,6 +27,7 @@ import (
 	"getsturdy.com/api/pkg/configuration"
 	"getsturdy.com/api/pkg/di"
 	eventsv2 "getsturdy.com/api/pkg/events/v2"
+	"getsturdy.com/api/pkg/gc"
 	service_gc "getsturdy.com/api/pkg/gc/service"
 	gqldataloader "getsturdy.com/api/pkg/graphql/dataloader"
 	gqlerror "getsturdy.com/api/pkg/graphql/errors"
@@ -517,7 +518,7 @@ func TestCreate(t *testing.T) {
 
 	{
 		// Trigger GC
-		err := gcService.WorkWithOptions(context.Background(), logger, codebaseRes.ID, 0, 0)
+		err := gcService.WorkWithOptions(context.Background(), logger, codebaseRes.ID, 0, &gc.Policy{CodebaseID: codebaseRes.ID})
 		assert.NoError(t, err)
 
 		// make another change (after gc)
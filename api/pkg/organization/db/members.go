@@ -29,7 +29,7 @@ func NewMember(db *sqlx.DB) MemberRepository {
 
 func (r *memberRepository) GetByID(ctx context.Context, id string) (*organization.Member, error) {
 	var mem organization.Member
-	if err := r.db.GetContext(ctx, &mem, `SELECT id, user_id, organization_id, created_at, created_by, deleted_at, deleted_by
+	if err := r.db.GetContext(ctx, &mem, `SELECT id, user_id, organization_id, role, created_at, created_by, deleted_at, deleted_by
 		FROM organization_members
 		WHERE id = $1`, id); err != nil {
 		return nil, fmt.Errorf("failed to get organization_member by id: %w", err)
@@ -39,7 +39,7 @@ func (r *memberRepository) GetByID(ctx context.Context, id string) (*organizatio
 
 func (r *memberRepository) GetByUserIDAndOrganizationID(ctx context.Context, userID users.ID, organizationID string) (*organization.Member, error) {
 	var mem organization.Member
-	if err := r.db.GetContext(ctx, &mem, `SELECT id, user_id, organization_id, created_at, created_by, deleted_at, deleted_by
+	if err := r.db.GetContext(ctx, &mem, `SELECT id, user_id, organization_id, role, created_at, created_by, deleted_at, deleted_by
 		FROM organization_members
 		WHERE user_id = $1
 		  AND organization_id = $2
@@ -51,7 +51,7 @@ func (r *memberRepository) GetByUserIDAndOrganizationID(ctx context.Context, use
 
 func (r *memberRepository) ListByOrganizationID(ctx context.Context, id string) ([]*organization.Member, error) {
 	var res []*organization.Member
-	if err := r.db.SelectContext(ctx, &res, `SELECT id, user_id, organization_id, created_at, created_by, deleted_at, deleted_by
+	if err := r.db.SelectContext(ctx, &res, `SELECT id, user_id, organization_id, role, created_at, created_by, deleted_at, deleted_by
 		FROM organization_members
 		WHERE organization_id = $1
 		  AND deleted_at IS NULL`, id); err != nil {
@@ -62,7 +62,7 @@ func (r *memberRepository) ListByOrganizationID(ctx context.Context, id string)
 
 func (r *memberRepository) ListByUserID(ctx context.Context, id users.ID) ([]*organization.Member, error) {
 	var res []*organization.Member
-	if err := r.db.SelectContext(ctx, &res, `SELECT id, user_id, organization_id, created_at, created_by, deleted_at, deleted_by
+	if err := r.db.SelectContext(ctx, &res, `SELECT id, user_id, organization_id, role, created_at, created_by, deleted_at, deleted_by
 		FROM organization_members
 		WHERE user_id = $1
 		  AND deleted_at IS NULL`, id); err != nil {
@@ -72,12 +72,12 @@ func (r *memberRepository) ListByUserID(ctx context.Context, id users.ID) ([]*or
 }
 
 func (r *memberRepository) Create(ctx context.Context, mem *organization.Member) error {
-	if err := r.db.GetContext(ctx, mem, `INSERT INTO organization_members (id, user_id, organization_id, created_at, created_by, deleted_at, deleted_by)
-		VALUES ($1, $2, $3, $4, $5, NULL, NULL)
+	if err := r.db.GetContext(ctx, mem, `INSERT INTO organization_members (id, user_id, organization_id, role, created_at, created_by, deleted_at, deleted_by)
+		VALUES ($1, $2, $3, $4, $5, $6, NULL, NULL)
 		ON CONFLICT (user_id, organization_id) DO UPDATE
-		SET deleted_at = NULL, deleted_by = NULL
-		RETURNING id, user_id, organization_id, created_at, created_by, deleted_at, deleted_by`,
-		mem.ID, mem.UserID, mem.OrganizationID, mem.CreatedAt, mem.CreatedBy); err != nil {
+		SET deleted_at = NULL, deleted_by = NULL, role = EXCLUDED.role
+		RETURNING id, user_id, organization_id, role, created_at, created_by, deleted_at, deleted_by`,
+		mem.ID, mem.UserID, mem.OrganizationID, mem.EffectiveRole(), mem.CreatedAt, mem.CreatedBy); err != nil {
 		return fmt.Errorf("failed to create organization_member: %w", err)
 	}
 
@@ -87,7 +87,8 @@ func (r *memberRepository) Create(ctx context.Context, mem *organization.Member)
 func (r *memberRepository) Update(ctx context.Context, org *organization.Member) error {
 	if _, err := r.db.NamedExecContext(ctx, `UPDATE organization_members
 		SET deleted_at = :deleted_at,
-		    deleted_by = :deleted_by
+		    deleted_by = :deleted_by,
+		    role = :role
 		WHERE id = :id
 `, org); err != nil {
 		return fmt.Errorf("failed to update organization: %w", err)
@@ -82,3 +82,27 @@ func TestAddTwoSameMembers_returns_same_id(t *testing.T) {
 
 	assert.Equal(t, oldID, member.ID)
 }
+
+func TestUpdateRole(t *testing.T) {
+	d := dbtest.DB(t)
+	repo := db.NewMember(d)
+	ctx := context.Background()
+
+	member := &organization.Member{
+		ID:             uuid.NewString(),
+		OrganizationID: uuid.NewString(),
+		UserID:         users.ID(uuid.NewString()),
+		CreatedAt:      time.Now(),
+		CreatedBy:      users.ID(uuid.NewString()),
+	}
+
+	assert.NoError(t, repo.Create(ctx, member))
+	assert.Equal(t, organization.RoleMember, member.Role)
+
+	member.Role = organization.RoleAdmin
+	assert.NoError(t, repo.Update(ctx, member))
+
+	updated, err := repo.GetByUserIDAndOrganizationID(ctx, member.UserID, member.OrganizationID)
+	assert.NoError(t, err)
+	assert.Equal(t, organization.RoleAdmin, updated.Role)
+}
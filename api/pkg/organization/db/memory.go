@@ -27,7 +27,7 @@ func (r *inMemoryOrganizationMemberRepository) GetByID(_ context.Context, id str
 
 func (r *inMemoryOrganizationMemberRepository) GetByUserIDAndOrganizationID(ctx context.Context, userID users.ID, organizationID string) (*organization.Member, error) {
 	for _, u := range r.users {
-		if u.UserID == userID && u.OrganizationID == organizationID {
+		if u.UserID == userID && u.OrganizationID == organizationID && u.DeletedAt == nil {
 			return &u, nil
 		}
 	}
@@ -37,7 +37,7 @@ func (r *inMemoryOrganizationMemberRepository) GetByUserIDAndOrganizationID(ctx
 func (r *inMemoryOrganizationMemberRepository) ListByOrganizationID(ctx context.Context, id string) ([]*organization.Member, error) {
 	var res []*organization.Member
 	for _, u := range r.users {
-		if u.OrganizationID == id {
+		if u.OrganizationID == id && u.DeletedAt == nil {
 			u2 := u
 			res = append(res, &u2)
 		}
@@ -48,7 +48,7 @@ func (r *inMemoryOrganizationMemberRepository) ListByOrganizationID(ctx context.
 func (r *inMemoryOrganizationMemberRepository) ListByUserID(ctx context.Context, id users.ID) ([]*organization.Member, error) {
 	var res []*organization.Member
 	for _, u := range r.users {
-		if u.UserID == id {
+		if u.UserID == id && u.DeletedAt == nil {
 			u2 := u
 			res = append(res, &u2)
 		}
@@ -176,7 +176,7 @@ func (r *organizationRootResolver) UpdateOrganization(ctx context.Context, args
 		return nil, gqlerrors.Error(err)
 	}
 
-	if authErr := r.authService.CanWrite(ctx, org); authErr != nil {
+	if authErr := r.authService.CanAdmin(ctx, org); authErr != nil {
 		return nil, gqlerrors.Error(authErr)
 	}
 
@@ -193,7 +193,7 @@ func (r *organizationRootResolver) AddUserToOrganization(ctx context.Context, ar
 		return nil, gqlerrors.Error(err)
 	}
 
-	if err := r.authService.CanWrite(ctx, org); err != nil {
+	if err := r.authService.CanAdmin(ctx, org); err != nil {
 		return nil, gqlerrors.Error(err)
 	}
 
@@ -202,6 +202,18 @@ func (r *organizationRootResolver) AddUserToOrganization(ctx context.Context, ar
 		return nil, err
 	}
 
+	role := organization.RoleMember
+	if args.Input.Role != nil {
+		var ok bool
+		if role, ok = roleFromGraphQL[*args.Input.Role]; !ok {
+			return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "role", "invalid role")
+		}
+	}
+
+	if err := r.service.CanAssignRole(ctx, org.ID, addedByUserID, role); err != nil {
+		return nil, roleError(err)
+	}
+
 	invited, err := r.userService.GetByEmail(ctx, args.Input.Email)
 	if errors.Is(err, sql.ErrNoRows) {
 		userReferer := service_user.UserReferer(addedByUserID)
@@ -213,8 +225,8 @@ func (r *organizationRootResolver) AddUserToOrganization(ctx context.Context, ar
 		return nil, gqlerrors.Error(err)
 	}
 
-	if _, err := r.service.AddMember(ctx, org.ID, invited.ID, addedByUserID); err != nil {
-		return nil, gqlerrors.Error(err)
+	if _, err := r.service.AddMember(ctx, org.ID, invited.ID, addedByUserID, role); err != nil {
+		return nil, roleError(err)
 	}
 
 	return &organizationResolver{root: r, org: org}, nil
@@ -226,7 +238,7 @@ func (r *organizationRootResolver) RemoveUserFromOrganization(ctx context.Contex
 		return nil, gqlerrors.Error(err)
 	}
 
-	if err := r.authService.CanWrite(ctx, org); err != nil {
+	if err := r.authService.CanAdmin(ctx, org); err != nil {
 		return nil, gqlerrors.Error(err)
 	}
 
@@ -236,12 +248,52 @@ func (r *organizationRootResolver) RemoveUserFromOrganization(ctx context.Contex
 	}
 
 	if err := r.service.RemoveMember(ctx, org.ID, users.ID(args.Input.UserID), removedByUserID); err != nil {
+		return nil, roleError(err)
+	}
+
+	return &organizationResolver{root: r, org: org}, nil
+}
+
+func (r *organizationRootResolver) UpdateOrganizationMemberRole(ctx context.Context, args resolvers.UpdateOrganizationMemberRoleArgs) (resolvers.OrganizationResolver, error) {
+	role, ok := roleFromGraphQL[args.Input.Role]
+	if !ok {
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "role", "invalid role")
+	}
+
+	org, err := r.service.GetByID(ctx, string(args.Input.OrganizationID))
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	if err := r.authService.CanAdmin(ctx, org); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	updatedByUserID, err := auth.UserID(ctx)
+	if err != nil {
 		return nil, gqlerrors.Error(err)
 	}
 
+	if _, err := r.service.UpdateMemberRole(ctx, org.ID, users.ID(args.Input.UserID), role, updatedByUserID); err != nil {
+		return nil, roleError(err)
+	}
+
 	return &organizationResolver{root: r, org: org}, nil
 }
 
+func roleError(err error) error {
+	switch {
+	case errors.Is(err, service_organization.ErrInvalidRole):
+		return gqlerrors.Error(gqlerrors.ErrBadRequest, "role", "invalid role")
+	case errors.Is(err, service_organization.ErrLastOwner):
+		return gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "The organization must have at least one owner")
+	case errors.Is(err, service_organization.ErrOnlyOwners):
+		return gqlerrors.Error(gqlerrors.ErrForbidden, "message", "Only owners can manage other owners")
+	default:
+		return gqlerrors.Error(err)
+	}
+}
+
 func (r *organizationRootResolver) UpdatedOrganization(ctx context.Context, args resolvers.UpdatedOrganizationArgs) (<-chan resolvers.OrganizationResolver, error) {
 	userID, err := auth.UserID(ctx)
 	if err != nil {
@@ -318,6 +370,19 @@ func (r *organizationResolver) Members(ctx context.Context) ([]resolvers.AuthorR
 	return res, nil
 }
 
+func (r *organizationResolver) Memberships(ctx context.Context) ([]resolvers.OrganizationMemberResolver, error) {
+	members, err := r.root.service.Members(ctx, r.org.ID)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	res := make([]resolvers.OrganizationMemberResolver, 0, len(members))
+	for _, m := range members {
+		res = append(res, &organizationMemberResolver{root: r.root, member: m})
+	}
+	return res, nil
+}
+
 func (r *organizationResolver) Codebases(ctx context.Context) ([]resolvers.CodebaseResolver, error) {
 	userID, err := auth.UserID(ctx)
 	if err != nil {
@@ -326,10 +391,11 @@ func (r *organizationResolver) Codebases(ctx context.Context) ([]resolvers.Codeb
 
 	var isMemberOfOrganization bool
 
-	_, err = r.root.service.GetMember(ctx, r.org.ID, userID)
+	member, err := r.root.service.GetMember(ctx, r.org.ID, userID)
 	switch {
 	case err == nil:
-		isMemberOfOrganization = true
+		// billing members can see the organization, but not its codebases
+		isMemberOfOrganization = member.EffectiveRole().CanReadCodebases()
 	case errors.Is(err, sql.ErrNoRows):
 		isMemberOfOrganization = false
 	case err != nil:
@@ -376,3 +442,54 @@ func (r *organizationResolver) Writeable(ctx context.Context) bool {
 	}
 	return false
 }
+
+func (r *organizationResolver) ViewerRole(ctx context.Context) (*resolvers.OrganizationRole, error) {
+	userID, err := auth.UserID(ctx)
+	switch {
+	case err == nil:
+	case errors.Is(err, auth.ErrUnauthenticated):
+		return nil, nil
+	default:
+		return nil, gqlerrors.Error(err)
+	}
+
+	member, err := r.root.service.GetMember(ctx, r.org.ID, userID)
+	switch {
+	case err == nil:
+		role := roleToGraphQL[member.EffectiveRole()]
+		return &role, nil
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, nil
+	default:
+		return nil, gqlerrors.Error(err)
+	}
+}
+
+type organizationMemberResolver struct {
+	root   *organizationRootResolver
+	member *organization.Member
+}
+
+func (r *organizationMemberResolver) User(ctx context.Context) (resolvers.AuthorResolver, error) {
+	return r.root.authorRootResolver.Author(ctx, graphql.ID(r.member.UserID))
+}
+
+func (r *organizationMemberResolver) Role() resolvers.OrganizationRole {
+	return roleToGraphQL[r.member.EffectiveRole()]
+}
+
+var roleToGraphQL = map[organization.Role]resolvers.OrganizationRole{
+	organization.RoleOwner:   resolvers.OrganizationRoleOwner,
+	organization.RoleAdmin:   resolvers.OrganizationRoleAdmin,
+	organization.RoleMember:  resolvers.OrganizationRoleMember,
+	organization.RoleBilling: resolvers.OrganizationRoleBilling,
+	organization.RoleGuest:   resolvers.OrganizationRoleGuest,
+}
+
+var roleFromGraphQL = map[resolvers.OrganizationRole]organization.Role{
+	resolvers.OrganizationRoleOwner:   organization.RoleOwner,
+	resolvers.OrganizationRoleAdmin:   organization.RoleAdmin,
+	resolvers.OrganizationRoleMember:  organization.RoleMember,
+	resolvers.OrganizationRoleBilling: organization.RoleBilling,
+	resolvers.OrganizationRoleGuest:   organization.RoleGuest,
+}
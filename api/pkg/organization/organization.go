@@ -22,8 +22,57 @@ type Member struct {
 	ID             string     `db:"id"`
 	UserID         users.ID   `db:"user_id"`
 	OrganizationID string     `db:"organization_id"`
+	Role           Role       `db:"role"`
 	CreatedAt      time.Time  `db:"created_at"`
 	CreatedBy      users.ID   `db:"created_by"`
 	DeletedAt      *time.Time `db:"deleted_at"`
 	DeletedBy      *users.ID  `db:"deleted_by"`
 }
+
+// EffectiveRole returns the role of the member, members without a role are regular members.
+func (m *Member) EffectiveRole() Role {
+	if m.Role == "" {
+		return RoleMember
+	}
+	return m.Role
+}
+
+// Role is the role of a member in an organization.
+type Role string
+
+const (
+	// RoleOwner can do everything, including managing other owners.
+	RoleOwner Role = "owner"
+	// RoleAdmin can manage the organization and its members, but not its owners.
+	RoleAdmin Role = "admin"
+	// RoleMember can read and write all codebases of the organization.
+	RoleMember Role = "member"
+	// RoleBilling can see the organization and its licenses, but not its codebases.
+	RoleBilling Role = "billing"
+	// RoleGuest can read all codebases of the organization.
+	RoleGuest Role = "guest"
+)
+
+func (r Role) Valid() bool {
+	switch r {
+	case RoleOwner, RoleAdmin, RoleMember, RoleBilling, RoleGuest:
+		return true
+	default:
+		return false
+	}
+}
+
+// CanAdministrate returns true if the role can manage the organization and its members.
+func (r Role) CanAdministrate() bool {
+	return r == RoleOwner || r == RoleAdmin
+}
+
+// CanWriteCodebases returns true if the role can create codebases in the organization, and write to them.
+func (r Role) CanWriteCodebases() bool {
+	return r == RoleOwner || r == RoleAdmin || r == RoleMember
+}
+
+// CanReadCodebases returns true if the role can read all codebases of the organization.
+func (r Role) CanReadCodebases() bool {
+	return r.CanWriteCodebases() || r == RoleGuest
+}
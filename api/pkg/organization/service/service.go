@@ -21,6 +21,12 @@ import (
 	"github.com/google/uuid"
 )
 
+var (
+	ErrInvalidRole = errors.New("invalid role")
+	ErrOnlyOwners  = errors.New("only owners can manage owners")
+	ErrLastOwner   = errors.New("organization must have at least one owner")
+)
+
 type Service struct {
 	logger                       *zap.Logger
 	eventsSender                 *events.Publisher
@@ -108,8 +114,8 @@ func (svc *Service) Create(ctx context.Context, userID users.ID, name string) (*
 		return nil, fmt.Errorf("failed to create organization: %w", err)
 	}
 
-	// add the creator as a member
-	if _, err := svc.AddMember(ctx, org.ID, userID, userID); err != nil {
+	// add the creator as the owner
+	if _, err := svc.AddMember(ctx, org.ID, userID, userID, organization.RoleOwner); err != nil {
 		return nil, fmt.Errorf("failed to invite creator to organization: %w", err)
 	}
 
@@ -139,7 +145,13 @@ func (svc *Service) Update(ctx context.Context, organizationID string, newName s
 	return org, nil
 }
 
-func (svc *Service) AddMember(ctx context.Context, orgID string, userID, addedByUserID users.ID) (*organization.Member, error) {
+// AddMember adds the user to the organization with the given role. If the user is already a member, the existing
+// membership is returned and the role is not changed.
+func (svc *Service) AddMember(ctx context.Context, orgID string, userID, addedByUserID users.ID, role organization.Role) (*organization.Member, error) {
+	if !role.Valid() {
+		return nil, ErrInvalidRole
+	}
+
 	if existing, err := svc.organizationMemberRepository.GetByUserIDAndOrganizationID(ctx, userID, orgID); errors.Is(err, sql.ErrNoRows) {
 		// go on
 	} else if err != nil {
@@ -152,6 +164,7 @@ func (svc *Service) AddMember(ctx context.Context, orgID string, userID, addedBy
 		ID:             uuid.NewString(),
 		OrganizationID: orgID,
 		UserID:         userID,
+		Role:           role,
 		CreatedAt:      time.Now(),
 		CreatedBy:      addedByUserID,
 	}
@@ -181,6 +194,15 @@ func (svc *Service) RemoveMember(ctx context.Context, orgID string, userID users
 		return fmt.Errorf("could not get member: %w", err)
 	}
 
+	if member.EffectiveRole() == organization.RoleOwner {
+		if err := svc.CanAssignRole(ctx, orgID, deletedByUserID, organization.RoleOwner); err != nil {
+			return err
+		}
+		if err := svc.requireAnotherOwner(ctx, member); err != nil {
+			return err
+		}
+	}
+
 	t := time.Now()
 	member.DeletedAt = &t
 	member.DeletedBy = &deletedByUserID
@@ -197,6 +219,83 @@ func (svc *Service) RemoveMember(ctx context.Context, orgID string, userID users
 	return nil
 }
 
+// UpdateMemberRole changes the role of a member. Only owners can make other members owners, or change the role of
+// other owners, and the last owner can't be demoted.
+func (svc *Service) UpdateMemberRole(ctx context.Context, orgID string, userID users.ID, role organization.Role, updatedByUserID users.ID) (*organization.Member, error) {
+	if !role.Valid() {
+		return nil, ErrInvalidRole
+	}
+
+	member, err := svc.organizationMemberRepository.GetByUserIDAndOrganizationID(ctx, userID, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get member: %w", err)
+	}
+
+	previous := member.EffectiveRole()
+	if previous == role {
+		return member, nil
+	}
+
+	if previous == organization.RoleOwner {
+		if err := svc.CanAssignRole(ctx, orgID, updatedByUserID, previous); err != nil {
+			return nil, err
+		}
+		if err := svc.requireAnotherOwner(ctx, member); err != nil {
+			return nil, err
+		}
+	}
+	if err := svc.CanAssignRole(ctx, orgID, updatedByUserID, role); err != nil {
+		return nil, err
+	}
+
+	member.Role = role
+	if err := svc.organizationMemberRepository.Update(ctx, member); err != nil {
+		return nil, fmt.Errorf("could not update member: %w", err)
+	}
+
+	svc.analyticsService.Capture(ctx, "update organization member role",
+		analytics.OrganizationID(orgID),
+		analytics.Property("user_id", userID),
+		analytics.Property("role", role),
+	)
+
+	return member, nil
+}
+
+// CanAssignRole returns ErrOnlyOwners if the user is not allowed to give the role to other members. Owners can
+// be appointed by owners only. Other roles can be assigned by anyone who is allowed to manage the members.
+func (svc *Service) CanAssignRole(ctx context.Context, orgID string, userID users.ID, role organization.Role) error {
+	if role != organization.RoleOwner {
+		return nil
+	}
+
+	member, err := svc.organizationMemberRepository.GetByUserIDAndOrganizationID(ctx, userID, orgID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return ErrOnlyOwners
+	case err != nil:
+		return fmt.Errorf("could not get member: %w", err)
+	case member.EffectiveRole() != organization.RoleOwner:
+		return ErrOnlyOwners
+	default:
+		return nil
+	}
+}
+
+// requireAnotherOwner returns ErrLastOwner if the member is the only owner of the organization.
+func (svc *Service) requireAnotherOwner(ctx context.Context, member *organization.Member) error {
+	members, err := svc.organizationMemberRepository.ListByOrganizationID(ctx, member.OrganizationID)
+	if err != nil {
+		return fmt.Errorf("could not get members: %w", err)
+	}
+	for _, m := range members {
+		if m.ID != member.ID && m.EffectiveRole() == organization.RoleOwner {
+			return nil
+		}
+	}
+	return ErrLastOwner
+}
+
 func (svc *Service) GetByID(ctx context.Context, organizationID string) (*organization.Organization, error) {
 	member, err := svc.organizationRepository.Get(ctx, organizationID)
 	if err != nil {
@@ -0,0 +1,66 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"getsturdy.com/api/pkg/analytics/disabled"
+	service_analytics "getsturdy.com/api/pkg/analytics/service"
+	"getsturdy.com/api/pkg/organization"
+	db_organization "getsturdy.com/api/pkg/organization/db"
+	service_organization "getsturdy.com/api/pkg/organization/service"
+	"getsturdy.com/api/pkg/users"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestUpdateMemberRole(t *testing.T) {
+	ctx := context.Background()
+	analyticsService := service_analytics.New(zap.NewNop(), disabled.NewClient(zap.NewNop()))
+	svc := service_organization.New(zap.NewNop(), nil, db_organization.NewInMemoryOrganizationRepo(), db_organization.NewInMemoryOrganizationMemberRepository(), analyticsService, nil)
+
+	orgID := "org-id"
+	owner, admin, member := users.ID("owner"), users.ID("admin"), users.ID("member")
+	for userID, role := range map[users.ID]organization.Role{
+		owner:  organization.RoleOwner,
+		admin:  organization.RoleAdmin,
+		member: organization.RoleMember,
+	} {
+		_, err := svc.AddMember(ctx, orgID, userID, userID, role)
+		require.NoError(t, err)
+	}
+
+	_, err := svc.UpdateMemberRole(ctx, orgID, member, "superuser", admin)
+	assert.ErrorIs(t, err, service_organization.ErrInvalidRole)
+
+	// only owners can appoint owners
+	_, err = svc.UpdateMemberRole(ctx, orgID, member, organization.RoleOwner, admin)
+	assert.ErrorIs(t, err, service_organization.ErrOnlyOwners)
+
+	// only owners can demote owners
+	_, err = svc.UpdateMemberRole(ctx, orgID, owner, organization.RoleMember, admin)
+	assert.ErrorIs(t, err, service_organization.ErrOnlyOwners)
+
+	// the last owner can't be demoted, or removed
+	_, err = svc.UpdateMemberRole(ctx, orgID, owner, organization.RoleAdmin, owner)
+	assert.ErrorIs(t, err, service_organization.ErrLastOwner)
+	assert.ErrorIs(t, svc.RemoveMember(ctx, orgID, owner, owner), service_organization.ErrLastOwner)
+
+	updated, err := svc.UpdateMemberRole(ctx, orgID, member, organization.RoleGuest, admin)
+	require.NoError(t, err)
+	assert.Equal(t, organization.RoleGuest, updated.Role)
+
+	_, err = svc.UpdateMemberRole(ctx, orgID, admin, organization.RoleOwner, owner)
+	require.NoError(t, err)
+
+	// with another owner, the first one can step down
+	updated, err = svc.UpdateMemberRole(ctx, orgID, owner, organization.RoleMember, owner)
+	require.NoError(t, err)
+	assert.Equal(t, organization.RoleMember, updated.Role)
+
+	got, err := svc.GetMember(ctx, orgID, owner)
+	require.NoError(t, err)
+	assert.Equal(t, organization.RoleMember, got.EffectiveRole())
+}
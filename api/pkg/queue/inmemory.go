@@ -106,6 +106,20 @@ func (q *InMemoryQueue) Publish(ctx context.Context, name names.IncompleteQueueN
 	}
 }
 
+// PublishDelayed publishes the message once the delay has passed. The message is kept in memory until then, and is
+// lost if the process exits.
+func (q *InMemoryQueue) PublishDelayed(_ context.Context, name names.IncompleteQueueName, msg any, delay time.Duration) error {
+	if _, err := newInmemoryMessage(msg); err != nil {
+		return fmt.Errorf("failed to create message: %w", err)
+	}
+	time.AfterFunc(delay, func() {
+		if err := q.Publish(context.Background(), name, msg); err != nil {
+			q.logger.Error("failed to publish delayed message", zap.String("queue", name.String()), zap.Error(err))
+		}
+	})
+	return nil
+}
+
 func (q *InMemoryQueue) Subscribe(ctx context.Context, name names.IncompleteQueueName, messages chan<- Message) error {
 	q.logger.Info("subscribing to queue", zap.String("queue", name.String()))
 	ch := q.getChannel(name)
@@ -29,6 +29,9 @@ const (
 	GithubWebhooks                    IncompleteQueueName = "github_webhooks"
 	ViewSnapshot                      IncompleteQueueName = "view_snapshot"
 	CITriggerQueue                    IncompleteQueueName = "ci_trigger"
+	CodebaseMergeQueue                IncompleteQueueName = "codebase_merge_queue"
+	CodebaseWebhooks                  IncompleteQueueName = "codebase_webhooks"
+	CodebaseChat                      IncompleteQueueName = "codebase_chat"
 	longestAllowedName                IncompleteQueueName = "xxxxxXXXXXxxxxxXXXXXxxxx" // To highlight how long a name can be
 )
 
@@ -2,6 +2,7 @@ package queue
 
 import (
 	"context"
+	"time"
 
 	"getsturdy.com/api/pkg/queue/names"
 )
@@ -16,6 +17,10 @@ func (*noopQueue) Publish(context.Context, names.IncompleteQueueName, any) error
 	return nil
 }
 
+func (*noopQueue) PublishDelayed(context.Context, names.IncompleteQueueName, any, time.Duration) error {
+	return nil
+}
+
 func (*noopQueue) Subscribe(ctx context.Context, _ names.IncompleteQueueName, _ chan<- Message) error {
 	<-ctx.Done()
 	return nil
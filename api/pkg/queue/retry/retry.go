@@ -0,0 +1,29 @@
+package retry
+
+import (
+	"time"
+)
+
+// Backoff decides when a message that failed to be handled is attempted again. Messages are attempted a limited number
+// of times, and the delay is doubled for every retry.
+type Backoff struct {
+	// MaxAttempts is how many times a message is attempted before giving up.
+	MaxAttempts int
+	// Initial is the delay before the first retry.
+	Initial time.Duration
+}
+
+// Default is the backoff of messages that are sent to other services, such as webhooks and chat notifications.
+var Default = Backoff{
+	MaxAttempts: 5,
+	Initial:     30 * time.Second,
+}
+
+// Next returns for how long to wait before retrying a message that has been attempted attempts times. It returns false
+// if the message has no attempts left.
+func (b Backoff) Next(attempts int) (time.Duration, bool) {
+	if attempts >= b.MaxAttempts {
+		return 0, false
+	}
+	return b.Initial << (attempts - 1), true
+}
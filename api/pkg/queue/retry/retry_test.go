@@ -0,0 +1,23 @@
+package retry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff_Next(t *testing.T) {
+	b := Backoff{MaxAttempts: 3, Initial: time.Second}
+
+	wait, ok := b.Next(1)
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, wait)
+
+	wait, ok = b.Next(2)
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Second, wait)
+
+	_, ok = b.Next(3)
+	assert.False(t, ok)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"getsturdy.com/api/pkg/review"
+	"getsturdy.com/api/pkg/snapshots"
 	"getsturdy.com/api/pkg/users"
 
 	"github.com/jmoiron/sqlx"
@@ -19,8 +20,8 @@ func NewReviewRepository(db *sqlx.DB) ReviewRepository {
 }
 
 func (r *database) Create(ctx context.Context, rev review.Review) error {
-	_, err := r.db.NamedExecContext(ctx, `INSERT INTO workspace_reviews (id, codebase_id, workspace_id, user_id, grade, created_at, is_replaced, requested_by)
-		VALUES(:id, :codebase_id, :workspace_id, :user_id, :grade, :created_at, :is_replaced, :requested_by)`, rev)
+	_, err := r.db.NamedExecContext(ctx, `INSERT INTO workspace_reviews (id, codebase_id, workspace_id, user_id, grade, created_at, is_replaced, requested_by, snapshot_id)
+		VALUES(:id, :codebase_id, :workspace_id, :user_id, :grade, :created_at, :is_replaced, :requested_by, :snapshot_id)`, rev)
 	if err != nil {
 		return fmt.Errorf("failed to insert review: %w", err)
 	}
@@ -42,7 +43,7 @@ func (r *database) Update(ctx context.Context, rev *review.Review) error {
 
 func (r *database) Get(ctx context.Context, id string) (*review.Review, error) {
 	var res review.Review
-	err := r.db.GetContext(ctx, &res, `SELECT id, codebase_id, workspace_id, user_id, grade, created_at, dismissed_at, is_replaced, requested_by
+	err := r.db.GetContext(ctx, &res, `SELECT id, codebase_id, workspace_id, user_id, grade, created_at, dismissed_at, is_replaced, requested_by, snapshot_id
 		FROM workspace_reviews
 		WHERE id = $1`, id)
 	if err != nil {
@@ -53,7 +54,7 @@ func (r *database) Get(ctx context.Context, id string) (*review.Review, error) {
 
 func (r *database) GetLatestByUserAndWorkspace(ctx context.Context, userID users.ID, workspaceID string) (*review.Review, error) {
 	var res review.Review
-	err := r.db.GetContext(ctx, &res, `SELECT id, codebase_id, workspace_id, user_id, grade, created_at, dismissed_at, is_replaced, requested_by
+	err := r.db.GetContext(ctx, &res, `SELECT id, codebase_id, workspace_id, user_id, grade, created_at, dismissed_at, is_replaced, requested_by, snapshot_id
 		FROM workspace_reviews
 		WHERE workspace_id = $1
 	      AND user_id = $2
@@ -66,7 +67,7 @@ func (r *database) GetLatestByUserAndWorkspace(ctx context.Context, userID users
 
 func (r *database) ListLatestByWorkspace(ctx context.Context, workspaceID string) ([]*review.Review, error) {
 	var res []*review.Review
-	err := r.db.SelectContext(ctx, &res, `SELECT id, codebase_id, workspace_id, user_id, grade, created_at, dismissed_at, is_replaced, requested_by
+	err := r.db.SelectContext(ctx, &res, `SELECT id, codebase_id, workspace_id, user_id, grade, created_at, dismissed_at, is_replaced, requested_by, snapshot_id
 		FROM workspace_reviews
 		WHERE workspace_id = $1
 		AND dismissed_at IS NULL
@@ -76,3 +77,16 @@ func (r *database) ListLatestByWorkspace(ctx context.Context, workspaceID string
 	}
 	return res, nil
 }
+
+func (r *database) ListBySnapshotID(ctx context.Context, snapshotID snapshots.ID) ([]*review.Review, error) {
+	var res []*review.Review
+	err := r.db.SelectContext(ctx, &res, `SELECT id, codebase_id, workspace_id, user_id, grade, created_at, dismissed_at, is_replaced, requested_by, snapshot_id
+		FROM workspace_reviews
+		WHERE snapshot_id = $1
+		AND dismissed_at IS NULL
+		AND is_replaced IS FALSE`, snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list by snapshot: %w", err)
+	}
+	return res, nil
+}
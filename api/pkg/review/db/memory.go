@@ -5,6 +5,7 @@ import (
 	"database/sql"
 
 	"getsturdy.com/api/pkg/review"
+	"getsturdy.com/api/pkg/snapshots"
 	"getsturdy.com/api/pkg/users"
 )
 
@@ -84,3 +85,17 @@ func (m *memory) ListLatestByWorkspace(ctx context.Context, workspaceID string)
 	}
 	return rr, nil
 }
+
+func (m *memory) ListBySnapshotID(ctx context.Context, snapshotID snapshots.ID) ([]*review.Review, error) {
+	rr := []*review.Review{}
+	for _, review := range m.byID {
+		if review.SnapshotID == nil || *review.SnapshotID != snapshotID {
+			continue
+		}
+		if review.DismissedAt != nil || review.IsReplaced {
+			continue
+		}
+		rr = append(rr, review)
+	}
+	return rr, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"getsturdy.com/api/pkg/review"
+	"getsturdy.com/api/pkg/snapshots"
 	"getsturdy.com/api/pkg/users"
 )
 
@@ -13,4 +14,6 @@ type ReviewRepository interface {
 	Get(ctx context.Context, id string) (*review.Review, error)
 	GetLatestByUserAndWorkspace(ctx context.Context, userID users.ID, workspaceID string) (*review.Review, error)
 	ListLatestByWorkspace(ctx context.Context, workspaceID string) ([]*review.Review, error)
+	// ListBySnapshotID returns the reviews that were made against the snapshot, and that are neither dismissed nor replaced.
+	ListBySnapshotID(ctx context.Context, snapshotID snapshots.ID) ([]*review.Review, error)
 }
@@ -9,9 +9,13 @@ import (
 	eventsv2 "getsturdy.com/api/pkg/events/v2"
 	"getsturdy.com/api/pkg/graphql/resolvers"
 	"getsturdy.com/api/pkg/logger"
+	service_chat "getsturdy.com/api/pkg/notification/chat/service"
 	"getsturdy.com/api/pkg/notification/sender"
 	db_review "getsturdy.com/api/pkg/review/db"
+	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
+	service_webhooks "getsturdy.com/api/pkg/webhooks/service"
 	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
+	service_workspace "getsturdy.com/api/pkg/workspaces/service"
 	service_workspace_watchers "getsturdy.com/api/pkg/workspaces/watchers/service"
 )
 
@@ -20,6 +24,8 @@ func Module(c *di.Container) {
 	c.Import(db_review.Module)
 	c.Import(db_workspaces.Module)
 	c.Import(service_auth.Module)
+	c.Import(service_snapshots.Module)
+	c.Import(service_workspace.Module)
 	c.Import(grapqhl_author.Module)
 	c.Import(resolvers.Module)
 	c.Import(events.Module)
@@ -27,5 +33,7 @@ func Module(c *di.Container) {
 	c.Import(sender.Module)
 	c.Import(service_analytics.Module)
 	c.Import(service_workspace_watchers.Module)
+	c.Import(service_webhooks.Module)
+	c.Import(service_chat.Module)
 	c.Register(New)
 }
@@ -2,11 +2,15 @@ package graphql
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 
 	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
 	"getsturdy.com/api/pkg/graphql/resolvers"
 	"getsturdy.com/api/pkg/review"
+	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
+	"getsturdy.com/api/pkg/unidiff"
+	service_workspace "getsturdy.com/api/pkg/workspaces/service"
 
 	"github.com/graph-gophers/graphql-go"
 )
@@ -65,3 +69,44 @@ func (r *reviewResolver) Workspace(ctx context.Context) (resolvers.WorkspaceReso
 	}
 	return resolver, err
 }
+
+func (r *reviewResolver) Interdiff(ctx context.Context) ([]resolvers.FileDiffResolver, error) {
+	if r.rev.SnapshotID == nil {
+		return nil, nil
+	}
+
+	ws, err := r.root.workspaceReader.Get(r.rev.WorkspaceID)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	allower, err := r.root.authService.GetAllower(ctx, ws)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	reviewed, err := r.root.snapshotService.Diffs(ctx, *r.rev.SnapshotID, service_snapshots.WithAllower(allower))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		// the snapshot has been garbage collected
+		return nil, nil
+	case err != nil:
+		return nil, gqlerrors.Error(err)
+	}
+
+	current, _, err := r.root.workspaceService.Diffs(ctx, ws.ID, service_workspace.WithAllower(allower))
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	interdiff, err := unidiff.Interdiff(reviewed, current)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	res := make([]resolvers.FileDiffResolver, 0, len(interdiff))
+	for k := range interdiff {
+		res = append(res, (*r.root.fileDiffRootResolver).InternalFileDiff("interdiff-"+r.rev.ID, &interdiff[k]))
+	}
+	return res, nil
+}
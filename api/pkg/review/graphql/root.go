@@ -18,11 +18,16 @@ import (
 	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
 	"getsturdy.com/api/pkg/graphql/resolvers"
 	"getsturdy.com/api/pkg/notification"
+	service_chat "getsturdy.com/api/pkg/notification/chat/service"
 	"getsturdy.com/api/pkg/notification/sender"
 	"getsturdy.com/api/pkg/review"
 	db_review "getsturdy.com/api/pkg/review/db"
+	"getsturdy.com/api/pkg/snapshots"
+	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
 	"getsturdy.com/api/pkg/users"
+	service_webhooks "getsturdy.com/api/pkg/webhooks/service"
 	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
+	service_workspace "getsturdy.com/api/pkg/workspaces/service"
 	service_workspace_watchers "getsturdy.com/api/pkg/workspaces/watchers/service"
 
 	"github.com/google/uuid"
@@ -32,12 +37,15 @@ import (
 type reviewRootResolver struct {
 	logger *zap.Logger
 
-	reviewRepo      db_review.ReviewRepository
-	workspaceReader db_workspaces.WorkspaceReader
-	authService     *service_auth.Service
+	reviewRepo       db_review.ReviewRepository
+	workspaceReader  db_workspaces.WorkspaceReader
+	authService      *service_auth.Service
+	snapshotService  *service_snapshots.Service
+	workspaceService *service_workspace.Service
 
 	authorRootResolver    resolvers.AuthorRootResolver
 	workspaceRootResolver *resolvers.WorkspaceRootResolver
+	fileDiffRootResolver  *resolvers.FileDiffRootResolver
 
 	eventsSender       events.EventSender
 	eventPublisher     *eventsv2.Publisher
@@ -48,6 +56,8 @@ type reviewRootResolver struct {
 	analyticsService *service_analytics.Service
 
 	workspaceWatchersService *service_workspace_watchers.Service
+	webhooksService          *service_webhooks.Service
+	chatService              *service_chat.Service
 }
 
 func New(
@@ -55,9 +65,12 @@ func New(
 	reviewRepo db_review.ReviewRepository,
 	workspaceReader db_workspaces.WorkspaceReader,
 	authService *service_auth.Service,
+	snapshotService *service_snapshots.Service,
+	workspaceService *service_workspace.Service,
 
 	authorRootResolver resolvers.AuthorRootResolver,
 	workspaceRootResolver *resolvers.WorkspaceRootResolver,
+	fileDiffRootResolver *resolvers.FileDiffRootResolver,
 
 	eventsSender events.EventSender,
 	eventPublisher *eventsv2.Publisher,
@@ -68,16 +81,21 @@ func New(
 	analyticsService *service_analytics.Service,
 
 	workspaceWatchersService *service_workspace_watchers.Service,
+	webhooksService *service_webhooks.Service,
+	chatService *service_chat.Service,
 ) resolvers.ReviewRootResolver {
 	return &reviewRootResolver{
 		logger: logger.Named("reviewRootResolver"),
 
-		reviewRepo:      reviewRepo,
-		workspaceReader: workspaceReader,
-		authService:     authService,
+		reviewRepo:       reviewRepo,
+		workspaceReader:  workspaceReader,
+		authService:      authService,
+		snapshotService:  snapshotService,
+		workspaceService: workspaceService,
 
 		authorRootResolver:    authorRootResolver,
 		workspaceRootResolver: workspaceRootResolver,
+		fileDiffRootResolver:  fileDiffRootResolver,
 
 		eventsSender:       eventsSender,
 		eventPublisher:     eventPublisher,
@@ -88,6 +106,8 @@ func New(
 		analyticsService: analyticsService,
 
 		workspaceWatchersService: workspaceWatchersService,
+		webhooksService:          webhooksService,
+		chatService:              chatService,
 	}
 }
 
@@ -150,8 +170,8 @@ func (r *reviewRootResolver) CreateOrUpdateReview(ctx context.Context, args reso
 
 	// Mark existing as replaced
 	if existing, err := r.reviewRepo.GetLatestByUserAndWorkspace(ctx, userID, workspaceID); err == nil {
-		// If this review is the same as the existing one, and the review is not dismissed, don't change anything
-		if existing.DismissedAt == nil && existing.Grade == inputGrade {
+		// If this review is the same as the existing one, of the same snapshot, and the review is not dismissed, don't change anything
+		if existing.DismissedAt == nil && existing.Grade == inputGrade && sameSnapshot(existing.SnapshotID, ws.LatestSnapshotID) {
 			return &reviewResolver{root: r, rev: existing}, nil
 		}
 
@@ -173,6 +193,7 @@ func (r *reviewRootResolver) CreateOrUpdateReview(ctx context.Context, args reso
 		WorkspaceID: workspaceID,
 		Grade:       inputGrade,
 		CreatedAt:   time.Now(),
+		SnapshotID:  ws.LatestSnapshotID,
 	}
 
 	if err := r.reviewRepo.Create(ctx, rev); err != nil {
@@ -199,6 +220,16 @@ func (r *reviewRootResolver) CreateOrUpdateReview(ctx context.Context, args reso
 		// do not fail
 	}
 
+	if err := r.webhooksService.ReviewSubmitted(ctx, &rev); err != nil {
+		r.logger.Error("failed to send review submitted webhook", zap.Error(err))
+		// do not fail
+	}
+
+	if err := r.chatService.ReviewSubmitted(ctx, &rev); err != nil {
+		r.logger.Error("failed to send review submitted chat notification", zap.Error(err))
+		// do not fail
+	}
+
 	r.analyticsService.Capture(ctx, "review created",
 		analytics.CodebaseID(ws.CodebaseID),
 		analytics.Property("workspace_id", ws.ID),
@@ -208,6 +239,13 @@ func (r *reviewRootResolver) CreateOrUpdateReview(ctx context.Context, args reso
 	return &reviewResolver{root: r, rev: &rev}, nil
 }
 
+func sameSnapshot(a, b *snapshots.ID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
 func (r *reviewRootResolver) RequestReview(ctx context.Context, args resolvers.RequestReviewArgs) (resolvers.ReviewResolver, error) {
 	userID, err := auth.UserID(ctx)
 	if err != nil {
@@ -276,6 +314,11 @@ func (r *reviewRootResolver) RequestReview(ctx context.Context, args resolvers.R
 		return nil, gqlerrors.Error(fmt.Errorf("failed to send notification: %w", err))
 	}
 
+	if err := r.chatService.ReviewRequested(ctx, &rev); err != nil {
+		r.logger.Error("failed to send review requested chat notification", zap.Error(err))
+		// do not fail
+	}
+
 	// Send events
 	if err := r.eventsSender.Codebase(ws.CodebaseID, events.WorkspaceUpdatedReviews, ws.ID); err != nil {
 		r.logger.Error("failed to send codebase event", zap.Error(err))
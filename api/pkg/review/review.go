@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/snapshots"
 	"getsturdy.com/api/pkg/users"
 )
 
@@ -17,6 +18,9 @@ type Review struct {
 	DismissedAt *time.Time   `db:"dismissed_at"`
 	IsReplaced  bool         `db:"is_replaced"` // Is false for new reviews.
 	RequestedBy *users.ID    `db:"requested_by"`
+	// SnapshotID is the latest snapshot of the workspace when the review was made. Is nil for requested reviews, and
+	// for reviews made before snapshots were recorded.
+	SnapshotID *snapshots.ID `db:"snapshot_id"`
 }
 
 type ReviewGrade string
@@ -3,6 +3,8 @@ package db
 import (
 	"context"
 	"database/sql"
+	"sort"
+	"time"
 
 	"getsturdy.com/api/pkg/snapshots"
 )
@@ -46,7 +48,12 @@ func (f *snapshotRepo) Update(s *snapshots.Snapshot) error {
 }
 
 func (f *snapshotRepo) GetByCommitSHA(_ context.Context, sha string) (*snapshots.Snapshot, error) {
-	panic("not implemented")
+	for _, snap := range f.byID {
+		if snap.CommitSHA == sha {
+			return snap, nil
+		}
+	}
+	return nil, sql.ErrNoRows
 }
 
 func (f *snapshotRepo) ListByIDs(ctx context.Context, ids []snapshots.ID) ([]*snapshots.Snapshot, error) {
@@ -67,3 +74,23 @@ func (r *snapshotRepo) GetByPreviousSnapshotID(ctx context.Context, previousSnap
 	}
 	return nil, sql.ErrNoRows
 }
+
+func (r *snapshotRepo) ListByWorkspaceID(_ context.Context, workspaceID string, before *time.Time, limit int) ([]*snapshots.Snapshot, error) {
+	res := []*snapshots.Snapshot{}
+	for _, snap := range r.byID {
+		if snap.WorkspaceID != workspaceID || snap.IsDeleted() {
+			continue
+		}
+		if before != nil && !snap.CreatedAt.Before(*before) {
+			continue
+		}
+		res = append(res, snap)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].CreatedAt.After(res[j].CreatedAt)
+	})
+	if len(res) > limit {
+		res = res[:limit]
+	}
+	return res, nil
+}
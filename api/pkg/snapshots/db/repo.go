@@ -3,6 +3,7 @@ package db
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"getsturdy.com/api/pkg/snapshots"
 
@@ -16,6 +17,9 @@ type Repository interface {
 	GetByCommitSHA(context.Context, string) (*snapshots.Snapshot, error)
 	GetByPreviousSnapshotID(context.Context, snapshots.ID) (*snapshots.Snapshot, error)
 	ListByIDs(context.Context, []snapshots.ID) ([]*snapshots.Snapshot, error)
+	// ListByWorkspaceID returns up to limit snapshots of the workspace, newest first. If before is set, only snapshots
+	// created before it are returned.
+	ListByWorkspaceID(ctx context.Context, workspaceID string, before *time.Time, limit int) ([]*snapshots.Snapshot, error)
 	Get(snapshots.ID) (*snapshots.Snapshot, error)
 	Update(*snapshots.Snapshot) error
 }
@@ -113,3 +117,18 @@ func (r *dbrepo) GetByPreviousSnapshotID(ctx context.Context, previousSnapshotID
 	}
 	return &res, nil
 }
+
+func (r *dbrepo) ListByWorkspaceID(ctx context.Context, workspaceID string, before *time.Time, limit int) ([]*snapshots.Snapshot, error) {
+	var res []*snapshots.Snapshot
+	if err := r.db.SelectContext(ctx, &res, `SELECT id, created_at, previous_snapshot_id, codebase_id, commit_id, workspace_id,  action, diffs_count
+		FROM snapshots
+		WHERE workspace_id = $1
+		AND ($2::timestamptz IS NULL OR created_at < $2)
+		AND deleted_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT $3
+	`, workspaceID, before, limit); err != nil {
+		return nil, fmt.Errorf("failed to list by workspace id: %w", err)
+	}
+	return res, nil
+}
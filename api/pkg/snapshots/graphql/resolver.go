@@ -4,10 +4,14 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"sync"
 
 	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
 	"getsturdy.com/api/pkg/graphql/resolvers"
 	"getsturdy.com/api/pkg/snapshots"
+	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
+	"getsturdy.com/api/pkg/unidiff"
 
 	"github.com/graph-gophers/graphql-go"
 )
@@ -17,6 +21,33 @@ var _ resolvers.SnapshotResolver = (*resolver)(nil)
 type resolver struct {
 	root     *rootResolver
 	snapshot *snapshots.Snapshot
+
+	// statsBatch is set if the snapshot was resolved together with other snapshots of the workspace.
+	statsBatch *diffStatsBatch
+}
+
+// diffStatsBatch computes the diff stats of multiple snapshots at once.
+type diffStatsBatch struct {
+	snapshots []*snapshots.Snapshot
+
+	once  sync.Once
+	stats map[snapshots.ID]unidiff.Stats
+	err   error
+}
+
+func (b *diffStatsBatch) get(ctx context.Context, r *resolver) (unidiff.Stats, error) {
+	b.once.Do(func() {
+		allower, err := r.allower(ctx)
+		if err != nil {
+			b.err = err
+			return
+		}
+		b.stats, b.err = r.root.snapshotService.DiffStats(ctx, b.snapshots, service_snapshots.WithAllower(allower))
+	})
+	if b.err != nil {
+		return unidiff.Stats{}, b.err
+	}
+	return b.stats[r.snapshot.ID], nil
 }
 
 func (r *resolver) ID() graphql.ID {
@@ -61,11 +92,92 @@ func (r *resolver) Description(context.Context) (*string, error) {
 		return p("undo patch"), nil
 	case snapshots.ActionSuggestionApply:
 		return p("suggestion apply"), nil
+	case snapshots.ActionSuggestionLines:
+		return p("line suggestion"), nil
+	case snapshots.ActionRestore:
+		return p("restore"), nil
 	default:
 		return nil, nil
 	}
 }
 
+func (r *resolver) DiffStats(ctx context.Context) (resolvers.DiffStatsResolver, error) {
+	if r.statsBatch != nil {
+		stats, err := r.statsBatch.get(ctx, r)
+		if err != nil {
+			return nil, gqlerrors.Error(err)
+		}
+		return &diffStatsResolver{stats: stats}, nil
+	}
+
+	allower, err := r.allower(ctx)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+	diffs, err := r.root.snapshotService.Diffs(ctx, r.snapshot.ID, service_snapshots.WithAllower(allower))
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+	return &diffStatsResolver{stats: unidiff.NewStats(diffs)}, nil
+}
+
+func (r *resolver) Diffs(ctx context.Context, args resolvers.SnapshotDiffsArgs) ([]resolvers.FileDiffResolver, error) {
+	allower, err := r.allower(ctx)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	var diffs []unidiff.FileDiff
+	keyPrefix := string(r.snapshot.ID)
+	if args.Input != nil && args.Input.Against != nil {
+		keyPrefix += "_" + string(*args.Input.Against)
+		diffs, err = r.root.snapshotService.DiffBetween(ctx, snapshots.ID(*args.Input.Against), r.snapshot.ID, service_snapshots.WithAllower(allower))
+	} else {
+		diffs, err = r.root.snapshotService.Diffs(ctx, r.snapshot.ID, service_snapshots.WithAllower(allower))
+	}
+	switch {
+	case errors.Is(err, service_snapshots.ErrDifferentCodebases):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "against", err.Error())
+	case err != nil:
+		return nil, gqlerrors.Error(err)
+	}
+
+	res := make([]resolvers.FileDiffResolver, 0, len(diffs))
+	for k := range diffs {
+		res = append(res, r.root.fileDiffRootResolver.InternalFileDiff(keyPrefix, &diffs[k]))
+	}
+	return res, nil
+}
+
+// allower returns the allower of the workspace that the snapshot was taken of.
+func (r *resolver) allower(ctx context.Context) (*unidiff.Allower, error) {
+	ws, err := r.root.workspaceService.GetByID(ctx, r.snapshot.WorkspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspace: %w", err)
+	}
+	allower, err := r.root.authService.GetAllower(ctx, ws)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get allowed patterns: %w", err)
+	}
+	return allower, nil
+}
+
+type diffStatsResolver struct {
+	stats unidiff.Stats
+}
+
+func (r *diffStatsResolver) FilesChanged() int32 {
+	return r.stats.FilesChanged
+}
+
+func (r *diffStatsResolver) Additions() int32 {
+	return r.stats.Additions
+}
+
+func (r *diffStatsResolver) Deletions() int32 {
+	return r.stats.Deletions
+}
+
 func p[T any](v T) *T {
 	return &v
 }
@@ -3,21 +3,35 @@ package graphql
 import (
 	"context"
 
+	service_auth "getsturdy.com/api/pkg/auth/service"
 	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
 	"getsturdy.com/api/pkg/graphql/resolvers"
 	"getsturdy.com/api/pkg/snapshots"
 	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
+	service_workspace "getsturdy.com/api/pkg/workspaces/service"
 )
 
 type rootResolver struct {
-	snapshotService *service_snapshots.Service
+	snapshotService  *service_snapshots.Service
+	workspaceService *service_workspace.Service
+	authService      *service_auth.Service
+
+	fileDiffRootResolver resolvers.FileDiffRootResolver
 }
 
 func NewRoot(
 	snapshotService *service_snapshots.Service,
+	workspaceService *service_workspace.Service,
+	authService *service_auth.Service,
+
+	fileDiffRootResolver resolvers.FileDiffRootResolver,
 ) resolvers.SnapshotsRootResolver {
 	return &rootResolver{
-		snapshotService: snapshotService,
+		snapshotService:  snapshotService,
+		workspaceService: workspaceService,
+		authService:      authService,
+
+		fileDiffRootResolver: fileDiffRootResolver,
 	}
 }
 
@@ -26,8 +40,27 @@ func (r *rootResolver) InternalByID(ctx context.Context, id snapshots.ID) (resol
 	if err != nil {
 		return nil, gqlerrors.Error(err)
 	}
+	return r.InternalSnapshot(snap), nil
+}
+
+func (r *rootResolver) InternalSnapshot(snap *snapshots.Snapshot) resolvers.SnapshotResolver {
 	return &resolver{
 		snapshot: snap,
 		root:     r,
-	}, nil
+	}
+}
+
+// InternalSnapshots returns resolvers for snapshots of the same workspace. The diff stats of all of them are computed
+// together, the first time that any of them is resolved.
+func (r *rootResolver) InternalSnapshots(snaps []*snapshots.Snapshot) []resolvers.SnapshotResolver {
+	batch := &diffStatsBatch{snapshots: snaps}
+	res := make([]resolvers.SnapshotResolver, 0, len(snaps))
+	for _, snap := range snaps {
+		res = append(res, &resolver{
+			snapshot:   snap,
+			root:       r,
+			statsBatch: batch,
+		})
+	}
+	return res
 }
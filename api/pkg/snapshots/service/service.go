@@ -179,8 +179,32 @@ func (s *Service) Next(ctx context.Context, snapshot *snapshots.Snapshot) (*snap
 var (
 	ErrCantSnapshotRebasing    = errors.New("can't snapshot, rebasing in progress")
 	ErrCantSnapshotWrongBranch = errors.New("can't snapshot, unexpected branch")
+	ErrWrongWorkspace          = errors.New("snapshot is not from the workspace")
+	ErrDifferentCodebases      = errors.New("snapshots are from different codebases")
 )
 
+// History returns up to limit snapshots of the workspace, newest first. If before is set, the history continues from
+// the snapshot that was taken before it.
+func (s *Service) History(ctx context.Context, workspaceID string, before *snapshots.ID, limit int) ([]*snapshots.Snapshot, error) {
+	var beforeTime *time.Time
+	if before != nil {
+		snap, err := s.snapshotsRepo.Get(*before)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get snapshot: %w", err)
+		}
+		if snap.WorkspaceID != workspaceID {
+			return nil, ErrWrongWorkspace
+		}
+		beforeTime = &snap.CreatedAt
+	}
+
+	history, err := s.snapshotsRepo.ListByWorkspaceID(ctx, workspaceID, beforeTime, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	return history, nil
+}
+
 func (s *Service) Delete(ctx context.Context, snapshot *snapshots.Snapshot) error {
 	now := time.Now()
 	snapshot.DeletedAt = &now
@@ -575,43 +599,107 @@ func (s *Service) Diffs(ctx context.Context, snapshotID snapshots.ID, oo ...Diff
 }
 
 func (s *Service) diffs(ctx context.Context, snapshot *snapshots.Snapshot, oo ...DiffsOption) ([]unidiff.FileDiff, error) {
-	options := getDiffOptions(oo...)
-
 	var diffs []unidiff.FileDiff
 	if err := s.executorProvider.New().GitRead(func(repo vcs.RepoGitReader) error {
-		snapParent, err := repo.GetCommitParents(snapshot.CommitSHA)
-		if err != nil {
-			return fmt.Errorf("failed to get commit parents: %w", err)
-		}
-		if len(snapParent) != 1 {
-			return fmt.Errorf("unexpected number of snapshot parents: %d, expected %d", len(snapParent), 1)
+		var err error
+		diffs, err = s.snapshotDiffs(repo, snapshot, oo...)
+		return err
+	}).ExecTrunk(snapshot.CodebaseID, "snapshotDiffs"); err != nil {
+		return nil, fmt.Errorf("failed to get diffs from snapshot: %w", err)
+	}
+	return diffs, nil
+}
+
+// DiffStats returns the size of the changes in each of the snapshots. All snapshots are diffed in a single
+// execution, so they must be from the same codebase.
+func (s *Service) DiffStats(ctx context.Context, snaps []*snapshots.Snapshot, oo ...DiffsOption) (map[snapshots.ID]unidiff.Stats, error) {
+	stats := make(map[snapshots.ID]unidiff.Stats, len(snaps))
+	if len(snaps) == 0 {
+		return stats, nil
+	}
+
+	codebaseID := snaps[0].CodebaseID
+	for _, snap := range snaps {
+		if snap.CodebaseID != codebaseID {
+			return nil, ErrDifferentCodebases
 		}
+	}
 
-		gitDiffs, err := repo.DiffCommits(snapParent[0], snapshot.CommitSHA)
-		if err != nil {
-			return fmt.Errorf("failed to get git diffs: %w", err)
+	if err := s.executorProvider.New().GitRead(func(repo vcs.RepoGitReader) error {
+		for _, snap := range snaps {
+			diffs, err := s.snapshotDiffs(repo, snap, oo...)
+			if err != nil {
+				return fmt.Errorf("snapshot %s: %w", snap.ID, err)
+			}
+			stats[snap.ID] = unidiff.NewStats(diffs)
 		}
-		defer gitDiffs.Free()
+		return nil
+	}).ExecTrunk(codebaseID, "snapshotDiffStats"); err != nil {
+		return nil, fmt.Errorf("failed to get diff stats of snapshots: %w", err)
+	}
+	return stats, nil
+}
 
-		differ := unidiff.NewUnidiff(unidiff.NewGitPatchReader(gitDiffs), s.logger).
-			WithExpandedHunks()
+// snapshotDiffs returns the changes in the workspace when the snapshot was taken.
+func (s *Service) snapshotDiffs(repo vcs.RepoGitReader, snapshot *snapshots.Snapshot, oo ...DiffsOption) ([]unidiff.FileDiff, error) {
+	snapParent, err := repo.GetCommitParents(snapshot.CommitSHA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit parents: %w", err)
+	}
+	if len(snapParent) != 1 {
+		return nil, fmt.Errorf("unexpected number of snapshot parents: %d, expected %d", len(snapParent), 1)
+	}
+	return s.diffCommits(repo, snapParent[0], snapshot.CommitSHA, oo...)
+}
 
-		if options.Allower != nil {
-			differ = differ.WithAllower(options.Allower)
-		}
+// DiffBetween returns the diffs from the contents of one snapshot to the contents of another. The snapshots must be
+// from the same codebase, but they can be from different workspaces, or be based on different changes.
+func (s *Service) DiffBetween(ctx context.Context, fromID, toID snapshots.ID, oo ...DiffsOption) ([]unidiff.FileDiff, error) {
+	from, err := s.snapshotsRepo.Get(fromID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get snapshot: %w", err)
+	}
+	to, err := s.snapshotsRepo.Get(toID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get snapshot: %w", err)
+	}
+	if from.CodebaseID != to.CodebaseID {
+		return nil, ErrDifferentCodebases
+	}
 
-		if options.PatchIDs != nil {
-			differ = differ.WithHunksFilter(*options.PatchIDs...)
-		}
+	var diffs []unidiff.FileDiff
+	if err := s.executorProvider.New().GitRead(func(repo vcs.RepoGitReader) error {
+		diffs, err = s.diffCommits(repo, from.CommitSHA, to.CommitSHA, oo...)
+		return err
+	}).ExecTrunk(to.CodebaseID, "snapshotDiffBetween"); err != nil {
+		return nil, fmt.Errorf("failed to get diffs between snapshots: %w", err)
+	}
+	return diffs, nil
+}
 
-		hunkifiedDiff, err := differ.Decorate()
-		if err != nil {
-			return fmt.Errorf("failed to decorate diffs: %w", err)
-		}
-		diffs = hunkifiedDiff
-		return nil
-	}).ExecTrunk(snapshot.CodebaseID, "snapshotDiffs"); err != nil {
-		return nil, fmt.Errorf("failed to get diffs from snapshot: %w", err)
+func (s *Service) diffCommits(repo vcs.RepoGitReader, fromSHA, toSHA string, oo ...DiffsOption) ([]unidiff.FileDiff, error) {
+	options := getDiffOptions(oo...)
+
+	gitDiffs, err := repo.DiffCommits(fromSHA, toSHA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get git diffs: %w", err)
+	}
+	defer gitDiffs.Free()
+
+	differ := unidiff.NewUnidiff(unidiff.NewGitPatchReader(gitDiffs), s.logger).
+		WithExpandedHunks()
+
+	if options.Allower != nil {
+		differ = differ.WithAllower(options.Allower)
+	}
+
+	if options.PatchIDs != nil {
+		differ = differ.WithHunksFilter(*options.PatchIDs...)
+	}
+
+	diffs, err := differ.Decorate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decorate diffs: %w", err)
 	}
 	return diffs, nil
 }
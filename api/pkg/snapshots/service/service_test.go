@@ -19,9 +19,12 @@ import (
 	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
 	db_statuses "getsturdy.com/api/pkg/statuses/db"
 	db_suggestions "getsturdy.com/api/pkg/suggestions/db"
+	"getsturdy.com/api/pkg/unidiff"
 	"getsturdy.com/api/pkg/users"
 	db_view "getsturdy.com/api/pkg/views/db"
 	service_view "getsturdy.com/api/pkg/views/service"
+	db_webhooks "getsturdy.com/api/pkg/webhooks/db"
+	"getsturdy.com/api/pkg/workspaces"
 	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
 	service_workspace "getsturdy.com/api/pkg/workspaces/service"
 	"getsturdy.com/api/vcs"
@@ -47,6 +50,7 @@ func testModule(t *testing.T) di.Module {
 		c.ImportWithForce(db_codebases.TestModule)
 		c.ImportWithForce(db_installations.TestModule)
 		c.ImportWithForce(db_statuses.TestModule)
+		c.ImportWithForce(db_webhooks.TestModule)
 		c.ImportWithForce(module_queue.TestModule(t))
 		c.ImportWithForce(configuration.TestModule)
 		c.RegisterWithForce(logger.NewTest)
@@ -199,6 +203,86 @@ func TestSnapshot_should_not_fail_with_non_existing_commit_sha(t *testing.T) {
 	assert.NotEqual(t, snapOnce.ID, snapTwice.ID, "snapshots must not be identical, can't compare with non existing commit")
 }
 
+func TestHistory(t *testing.T) {
+	tc := setup(t)
+	ctx := context.Background()
+
+	var taken []*snapshots.Snapshot
+	for _, content := range []string{"one", "two", "three"} {
+		assert.NoError(t, tc.executorProvider.New().Write(writeFile("test.txt", []byte(content))).ExecView(tc.codebaseID, tc.viewID, "make some changes"))
+		snap, err := tc.snapshotService.Snapshot(ctx, tc.codebaseID, tc.workspaceID, snapshots.ActionViewSync, service_snapshots.WithOnView(tc.viewID), service_snapshots.WithNoThrottle())
+		assert.NoError(t, err)
+		taken = append(taken, snap)
+	}
+
+	history, err := tc.snapshotService.History(ctx, tc.workspaceID, nil, 2)
+	assert.NoError(t, err)
+	if assert.Len(t, history, 2) {
+		assert.Equal(t, taken[2].ID, history[0].ID)
+		assert.Equal(t, taken[1].ID, history[1].ID)
+	}
+
+	history, err = tc.snapshotService.History(ctx, tc.workspaceID, &history[1].ID, 2)
+	assert.NoError(t, err)
+	if assert.Len(t, history, 1) {
+		assert.Equal(t, taken[0].ID, history[0].ID)
+	}
+
+	_, err = tc.snapshotService.History(ctx, "other-workspace", &taken[0].ID, 2)
+	assert.ErrorIs(t, err, service_snapshots.ErrWrongWorkspace)
+
+	diffs, err := tc.snapshotService.DiffBetween(ctx, taken[0].ID, taken[2].ID)
+	assert.NoError(t, err)
+	if assert.Len(t, diffs, 1) {
+		assert.Equal(t, "test.txt", diffs[0].PreferredName)
+	}
+
+	stats, err := tc.snapshotService.DiffStats(ctx, taken)
+	assert.NoError(t, err)
+	if assert.Len(t, stats, 3) {
+		for _, snap := range taken {
+			assert.Equal(t, unidiff.Stats{FilesChanged: 1, Additions: 1}, stats[snap.ID])
+		}
+	}
+}
+
+func TestRestoreSnapshot(t *testing.T) {
+	tc := setup(t)
+	ctx := context.Background()
+
+	assert.NoError(t, tc.executorProvider.New().Write(writeFile("test.txt", []byte("one"))).ExecView(tc.codebaseID, tc.viewID, "make some changes"))
+	first, err := tc.snapshotService.Snapshot(ctx, tc.codebaseID, tc.workspaceID, snapshots.ActionViewSync, service_snapshots.WithOnView(tc.viewID))
+	assert.NoError(t, err)
+
+	assert.NoError(t, tc.executorProvider.New().Write(writeFile("test.txt", []byte("two"))).ExecView(tc.codebaseID, tc.viewID, "make some changes"))
+	second, err := tc.snapshotService.Snapshot(ctx, tc.codebaseID, tc.workspaceID, snapshots.ActionViewSync, service_snapshots.WithOnView(tc.viewID), service_snapshots.WithNoThrottle())
+	assert.NoError(t, err)
+
+	ws, err := tc.workspaceService.GetByID(ctx, tc.workspaceID)
+	assert.NoError(t, err)
+
+	restored, err := tc.workspaceService.RestoreSnapshot(ctx, ws, first, &users.User{ID: tc.userID, Name: "Test", Email: "test@getsturdy.com"})
+	assert.NoError(t, err)
+	assert.NotEqual(t, first.ID, restored.ID, "the restore must be a new snapshot")
+	assert.Equal(t, snapshots.ActionRestore, restored.Action)
+	if assert.NotNil(t, restored.PreviousSnapshotID) {
+		assert.Equal(t, second.ID, *restored.PreviousSnapshotID, "the restore must be undoable")
+	}
+
+	ws, err = tc.workspaceService.GetByID(ctx, tc.workspaceID)
+	assert.NoError(t, err)
+	if assert.NotNil(t, ws.LatestSnapshotID) {
+		assert.Equal(t, restored.ID, *ws.LatestSnapshotID)
+	}
+
+	diffs, err := tc.snapshotService.DiffBetween(ctx, first.ID, restored.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, diffs, "the restored snapshot must have the contents of the first snapshot")
+
+	_, err = tc.workspaceService.RestoreSnapshot(ctx, &workspaces.Workspace{ID: "other-workspace"}, first, nil)
+	assert.ErrorIs(t, err, service_snapshots.ErrWrongWorkspace)
+}
+
 func writeFile(filename string, content []byte) func(vcs.RepoWriter) error {
 	return func(repo vcs.RepoWriter) error {
 		file, err := os.Create(path.Join(repo.Path(), filename))
@@ -61,5 +61,7 @@ const (
 	ActionWorkspaceExtract          Action = "workspace_extract"
 	ActionChangeReverted            Action = "change_reverted"
 	ActionSuggestionApply           Action = "suggestion_apply"
+	ActionSuggestionLines           Action = "suggestion_lines"
 	ActionCITrigger                 Action = "ci_trigger"
+	ActionRestore                   Action = "restore"
 )
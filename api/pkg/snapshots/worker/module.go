@@ -1,11 +1,13 @@
 package worker
 
 import (
+	service_codeowners "getsturdy.com/api/pkg/codeowners/service"
 	"getsturdy.com/api/pkg/di"
 	"getsturdy.com/api/pkg/logger"
 	queue "getsturdy.com/api/pkg/queue/module"
 	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
 	service_users "getsturdy.com/api/pkg/users/service/module"
+	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
 )
 
 func Module(c *di.Container) {
@@ -13,5 +15,7 @@ func Module(c *di.Container) {
 	c.Import(queue.Module)
 	c.Import(service_snapshots.Module)
 	c.Import(service_users.Module)
+	c.Import(service_codeowners.Module)
+	c.Import(db_workspaces.Module)
 	c.Register(New)
 }
@@ -7,12 +7,14 @@ import (
 	"time"
 
 	"getsturdy.com/api/pkg/codebases"
+	service_codeowners "getsturdy.com/api/pkg/codeowners/service"
 	"getsturdy.com/api/pkg/queue"
 	"getsturdy.com/api/pkg/queue/names"
 	"getsturdy.com/api/pkg/snapshots"
 	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
 	"getsturdy.com/api/pkg/users"
 	service_users "getsturdy.com/api/pkg/users/service"
+	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
 
 	"go.uber.org/zap"
 )
@@ -27,8 +29,10 @@ type q struct {
 	queue  queue.Queue
 	name   names.IncompleteQueueName
 
-	snapshotter *service_snapshots.Service
-	userService service_users.Service
+	snapshotter       *service_snapshots.Service
+	userService       service_users.Service
+	codeownersService *service_codeowners.Service
+	workspaceReader   db_workspaces.WorkspaceReader
 }
 
 func New(
@@ -36,13 +40,17 @@ func New(
 	queue queue.Queue,
 	snapshotter *service_snapshots.Service,
 	userService service_users.Service,
+	codeownersService *service_codeowners.Service,
+	workspaceReader db_workspaces.WorkspaceReader,
 ) Queue {
 	return &q{
-		logger:      logger.Named("snapshotterQueue"),
-		queue:       queue,
-		name:        names.ViewSnapshot,
-		snapshotter: snapshotter,
-		userService: userService,
+		logger:            logger.Named("snapshotterQueue"),
+		queue:             queue,
+		name:              names.ViewSnapshot,
+		snapshotter:       snapshotter,
+		userService:       userService,
+		codeownersService: codeownersService,
+		workspaceReader:   workspaceReader,
 	}
 }
 
@@ -133,6 +141,8 @@ func (q *q) Start(ctx context.Context) error {
 			}
 
 			logger.Info("created snapshot", zap.Duration("duration", time.Since(t0)))
+
+			q.requestReviews(m.WorkspaceID, logger)
 		}
 	}()
 
@@ -144,3 +154,20 @@ func (q *q) Start(ctx context.Context) error {
 
 	return nil
 }
+
+// requestReviews requests reviews from the owners of the files that are changed in the workspace.
+func (q *q) requestReviews(workspaceID string, logger *zap.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	ws, err := q.workspaceReader.Get(workspaceID)
+	if err != nil {
+		logger.Error("failed to get workspace", zap.Error(err))
+		return
+	}
+
+	if err := q.codeownersService.RequestReviews(ctx, ws); err != nil {
+		logger.Error("failed to request reviews from code owners", zap.Error(err))
+		return
+	}
+}
@@ -0,0 +1,100 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"getsturdy.com/api/pkg/sso"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var _ SettingsRepository = &settingsDatabase{}
+
+type settingsDatabase struct {
+	db *sqlx.DB
+}
+
+func NewSettingsDatabase(db *sqlx.DB) SettingsRepository {
+	return &settingsDatabase{db: db}
+}
+
+func (d *settingsDatabase) Get(ctx context.Context, organizationID string) (*sso.Settings, error) {
+	var s sso.Settings
+	if err := d.db.GetContext(ctx, &s, `
+		SELECT
+			organization_id, enforce_sso, jit_membership, email_domain, updated_by, updated_at
+		FROM organization_sso_settings
+		WHERE organization_id = $1
+	`, organizationID); err != nil {
+		return nil, fmt.Errorf("failed to get sso settings: %w", err)
+	}
+	return &s, nil
+}
+
+func (d *settingsDatabase) Upsert(ctx context.Context, s *sso.Settings) error {
+	if _, err := d.db.NamedExecContext(ctx, `
+		INSERT INTO organization_sso_settings
+			(organization_id, enforce_sso, jit_membership, email_domain, updated_by, updated_at)
+		VALUES
+			(:organization_id, :enforce_sso, :jit_membership, :email_domain, :updated_by, :updated_at)
+		ON CONFLICT (organization_id) DO UPDATE
+		SET
+			enforce_sso = :enforce_sso,
+			jit_membership = :jit_membership,
+			email_domain = :email_domain,
+			updated_by = :updated_by,
+			updated_at = :updated_at
+	`, s); err != nil {
+		return fmt.Errorf("failed to upsert sso settings: %w", err)
+	}
+	return nil
+}
+
+func (d *settingsDatabase) ListJITByEmailDomain(ctx context.Context, domain string) ([]*sso.Settings, error) {
+	var settings []*sso.Settings
+	if err := d.db.SelectContext(ctx, &settings, `
+		SELECT
+			organization_id, enforce_sso, jit_membership, email_domain, updated_by, updated_at
+		FROM organization_sso_settings
+		WHERE jit_membership AND email_domain = $1
+	`, domain); err != nil {
+		return nil, fmt.Errorf("failed to list sso settings: %w", err)
+	}
+	return settings, nil
+}
+
+var _ IdentityRepository = &identityDatabase{}
+
+type identityDatabase struct {
+	db *sqlx.DB
+}
+
+func NewIdentityDatabase(db *sqlx.DB) IdentityRepository {
+	return &identityDatabase{db: db}
+}
+
+func (d *identityDatabase) Get(ctx context.Context, issuer, subject string) (*sso.Identity, error) {
+	var i sso.Identity
+	if err := d.db.GetContext(ctx, &i, `
+		SELECT
+			issuer, subject, user_id, created_at
+		FROM sso_identities
+		WHERE issuer = $1 AND subject = $2
+	`, issuer, subject); err != nil {
+		return nil, fmt.Errorf("failed to get sso identity: %w", err)
+	}
+	return &i, nil
+}
+
+func (d *identityDatabase) Create(ctx context.Context, i *sso.Identity) error {
+	if _, err := d.db.NamedExecContext(ctx, `
+		INSERT INTO sso_identities
+			(issuer, subject, user_id, created_at)
+		VALUES
+			(:issuer, :subject, :user_id, :created_at)
+	`, i); err != nil {
+		return fmt.Errorf("failed to create sso identity: %w", err)
+	}
+	return nil
+}
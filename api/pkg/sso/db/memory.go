@@ -0,0 +1,87 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"getsturdy.com/api/pkg/sso"
+)
+
+var _ SettingsRepository = &settingsMemory{}
+
+type settingsMemory struct {
+	mu               sync.RWMutex
+	byOrganizationID map[string]sso.Settings
+}
+
+func NewSettingsMemory() SettingsRepository {
+	return &settingsMemory{
+		byOrganizationID: make(map[string]sso.Settings),
+	}
+}
+
+func (m *settingsMemory) Get(_ context.Context, organizationID string) (*sso.Settings, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	s, found := m.byOrganizationID[organizationID]
+	if !found {
+		return nil, sql.ErrNoRows
+	}
+	return &s, nil
+}
+
+func (m *settingsMemory) Upsert(_ context.Context, s *sso.Settings) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.byOrganizationID[s.OrganizationID] = *s
+	return nil
+}
+
+func (m *settingsMemory) ListJITByEmailDomain(_ context.Context, domain string) ([]*sso.Settings, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var settings []*sso.Settings
+	for _, s := range m.byOrganizationID {
+		if s.JITMembership && s.EmailDomain != nil && *s.EmailDomain == domain {
+			s := s
+			settings = append(settings, &s)
+		}
+	}
+	return settings, nil
+}
+
+var _ IdentityRepository = &identityMemory{}
+
+type identityKey struct {
+	issuer  string
+	subject string
+}
+
+type identityMemory struct {
+	mu         sync.RWMutex
+	identities map[identityKey]sso.Identity
+}
+
+func NewIdentityMemory() IdentityRepository {
+	return &identityMemory{
+		identities: make(map[identityKey]sso.Identity),
+	}
+}
+
+func (m *identityMemory) Get(_ context.Context, issuer, subject string) (*sso.Identity, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	i, found := m.identities[identityKey{issuer: issuer, subject: subject}]
+	if !found {
+		return nil, sql.ErrNoRows
+	}
+	return &i, nil
+}
+
+func (m *identityMemory) Create(_ context.Context, i *sso.Identity) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.identities[identityKey{issuer: i.Issuer, subject: i.Subject}] = *i
+	return nil
+}
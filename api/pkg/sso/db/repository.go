@@ -0,0 +1,21 @@
+package db
+
+import (
+	"context"
+
+	"getsturdy.com/api/pkg/sso"
+)
+
+type SettingsRepository interface {
+	// Get returns sql.ErrNoRows if the organization has no settings.
+	Get(ctx context.Context, organizationID string) (*sso.Settings, error)
+	Upsert(context.Context, *sso.Settings) error
+	// ListJITByEmailDomain returns the settings of organizations with just-in-time membership for the domain.
+	ListJITByEmailDomain(ctx context.Context, domain string) ([]*sso.Settings, error)
+}
+
+type IdentityRepository interface {
+	// Get returns sql.ErrNoRows if the identity is not linked to a user.
+	Get(ctx context.Context, issuer, subject string) (*sso.Identity, error)
+	Create(context.Context, *sso.Identity) error
+}
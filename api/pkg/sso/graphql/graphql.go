@@ -0,0 +1,128 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"getsturdy.com/api/pkg/auth"
+	service_auth "getsturdy.com/api/pkg/auth/service"
+	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
+	"getsturdy.com/api/pkg/graphql/resolvers"
+	service_organization "getsturdy.com/api/pkg/organization/service"
+	"getsturdy.com/api/pkg/sso"
+	service_sso "getsturdy.com/api/pkg/sso/service"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+type rootResolver struct {
+	ssoService          *service_sso.Service
+	organizationService *service_organization.Service
+	authService         *service_auth.Service
+
+	organizationRootResolver *resolvers.OrganizationRootResolver
+}
+
+func New(
+	ssoService *service_sso.Service,
+	organizationService *service_organization.Service,
+	authService *service_auth.Service,
+
+	organizationRootResolver *resolvers.OrganizationRootResolver,
+) resolvers.OrganizationSSOSettingsRootResolver {
+	return &rootResolver{
+		ssoService:          ssoService,
+		organizationService: organizationService,
+		authService:         authService,
+
+		organizationRootResolver: organizationRootResolver,
+	}
+}
+
+func (r *rootResolver) OrganizationSSOSettings(ctx context.Context, args resolvers.OrganizationSSOSettingsArgs) (resolvers.OrganizationSSOSettingsResolver, error) {
+	org, err := r.organizationService.GetByID(ctx, string(args.OrganizationID))
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	if err := r.authService.CanRead(ctx, org); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	settings, err := r.ssoService.GetSettings(ctx, org.ID)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	return &resolver{root: r, settings: settings}, nil
+}
+
+func (r *rootResolver) UpdateOrganizationSSOSettings(ctx context.Context, args resolvers.UpdateOrganizationSSOSettingsArgs) (resolvers.OrganizationSSOSettingsResolver, error) {
+	userID, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	org, err := r.organizationService.GetByID(ctx, string(args.Input.OrganizationID))
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	if err := r.authService.CanAdmin(ctx, org); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	settings, err := r.ssoService.GetSettings(ctx, org.ID)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	if args.Input.EnforceSSO != nil {
+		settings.EnforceSSO = *args.Input.EnforceSSO
+	}
+	if args.Input.JITMembership != nil {
+		settings.JITMembership = *args.Input.JITMembership
+	}
+	if args.Input.EmailDomain != nil {
+		settings.EmailDomain = args.Input.EmailDomain
+	}
+
+	if err := r.ssoService.UpdateSettings(ctx, settings, userID); errors.Is(err, service_sso.ErrSSONotConfigured) || errors.Is(err, service_sso.ErrInvalidEmailDomain) {
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", err.Error())
+	} else if err != nil {
+		return nil, gqlerrors.Error(fmt.Errorf("failed to update sso settings: %w", err))
+	}
+
+	return &resolver{root: r, settings: settings}, nil
+}
+
+type resolver struct {
+	root     *rootResolver
+	settings *sso.Settings
+}
+
+func (r *resolver) Organization(ctx context.Context) (resolvers.OrganizationResolver, error) {
+	id := graphql.ID(r.settings.OrganizationID)
+	return (*r.root.organizationRootResolver).Organization(ctx, resolvers.OrganizationArgs{ID: &id})
+}
+
+func (r *resolver) EnforceSSO() bool {
+	return r.settings.EnforceSSO
+}
+
+func (r *resolver) JITMembership() bool {
+	return r.settings.JITMembership
+}
+
+func (r *resolver) EmailDomain() *string {
+	return r.settings.EmailDomain
+}
+
+func (r *resolver) UpdatedAt() *int32 {
+	if r.settings.UpdatedAt.IsZero() {
+		return nil
+	}
+	t := int32(r.settings.UpdatedAt.Unix())
+	return &t
+}
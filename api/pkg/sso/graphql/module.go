@@ -0,0 +1,17 @@
+package graphql
+
+import (
+	service_auth "getsturdy.com/api/pkg/auth/service"
+	"getsturdy.com/api/pkg/di"
+	"getsturdy.com/api/pkg/graphql/resolvers"
+	service_organization "getsturdy.com/api/pkg/organization/service"
+	service_sso "getsturdy.com/api/pkg/sso/service"
+)
+
+func Module(c *di.Container) {
+	c.Import(service_sso.Module)
+	c.Import(service_organization.Module)
+	c.Import(service_auth.Module)
+	c.Import(resolvers.Module)
+	c.Register(New)
+}
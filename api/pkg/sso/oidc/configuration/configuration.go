@@ -0,0 +1,12 @@
+package configuration
+
+type Configuration struct {
+	Enable       bool     `long:"enable" description:"Allow users to log in with an OpenID Connect provider" env:"ENABLE"`
+	Name         string   `long:"name" description:"Name of the provider, shown on the login page" default:"SSO" env:"NAME"`
+	Issuer       string   `long:"issuer" description:"Issuer URL of the provider, for example 'https://accounts.google.com'" env:"ISSUER"`
+	ClientID     string   `long:"client-id" description:"Client ID registered with the provider" env:"CLIENT_ID"`
+	ClientSecret string   `long:"client-secret" description:"Client secret registered with the provider" env:"CLIENT_SECRET"`
+	RedirectURL  string   `long:"redirect-url" description:"URL that the provider redirects to after login, must point to /v3/auth/oidc/callback of the api" default:"http://localhost:3000/v3/auth/oidc/callback" env:"REDIRECT_URL"`
+	AppURL       string   `long:"app-url" description:"URL of the web app, users are redirected to it after login" default:"http://localhost:8080" env:"APP_URL"`
+	Scopes       []string `long:"scope" description:"Scopes to request from the provider" default:"openid" default:"email" default:"profile" env:"SCOPES" env-delim:","`
+}
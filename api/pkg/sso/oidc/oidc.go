@@ -0,0 +1,304 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"getsturdy.com/api/pkg/sso/oidc/configuration"
+
+	"golang.org/x/oauth2"
+	"gopkg.in/square/go-jose.v2"
+	jose_jwt "gopkg.in/square/go-jose.v2/jwt"
+)
+
+var (
+	ErrDisabled     = errors.New("oidc is not enabled")
+	ErrInvalidToken = errors.New("id token is invalid")
+)
+
+// Provider implements the authorization code flow with PKCE against an OpenID Connect provider. The provider
+// configuration is discovered the first time it's needed.
+type Provider struct {
+	cfg    *configuration.Configuration
+	client *http.Client
+
+	mu        sync.Mutex
+	discovery *discovery
+	keys      *jose.JSONWebKeySet
+}
+
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+func New(cfg *configuration.Configuration) *Provider {
+	return &Provider{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *Provider) Enabled() bool {
+	return p.cfg != nil && p.cfg.Enable
+}
+
+func (p *Provider) Name() string {
+	return p.cfg.Name
+}
+
+// Request is the state of a login that is in progress. It's kept by the user agent between the redirect to the
+// provider and the callback.
+type Request struct {
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+	// Redirect is where the user is sent after the login, relative to the app url.
+	Redirect string `json:"redirect"`
+}
+
+func NewRequest(redirect string) (*Request, error) {
+	state, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+	return &Request{State: state, Nonce: nonce, Verifier: verifier, Redirect: redirect}, nil
+}
+
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random string: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Claims are the claims of a verified id token.
+type Claims struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// AuthCodeURL returns the url of the provider that the user should be redirected to, to start the login.
+func (p *Provider) AuthCodeURL(ctx context.Context, req *Request) (string, error) {
+	cfg, err := p.oauth2Config(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	challenge := sha256.Sum256([]byte(req.Verifier))
+	return cfg.AuthCodeURL(req.State,
+		oauth2.SetAuthURLParam("nonce", req.Nonce),
+		oauth2.SetAuthURLParam("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:])),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+	), nil
+}
+
+// Exchange exchanges the code that the provider redirected back with for an id token, and returns its claims
+// once the token is verified.
+func (p *Provider) Exchange(ctx context.Context, req *Request, code string) (*Claims, error) {
+	cfg, err := p.oauth2Config(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := cfg.Exchange(context.WithValue(ctx, oauth2.HTTPClient, p.client), code,
+		oauth2.SetAuthURLParam("code_verifier", req.Verifier),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange code: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, fmt.Errorf("%w: no id token in response", ErrInvalidToken)
+	}
+
+	return p.verify(ctx, rawIDToken, req.Nonce)
+}
+
+type idTokenClaims struct {
+	jose_jwt.Claims
+	Nonce         string `json:"nonce"`
+	Email         string `json:"email"`
+	EmailVerified any    `json:"email_verified"`
+	Name          string `json:"name"`
+}
+
+func (p *Provider) verify(ctx context.Context, rawIDToken, nonce string) (*Claims, error) {
+	token, err := jose_jwt.ParseSigned(rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidToken, err)
+	}
+	if len(token.Headers) != 1 {
+		return nil, fmt.Errorf("%w: unexpected number of signatures", ErrInvalidToken)
+	}
+
+	key, err := p.key(ctx, token.Headers[0].KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims idTokenClaims
+	if err := token.Claims(key, &claims); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidToken, err)
+	}
+
+	d, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := claims.ValidateWithLeeway(jose_jwt.Expected{
+		Issuer:   d.Issuer,
+		Audience: jose_jwt.Audience{p.cfg.ClientID},
+		Time:     time.Now(),
+	}, time.Minute); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidToken, err)
+	}
+	if claims.Expiry == nil {
+		return nil, fmt.Errorf("%w: no expiry", ErrInvalidToken)
+	}
+	if claims.Nonce != nonce {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidToken)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: no subject", ErrInvalidToken)
+	}
+
+	return &Claims{
+		Issuer:        claims.Issuer,
+		Subject:       claims.Subject,
+		Email:         strings.TrimSpace(claims.Email),
+		EmailVerified: isTrue(claims.EmailVerified),
+		Name:          claims.Name,
+	}, nil
+}
+
+// isTrue returns true if the claim is true, some providers send booleans as strings.
+func isTrue(claim any) bool {
+	switch v := claim.(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	default:
+		return false
+	}
+}
+
+// key returns the signing key with the given id. Keys are refetched if the key is not known, as providers rotate
+// their keys.
+func (p *Provider) key(ctx context.Context, keyID string) (*jose.JSONWebKey, error) {
+	p.mu.Lock()
+	keys := p.keys
+	p.mu.Unlock()
+
+	if keys != nil {
+		if found := keys.Key(keyID); len(found) > 0 {
+			return &found[0], nil
+		}
+	}
+
+	d, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keys = &jose.JSONWebKeySet{}
+	if err := p.getJSON(ctx, d.JWKSURI, keys); err != nil {
+		return nil, fmt.Errorf("failed to get signing keys: %w", err)
+	}
+
+	p.mu.Lock()
+	p.keys = keys
+	p.mu.Unlock()
+
+	if found := keys.Key(keyID); len(found) > 0 {
+		return &found[0], nil
+	}
+	return nil, fmt.Errorf("%w: unknown signing key %q", ErrInvalidToken, keyID)
+}
+
+func (p *Provider) oauth2Config(ctx context.Context) (*oauth2.Config, error) {
+	d, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &oauth2.Config{
+		ClientID:     p.cfg.ClientID,
+		ClientSecret: p.cfg.ClientSecret,
+		RedirectURL:  p.cfg.RedirectURL,
+		Scopes:       p.cfg.Scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  d.AuthorizationEndpoint,
+			TokenURL: d.TokenEndpoint,
+		},
+	}, nil
+}
+
+func (p *Provider) getDiscovery(ctx context.Context) (*discovery, error) {
+	if !p.Enabled() {
+		return nil, ErrDisabled
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	issuer := strings.TrimSuffix(p.cfg.Issuer, "/")
+	var d discovery
+	if err := p.getJSON(ctx, issuer+"/.well-known/openid-configuration", &d); err != nil {
+		return nil, fmt.Errorf("failed to discover provider: %w", err)
+	}
+	if strings.TrimSuffix(d.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("issuer %q of the provider does not match %q", d.Issuer, p.cfg.Issuer)
+	}
+
+	p.discovery = &d
+	return p.discovery, nil
+}
+
+func (p *Provider) getJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, url)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
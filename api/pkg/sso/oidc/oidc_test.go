@@ -0,0 +1,204 @@
+package oidc_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"getsturdy.com/api/pkg/sso/oidc"
+	"getsturdy.com/api/pkg/sso/oidc/configuration"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+	jose_jwt "gopkg.in/square/go-jose.v2/jwt"
+)
+
+// idp is a minimal OpenID Connect provider. Codes are issued by authorize, and exchanged for an id token with
+// the claims that are set on the provider.
+type idp struct {
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu     sync.Mutex
+	claims map[string]any
+	codes  map[string]url.Values
+}
+
+func newIDP(t *testing.T) *idp {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	p := &idp{t: t, key: key, codes: map[string]url.Values{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 p.server.URL,
+			"authorization_endpoint": p.server.URL + "/authorize",
+			"token_endpoint":         p.server.URL + "/token",
+			"jwks_uri":               p.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: key.Public(), KeyID: "key-id", Algorithm: string(jose.RS256), Use: "sig"},
+		}})
+	})
+	mux.HandleFunc("/token", p.token)
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+
+	return p
+}
+
+// authorize returns a code for the authorization url, as if the user had logged in.
+func (p *idp) authorize(authURL string) string {
+	u, err := url.Parse(authURL)
+	require.NoError(p.t, err)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	code := u.Query().Get("state") + "-code"
+	p.codes[code] = u.Query()
+	return code
+}
+
+func (p *idp) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	p.mu.Lock()
+	params, ok := p.codes[r.PostForm.Get("code")]
+	claims := p.claims
+	p.mu.Unlock()
+	if !ok {
+		http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+		return
+	}
+
+	challenge := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if params.Get("code_challenge_method") != "S256" || params.Get("code_challenge") != base64.RawURLEncoding.EncodeToString(challenge[:]) {
+		http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+		return
+	}
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: p.key}, (&jose.SignerOptions{}).WithHeader("kid", "key-id"))
+	require.NoError(p.t, err)
+
+	idToken, err := jose_jwt.Signed(signer).Claims(jose_jwt.Claims{
+		Issuer:   p.server.URL,
+		Subject:  "subject",
+		Audience: jose_jwt.Audience{"client-id"},
+		Expiry:   jose_jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		IssuedAt: jose_jwt.NewNumericDate(time.Now()),
+	}).Claims(map[string]any{"nonce": params.Get("nonce")}).Claims(claims).CompactSerialize()
+	require.NoError(p.t, err)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"access_token": "access-token",
+		"token_type":   "Bearer",
+		"id_token":     idToken,
+	})
+}
+
+func newProvider(p *idp) *oidc.Provider {
+	return oidc.New(&configuration.Configuration{
+		Enable:      true,
+		Issuer:      p.server.URL,
+		ClientID:    "client-id",
+		RedirectURL: "http://localhost:3000/v3/auth/oidc/callback",
+		Scopes:      []string{"openid", "email", "profile"},
+	})
+}
+
+func TestExchange(t *testing.T) {
+	ctx := context.Background()
+	idp := newIDP(t)
+	idp.claims = map[string]any{"email": "alice@example.com", "email_verified": true, "name": "Alice"}
+	provider := newProvider(idp)
+
+	req, err := oidc.NewRequest("/codebases")
+	require.NoError(t, err)
+
+	authURL, err := provider.AuthCodeURL(ctx, req)
+	require.NoError(t, err)
+
+	claims, err := provider.Exchange(ctx, req, idp.authorize(authURL))
+	require.NoError(t, err)
+	assert.Equal(t, &oidc.Claims{
+		Issuer:        idp.server.URL,
+		Subject:       "subject",
+		Email:         "alice@example.com",
+		EmailVerified: true,
+		Name:          "Alice",
+	}, claims)
+}
+
+func TestExchange_wrongVerifier(t *testing.T) {
+	ctx := context.Background()
+	idp := newIDP(t)
+	provider := newProvider(idp)
+
+	req, err := oidc.NewRequest("")
+	require.NoError(t, err)
+	authURL, err := provider.AuthCodeURL(ctx, req)
+	require.NoError(t, err)
+
+	req.Verifier = "something else"
+	_, err = provider.Exchange(ctx, req, idp.authorize(authURL))
+	assert.Error(t, err)
+}
+
+func TestExchange_wrongNonce(t *testing.T) {
+	ctx := context.Background()
+	idp := newIDP(t)
+	provider := newProvider(idp)
+
+	req, err := oidc.NewRequest("")
+	require.NoError(t, err)
+	authURL, err := provider.AuthCodeURL(ctx, req)
+	require.NoError(t, err)
+
+	req.Nonce = "something else"
+	_, err = provider.Exchange(ctx, req, idp.authorize(authURL))
+	assert.ErrorIs(t, err, oidc.ErrInvalidToken)
+}
+
+func TestExchange_wrongAudience(t *testing.T) {
+	ctx := context.Background()
+	idp := newIDP(t)
+	idp.claims = map[string]any{"aud": "another-client-id"}
+	provider := newProvider(idp)
+
+	req, err := oidc.NewRequest("")
+	require.NoError(t, err)
+	authURL, err := provider.AuthCodeURL(ctx, req)
+	require.NoError(t, err)
+
+	_, err = provider.Exchange(ctx, req, idp.authorize(authURL))
+	assert.ErrorIs(t, err, oidc.ErrInvalidToken)
+}
+
+func TestDisabled(t *testing.T) {
+	provider := oidc.New(&configuration.Configuration{})
+	assert.False(t, provider.Enabled())
+
+	req, err := oidc.NewRequest("")
+	require.NoError(t, err)
+	_, err = provider.AuthCodeURL(context.Background(), req)
+	assert.ErrorIs(t, err, oidc.ErrDisabled)
+}
@@ -0,0 +1,179 @@
+package routes
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"getsturdy.com/api/pkg/analytics"
+	service_analytics "getsturdy.com/api/pkg/analytics/service"
+	"getsturdy.com/api/pkg/auth"
+	service_jwt "getsturdy.com/api/pkg/jwt/service"
+	"getsturdy.com/api/pkg/sso/oidc"
+	"getsturdy.com/api/pkg/sso/oidc/configuration"
+	service_sso "getsturdy.com/api/pkg/sso/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	requestCookieName = "oidc_request"
+	requestCookiePath = "/v3/auth/oidc"
+	// requestMaxAge is the time that users have to log in with the provider, in seconds.
+	requestMaxAge = 10 * 60
+)
+
+// Get returns if single sign-on is enabled, and the name of the provider to show on the login page.
+func Get(provider *oidc.Provider) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		if !provider.Enabled() {
+			c.JSON(http.StatusOK, gin.H{"enabled": false})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"enabled": true, "name": provider.Name()})
+	}
+}
+
+// Login redirects the user to the provider. The state of the login is kept in a cookie until the provider
+// redirects back to Callback.
+func Login(logger *zap.Logger, cfg *configuration.Configuration, provider *oidc.Provider) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		if !provider.Enabled() {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "single sign-on is not enabled"})
+			return
+		}
+
+		req, err := oidc.NewRequest(safeRedirect(c.Query("redirect")))
+		if err != nil {
+			logger.Error("failed to create oidc request", zap.Error(err))
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		authURL, err := provider.AuthCodeURL(c.Request.Context(), req)
+		if err != nil {
+			logger.Error("failed to get oidc auth url", zap.Error(err))
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		value, err := json.Marshal(req)
+		if err != nil {
+			logger.Error("failed to marshal oidc request", zap.Error(err))
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		setRequestCookie(c, cfg, base64.RawURLEncoding.EncodeToString(value), requestMaxAge)
+		c.Redirect(http.StatusFound, authURL)
+	}
+}
+
+// Callback is where the provider redirects to after the user has logged in.
+func Callback(
+	logger *zap.Logger,
+	cfg *configuration.Configuration,
+	provider *oidc.Provider,
+	ssoService *service_sso.Service,
+	jwtService *service_jwt.Service,
+	analyticsService *service_analytics.Service,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		cookie, err := c.Cookie(requestCookieName)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "login expired, please try again"})
+			return
+		}
+		// the request can only be used once
+		setRequestCookie(c, cfg, "", -1)
+
+		var req oidc.Request
+		if value, err := base64.RawURLEncoding.DecodeString(cookie); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid login request"})
+			return
+		} else if err := json.Unmarshal(value, &req); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid login request"})
+			return
+		}
+
+		if req.State == "" || c.Query("state") != req.State {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid login request"})
+			return
+		}
+
+		if errCode := c.Query("error"); errCode != "" {
+			logger.Warn("oidc provider returned an error", zap.String("error", errCode), zap.String("description", c.Query("error_description")))
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "login failed"})
+			return
+		}
+
+		ctx := c.Request.Context()
+		claims, err := provider.Exchange(ctx, &req, c.Query("code"))
+		if errors.Is(err, oidc.ErrInvalidToken) {
+			logger.Warn("invalid id token", zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "login failed"})
+			return
+		} else if err != nil {
+			logger.Error("failed to exchange oidc code", zap.Error(err))
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		user, err := ssoService.Login(ctx, claims)
+		if errors.Is(err, service_sso.ErrEmailNotVerified) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Your email is not verified by the identity provider"})
+			return
+		} else if err != nil {
+			logger.Error("failed to login with oidc", zap.Error(err))
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		if err := auth.SetAuthCookieForUser(c, user.ID, jwtService, service_jwt.WithSSO(true)); err != nil {
+			logger.Error("failed to set auth cookie", zap.Error(err))
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		analyticsService.IdentifyUser(ctx, user)
+		analyticsService.CaptureUser(ctx, user.ID, "logged in", analytics.Property("type", "oidc"))
+
+		c.Redirect(http.StatusFound, strings.TrimSuffix(cfg.AppURL, "/")+req.Redirect)
+	}
+}
+
+// setRequestCookie sets the cookie with the state of the login. The cookie is only sent over https if the provider
+// redirects back to the api over https, the scheme of the request is not known behind a proxy.
+func setRequestCookie(c *gin.Context, cfg *configuration.Configuration, value string, maxAge int) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     requestCookieName,
+		Value:    value,
+		MaxAge:   maxAge,
+		Path:     requestCookiePath,
+		SameSite: http.SameSiteLaxMode, // the provider redirects back with a top level navigation
+		Secure:   isSecure(cfg),
+		HttpOnly: true,
+	})
+}
+
+// isSecure returns true if the provider redirects back to the api over https.
+func isSecure(cfg *configuration.Configuration) bool {
+	u, err := url.Parse(cfg.RedirectURL)
+	return err == nil && u.Scheme == "https"
+}
+
+// safeRedirect returns the path to redirect to after login. Only paths of the app are allowed.
+func safeRedirect(redirect string) string {
+	if strings.Contains(redirect, `\`) {
+		return "/"
+	}
+	u, err := url.Parse(redirect)
+	if err != nil || u.IsAbs() || u.Host != "" || !strings.HasPrefix(u.Path, "/") || strings.HasPrefix(u.Path, "//") {
+		return "/"
+	}
+	return u.RequestURI()
+}
@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"testing"
+
+	"getsturdy.com/api/pkg/sso/oidc/configuration"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSafeRedirect(t *testing.T) {
+	cases := map[string]string{
+		"":                          "/",
+		"/codebases":                "/codebases",
+		"/codebases?tab=changes":    "/codebases?tab=changes",
+		"codebases":                 "/",
+		"https://evil.example.com/": "/",
+		"//evil.example.com/":       "/",
+		`/\evil.example.com/`:       "/",
+	}
+	for redirect, expected := range cases {
+		assert.Equal(t, expected, safeRedirect(redirect), redirect)
+	}
+}
+
+func TestIsSecure(t *testing.T) {
+	assert.True(t, isSecure(&configuration.Configuration{RedirectURL: "https://api.example.com/v3/auth/oidc/callback"}))
+	assert.False(t, isSecure(&configuration.Configuration{RedirectURL: "http://localhost:3000/v3/auth/oidc/callback"}))
+}
@@ -0,0 +1,21 @@
+package service
+
+import (
+	"getsturdy.com/api/pkg/di"
+	"getsturdy.com/api/pkg/logger"
+	service_organization "getsturdy.com/api/pkg/organization/service"
+	db_sso "getsturdy.com/api/pkg/sso/db"
+	"getsturdy.com/api/pkg/sso/oidc"
+	db_user "getsturdy.com/api/pkg/users/db"
+	service_user "getsturdy.com/api/pkg/users/service/module"
+)
+
+func Module(c *di.Container) {
+	c.Import(logger.Module)
+	c.Import(db_sso.Module)
+	c.Import(db_user.Module)
+	c.Import(service_user.Module)
+	c.Import(service_organization.Module)
+	c.Import(oidc.Module)
+	c.Register(New)
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"getsturdy.com/api/pkg/organization"
+	service_organization "getsturdy.com/api/pkg/organization/service"
+	"getsturdy.com/api/pkg/sso"
+	db_sso "getsturdy.com/api/pkg/sso/db"
+	"getsturdy.com/api/pkg/sso/oidc"
+	"getsturdy.com/api/pkg/users"
+	db_user "getsturdy.com/api/pkg/users/db"
+	service_user "getsturdy.com/api/pkg/users/service"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrEmailNotVerified   = errors.New("email is not verified by the identity provider")
+	ErrSSORequired        = errors.New("organization requires single sign-on")
+	ErrSSONotConfigured   = errors.New("single sign-on is not configured")
+	ErrInvalidEmailDomain = errors.New("invalid email domain")
+)
+
+type Service struct {
+	logger *zap.Logger
+
+	settingsRepo        db_sso.SettingsRepository
+	identityRepo        db_sso.IdentityRepository
+	userRepo            db_user.Repository
+	userService         service_user.Service
+	organizationService *service_organization.Service
+	provider            *oidc.Provider
+}
+
+func New(
+	logger *zap.Logger,
+
+	settingsRepo db_sso.SettingsRepository,
+	identityRepo db_sso.IdentityRepository,
+	userRepo db_user.Repository,
+	userService service_user.Service,
+	organizationService *service_organization.Service,
+	provider *oidc.Provider,
+) *Service {
+	return &Service{
+		logger: logger.Named("ssoService"),
+
+		settingsRepo:        settingsRepo,
+		identityRepo:        identityRepo,
+		userRepo:            userRepo,
+		userService:         userService,
+		organizationService: organizationService,
+		provider:            provider,
+	}
+}
+
+type referer struct {
+	issuer string
+}
+
+func (r *referer) URL() string {
+	u := url.URL{
+		Scheme: "referer",
+		Host:   "oidc",
+		Path:   url.PathEscape(r.issuer),
+	}
+	return u.String()
+}
+
+// Login returns the user that the claims belong to. Users are first looked up by identity, then by email. If
+// there is no user with the email, a new one is created. Only emails that are verified by the identity provider
+// are trusted.
+func (s *Service) Login(ctx context.Context, claims *oidc.Claims) (*users.User, error) {
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, ErrEmailNotVerified
+	}
+
+	user, err := s.userByClaims(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.EmailVerified {
+		user.EmailVerified = true
+		if err := s.userRepo.Update(user); err != nil {
+			return nil, fmt.Errorf("failed to update user: %w", err)
+		}
+	}
+
+	if err := s.userService.Activate(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to activate user: %w", err)
+	}
+
+	if err := s.addToOrganizations(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *Service) userByClaims(ctx context.Context, claims *oidc.Claims) (*users.User, error) {
+	identity, err := s.identityRepo.Get(ctx, claims.Issuer, claims.Subject)
+	switch {
+	case err == nil:
+		user, err := s.userService.GetByID(ctx, identity.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
+		return user, nil
+	case errors.Is(err, sql.ErrNoRows):
+	default:
+		return nil, fmt.Errorf("failed to get identity: %w", err)
+	}
+
+	user, err := s.userService.GetByEmail(ctx, claims.Email)
+	if errors.Is(err, sql.ErrNoRows) {
+		var name *string
+		if claims.Name != "" {
+			name = &claims.Name
+		}
+		if user, err = s.userService.CreateShadow(ctx, claims.Email, &referer{issuer: claims.Issuer}, name); err != nil {
+			return nil, fmt.Errorf("failed to create user: %w", err)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get user by email: %w", err)
+	}
+
+	if err := s.identityRepo.Create(ctx, &sso.Identity{
+		Issuer:    claims.Issuer,
+		Subject:   claims.Subject,
+		UserID:    user.ID,
+		CreatedAt: time.Now(),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to link identity: %w", err)
+	}
+
+	return user, nil
+}
+
+// addToOrganizations adds the user to the organizations that have just-in-time membership for the domain of the
+// user's email.
+func (s *Service) addToOrganizations(ctx context.Context, user *users.User) error {
+	domain := emailDomain(user.Email)
+	if domain == "" {
+		return nil
+	}
+
+	settings, err := s.settingsRepo.ListJITByEmailDomain(ctx, domain)
+	if err != nil {
+		return fmt.Errorf("failed to list sso settings: %w", err)
+	}
+
+	for _, ss := range settings {
+		// users join by themselves, nobody is notified
+		if _, err := s.organizationService.AddMember(ctx, ss.OrganizationID, user.ID, user.ID, organization.RoleMember); err != nil {
+			return fmt.Errorf("failed to add user to organization: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func emailDomain(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return ""
+	}
+	return strings.ToLower(email[at+1:])
+}
+
+// CheckPasswordLogin returns ErrSSORequired if the user is a member of an organization that enforces single
+// sign-on, and can't log in with a password or a magic link.
+func (s *Service) CheckPasswordLogin(ctx context.Context, user *users.User) error {
+	orgs, err := s.organizationService.ListByUserID(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list organizations: %w", err)
+	}
+
+	for _, org := range orgs {
+		settings, err := s.GetSettings(ctx, org.ID)
+		if err != nil {
+			return err
+		}
+		if settings.EnforceSSO {
+			return ErrSSORequired
+		}
+	}
+
+	return nil
+}
+
+// GetSettings returns the settings of the organization. If the organization has no settings, settings with
+// single sign-on disabled are returned.
+func (s *Service) GetSettings(ctx context.Context, organizationID string) (*sso.Settings, error) {
+	settings, err := s.settingsRepo.Get(ctx, organizationID)
+	switch {
+	case err == nil:
+		return settings, nil
+	case errors.Is(err, sql.ErrNoRows):
+		return &sso.Settings{OrganizationID: organizationID}, nil
+	default:
+		return nil, fmt.Errorf("failed to get sso settings: %w", err)
+	}
+}
+
+func (s *Service) UpdateSettings(ctx context.Context, settings *sso.Settings, userID users.ID) error {
+	if settings.EnforceSSO && !s.provider.Enabled() {
+		return ErrSSONotConfigured
+	}
+
+	if settings.EmailDomain != nil {
+		domain := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(*settings.EmailDomain), "@"))
+		if domain == "" {
+			settings.EmailDomain = nil
+		} else if strings.ContainsAny(domain, "@/ ") || !strings.Contains(domain, ".") {
+			return ErrInvalidEmailDomain
+		} else {
+			settings.EmailDomain = &domain
+		}
+	}
+
+	if settings.JITMembership && settings.EmailDomain == nil {
+		return fmt.Errorf("%w: just-in-time membership requires an email domain", ErrInvalidEmailDomain)
+	}
+
+	settings.UpdatedBy = userID
+	settings.UpdatedAt = time.Now()
+	if err := s.settingsRepo.Upsert(ctx, settings); err != nil {
+		return fmt.Errorf("failed to update sso settings: %w", err)
+	}
+	return nil
+}
+
+// Enabled returns true if users can log in with single sign-on.
+func (s *Service) Enabled() bool {
+	return s.provider.Enabled()
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"getsturdy.com/api/pkg/analytics/disabled"
+	service_analytics "getsturdy.com/api/pkg/analytics/service"
+	"getsturdy.com/api/pkg/organization"
+	db_organization "getsturdy.com/api/pkg/organization/db"
+	service_organization "getsturdy.com/api/pkg/organization/service"
+	"getsturdy.com/api/pkg/sso"
+	db_sso "getsturdy.com/api/pkg/sso/db"
+	"getsturdy.com/api/pkg/sso/oidc"
+	"getsturdy.com/api/pkg/sso/oidc/configuration"
+	"getsturdy.com/api/pkg/users"
+	db_user "getsturdy.com/api/pkg/users/db"
+	service_user "getsturdy.com/api/pkg/users/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type testService struct {
+	*Service
+	userRepo db_user.Repository
+	orgRepo  db_organization.Repository
+}
+
+func newTestService(t *testing.T, oidcEnabled bool) *testService {
+	logger := zap.NewNop()
+	analyticsService := service_analytics.New(logger, disabled.NewClient(logger))
+	userRepo := db_user.NewMemory()
+	orgRepo := db_organization.NewInMemoryOrganizationRepo()
+	orgService := service_organization.New(logger, nil, orgRepo, db_organization.NewInMemoryOrganizationMemberRepository(), analyticsService, nil)
+
+	return &testService{
+		Service: New(
+			logger,
+			db_sso.NewSettingsMemory(),
+			db_sso.NewIdentityMemory(),
+			userRepo,
+			service_user.New(logger, userRepo, analyticsService),
+			orgService,
+			oidc.New(&configuration.Configuration{Enable: oidcEnabled}),
+		),
+		userRepo: userRepo,
+		orgRepo:  orgRepo,
+	}
+}
+
+func (s *testService) createOrganization(t *testing.T, id string) {
+	require.NoError(t, s.orgRepo.Create(context.Background(), organization.Organization{ID: id, Name: id}))
+}
+
+func TestLogin_createsUser(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService(t, true)
+	svc.createOrganization(t, "org-id")
+
+	domain := "Example.com"
+	require.NoError(t, svc.UpdateSettings(ctx, &sso.Settings{OrganizationID: "org-id", JITMembership: true, EmailDomain: &domain}, "admin-id"))
+
+	claims := &oidc.Claims{Issuer: "https://idp.example.com", Subject: "alice", Email: "alice@example.com", EmailVerified: true, Name: "Alice"}
+	user, err := svc.Login(ctx, claims)
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", user.Name)
+	assert.Equal(t, users.StatusActive, user.Status)
+	assert.True(t, user.EmailVerified)
+
+	member, err := svc.organizationService.GetMember(ctx, "org-id", user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, member.UserID)
+
+	// logging in again returns the same user
+	again, err := svc.Login(ctx, claims)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, again.ID)
+}
+
+func TestLogin_linksExistingUser(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService(t, true)
+
+	existing := &users.User{ID: "existing-id", Email: "bob@example.com", Status: users.StatusActive}
+	require.NoError(t, svc.userRepo.Create(existing))
+
+	user, err := svc.Login(ctx, &oidc.Claims{Issuer: "https://idp.example.com", Subject: "bob", Email: "bob@example.com", EmailVerified: true})
+	require.NoError(t, err)
+	assert.Equal(t, existing.ID, user.ID)
+	assert.True(t, user.EmailVerified)
+
+	identity, err := svc.identityRepo.Get(ctx, "https://idp.example.com", "bob")
+	require.NoError(t, err)
+	assert.Equal(t, existing.ID, identity.UserID)
+}
+
+func TestLogin_unverifiedEmail(t *testing.T) {
+	svc := newTestService(t, true)
+
+	_, err := svc.Login(context.Background(), &oidc.Claims{Issuer: "https://idp.example.com", Subject: "eve", Email: "eve@example.com"})
+	assert.ErrorIs(t, err, ErrEmailNotVerified)
+}
+
+func TestCheckPasswordLogin(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService(t, true)
+	svc.createOrganization(t, "org-id")
+
+	user := &users.User{ID: "user-id", Email: "alice@example.com"}
+	_, err := svc.organizationService.AddMember(ctx, "org-id", user.ID, user.ID, organization.RoleMember)
+	require.NoError(t, err)
+
+	assert.NoError(t, svc.CheckPasswordLogin(ctx, user))
+
+	require.NoError(t, svc.UpdateSettings(ctx, &sso.Settings{OrganizationID: "org-id", EnforceSSO: true}, "admin-id"))
+	assert.ErrorIs(t, svc.CheckPasswordLogin(ctx, user), ErrSSORequired)
+}
+
+func TestUpdateSettings(t *testing.T) {
+	ctx := context.Background()
+
+	svc := newTestService(t, false)
+	assert.ErrorIs(t, svc.UpdateSettings(ctx, &sso.Settings{OrganizationID: "org-id", EnforceSSO: true}, "admin-id"), ErrSSONotConfigured)
+
+	svc = newTestService(t, true)
+	assert.ErrorIs(t, svc.UpdateSettings(ctx, &sso.Settings{OrganizationID: "org-id", JITMembership: true}, "admin-id"), ErrInvalidEmailDomain)
+
+	invalid := "not a domain"
+	assert.ErrorIs(t, svc.UpdateSettings(ctx, &sso.Settings{OrganizationID: "org-id", EmailDomain: &invalid}, "admin-id"), ErrInvalidEmailDomain)
+
+	domain := " @Example.COM"
+	require.NoError(t, svc.UpdateSettings(ctx, &sso.Settings{OrganizationID: "org-id", JITMembership: true, EmailDomain: &domain}, "admin-id"))
+
+	settings, err := svc.GetSettings(ctx, "org-id")
+	require.NoError(t, err)
+	if assert.NotNil(t, settings.EmailDomain) {
+		assert.Equal(t, "example.com", *settings.EmailDomain)
+	}
+	assert.Equal(t, users.ID("admin-id"), settings.UpdatedBy)
+}
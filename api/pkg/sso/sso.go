@@ -0,0 +1,32 @@
+package sso
+
+import (
+	"time"
+
+	"getsturdy.com/api/pkg/users"
+)
+
+// Settings are the single sign-on settings of an organization.
+type Settings struct {
+	OrganizationID string `db:"organization_id"`
+
+	// EnforceSSO prevents members of the organization from logging in with a password or a magic link. Only
+	// sessions that were started with single sign-on can access the organization and its codebases, sessions and
+	// personal access tokens that were created in any other way are rejected.
+	EnforceSSO bool `db:"enforce_sso"`
+	// JITMembership adds users that log in with single sign-on, and have an email in EmailDomain, to the
+	// organization.
+	JITMembership bool    `db:"jit_membership"`
+	EmailDomain   *string `db:"email_domain"`
+
+	UpdatedBy users.ID  `db:"updated_by"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// Identity links a user of an identity provider to a Sturdy user.
+type Identity struct {
+	Issuer    string    `db:"issuer"`
+	Subject   string    `db:"subject"`
+	UserID    users.ID  `db:"user_id"`
+	CreatedAt time.Time `db:"created_at"`
+}
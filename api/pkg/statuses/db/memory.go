@@ -37,7 +37,20 @@ func (m *memory) ListByWorkspaceID(ctx context.Context, workspaceID string) ([]*
 	return nil, nil
 }
 
+// ListByCodebaseIDAndCommitID returns a list of latest statuses for commit_id grouped by title.
 func (m *memory) ListByCodebaseIDAndCommitID(ctx context.Context, codebaseID codebases.ID, commitID string) ([]*statuses.Status, error) {
-	// todo: implement
-	return nil, nil
+	latest := make(map[string]*statuses.Status)
+	for _, status := range m.byID {
+		if status.CodebaseID != codebaseID || status.CommitSHA != commitID {
+			continue
+		}
+		if l, ok := latest[status.Title]; !ok || status.Timestamp.After(l.Timestamp) {
+			latest[status.Title] = status
+		}
+	}
+	ss := make([]*statuses.Status, 0, len(latest))
+	for _, status := range latest {
+		ss = append(ss, status)
+	}
+	return ss, nil
 }
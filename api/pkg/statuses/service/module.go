@@ -4,12 +4,16 @@ import (
 	"getsturdy.com/api/pkg/di"
 	"getsturdy.com/api/pkg/events/v2"
 	"getsturdy.com/api/pkg/logger"
+	service_chat "getsturdy.com/api/pkg/notification/chat/service"
 	db_statuses "getsturdy.com/api/pkg/statuses/db"
+	service_webhooks "getsturdy.com/api/pkg/webhooks/service"
 )
 
 func Module(c *di.Container) {
 	c.Import(logger.Module)
 	c.Import(db_statuses.Module)
 	c.Import(events.Module)
+	c.Import(service_webhooks.Module)
+	c.Import(service_chat.Module)
 	c.Register(New)
 }
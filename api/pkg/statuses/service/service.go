@@ -9,25 +9,33 @@ import (
 
 	"getsturdy.com/api/pkg/codebases"
 	"getsturdy.com/api/pkg/events/v2"
+	service_chat "getsturdy.com/api/pkg/notification/chat/service"
 	"getsturdy.com/api/pkg/statuses"
 	db_statuses "getsturdy.com/api/pkg/statuses/db"
+	service_webhooks "getsturdy.com/api/pkg/webhooks/service"
 )
 
 type Service struct {
 	logger          *zap.Logger
 	repo            db_statuses.Repository
 	eventsPublisher *events.Publisher
+	webhooksService *service_webhooks.Service
+	chatService     *service_chat.Service
 }
 
 func New(
 	logger *zap.Logger,
 	repo db_statuses.Repository,
 	eventsPublisher *events.Publisher,
+	webhooksService *service_webhooks.Service,
+	chatService *service_chat.Service,
 ) *Service {
 	return &Service{
 		logger:          logger,
 		repo:            repo,
 		eventsPublisher: eventsPublisher,
+		webhooksService: webhooksService,
+		chatService:     chatService,
 	}
 }
 
@@ -43,6 +51,12 @@ func (s *Service) Set(ctx context.Context, status *statuses.Status) error {
 	if err := s.eventsPublisher.StatusUpdated(ctx, events.Codebase(status.CodebaseID), status); err != nil {
 		s.logger.Error("failed to send status updated event", zap.Error(err))
 	}
+	if err := s.webhooksService.StatusUpdated(ctx, status); err != nil {
+		s.logger.Error("failed to send status updated webhook", zap.Error(err))
+	}
+	if err := s.chatService.StatusUpdated(ctx, status); err != nil {
+		s.logger.Error("failed to send status failed chat notification", zap.Error(err))
+	}
 	return nil
 }
 
@@ -97,6 +97,49 @@ func (r *RootResolver) CreateSuggestion(ctx context.Context, args resolvers.Crea
 	return r.InternalSuggestion(ctx, suggestion)
 }
 
+func (r *RootResolver) CreateSuggestionForLines(ctx context.Context, args resolvers.CreateSuggestionForLinesArgs) (resolvers.SuggestionResolver, error) {
+	userID, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	ws, err := r.workspaceService.GetByID(ctx, string(args.Input.WorkspaceID))
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	if err := r.authService.CanRead(ctx, ws); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	allower, err := r.authService.GetAllower(ctx, ws)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	suggestion, err := r.suggestionsService.CreateForLines(
+		ctx,
+		userID,
+		ws,
+		allower,
+		args.Input.Path,
+		int(args.Input.StartLine),
+		int(args.Input.EndLine),
+		args.Input.Replacement,
+	)
+	switch {
+	case err == nil:
+	case errors.Is(err, service_suggestions.ErrInvalidPath):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "path", "file not found")
+	case errors.Is(err, service_suggestions.ErrInvalidLines):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "startLine", "invalid line range")
+	default:
+		return nil, gqlerrors.Error(err)
+	}
+
+	return r.InternalSuggestion(ctx, suggestion)
+}
+
 func (r *RootResolver) ApplySuggestionHunks(ctx context.Context, args resolvers.ApplySuggestionHunksArgs) (resolvers.SuggestionResolver, error) {
 	suggestion, err := r.suggestionsService.GetByID(ctx, suggestions.ID(args.Input.ID))
 	if err != nil {
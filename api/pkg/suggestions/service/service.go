@@ -5,6 +5,10 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
 	"time"
 
 	"getsturdy.com/api/pkg/analytics"
@@ -27,9 +31,15 @@ import (
 	"getsturdy.com/api/vcs/executor"
 
 	"github.com/google/uuid"
+	git "github.com/libgit2/git2go/v33"
 	"go.uber.org/zap"
 )
 
+var (
+	ErrInvalidPath  = errors.New("invalid path")
+	ErrInvalidLines = errors.New("invalid line range")
+)
+
 type Service struct {
 	logger *zap.Logger
 
@@ -159,6 +169,184 @@ func (s *Service) Create(ctx context.Context, userID users.ID, forWorkspace *wor
 	return suggestion, nil
 }
 
+// CreateForLines creates a suggestion that replaces lines from startLine to endLine (1-based, inclusive) of the file at
+// path with replacement. Lines are counted in the latest snapshot of the workspace. Only regular files that are allowed
+// by allower can be suggested on.
+//
+// Unlike Create, the suggesting workspace doesn't need a view, the suggested snapshot is created in a temporary view.
+func (s *Service) CreateForLines(
+	ctx context.Context,
+	userID users.ID,
+	forWorkspace *workspaces.Workspace,
+	allower *unidiff.Allower,
+	path string,
+	startLine, endLine int,
+	replacement string,
+) (*suggestions.Suggestion, error) {
+	if forWorkspace.LatestSnapshotID == nil {
+		return nil, fmt.Errorf("workspace has no snapshot")
+	}
+
+	path = filepath.Clean(path)
+	if filepath.IsAbs(path) || path == "." || strings.HasPrefix(path, "..") || path == ".git" || strings.HasPrefix(path, ".git/") {
+		return nil, ErrInvalidPath
+	}
+	if !allower.IsAllowed(path, false) {
+		// files that the user can't see don't exist
+		return nil, ErrInvalidPath
+	}
+
+	forSnapshot, err := s.snapshotter.GetByID(ctx, *forWorkspace.LatestSnapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshot: %w", err)
+	}
+
+	// validate the suggestion before creating anything
+	var replaced []byte
+	if err := s.executorProvider.New().GitRead(func(repo vcs.RepoGitReader) error {
+		// only the contents of regular files can be replaced, not the targets of symlinks or submodules
+		mode, err := repo.FileModeAtCommit(forSnapshot.CommitSHA, path)
+		switch {
+		case err == nil:
+		case errors.Is(err, vcs.ErrFileNotFound):
+			return ErrInvalidPath
+		default:
+			return fmt.Errorf("failed to read file: %w", err)
+		}
+		if mode != git.FilemodeBlob && mode != git.FilemodeBlobExecutable {
+			return ErrInvalidPath
+		}
+
+		content, err := repo.FileContentsAtCommit(forSnapshot.CommitSHA, path)
+		if err != nil {
+			return fmt.Errorf("failed to read file: %w", err)
+		}
+
+		replaced, err = replaceLines(content, startLine, endLine, replacement)
+		return err
+	}).ExecTrunk(forWorkspace.CodebaseID, "createSuggestionForLinesRead"); err != nil {
+		return nil, fmt.Errorf("failed to replace lines: %w", err)
+	}
+
+	name := ""
+	if forWorkspace.Name != nil {
+		name = fmt.Sprintf("Suggestions: %s", *forWorkspace.Name)
+	}
+
+	ws, err := s.workspaceService.CreateFromWorkspace(ctx, forWorkspace, userID, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy workspace: %w", err)
+	}
+
+	if err := s.executorProvider.New().
+		Write(vcs_view.CheckoutSnapshot(forSnapshot)).
+		Write(func(repo vcs.RepoWriter) error {
+			if err := writeRegularFile(repo.Path(), path, replaced); err != nil {
+				return err
+			}
+
+			if _, err := s.snapshotter.Snapshot(
+				ctx,
+				ws.CodebaseID,
+				ws.ID,
+				snapshots.ActionSuggestionLines,
+				service_snapshots.WithOnView(*repo.ViewID()),
+				service_snapshots.WithOnRepo(repo),
+				service_snapshots.WithMarkAsLatestInWorkspace(),
+			); err != nil {
+				return fmt.Errorf("failed to snapshot: %w", err)
+			}
+
+			return nil
+		}).ExecTemporaryView(forWorkspace.CodebaseID, "createSuggestionForLines"); err != nil {
+		return nil, fmt.Errorf("failed to create suggestion snapshot: %w", err)
+	}
+
+	suggestion := &suggestions.Suggestion{
+		ID:             suggestions.ID(uuid.NewString()),
+		CodebaseID:     ws.CodebaseID,
+		WorkspaceID:    ws.ID,
+		ForSnapshotID:  forSnapshot.ID,
+		ForWorkspaceID: forWorkspace.ID,
+		UserID:         userID,
+		CreatedAt:      time.Now(),
+	}
+	if err := s.suggestionRepo.Create(ctx, suggestion); err != nil {
+		return nil, fmt.Errorf("failed to create: %w", err)
+	}
+
+	// the suggestion is complete, notify the author right away
+	if err := s.RecordActivity(ctx, ws.ID); err != nil {
+		return nil, fmt.Errorf("failed to record activity: %w", err)
+	}
+
+	s.analyticsService.Capture(ctx, "suggestions-create-for-lines",
+		analytics.CodebaseID(suggestion.CodebaseID),
+		analytics.Property("suggestion_id", suggestion.ID),
+		analytics.Property("workspace_id", suggestion.ForWorkspaceID),
+	)
+
+	return suggestion, nil
+}
+
+// writeRegularFile replaces the contents of the regular file at path in root. It returns ErrInvalidPath if the file,
+// or any of its parents, is a symlink, so that it never writes outside of root.
+func writeRegularFile(root, path string, content []byte) error {
+	target := filepath.Join(root, path)
+	current := root
+	for _, name := range strings.Split(path, string(filepath.Separator)) {
+		current = filepath.Join(current, name)
+		fi, err := os.Lstat(current)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			return ErrInvalidPath
+		case err != nil:
+			return fmt.Errorf("failed to stat %s: %w", name, err)
+		case current == target && !fi.Mode().IsRegular(), current != target && !fi.IsDir():
+			return ErrInvalidPath
+		}
+	}
+
+	f, err := os.OpenFile(current, os.O_WRONLY|os.O_TRUNC|syscall.O_NOFOLLOW, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
+
+// replaceLines replaces lines from start to end (1-based, inclusive) of content with replacement.
+func replaceLines(content []byte, start, end int, replacement string) ([]byte, error) {
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if start < 1 || end < start || end > len(lines) {
+		return nil, ErrInvalidLines
+	}
+
+	// keep the line ending of the last replaced line
+	if replacement != "" && !strings.HasSuffix(replacement, "\n") && strings.HasSuffix(lines[end-1], "\n") {
+		replacement += "\n"
+	}
+
+	var b strings.Builder
+	for _, line := range lines[:start-1] {
+		b.WriteString(line)
+	}
+	b.WriteString(replacement)
+	for _, line := range lines[end:] {
+		b.WriteString(line)
+	}
+	return []byte(b.String()), nil
+}
+
 // GetByID returns a suggestion by id.
 func (s *Service) GetByID(ctx context.Context, id suggestions.ID) (*suggestions.Suggestion, error) {
 	suggestion, err := s.suggestionRepo.GetByID(ctx, id)
@@ -29,6 +29,7 @@ import (
 	"getsturdy.com/api/pkg/views"
 	db_view "getsturdy.com/api/pkg/views/db"
 	vcs_view "getsturdy.com/api/pkg/views/vcs"
+	db_webhooks "getsturdy.com/api/pkg/webhooks/db"
 	"getsturdy.com/api/pkg/workspaces"
 	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
 	service_workspace "getsturdy.com/api/pkg/workspaces/service"
@@ -265,6 +266,7 @@ func testModule(t *testing.T) di.Module {
 		c.ImportWithForce(db_snapshots.TestModule)
 		c.ImportWithForce(db_changes.TestModule)
 		c.ImportWithForce(db_statuses.TestModule)
+		c.ImportWithForce(db_webhooks.TestModule)
 		c.ImportWithForce(db_codebases.TestModule)
 		c.ImportWithForce(module_queue.TestModule(t))
 		c.ImportWithForce(configuration.TestModule)
@@ -1195,3 +1197,72 @@ func TestDiff(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateForLines(t *testing.T) {
+	test := newTest(t, []*operation{
+		{writeOriginal: map[string][]byte{"file": []byte("a\nb\nc\n")}},
+	})
+	test.run(t)
+
+	ctx := context.Background()
+	allowAll, err := unidiff.NewAllower("*")
+	assert.NoError(t, err)
+
+	suggestion, err := test.suggestionService.CreateForLines(ctx, test.suggestingUserID, test.originalWorkspace, allowAll, "file", 2, 2, "B")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, *test.originalWorkspace.LatestSnapshotID, suggestion.ForSnapshotID)
+
+	diffs, err := test.suggestionService.Diffs(ctx, suggestion)
+	if assert.NoError(t, err) && assert.Len(t, diffs, 1) && assert.Len(t, diffs[0].Hunks, 1) {
+		assert.Equal(t, "file", diffs[0].PreferredName)
+		assert.Contains(t, diffs[0].Hunks[0].Patch, "-b\n+B\n")
+
+		assert.NoError(t, test.suggestionService.ApplyHunks(ctx, suggestion, diffs[0].Hunks[0].ID))
+		content, err := os.ReadFile(path.Join(test.repoProvider.ViewPath(test.codebaseID, test.originalViewID), "file"))
+		assert.NoError(t, err)
+		assert.Equal(t, "a\nB\nc\n", string(content))
+	}
+
+	_, err = test.suggestionService.CreateForLines(ctx, test.suggestingUserID, test.originalWorkspace, allowAll, "file", 3, 4, "x")
+	assert.ErrorIs(t, err, service_suggestions.ErrInvalidLines)
+
+	_, err = test.suggestionService.CreateForLines(ctx, test.suggestingUserID, test.originalWorkspace, allowAll, "../file", 1, 1, "x")
+	assert.ErrorIs(t, err, service_suggestions.ErrInvalidPath)
+
+	_, err = test.suggestionService.CreateForLines(ctx, test.suggestingUserID, test.originalWorkspace, allowAll, "missing", 1, 1, "x")
+	assert.ErrorIs(t, err, service_suggestions.ErrInvalidPath)
+}
+
+func TestCreateForLines_rejected(t *testing.T) {
+	test := newTest(t, []*operation{
+		{writeOriginal: map[string][]byte{"file": []byte("a\nb\nc\n")}},
+	})
+	test.run(t)
+
+	// commit a symlink to the file, and a symlinked directory
+	viewPath := test.repoProvider.ViewPath(test.codebaseID, test.originalViewID)
+	assert.NoError(t, os.Symlink("file", path.Join(viewPath, "link")))
+	assert.NoError(t, os.Symlink(".", path.Join(viewPath, "dir")))
+	(&operation{}).snapshotOriginal(t, test)
+
+	ctx := context.Background()
+	allowAll, err := unidiff.NewAllower("*")
+	assert.NoError(t, err)
+
+	_, err = test.suggestionService.CreateForLines(ctx, test.suggestingUserID, test.originalWorkspace, allowAll, "link", 1, 1, "x")
+	assert.ErrorIs(t, err, service_suggestions.ErrInvalidPath, "symlinks must be rejected")
+
+	_, err = test.suggestionService.CreateForLines(ctx, test.suggestingUserID, test.originalWorkspace, allowAll, "dir/file", 1, 1, "x")
+	assert.ErrorIs(t, err, service_suggestions.ErrInvalidPath, "symlinked parents must be rejected")
+
+	hideFile, err := unidiff.NewAllower("*", "!file")
+	assert.NoError(t, err)
+	_, err = test.suggestionService.CreateForLines(ctx, test.suggestingUserID, test.originalWorkspace, hideFile, "file", 1, 1, "x")
+	assert.ErrorIs(t, err, service_suggestions.ErrInvalidPath, "files that are not allowed must be rejected")
+
+	content, err := os.ReadFile(path.Join(viewPath, "file"))
+	assert.NoError(t, err)
+	assert.Equal(t, "a\nb\nc\n", string(content))
+}
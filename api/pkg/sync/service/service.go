@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -65,6 +66,59 @@ const unsavedCommitMessage = "Unsaved workspace changes"
 // The current work in progress will be added to a commit, that is rebased on top of the trunk.
 // After the syncing is done, the commit is "git reset --mixed HEAD^1"-ed, to restore it to the WIP.
 func (svc *Service) OnTrunk(ctx context.Context, ws *workspaces.Workspace) (*sync.RebaseStatusResponse, error) {
+	return svc.onBranch(ctx, ws, "sturdytrunk")
+}
+
+var ErrNotStacked = errors.New("workspace is not stacked on another workspace")
+
+// OnBaseWorkspace starts a sync of a stacked workspace on top of the latest snapshot of its base workspace.
+// Conflicts are handled in the same way as in OnTrunk.
+func (svc *Service) OnBaseWorkspace(ctx context.Context, ws *workspaces.Workspace) (*sync.RebaseStatusResponse, error) {
+	if ws.BaseWorkspaceID == nil {
+		return nil, ErrNotStacked
+	}
+
+	base, err := svc.workspaceReader.Get(*ws.BaseWorkspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get base workspace: %w", err)
+	}
+
+	if base.ViewID != nil {
+		// make sure that the latest changes of the base workspace are included
+		if _, err := svc.snap.Snapshot(ctx, base.CodebaseID, base.ID, snapshots.ActionViewSync,
+			service_snapshots.WithOnView(*base.ViewID),
+			service_snapshots.WithMarkAsLatestInWorkspace(),
+		); err != nil {
+			return nil, fmt.Errorf("failed to snapshot base workspace: %w", err)
+		}
+		if base, err = svc.workspaceReader.Get(base.ID); err != nil {
+			return nil, fmt.Errorf("failed to get base workspace: %w", err)
+		}
+	}
+
+	if base.LatestSnapshotID == nil {
+		return nil, fmt.Errorf("base workspace has no snapshot")
+	}
+
+	baseSnapshot, err := svc.snap.GetByID(ctx, *base.LatestSnapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get base workspace snapshot: %w", err)
+	}
+
+	return svc.onBranch(ctx, ws, baseSnapshot.BranchName())
+}
+
+// Sync syncs the workspace on top of what it's based on: the base workspace if the workspace is stacked, otherwise
+// the trunk.
+func (svc *Service) Sync(ctx context.Context, ws *workspaces.Workspace) (*sync.RebaseStatusResponse, error) {
+	if ws.BaseWorkspaceID != nil {
+		return svc.OnBaseWorkspace(ctx, ws)
+	}
+	return svc.OnTrunk(ctx, ws)
+}
+
+// onBranch rebases the work in progress changes of the workspace on top of the branch ontoBranchName.
+func (svc *Service) onBranch(ctx context.Context, ws *workspaces.Workspace, ontoBranchName string) (*sync.RebaseStatusResponse, error) {
 	syncID := uuid.NewString()
 
 	branchName := fmt.Sprintf("sync-%s", syncID)
@@ -85,11 +139,11 @@ func (svc *Service) OnTrunk(ctx context.Context, ws *workspaces.Workspace) (*syn
 			return nil
 		}
 
-		if err := repo.FetchBranch("sturdytrunk"); err != nil {
+		if err := repo.FetchBranch(ontoBranchName); err != nil {
 			return err
 		}
 
-		trunkHeadCommit, err := repo.RemoteBranchCommit("origin", "sturdytrunk")
+		ontoHeadCommit, err := repo.RemoteBranchCommit("origin", ontoBranchName)
 		if err != nil {
 			return err
 		}
@@ -113,7 +167,7 @@ func (svc *Service) OnTrunk(ctx context.Context, ws *workspaces.Workspace) (*syn
 
 		// no changes, early return
 		if treeID == nil {
-			if err := repo.MoveBranchToCommit(branchName, trunkHeadCommit.Id().String()); err != nil {
+			if err := repo.MoveBranchToCommit(branchName, ontoHeadCommit.Id().String()); err != nil {
 				return fmt.Errorf("failed to move branch to commit in early return: %w", err)
 			}
 			if err := repo.CheckoutBranchWithForce(branchName); err != nil {
@@ -137,14 +191,14 @@ func (svc *Service) OnTrunk(ctx context.Context, ws *workspaces.Workspace) (*syn
 			return fmt.Errorf("failed to create commit with unsave changes: %w", err)
 		}
 
-		if err := repo.CreateAndCheckoutBranchAtCommit(trunkHeadCommit.Id().String(), branchName); err != nil {
+		if err := repo.CreateAndCheckoutBranchAtCommit(ontoHeadCommit.Id().String(), branchName); err != nil {
 			return fmt.Errorf("create and checkout branch failed: %w", err)
 		}
 
 		// Apply our unsaved changes
 		rb, rebasedCommits, err := repo.InitRebaseRaw(
 			unsavedCommitID,
-			trunkHeadCommit.Id().String(),
+			ontoHeadCommit.Id().String(),
 		)
 		if err != nil {
 			return err
@@ -0,0 +1,97 @@
+package unidiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Interdiff returns the diff of two sets of diffs of the same workspace, for example the diffs that were reviewed and
+// the diffs of the workspace now.
+//
+// Hunks that are only in after are returned as they are, and hunks that are only in before (changes that have been
+// undone since) are returned inverted. Hunks are compared by their contents, so a hunk that has only moved because of
+// changes elsewhere in the same file is not a part of the interdiff.
+func Interdiff(before, after []FileDiff) ([]FileDiff, error) {
+	beforeByName := make(map[string]FileDiff, len(before))
+	for _, fd := range before {
+		if fd.IsHidden {
+			continue
+		}
+		beforeByName[fd.PreferredName] = fd
+	}
+
+	var res []FileDiff
+	seen := make(map[string]bool, len(after))
+	for _, fd := range after {
+		if fd.IsHidden {
+			continue
+		}
+		seen[fd.PreferredName] = true
+
+		added := subtractHunks(fd.Hunks, beforeByName[fd.PreferredName].Hunks)
+		removed, err := invertHunks(subtractHunks(beforeByName[fd.PreferredName].Hunks, fd.Hunks))
+		if err != nil {
+			return nil, fmt.Errorf("failed to invert hunks of %s: %w", fd.PreferredName, err)
+		}
+		if len(added) == 0 && len(removed) == 0 {
+			continue
+		}
+
+		fd.Hunks = append(added, removed...)
+		fd.IsLarge, fd.LargeFileInfo = largeData(fd.Hunks)
+		res = append(res, fd)
+	}
+
+	// files that are no longer changed at all
+	for _, fd := range before {
+		if fd.IsHidden || seen[fd.PreferredName] {
+			continue
+		}
+
+		inverted, err := invertHunks(fd.Hunks)
+		if err != nil {
+			return nil, fmt.Errorf("failed to invert hunks of %s: %w", fd.PreferredName, err)
+		}
+
+		fd.OrigName, fd.NewName = fd.NewName, fd.OrigName
+		fd.IsNew, fd.IsDeleted = fd.IsDeleted, fd.IsNew
+		fd.Hunks = inverted
+		fd.IsLarge, fd.LargeFileInfo = largeData(fd.Hunks)
+		res = append(res, fd)
+	}
+
+	return res, nil
+}
+
+// subtractHunks returns the hunks in a that have no hunk with the same contents in b.
+func subtractHunks(a, b []Hunk) []Hunk {
+	contents := make(map[string]bool, len(b))
+	for _, h := range b {
+		contents[hunkContents(h.Patch)] = true
+	}
+
+	var res []Hunk
+	for _, h := range a {
+		if !contents[hunkContents(h.Patch)] {
+			res = append(res, h)
+		}
+	}
+	return res
+}
+
+// hunkContents returns the changed lines of a patch, without the file header and the line numbers in the hunk headers.
+func hunkContents(patch string) string {
+	var b strings.Builder
+	inHunk := false
+	for _, line := range strings.Split(patch, "\n") {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+			b.WriteString("@@\n")
+		case inHunk:
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
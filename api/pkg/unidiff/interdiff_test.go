@@ -0,0 +1,54 @@
+package unidiff
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestInterdiff(t *testing.T) {
+	decorate := func(patches ...string) []FileDiff {
+		diffs, err := NewUnidiff(NewStringsPatchReader(patches), zap.NewNop()).WithExpandedHunks().Decorate()
+		require.NoError(t, err)
+		return diffs
+	}
+
+	newFile, err := ioutil.ReadFile("testdata/sample_new.diff")
+	require.NoError(t, err)
+	newFileInverted, err := ioutil.ReadFile("testdata/sample_new_inverted.diff")
+	require.NoError(t, err)
+
+	reviewed := decorate(
+		"diff --git a/one.txt b/one.txt\nindex 4fce4a5..fef85d8 100644\n--- a/one.txt\n+++ b/one.txt\n@@ -2,3 +2,2 @@ a\n b\n-c\n d\n@@ -16,3 +15,2 @@ o\n p\n-q\n r\n",
+		string(newFile),
+	)
+	// c is no longer removed, x is added, and q is still removed, but on another line
+	current := decorate(
+		"diff --git a/one.txt b/one.txt\nindex 4fce4a5..1a2b3c4 100644\n--- a/one.txt\n+++ b/one.txt\n@@ -1,3 +1,4 @@\n+x\n a\n b\n c\n@@ -16,3 +17,2 @@ o\n p\n-q\n r\n",
+	)
+
+	interdiff, err := Interdiff(reviewed, current)
+	require.NoError(t, err)
+	require.Len(t, interdiff, 2)
+
+	assert.Equal(t, "one.txt", interdiff[0].PreferredName)
+	if assert.Len(t, interdiff[0].Hunks, 2) {
+		assert.Equal(t, current[0].Hunks[0], interdiff[0].Hunks[0])
+		assert.Contains(t, interdiff[0].Hunks[1].Patch, "\n+c\n")
+	}
+
+	assert.Equal(t, "README_XOXO.md", interdiff[1].PreferredName)
+	assert.True(t, interdiff[1].IsDeleted)
+	assert.False(t, interdiff[1].IsNew)
+	if assert.Len(t, interdiff[1].Hunks, 1) {
+		assert.Equal(t, string(newFileInverted), interdiff[1].Hunks[0].Patch)
+	}
+
+	// nothing has changed since the review
+	interdiff, err = Interdiff(current, current)
+	require.NoError(t, err)
+	assert.Empty(t, interdiff)
+}
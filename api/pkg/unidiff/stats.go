@@ -0,0 +1,45 @@
+package unidiff
+
+import "strings"
+
+// Stats is a summary of the size of a set of diffs.
+type Stats struct {
+	FilesChanged int32
+	Additions    int32
+	Deletions    int32
+}
+
+// NewStats counts the changed files, and the added and deleted lines in diffs.
+// Files that are hidden by an Allower are not counted.
+func NewStats(diffs []FileDiff) Stats {
+	var stats Stats
+	for _, diff := range diffs {
+		if diff.IsHidden {
+			continue
+		}
+		stats.FilesChanged++
+		for _, hunk := range diff.Hunks {
+			additions, deletions := countLines(hunk.Patch)
+			stats.Additions += additions
+			stats.Deletions += deletions
+		}
+	}
+	return stats
+}
+
+func countLines(patch string) (additions, deletions int32) {
+	// the file header ("--- a/file", "+++ b/file") comes before the first hunk header
+	inHunk := false
+	for _, line := range strings.Split(patch, "\n") {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+		case !inHunk:
+		case strings.HasPrefix(line, "+"):
+			additions++
+		case strings.HasPrefix(line, "-"):
+			deletions++
+		}
+	}
+	return additions, deletions
+}
@@ -0,0 +1,33 @@
+package unidiff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStats(t *testing.T) {
+	diffs := []FileDiff{
+		{
+			OrigName: "one.txt",
+			NewName:  "one.txt",
+			Hunks: []Hunk{
+				{Patch: "diff --git \"a/one.txt\" \"b/one.txt\"\nindex 4fce4a5..fef85d8 100644\n--- \"a/one.txt\"\n+++ \"b/one.txt\"\n@@ -2,7 +2,6 @@ a\n b\n c\n d\n-e\n f\n g\n h\n"},
+				{Patch: "diff --git \"a/one.txt\" \"b/one.txt\"\nindex 4fce4a5..fef85d8 100644\n--- \"a/one.txt\"\n+++ \"b/one.txt\"\n@@ -16,7 +15,7 @@ o\n p\n q\n r\n-s\n+--- s\n t\n y\n v\n"},
+			},
+		},
+		{
+			NewName: "new.txt",
+			IsNew:   true,
+			Hunks: []Hunk{
+				{Patch: "diff --git /dev/null \"b/new.txt\"\nnew file mode 100644\nindex 0000000..a1f8944\n--- /dev/null\n+++ \"b/new.txt\"\n@@ -0,0 +1,2 @@\n+foo\n+bar\n\\ No newline at end of file\n"},
+			},
+		},
+		{
+			IsHidden: true,
+		},
+	}
+
+	assert.Equal(t, Stats{FilesChanged: 2, Additions: 3, Deletions: 2}, NewStats(diffs))
+	assert.Equal(t, Stats{}, NewStats(nil))
+}
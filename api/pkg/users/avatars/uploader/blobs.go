@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"mime"
 	"path"
 
 	"getsturdy.com/api/pkg/blobs"
@@ -38,7 +39,7 @@ func NewBlobs(cfg *configuration.Configuration, blobsService *service_blobs.Serv
 }
 
 func (p *Blobs) Upload(ctx context.Context, key string, file io.Reader) (*avatars.Avatar, error) {
-	if err := p.blobsService.Store(ctx, blobs.ID(key), file); err != nil {
+	if err := p.blobsService.Store(ctx, blobs.ID(key), mime.TypeByExtension(path.Ext(key)), file); err != nil {
 		return nil, fmt.Errorf("failed to store blob: %w", err)
 	}
 	return &avatars.Avatar{
@@ -7,13 +7,14 @@ import (
 	"getsturdy.com/api/pkg/auth"
 	service_jwt "getsturdy.com/api/pkg/jwt/service"
 	"getsturdy.com/api/pkg/onetime/service"
+	service_sso "getsturdy.com/api/pkg/sso/service"
 	service_user "getsturdy.com/api/pkg/users/enterprise/cloud/service"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
-func VerifyMagicLink(logger *zap.Logger, userService *service_user.Service, jwtService *service_jwt.Service) gin.HandlerFunc {
+func VerifyMagicLink(logger *zap.Logger, userService *service_user.Service, jwtService *service_jwt.Service, ssoService *service_sso.Service) gin.HandlerFunc {
 	type request struct {
 		Code  string `json:"code" binding:"required"`
 		Email string `json:"email" binding:"required"`
@@ -31,6 +32,15 @@ func VerifyMagicLink(logger *zap.Logger, userService *service_user.Service, jwtS
 			return
 		}
 
+		if err := ssoService.CheckPasswordLogin(c.Request.Context(), user); errors.Is(err, service_sso.ErrSSORequired) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "organization requires single sign-on"})
+			return
+		} else if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "something went wrong"})
+			logger.Error("failed to check if single sign-on is required", zap.Error(err))
+			return
+		}
+
 		if err := userService.VerifyMagicLink(c.Request.Context(), user, req.Code); errors.Is(err, service.ErrExpired) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "code expired"})
 			return
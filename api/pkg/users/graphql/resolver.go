@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 
+	"getsturdy.com/api/pkg/accesstokens"
 	service_analytics "getsturdy.com/api/pkg/analytics/service"
 	"getsturdy.com/api/pkg/auth"
 	"getsturdy.com/api/pkg/codebases"
@@ -74,6 +75,11 @@ func (r *userRootResolver) UpdateUser(ctx context.Context, args resolvers.Update
 		return nil, gqlerrors.Error(err)
 	}
 
+	// Access tokens can only change the user if they have the admin scope
+	if err := auth.RequireScope(ctx, accesstokens.ScopeAdmin); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
 	user, err := r.userRepo.Get(userID)
 	if err != nil {
 		return nil, gqlerrors.Error(err)
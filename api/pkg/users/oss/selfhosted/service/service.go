@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 
+	"getsturdy.com/api/pkg/organization"
 	service_organization "getsturdy.com/api/pkg/organization/service"
 	"getsturdy.com/api/pkg/users"
 	"getsturdy.com/api/pkg/users/service"
@@ -38,7 +39,7 @@ func (s *Service) CreateWithPassword(ctx context.Context, name, password, email
 	switch {
 	case err == nil:
 		// add this user
-		if _, err := s.organizationService.AddMember(ctx, first.ID, usr.ID, usr.ID); err != nil {
+		if _, err := s.organizationService.AddMember(ctx, first.ID, usr.ID, usr.ID, organization.RoleMember); err != nil {
 			return nil, fmt.Errorf("failed to add member to existing org: %w", err)
 		}
 	case errors.Is(err, sql.ErrNoRows):
@@ -36,7 +36,8 @@ func ClientToken(db db.Repository, jwtService *service_jwt.Service) func(*gin.Co
 			return
 		}
 
-		token, err := jwtService.IssueToken(c.Request.Context(), user.ID.String(), oneMonth, jwt.TokenTypeAuth)
+		// the client is authenticated in the same way as the session that it was created from
+		token, err := jwtService.IssueToken(c.Request.Context(), user.ID.String(), oneMonth, jwt.TokenTypeAuth, service_jwt.WithSSO(auth.IsSSO(c.Request.Context())))
 		if err != nil {
 			log.Println(err)
 			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
@@ -85,7 +86,7 @@ func RenewToken(logger *zap.Logger, db db.Repository, jwtService *service_jwt.Se
 
 		// If expires within 25 days, renew it! (original expire duration is 30 days)
 		if token.ExpiresAt.Before(time.Now().Add(time.Hour * 24 * 25)) {
-			newToken, err := jwtService.IssueToken(c.Request.Context(), user.ID.String(), oneMonth, jwt.TokenTypeAuth)
+			newToken, err := jwtService.IssueToken(c.Request.Context(), user.ID.String(), oneMonth, jwt.TokenTypeAuth, service_jwt.WithSSO(token.SSO))
 			if err != nil {
 				logger.Error("failed to renew token for user", zap.Error(err))
 				c.AbortWithStatus(http.StatusBadRequest)
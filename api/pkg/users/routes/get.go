@@ -27,7 +27,7 @@ func GetSelf(userService service_users.Service, jwtService *service_jwt.Service)
 		}
 		// Refresh the users auth cookie
 		// For requests from a browser, this takes care of all of the auth renewal we need. :-)
-		auth.SetAuthCookieForUser(c, u.ID, jwtService)
+		auth.SetAuthCookieForUser(c, u.ID, jwtService, service_jwt.WithSSO(auth.IsSSO(c.Request.Context())))
 		c.JSON(http.StatusOK, u)
 	}
 }
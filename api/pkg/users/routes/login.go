@@ -1,6 +1,7 @@
 package routes
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
@@ -8,6 +9,7 @@ import (
 	service_analytics "getsturdy.com/api/pkg/analytics/service"
 	"getsturdy.com/api/pkg/auth"
 	service_jwt "getsturdy.com/api/pkg/jwt/service"
+	service_sso "getsturdy.com/api/pkg/sso/service"
 	service_users "getsturdy.com/api/pkg/users/service"
 
 	"github.com/gin-gonic/gin"
@@ -15,7 +17,7 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-func Login(logger *zap.Logger, userService service_users.Service, analyticsService *service_analytics.Service, jwtService *service_jwt.Service) func(c *gin.Context) {
+func Login(logger *zap.Logger, userService service_users.Service, analyticsService *service_analytics.Service, jwtService *service_jwt.Service, ssoService *service_sso.Service) func(c *gin.Context) {
 	type request struct {
 		Email    string `json:"email" binding:"required"`
 		Password string `json:"password" binding:"required"`
@@ -48,6 +50,15 @@ func Login(logger *zap.Logger, userService service_users.Service, analyticsServi
 			return
 		}
 
+		if err := ssoService.CheckPasswordLogin(c.Request.Context(), getUser); errors.Is(err, service_sso.ErrSSORequired) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Your organization requires you to log in with single sign-on"})
+			return
+		} else if err != nil {
+			logger.Error("failed to check if single sign-on is required", zap.Error(err))
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
 		if err := auth.SetAuthCookieForUser(c, getUser.ID, jwtService); err != nil {
 			logger.Error("failed to set auth cookie", zap.Error(err))
 			c.AbortWithStatus(http.StatusInternalServerError)
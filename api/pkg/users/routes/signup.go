@@ -9,6 +9,7 @@ import (
 	service_analytics "getsturdy.com/api/pkg/analytics/service"
 	"getsturdy.com/api/pkg/auth"
 	service_jwt "getsturdy.com/api/pkg/jwt/service"
+	service_sso "getsturdy.com/api/pkg/sso/service"
 	service_user "getsturdy.com/api/pkg/users/service"
 
 	"github.com/gin-gonic/gin"
@@ -20,6 +21,7 @@ func Signup(
 	userService service_user.Service,
 	jwtService *service_jwt.Service,
 	analyticsService *service_analytics.Service,
+	ssoService *service_sso.Service,
 ) func(c *gin.Context) {
 	type request struct {
 		Name     string `json:"name" binding:"required"`
@@ -52,6 +54,16 @@ func Signup(
 			return
 		}
 
+		// the user might have joined an organization that requires single sign-on
+		if err := ssoService.CheckPasswordLogin(c.Request.Context(), newUser); errors.Is(err, service_sso.ErrSSORequired) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Your organization requires you to log in with single sign-on"})
+			return
+		} else if err != nil {
+			logger.Error("failed to check if single sign-on is required", zap.Error(err))
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
 		if err := auth.SetAuthCookieForUser(c, newUser.ID, jwtService); err != nil {
 			logger.Error("failed to set auth cookie", zap.Error(err))
 			c.AbortWithStatus(http.StatusInternalServerError)
@@ -0,0 +1,109 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	service_auth "getsturdy.com/api/pkg/auth/service"
+	"getsturdy.com/api/pkg/snapshots"
+	db_view "getsturdy.com/api/pkg/views/db"
+	"getsturdy.com/api/pkg/views/meta"
+	service_view "getsturdy.com/api/pkg/views/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// maxWriteFilesRequestSize is the max size of a WriteFilesRequest, clients are expected to split larger changes into
+// multiple requests
+const maxWriteFilesRequestSize = 64 << 20
+
+type WriteFilesRequest struct {
+	Files []WriteFileRequest `json:"files" binding:"required"`
+}
+
+type WriteFileRequest struct {
+	Path       string `json:"path" binding:"required"`
+	Contents   []byte `json:"contents"` // base64 encoded
+	Executable bool   `json:"executable"`
+	Deleted    bool   `json:"deleted"`
+}
+
+// WriteFiles applies file changes from clients that are syncing without mutagen. Access to the view is checked
+// elsewhere, the files are checked against the ACLs of the codebase here.
+func WriteFiles(
+	logger *zap.Logger,
+	authService *service_auth.Service,
+	viewRepo db_view.Repository,
+	viewService *service_view.Service,
+	viewUpdatedFunc meta.ViewUpdatedFunc,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxWriteFilesRequestSize)
+
+		var req WriteFilesRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logger.Warn("failed to parse request", zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "failed to parse or validate input"})
+			return
+		}
+
+		view, err := viewRepo.Get(c.Param("viewID"))
+		if err != nil {
+			logger.Error("failed to get view", zap.Error(err))
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		if len(req.Files) == 0 {
+			c.JSON(http.StatusOK, gin.H{"status": "ok"})
+			return
+		}
+
+		changes := make([]service_view.FileChange, 0, len(req.Files))
+		for _, f := range req.Files {
+			changes = append(changes, service_view.FileChange{
+				Path:       f.Path,
+				Contents:   f.Contents,
+				Executable: f.Executable,
+				Deleted:    f.Deleted,
+			})
+		}
+
+		allower, err := authService.GetAllower(c.Request.Context(), view)
+		if err != nil {
+			logger.Error("failed to get allower", zap.Error(err))
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		if err := viewService.WriteFiles(c.Request.Context(), view, allower, changes); errors.Is(err, service_view.ErrInvalidPath) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		} else if errors.Is(err, service_view.ErrNotAllowed) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		} else if err != nil {
+			logger.Error("failed to write files", zap.Error(err))
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		// Set LastUsedAt
+		t := time.Now()
+		view.LastUsedAt = &t
+		if err := viewRepo.Update(view); err != nil {
+			logger.Error("failed to update view", zap.Error(err))
+			// Don't fail
+		}
+
+		if err := viewUpdatedFunc(c.Request.Context(), view, snapshots.ActionViewSync); err != nil {
+			logger.Error("failed to mark as updated", zap.Error(err))
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	}
+}
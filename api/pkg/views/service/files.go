@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"getsturdy.com/api/pkg/unidiff"
+	"getsturdy.com/api/pkg/views"
+	"getsturdy.com/api/vcs"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrInvalidPath = errors.New("invalid path")
+	ErrNotAllowed  = errors.New("not allowed")
+)
+
+// FileChange is a change to a single file in a view, made by a client that syncs without mutagen.
+type FileChange struct {
+	// Path is the slash separated path of the file, relative to the root of the view
+	Path     string
+	Contents []byte
+	// Executable is true if the file should be executable
+	Executable bool
+	// Deleted is true if the file has been removed, Contents and Executable are ignored if it's set
+	Deleted bool
+}
+
+// WriteFiles applies changes to the files in the view. ErrNotAllowed is returned if any of the files is not allowed
+// by the allower, and then no files are written.
+//
+// Files can be written while the view is rebasing, as conflicts are resolved by editing the conflicting files.
+func (s *Service) WriteFiles(ctx context.Context, view *views.View, allower *unidiff.Allower, changes []FileChange) error {
+	for _, change := range changes {
+		if err := validatePath(change.Path); err != nil {
+			return err
+		}
+		if !allower.IsAllowed(change.Path, false) {
+			return fmt.Errorf("%w: %q", ErrNotAllowed, change.Path)
+		}
+	}
+
+	if err := s.executorProvider.New().
+		AllowRebasingState().
+		Write(func(repo vcs.RepoWriter) error {
+			for _, change := range changes {
+				if err := writeFile(repo.Path(), change); err != nil {
+					return err
+				}
+			}
+			return nil
+		}).ExecView(view.CodebaseID, view.ID, "writeFiles"); err != nil {
+		return fmt.Errorf("failed to write files: %w", err)
+	}
+
+	return nil
+}
+
+// writeFile applies the change to the file in root. It returns ErrInvalidPath if any of the parents of the file is a
+// symlink, or if the file itself is a symlink that would be written through, so that it never writes outside of root.
+func writeFile(root string, change FileChange) error {
+	fullPath := filepath.Join(root, filepath.FromSlash(change.Path))
+
+	if err := mkdirParents(root, change.Path, !change.Deleted); err != nil {
+		return err
+	}
+
+	if change.Deleted {
+		// Remove does not follow symlinks, a symlink is removed itself
+		if err := os.Remove(fullPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove %s: %w", change.Path, err)
+		}
+		removeEmptyParents(root, filepath.Dir(fullPath))
+		return nil
+	}
+
+	switch fi, err := os.Lstat(fullPath); {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return fmt.Errorf("failed to stat %s: %w", change.Path, err)
+	case !fi.Mode().IsRegular():
+		return fmt.Errorf("%w: %q is not a regular file", ErrInvalidPath, change.Path)
+	}
+
+	var mode os.FileMode = 0o644
+	if change.Executable {
+		mode = 0o755
+	}
+
+	// O_NOFOLLOW makes sure that a symlink that is created after the file was checked is not written through
+	f, err := os.OpenFile(fullPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC|syscall.O_NOFOLLOW, mode)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", change.Path, err)
+	}
+	if _, err := f.Write(change.Contents); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", change.Path, err)
+	}
+	// OpenFile does not change the mode of files that already exist
+	if err := f.Chmod(mode); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to set mode of %s: %w", change.Path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", change.Path, err)
+	}
+	return nil
+}
+
+// mkdirParents checks that all parents of the slash separated path p in root are directories, and not symlinks. If
+// create is true, parents that don't exist are created, otherwise they are left missing.
+func mkdirParents(root, p string, create bool) error {
+	current := root
+	names := strings.Split(p, "/")
+	for _, name := range names[:len(names)-1] {
+		current = filepath.Join(current, name)
+		fi, err := os.Lstat(current)
+		switch {
+		case errors.Is(err, os.ErrNotExist) && !create:
+			return nil
+		case errors.Is(err, os.ErrNotExist):
+			if err := os.Mkdir(current, 0o755); err != nil {
+				return fmt.Errorf("failed to create directory for %s: %w", p, err)
+			}
+		case err != nil:
+			return fmt.Errorf("failed to stat %s: %w", p, err)
+		case !fi.IsDir():
+			return fmt.Errorf("%w: %q has a parent that is not a directory", ErrInvalidPath, p)
+		}
+	}
+	return nil
+}
+
+// removeEmptyParents removes dir and its parents, up until root, as long as they are empty
+func removeEmptyParents(root, dir string) {
+	for dir != root && strings.HasPrefix(dir, root) {
+		// Remove fails if the directory is not empty
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// validatePath makes sure that p is a path inside of the view, that is not a part of the git repository itself
+func validatePath(p string) error {
+	switch {
+	case p == "", p == ".",
+		path.IsAbs(p),
+		path.Clean(p) != p,
+		p == "..", strings.HasPrefix(p, "../"),
+		strings.Contains(p, "\\"):
+		return fmt.Errorf("%w: %q", ErrInvalidPath, p)
+	}
+	if first := strings.SplitN(p, "/", 2)[0]; first == ".git" {
+		return fmt.Errorf("%w: %q", ErrInvalidPath, p)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/unidiff"
+	"getsturdy.com/api/pkg/views"
+	"getsturdy.com/api/vcs"
+	"getsturdy.com/api/vcs/executor"
+	"getsturdy.com/api/vcs/testutil"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestValidatePath(t *testing.T) {
+	valid := []string{"README.md", "src/main.go", ".gitignore", "docs/.git-keep", "a/.git"}
+	for _, p := range valid {
+		assert.NoError(t, validatePath(p), p)
+	}
+
+	invalid := []string{"", ".", "..", "../outside", "/etc/passwd", "src/../../outside", "src//main.go", "./main.go", ".git", ".git/config", `src\main.go`}
+	for _, p := range invalid {
+		assert.ErrorIs(t, validatePath(p), ErrInvalidPath, p)
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	root := t.TempDir()
+
+	require.NoError(t, writeFile(root, FileChange{Path: "a/b/script.sh", Contents: []byte("#!/bin/sh"), Executable: true}))
+	contents, err := os.ReadFile(filepath.Join(root, "a", "b", "script.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, "#!/bin/sh", string(contents))
+	info, err := os.Stat(filepath.Join(root, "a", "b", "script.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o755), info.Mode().Perm())
+
+	// the mode of existing files is updated
+	require.NoError(t, writeFile(root, FileChange{Path: "a/b/script.sh", Contents: []byte("echo")}))
+	info, err = os.Stat(filepath.Join(root, "a", "b", "script.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o644), info.Mode().Perm())
+
+	require.NoError(t, writeFile(root, FileChange{Path: "a/other.txt", Contents: []byte("other")}))
+
+	// empty directories are removed together with the last file in them
+	require.NoError(t, writeFile(root, FileChange{Path: "a/b/script.sh", Deleted: true}))
+	_, err = os.Stat(filepath.Join(root, "a", "b"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	_, err = os.Stat(filepath.Join(root, "a", "other.txt"))
+	assert.NoError(t, err)
+
+	// deleting files that don't exist is a no-op
+	assert.NoError(t, writeFile(root, FileChange{Path: "missing.txt", Deleted: true}))
+	_, err = os.Stat(root)
+	assert.NoError(t, err)
+}
+
+func TestWriteFile_symlinks(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(outside, "file"), []byte("outside"), 0o644))
+	require.NoError(t, os.Symlink(outside, filepath.Join(root, "dir")))
+	require.NoError(t, os.Symlink(filepath.Join(outside, "file"), filepath.Join(root, "link")))
+
+	for _, change := range []FileChange{
+		{Path: "link", Contents: []byte("inside")},
+		{Path: "dir/file", Contents: []byte("inside")},
+		{Path: "dir/new", Contents: []byte("inside")},
+		{Path: "dir/file", Deleted: true},
+	} {
+		assert.ErrorIs(t, writeFile(root, change), ErrInvalidPath, change.Path)
+	}
+
+	// nothing outside of root is changed
+	entries, err := os.ReadDir(outside)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+	contents, err := os.ReadFile(filepath.Join(outside, "file"))
+	require.NoError(t, err)
+	assert.Equal(t, "outside", string(contents))
+
+	// symlinks themselves can be removed
+	require.NoError(t, writeFile(root, FileChange{Path: "link", Deleted: true}))
+	_, err = os.Lstat(filepath.Join(root, "link"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	_, err = os.Stat(filepath.Join(outside, "file"))
+	assert.NoError(t, err)
+}
+
+func TestWriteFiles_notAllowed(t *testing.T) {
+	allower, err := unidiff.NewAllower("allowed.txt")
+	require.NoError(t, err)
+
+	// nothing is written, the service has no executor to write with
+	svc := &Service{}
+	view := &views.View{ID: uuid.NewString(), CodebaseID: codebases.ID(uuid.NewString())}
+	for _, change := range []FileChange{
+		{Path: "secret.txt", Contents: []byte("pwned")},
+		{Path: "secret.txt", Deleted: true},
+	} {
+		err = svc.WriteFiles(context.Background(), view, allower, []FileChange{{Path: "allowed.txt"}, change})
+		assert.ErrorIs(t, err, ErrNotAllowed)
+	}
+}
+
+func TestWriteFiles_committed_symlinks(t *testing.T) {
+	repoProvider := testutil.TestingRepoProvider(t)
+	svc := &Service{executorProvider: executor.NewProvider(zap.NewNop(), repoProvider)}
+
+	view := &views.View{ID: uuid.NewString(), CodebaseID: codebases.ID(uuid.NewString())}
+	trunkPath := repoProvider.TrunkPath(view.CodebaseID)
+	_, err := vcs.CreateBareRepoWithRootCommit(trunkPath)
+	require.NoError(t, err)
+	viewPath := repoProvider.ViewPath(view.CodebaseID, view.ID)
+	_, err = vcs.CloneRepo(trunkPath, viewPath)
+	require.NoError(t, err)
+
+	// the view has symlinks that point outside of it committed
+	outside := t.TempDir()
+	require.NoError(t, os.Symlink(outside, filepath.Join(viewPath, "dir")))
+	require.NoError(t, os.Symlink(filepath.Join(outside, "file"), filepath.Join(viewPath, "link")))
+	for _, args := range [][]string{
+		{"add", "dir", "link"},
+		{"-c", "user.name=test", "-c", "user.email=test@getsturdy.com", "commit", "--quiet", "-m", "symlinks"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = viewPath
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+
+	allower, err := unidiff.NewAllower("*")
+	require.NoError(t, err)
+	for _, p := range []string{"link", "dir/file"} {
+		err := svc.WriteFiles(context.Background(), view, allower, []FileChange{{Path: p, Contents: []byte("pwned")}})
+		assert.ErrorIs(t, err, ErrInvalidPath, p)
+	}
+
+	entries, err := os.ReadDir(outside)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
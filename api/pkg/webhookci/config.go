@@ -0,0 +1,25 @@
+package webhookci
+
+import (
+	"time"
+
+	"getsturdy.com/api/pkg/codebases"
+)
+
+// Config is the configuration of a generic build provider, that is triggered by sending a signed http request
+// to a URL, and that reports the results of the build back to Sturdy in the same way.
+type Config struct {
+	ID            string       `db:"id"`
+	CodebaseID    codebases.ID `db:"codebase_id"`
+	IntegrationID string       `db:"integration_id"`
+
+	// Name is used as the title of the statuses of the builds.
+	Name string `db:"name"`
+	// URL is where build requests are sent.
+	URL string `db:"url"`
+	// Secret is used to sign the requests in both directions.
+	Secret string `db:"secret"`
+
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt *time.Time `db:"updated_at"`
+}
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/webhookci"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var _ Repository = &database{}
+
+type database struct {
+	db *sqlx.DB
+}
+
+func NewDatabase(db *sqlx.DB) Repository {
+	return &database{db: db}
+}
+
+func (d *database) Create(ctx context.Context, cfg *webhookci.Config) error {
+	if _, err := d.db.NamedExecContext(ctx, `
+		INSERT INTO ci_configurations_webhook
+			(id, codebase_id, integration_id, name, url, secret, created_at)
+		VALUES
+			(:id, :codebase_id, :integration_id, :name, :url, :secret, :created_at)
+	`, cfg); err != nil {
+		return fmt.Errorf("failed to insert ci_configurations_webhook: %w", err)
+	}
+	return nil
+}
+
+func (d *database) Update(ctx context.Context, cfg *webhookci.Config) error {
+	if _, err := d.db.NamedExecContext(ctx, `
+		UPDATE ci_configurations_webhook
+		SET
+			name = :name,
+			url = :url,
+			secret = :secret,
+			updated_at = :updated_at
+		WHERE
+			id = :id
+	`, cfg); err != nil {
+		return fmt.Errorf("failed to update: %w", err)
+	}
+	return nil
+}
+
+func (d *database) GetConfigsByCodebaseID(ctx context.Context, codebaseID codebases.ID) ([]*webhookci.Config, error) {
+	var cfgs []*webhookci.Config
+	if err := d.db.SelectContext(ctx, &cfgs, `
+		SELECT
+			id, codebase_id, integration_id, name, url, secret, created_at, updated_at
+		FROM ci_configurations_webhook
+		WHERE codebase_id = $1
+	`, codebaseID); err != nil {
+		return nil, fmt.Errorf("failed to get configs: %w", err)
+	}
+	return cfgs, nil
+}
+
+func (d *database) GetConfigByIntegrationID(ctx context.Context, integrationID string) (*webhookci.Config, error) {
+	var cfg webhookci.Config
+	if err := d.db.GetContext(ctx, &cfg, `
+		SELECT
+			id, codebase_id, integration_id, name, url, secret, created_at, updated_at
+		FROM ci_configurations_webhook
+		WHERE integration_id = $1
+	`, integrationID); err != nil {
+		return nil, fmt.Errorf("failed to get config: %w", err)
+	}
+	return &cfg, nil
+}
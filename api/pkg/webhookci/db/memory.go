@@ -0,0 +1,59 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/webhookci"
+)
+
+var _ Repository = &memory{}
+
+type memory struct {
+	mu              sync.RWMutex
+	byIntegrationID map[string]*webhookci.Config
+}
+
+func NewMemory() Repository {
+	return &memory{
+		byIntegrationID: make(map[string]*webhookci.Config),
+	}
+}
+
+func (m *memory) Create(_ context.Context, cfg *webhookci.Config) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.byIntegrationID[cfg.IntegrationID] = cfg
+	return nil
+}
+
+func (m *memory) Update(_ context.Context, cfg *webhookci.Config) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.byIntegrationID[cfg.IntegrationID] = cfg
+	return nil
+}
+
+func (m *memory) GetConfigsByCodebaseID(_ context.Context, codebaseID codebases.ID) ([]*webhookci.Config, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var res []*webhookci.Config
+	for _, cfg := range m.byIntegrationID {
+		if cfg.CodebaseID == codebaseID {
+			res = append(res, cfg)
+		}
+	}
+	return res, nil
+}
+
+func (m *memory) GetConfigByIntegrationID(_ context.Context, integrationID string) (*webhookci.Config, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	cfg, found := m.byIntegrationID[integrationID]
+	if !found {
+		return nil, sql.ErrNoRows
+	}
+	return cfg, nil
+}
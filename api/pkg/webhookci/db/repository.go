@@ -0,0 +1,15 @@
+package db
+
+import (
+	"context"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/webhookci"
+)
+
+type Repository interface {
+	Create(context.Context, *webhookci.Config) error
+	Update(context.Context, *webhookci.Config) error
+	GetConfigsByCodebaseID(context.Context, codebases.ID) ([]*webhookci.Config, error)
+	GetConfigByIntegrationID(ctx context.Context, integrationID string) (*webhookci.Config, error)
+}
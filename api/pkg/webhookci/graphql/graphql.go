@@ -0,0 +1,176 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	service_auth "getsturdy.com/api/pkg/auth/service"
+	service_ci "getsturdy.com/api/pkg/ci/service"
+	"getsturdy.com/api/pkg/codebases"
+	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
+	"getsturdy.com/api/pkg/graphql/resolvers"
+	"getsturdy.com/api/pkg/integrations"
+	"getsturdy.com/api/pkg/integrations/providers"
+	"getsturdy.com/api/pkg/webhookci"
+	service_webhookci "getsturdy.com/api/pkg/webhookci/service"
+
+	"github.com/google/uuid"
+	"github.com/graph-gophers/graphql-go"
+)
+
+type rootResolver struct {
+	authService                    *service_auth.Service
+	webhookCIService               *service_webhookci.Service
+	instantIntegrationService      *service_ci.Service
+	instantIntegrationRootResolver *resolvers.IntegrationRootResolver
+}
+
+func New(
+	authService *service_auth.Service,
+	webhookCIService *service_webhookci.Service,
+	instantIntegrationService *service_ci.Service,
+	instantIntegrationRootResolver *resolvers.IntegrationRootResolver,
+) resolvers.WebhookInstantIntegrationRootResolver {
+	return &rootResolver{
+		authService:                    authService,
+		webhookCIService:               webhookCIService,
+		instantIntegrationService:      instantIntegrationService,
+		instantIntegrationRootResolver: instantIntegrationRootResolver,
+	}
+}
+
+func (root *rootResolver) createNewConfiguration(ctx context.Context, args resolvers.CreateOrUpdateWebhookIntegrationArgs) (*integrations.Integration, error) {
+	integration := &integrations.Integration{
+		ID:           uuid.NewString(),
+		CodebaseID:   codebases.ID(args.Input.CodebaseID),
+		Provider:     providers.ProviderNameWebhook,
+		ProviderType: providers.ProviderTypeBuild,
+		CreatedAt:    time.Now(),
+	}
+
+	if err := root.instantIntegrationService.CreateIntegration(ctx, integration); err != nil {
+		return nil, fmt.Errorf("failed to create integration: %w", err)
+	}
+
+	cfg := &webhookci.Config{
+		ID:            uuid.NewString(),
+		CodebaseID:    codebases.ID(args.Input.CodebaseID),
+		IntegrationID: integration.ID,
+		Name:          args.Input.Name,
+		URL:           args.Input.URL,
+		Secret:        args.Input.Secret,
+		CreatedAt:     time.Now(),
+	}
+
+	if err := root.webhookCIService.CreateIntegration(ctx, cfg); err != nil {
+		return nil, fmt.Errorf("failed to create configuration: %w", err)
+	}
+
+	return integration, nil
+}
+
+func (root *rootResolver) updateConfiguration(ctx context.Context, existingCfg *webhookci.Config, args resolvers.CreateOrUpdateWebhookIntegrationArgs) (*integrations.Integration, error) {
+	integration, err := root.instantIntegrationService.GetByID(ctx, existingCfg.IntegrationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get integration: %w", err)
+	}
+
+	configChanged := existingCfg.Name != args.Input.Name ||
+		existingCfg.URL != args.Input.URL ||
+		existingCfg.Secret != args.Input.Secret
+
+	if !configChanged {
+		return integration, nil
+	}
+
+	now := time.Now()
+	existingCfg.Name = args.Input.Name
+	existingCfg.URL = args.Input.URL
+	existingCfg.Secret = args.Input.Secret
+	existingCfg.UpdatedAt = &now
+	if err := root.webhookCIService.UpdateIntegration(ctx, existingCfg); err != nil {
+		return nil, fmt.Errorf("failed to update configuration: %w", err)
+	}
+
+	integration.UpdatedAt = now
+	if err := root.instantIntegrationService.UpdateIntegration(ctx, integration); err != nil {
+		return nil, fmt.Errorf("failed to update integration: %w", err)
+	}
+
+	return integration, nil
+}
+
+func (root *rootResolver) CreateOrUpdateWebhookIntegration(ctx context.Context, args resolvers.CreateOrUpdateWebhookIntegrationArgs) (resolvers.IntegrationResolver, error) {
+	if err := root.authService.CanWrite(ctx, &codebases.Codebase{ID: codebases.ID(args.Input.CodebaseID)}); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	if u, err := url.Parse(args.Input.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "url must be a valid http or https url")
+	}
+	if args.Input.Secret == "" {
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "secret can not be empty")
+	}
+
+	// Create new
+	if args.Input.IntegrationID == nil {
+		integration, err := root.createNewConfiguration(ctx, args)
+		if err != nil {
+			return nil, gqlerrors.Error(fmt.Errorf("failed to create new configuration: %w", err))
+		}
+		return (*root.instantIntegrationRootResolver).InternalIntegrationProvider(integration), nil
+	}
+
+	// Update existing
+	existingCfg, err := root.webhookCIService.GetConfigurationByIntegrationID(ctx, string(*args.Input.IntegrationID))
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+	if existingCfg.CodebaseID != codebases.ID(args.Input.CodebaseID) {
+		return nil, gqlerrors.Error(gqlerrors.ErrNotFound)
+	}
+	integration, err := root.updateConfiguration(ctx, existingCfg, args)
+	if err != nil {
+		return nil, gqlerrors.Error(fmt.Errorf("failed to update existing configuration: %w", err))
+	}
+
+	return (*root.instantIntegrationRootResolver).InternalIntegrationProvider(integration), nil
+}
+
+func (root *rootResolver) InternalWebhookConfigurationByIntegrationID(ctx context.Context, integrationID string) (resolvers.WebhookConfigurationResolver, error) {
+	cfg, err := root.webhookCIService.GetConfigurationByIntegrationID(ctx, integrationID)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+	return &configurationResolver{
+		cfg:         cfg,
+		statusesURL: root.instantIntegrationService.WebhookStatusesURL(integrationID),
+	}, nil
+}
+
+type configurationResolver struct {
+	cfg         *webhookci.Config
+	statusesURL string
+}
+
+func (r *configurationResolver) ID() graphql.ID {
+	return graphql.ID(r.cfg.ID)
+}
+
+func (r *configurationResolver) Name() string {
+	return r.cfg.Name
+}
+
+func (r *configurationResolver) URL() string {
+	return r.cfg.URL
+}
+
+func (r *configurationResolver) Secret() string {
+	return r.cfg.Secret
+}
+
+func (r *configurationResolver) StatusesURL() string {
+	return r.statusesURL
+}
@@ -0,0 +1,137 @@
+package routes
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	svc_ci "getsturdy.com/api/pkg/ci/service"
+	"getsturdy.com/api/pkg/statuses"
+	svc_statuses "getsturdy.com/api/pkg/statuses/service"
+	service_webhookci "getsturdy.com/api/pkg/webhookci/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const maxRequestSize = 1 << 20
+
+var stateToType = map[string]statuses.Type{
+	"pending": statuses.TypePending,
+	"running": statuses.TypePending,
+
+	"healthy": statuses.TypeHealthy,
+	"success": statuses.TypeHealthy,
+
+	"failing": statuses.TypeFailing,
+	"failure": statuses.TypeFailing,
+	"error":   statuses.TypeFailing,
+}
+
+// statusRequest is the payload that builds send to the statuses url to report their status.
+type statusRequest struct {
+	// CommitID is the commit in the CI repository, as received in the build request.
+	CommitID string `json:"commit_id"`
+	State    string `json:"state"`
+	// Title is added to the description, the status is titled with the name of the integration.
+	Title       *string `json:"title"`
+	Description *string `json:"description"`
+	URL         *string `json:"url"`
+}
+
+// StatusesHandler receives statuses from builds that were started by webhook integrations. Requests must be
+// signed with the secret of the integration, in the same way as the build requests, and have a recent timestamp.
+func StatusesHandler(
+	logger *zap.Logger,
+	webhookCIService *service_webhookci.Service,
+	ciService *svc_ci.Service,
+	statusesService *svc_statuses.Service,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		integrationID := c.Param("id")
+		logger := logger.With(zap.String("integration_id", integrationID))
+
+		cfg, err := webhookCIService.GetConfigurationByIntegrationID(c.Request.Context(), integrationID)
+		if errors.Is(err, sql.ErrNoRows) {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		} else if err != nil {
+			logger.Error("failed to get configuration", zap.Error(err))
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		requestBody, err := io.ReadAll(io.LimitReader(c.Request.Body, maxRequestSize))
+		if err != nil {
+			logger.Error("failed to read body", zap.Error(err))
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		if err := service_webhookci.VerifySignature(
+			cfg.Secret,
+			requestBody,
+			c.GetHeader(service_webhookci.TimestampHeader),
+			c.GetHeader(service_webhookci.SignatureHeader),
+		); err != nil {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+
+		var payload statusRequest
+		if err := json.Unmarshal(requestBody, &payload); err != nil {
+			c.AbortWithError(http.StatusBadRequest, fmt.Errorf("failed to parse payload"))
+			return
+		}
+
+		statusType, ok := stateToType[payload.State]
+		if !ok {
+			c.AbortWithError(http.StatusBadRequest, fmt.Errorf("invalid state: %s", payload.State))
+			return
+		}
+
+		trunkCommitSHA, err := ciService.GetTrunkCommitSHA(c.Request.Context(), cfg.CodebaseID, payload.CommitID)
+		if errors.Is(err, sql.ErrNoRows) {
+			c.AbortWithError(http.StatusBadRequest, fmt.Errorf("unknown commit: %s", payload.CommitID))
+			return
+		} else if err != nil {
+			logger.Error("could not find trunk commit", zap.String("commit_id", payload.CommitID), zap.Error(err))
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		// The status has the same title as the pending status that was set when the build was started, so that it
+		// replaces it. The title of the payload is a part of the description.
+		description := payload.Description
+		if payload.Title != nil && *payload.Title != "" {
+			d := *payload.Title
+			if description != nil && *description != "" {
+				d = fmt.Sprintf("%s: %s", *payload.Title, *description)
+			}
+			description = &d
+		}
+
+		status := &statuses.Status{
+			ID:          uuid.NewString(),
+			CommitSHA:   trunkCommitSHA,
+			CodebaseID:  cfg.CodebaseID,
+			Type:        statusType,
+			Title:       cfg.Name,
+			Description: description,
+			DetailsURL:  payload.URL,
+			Timestamp:   time.Now(),
+		}
+		if err := statusesService.Set(c.Request.Context(), status); err != nil {
+			logger.Error("failed to set status", zap.Error(err))
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, status)
+	}
+}
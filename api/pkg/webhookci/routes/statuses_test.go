@@ -0,0 +1,130 @@
+package routes_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"getsturdy.com/api/pkg/ci"
+	db_ci "getsturdy.com/api/pkg/ci/db"
+	svc_ci "getsturdy.com/api/pkg/ci/service"
+	"getsturdy.com/api/pkg/codebases"
+	db_codebases "getsturdy.com/api/pkg/codebases/db"
+	"getsturdy.com/api/pkg/configuration"
+	"getsturdy.com/api/pkg/di"
+	db_installations "getsturdy.com/api/pkg/installations/db"
+	"getsturdy.com/api/pkg/logger"
+	db_chat "getsturdy.com/api/pkg/notification/chat/db"
+	module_queue "getsturdy.com/api/pkg/queue/module"
+	db_snapshots "getsturdy.com/api/pkg/snapshots/db"
+	"getsturdy.com/api/pkg/statuses"
+	db_statuses "getsturdy.com/api/pkg/statuses/db"
+	svc_statuses "getsturdy.com/api/pkg/statuses/service"
+	db_suggestions "getsturdy.com/api/pkg/suggestions/db"
+	db_view "getsturdy.com/api/pkg/views/db"
+	"getsturdy.com/api/pkg/webhookci"
+	db_webhookci "getsturdy.com/api/pkg/webhookci/db"
+	routes_webhookci "getsturdy.com/api/pkg/webhookci/routes"
+	service_webhookci "getsturdy.com/api/pkg/webhookci/service"
+	db_webhooks "getsturdy.com/api/pkg/webhooks/db"
+	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
+	"getsturdy.com/api/vcs/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestStatusesHandler(t *testing.T) {
+	ctx := context.Background()
+
+	type deps struct {
+		di.In
+		CIService        *svc_ci.Service
+		WebhookCIService *service_webhookci.Service
+		StatusesService  *svc_statuses.Service
+		ConfigRepo       db_webhookci.Repository
+		CommitRepo       db_ci.CommitRepository
+	}
+	module := func(c *di.Container) {
+		c.Import(svc_ci.Module)
+		c.Import(service_webhookci.Module)
+		c.Import(svc_statuses.Module)
+		c.ImportWithForce(db_ci.TestModule)
+		c.ImportWithForce(db_webhookci.TestModule)
+		c.ImportWithForce(db_statuses.TestModule)
+		c.ImportWithForce(db_webhooks.TestModule)
+		c.ImportWithForce(db_chat.TestModule)
+		c.ImportWithForce(db_snapshots.TestModule)
+		c.ImportWithForce(db_view.TestModule)
+		c.ImportWithForce(db_workspaces.TestModule)
+		c.ImportWithForce(db_suggestions.TestModule)
+		c.ImportWithForce(db_codebases.TestModule)
+		c.ImportWithForce(db_installations.TestModule)
+		c.ImportWithForce(module_queue.TestModule(t))
+		c.ImportWithForce(configuration.TestModule)
+		c.RegisterWithForce(logger.NewTest)
+		c.RegisterWithForce(func() *sqlx.DB { return nil })
+		c.Register(func() *testing.T { return t })
+		c.RegisterWithForce(testutil.TestingRepoProvider)
+	}
+	var d deps
+	require.NoError(t, di.Init(module).To(&d))
+
+	codebaseID := codebases.ID(uuid.NewString())
+	require.NoError(t, d.ConfigRepo.Create(ctx, &webhookci.Config{
+		ID:            uuid.NewString(),
+		CodebaseID:    codebaseID,
+		IntegrationID: "integration-id",
+		Name:          "Jenkins",
+		URL:           "https://ci.example.com",
+		Secret:        "secret",
+	}))
+	require.NoError(t, d.CommitRepo.Create(ctx, &ci.Commit{
+		ID:              uuid.NewString(),
+		CodebaseID:      codebaseID,
+		CiRepoCommitSHA: "ci-commit",
+		TrunkCommitSHA:  "trunk-commit",
+		CreatedAt:       time.Now(),
+	}))
+
+	// the pending status is set when the build is started, and is titled with the name of the build
+	require.NoError(t, d.StatusesService.Set(ctx, &statuses.Status{
+		ID:         uuid.NewString(),
+		CommitSHA:  "trunk-commit",
+		CodebaseID: codebaseID,
+		Type:       statuses.TypePending,
+		Title:      "Jenkins",
+		Timestamp:  time.Now().Add(-time.Minute),
+	}))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/v3/integrations/webhook/:id/statuses", routes_webhookci.StatusesHandler(zap.NewNop(), d.WebhookCIService, d.CIService, d.StatusesService))
+
+	body := []byte(`{"commit_id": "ci-commit", "state": "success", "title": "unit tests", "description": "all tests passed"}`)
+	now := time.Now()
+	req := httptest.NewRequest(http.MethodPost, "/v3/integrations/webhook/integration-id/statuses", bytes.NewReader(body))
+	req.Header.Set(service_webhookci.TimestampHeader, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(service_webhookci.SignatureHeader, service_webhookci.Sign("secret", now, body))
+	res := httptest.NewRecorder()
+	router.ServeHTTP(res, req)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+
+	// the reported status replaces the pending one
+	ss, err := d.StatusesService.List(ctx, codebaseID, "trunk-commit")
+	require.NoError(t, err)
+	require.Len(t, ss, 1)
+	assert.Equal(t, statuses.TypeHealthy, ss[0].Type)
+	assert.Equal(t, "Jenkins", ss[0].Title)
+	if assert.NotNil(t, ss[0].Description) {
+		assert.Equal(t, "unit tests: all tests passed", *ss[0].Description)
+	}
+}
@@ -0,0 +1,11 @@
+package service
+
+import (
+	"getsturdy.com/api/pkg/di"
+	db_webhookci "getsturdy.com/api/pkg/webhookci/db"
+)
+
+func Module(c *di.Container) {
+	c.Import(db_webhookci.Module)
+	c.Register(New)
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"getsturdy.com/api/pkg/changes"
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/webhookci"
+	db_webhookci "getsturdy.com/api/pkg/webhookci/db"
+)
+
+const (
+	// SignatureHeader contains the hex encoded HMAC-SHA256 of the timestamp, a dot, and the request body, using the
+	// secret of the integration as the key, prefixed with "sha256=". It's set on requests in both directions.
+	SignatureHeader = "X-Sturdy-Signature"
+	// TimestampHeader contains the unix time of when the request was signed.
+	TimestampHeader = "X-Sturdy-Timestamp"
+
+	signaturePrefix = "sha256="
+
+	// maxResponseSize is the max number of bytes that are read from the response to a build request.
+	maxResponseSize = 1 << 20
+)
+
+var (
+	// signatureTolerance is how far off the timestamp of a signed request can be from the current time. Requests
+	// outside of the window are rejected, so that they can not be replayed.
+	signatureTolerance = 5 * time.Minute
+)
+
+var ErrInvalidSignature = errors.New("invalid signature")
+
+type Service struct {
+	configRepo db_webhookci.Repository
+	client     *http.Client
+}
+
+func New(configRepo db_webhookci.Repository) *Service {
+	return &Service{
+		configRepo: configRepo,
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (s *Service) CreateIntegration(ctx context.Context, cfg *webhookci.Config) error {
+	return s.configRepo.Create(ctx, cfg)
+}
+
+func (s *Service) UpdateIntegration(ctx context.Context, cfg *webhookci.Config) error {
+	return s.configRepo.Update(ctx, cfg)
+}
+
+func (s *Service) GetConfigurationByIntegrationID(ctx context.Context, integrationID string) (*webhookci.Config, error) {
+	return s.configRepo.GetConfigByIntegrationID(ctx, integrationID)
+}
+
+// BuildRequest is the payload that is sent to the URL of the integration to start a build.
+type BuildRequest struct {
+	IntegrationID string       `json:"integration_id"`
+	CodebaseID    codebases.ID `json:"codebase_id"`
+	// CommitID is the commit in the CI repository of the codebase that should be built.
+	CommitID string `json:"commit_id"`
+	// TrunkCommitID is the commit in the codebase that the commit in the CI repository was created from.
+	TrunkCommitID string      `json:"trunk_commit_id"`
+	Title         string      `json:"title"`
+	WorkspaceID   *string     `json:"workspace_id,omitempty"`
+	ChangeID      *changes.ID `json:"change_id,omitempty"`
+	// StatusesURL is where the build should report its statuses, see StatusRequest.
+	StatusesURL string `json:"statuses_url"`
+}
+
+// buildResponse is the optional response to a build request.
+type buildResponse struct {
+	URL string `json:"url"`
+}
+
+type Build struct {
+	Name string
+	URL  *string
+}
+
+// CreateBuild sends a signed build request to the URL of the integration.
+func (s *Service) CreateBuild(ctx context.Context, integrationID string, request *BuildRequest) (*Build, error) {
+	cfg, err := s.configRepo.GetConfigByIntegrationID(ctx, integrationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config by integration id: %w", err)
+	}
+
+	request.IntegrationID = integrationID
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build json: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	SignRequest(req, cfg.Secret, body)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make build request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected response status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	build := &Build{Name: cfg.Name}
+
+	// The response is allowed to be empty, or anything else than json, but if it contains a url, it's used
+	// as the details url of the build status.
+	var parsed buildResponse
+	if err := json.Unmarshal(respBody, &parsed); err == nil && parsed.URL != "" {
+		build.URL = &parsed.URL
+	}
+
+	return build, nil
+}
+
+// SignRequest sets the signature and the timestamp headers of the request.
+func SignRequest(req *http.Request, secret string, body []byte) {
+	timestamp := time.Now()
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp.Unix(), 10))
+	req.Header.Set(SignatureHeader, Sign(secret, timestamp, body))
+}
+
+// Sign returns the value of the signature header for the body, signed at timestamp.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = fmt.Fprintf(mac, "%d.", timestamp.Unix())
+	_, _ = mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature returns ErrInvalidSignature if signature is not a valid signature of the body at the timestamp,
+// or if the timestamp is not within the tolerance window.
+func VerifySignature(secret string, body []byte, timestamp, signature string) error {
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	signedAt := time.Unix(unix, 0)
+	if since := time.Since(signedAt); since > signatureTolerance || since < -signatureTolerance {
+		return ErrInvalidSignature
+	}
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(Sign(secret, signedAt, body)), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
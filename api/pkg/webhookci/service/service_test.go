@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"getsturdy.com/api/pkg/webhookci"
+	db_webhookci "getsturdy.com/api/pkg/webhookci/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateBuild(t *testing.T) {
+	ctx := context.Background()
+
+	var received BuildRequest
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		if err := VerifySignature("secret", body, r.Header.Get(TimestampHeader), r.Header.Get(SignatureHeader)); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		require.NoError(t, json.Unmarshal(body, &received))
+		_, _ = w.Write([]byte(`{"url": "https://ci.example.com/builds/1"}`))
+	}))
+	defer stub.Close()
+
+	repo := db_webhookci.NewMemory()
+	require.NoError(t, repo.Create(ctx, &webhookci.Config{
+		ID:            "config-id",
+		CodebaseID:    "codebase-id",
+		IntegrationID: "integration-id",
+		Name:          "Jenkins",
+		URL:           stub.URL,
+		Secret:        "secret",
+	}))
+
+	svc := New(repo)
+
+	workspaceID := "workspace-id"
+	build, err := svc.CreateBuild(ctx, "integration-id", &BuildRequest{
+		CodebaseID:    "codebase-id",
+		CommitID:      "ci-commit",
+		TrunkCommitID: "trunk-commit",
+		Title:         "My workspace",
+		WorkspaceID:   &workspaceID,
+		StatusesURL:   "https://api.example.com/statuses",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Jenkins", build.Name)
+	if assert.NotNil(t, build.URL) {
+		assert.Equal(t, "https://ci.example.com/builds/1", *build.URL)
+	}
+
+	assert.Equal(t, "integration-id", received.IntegrationID)
+	assert.Equal(t, "ci-commit", received.CommitID)
+	assert.Equal(t, "trunk-commit", received.TrunkCommitID)
+	assert.Equal(t, &workspaceID, received.WorkspaceID)
+	assert.Nil(t, received.ChangeID)
+}
+
+func TestCreateBuild_failed(t *testing.T) {
+	ctx := context.Background()
+
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer stub.Close()
+
+	repo := db_webhookci.NewMemory()
+	require.NoError(t, repo.Create(ctx, &webhookci.Config{
+		IntegrationID: "integration-id",
+		URL:           stub.URL,
+		Secret:        "secret",
+	}))
+
+	_, err := New(repo).CreateBuild(ctx, "integration-id", &BuildRequest{})
+	assert.Error(t, err)
+}
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte(`{"state":"success"}`)
+	now := time.Now()
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	signature := Sign("secret", now, body)
+
+	assert.NoError(t, VerifySignature("secret", body, timestamp, signature))
+	assert.ErrorIs(t, VerifySignature("other", body, timestamp, signature), ErrInvalidSignature)
+	assert.ErrorIs(t, VerifySignature("secret", []byte(`{"state":"failure"}`), timestamp, signature), ErrInvalidSignature)
+	assert.ErrorIs(t, VerifySignature("secret", body, timestamp, ""), ErrInvalidSignature)
+	assert.ErrorIs(t, VerifySignature("secret", body, "", signature), ErrInvalidSignature)
+
+	// the timestamp is signed, and can not be changed
+	later := strconv.FormatInt(now.Add(time.Minute).Unix(), 10)
+	assert.ErrorIs(t, VerifySignature("secret", body, later, signature), ErrInvalidSignature)
+}
+
+func TestVerifySignature_replayed(t *testing.T) {
+	body := []byte(`{"state":"success"}`)
+
+	old := time.Now().Add(-time.Hour)
+	assert.ErrorIs(t, VerifySignature("secret", body, strconv.FormatInt(old.Unix(), 10), Sign("secret", old, body)), ErrInvalidSignature)
+
+	future := time.Now().Add(time.Hour)
+	assert.ErrorIs(t, VerifySignature("secret", body, strconv.FormatInt(future.Unix(), 10), Sign("secret", future, body)), ErrInvalidSignature)
+}
@@ -0,0 +1,145 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/webhooks"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var _ Repository = &database{}
+
+type database struct {
+	db *sqlx.DB
+}
+
+func NewDatabase(db *sqlx.DB) Repository {
+	return &database{db: db}
+}
+
+func (d *database) Create(ctx context.Context, webhook *webhooks.Webhook) error {
+	if _, err := d.db.NamedExecContext(ctx, `
+		INSERT INTO webhooks
+			(id, codebase_id, url, secret, events, created_by, created_at)
+		VALUES
+			(:id, :codebase_id, :url, :secret, :events, :created_by, :created_at)
+	`, webhook); err != nil {
+		return fmt.Errorf("failed to insert webhook: %w", err)
+	}
+	return nil
+}
+
+func (d *database) Update(ctx context.Context, webhook *webhooks.Webhook) error {
+	if _, err := d.db.NamedExecContext(ctx, `
+		UPDATE webhooks
+		SET
+			url = :url,
+			secret = :secret,
+			events = :events,
+			updated_at = :updated_at,
+			deleted_at = :deleted_at
+		WHERE
+			id = :id
+	`, webhook); err != nil {
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+	return nil
+}
+
+func (d *database) Get(ctx context.Context, id webhooks.ID) (*webhooks.Webhook, error) {
+	var webhook webhooks.Webhook
+	if err := d.db.GetContext(ctx, &webhook, `
+		SELECT
+			id, codebase_id, url, secret, events, created_by, created_at, updated_at, deleted_at
+		FROM webhooks
+		WHERE id = $1
+	`, id); err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	return &webhook, nil
+}
+
+func (d *database) ListByCodebaseID(ctx context.Context, codebaseID codebases.ID) ([]*webhooks.Webhook, error) {
+	var res []*webhooks.Webhook
+	if err := d.db.SelectContext(ctx, &res, `
+		SELECT
+			id, codebase_id, url, secret, events, created_by, created_at, updated_at, deleted_at
+		FROM webhooks
+		WHERE codebase_id = $1
+			AND deleted_at IS NULL
+		ORDER BY created_at ASC
+	`, codebaseID); err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	return res, nil
+}
+
+var _ DeliveryRepository = &deliveryDatabase{}
+
+type deliveryDatabase struct {
+	db *sqlx.DB
+}
+
+func NewDeliveryDatabase(db *sqlx.DB) DeliveryRepository {
+	return &deliveryDatabase{db: db}
+}
+
+func (d *deliveryDatabase) Create(ctx context.Context, delivery *webhooks.Delivery) error {
+	if _, err := d.db.NamedExecContext(ctx, `
+		INSERT INTO webhook_deliveries
+			(id, webhook_id, event_id, event_type, payload, attempts, status_code, error, created_at, last_attempt_at, delivered_at)
+		VALUES
+			(:id, :webhook_id, :event_id, :event_type, :payload, :attempts, :status_code, :error, :created_at, :last_attempt_at, :delivered_at)
+	`, delivery); err != nil {
+		return fmt.Errorf("failed to insert delivery: %w", err)
+	}
+	return nil
+}
+
+func (d *deliveryDatabase) Update(ctx context.Context, delivery *webhooks.Delivery) error {
+	if _, err := d.db.NamedExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET
+			attempts = :attempts,
+			status_code = :status_code,
+			error = :error,
+			last_attempt_at = :last_attempt_at,
+			delivered_at = :delivered_at
+		WHERE
+			id = :id
+	`, delivery); err != nil {
+		return fmt.Errorf("failed to update delivery: %w", err)
+	}
+	return nil
+}
+
+func (d *deliveryDatabase) Get(ctx context.Context, id webhooks.DeliveryID) (*webhooks.Delivery, error) {
+	var delivery webhooks.Delivery
+	if err := d.db.GetContext(ctx, &delivery, `
+		SELECT
+			id, webhook_id, event_id, event_type, payload, attempts, status_code, error, created_at, last_attempt_at, delivered_at
+		FROM webhook_deliveries
+		WHERE id = $1
+	`, id); err != nil {
+		return nil, fmt.Errorf("failed to get delivery: %w", err)
+	}
+	return &delivery, nil
+}
+
+func (d *deliveryDatabase) ListByWebhookID(ctx context.Context, webhookID webhooks.ID, limit int) ([]*webhooks.Delivery, error) {
+	var res []*webhooks.Delivery
+	if err := d.db.SelectContext(ctx, &res, `
+		SELECT
+			id, webhook_id, event_id, event_type, payload, attempts, status_code, error, created_at, last_attempt_at, delivered_at
+		FROM webhook_deliveries
+		WHERE webhook_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`, webhookID, limit); err != nil {
+		return nil, fmt.Errorf("failed to list deliveries: %w", err)
+	}
+	return res, nil
+}
@@ -0,0 +1,128 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/webhooks"
+)
+
+var _ Repository = &memory{}
+
+type memory struct {
+	mu   sync.RWMutex
+	byID map[webhooks.ID]webhooks.Webhook
+}
+
+func NewMemory() Repository {
+	return &memory{
+		byID: make(map[webhooks.ID]webhooks.Webhook),
+	}
+}
+
+func (m *memory) Create(_ context.Context, webhook *webhooks.Webhook) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.byID[webhook.ID] = *webhook
+	return nil
+}
+
+func (m *memory) Update(_ context.Context, webhook *webhooks.Webhook) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, found := m.byID[webhook.ID]; !found {
+		return sql.ErrNoRows
+	}
+	m.byID[webhook.ID] = *webhook
+	return nil
+}
+
+func (m *memory) Get(_ context.Context, id webhooks.ID) (*webhooks.Webhook, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	webhook, found := m.byID[id]
+	if !found {
+		return nil, sql.ErrNoRows
+	}
+	return &webhook, nil
+}
+
+func (m *memory) ListByCodebaseID(_ context.Context, codebaseID codebases.ID) ([]*webhooks.Webhook, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var res []*webhooks.Webhook
+	for _, webhook := range m.byID {
+		if webhook.CodebaseID != codebaseID || webhook.DeletedAt != nil {
+			continue
+		}
+		webhook := webhook
+		res = append(res, &webhook)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].CreatedAt.Before(res[j].CreatedAt)
+	})
+	return res, nil
+}
+
+var _ DeliveryRepository = &deliveryMemory{}
+
+type deliveryMemory struct {
+	mu   sync.RWMutex
+	byID map[webhooks.DeliveryID]webhooks.Delivery
+}
+
+func NewDeliveryMemory() DeliveryRepository {
+	return &deliveryMemory{
+		byID: make(map[webhooks.DeliveryID]webhooks.Delivery),
+	}
+}
+
+func (m *deliveryMemory) Create(_ context.Context, delivery *webhooks.Delivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.byID[delivery.ID] = *delivery
+	return nil
+}
+
+func (m *deliveryMemory) Update(_ context.Context, delivery *webhooks.Delivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, found := m.byID[delivery.ID]; !found {
+		return sql.ErrNoRows
+	}
+	m.byID[delivery.ID] = *delivery
+	return nil
+}
+
+func (m *deliveryMemory) Get(_ context.Context, id webhooks.DeliveryID) (*webhooks.Delivery, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	delivery, found := m.byID[id]
+	if !found {
+		return nil, sql.ErrNoRows
+	}
+	return &delivery, nil
+}
+
+func (m *deliveryMemory) ListByWebhookID(_ context.Context, webhookID webhooks.ID, limit int) ([]*webhooks.Delivery, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var res []*webhooks.Delivery
+	for _, delivery := range m.byID {
+		if delivery.WebhookID != webhookID {
+			continue
+		}
+		delivery := delivery
+		res = append(res, &delivery)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].CreatedAt.After(res[j].CreatedAt)
+	})
+	if len(res) > limit {
+		res = res[:limit]
+	}
+	return res, nil
+}
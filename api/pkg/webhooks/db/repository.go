@@ -0,0 +1,24 @@
+package db
+
+import (
+	"context"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/webhooks"
+)
+
+type Repository interface {
+	Create(context.Context, *webhooks.Webhook) error
+	Update(context.Context, *webhooks.Webhook) error
+	Get(context.Context, webhooks.ID) (*webhooks.Webhook, error)
+	// ListByCodebaseID returns all webhooks of the codebase that are not deleted.
+	ListByCodebaseID(context.Context, codebases.ID) ([]*webhooks.Webhook, error)
+}
+
+type DeliveryRepository interface {
+	Create(context.Context, *webhooks.Delivery) error
+	Update(context.Context, *webhooks.Delivery) error
+	Get(context.Context, webhooks.DeliveryID) (*webhooks.Delivery, error)
+	// ListByWebhookID returns the latest deliveries to the webhook, newest first.
+	ListByWebhookID(ctx context.Context, webhookID webhooks.ID, limit int) ([]*webhooks.Delivery, error)
+}
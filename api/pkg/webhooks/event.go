@@ -0,0 +1,146 @@
+package webhooks
+
+import (
+	"time"
+
+	"getsturdy.com/api/pkg/changes"
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/comments"
+	"getsturdy.com/api/pkg/review"
+	"getsturdy.com/api/pkg/statuses"
+	"getsturdy.com/api/pkg/users"
+	"getsturdy.com/api/pkg/workspaces"
+)
+
+// Event is the body of a delivery. Exactly one of the objects is set, depending on the type of the event.
+type Event struct {
+	ID         string       `json:"id"`
+	Type       EventType    `json:"type"`
+	CodebaseID codebases.ID `json:"codebase_id"`
+	CreatedAt  time.Time    `json:"created_at"`
+
+	Change    *Change    `json:"change,omitempty"`
+	Workspace *Workspace `json:"workspace,omitempty"`
+	Review    *Review    `json:"review,omitempty"`
+	Comment   *Comment   `json:"comment,omitempty"`
+	Status    *Status    `json:"status,omitempty"`
+}
+
+type Change struct {
+	ID          changes.ID `json:"id"`
+	Title       *string    `json:"title"`
+	Description string     `json:"description"`
+	CommitID    *string    `json:"commit_id"`
+	WorkspaceID *string    `json:"workspace_id"`
+	UserID      *users.ID  `json:"user_id"`
+	CreatedAt   *time.Time `json:"created_at"`
+}
+
+func NewChange(ch *changes.Change) *Change {
+	return &Change{
+		ID:          ch.ID,
+		Title:       ch.Title,
+		Description: ch.UpdatedDescription,
+		CommitID:    ch.CommitID,
+		WorkspaceID: ch.WorkspaceID,
+		UserID:      ch.UserID,
+		CreatedAt:   ch.CreatedAt,
+	}
+}
+
+type Workspace struct {
+	ID              string     `json:"id"`
+	Name            *string    `json:"name"`
+	UserID          users.ID   `json:"user_id"`
+	BaseWorkspaceID *string    `json:"base_workspace_id"`
+	CreatedAt       *time.Time `json:"created_at"`
+}
+
+func NewWorkspace(ws *workspaces.Workspace) *Workspace {
+	return &Workspace{
+		ID:              ws.ID,
+		Name:            ws.Name,
+		UserID:          ws.UserID,
+		BaseWorkspaceID: ws.BaseWorkspaceID,
+		CreatedAt:       ws.CreatedAt,
+	}
+}
+
+type Review struct {
+	ID          string             `json:"id"`
+	WorkspaceID string             `json:"workspace_id"`
+	UserID      users.ID           `json:"user_id"`
+	Grade       review.ReviewGrade `json:"grade"`
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+func NewReview(rev *review.Review) *Review {
+	return &Review{
+		ID:          rev.ID,
+		WorkspaceID: rev.WorkspaceID,
+		UserID:      rev.UserID,
+		Grade:       rev.Grade,
+		CreatedAt:   rev.CreatedAt,
+	}
+}
+
+type Comment struct {
+	ID              comments.ID  `json:"id"`
+	ChangeID        *changes.ID  `json:"change_id"`
+	WorkspaceID     *string      `json:"workspace_id"`
+	ParentCommentID *comments.ID `json:"parent_comment_id"`
+	UserID          users.ID     `json:"user_id"`
+	Message         string       `json:"message"`
+	Path            string       `json:"path,omitempty"`
+	LineStart       int          `json:"line_start,omitempty"`
+	LineEnd         int          `json:"line_end,omitempty"`
+	CreatedAt       time.Time    `json:"created_at"`
+}
+
+func NewComment(c *comments.Comment) *Comment {
+	return &Comment{
+		ID:              c.ID,
+		ChangeID:        c.ChangeID,
+		WorkspaceID:     c.WorkspaceID,
+		ParentCommentID: c.ParentComment,
+		UserID:          c.UserID,
+		Message:         c.Message,
+		Path:            c.Path,
+		LineStart:       c.LineStart,
+		LineEnd:         c.LineEnd,
+		CreatedAt:       c.CreatedAt,
+	}
+}
+
+// Redacted returns a copy of the comment without its message and the file that it's on, for receivers that are not
+// allowed to see the file.
+func (c *Comment) Redacted() *Comment {
+	redacted := *c
+	redacted.Message = ""
+	redacted.Path = ""
+	redacted.LineStart = 0
+	redacted.LineEnd = 0
+	return &redacted
+}
+
+type Status struct {
+	ID          string        `json:"id"`
+	CommitID    string        `json:"commit_id"`
+	Type        statuses.Type `json:"type"`
+	Title       string        `json:"title"`
+	Description *string       `json:"description"`
+	DetailsURL  *string       `json:"details_url"`
+	Timestamp   time.Time     `json:"timestamp"`
+}
+
+func NewStatus(s *statuses.Status) *Status {
+	return &Status{
+		ID:          s.ID,
+		CommitID:    s.CommitSHA,
+		Type:        s.Type,
+		Title:       s.Title,
+		Description: s.Description,
+		DetailsURL:  s.DetailsURL,
+		Timestamp:   s.Timestamp,
+	}
+}
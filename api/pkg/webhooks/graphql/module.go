@@ -0,0 +1,17 @@
+package graphql
+
+import (
+	service_auth "getsturdy.com/api/pkg/auth/service"
+	service_codebase "getsturdy.com/api/pkg/codebases/service"
+	"getsturdy.com/api/pkg/di"
+	"getsturdy.com/api/pkg/graphql/resolvers"
+	service_webhooks "getsturdy.com/api/pkg/webhooks/service"
+)
+
+func Module(c *di.Container) {
+	c.Import(service_webhooks.Module)
+	c.Import(service_codebase.Module)
+	c.Import(service_auth.Module)
+	c.Import(resolvers.Module)
+	c.Register(New)
+}
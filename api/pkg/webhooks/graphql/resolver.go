@@ -0,0 +1,137 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
+	"getsturdy.com/api/pkg/graphql/resolvers"
+	"getsturdy.com/api/pkg/webhooks"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+const (
+	defaultDeliveries = 20
+	maxDeliveries     = 100
+)
+
+type webhookResolver struct {
+	root    *rootResolver
+	webhook *webhooks.Webhook
+}
+
+func (r *webhookResolver) ID() graphql.ID {
+	return graphql.ID(r.webhook.ID)
+}
+
+func (r *webhookResolver) Codebase(ctx context.Context) (resolvers.CodebaseResolver, error) {
+	id := graphql.ID(r.webhook.CodebaseID)
+	return (*r.root.codebaseRootResolver).Codebase(ctx, resolvers.CodebaseArgs{ID: &id})
+}
+
+func (r *webhookResolver) URL() string {
+	return r.webhook.URL
+}
+
+func (r *webhookResolver) Events() ([]resolvers.WebhookEvent, error) {
+	res := make([]resolvers.WebhookEvent, 0, len(r.webhook.Events))
+	for _, e := range r.webhook.EventTypes() {
+		event, ok := fromEvent[e]
+		if !ok {
+			return nil, gqlerrors.Error(fmt.Errorf("unknown event: %s", e))
+		}
+		res = append(res, event)
+	}
+	return res, nil
+}
+
+func (r *webhookResolver) CreatedAt() int32 {
+	return int32(r.webhook.CreatedAt.Unix())
+}
+
+func (r *webhookResolver) UpdatedAt() *int32 {
+	return toUnix(r.webhook.UpdatedAt)
+}
+
+func (r *webhookResolver) Deliveries(ctx context.Context, args resolvers.WebhookDeliveriesArgs) ([]resolvers.WebhookDeliveryResolver, error) {
+	limit := defaultDeliveries
+	if args.Last != nil {
+		limit = int(*args.Last)
+	}
+	if limit < 1 || limit > maxDeliveries {
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", fmt.Sprintf("last must be between 1 and %d", maxDeliveries))
+	}
+
+	deliveries, err := r.root.webhooksService.ListDeliveries(ctx, r.webhook.ID, limit)
+	if err != nil {
+		return nil, gqlerrors.Error(fmt.Errorf("failed to list deliveries: %w", err))
+	}
+
+	res := make([]resolvers.WebhookDeliveryResolver, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		res = append(res, &deliveryResolver{delivery: delivery})
+	}
+	return res, nil
+}
+
+type deliveryResolver struct {
+	delivery *webhooks.Delivery
+}
+
+func (r *deliveryResolver) ID() graphql.ID {
+	return graphql.ID(r.delivery.ID)
+}
+
+func (r *deliveryResolver) EventID() graphql.ID {
+	return graphql.ID(r.delivery.EventID)
+}
+
+func (r *deliveryResolver) Event() (resolvers.WebhookEvent, error) {
+	event, ok := fromEvent[r.delivery.EventType]
+	if !ok {
+		return resolvers.WebhookEventUndefined, gqlerrors.Error(fmt.Errorf("unknown event: %s", r.delivery.EventType))
+	}
+	return event, nil
+}
+
+func (r *deliveryResolver) Payload() string {
+	return string(r.delivery.Payload)
+}
+
+func (r *deliveryResolver) Attempts() int32 {
+	return int32(r.delivery.Attempts)
+}
+
+func (r *deliveryResolver) StatusCode() *int32 {
+	if r.delivery.StatusCode == nil {
+		return nil
+	}
+	code := int32(*r.delivery.StatusCode)
+	return &code
+}
+
+func (r *deliveryResolver) Error() *string {
+	return r.delivery.Error
+}
+
+func (r *deliveryResolver) CreatedAt() int32 {
+	return int32(r.delivery.CreatedAt.Unix())
+}
+
+func (r *deliveryResolver) LastAttemptAt() *int32 {
+	return toUnix(r.delivery.LastAttemptAt)
+}
+
+func (r *deliveryResolver) DeliveredAt() *int32 {
+	return toUnix(r.delivery.DeliveredAt)
+}
+
+func toUnix(t *time.Time) *int32 {
+	if t == nil {
+		return nil
+	}
+	unix := int32(t.Unix())
+	return &unix
+}
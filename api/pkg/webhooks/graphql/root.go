@@ -0,0 +1,203 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"getsturdy.com/api/pkg/auth"
+	service_auth "getsturdy.com/api/pkg/auth/service"
+	"getsturdy.com/api/pkg/codebases"
+	service_codebase "getsturdy.com/api/pkg/codebases/service"
+	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
+	"getsturdy.com/api/pkg/graphql/resolvers"
+	"getsturdy.com/api/pkg/webhooks"
+	service_webhooks "getsturdy.com/api/pkg/webhooks/service"
+)
+
+var (
+	toEvent = map[resolvers.WebhookEvent]webhooks.EventType{
+		resolvers.WebhookEventChangeLanded:     webhooks.EventTypeChangeLanded,
+		resolvers.WebhookEventWorkspaceCreated: webhooks.EventTypeWorkspaceCreated,
+		resolvers.WebhookEventReviewSubmitted:  webhooks.EventTypeReviewSubmitted,
+		resolvers.WebhookEventCommentCreated:   webhooks.EventTypeCommentCreated,
+		resolvers.WebhookEventStatusUpdated:    webhooks.EventTypeStatusUpdated,
+	}
+
+	fromEvent = map[webhooks.EventType]resolvers.WebhookEvent{
+		webhooks.EventTypeChangeLanded:     resolvers.WebhookEventChangeLanded,
+		webhooks.EventTypeWorkspaceCreated: resolvers.WebhookEventWorkspaceCreated,
+		webhooks.EventTypeReviewSubmitted:  resolvers.WebhookEventReviewSubmitted,
+		webhooks.EventTypeCommentCreated:   resolvers.WebhookEventCommentCreated,
+		webhooks.EventTypeStatusUpdated:    resolvers.WebhookEventStatusUpdated,
+	}
+)
+
+type rootResolver struct {
+	webhooksService *service_webhooks.Service
+	codebaseService *service_codebase.Service
+	authService     *service_auth.Service
+
+	codebaseRootResolver *resolvers.CodebaseRootResolver
+}
+
+func New(
+	webhooksService *service_webhooks.Service,
+	codebaseService *service_codebase.Service,
+	authService *service_auth.Service,
+
+	codebaseRootResolver *resolvers.CodebaseRootResolver,
+) resolvers.WebhooksRootResolver {
+	return &rootResolver{
+		webhooksService: webhooksService,
+		codebaseService: codebaseService,
+		authService:     authService,
+
+		codebaseRootResolver: codebaseRootResolver,
+	}
+}
+
+// authorize checks that the user is allowed to manage webhooks of the codebase. Webhooks receive everything that
+// happens in the codebase, so they can only be managed by administrators of the codebase.
+func (r *rootResolver) authorize(ctx context.Context, codebaseID codebases.ID) error {
+	if _, err := auth.UserID(ctx); err != nil {
+		return err
+	}
+	cb, err := r.codebaseService.GetByID(ctx, codebaseID)
+	if err != nil {
+		return fmt.Errorf("failed to get codebase: %w", err)
+	}
+	return r.authService.CanAdmin(ctx, cb)
+}
+
+func toEvents(events []resolvers.WebhookEvent) ([]webhooks.EventType, error) {
+	res := make([]webhooks.EventType, 0, len(events))
+	for _, e := range events {
+		event, ok := toEvent[e]
+		if !ok {
+			return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", fmt.Sprintf("unknown event: %s", e))
+		}
+		res = append(res, event)
+	}
+	return res, nil
+}
+
+func validationError(err error) error {
+	switch {
+	case errors.Is(err, service_webhooks.ErrInvalidURL),
+		errors.Is(err, service_webhooks.ErrPrivateURL),
+		errors.Is(err, service_webhooks.ErrNoSecret),
+		errors.Is(err, service_webhooks.ErrNoEvents),
+		errors.Is(err, service_webhooks.ErrInvalidEvent):
+		return gqlerrors.Error(gqlerrors.ErrBadRequest, "message", err.Error())
+	default:
+		return gqlerrors.Error(err)
+	}
+}
+
+func (r *rootResolver) Webhooks(ctx context.Context, args resolvers.WebhooksArgs) ([]resolvers.WebhookResolver, error) {
+	codebaseID := codebases.ID(args.CodebaseID)
+	if err := r.authorize(ctx, codebaseID); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	hooks, err := r.webhooksService.ListByCodebaseID(ctx, codebaseID)
+	if err != nil {
+		return nil, gqlerrors.Error(fmt.Errorf("failed to list webhooks: %w", err))
+	}
+
+	res := make([]resolvers.WebhookResolver, 0, len(hooks))
+	for _, hook := range hooks {
+		res = append(res, &webhookResolver{root: r, webhook: hook})
+	}
+	return res, nil
+}
+
+func (r *rootResolver) CreateWebhook(ctx context.Context, args resolvers.CreateWebhookArgs) (resolvers.WebhookResolver, error) {
+	codebaseID := codebases.ID(args.Input.CodebaseID)
+	if err := r.authorize(ctx, codebaseID); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	userID, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	events, err := toEvents(args.Input.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	hook, err := r.webhooksService.Create(ctx, codebaseID, userID, args.Input.URL, args.Input.Secret, events)
+	if err != nil {
+		return nil, validationError(err)
+	}
+
+	return &webhookResolver{root: r, webhook: hook}, nil
+}
+
+func (r *rootResolver) UpdateWebhook(ctx context.Context, args resolvers.UpdateWebhookArgs) (resolvers.WebhookResolver, error) {
+	hook, err := r.getWebhook(ctx, webhooks.ID(args.Input.ID))
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	events, err := toEvents(args.Input.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := r.webhooksService.Update(ctx, hook, args.Input.URL, args.Input.Secret, events)
+	if err != nil {
+		return nil, validationError(err)
+	}
+
+	return &webhookResolver{root: r, webhook: updated}, nil
+}
+
+func (r *rootResolver) DeleteWebhook(ctx context.Context, args resolvers.DeleteWebhookArgs) (resolvers.WebhookResolver, error) {
+	hook, err := r.getWebhook(ctx, webhooks.ID(args.ID))
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	if err := r.webhooksService.Delete(ctx, hook); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	return &webhookResolver{root: r, webhook: hook}, nil
+}
+
+func (r *rootResolver) RedeliverWebhookDelivery(ctx context.Context, args resolvers.RedeliverWebhookDeliveryArgs) (resolvers.WebhookDeliveryResolver, error) {
+	delivery, err := r.webhooksService.GetDelivery(ctx, webhooks.DeliveryID(args.ID))
+	if err != nil {
+		return nil, gqlerrors.Error(fmt.Errorf("failed to get delivery: %w", err))
+	}
+
+	if _, err := r.getWebhook(ctx, delivery.WebhookID); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	redelivery, err := r.webhooksService.Redeliver(ctx, delivery)
+	if err != nil {
+		return nil, gqlerrors.Error(fmt.Errorf("failed to redeliver: %w", err))
+	}
+
+	return &deliveryResolver{delivery: redelivery}, nil
+}
+
+// getWebhook returns the webhook, if it's not deleted, and the user is allowed to manage it.
+func (r *rootResolver) getWebhook(ctx context.Context, id webhooks.ID) (*webhooks.Webhook, error) {
+	hook, err := r.webhooksService.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	if err := r.authorize(ctx, hook.CodebaseID); err != nil {
+		return nil, err
+	}
+	if hook.DeletedAt != nil {
+		return nil, gqlerrors.ErrNotFound
+	}
+	return hook, nil
+}
@@ -0,0 +1,19 @@
+package service
+
+import (
+	db_acl "getsturdy.com/api/pkg/codebases/acl/db"
+	"getsturdy.com/api/pkg/di"
+	"getsturdy.com/api/pkg/logger"
+	queue "getsturdy.com/api/pkg/queue/module"
+	db_user "getsturdy.com/api/pkg/users/db"
+	db_webhooks "getsturdy.com/api/pkg/webhooks/db"
+)
+
+func Module(c *di.Container) {
+	c.Import(logger.Module)
+	c.Import(db_webhooks.Module)
+	c.Import(queue.Module)
+	c.Import(db_acl.Module)
+	c.Import(db_user.Module)
+	c.Register(New)
+}
@@ -0,0 +1,405 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"getsturdy.com/api/pkg/changes"
+	"getsturdy.com/api/pkg/codebases"
+	db_acl "getsturdy.com/api/pkg/codebases/acl/db"
+	"getsturdy.com/api/pkg/comments"
+	"getsturdy.com/api/pkg/ip"
+	"getsturdy.com/api/pkg/queue"
+	"getsturdy.com/api/pkg/queue/names"
+	"getsturdy.com/api/pkg/review"
+	"getsturdy.com/api/pkg/statuses"
+	"getsturdy.com/api/pkg/unidiff"
+	"getsturdy.com/api/pkg/users"
+	db_user "getsturdy.com/api/pkg/users/db"
+	service_webhookci "getsturdy.com/api/pkg/webhookci/service"
+	"getsturdy.com/api/pkg/webhooks"
+	db_webhooks "getsturdy.com/api/pkg/webhooks/db"
+	"getsturdy.com/api/pkg/workspaces"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// EventHeader contains the type of the delivered event.
+	EventHeader = "X-Sturdy-Event"
+	// DeliveryHeader contains the id of the delivery.
+	DeliveryHeader = "X-Sturdy-Delivery"
+	// SignatureHeader contains the signature of the body, using the secret of the webhook. It's computed the same
+	// way as for webhook CI integrations.
+	SignatureHeader = service_webhookci.SignatureHeader
+	// TimestampHeader contains the time of when the delivery was signed, it's included in the signature.
+	TimestampHeader = service_webhookci.TimestampHeader
+)
+
+var (
+	ErrInvalidURL       = errors.New("url must be an absolute http or https url")
+	ErrPrivateURL       = errors.New("url must not point to a private, loopback or link-local address")
+	ErrNoSecret         = errors.New("secret is required")
+	ErrNoEvents         = errors.New("at least one event is required")
+	ErrInvalidEvent     = errors.New("invalid event")
+	ErrWebhookDeleted   = errors.New("webhook is deleted")
+	ErrAlreadyDelivered = errors.New("delivery has already been delivered")
+)
+
+// DeliveryMessage is published to the queue for every attempt to deliver an event.
+type DeliveryMessage struct {
+	DeliveryID webhooks.DeliveryID `json:"delivery_id"`
+}
+
+type Service struct {
+	logger       *zap.Logger
+	repo         db_webhooks.Repository
+	deliveryRepo db_webhooks.DeliveryRepository
+	queue        queue.Queue
+	aclRepo      db_acl.ACLRepository
+	userRepo     db_user.Repository
+	filter       ip.Filter
+	client       *http.Client
+}
+
+func New(
+	logger *zap.Logger,
+	repo db_webhooks.Repository,
+	deliveryRepo db_webhooks.DeliveryRepository,
+	queue queue.Queue,
+	aclRepo db_acl.ACLRepository,
+	userRepo db_user.Repository,
+) *Service {
+	return newService(logger, repo, deliveryRepo, queue, aclRepo, userRepo, ip.Public)
+}
+
+func newService(
+	logger *zap.Logger,
+	repo db_webhooks.Repository,
+	deliveryRepo db_webhooks.DeliveryRepository,
+	queue queue.Queue,
+	aclRepo db_acl.ACLRepository,
+	userRepo db_user.Repository,
+	filter ip.Filter,
+) *Service {
+	return &Service{
+		logger:       logger.Named("webhooksService"),
+		repo:         repo,
+		deliveryRepo: deliveryRepo,
+		queue:        queue,
+		aclRepo:      aclRepo,
+		userRepo:     userRepo,
+		filter:       filter,
+		client:       filter.Client(10 * time.Second),
+	}
+}
+
+func (s *Service) validate(ctx context.Context, webhookURL, secret string, events []webhooks.EventType) error {
+	u, err := url.Parse(webhookURL)
+	if err != nil || !u.IsAbs() || (u.Scheme != "http" && u.Scheme != "https") {
+		return ErrInvalidURL
+	}
+	if err := s.filter.CheckHost(ctx, u.Hostname()); err != nil {
+		return ErrPrivateURL
+	}
+	if secret == "" {
+		return ErrNoSecret
+	}
+	if len(events) == 0 {
+		return ErrNoEvents
+	}
+	for _, event := range events {
+		if !event.Valid() {
+			return fmt.Errorf("%w: %s", ErrInvalidEvent, event)
+		}
+	}
+	return nil
+}
+
+func toStringArray(events []webhooks.EventType) []string {
+	res := make([]string, 0, len(events))
+	for _, event := range events {
+		res = append(res, string(event))
+	}
+	return res
+}
+
+func (s *Service) Create(ctx context.Context, codebaseID codebases.ID, userID users.ID, webhookURL, secret string, events []webhooks.EventType) (*webhooks.Webhook, error) {
+	if err := s.validate(ctx, webhookURL, secret, events); err != nil {
+		return nil, err
+	}
+
+	webhook := &webhooks.Webhook{
+		ID:         webhooks.ID(uuid.NewString()),
+		CodebaseID: codebaseID,
+		URL:        webhookURL,
+		Secret:     secret,
+		Events:     toStringArray(events),
+		CreatedBy:  userID,
+		CreatedAt:  time.Now(),
+	}
+	if err := s.repo.Create(ctx, webhook); err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+	return webhook, nil
+}
+
+// Update replaces the url, secret and events of the webhook. If secret is nil, the secret is not changed.
+func (s *Service) Update(ctx context.Context, webhook *webhooks.Webhook, webhookURL string, secret *string, events []webhooks.EventType) (*webhooks.Webhook, error) {
+	updated := *webhook
+	updated.URL = webhookURL
+	if secret != nil {
+		updated.Secret = *secret
+	}
+	updated.Events = toStringArray(events)
+
+	if err := s.validate(ctx, updated.URL, updated.Secret, events); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	updated.UpdatedAt = &now
+	if err := s.repo.Update(ctx, &updated); err != nil {
+		return nil, fmt.Errorf("failed to update webhook: %w", err)
+	}
+	return &updated, nil
+}
+
+func (s *Service) Delete(ctx context.Context, webhook *webhooks.Webhook) error {
+	now := time.Now()
+	webhook.DeletedAt = &now
+	if err := s.repo.Update(ctx, webhook); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) Get(ctx context.Context, id webhooks.ID) (*webhooks.Webhook, error) {
+	return s.repo.Get(ctx, id)
+}
+
+func (s *Service) ListByCodebaseID(ctx context.Context, codebaseID codebases.ID) ([]*webhooks.Webhook, error) {
+	return s.repo.ListByCodebaseID(ctx, codebaseID)
+}
+
+func (s *Service) GetDelivery(ctx context.Context, id webhooks.DeliveryID) (*webhooks.Delivery, error) {
+	return s.deliveryRepo.Get(ctx, id)
+}
+
+func (s *Service) ListDeliveries(ctx context.Context, webhookID webhooks.ID, limit int) ([]*webhooks.Delivery, error) {
+	return s.deliveryRepo.ListByWebhookID(ctx, webhookID, limit)
+}
+
+func (s *Service) ChangeLanded(ctx context.Context, change *changes.Change) error {
+	return s.publish(ctx, &webhooks.Event{Type: webhooks.EventTypeChangeLanded, CodebaseID: change.CodebaseID, Change: webhooks.NewChange(change)})
+}
+
+func (s *Service) WorkspaceCreated(ctx context.Context, workspace *workspaces.Workspace) error {
+	return s.publish(ctx, &webhooks.Event{Type: webhooks.EventTypeWorkspaceCreated, CodebaseID: workspace.CodebaseID, Workspace: webhooks.NewWorkspace(workspace)})
+}
+
+func (s *Service) ReviewSubmitted(ctx context.Context, rev *review.Review) error {
+	return s.publish(ctx, &webhooks.Event{Type: webhooks.EventTypeReviewSubmitted, CodebaseID: rev.CodebaseID, Review: webhooks.NewReview(rev)})
+}
+
+func (s *Service) CommentCreated(ctx context.Context, comment *comments.Comment) error {
+	return s.publish(ctx, &webhooks.Event{Type: webhooks.EventTypeCommentCreated, CodebaseID: comment.CodebaseID, Comment: webhooks.NewComment(comment)})
+}
+
+func (s *Service) StatusUpdated(ctx context.Context, status *statuses.Status) error {
+	return s.publish(ctx, &webhooks.Event{Type: webhooks.EventTypeStatusUpdated, CodebaseID: status.CodebaseID, Status: webhooks.NewStatus(status)})
+}
+
+// publish creates a delivery of the event for every webhook in the codebase that subscribes to it, and schedules
+// them to be delivered.
+func (s *Service) publish(ctx context.Context, event *webhooks.Event) error {
+	hooks, err := s.repo.ListByCodebaseID(ctx, event.CodebaseID)
+	if err != nil {
+		return fmt.Errorf("failed to list webhooks: %w", err)
+	}
+
+	var subscribed []*webhooks.Webhook
+	for _, hook := range hooks {
+		if hook.Subscribes(event.Type) {
+			subscribed = append(subscribed, hook)
+		}
+	}
+	if len(subscribed) == 0 {
+		return nil
+	}
+
+	event.ID = uuid.NewString()
+	event.CreatedAt = time.Now()
+
+	for _, hook := range subscribed {
+		hookEvent, err := s.filterEvent(ctx, hook, event)
+		if err != nil {
+			return err
+		}
+		payload, err := json.Marshal(hookEvent)
+		if err != nil {
+			return fmt.Errorf("failed to marshal event: %w", err)
+		}
+		if _, err := s.createDelivery(ctx, hook.ID, event.ID, event.Type, payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// filterEvent removes everything from the event that the creator of the webhook is not allowed to see.
+func (s *Service) filterEvent(ctx context.Context, hook *webhooks.Webhook, event *webhooks.Event) (*webhooks.Event, error) {
+	if event.Comment == nil || event.Comment.Path == "" {
+		return event, nil
+	}
+
+	allower, err := s.getAllower(ctx, hook)
+	if err != nil {
+		return nil, err
+	}
+	if allower.IsAllowed(event.Comment.Path, false) {
+		return event, nil
+	}
+
+	filtered := *event
+	filtered.Comment = event.Comment.Redacted()
+	return &filtered, nil
+}
+
+// getAllower returns the files of the codebase that the creator of the webhook is allowed to access.
+func (s *Service) getAllower(ctx context.Context, hook *webhooks.Webhook) (*unidiff.Allower, error) {
+	policy, err := s.aclRepo.GetByCodebaseID(ctx, hook.CodebaseID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		// the default policy, that is created when the acl is first used, allows everyone to access all files
+		return unidiff.NewAllower("*")
+	case err != nil:
+		return nil, fmt.Errorf("failed to get acl: %w", err)
+	}
+	if err := policy.ParsePolicy(); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.Get(hook.CreatedBy)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return unidiff.NewAllower()
+	case err != nil:
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return unidiff.NewAllower(policy.Policy.AllowedFiles(user)...)
+}
+
+func (s *Service) createDelivery(ctx context.Context, webhookID webhooks.ID, eventID string, eventType webhooks.EventType, payload []byte) (*webhooks.Delivery, error) {
+	delivery := &webhooks.Delivery{
+		ID:        webhooks.DeliveryID(uuid.NewString()),
+		WebhookID: webhookID,
+		EventID:   eventID,
+		EventType: eventType,
+		Payload:   payload,
+		CreatedAt: time.Now(),
+	}
+	if err := s.deliveryRepo.Create(ctx, delivery); err != nil {
+		return nil, fmt.Errorf("failed to create delivery: %w", err)
+	}
+	if err := s.Enqueue(ctx, delivery.ID); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// Redeliver sends the event of the delivery to the webhook again, as a new delivery.
+func (s *Service) Redeliver(ctx context.Context, delivery *webhooks.Delivery) (*webhooks.Delivery, error) {
+	return s.createDelivery(ctx, delivery.WebhookID, delivery.EventID, delivery.EventType, delivery.Payload)
+}
+
+// Enqueue schedules an attempt to deliver the delivery.
+func (s *Service) Enqueue(ctx context.Context, id webhooks.DeliveryID) error {
+	if err := s.queue.Publish(ctx, names.CodebaseWebhooks, &DeliveryMessage{DeliveryID: id}); err != nil {
+		return fmt.Errorf("could not publish to queue: %w", err)
+	}
+	return nil
+}
+
+// EnqueueRetry schedules another attempt to deliver the delivery, once the backoff has passed.
+func (s *Service) EnqueueRetry(ctx context.Context, id webhooks.DeliveryID, backoff time.Duration) error {
+	if err := s.queue.PublishDelayed(ctx, names.CodebaseWebhooks, &DeliveryMessage{DeliveryID: id}, backoff); err != nil {
+		return fmt.Errorf("could not publish to queue: %w", err)
+	}
+	return nil
+}
+
+// Deliver makes one attempt to post the delivery to its webhook, and records the result in the delivery log. An
+// error is returned if the attempt failed.
+func (s *Service) Deliver(ctx context.Context, id webhooks.DeliveryID) (*webhooks.Delivery, error) {
+	delivery, err := s.deliveryRepo.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get delivery: %w", err)
+	}
+	if delivery.IsDelivered() {
+		return delivery, ErrAlreadyDelivered
+	}
+
+	webhook, err := s.repo.Get(ctx, delivery.WebhookID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	if webhook.DeletedAt != nil {
+		return delivery, ErrWebhookDeleted
+	}
+
+	statusCode, attemptErr := s.post(ctx, webhook, delivery)
+
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.StatusCode = statusCode
+	delivery.Error = nil
+	if attemptErr != nil {
+		msg := attemptErr.Error()
+		delivery.Error = &msg
+	} else {
+		delivery.DeliveredAt = &now
+	}
+
+	if err := s.deliveryRepo.Update(ctx, delivery); err != nil {
+		return nil, fmt.Errorf("failed to update delivery: %w", err)
+	}
+
+	return delivery, attemptErr
+}
+
+func (s *Service) post(ctx context.Context, webhook *webhooks.Webhook, delivery *webhooks.Delivery) (*int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Sturdy-Webhooks")
+	req.Header.Set(EventHeader, string(delivery.EventType))
+	req.Header.Set(DeliveryHeader, delivery.ID.String())
+	service_webhookci.SignRequest(req, webhook.Secret, delivery.Payload)
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer res.Body.Close()
+
+	// the body of the response is never read, it's not returned to users, so that the webhook can not be used to
+	// read the responses of other services
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return &res.StatusCode, fmt.Errorf("unexpected response status %d", res.StatusCode)
+	}
+
+	return &res.StatusCode, nil
+}
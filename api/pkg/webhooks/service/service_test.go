@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/codebases/acl"
+	db_acl "getsturdy.com/api/pkg/codebases/acl/db"
+	"getsturdy.com/api/pkg/comments"
+	"getsturdy.com/api/pkg/ip"
+	"getsturdy.com/api/pkg/queue"
+	"getsturdy.com/api/pkg/statuses"
+	"getsturdy.com/api/pkg/users"
+	db_user "getsturdy.com/api/pkg/users/db"
+	service_webhookci "getsturdy.com/api/pkg/webhookci/service"
+	"getsturdy.com/api/pkg/webhooks"
+	db_webhooks "getsturdy.com/api/pkg/webhooks/db"
+	"getsturdy.com/api/pkg/workspaces"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var allowAll ip.Filter = func(net.IP) bool { return true }
+
+// newTestService returns a service that can deliver to the stub servers of the tests, that listen on localhost.
+func newTestService() *Service {
+	return newService(zap.NewNop(), db_webhooks.NewMemory(), db_webhooks.NewDeliveryMemory(), queue.NewInMemory(zap.NewNop()), db_acl.NewInMemoryAclRepo(), db_user.NewMemory(), allowAll)
+}
+
+func TestDeliver(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService()
+
+	var received webhooks.Event
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		if err := service_webhookci.VerifySignature("secret", body, r.Header.Get(TimestampHeader), r.Header.Get(SignatureHeader)); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, string(webhooks.EventTypeStatusUpdated), r.Header.Get(EventHeader))
+		require.NoError(t, json.Unmarshal(body, &received))
+	}))
+	defer stub.Close()
+
+	codebaseID := codebases.ID("codebase-id")
+	webhook, err := svc.Create(ctx, codebaseID, "user-id", stub.URL, "secret", []webhooks.EventType{webhooks.EventTypeStatusUpdated})
+	require.NoError(t, err)
+
+	// not subscribed to
+	require.NoError(t, svc.WorkspaceCreated(ctx, &workspaces.Workspace{ID: "workspace-id", CodebaseID: codebaseID}))
+	deliveries, err := svc.ListDeliveries(ctx, webhook.ID, 10)
+	require.NoError(t, err)
+	assert.Empty(t, deliveries)
+
+	require.NoError(t, svc.StatusUpdated(ctx, &statuses.Status{ID: "status-id", CodebaseID: codebaseID, CommitSHA: "abc", Type: statuses.TypeHealthy}))
+	deliveries, err = svc.ListDeliveries(ctx, webhook.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+
+	delivered, err := svc.Deliver(ctx, deliveries[0].ID)
+	require.NoError(t, err)
+	assert.True(t, delivered.IsDelivered())
+	assert.Equal(t, 1, delivered.Attempts)
+	if assert.NotNil(t, delivered.StatusCode) {
+		assert.Equal(t, http.StatusOK, *delivered.StatusCode)
+	}
+
+	assert.Equal(t, delivered.EventID, received.ID)
+	assert.Equal(t, webhooks.EventTypeStatusUpdated, received.Type)
+	if assert.NotNil(t, received.Status) {
+		assert.Equal(t, "status-id", received.Status.ID)
+	}
+
+	_, err = svc.Deliver(ctx, delivered.ID)
+	assert.ErrorIs(t, err, ErrAlreadyDelivered)
+
+	redelivery, err := svc.Redeliver(ctx, delivered)
+	require.NoError(t, err)
+	assert.NotEqual(t, delivered.ID, redelivery.ID)
+	assert.Equal(t, delivered.EventID, redelivery.EventID)
+	assert.Equal(t, delivered.Payload, redelivery.Payload)
+}
+
+func TestDeliver_failed(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService()
+
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("oops"))
+	}))
+	defer stub.Close()
+
+	codebaseID := codebases.ID("codebase-id")
+	webhook, err := svc.Create(ctx, codebaseID, "user-id", stub.URL, "secret", []webhooks.EventType{webhooks.EventTypeWorkspaceCreated})
+	require.NoError(t, err)
+
+	require.NoError(t, svc.WorkspaceCreated(ctx, &workspaces.Workspace{ID: "workspace-id", CodebaseID: codebaseID}))
+	deliveries, err := svc.ListDeliveries(ctx, webhook.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+
+	failed, err := svc.Deliver(ctx, deliveries[0].ID)
+	assert.Error(t, err)
+	assert.False(t, failed.IsDelivered())
+	assert.Equal(t, 1, failed.Attempts)
+	if assert.NotNil(t, failed.Error) {
+		assert.Contains(t, *failed.Error, "500")
+		// the response is not stored
+		assert.NotContains(t, *failed.Error, "oops")
+	}
+
+	require.NoError(t, svc.Delete(ctx, webhook))
+	_, err = svc.Deliver(ctx, failed.ID)
+	assert.ErrorIs(t, err, ErrWebhookDeleted)
+}
+
+func TestCreate_validation(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService()
+	events := []webhooks.EventType{webhooks.EventTypeChangeLanded}
+
+	_, err := svc.Create(ctx, "codebase-id", "user-id", "ftp://example.com", "secret", events)
+	assert.ErrorIs(t, err, ErrInvalidURL)
+
+	_, err = svc.Create(ctx, "codebase-id", "user-id", "https://example.com", "", events)
+	assert.ErrorIs(t, err, ErrNoSecret)
+
+	_, err = svc.Create(ctx, "codebase-id", "user-id", "https://example.com", "secret", nil)
+	assert.ErrorIs(t, err, ErrNoEvents)
+
+	_, err = svc.Create(ctx, "codebase-id", "user-id", "https://example.com", "secret", []webhooks.EventType{"everything"})
+	assert.ErrorIs(t, err, ErrInvalidEvent)
+}
+
+func TestCreate_private(t *testing.T) {
+	ctx := context.Background()
+	svc := New(zap.NewNop(), db_webhooks.NewMemory(), db_webhooks.NewDeliveryMemory(), queue.NewInMemory(zap.NewNop()), db_acl.NewInMemoryAclRepo(), db_user.NewMemory())
+	events := []webhooks.EventType{webhooks.EventTypeChangeLanded}
+
+	for _, u := range []string{
+		"http://127.0.0.1:8080/hook",
+		"http://localhost/hook",
+		"http://10.0.0.1/hook",
+		"http://192.168.1.1/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[::1]/hook",
+		"http://0.0.0.0/hook",
+	} {
+		_, err := svc.Create(ctx, "codebase-id", "user-id", u, "secret", events)
+		assert.ErrorIs(t, err, ErrPrivateURL, u)
+	}
+}
+
+func TestDeliver_private(t *testing.T) {
+	ctx := context.Background()
+	repo := db_webhooks.NewMemory()
+	svc := New(zap.NewNop(), repo, db_webhooks.NewDeliveryMemory(), queue.NewInMemory(zap.NewNop()), db_acl.NewInMemoryAclRepo(), db_user.NewMemory())
+
+	var called bool
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer stub.Close()
+
+	// a webhook to a host that resolved to a public address when it was created
+	codebaseID := codebases.ID("codebase-id")
+	webhook := &webhooks.Webhook{
+		ID:         "webhook-id",
+		CodebaseID: codebaseID,
+		URL:        stub.URL,
+		Secret:     "secret",
+		Events:     []string{string(webhooks.EventTypeWorkspaceCreated)},
+		CreatedAt:  time.Now(),
+	}
+	require.NoError(t, repo.Create(ctx, webhook))
+
+	require.NoError(t, svc.WorkspaceCreated(ctx, &workspaces.Workspace{ID: "workspace-id", CodebaseID: codebaseID}))
+	deliveries, err := svc.ListDeliveries(ctx, webhook.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+
+	failed, err := svc.Deliver(ctx, deliveries[0].ID)
+	assert.ErrorIs(t, err, ip.ErrNotAllowed)
+	assert.False(t, failed.IsDelivered())
+	assert.False(t, called)
+}
+
+func TestCommentCreated_acl(t *testing.T) {
+	ctx := context.Background()
+	aclRepo := db_acl.NewInMemoryAclRepo()
+	userRepo := db_user.NewMemory()
+	svc := newService(zap.NewNop(), db_webhooks.NewMemory(), db_webhooks.NewDeliveryMemory(), queue.NewInMemory(zap.NewNop()), aclRepo, userRepo, allowAll)
+
+	codebaseID := codebases.ID("codebase-id")
+	require.NoError(t, userRepo.Create(&users.User{ID: "user-id", Email: "user@getsturdy.com"}))
+	require.NoError(t, aclRepo.Create(ctx, acl.ACL{
+		ID:         "acl-id",
+		CodebaseID: codebaseID,
+		RawPolicy: `{
+			"rules": [
+				{
+					"id": "user can access public files",
+					"principals": ["users::user@getsturdy.com"],
+					"action": "write",
+					"resources": ["files::public/**"],
+				},
+			],
+		}`,
+	}))
+
+	webhook, err := svc.Create(ctx, codebaseID, "user-id", "https://example.com/hook", "secret", []webhooks.EventType{webhooks.EventTypeCommentCreated})
+	require.NoError(t, err)
+
+	require.NoError(t, svc.CommentCreated(ctx, &comments.Comment{ID: "public-comment", CodebaseID: codebaseID, Message: "public", Path: "public/a.txt", LineStart: 1, LineEnd: 1}))
+	require.NoError(t, svc.CommentCreated(ctx, &comments.Comment{ID: "secret-comment", CodebaseID: codebaseID, Message: "secret", Path: "secret/a.txt", LineStart: 1, LineEnd: 1}))
+
+	deliveries, err := svc.ListDeliveries(ctx, webhook.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+
+	received := map[comments.ID]*webhooks.Comment{}
+	for _, delivery := range deliveries {
+		var event webhooks.Event
+		require.NoError(t, json.Unmarshal(delivery.Payload, &event))
+		require.NotNil(t, event.Comment)
+		received[event.Comment.ID] = event.Comment
+	}
+
+	if assert.Contains(t, received, comments.ID("public-comment")) {
+		assert.Equal(t, "public", received["public-comment"].Message)
+		assert.Equal(t, "public/a.txt", received["public-comment"].Path)
+	}
+	if assert.Contains(t, received, comments.ID("secret-comment")) {
+		assert.Empty(t, received["secret-comment"].Message)
+		assert.Empty(t, received["secret-comment"].Path)
+		assert.Zero(t, received["secret-comment"].LineStart)
+	}
+	for _, delivery := range deliveries {
+		assert.NotContains(t, string(delivery.Payload), "secret/a.txt")
+	}
+}
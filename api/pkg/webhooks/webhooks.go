@@ -0,0 +1,101 @@
+package webhooks
+
+import (
+	"time"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/users"
+
+	"github.com/lib/pq"
+)
+
+type ID string
+
+func (id ID) String() string {
+	return string(id)
+}
+
+type EventType string
+
+const (
+	EventTypeUndefined        EventType = ""
+	EventTypeChangeLanded     EventType = "change_landed"
+	EventTypeWorkspaceCreated EventType = "workspace_created"
+	EventTypeReviewSubmitted  EventType = "review_submitted"
+	EventTypeCommentCreated   EventType = "comment_created"
+	EventTypeStatusUpdated    EventType = "status_updated"
+)
+
+func (t EventType) Valid() bool {
+	switch t {
+	case EventTypeChangeLanded,
+		EventTypeWorkspaceCreated,
+		EventTypeReviewSubmitted,
+		EventTypeCommentCreated,
+		EventTypeStatusUpdated:
+		return true
+	default:
+		return false
+	}
+}
+
+// Webhook is a subscription to events in a codebase. Events of the selected types are posted to the url,
+// signed with the secret.
+type Webhook struct {
+	ID         ID             `db:"id"`
+	CodebaseID codebases.ID   `db:"codebase_id"`
+	URL        string         `db:"url"`
+	Secret     string         `db:"secret"`
+	Events     pq.StringArray `db:"events"`
+	CreatedBy  users.ID       `db:"created_by"`
+	CreatedAt  time.Time      `db:"created_at"`
+	UpdatedAt  *time.Time     `db:"updated_at"`
+	DeletedAt  *time.Time     `db:"deleted_at"`
+}
+
+func (w *Webhook) EventTypes() []EventType {
+	res := make([]EventType, 0, len(w.Events))
+	for _, e := range w.Events {
+		res = append(res, EventType(e))
+	}
+	return res
+}
+
+// Subscribes returns true if the webhook should receive events of type t.
+func (w *Webhook) Subscribes(t EventType) bool {
+	for _, e := range w.Events {
+		if EventType(e) == t {
+			return true
+		}
+	}
+	return false
+}
+
+type DeliveryID string
+
+func (id DeliveryID) String() string {
+	return string(id)
+}
+
+// Delivery is a single event sent to a webhook. A delivery is attempted until it succeeds, or until it runs out
+// of attempts. Redelivering an event creates a new delivery with the same payload.
+type Delivery struct {
+	ID        DeliveryID `db:"id"`
+	WebhookID ID         `db:"webhook_id"`
+	// EventID is the same for all deliveries of the same event, and can be used by receivers to deduplicate them.
+	EventID   string    `db:"event_id"`
+	EventType EventType `db:"event_type"`
+	Payload   []byte    `db:"payload"`
+	Attempts  int       `db:"attempts"`
+	// StatusCode is the http status code of the latest attempt, if a response was received.
+	StatusCode *int `db:"status_code"`
+	// Error describes why the latest attempt failed.
+	Error         *string    `db:"error"`
+	CreatedAt     time.Time  `db:"created_at"`
+	LastAttemptAt *time.Time `db:"last_attempt_at"`
+	DeliveredAt   *time.Time `db:"delivered_at"`
+}
+
+func (d *Delivery) IsDelivered() bool {
+	return d.DeliveredAt != nil
+}
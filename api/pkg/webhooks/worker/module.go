@@ -0,0 +1,15 @@
+package worker
+
+import (
+	"getsturdy.com/api/pkg/di"
+	"getsturdy.com/api/pkg/logger"
+	queue "getsturdy.com/api/pkg/queue/module"
+	service_webhooks "getsturdy.com/api/pkg/webhooks/service"
+)
+
+func Module(c *di.Container) {
+	c.Import(logger.Module)
+	c.Import(queue.Module)
+	c.Import(service_webhooks.Module)
+	c.Register(New)
+}
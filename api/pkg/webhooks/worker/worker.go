@@ -0,0 +1,101 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"getsturdy.com/api/pkg/queue"
+	"getsturdy.com/api/pkg/queue/names"
+	"getsturdy.com/api/pkg/queue/retry"
+	"getsturdy.com/api/pkg/webhooks"
+	"getsturdy.com/api/pkg/webhooks/service"
+)
+
+type Queue struct {
+	logger *zap.Logger
+	queue  queue.Queue
+	name   names.IncompleteQueueName
+
+	service *service.Service
+	backoff retry.Backoff
+}
+
+func New(
+	logger *zap.Logger,
+	queue queue.Queue,
+	service *service.Service,
+) *Queue {
+	return &Queue{
+		logger:  logger.Named("webhooksQueue"),
+		queue:   queue,
+		name:    names.CodebaseWebhooks,
+		service: service,
+		backoff: retry.Default,
+	}
+}
+
+func (q *Queue) Start(ctx context.Context) error {
+	messages := make(chan queue.Message)
+	go func() {
+		defer func() {
+			if rec := recover(); rec != nil {
+				q.logger.Error("panic in runner", zap.String("panic", fmt.Sprintf("%v", rec)))
+			}
+		}()
+
+		for msg := range messages {
+			m := &service.DeliveryMessage{}
+			if err := msg.As(m); err != nil {
+				q.logger.Error("failed to decode message", zap.Error(err))
+				continue
+			}
+
+			q.deliver(ctx, m.DeliveryID)
+
+			if err := msg.Ack(); err != nil {
+				q.logger.Error("failed to ack message", zap.Error(err), zap.Stringer("delivery_id", m.DeliveryID))
+				continue
+			}
+		}
+	}()
+
+	q.logger.Info("starting queue", zap.Stringer("queue_name", q.name))
+	if err := q.queue.Subscribe(ctx, q.name, messages); err != nil {
+		return fmt.Errorf("could not subscribe to queue: %w", err)
+	}
+	q.logger.Info("queue stoped", zap.Stringer("queue_name", q.name))
+
+	return nil
+}
+
+// deliver attempts the delivery, and schedules a retry on the queue if it failed and has attempts left.
+func (q *Queue) deliver(ctx context.Context, id webhooks.DeliveryID) {
+	logger := q.logger.With(zap.Stringer("delivery_id", id))
+
+	delivery, err := q.service.Deliver(context.Background(), id)
+	switch {
+	case err == nil:
+		logger.Info("delivered")
+		return
+	case errors.Is(err, service.ErrAlreadyDelivered), errors.Is(err, service.ErrWebhookDeleted):
+		logger.Info("skipping delivery", zap.Error(err))
+		return
+	case delivery == nil:
+		logger.Error("failed to deliver", zap.Error(err))
+		return
+	}
+
+	wait, ok := q.backoff.Next(delivery.Attempts)
+	if !ok {
+		logger.Warn("failed to deliver, giving up", zap.Error(err), zap.Int("attempts", delivery.Attempts))
+		return
+	}
+	logger.Info("failed to deliver, retrying", zap.Error(err), zap.Int("attempts", delivery.Attempts), zap.Duration("backoff", wait))
+
+	if err := q.service.EnqueueRetry(ctx, id, wait); err != nil {
+		logger.Error("failed to enqueue retry", zap.Error(err))
+	}
+}
@@ -24,9 +24,9 @@ func NewRepo(db *sqlx.DB) Repository {
 
 func (r *repo) Create(entity workspaces.Workspace) error {
 	_, err := r.db.NamedExec(`INSERT INTO workspaces
-		(id, user_id, codebase_id, name, created_at, view_id, latest_snapshot_id, draft_description, diffs_count)
+		(id, user_id, codebase_id, name, created_at, view_id, latest_snapshot_id, draft_description, diffs_count, base_workspace_id)
 		VALUES
-		(:id, :user_id, :codebase_id, :name, :created_at, :view_id, :latest_snapshot_id, :draft_description, :diffs_count)`, &entity)
+		(:id, :user_id, :codebase_id, :name, :created_at, :view_id, :latest_snapshot_id, :draft_description, :diffs_count, :base_workspace_id)`, &entity)
 	if err != nil {
 		return fmt.Errorf("failed to insert workspace: %w", err)
 	}
@@ -35,7 +35,7 @@ func (r *repo) Create(entity workspaces.Workspace) error {
 
 func (r *repo) Get(id string) (*workspaces.Workspace, error) {
 	var entity workspaces.Workspace
-	err := r.db.Get(&entity, `SELECT id, user_id, codebase_id, name,  created_at, last_landed_at, archived_at, unarchived_at, updated_at, draft_description, view_id, latest_snapshot_id, up_to_date_with_trunk, head_change_id, head_change_computed, diffs_count, change_id
+	err := r.db.Get(&entity, `SELECT id, user_id, codebase_id, name,  created_at, last_landed_at, archived_at, unarchived_at, updated_at, draft_description, view_id, latest_snapshot_id, up_to_date_with_trunk, head_change_id, head_change_computed, diffs_count, change_id, base_workspace_id
 	FROM workspaces
 	WHERE id=$1`, id)
 	if err != nil {
@@ -45,7 +45,7 @@ func (r *repo) Get(id string) (*workspaces.Workspace, error) {
 }
 
 func (r *repo) ListByCodebaseIDs(codebaseIDs []codebases.ID, includeArchived bool) ([]*workspaces.Workspace, error) {
-	q := `SELECT id, user_id, codebase_id, name, created_at, last_landed_at, archived_at, unarchived_at, updated_at, draft_description, view_id, latest_snapshot_id, up_to_date_with_trunk, head_change_id, head_change_computed, diffs_count, change_id
+	q := `SELECT id, user_id, codebase_id, name, created_at, last_landed_at, archived_at, unarchived_at, updated_at, draft_description, view_id, latest_snapshot_id, up_to_date_with_trunk, head_change_id, head_change_computed, diffs_count, change_id, base_workspace_id
 	FROM workspaces
 	WHERE codebase_id IN(?)`
 
@@ -68,7 +68,7 @@ func (r *repo) ListByCodebaseIDs(codebaseIDs []codebases.ID, includeArchived boo
 }
 
 func (r *repo) ListByCodebaseIDsAndUserID(codebaseIDs []codebases.ID, userID string) ([]*workspaces.Workspace, error) {
-	query, args, err := sqlx.In(`SELECT id, user_id, codebase_id, name, created_at, last_landed_at, archived_at, unarchived_at, updated_at, draft_description, view_id, latest_snapshot_id, up_to_date_with_trunk, head_change_id, diffs_count, change_id
+	query, args, err := sqlx.In(`SELECT id, user_id, codebase_id, name, created_at, last_landed_at, archived_at, unarchived_at, updated_at, draft_description, view_id, latest_snapshot_id, up_to_date_with_trunk, head_change_id, diffs_count, change_id, base_workspace_id
 	FROM workspaces
 	WHERE codebase_id IN(?)
 	  AND user_id = ?
@@ -99,7 +99,7 @@ func (r *repo) UnsetUpToDateWithTrunkForAllInCodebase(codebaseID codebases.ID) e
 func (r *repo) GetByViewID(viewID string, includeArchived bool) (*workspaces.Workspace, error) {
 	var entity workspaces.Workspace
 
-	q := `SELECT id, user_id, codebase_id, name, created_at, last_landed_at, archived_at, unarchived_at, updated_at, draft_description, view_id, latest_snapshot_id, up_to_date_with_trunk, head_change_id, head_change_computed, diffs_count, change_id
+	q := `SELECT id, user_id, codebase_id, name, created_at, last_landed_at, archived_at, unarchived_at, updated_at, draft_description, view_id, latest_snapshot_id, up_to_date_with_trunk, head_change_id, head_change_computed, diffs_count, change_id, base_workspace_id
 		FROM workspaces
 		WHERE view_id=$1`
 
@@ -132,7 +132,8 @@ func (r *repo) ListByUserID(ctx context.Context, userID users.ID) ([]*workspaces
 		head_change_id, 
 		head_change_computed, 
 		diffs_count, 
-		change_id
+		change_id,
+		base_workspace_id
 	FROM workspaces
 	WHERE user_id=$1
 	AND archived_at IS NULL`, userID); err != nil {
@@ -161,7 +162,8 @@ func (r *repo) GetBySnapshotID(snapshotID snapshots.ID) (*workspaces.Workspace,
 			head_change_id,
 			head_change_computed,
 			diffs_count,
-			change_id
+			change_id,
+			base_workspace_id
 		FROM 
 			workspaces
 		WHERE
@@ -253,6 +255,9 @@ func (r *repo) UpdateFields(ctx context.Context, workspaceID string, fields ...U
 	if opts.userIDSet {
 		query.Set("user_id", opts.userID)
 	}
+	if opts.baseWorkspaceIDSet {
+		query.Set("base_workspace_id", opts.baseWorkspaceID)
+	}
 
 	if _, err := r.db.NamedExecContext(ctx, query.String(workspaceID), query.args); err != nil {
 		return fmt.Errorf("failed to update workspace: %w", err)
@@ -279,10 +284,40 @@ func (r *repo) ListByIDs(ctx context.Context, ids ...string) ([]*workspaces.Work
 		head_change_id, 
 		head_change_computed, 
 		diffs_count, 
-		change_id
+		change_id,
+		base_workspace_id
 	FROM workspaces
 	WHERE id = ANY($1)`, pq.Array(ids)); err != nil {
 		return nil, fmt.Errorf("failed to ListByIDs: %w", err)
 	}
 	return entities, nil
 }
+
+func (r *repo) ListByBaseWorkspaceID(ctx context.Context, baseWorkspaceID string) ([]*workspaces.Workspace, error) {
+	var entities []*workspaces.Workspace
+	if err := r.db.SelectContext(ctx, &entities, `SELECT 
+		id,
+		user_id, 
+		codebase_id, 
+		name, 
+		created_at, 
+		last_landed_at, 
+		archived_at, 
+		unarchived_at, 
+		updated_at, 
+		draft_description, 
+		view_id, 
+		latest_snapshot_id, 
+		up_to_date_with_trunk, 
+		head_change_id, 
+		head_change_computed, 
+		diffs_count, 
+		change_id,
+		base_workspace_id
+	FROM workspaces
+	WHERE base_workspace_id = $1
+	AND archived_at IS NULL`, baseWorkspaceID); err != nil {
+		return nil, fmt.Errorf("failed to ListByBaseWorkspaceID: %w", err)
+	}
+	return entities, nil
+}
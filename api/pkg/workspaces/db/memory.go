@@ -96,6 +96,9 @@ func (f *memory) UpdateFields(_ context.Context, workspaceID string, fields ...U
 		if opts.userIDSet {
 			ws.UserID = opts.userID
 		}
+		if opts.baseWorkspaceIDSet {
+			ws.BaseWorkspaceID = opts.baseWorkspaceID
+		}
 		return nil
 	}
 	return sql.ErrNoRows
@@ -136,3 +139,13 @@ func (f *memory) ListByIDs(_ context.Context, ids ...string) ([]*workspaces.Work
 	}
 	return ww, nil
 }
+
+func (f *memory) ListByBaseWorkspaceID(_ context.Context, baseWorkspaceID string) ([]*workspaces.Workspace, error) {
+	ww := []*workspaces.Workspace{}
+	for _, workspace := range f.workspaces {
+		if workspace.BaseWorkspaceID != nil && *workspace.BaseWorkspaceID == baseWorkspaceID && workspace.ArchivedAt == nil {
+			ww = append(ww, workspace)
+		}
+	}
+	return ww, nil
+}
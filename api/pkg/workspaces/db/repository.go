@@ -31,6 +31,7 @@ type WorkspaceReader interface {
 	ListByUserID(context.Context, users.ID) ([]*workspaces.Workspace, error)
 	GetByViewID(viewID string, includeArchived bool) (*workspaces.Workspace, error)
 	GetBySnapshotID(snapshots.ID) (*workspaces.Workspace, error)
+	ListByBaseWorkspaceID(ctx context.Context, baseWorkspaceID string) ([]*workspaces.Workspace, error)
 }
 
 type UpdateOptions struct {
@@ -75,6 +76,9 @@ type UpdateOptions struct {
 
 	userID    users.ID
 	userIDSet bool
+
+	baseWorkspaceID    *string
+	baseWorkspaceIDSet bool
 }
 
 type UpdateOption func(*UpdateOptions)
@@ -186,3 +190,10 @@ func SetUserID(userID users.ID) UpdateOption {
 		opts.userIDSet = true
 	}
 }
+
+func SetBaseWorkspaceID(baseWorkspaceID *string) UpdateOption {
+	return func(opts *UpdateOptions) {
+		opts.baseWorkspaceID = baseWorkspaceID
+		opts.baseWorkspaceIDSet = true
+	}
+}
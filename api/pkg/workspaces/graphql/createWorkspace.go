@@ -2,6 +2,7 @@ package graphql
 
 import (
 	"context"
+	"errors"
 
 	"getsturdy.com/api/pkg/auth"
 	"getsturdy.com/api/pkg/changes"
@@ -38,11 +39,30 @@ func (r *WorkspaceRootResolver) CreateWorkspace(ctx context.Context, args resolv
 		)
 	}
 
+	if args.Input.OnTopOfWorkspace != nil && (args.Input.OnTopOfChange != nil || args.Input.OnTopOfChangeWithRevert != nil) {
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest,
+			"onTopOfWorkspace", "can't be set together with onTopOfChange or onTopOfChangeWithRevert",
+		)
+	}
+
 	// Create request to pass to the old REST API route handler
 	req := service.CreateWorkspaceRequest{
 		CodebaseID: codebaseID,
 		UserID:     userID,
 	}
+
+	if args.Input.OnTopOfWorkspace != nil {
+		base, err := r.workspaceService.GetByID(ctx, string(*args.Input.OnTopOfWorkspace))
+		if err != nil {
+			return nil, gqlerrors.Error(err)
+		}
+		if err := r.authService.CanRead(ctx, base); err != nil {
+			return nil, gqlerrors.Error(err)
+		}
+		req.BaseWorkspaceID = &base.ID
+		req.Name = "On " + base.NameOrFallback()
+	}
+
 	if args.Input.OnTopOfChange != nil || args.Input.OnTopOfChangeWithRevert != nil {
 		var id *graphql.ID
 		if args.Input.OnTopOfChange != nil {
@@ -69,7 +89,14 @@ func (r *WorkspaceRootResolver) CreateWorkspace(ctx context.Context, args resolv
 	}
 
 	ws, err := r.workspaceService.Create(ctx, req)
-	if err != nil {
+	switch {
+	case errors.Is(err, service.ErrBaseWorkspaceArchived):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "onTopOfWorkspace", "workspace is archived")
+	case errors.Is(err, service.ErrBaseWorkspaceNoSnapshot):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "onTopOfWorkspace", "workspace has no changes")
+	case errors.Is(err, service.ErrBaseWorkspaceOtherCodebase):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "onTopOfWorkspace", "workspace belongs to another codebase")
+	case err != nil:
 		return nil, gqlerrors.Error(err)
 	}
 
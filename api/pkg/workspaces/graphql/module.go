@@ -11,6 +11,7 @@ import (
 	"getsturdy.com/api/pkg/di"
 	graphql_github_pr "getsturdy.com/api/pkg/github/graphql/pr"
 	"getsturdy.com/api/pkg/graphql/resolvers"
+	graphql_mergequeue "getsturdy.com/api/pkg/mergequeue/graphql"
 	graphql_presence "getsturdy.com/api/pkg/presence/graphql"
 	graphql_review "getsturdy.com/api/pkg/review/graphql"
 	db_snapshots "getsturdy.com/api/pkg/snapshots/db"
@@ -42,6 +43,7 @@ func Module(c *di.Container) {
 	c.Import(graphql_workspace_watchers.Module)
 	c.Import(graphql_rebase.Module)
 	c.Import(graphql_snapshots.Module)
+	c.Import(graphql_mergequeue.Module)
 
 	c.Register(NewResolver)
 
@@ -13,6 +13,7 @@ import (
 	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
 	"getsturdy.com/api/pkg/graphql/resolvers"
 	"getsturdy.com/api/pkg/snapshots"
+	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
 	"getsturdy.com/api/pkg/unidiff"
 	"getsturdy.com/api/pkg/workspaces"
 	"getsturdy.com/api/pkg/workspaces/db"
@@ -398,3 +399,60 @@ func (r *WorkspaceResolver) Snapshot(ctx context.Context) (resolvers.SnapshotRes
 	}
 	return sr, nil
 }
+
+func (r *WorkspaceResolver) Snapshots(ctx context.Context, args resolvers.WorkspaceSnapshotsArgs) ([]resolvers.SnapshotResolver, error) {
+	const defaultLimit int = 100
+	var (
+		limit  = defaultLimit
+		before *snapshots.ID
+	)
+	if args.Input != nil {
+		if args.Input.Limit != nil {
+			if *args.Input.Limit < 1 || *args.Input.Limit > int32(defaultLimit) {
+				return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "limit", fmt.Sprintf("must be between 1 and %d", defaultLimit))
+			}
+			limit = int(*args.Input.Limit)
+		}
+		if args.Input.Before != nil {
+			id := snapshots.ID(*args.Input.Before)
+			before = &id
+		}
+	}
+
+	history, err := r.root.gitSnapshotter.History(ctx, r.w.ID, before, limit)
+	switch {
+	case errors.Is(err, service_snapshots.ErrWrongWorkspace):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "before", err.Error())
+	case err != nil:
+		return nil, gqlerrors.Error(err)
+	}
+
+	return r.root.snapshotsResolver.InternalSnapshots(history), nil
+}
+
+func (r *WorkspaceResolver) MergeQueueEntry(ctx context.Context) (resolvers.MergeQueueEntryResolver, error) {
+	return r.root.mergeQueueResolver.InternalMergeQueueEntryByWorkspaceID(ctx, r.w.ID)
+}
+
+func (r *WorkspaceResolver) BaseWorkspace(ctx context.Context) (resolvers.WorkspaceResolver, error) {
+	base, err := r.root.workspaceService.BaseWorkspace(ctx, r.w)
+	switch {
+	case errors.Is(err, service_workspace.ErrNotFound), errors.Is(err, sql.ErrNoRows):
+		return nil, nil
+	case err != nil:
+		return nil, gqlerrors.Error(err)
+	}
+	return &WorkspaceResolver{w: base, root: r.root}, nil
+}
+
+func (r *WorkspaceResolver) StackedWorkspaces(ctx context.Context) ([]resolvers.WorkspaceResolver, error) {
+	stacked, err := r.root.workspaceService.ListStackedOn(ctx, r.w)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+	res := make([]resolvers.WorkspaceResolver, 0, len(stacked))
+	for _, ws := range stacked {
+		res = append(res, &WorkspaceResolver{w: ws, root: r.root})
+	}
+	return res, nil
+}
@@ -2,8 +2,10 @@ package graphql
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"getsturdy.com/api/pkg/auth"
 	service_auth "getsturdy.com/api/pkg/auth/service"
 	service_change "getsturdy.com/api/pkg/changes/service"
 	"getsturdy.com/api/pkg/codebases"
@@ -50,6 +52,7 @@ type WorkspaceRootResolver struct {
 	rebaseStatusRootResolver      resolvers.RebaseStatusRootResolver
 	downloadsResolver             resolvers.ContentsDownloadUrlRootResolver
 	snapshotsResolver             resolvers.SnapshotsRootResolver
+	mergeQueueResolver            resolvers.MergeQueueRootResolver
 
 	suggestionsService *service_suggestions.Service
 	workspaceService   *service_workspace.Service
@@ -89,6 +92,7 @@ func NewResolver(
 	rebaseStatusRootResolver resolvers.RebaseStatusRootResolver,
 	downloadsResolver resolvers.ContentsDownloadUrlRootResolver,
 	snapshotsResolver resolvers.SnapshotsRootResolver,
+	mergeQueueResolver resolvers.MergeQueueRootResolver,
 
 	suggestionsService *service_suggestions.Service,
 	workspaceService *service_workspace.Service,
@@ -127,6 +131,7 @@ func NewResolver(
 		rebaseStatusRootResolver:      rebaseStatusRootResolver,
 		downloadsResolver:             downloadsResolver,
 		snapshotsResolver:             snapshotsResolver,
+		mergeQueueResolver:            mergeQueueResolver,
 
 		suggestionsService: suggestionsService,
 		workspaceService:   workspaceService,
@@ -249,3 +254,36 @@ func (r *WorkspaceRootResolver) SetWorkspaceSnapshot(ctx context.Context, args r
 
 	return &WorkspaceResolver{w: ws, root: r}, nil
 }
+
+func (r *WorkspaceRootResolver) RestoreWorkspaceSnapshot(ctx context.Context, args resolvers.RestoreWorkspaceSnapshotArgs) (resolvers.WorkspaceResolver, error) {
+	userID, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	ws, err := r.workspaceService.GetByID(ctx, string(args.Input.WorkspaceID))
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+	if err := r.authService.CanWrite(ctx, ws); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	snap, err := r.snapshotsRepo.Get(snapshots.ID(args.Input.SnapshotID))
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	user, err := r.userService.GetByID(ctx, userID)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	if _, err := r.workspaceService.RestoreSnapshot(ctx, ws, snap, user); errors.Is(err, service_snapshots.ErrWrongWorkspace) {
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "snapshotID", err.Error())
+	} else if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	return &WorkspaceResolver{w: ws, root: r}, nil
+}
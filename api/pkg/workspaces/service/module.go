@@ -12,6 +12,7 @@ import (
 	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
 	service_users "getsturdy.com/api/pkg/users/service/module"
 	service_view "getsturdy.com/api/pkg/views/service"
+	service_webhooks "getsturdy.com/api/pkg/webhooks/service"
 	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
 	"getsturdy.com/api/vcs/executor"
 )
@@ -29,5 +30,6 @@ func Module(c *di.Container) {
 	c.Import(events.Module)
 	c.Import(eventsv2.Module)
 	c.Import(service_snapshots.Module)
+	c.Import(service_webhooks.Module)
 	c.Register(New)
 }
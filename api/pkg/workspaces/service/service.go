@@ -25,6 +25,7 @@ import (
 	service_users "getsturdy.com/api/pkg/users/service"
 	service_view "getsturdy.com/api/pkg/views/service"
 	vcs_view "getsturdy.com/api/pkg/views/vcs"
+	service_webhooks "getsturdy.com/api/pkg/webhooks/service"
 	"getsturdy.com/api/pkg/workspaces"
 	"getsturdy.com/api/pkg/workspaces/db"
 	vcs_workspace "getsturdy.com/api/pkg/workspaces/vcs"
@@ -43,6 +44,10 @@ type CreateWorkspaceRequest struct {
 
 	BaseChangeID *changes.ID
 	Revert       bool
+
+	// BaseWorkspaceID is set to create a workspace stacked on top of another workspace.
+	// Mutually exclusive with BaseChangeID.
+	BaseWorkspaceID *string
 }
 
 type Service struct {
@@ -56,6 +61,7 @@ type Service struct {
 	viewService     *service_view.Service
 	usersService    service_users.Service
 	statusesService *service_statuses.Service
+	webhooksService *service_webhooks.Service
 
 	eventsSender     events.EventSender
 	eventsSenderV2   *eventsv2.Publisher
@@ -74,6 +80,7 @@ func New(
 	viewService *service_view.Service,
 	usersService service_users.Service,
 	statusesService *service_statuses.Service,
+	webhooksService *service_webhooks.Service,
 
 	executorProvider executor.Provider,
 	eventsSender events.EventSender,
@@ -91,6 +98,7 @@ func New(
 		viewService:     viewService,
 		usersService:    usersService,
 		statusesService: statusesService,
+		webhooksService: webhooksService,
 
 		executorProvider: executorProvider,
 		eventsSender:     eventsSender,
@@ -232,6 +240,67 @@ func (s *Service) SetSnapshot(ctx context.Context, ws *workspaces.Workspace, sna
 	return nil
 }
 
+// RestoreSnapshot restores the workspace to the contents of one of its snapshots. Unlike SetSnapshot, the restored
+// contents are snapshotted again, so the restore becomes the latest entry in the history of the workspace, and can be
+// undone by restoring the snapshot that was the latest before it.
+func (s *Service) RestoreSnapshot(ctx context.Context, ws *workspaces.Workspace, snap *snapshots.Snapshot, user *users.User) (*snapshots.Snapshot, error) {
+	if snap.WorkspaceID != ws.ID {
+		return nil, service_snapshots.ErrWrongWorkspace
+	}
+
+	var restored *snapshots.Snapshot
+	snapshotRestored := func(repo vcs.RepoWriter) error {
+		var err error
+		restored, err = s.snap.Snapshot(
+			ctx,
+			ws.CodebaseID,
+			ws.ID,
+			snapshots.ActionRestore,
+			service_snapshots.WithOnView(*repo.ViewID()),
+			service_snapshots.WithMarkAsLatestInWorkspace(),
+			service_snapshots.WithOnRepo(repo),
+			service_snapshots.WithNoThrottle(),
+			service_snapshots.WithUser(user),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to snapshot: %w", err)
+		}
+		return nil
+	}
+
+	if ws.ViewID != nil {
+		if err := s.executorProvider.New().
+			Write(vcs_snapshots.Restore(s.logger, snap)).
+			Write(snapshotRestored).
+			ExecView(ws.CodebaseID, *ws.ViewID, "restoreSnapshot"); err != nil {
+			return nil, fmt.Errorf("failed to restore view: %w", err)
+		}
+	} else {
+		if err := s.executorProvider.New().
+			Write(vcs_view.CheckoutSnapshot(snap)).
+			Write(snapshotRestored).
+			ExecTemporaryView(ws.CodebaseID, "restoreSnapshot"); err != nil {
+			return nil, fmt.Errorf("failed to restore snapshot: %w", err)
+		}
+	}
+
+	// the restored snapshot can be based on another change than the workspace was
+	if err := s.workspaceWriter.UpdateFields(ctx, ws.ID, db.SetHeadChangeComputed(false)); err != nil {
+		return nil, fmt.Errorf("failed to update workspace: %w", err)
+	}
+	ws.SetSnapshot(restored)
+	ws.HeadChangeComputed = false
+
+	s.analyticsService.Capture(ctx, "restore-snapshot",
+		analytics.Property("workspace_id", ws.ID),
+		analytics.Property("snapshot_id", snap.ID),
+		analytics.Property("restored_snapshot_id", restored.ID),
+		analytics.Property("codebase_id", ws.CodebaseID),
+	)
+
+	return restored, nil
+}
+
 type CopyPatchesOptions struct {
 	PatchIDs *[]string
 }
@@ -352,6 +421,20 @@ func (s *Service) Create(ctx context.Context, req CreateWorkspaceRequest) (*work
 		ws.Name = &n
 	}
 
+	if req.BaseChangeID != nil && req.BaseWorkspaceID != nil {
+		return nil, fmt.Errorf("base change and base workspace are mutually exclusive")
+	}
+
+	var baseSnapshot *snapshots.Snapshot
+	if req.BaseWorkspaceID != nil {
+		var err error
+		baseSnapshot, err = s.baseSnapshot(ctx, req.CodebaseID, *req.BaseWorkspaceID)
+		if err != nil {
+			return nil, err
+		}
+		ws.BaseWorkspaceID = req.BaseWorkspaceID
+	}
+
 	var baseCommitSha string
 	var baseCommitParentSha *string
 	if req.BaseChangeID != nil {
@@ -393,6 +476,11 @@ func (s *Service) Create(ctx context.Context, req CreateWorkspaceRequest) (*work
 			if err := vcs_workspace.CreateOnCommitID(repo, ws.ID, baseCommitSha); err != nil {
 				return fmt.Errorf("failed to create workspace at change: %w", err)
 			}
+		} else if baseSnapshot != nil {
+			// Create workspace on top of the latest snapshot of the base workspace
+			if err := vcs_workspace.CreateOnCommitID(repo, ws.ID, baseSnapshot.CommitSHA); err != nil {
+				return fmt.Errorf("failed to create workspace at base workspace: %w", err)
+			}
 		} else {
 			// Create workspace at current trunk
 			if err := vcs_workspace.Create(repo, ws.ID); err != nil {
@@ -427,13 +515,91 @@ func (s *Service) Create(ctx context.Context, req CreateWorkspaceRequest) (*work
 		analytics.CodebaseID(req.CodebaseID),
 		analytics.Property("id", ws.ID),
 		analytics.Property("at_existing_change", req.BaseChangeID != nil),
+		analytics.Property("stacked", req.BaseWorkspaceID != nil),
 		analytics.Property("name", ws.Name),
 	)
 
+	if err := s.webhooksService.WorkspaceCreated(ctx, &ws); err != nil {
+		s.logger.Error("failed to send workspace created webhook", zap.Error(err))
+	}
+
 	return &ws, nil
 }
 
-var ErrNotFound = errors.New("not found")
+var (
+	ErrNotFound                   = errors.New("not found")
+	ErrBaseWorkspaceNotFound      = errors.New("base workspace not found")
+	ErrBaseWorkspaceArchived      = errors.New("base workspace is archived")
+	ErrBaseWorkspaceNoSnapshot    = errors.New("base workspace has no snapshot")
+	ErrBaseWorkspaceOtherCodebase = errors.New("base workspace belongs to another codebase")
+)
+
+// baseSnapshot returns the snapshot that a workspace stacked on top of baseWorkspaceID should be based on.
+// If the base workspace has a view, it's snapshotted first, to make sure that the latest changes are included.
+func (s *Service) baseSnapshot(ctx context.Context, codebaseID codebases.ID, baseWorkspaceID string) (*snapshots.Snapshot, error) {
+	base, err := s.workspaceReader.Get(baseWorkspaceID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, ErrBaseWorkspaceNotFound
+	case err != nil:
+		return nil, fmt.Errorf("failed to get base workspace: %w", err)
+	}
+
+	if base.CodebaseID != codebaseID {
+		return nil, ErrBaseWorkspaceOtherCodebase
+	}
+
+	if base.IsArchived() {
+		return nil, ErrBaseWorkspaceArchived
+	}
+
+	if base.ViewID != nil {
+		snapshot, err := s.snap.Snapshot(ctx, base.CodebaseID, base.ID, snapshots.ActionViewSync,
+			service_snapshots.WithOnView(*base.ViewID),
+			service_snapshots.WithMarkAsLatestInWorkspace(),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to snapshot base workspace: %w", err)
+		}
+		return snapshot, nil
+	}
+
+	if base.LatestSnapshotID == nil {
+		return nil, ErrBaseWorkspaceNoSnapshot
+	}
+
+	snapshot, err := s.snap.GetByID(ctx, *base.LatestSnapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get base workspace snapshot: %w", err)
+	}
+	return snapshot, nil
+}
+
+// BaseWorkspace returns the workspace that ws is stacked on top of, or ErrNotFound if ws is not stacked.
+func (s *Service) BaseWorkspace(ctx context.Context, ws *workspaces.Workspace) (*workspaces.Workspace, error) {
+	if ws.BaseWorkspaceID == nil {
+		return nil, ErrNotFound
+	}
+	return s.workspaceReader.Get(*ws.BaseWorkspaceID)
+}
+
+// ListStackedOn returns all non-archived workspaces that are stacked on top of ws.
+func (s *Service) ListStackedOn(ctx context.Context, ws *workspaces.Workspace) ([]*workspaces.Workspace, error) {
+	return s.workspaceReader.ListByBaseWorkspaceID(ctx, ws.ID)
+}
+
+// Unstack detaches ws from its base workspace. After this, the workspace is considered to be based on the trunk, and
+// will be synced on top of the trunk.
+func (s *Service) Unstack(ctx context.Context, ws *workspaces.Workspace) error {
+	if ws.BaseWorkspaceID == nil {
+		return nil // noop
+	}
+	if err := s.workspaceWriter.UpdateFields(ctx, ws.ID, db.SetBaseWorkspaceID(nil)); err != nil {
+		return fmt.Errorf("failed to unstack workspace: %w", err)
+	}
+	ws.BaseWorkspaceID = nil
+	return nil
+}
 
 func (s *Service) HeadChange(ctx context.Context, ws *workspaces.Workspace) (*changes.Change, error) {
 	if ws.HeadChangeComputed {
@@ -645,11 +811,32 @@ func (s *Service) HasConflicts(ctx context.Context, ws *workspaces.Workspace) (b
 		return false, nil
 	}
 
-	snapshotBranchName := fmt.Sprintf("snapshot-%s", *ws.LatestSnapshotID)
+	snapshot, err := s.snap.GetByID(ctx, *ws.LatestSnapshotID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get snapshot: %w", err)
+	}
+	snapshotBranchName := snapshot.BranchName()
+
+	// Stacked workspaces are checked for conflicts against their base workspace
+	ontoBranchName := "sturdytrunk"
+	if ws.BaseWorkspaceID != nil {
+		base, err := s.workspaceReader.Get(*ws.BaseWorkspaceID)
+		if err != nil {
+			return false, fmt.Errorf("failed to get base workspace: %w", err)
+		}
+		if base.LatestSnapshotID == nil {
+			return false, nil
+		}
+		baseSnapshot, err := s.snap.GetByID(ctx, *base.LatestSnapshotID)
+		if err != nil {
+			return false, fmt.Errorf("failed to get base workspace snapshot: %w", err)
+		}
+		ontoBranchName = baseSnapshot.BranchName()
+	}
 
 	var hasConflicts bool
 	checkConflicts := func(repo vcs.RepoGitWriter) error {
-		idx, err := repo.MergeBranches(snapshotBranchName, "sturdytrunk")
+		idx, err := repo.MergeBranches(snapshotBranchName, ontoBranchName)
 		if err != nil {
 			return fmt.Errorf("failed to merge branches: %w", err)
 		}
@@ -665,7 +852,7 @@ func (s *Service) HasConflicts(ctx context.Context, ws *workspaces.Workspace) (b
 			return nil
 		}
 
-		if err := repo.FetchBranch(snapshotBranchName, "sturdytrunk"); err != nil {
+		if err := repo.FetchBranch(snapshotBranchName, ontoBranchName); err != nil {
 			return fmt.Errorf("failed to fetch branch: %w", err)
 		}
 
@@ -23,6 +23,7 @@ import (
 	"getsturdy.com/api/pkg/users"
 	db_view "getsturdy.com/api/pkg/views/db"
 	service_view "getsturdy.com/api/pkg/views/service"
+	db_webhooks "getsturdy.com/api/pkg/webhooks/db"
 	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
 	service_workspace "getsturdy.com/api/pkg/workspaces/service"
 	"getsturdy.com/api/vcs"
@@ -48,6 +49,7 @@ func testModule(t *testing.T) di.Module {
 		c.ImportWithForce(db_codebases.TestModule)
 		c.ImportWithForce(db_installations.TestModule)
 		c.ImportWithForce(db_statuses.TestModule)
+		c.ImportWithForce(db_webhooks.TestModule)
 		c.ImportWithForce(module_queue.TestModule(t))
 		c.ImportWithForce(configuration.TestModule)
 		c.RegisterWithForce(logger.NewTest)
@@ -197,3 +199,62 @@ func writeFile(filename string, content []byte) func(vcs.RepoWriter) error {
 		return nil
 	}
 }
+
+func TestCreate_stacked(t *testing.T) {
+	tc := setup(t)
+
+	ctx := context.Background()
+
+	base, err := tc.workspaceService.Create(ctx, service_workspace.CreateWorkspaceRequest{UserID: tc.userID, CodebaseID: tc.codebaseID})
+	assert.NoError(t, err)
+
+	baseView, err := tc.viewService.Create(ctx, tc.userID, base, nil, nil)
+	assert.NoError(t, err)
+
+	assert.NoError(t, tc.executorProvider.New().Write(writeFile("base.txt", []byte("base"))).ExecView(tc.codebaseID, baseView.ID, "make some changes"))
+
+	stacked, err := tc.workspaceService.Create(ctx, service_workspace.CreateWorkspaceRequest{
+		UserID:          tc.userID,
+		CodebaseID:      tc.codebaseID,
+		BaseWorkspaceID: &base.ID,
+	})
+	assert.NoError(t, err)
+	if assert.NotNil(t, stacked.BaseWorkspaceID) {
+		assert.Equal(t, base.ID, *stacked.BaseWorkspaceID)
+	}
+
+	stackedOn, err := tc.workspaceService.ListStackedOn(ctx, base)
+	assert.NoError(t, err)
+	if assert.Len(t, stackedOn, 1) {
+		assert.Equal(t, stacked.ID, stackedOn[0].ID)
+	}
+
+	stackedView, err := tc.viewService.Create(ctx, tc.userID, stacked, nil, nil)
+	assert.NoError(t, err)
+
+	assert.NoError(t, tc.executorProvider.New().Write(writeFile("stacked.txt", []byte("stacked"))).ExecView(tc.codebaseID, stackedView.ID, "make more changes"))
+
+	// diffs are computed against the base workspace
+	diffs, _, err := tc.workspaceService.Diffs(ctx, stacked.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, diffs, 1) {
+		assert.Equal(t, "stacked.txt", diffs[0].NewName)
+	}
+}
+
+func TestCreate_stacked_on_archived(t *testing.T) {
+	tc := setup(t)
+
+	ctx := context.Background()
+
+	base, err := tc.workspaceService.Create(ctx, service_workspace.CreateWorkspaceRequest{UserID: tc.userID, CodebaseID: tc.codebaseID})
+	assert.NoError(t, err)
+	assert.NoError(t, tc.workspaceService.Archive(ctx, base))
+
+	_, err = tc.workspaceService.Create(ctx, service_workspace.CreateWorkspaceRequest{
+		UserID:          tc.userID,
+		CodebaseID:      tc.codebaseID,
+		BaseWorkspaceID: &base.ID,
+	})
+	assert.ErrorIs(t, err, service_workspace.ErrBaseWorkspaceArchived)
+}
@@ -44,6 +44,11 @@ type Workspace struct {
 
 	// ChangeID is the last change id that was landed from this workspace.
 	ChangeID *changes.ID `db:"change_id" json:"-"`
+
+	// BaseWorkspaceID is set if this workspace is stacked on top of another workspace,
+	// instead of on top of the trunk. The workspace is based on the latest snapshot of the
+	// base workspace, and it's diffs are computed against the base workspace.
+	BaseWorkspaceID *string `db:"base_workspace_id" json:"-"`
 }
 
 func (w *Workspace) SetSnapshot(snapshot *snapshots.Snapshot) {
@@ -56,6 +61,10 @@ func (w *Workspace) SetSnapshot(snapshot *snapshots.Snapshot) {
 	}
 }
 
+func (w Workspace) IsStacked() bool {
+	return w.BaseWorkspaceID != nil
+}
+
 func (w Workspace) IsArchived() bool {
 	if w.UnarchivedAt != nil {
 		return false
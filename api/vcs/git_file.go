@@ -49,6 +49,33 @@ func (r *repository) FileBlobAtCommit(commitID, filePath string) (*git.Blob, err
 	return blob, nil
 }
 
+// FileModeAtCommit returns the mode of the tree entry at filePath, it returns ErrFileNotFound if there is no entry.
+func (r *repository) FileModeAtCommit(commitID, filePath string) (git.Filemode, error) {
+	defer getMeterFunc("FileModeAtCommit")()
+	oid, err := git.NewOid(commitID)
+	if err != nil {
+		return 0, err
+	}
+
+	commit, err := r.r.LookupCommit(oid)
+	if err != nil {
+		return 0, err
+	}
+	defer commit.Free()
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return 0, err
+	}
+	defer tree.Free()
+
+	entry, err := tree.EntryByPath(filePath)
+	if err != nil {
+		return 0, ErrFileNotFound
+	}
+	return entry.Filemode, nil
+}
+
 func (r *repository) DirectoryChildrenAtCommit(commitID, directoryPath string) ([]string, error) {
 	defer getMeterFunc("DirectoryChildrenAtCommit")()
 	oid, err := git.NewOid(commitID)
@@ -0,0 +1,186 @@
+package vcs
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	git "github.com/libgit2/git2go/v33"
+)
+
+// FilterCommit writes a copy of the commit, and all of its ancestors, where all files that are not allowed
+// are removed from the trees. It returns the id of the copy of the commit.
+//
+// rewritten maps commits that already have been filtered to the id of their copies, and is updated with all
+// commits filtered by this call. Copies are deterministic, filtering the same commit with the same allowed
+// function always returns the same id.
+func (r *repository) FilterCommit(commitID string, allowed func(filePath string) bool, rewritten map[string]string) (string, error) {
+	defer getMeterFunc("FilterCommit")()
+
+	if id, ok := rewritten[commitID]; ok {
+		return id, nil
+	}
+
+	trees := make(map[string]*git.Oid)
+
+	// Walk the history iteratively, a commit is filtered once all of its parents are
+	stack := []string{commitID}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		if _, ok := rewritten[id]; ok {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		commit, err := r.Commit(id)
+		if err != nil {
+			return "", fmt.Errorf("failed to lookup commit %s: %w", id, err)
+		}
+
+		var missingParents bool
+		parentIDs := make([]*git.Oid, 0, commit.ParentCount())
+		for i := uint(0); i < commit.ParentCount(); i++ {
+			parentID := commit.ParentId(i).String()
+			filteredParentID, ok := rewritten[parentID]
+			if !ok {
+				stack = append(stack, parentID)
+				missingParents = true
+				continue
+			}
+			oid, err := git.NewOid(filteredParentID)
+			if err != nil {
+				commit.Free()
+				return "", err
+			}
+			parentIDs = append(parentIDs, oid)
+		}
+		if missingParents {
+			commit.Free()
+			continue
+		}
+
+		treeID, err := r.filterTree(commit.TreeId(), "", allowed, trees)
+		if err != nil {
+			commit.Free()
+			return "", fmt.Errorf("failed to filter tree of %s: %w", id, err)
+		}
+
+		filteredID, err := r.r.CreateCommitFromIds("", commit.Author(), commit.Committer(), commit.RawMessage(), treeID, parentIDs...)
+		commit.Free()
+		if err != nil {
+			return "", fmt.Errorf("failed to create filtered commit of %s: %w", id, err)
+		}
+
+		rewritten[id] = filteredID.String()
+		stack = stack[:len(stack)-1]
+	}
+
+	return rewritten[commitID], nil
+}
+
+func (r *repository) filterTree(treeID *git.Oid, prefix string, allowed func(string) bool, trees map[string]*git.Oid) (*git.Oid, error) {
+	cacheKey := prefix + ":" + treeID.String()
+	if id, ok := trees[cacheKey]; ok {
+		return id, nil
+	}
+
+	tree, err := r.r.LookupTree(treeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lookup tree: %w", err)
+	}
+	defer tree.Free()
+
+	tb, err := r.r.TreeBuilder()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree builder: %w", err)
+	}
+	defer tb.Free()
+
+	var entries int
+	for i := uint64(0); i < tree.EntryCount(); i++ {
+		entry := tree.EntryByIndex(i)
+		entryPath := path.Join(prefix, entry.Name)
+
+		id := entry.Id
+		if entry.Type == git.ObjectTree {
+			if id, err = r.filterTree(entry.Id, entryPath, allowed, trees); err != nil {
+				return nil, err
+			}
+			if id == nil {
+				continue
+			}
+		} else if !allowed(entryPath) {
+			continue
+		}
+
+		if err := tb.Insert(entry.Name, id, entry.Filemode); err != nil {
+			return nil, fmt.Errorf("failed to insert %s: %w", entryPath, err)
+		}
+		entries++
+	}
+
+	// Directories without any allowed files are removed, except for the root
+	if entries == 0 && prefix != "" {
+		trees[cacheKey] = nil
+		return nil, nil
+	}
+
+	id, err := tb.Write()
+	if err != nil {
+		return nil, fmt.Errorf("failed to write tree: %w", err)
+	}
+	trees[cacheKey] = id
+	return id, nil
+}
+
+// SetRefs makes the references under prefix point to the given commits, and deletes all other
+// references under prefix.
+func (r *repository) SetRefs(prefix string, refs map[string]string) error {
+	defer getMeterFunc("SetRefs")()
+
+	iter, err := r.r.NewReferenceIterator()
+	if err != nil {
+		return fmt.Errorf("failed to list references: %w", err)
+	}
+	defer iter.Free()
+
+	var existing []string
+	names := iter.Names()
+	for {
+		name, err := names.Next()
+		if git.IsErrorCode(err, git.ErrorCodeIterOver) {
+			break
+		} else if err != nil {
+			return fmt.Errorf("failed to list references: %w", err)
+		}
+		if strings.HasPrefix(name, prefix) {
+			existing = append(existing, name)
+		}
+	}
+
+	for _, name := range existing {
+		if _, ok := refs[name]; ok {
+			continue
+		}
+		ref, err := r.r.References.Lookup(name)
+		if err != nil {
+			return fmt.Errorf("failed to lookup %s: %w", name, err)
+		}
+		err = ref.Delete()
+		ref.Free()
+		if err != nil {
+			return fmt.Errorf("failed to delete %s: %w", name, err)
+		}
+	}
+
+	for name, commitID := range refs {
+		if !strings.HasPrefix(name, prefix) {
+			return fmt.Errorf("reference %s is not under %s", name, prefix)
+		}
+		if err := r.CreateRef(name, commitID); err != nil {
+			return fmt.Errorf("failed to set %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package vcs
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterCommit(t *testing.T) {
+	repoPath := t.TempDir()
+
+	repo, err := CreateNonBareRepoWithRootCommit(repoPath, "main")
+	assert.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(path.Join(repoPath, "a.txt"), []byte("a"), 0o644))
+	assert.NoError(t, os.MkdirAll(path.Join(repoPath, "secret"), 0o755))
+	assert.NoError(t, os.WriteFile(path.Join(repoPath, "secret", "b.txt"), []byte("b"), 0o644))
+	first, err := repo.AddAndCommit("first")
+	assert.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(path.Join(repoPath, "secret", "b.txt"), []byte("bb"), 0o644))
+	second, err := repo.AddAndCommit("second")
+	assert.NoError(t, err)
+
+	allowed := func(filePath string) bool {
+		return !strings.HasPrefix(filePath, "secret/")
+	}
+
+	rewritten := make(map[string]string)
+	filtered, err := repo.FilterCommit(second, allowed, rewritten)
+	assert.NoError(t, err)
+	assert.Contains(t, rewritten, first)
+
+	contents, err := repo.FileContentsAtCommit(filtered, "a.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "a", string(contents))
+
+	_, err = repo.FileContentsAtCommit(filtered, "secret/b.txt")
+	assert.Error(t, err)
+
+	_, message, err := repo.CommitMessage(filtered)
+	assert.NoError(t, err)
+	assert.Equal(t, "second", strings.TrimSpace(message))
+
+	// filtering is deterministic
+	again, err := repo.FilterCommit(second, allowed, make(map[string]string))
+	assert.NoError(t, err)
+	assert.Equal(t, filtered, again)
+
+	// everything allowed gives back the same commit
+	all, err := repo.FilterCommit(second, func(string) bool { return true }, make(map[string]string))
+	assert.NoError(t, err)
+	assert.Equal(t, second, all)
+}
+
+func TestSetRefs(t *testing.T) {
+	repoPath := t.TempDir()
+
+	repo, err := CreateBareRepoWithRootCommit(repoPath)
+	assert.NoError(t, err)
+
+	head, err := repo.BranchCommitID("sturdytrunk")
+	assert.NoError(t, err)
+
+	assert.NoError(t, repo.SetRefs("refs/sturdy/", map[string]string{
+		"refs/sturdy/a": head,
+		"refs/sturdy/b": head,
+	}))
+	assert.NoError(t, repo.SetRefs("refs/sturdy/", map[string]string{
+		"refs/sturdy/a": head,
+	}))
+
+	_, err = repo.r.References.Lookup("refs/sturdy/a")
+	assert.NoError(t, err)
+	_, err = repo.r.References.Lookup("refs/sturdy/b")
+	assert.Error(t, err)
+
+	// refs outside of the prefix are untouched
+	_, err = repo.BranchCommitID("sturdytrunk")
+	assert.NoError(t, err)
+}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"fmt"
 	"os/exec"
+	"strings"
+	"time"
 )
 
 func (r *repository) GitGC() error {
@@ -50,3 +52,45 @@ func (r *repository) GitRemotePrune(remoteName string) error {
 	}
 	return nil
 }
+
+// GitTruncateHistory cuts off the history of HEAD before the given time, so that the commits that were created
+// before it are collected by the next git gc. The repository is made shallow by fetching HEAD from itself with a depth
+// that keeps the commits after the given time, HEAD itself is always kept. It returns false if there was nothing to
+// cut off.
+func (r *repository) GitTruncateHistory(before time.Time) (bool, error) {
+	kept, err := r.gitOutput("rev-list", fmt.Sprintf("--since=%d", before.Unix()), "HEAD")
+	if err != nil {
+		return false, err
+	}
+
+	depth, oldest := 1, "HEAD"
+	if ids := strings.Fields(kept); len(ids) > 0 {
+		depth, oldest = len(ids), ids[len(ids)-1]
+	}
+
+	// the first id is the commit itself, followed by its parents
+	parents, err := r.gitOutput("rev-list", "--parents", "--max-count=1", oldest)
+	if err != nil {
+		return false, err
+	}
+	if len(strings.Fields(parents)) < 2 {
+		return false, nil
+	}
+
+	if _, err := r.gitOutput("fetch", fmt.Sprintf("--depth=%d", depth), r.path, "HEAD"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *repository) gitOutput(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	errLog := &bytes.Buffer{}
+	cmd.Dir = r.path
+	cmd.Stderr = errLog
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to run git %s: %w, %s", args[0], err, errLog.String())
+	}
+	return string(output), nil
+}
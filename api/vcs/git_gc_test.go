@@ -0,0 +1,54 @@
+package vcs
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitTruncateHistory(t *testing.T) {
+	repoPath := t.TempDir()
+
+	repo, err := CreateNonBareRepoWithRootCommit(repoPath, "main")
+	require.NoError(t, err)
+
+	var headID string
+	for _, name := range []string{"a.txt", "b.txt"} {
+		require.NoError(t, os.WriteFile(path.Join(repoPath, name), []byte(name), 0o644))
+		headID, err = repo.AddAndCommit(name)
+		require.NoError(t, err)
+	}
+
+	// nothing is kept but the head
+	truncated, err := repo.GitTruncateHistory(time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.True(t, truncated)
+
+	count, err := repo.gitOutput("rev-list", "--count", "HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, "1", strings.TrimSpace(count))
+
+	// the files of the head are still there
+	contents, err := repo.FileContentsAtCommit(headID, "a.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "a.txt", string(contents))
+
+	truncated, err = repo.GitTruncateHistory(time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.False(t, truncated)
+
+	// the truncated repository is still consistent once the cut off commits are collected
+	require.NoError(t, repo.GitReflogExpire())
+	require.NoError(t, repo.GitGC())
+	_, err = repo.gitOutput("fsck", "--no-dangling")
+	require.NoError(t, err)
+
+	count, err = repo.gitOutput("rev-list", "--count", "HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, "1", strings.TrimSpace(count))
+}
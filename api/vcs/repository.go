@@ -2,6 +2,7 @@ package vcs
 
 import (
 	"context"
+	"time"
 
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing/transport"
@@ -43,6 +44,7 @@ type RepoGitReader interface {
 
 	FileContentsAtCommit(commitID, filePath string) ([]byte, error)
 	FileBlobAtCommit(commitID, filePath string) (*git.Blob, error)
+	FileModeAtCommit(commitID, filePath string) (git.Filemode, error)
 	DirectoryChildrenAtCommit(commitID, directoryPath string) ([]string, error)
 
 	LogHead(limit int) ([]*LogEntry, error)
@@ -85,11 +87,15 @@ type RepoGitWriter interface {
 	GitGC() error
 	GitReflogExpire() error
 	GitRemotePrune(remoteName string) error
+	GitTruncateHistory(before time.Time) (bool, error)
 
 	MergeBranches(ourBranchName, theirBranchName string) (*git.Index, error)
 	MergeBranchInto(branchName, mergeIntoBranchName string) (mergeCommitId string, err error)
 
 	ApplyPatchesToIndex(ctx context.Context, patches [][]byte) (*git.Oid, error)
+
+	FilterCommit(commitID string, allowed func(filePath string) bool, rewritten map[string]string) (string, error)
+	SetRefs(prefix string, refs map[string]string) error
 }
 
 type RepoReaderGitWriter interface {
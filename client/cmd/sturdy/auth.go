@@ -104,29 +104,11 @@ func renewAuth(conf *config.Config, configPath string, api api.SturdyAPI) error
 		return nil
 	}
 
-	// This does _not_ validate the token. It simply extracts the expiration date to check if we're eligible for a token renewal
-	token, _, err := new(jwt.Parser).ParseUnverified(conf.Auth, jwt.MapClaims{})
-	if err != nil {
-		return nil
-	}
-
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return nil
-	}
-
-	exp, ok := claims["exp"]
+	expT, ok := authExpiry(conf.Auth)
 	if !ok {
 		return nil
 	}
 
-	expFloat, ok := exp.(float64)
-	if !ok {
-		return nil
-	}
-
-	expT := time.Unix(int64(expFloat), 0)
-
 	// If expires in more than 25 days
 	if !expT.Before(time.Now().Add(time.Hour * 24 * 25)) {
 		return nil
@@ -152,6 +134,32 @@ func renewAuth(conf *config.Config, configPath string, api api.SturdyAPI) error
 	return nil
 }
 
+// authExpiry returns when the auth token expires, ok is false if the token can't be parsed
+func authExpiry(authToken string) (expiresAt time.Time, ok bool) {
+	// This does _not_ validate the token. It simply extracts the expiration date to check if we're eligible for a token renewal
+	token, _, err := new(jwt.Parser).ParseUnverified(authToken, jwt.MapClaims{})
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	exp, ok := claims["exp"]
+	if !ok {
+		return time.Time{}, false
+	}
+
+	expFloat, ok := exp.(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return time.Unix(int64(expFloat), 0), true
+}
+
 func requireAuth(conf *config.Config, configPath string) (*api.HttpApiClient, error) {
 	// New authentication
 	if conf.Auth == "" {
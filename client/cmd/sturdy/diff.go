@@ -0,0 +1,76 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"getsturdy.com/client/cmd/sturdy/config"
+	"getsturdy.com/client/pkg/api"
+)
+
+func diff(conf *config.Config, args []string, apiClient api.SturdyAPI) {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Print the output as JSON")
+	nameOnly := fs.Bool("name-only", false, "Only print the names of the changed files")
+	target := fs.String("workspace", "", "The workspace to diff (defaults to the open workspace)")
+	if positional := parseInterspersed(fs, args); len(positional) > 0 {
+		log.Fatalln("❌ Unexpected number of arguments, usage: sturdy diff [--workspace <workspace>] [--name-only] [--json]")
+	}
+
+	view, viewInfo := requireView(conf, apiClient)
+	gqlClient := api.NewGraphQLClient(conf)
+
+	ws, err := targetWorkspace(gqlClient, view.ID, viewInfo.CodebaseID, *target)
+	if err != nil {
+		log.Fatalf("❌ %s\n", err)
+	}
+
+	diffs, err := gqlClient.WorkspaceDiffs(ws.ID)
+	if err != nil {
+		log.Fatalf("❌ %s\n", err)
+	}
+
+	switch {
+	case *jsonOutput:
+		printJSON(diffs)
+	case *nameOnly:
+		for _, d := range diffs {
+			fmt.Println(d.PreferredName)
+		}
+	default:
+		fmt.Print(unifiedDiff(diffs))
+	}
+}
+
+// unifiedDiff joins the hunks of the diffs into a single patch.
+//
+// Every hunk returned by the API is a complete patch, including the file header, so the header is only kept for the
+// first hunk of each file.
+func unifiedDiff(diffs []api.FileDiff) string {
+	var b strings.Builder
+	for _, d := range diffs {
+		switch {
+		case d.IsHidden:
+			continue
+		case d.IsLarge:
+			fmt.Fprintf(&b, "Large file %s differs\n", d.PreferredName)
+			continue
+		}
+
+		for i, hunk := range d.Hunks {
+			patch := hunk.Patch
+			if i > 0 {
+				if idx := strings.Index(patch, "\n@@"); idx >= 0 {
+					patch = patch[idx+1:]
+				}
+			}
+			b.WriteString(patch)
+			if !strings.HasSuffix(patch, "\n") {
+				b.WriteString("\n")
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"testing"
+
+	"getsturdy.com/client/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	header := "diff --git \"a/one.txt\" \"b/one.txt\"\nindex 4fce4a5..fef85d8 100644\n--- \"a/one.txt\"\n+++ \"b/one.txt\"\n"
+	diffs := []api.FileDiff{
+		{
+			PreferredName: "one.txt",
+			Hunks: []api.Hunk{
+				{Patch: header + "@@ -2,2 +2,1 @@ a\n b\n-c\n"},
+				{Patch: header + "@@ -16,2 +15,1 @@ o\n p\n-q\n"},
+			},
+		},
+		{PreferredName: "large.bin", IsLarge: true},
+		{PreferredName: "hidden.txt", IsHidden: true},
+	}
+
+	expected := header +
+		"@@ -2,2 +2,1 @@ a\n b\n-c\n" +
+		"@@ -16,2 +15,1 @@ o\n p\n-q\n" +
+		"Large file large.bin differs\n"
+	assert.Equal(t, expected, unifiedDiff(diffs))
+}
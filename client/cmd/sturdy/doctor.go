@@ -0,0 +1,657 @@
+package main
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"getsturdy.com/client/cmd/sturdy/config"
+	"getsturdy.com/client/cmd/sturdy/version"
+	"getsturdy.com/client/pkg/api"
+	"getsturdy.com/client/pkg/filesync"
+	"getsturdy.com/client/pkg/mutagen"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+type checkResult string
+
+const (
+	checkOK      checkResult = "ok"
+	checkWarning checkResult = "warning"
+	checkFailed  checkResult = "failed"
+)
+
+// Views on filesystems with less free space than minFreeDiskSpace are likely to fail to sync, and views with less than
+// lowFreeDiskSpace are close to it
+const (
+	minFreeDiskSpace = 1 << 30
+	lowFreeDiskSpace = 5 << 30
+)
+
+type doctorCheck struct {
+	Name    string      `json:"name"`
+	Result  checkResult `json:"result"`
+	Message string      `json:"message"`
+	Fix     string      `json:"fix,omitempty"`
+}
+
+type doctorReport struct {
+	Version   string        `json:"version"`
+	OS        string        `json:"os"`
+	Arch      string        `json:"arch"`
+	CreatedAt int64         `json:"createdAt"`
+	Checks    []doctorCheck `json:"checks"`
+}
+
+func (r *doctorReport) ok(name, format string, a ...interface{}) {
+	r.Checks = append(r.Checks, doctorCheck{Name: name, Result: checkOK, Message: fmt.Sprintf(format, a...)})
+}
+
+func (r *doctorReport) warn(name, fix, format string, a ...interface{}) {
+	r.Checks = append(r.Checks, doctorCheck{Name: name, Result: checkWarning, Message: fmt.Sprintf(format, a...), Fix: fix})
+}
+
+func (r *doctorReport) fail(name, fix, format string, a ...interface{}) {
+	r.Checks = append(r.Checks, doctorCheck{Name: name, Result: checkFailed, Message: fmt.Sprintf(format, a...), Fix: fix})
+}
+
+func (r *doctorReport) count(result checkResult) int {
+	var n int
+	for _, c := range r.Checks {
+		if c.Result == result {
+			n++
+		}
+	}
+	return n
+}
+
+// doctorState is what the checks have found out so far, checks that depend on something that's missing are skipped
+type doctorState struct {
+	configPath string
+	conf       *config.Config
+	// authTokens are all tokens that have been used, they are redacted from the support bundle
+	authTokens []string
+
+	apiClient *api.HttpApiClient
+	gqlClient *api.GraphQLClient
+	user      *api.GetUserResponse
+
+	syncVersion string
+	sessions    []mutagen.SessionStatus
+
+	// broken are the views that Sturdy reports errors for, they are repaired with --repair
+	broken []config.ViewConfig
+
+	report doctorReport
+}
+
+// doctor checks that everything needed to sync is working, and prints what to do about the problems that it finds
+func doctor(configPath string, args []string) {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+	repair := fs.Bool("repair", false, "Repair the codebases that Sturdy reports errors for")
+	bundlePath := fs.String("bundle", "", "Write a support bundle, with secrets removed, to this path")
+	jsonOutput := fs.Bool("json", false, "Print the report as JSON")
+	if positional := parseInterspersed(fs, args); len(positional) > 0 {
+		log.Fatalln("❌ Unexpected number of arguments, usage: sturdy doctor [--repair] [--bundle <path>] [--json]")
+	}
+
+	d := &doctorState{
+		configPath: configPath,
+		report: doctorReport{
+			Version:   version.Version,
+			OS:        runtime.GOOS,
+			Arch:      runtime.GOARCH,
+			CreatedAt: time.Now().Unix(),
+		},
+	}
+
+	d.checkConfig()
+	if d.conf != nil {
+		d.checkAuth()
+		d.checkSSH()
+		d.checkSyncDaemon()
+		d.checkIgnores()
+		d.checkDiskSpace()
+		if *repair {
+			d.repairViews()
+		}
+	}
+
+	if *jsonOutput {
+		printJSON(d.report)
+	} else {
+		printDoctorReport(d.report)
+	}
+
+	if *bundlePath != "" {
+		if err := d.writeSupportBundle(*bundlePath); err != nil {
+			log.Fatalf("❌ %s\n", err)
+		}
+		if !*jsonOutput {
+			fmt.Printf("📦 Wrote a support bundle to %s, attach it when contacting support\n", *bundlePath)
+		}
+	}
+
+	if d.report.count(checkFailed) > 0 {
+		os.Exit(1)
+	}
+}
+
+func (d *doctorState) checkConfig() {
+	const name = "Config"
+
+	_, err := os.Stat(d.configPath)
+	exists := err == nil
+
+	conf, err := config.ReadConfig(d.configPath)
+	if err != nil {
+		d.report.fail(name, fmt.Sprintf("Fix or remove %s, and run \"sturdy auth\"", d.configPath), "%s", err)
+		return
+	}
+	d.conf = conf
+	if conf.Auth != "" {
+		d.authTokens = append(d.authTokens, conf.Auth)
+	}
+
+	problems := configProblems(conf, d.configPath)
+	d.report.Checks = append(d.report.Checks, problems...)
+
+	switch {
+	case !exists:
+		d.report.warn(name, "Run \"sturdy auth\" to get started", "There is no configuration at %s", d.configPath)
+	case len(problems) == 0:
+		d.report.ok(name, "%s is valid, with %d %s", d.configPath, len(conf.Views), plural(len(conf.Views), "codebase", "codebases"))
+	}
+}
+
+// configProblems validates the remotes and the views of the configuration
+func configProblems(conf *config.Config, configPath string) []doctorCheck {
+	const name = "Config"
+	var r doctorReport
+
+	if u, err := url.Parse(conf.APIRemote); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		r.fail(name, fmt.Sprintf("Set api-remote in %s to the URL of the Sturdy API, for example https://api.getsturdy.com", configPath),
+			"api-remote %q is not a valid URL", conf.APIRemote)
+	}
+	if strings.Contains(conf.SyncRemote, "/") {
+		r.fail(name, fmt.Sprintf("Set sync-remote in %s to a host, with an optional port, for example sync.getsturdy.com", configPath),
+			"sync-remote %q is not a valid host", conf.SyncRemote)
+	}
+
+	fix := fmt.Sprintf("Remove the codebase from %s, and run \"sturdy init\" to set it up again", configPath)
+	ids := make(map[string]struct{})
+	for i, view := range conf.Views {
+		if view.ID == "" {
+			r.fail(name, fix, "The codebase at %s has no id", view.Path)
+		} else if _, ok := ids[view.ID]; ok {
+			r.fail(name, fix, "The codebase at %s is configured more than once", view.Path)
+		}
+		ids[view.ID] = struct{}{}
+
+		if !filepath.IsAbs(view.Path) {
+			r.fail(name, fix, "The path of the codebase %q is not absolute", view.Path)
+			continue
+		}
+		if info, err := os.Stat(view.Path); err != nil {
+			r.fail(name, fix, "The directory of the codebase %s can't be read: %s", view.Path, err)
+			continue
+		} else if !info.IsDir() {
+			r.fail(name, fix, "The codebase %s is not a directory", view.Path)
+			continue
+		}
+
+		for _, other := range conf.Views[:i] {
+			if ok, _ := pathIsSub(other.Path, view.Path); ok && other.ID != view.ID {
+				r.fail(name, fix, "The codebases %s and %s are inside each other", other.Path, view.Path)
+			}
+		}
+	}
+
+	return r.Checks
+}
+
+func (d *doctorState) checkAuth() {
+	const name = "Authentication"
+	fix := "Run \"sturdy auth\" to sign in again"
+
+	if d.conf.Auth == "" {
+		d.report.fail(name, "Run \"sturdy auth\" to sign in", "You're not signed in")
+		return
+	}
+
+	expiresAt, ok := authExpiry(d.conf.Auth)
+	if !ok {
+		d.report.fail(name, fix, "Your auth token is malformed")
+		return
+	}
+	if expiresAt.Before(time.Now()) {
+		d.report.fail(name, fix, "Your auth token expired on %s", expiresAt.Format("2006-01-02"))
+		return
+	}
+
+	// Renews the token if it's about to expire
+	if err := renewAuth(d.conf, d.configPath, api.NewHttpApiClient(d.conf)); err != nil {
+		d.report.fail(name, fix, "%s", err)
+		return
+	}
+	if d.conf.Auth != d.authTokens[len(d.authTokens)-1] {
+		d.authTokens = append(d.authTokens, d.conf.Auth)
+		expiresAt, _ = authExpiry(d.conf.Auth)
+	}
+
+	apiClient := api.NewHttpApiClient(d.conf)
+	user, err := apiClient.GetUser()
+	if errors.Is(err, api.ErrUnauthorized) {
+		d.report.fail(name, fix, "Sturdy didn't accept your auth token")
+		return
+	} else if err != nil {
+		d.report.fail(name, fmt.Sprintf("Check your internet connection, and that api-remote in %s is correct", d.configPath),
+			"Could not reach %s: %s", d.conf.APIRemote, err)
+		return
+	}
+
+	d.apiClient = apiClient
+	d.gqlClient = api.NewGraphQLClient(d.conf)
+	d.user = &user
+	d.report.ok(name, "Signed in as %s, the auth token expires on %s", user.Name, expiresAt.Format("2006-01-02"))
+}
+
+func (d *doctorState) checkSSH() {
+	const name = "SSH"
+
+	host, port := syncHostPort(d.conf.SyncRemote)
+	addr := net.JoinHostPort(host, port)
+	fix := fmt.Sprintf("Make sure that connections to port %s are allowed by your firewall, or use \"sturdy upload\" which uploads changes over HTTPS", port)
+
+	conn, err := net.DialTimeout("tcp", addr, 10*time.Second)
+	if err != nil {
+		d.report.fail(name, fix, "Could not connect to %s: %s", addr, err)
+		return
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(10 * time.Second))
+
+	hostKey, err := remoteHostKey(conn, addr)
+	if err != nil {
+		d.report.fail(name, fix, "Could not get the host key of %s: %s", addr, err)
+		return
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		d.report.fail(name, "", "Could not find your home directory: %s", err)
+		return
+	}
+	knownHostsPath := filepath.Join(homeDir, ".ssh", "known_hosts")
+
+	var keyErr *knownhosts.KeyError
+	switch err := checkKnownHost(knownHostsPath, addr, conn.RemoteAddr(), hostKey); {
+	case err == nil:
+		d.report.ok(name, "Connected to %s, and its host key is trusted", addr)
+	case errors.Is(err, os.ErrNotExist), errors.As(err, &keyErr) && len(keyErr.Want) == 0:
+		d.report.warn(name, "Run \"sturdy start\" to trust it", "The host key of %s is not in %s", addr, knownHostsPath)
+	case errors.As(err, &keyErr):
+		d.report.fail(name, fmt.Sprintf("If you trust the new key, remove the old one with \"ssh-keygen -R %s\", and run \"sturdy start\"", knownhosts.Normalize(addr)),
+			"The host key of %s does not match the key in %s", addr, knownHostsPath)
+	default:
+		d.report.fail(name, fmt.Sprintf("Fix or remove %s, and run \"sturdy start\"", knownHostsPath), "Could not read %s: %s", knownHostsPath, err)
+	}
+
+	if d.user != nil && len(d.conf.Views) > 0 {
+		privateKeyPath := filepath.Join(homeDir, ".sturdy-agent", "private-key-ed25519-"+d.user.ID+".pem")
+		if _, err := os.Stat(privateKeyPath); err != nil {
+			d.report.warn(name, "Run \"sturdy start\" to create one", "There is no SSH key for Sturdy at %s", privateKeyPath)
+		}
+	}
+}
+
+var errGotHostKey = errors.New("got host key")
+
+// remoteHostKey returns the host key that the SSH server on conn presents, without authenticating
+func remoteHostKey(conn net.Conn, addr string) (ssh.PublicKey, error) {
+	var hostKey ssh.PublicKey
+	_, _, _, err := ssh.NewClientConn(conn, addr, &ssh.ClientConfig{
+		User: "sturdy",
+		HostKeyCallback: func(_ string, _ net.Addr, key ssh.PublicKey) error {
+			hostKey = key
+			return errGotHostKey
+		},
+	})
+	if hostKey != nil {
+		return hostKey, nil
+	}
+	return nil, err
+}
+
+// checkKnownHost returns a *knownhosts.KeyError if key is not the known key of addr
+func checkKnownHost(knownHostsPath, addr string, remote net.Addr, key ssh.PublicKey) error {
+	callback, err := knownhosts.New(knownHostsPath)
+	if err != nil {
+		return err
+	}
+	return callback(addr, remote, key)
+}
+
+func (d *doctorState) checkSyncDaemon() {
+	const name = "sturdy-sync"
+
+	if len(d.conf.Views) == 0 {
+		return
+	}
+
+	if _, err := exec.LookPath("sturdy-sync"); err != nil {
+		d.report.warn(name, "Reinstall Sturdy, or use \"sturdy upload\" which uploads changes without sturdy-sync", "sturdy-sync is not installed")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, "sturdy-sync", "version").Output()
+	if err != nil {
+		d.report.fail(name, "Reinstall Sturdy", "Could not get the version of sturdy-sync: %s", err)
+		return
+	}
+	d.syncVersion = strings.TrimSpace(string(output))
+
+	sessions, err := mutagen.Status()
+	if err != nil {
+		d.report.fail(name, "Run \"sturdy restart\"", "Could not get the status of the sturdy-sync daemon: %s", err)
+		return
+	}
+	d.sessions = sessions
+	d.report.ok(name, "sturdy-sync %s is running", d.syncVersion)
+
+	sessionsByName := make(map[string]mutagen.SessionStatus)
+	for _, s := range sessions {
+		sessionsByName[s.Session.Name] = s
+	}
+
+	for _, view := range d.conf.Views {
+		checkName := fmt.Sprintf("Sync (%s)", view.Path)
+
+		session, ok := sessionsByName[viewMutagenName(view)]
+		switch {
+		case !ok:
+			d.report.warn(checkName, "Run \"sturdy start\", or \"sturdy upload\"", "The codebase is not being synced")
+		case session.Session.Labels["sessionVersion"] != SessionVersionNumber:
+			d.report.warn(checkName, "Run \"sturdy restart\"", "Syncing was set up by another version of Sturdy")
+		case session.Session.Paused:
+			d.report.warn(checkName, "Run \"sturdy start\"", "Syncing is paused")
+		case session.LastError != "":
+			d.report.fail(checkName, "Run \"sturdy restart\"", "Syncing failed: %s", session.LastError)
+		case !session.AlphaConnected || !session.BetaConnected:
+			d.report.warn(checkName, "Run \"sturdy restart\"", "The codebase is not connected")
+		default:
+			d.report.ok(checkName, "Connected")
+		}
+
+		d.checkViewStatus(checkName, view)
+	}
+}
+
+// checkViewStatus checks the status of the view that Sturdy has received from sturdy-sync
+func (d *doctorState) checkViewStatus(checkName string, view config.ViewConfig) {
+	if d.gqlClient == nil {
+		return
+	}
+
+	status, err := d.gqlClient.GetViewStatus(view.ID)
+	if err != nil {
+		d.report.warn(checkName, "", "Could not get the status of the codebase from Sturdy: %s", err)
+		return
+	}
+	if status == nil {
+		return
+	}
+
+	if status.LastError != nil && *status.LastError != "" {
+		d.report.fail(checkName, "Run \"sturdy doctor --repair\" to repair the codebase on the server", "Sturdy reports an error: %s", *status.LastError)
+		d.broken = append(d.broken, view)
+	}
+	if status.SturdyVersion != "" && strings.TrimPrefix(status.SturdyVersion, "v") != strings.TrimPrefix(d.syncVersion, "v") {
+		d.report.warn(checkName, "Run \"sturdy restart\" to restart the daemon",
+			"The codebase was last synced by sturdy-sync %s, but %s is installed", status.SturdyVersion, d.syncVersion)
+	}
+}
+
+func (d *doctorState) checkIgnores() {
+	if d.apiClient == nil {
+		return
+	}
+
+	for _, view := range d.conf.Views {
+		checkName := fmt.Sprintf("Ignores (%s)", view.Path)
+
+		ignores, err := d.apiClient.GetIgnores(view.ID)
+		if err != nil {
+			d.report.fail(checkName, "", "Could not get the ignores from Sturdy: %s", err)
+			continue
+		}
+
+		if bad := filesync.BadPatterns(ignores.Paths); len(bad) > 0 {
+			d.report.warn(checkName, "Fix the patterns in the .gitignore files of the codebase",
+				"%d malformed %s never %s anything: %s", len(bad), plural(len(bad), "pattern", "patterns"), plural(len(bad), "matches", "match"), strings.Join(bad, ", "))
+			continue
+		}
+		d.report.ok(checkName, "%d %s", len(ignores.Paths), plural(len(ignores.Paths), "pattern", "patterns"))
+	}
+}
+
+func (d *doctorState) checkDiskSpace() {
+	for _, view := range d.conf.Views {
+		// Missing directories are reported by the config check
+		if _, err := os.Stat(view.Path); err != nil {
+			continue
+		}
+		checkName := fmt.Sprintf("Disk space (%s)", view.Path)
+
+		free, err := freeDiskSpace(view.Path)
+		if err != nil {
+			d.report.warn(checkName, "", "Could not get the free disk space: %s", err)
+			continue
+		}
+
+		switch {
+		case free < minFreeDiskSpace:
+			d.report.fail(checkName, "Free up disk space", "Only %s is free", formatBytes(free))
+		case free < lowFreeDiskSpace:
+			d.report.warn(checkName, "Free up disk space", "Only %s is free", formatBytes(free))
+		default:
+			d.report.ok(checkName, "%s is free", formatBytes(free))
+		}
+	}
+}
+
+func (d *doctorState) repairViews() {
+	const name = "Repair"
+
+	if d.gqlClient == nil {
+		d.report.fail(name, "Fix the authentication problems first", "Can't repair codebases without being signed in")
+		return
+	}
+	if len(d.broken) == 0 {
+		d.report.ok(name, "Sturdy doesn't report any errors for your codebases, there is nothing to repair")
+		return
+	}
+
+	for _, view := range d.broken {
+		if err := d.gqlClient.RepairView(view.ID); err != nil {
+			d.report.fail(name, "Contact support, and attach a support bundle (sturdy doctor --bundle <path>)", "Could not repair %s: %s", view.Path, err)
+			continue
+		}
+		d.report.ok(name, "Repaired %s", view.Path)
+	}
+}
+
+func printDoctorReport(report doctorReport) {
+	for _, c := range report.Checks {
+		fmt.Printf("%s %s: %s\n", checkEmoji(c.Result), c.Name, c.Message)
+		if c.Fix != "" {
+			fmt.Printf("   👉 %s\n", c.Fix)
+		}
+	}
+	fmt.Println()
+
+	failed, warnings := report.count(checkFailed), report.count(checkWarning)
+	switch {
+	case failed > 0:
+		fmt.Printf("❌ Found %d %s and %d %s\n", failed, plural(failed, "problem", "problems"), warnings, plural(warnings, "warning", "warnings"))
+	case warnings > 0:
+		fmt.Printf("⚠️  Found %d %s\n", warnings, plural(warnings, "warning", "warnings"))
+	default:
+		fmt.Println("✅ Everything looks good!")
+	}
+}
+
+func checkEmoji(result checkResult) string {
+	switch result {
+	case checkOK:
+		return "✅"
+	case checkWarning:
+		return "⚠️ "
+	default:
+		return "❌"
+	}
+}
+
+func formatBytes(n uint64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1f GiB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20))
+	default:
+		return fmt.Sprintf("%d bytes", n)
+	}
+}
+
+func plural(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
+
+// writeSupportBundle writes a zip file with the report, the configuration and the state of sturdy-sync. Auth tokens,
+// email addresses and the path of the home directory are redacted, and SSH keys are never included.
+func (d *doctorState) writeSupportBundle(p string) error {
+	homeDir, _ := os.UserHomeDir()
+	r := newRedactor(d.authTokens, homeDir)
+
+	f, err := os.Create(p)
+	if err != nil {
+		return fmt.Errorf("failed to create support bundle: %w", err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	add := func(name string, data []byte) error {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()})
+		if err != nil {
+			return err
+		}
+		_, err = w.Write([]byte(r.redact(string(data))))
+		return err
+	}
+	addJSON := func(name string, v interface{}) error {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		return add(name, data)
+	}
+
+	if err := addJSON("report.json", d.report); err != nil {
+		return fmt.Errorf("failed to write support bundle: %w", err)
+	}
+
+	// The raw file, to include configs that can't be parsed
+	if data, err := ioutil.ReadFile(d.configPath); err == nil {
+		if err := add("config.json", data); err != nil {
+			return fmt.Errorf("failed to write support bundle: %w", err)
+		}
+	}
+
+	if d.sessions != nil {
+		if err := addJSON("sturdy-sync/sessions.json", d.sessions); err != nil {
+			return fmt.Errorf("failed to write support bundle: %w", err)
+		}
+	}
+
+	// The sturdy-sync configs of the views, the private keys in the same directory are not included
+	if homeDir != "" {
+		configs, _ := filepath.Glob(filepath.Join(homeDir, ".sturdy-agent", "*.yaml"))
+		for _, c := range configs {
+			data, err := ioutil.ReadFile(c)
+			if err != nil {
+				continue
+			}
+			if err := add("sturdy-sync/"+filepath.Base(c), data); err != nil {
+				return fmt.Errorf("failed to write support bundle: %w", err)
+			}
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to write support bundle: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write support bundle: %w", err)
+	}
+	return nil
+}
+
+var (
+	jwtPattern   = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+)
+
+type redactor struct {
+	secrets *strings.Replacer
+	homeDir *strings.Replacer
+}
+
+// newRedactor returns a redactor that removes the secrets, and any other JWTs and email addresses, from text. The
+// home directory is replaced with ~, also when it's escaped in JSON.
+func newRedactor(secrets []string, homeDir string) *redactor {
+	var oldnew []string
+	for _, s := range secrets {
+		if s != "" {
+			oldnew = append(oldnew, s, "[REDACTED]")
+		}
+	}
+
+	var homeOldnew []string
+	if homeDir != "" {
+		escaped, _ := json.Marshal(homeDir)
+		homeOldnew = append(homeOldnew, strings.Trim(string(escaped), `"`), "~", homeDir, "~")
+	}
+
+	return &redactor{
+		secrets: strings.NewReplacer(oldnew...),
+		homeDir: strings.NewReplacer(homeOldnew...),
+	}
+}
+
+func (r *redactor) redact(s string) string {
+	s = r.secrets.Replace(s)
+	s = jwtPattern.ReplaceAllString(s, "[REDACTED]")
+	s = emailPattern.ReplaceAllString(s, "[REDACTED]")
+	return r.homeDir.Replace(s)
+}
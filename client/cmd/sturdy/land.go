@@ -0,0 +1,122 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"html"
+	"log"
+	"os"
+	"strings"
+
+	"getsturdy.com/client/cmd/sturdy/config"
+	"getsturdy.com/client/pkg/api"
+)
+
+// stringsFlag is a flag that can be given multiple times
+type stringsFlag []string
+
+func (s *stringsFlag) String() string {
+	return strings.Join(*s, ", ")
+}
+
+func (s *stringsFlag) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+func land(conf *config.Config, args []string, apiClient api.SturdyAPI) {
+	fs := flag.NewFlagSet("land", flag.ExitOnError)
+	var messages stringsFlag
+	fs.Var(&messages, "m", "The description of the change, if given multiple times each value is a paragraph (defaults to the current draft description)")
+	target := fs.String("workspace", "", "The workspace to land (defaults to the open workspace)")
+	jsonOutput := fs.Bool("json", false, "Print the output as JSON")
+	if positional := parseInterspersed(fs, args); len(positional) > 0 {
+		log.Fatalln("❌ Unexpected number of arguments, usage: sturdy land -m <message> [--workspace <workspace>] [--json]")
+	}
+
+	view, viewInfo := requireView(conf, apiClient)
+	gqlClient := api.NewGraphQLClient(conf)
+
+	ws, err := targetWorkspace(gqlClient, view.ID, viewInfo.CodebaseID, *target)
+	if err != nil {
+		log.Fatalf("❌ %s\n", err)
+	}
+
+	if len(messages) > 0 {
+		description := draftDescription(messages)
+		if ws, err = gqlClient.UpdateWorkspace(ws.ID, nil, &description); err != nil {
+			log.Fatalf("❌ %s\n", err)
+		}
+	} else if strings.TrimSpace(ws.DraftDescription) == "" {
+		log.Fatalln("❌ The draft has no description, provide one with -m")
+	}
+
+	landed, err := gqlClient.LandWorkspaceChange(ws.ID)
+	if err != nil {
+		printLandError(gqlClient, ws, err)
+		os.Exit(1)
+	}
+
+	if *jsonOutput {
+		printJSON(landed)
+		return
+	}
+	fmt.Printf("✅ Landed %s\n", workspaceDisplayName(ws))
+}
+
+// draftDescription converts the messages to the HTML used for draft descriptions, every line becomes a paragraph
+func draftDescription(messages []string) string {
+	var b strings.Builder
+	for i, message := range messages {
+		if i > 0 {
+			b.WriteString("<p></p>")
+		}
+		for _, line := range strings.Split(strings.TrimSpace(message), "\n") {
+			fmt.Fprintf(&b, "<p>%s</p>", html.EscapeString(strings.TrimRight(line, "\r")))
+		}
+	}
+	return b.String()
+}
+
+func printLandError(gqlClient *api.GraphQLClient, ws api.Workspace, err error) {
+	gqlErr, ok := api.AsGraphQLError(err)
+	if !ok {
+		fmt.Printf("❌ %s\n", err)
+		return
+	}
+
+	fmt.Printf("❌ Could not land %s: %s\n", workspaceDisplayName(ws), gqlErr.Detail())
+
+	switch gqlErr.Reason() {
+	case api.ReasonUnhealthyWorkspace:
+		statuses, err := gqlClient.WorkspaceStatuses(ws.ID)
+		if err != nil {
+			return
+		}
+		for _, status := range statuses {
+			if status.Type == "Healthy" {
+				continue
+			}
+			line := fmt.Sprintf("   %s %s", statusEmoji(status.Type), status.Title)
+			if status.DetailsURL != nil {
+				line += " " + *status.DetailsURL
+			}
+			fmt.Println(line)
+		}
+	case api.ReasonOutdatedWorkspace:
+		fmt.Println("   Sync the draft with the trunk and try again")
+	case api.ReasonTooFewApprovals, api.ReasonMissingOwnerApproval:
+		fmt.Println("   Ask for a review with 'sturdy review request <user>'")
+	}
+}
+
+func statusEmoji(statusType string) string {
+	switch statusType {
+	case "Healthy":
+		return "✅"
+	case "Failing":
+		return "❌"
+	default:
+		return "⏳"
+	}
+}
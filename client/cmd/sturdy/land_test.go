@@ -0,0 +1,12 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDraftDescription(t *testing.T) {
+	assert.Equal(t, "<p>Fix &lt;div&gt; &amp; styles</p>", draftDescription([]string{"Fix <div> & styles"}))
+	assert.Equal(t, "<p>Title</p><p></p><p>first</p><p>second</p>", draftDescription([]string{"Title", "first\r\nsecond\n"}))
+}
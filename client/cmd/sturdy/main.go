@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"path"
+	"strings"
 
 	"getsturdy.com/client/cmd/sturdy/config"
 	"getsturdy.com/client/cmd/sturdy/legal"
@@ -18,15 +19,23 @@ func printHelpAndExit() {
 	fmt.Println("No subcommand provided")
 	fmt.Println("")
 	fmt.Println("Available commands:")
-	fmt.Println("  start    Start Sturdy connections for all connected codebases")
-	fmt.Println("  stop     Stop all connections and stop the daemon")
-	fmt.Println("  restart  Restart and re-configure all connections")
-	fmt.Println("  status   Get the current status of each codebase")
-	fmt.Println("  auth     Authenticate yourself with Sturdy")
-	fmt.Println("  init     Configure a new codebase to be used from this computer")
-	fmt.Println("  import   Import a Git repository to Sturdy")
-	fmt.Println("  version  Display Sturdy version information")
-	fmt.Println("  legal    Display legal credits")
+	fmt.Println("  start      Start Sturdy connections for all connected codebases")
+	fmt.Println("  stop       Stop all connections and stop the daemon")
+	fmt.Println("  restart    Restart and re-configure all connections")
+	fmt.Println("  status     Get the current status of each codebase")
+	fmt.Println("  upload     Upload local changes of all codebases without the daemon, until stopped (one-way, use instead of start)")
+	fmt.Println("  auth       Authenticate yourself with Sturdy")
+	fmt.Println("  init       Configure a new codebase to be used from this computer")
+	fmt.Println("  import     Import a Git repository to Sturdy")
+	fmt.Println("  workspace  List, create, switch, archive and rename workspaces")
+	fmt.Println("  diff       Show the changes in the current workspace")
+	fmt.Println("  land       Land the changes in the current workspace")
+	fmt.Println("  review     Request, approve or reject reviews of the current workspace")
+	fmt.Println("  sync       Sync the current workspace with the trunk")
+	fmt.Println("  resolve    Resolve conflicts of a sync in progress")
+	fmt.Println("  doctor     Diagnose problems with syncing, and write a support bundle")
+	fmt.Println("  version    Display Sturdy version information")
+	fmt.Println("  legal      Display legal credits")
 	os.Exit(1)
 }
 
@@ -48,9 +57,12 @@ func main() {
 		args = os.Args[2:]
 	}
 
+	// Only the leading --config flag is parsed here, other flags are parsed by the subcommands
+	globalArgs, subcommandArgs := splitGlobalFlags(args)
+
 	fs := flag.FlagSet{}
 	configPath := fs.String("config", path.Join(home, ".sturdy"), "Path to your Sturdy configuration file")
-	err = fs.Parse(args)
+	err = fs.Parse(globalArgs)
 	if err != nil {
 		log.Println("Failed to parse flags", err)
 		os.Exit(1)
@@ -58,7 +70,13 @@ func main() {
 	}
 
 	// Remaining arguments after flags have been parsed
-	args = fs.Args()
+	args = append(fs.Args(), subcommandArgs...)
+
+	// The config is read by doctor, to be able to report problems with it
+	if os.Args[1] == "doctor" {
+		doctor(*configPath, args)
+		return
+	}
 
 	conf, err := config.ReadConfig(*configPath)
 	if err != nil {
@@ -88,6 +106,10 @@ func main() {
 		apiClient, err := requireAuth(conf, *configPath)
 		exitIfErr(err)
 		startMutagen(*configPath, conf, apiClient)
+	case "upload":
+		apiClient, err := requireAuth(conf, *configPath)
+		exitIfErr(err)
+		upload(conf, args, apiClient)
 	case "stop":
 		stopMutagen(conf)
 	case "restart":
@@ -103,7 +125,47 @@ func main() {
 		apiClient, err := requireAuth(conf, *configPath)
 		exitIfErr(err)
 		importCodebase(conf, args, apiClient)
+	case "workspace":
+		apiClient, err := requireAuth(conf, *configPath)
+		exitIfErr(err)
+		workspace(conf, args, apiClient)
+	case "diff":
+		apiClient, err := requireAuth(conf, *configPath)
+		exitIfErr(err)
+		diff(conf, args, apiClient)
+	case "land":
+		apiClient, err := requireAuth(conf, *configPath)
+		exitIfErr(err)
+		land(conf, args, apiClient)
+	case "review":
+		apiClient, err := requireAuth(conf, *configPath)
+		exitIfErr(err)
+		review(conf, args, apiClient)
+	case "sync":
+		apiClient, err := requireAuth(conf, *configPath)
+		exitIfErr(err)
+		syncWorkspace(conf, args, apiClient)
+	case "resolve":
+		apiClient, err := requireAuth(conf, *configPath)
+		exitIfErr(err)
+		resolve(conf, args, apiClient)
 	default:
 		printHelpAndExit()
 	}
 }
+
+// splitGlobalFlags splits args into the leading --config flag, and the arguments of the subcommand
+func splitGlobalFlags(args []string) (global, rest []string) {
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "-config", args[i] == "--config":
+			if i+1 < len(args) {
+				i++
+			}
+		case strings.HasPrefix(args[i], "-config="), strings.HasPrefix(args[i], "--config="):
+		default:
+			return args[:i], args[i:]
+		}
+	}
+	return args, nil
+}
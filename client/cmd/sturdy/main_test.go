@@ -0,0 +1,26 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitGlobalFlags(t *testing.T) {
+	cases := []struct {
+		args   []string
+		global []string
+		rest   []string
+	}{
+		{args: nil, global: nil, rest: nil},
+		{args: []string{"--config", "conf", "a", "b"}, global: []string{"--config", "conf"}, rest: []string{"a", "b"}},
+		{args: []string{"-config=conf", "-m", "msg"}, global: []string{"-config=conf"}, rest: []string{"-m", "msg"}},
+		{args: []string{"-m", "msg", "--config", "conf"}, global: []string{}, rest: []string{"-m", "msg", "--config", "conf"}},
+	}
+
+	for _, tc := range cases {
+		global, rest := splitGlobalFlags(tc.args)
+		assert.Equal(t, tc.global, global)
+		assert.Equal(t, tc.rest, rest)
+	}
+}
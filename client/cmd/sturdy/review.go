@@ -0,0 +1,135 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"getsturdy.com/client/cmd/sturdy/config"
+	"getsturdy.com/client/pkg/api"
+)
+
+func printReviewHelpAndExit() {
+	fmt.Println("Review the open workspace, or the one given with --workspace")
+	fmt.Println("")
+	fmt.Println("Usage: sturdy review <command> [--workspace <workspace>] [--json]")
+	fmt.Println("")
+	fmt.Println("Available commands:")
+	fmt.Println("  request <user>...  Request a review, users can be referred to by their ID, email or name")
+	fmt.Println("  approve            Approve the workspace")
+	fmt.Println("  reject             Reject the workspace")
+	os.Exit(1)
+}
+
+func review(conf *config.Config, args []string, apiClient api.SturdyAPI) {
+	if len(args) < 1 {
+		printReviewHelpAndExit()
+	}
+
+	fs := flag.NewFlagSet("review "+args[0], flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Print the output as JSON")
+	target := fs.String("workspace", "", "The workspace to review (defaults to the open workspace)")
+	positional := parseInterspersed(fs, args[1:])
+
+	var grade api.ReviewGrade
+	switch args[0] {
+	case "request":
+		if len(positional) == 0 {
+			log.Fatalln("❌ Unexpected number of arguments, usage: sturdy review request <user>...")
+		}
+	case "approve", "reject":
+		if len(positional) > 0 {
+			log.Fatalf("❌ Unexpected number of arguments, usage: sturdy review %s\n", args[0])
+		}
+		grade = api.ReviewGradeApprove
+		if args[0] == "reject" {
+			grade = api.ReviewGradeReject
+		}
+	default:
+		printReviewHelpAndExit()
+	}
+
+	view, viewInfo := requireView(conf, apiClient)
+	gqlClient := api.NewGraphQLClient(conf)
+
+	ws, err := targetWorkspace(gqlClient, view.ID, viewInfo.CodebaseID, *target)
+	if err != nil {
+		log.Fatalf("❌ %s\n", err)
+	}
+
+	if grade != "" {
+		r, err := gqlClient.CreateOrUpdateReview(ws.ID, grade)
+		if err != nil {
+			log.Fatalf("❌ %s\n", err)
+		}
+		if *jsonOutput {
+			printJSON(r)
+			return
+		}
+		if grade == api.ReviewGradeApprove {
+			fmt.Printf("✅ Approved %s\n", workspaceDisplayName(ws))
+		} else {
+			fmt.Printf("✅ Rejected %s\n", workspaceDisplayName(ws))
+		}
+		return
+	}
+
+	members, err := gqlClient.CodebaseMembers(viewInfo.CodebaseID)
+	if err != nil {
+		log.Fatalf("❌ %s\n", err)
+	}
+	reviewers := make([]api.Author, 0, len(positional))
+	for _, user := range positional {
+		reviewer, err := matchMember(members, user)
+		if err != nil {
+			log.Fatalf("❌ %s\n", err)
+		}
+		reviewers = append(reviewers, reviewer)
+	}
+
+	requested := make([]api.Review, 0, len(reviewers))
+	for _, reviewer := range reviewers {
+		r, err := gqlClient.RequestReview(ws.ID, reviewer.ID)
+		if err != nil {
+			log.Fatalf("❌ %s\n", err)
+		}
+		requested = append(requested, r)
+		if !*jsonOutput {
+			fmt.Printf("✅ Requested a review of %s from %s\n", workspaceDisplayName(ws), reviewer.Name)
+		}
+	}
+	if *jsonOutput {
+		printJSON(requested)
+	}
+}
+
+// matchMember finds a codebase member by their ID, email or name, emails and names are matched case insensitively
+func matchMember(members []api.Author, user string) (api.Author, error) {
+	for _, m := range members {
+		if m.ID == user {
+			return m, nil
+		}
+	}
+	for _, m := range members {
+		if m.Email != "" && strings.EqualFold(m.Email, user) {
+			return m, nil
+		}
+	}
+
+	var matches []api.Author
+	for _, m := range members {
+		if strings.EqualFold(m.Name, user) {
+			matches = append(matches, m)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return api.Author{}, fmt.Errorf("could not find a member of the codebase with the ID, email or name %q", user)
+	case 1:
+		return matches[0], nil
+	default:
+		return api.Author{}, fmt.Errorf("there are %d members named %q, use their email instead", len(matches), user)
+	}
+}
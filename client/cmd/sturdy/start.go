@@ -17,6 +17,7 @@ import (
 	"getsturdy.com/client/cmd/sturdy/config"
 	"getsturdy.com/client/pkg/api"
 	"getsturdy.com/client/pkg/edkey"
+	"getsturdy.com/client/pkg/filesync"
 	"getsturdy.com/client/pkg/mutagen"
 
 	"golang.org/x/crypto/ed25519"
@@ -240,14 +241,7 @@ func terminateMutagenForView(view config.ViewConfig) error {
 
 // hostWithOptionalPort is on format "host" or "host:1234"
 func ensureKnownHosts(hostWithOptionalPort string) error {
-	var port = "22"
-	var host = hostWithOptionalPort
-
-	parts := strings.Split(hostWithOptionalPort, ":")
-	if len(parts) > 1 {
-		host = parts[0]
-		port = parts[1]
-	}
+	host, port := syncHostPort(hostWithOptionalPort)
 
 	cmd := exec.Command("ssh-keyscan", "-p", port, host)
 	output, err := cmd.Output()
@@ -298,6 +292,15 @@ func ensureKnownHosts(hostWithOptionalPort string) error {
 	return nil
 }
 
+// syncHostPort splits hostWithOptionalPort, on format "host" or "host:1234", into the host and the SSH port
+func syncHostPort(hostWithOptionalPort string) (host, port string) {
+	parts := strings.Split(hostWithOptionalPort, ":")
+	if len(parts) > 1 {
+		return parts[0], parts[1]
+	}
+	return hostWithOptionalPort, "22"
+}
+
 func addToKnownHosts(trustRows []string, existingKnownHosts []byte, lineSeparator string) []byte {
 	knownHosts := bytes.TrimSpace(existingKnownHosts)
 	if len(knownHosts) > 0 {
@@ -341,7 +344,7 @@ type mutagenSyncEndpointIgnoreConfig struct {
 }
 
 func configPathForView(privateKeyPath, sturdyAgentDir string, view config.ViewConfig, ignores []string) (string, error) {
-	ignores = append(ignores, "node_modules", ".DS_Store", "*.swp")
+	ignores = append(ignores, filesync.DefaultIgnores...)
 	conf := mutagenConfig{
 		Sync: mutagenSyncConfig{
 			Defaults: mutagenSyncEndpointConfig{
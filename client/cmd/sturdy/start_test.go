@@ -65,3 +65,13 @@ func TestAddToKnownHosts(t *testing.T) {
 		})
 	}
 }
+
+func TestSyncHostPort(t *testing.T) {
+	host, port := syncHostPort("sync.getsturdy.com")
+	assert.Equal(t, "sync.getsturdy.com", host)
+	assert.Equal(t, "22", port)
+
+	host, port = syncHostPort("localhost:2222")
+	assert.Equal(t, "localhost", host)
+	assert.Equal(t, "2222", port)
+}
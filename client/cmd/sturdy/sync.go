@@ -0,0 +1,292 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"getsturdy.com/client/cmd/sturdy/config"
+	"getsturdy.com/client/pkg/api"
+	"getsturdy.com/client/pkg/mutagen"
+)
+
+type syncOutput struct {
+	api.RebaseStatusResponse
+	// Resolutions maps the paths of the conflicting files that have been resolved to the version they are resolved with
+	Resolutions map[string]string `json:"resolutions"`
+}
+
+func syncWorkspace(conf *config.Config, args []string, apiClient api.SturdyAPI) {
+	fs := flag.NewFlagSet("sync", flag.ExitOnError)
+	cont := fs.Bool("continue", false, "Complete the sync once all conflicts have been resolved")
+	jsonOutput := fs.Bool("json", false, "Print the output as JSON")
+	if positional := parseInterspersed(fs, args); len(positional) > 0 {
+		log.Fatalln("❌ Unexpected number of arguments, usage: sturdy sync [--continue] [--json]")
+	}
+
+	view, _ := requireView(conf, apiClient)
+
+	status, err := apiClient.GetSyncStatus(view.ID)
+	if err != nil {
+		log.Fatalf("❌ %s\n", err)
+	}
+
+	if *cont {
+		continueSync(view, status, apiClient, *jsonOutput)
+		return
+	}
+
+	if status.IsRebasing {
+		// A sync is already in progress, show what is left to do
+		resolutions, err := readResolutions(view.ID)
+		if err != nil {
+			log.Fatalf("❌ %s\n", err)
+		}
+		printSyncStatus(status, resolutions, *jsonOutput)
+		return
+	}
+
+	ws, err := api.NewGraphQLClient(conf).GetViewWorkspace(view.ID)
+	if err != nil {
+		log.Fatalf("❌ %s\n", err)
+	}
+	if ws == nil {
+		log.Fatalln("❌ No workspace is open in this directory")
+	}
+
+	// Resolutions from earlier syncs are never valid for a new one
+	if err := writeResolutions(view.ID, nil); err != nil {
+		log.Fatalf("❌ %s\n", err)
+	}
+
+	if status, err = apiClient.StartSync(view.ID, ws.ID); err != nil {
+		log.Fatalf("❌ %s\n", err)
+	}
+	printSyncStatus(status, nil, *jsonOutput)
+}
+
+func continueSync(view config.ViewConfig, status api.RebaseStatusResponse, apiClient api.SturdyAPI, jsonOutput bool) {
+	if !status.IsRebasing {
+		log.Fatalln("❌ There is no sync in progress")
+	}
+
+	resolutions, err := readResolutions(view.ID)
+	if err != nil {
+		log.Fatalf("❌ %s\n", err)
+	}
+	if unresolved := unresolvedFiles(status, resolutions); len(unresolved) > 0 {
+		fmt.Println("❌ Not all conflicts have been resolved, resolve them with 'sturdy resolve <file> --ours|--theirs|--custom'")
+		for _, p := range unresolved {
+			fmt.Printf("   %s\n", p)
+		}
+		os.Exit(1)
+	}
+
+	files := make([]api.ResolveFile, 0, len(status.ConflictingFiles))
+	hasCustom := false
+	for _, f := range status.ConflictingFiles {
+		version := resolutions[f.Path]
+		hasCustom = hasCustom || version == api.ResolveVersionCustom
+		files = append(files, api.ResolveFile{FilePath: f.Path, Version: version})
+	}
+
+	// Custom resolutions are the files as they are on disk, make sure that the local edits have reached Sturdy
+	if hasCustom {
+		if _, err := mutagen.RunMutagenCommandWithRestart("sync", "flush", viewMutagenName(view)); err != nil {
+			fmt.Printf("⚠️  Could not make sure that all local changes have been synced: %s\n", err)
+		}
+	}
+
+	res, err := apiClient.ResolveSync(view.ID, files)
+	if err != nil {
+		log.Fatalf("❌ %s\n", err)
+	}
+	if err := writeResolutions(view.ID, nil); err != nil {
+		log.Fatalf("❌ %s\n", err)
+	}
+	printSyncStatus(res, nil, jsonOutput)
+}
+
+func resolve(conf *config.Config, args []string, apiClient api.SturdyAPI) {
+	fs := flag.NewFlagSet("resolve", flag.ExitOnError)
+	ours := fs.Bool("ours", false, "Resolve with the version from your workspace")
+	theirs := fs.Bool("theirs", false, "Resolve with the version from the trunk")
+	custom := fs.Bool("custom", false, "Resolve with the file as it is on disk, for example after editing it or using a merge tool")
+	positional := parseInterspersed(fs, args)
+
+	var version string
+	selected := 0
+	for v, ok := range map[string]bool{
+		api.ResolveVersionWorkspace: *ours,
+		api.ResolveVersionTrunk:     *theirs,
+		api.ResolveVersionCustom:    *custom,
+	} {
+		if ok {
+			version = v
+			selected++
+		}
+	}
+	if len(positional) == 0 || selected != 1 {
+		log.Fatalln("❌ Unexpected arguments, usage: sturdy resolve <file>... --ours|--theirs|--custom")
+	}
+
+	view, _ := requireView(conf, apiClient)
+
+	status, err := apiClient.GetSyncStatus(view.ID)
+	if err != nil {
+		log.Fatalf("❌ %s\n", err)
+	}
+	if !status.IsRebasing {
+		log.Fatalln("❌ There is no sync in progress, start one with 'sturdy sync'")
+	}
+
+	resolutions, err := readResolutions(view.ID)
+	if err != nil {
+		log.Fatalf("❌ %s\n", err)
+	}
+	if resolutions == nil {
+		resolutions = make(map[string]string)
+	}
+
+	for _, arg := range positional {
+		p, err := conflictPath(view, arg, status.ConflictingFiles)
+		if err != nil {
+			log.Fatalf("❌ %s\n", err)
+		}
+		resolutions[p] = version
+	}
+
+	if err := writeResolutions(view.ID, resolutions); err != nil {
+		log.Fatalf("❌ %s\n", err)
+	}
+
+	printSyncStatus(status, resolutions, false)
+}
+
+func printSyncStatus(status api.RebaseStatusResponse, resolutions map[string]string, jsonOutput bool) {
+	if jsonOutput {
+		printJSON(syncOutput{RebaseStatusResponse: status, Resolutions: resolutions})
+		return
+	}
+
+	if !status.IsRebasing && !status.HaveConflicts {
+		fmt.Println("✅ The workspace is synced with the trunk")
+		return
+	}
+
+	fmt.Println("⚠️  The sync has conflicts, resolve them with 'sturdy resolve <file> --ours|--theirs|--custom'")
+	for _, f := range status.ConflictingFiles {
+		if version, ok := resolutions[f.Path]; ok {
+			fmt.Printf("   ✅ %s (%s)\n", f.Path, versionDisplayName(version))
+		} else {
+			fmt.Printf("   ❌ %s\n", f.Path)
+		}
+	}
+
+	if len(unresolvedFiles(status, resolutions)) == 0 {
+		fmt.Println("All conflicts are resolved, complete the sync with 'sturdy sync --continue'")
+	}
+}
+
+func versionDisplayName(version string) string {
+	switch version {
+	case api.ResolveVersionWorkspace:
+		return "ours"
+	case api.ResolveVersionTrunk:
+		return "theirs"
+	default:
+		return version
+	}
+}
+
+func unresolvedFiles(status api.RebaseStatusResponse, resolutions map[string]string) []string {
+	var unresolved []string
+	for _, f := range status.ConflictingFiles {
+		if _, ok := resolutions[f.Path]; !ok {
+			unresolved = append(unresolved, f.Path)
+		}
+	}
+	sort.Strings(unresolved)
+	return unresolved
+}
+
+// conflictPath returns the path of the conflicting file that arg refers to. arg can either be relative to the
+// working directory, or to the root of the view.
+func conflictPath(view config.ViewConfig, arg string, conflicts []api.ConflictingFile) (string, error) {
+	candidates := []string{filepath.ToSlash(filepath.Clean(arg))}
+	if abs, err := absPath(arg); err == nil {
+		if viewPath, err := absPath(view.Path); err == nil {
+			if rel, err := filepath.Rel(viewPath, abs); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+				candidates = append([]string{filepath.ToSlash(rel)}, candidates...)
+			}
+		}
+	}
+
+	for _, candidate := range candidates {
+		for _, f := range conflicts {
+			if f.Path == candidate {
+				return f.Path, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("%s is not conflicting", arg)
+}
+
+func resolutionsPath(viewID string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to lookup home dir: %w", err)
+	}
+	return filepath.Join(homeDir, ".sturdy-resolutions", viewID+".json"), nil
+}
+
+// readResolutions returns the resolutions that have been made for the sync in progress in the view
+func readResolutions(viewID string) (map[string]string, error) {
+	p, err := resolutionsPath(viewID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read resolutions: %w", err)
+	}
+	var resolutions map[string]string
+	if err := json.Unmarshal(data, &resolutions); err != nil {
+		return nil, fmt.Errorf("failed to parse resolutions: %w", err)
+	}
+	return resolutions, nil
+}
+
+// writeResolutions saves the resolutions for the sync in progress in the view, nil removes them
+func writeResolutions(viewID string, resolutions map[string]string) error {
+	p, err := resolutionsPath(viewID)
+	if err != nil {
+		return err
+	}
+	if resolutions == nil {
+		if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove resolutions: %w", err)
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o744); err != nil {
+		return fmt.Errorf("could not create .sturdy-resolutions dir: %w", err)
+	}
+	data, err := json.MarshalIndent(resolutions, "", "    ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(p, data, 0o644); err != nil {
+		return fmt.Errorf("failed to save resolutions: %w", err)
+	}
+	return nil
+}
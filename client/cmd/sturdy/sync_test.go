@@ -0,0 +1,53 @@
+package main
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"getsturdy.com/client/cmd/sturdy/config"
+	"getsturdy.com/client/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConflictPath(t *testing.T) {
+	tmpDir := t.TempDir()
+	view := config.ViewConfig{ID: "view", Path: tmpDir}
+	conflicts := []api.ConflictingFile{{Path: "README.md"}, {Path: "src/main.go"}}
+
+	nested := path.Join(tmpDir, "src")
+	assert.NoError(t, os.MkdirAll(nested, 0o777))
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	assert.NoError(t, os.Chdir(nested))
+	defer os.Chdir(wd)
+
+	// relative to the working directory
+	p, err := conflictPath(view, "main.go", conflicts)
+	assert.NoError(t, err)
+	assert.Equal(t, "src/main.go", p)
+
+	p, err = conflictPath(view, "../README.md", conflicts)
+	assert.NoError(t, err)
+	assert.Equal(t, "README.md", p)
+
+	// relative to the root of the view
+	p, err = conflictPath(view, "src/main.go", conflicts)
+	assert.NoError(t, err)
+	assert.Equal(t, "src/main.go", p)
+
+	_, err = conflictPath(view, "other.go", conflicts)
+	assert.Error(t, err)
+}
+
+func TestUnresolvedFiles(t *testing.T) {
+	status := api.RebaseStatusResponse{
+		IsRebasing:       true,
+		HaveConflicts:    true,
+		ConflictingFiles: []api.ConflictingFile{{Path: "b"}, {Path: "a"}, {Path: "c"}},
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, unresolvedFiles(status, nil))
+	assert.Equal(t, []string{"c"}, unresolvedFiles(status, map[string]string{"a": api.ResolveVersionTrunk, "b": api.ResolveVersionCustom}))
+	assert.Empty(t, unresolvedFiles(status, map[string]string{"a": "trunk", "b": "trunk", "c": "workspace"}))
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+
+	"getsturdy.com/client/cmd/sturdy/config"
+	"getsturdy.com/client/pkg/api"
+	"getsturdy.com/client/pkg/filesync"
+)
+
+// upload uploads local changes of all views without the sturdy-sync daemon, until it's interrupted. Uploading is
+// one-way, changes made on Sturdy are not downloaded.
+func upload(conf *config.Config, args []string, apiClient *api.HttpApiClient) {
+	fs := flag.NewFlagSet("upload", flag.ExitOnError)
+	if positional := parseInterspersed(fs, args); len(positional) > 0 {
+		log.Fatalln("❌ Unexpected number of arguments, usage: sturdy upload")
+	}
+
+	if len(conf.Views) == 0 {
+		fmt.Println("You don't have any codebases configured. Go to https://getsturdy.com to get started!")
+		os.Exit(0)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	stateDir, err := mutagenSturdyAgentDirPath()
+	if err != nil {
+		log.Fatalf("❌ %s\n", err)
+	}
+
+	logger := log.New(os.Stdout, "", log.Ltime)
+
+	var wg sync.WaitGroup
+	for _, view := range conf.Views {
+		apiView, err := apiClient.GetView(view.ID)
+		if errors.Is(err, api.ErrUnauthorized) {
+			fmt.Printf("⚠️  Skipping %s (you don't have access to the codebase)\n", view.Path)
+			continue
+		}
+		if err != nil {
+			log.Fatalf("❌ %s\n", err)
+		}
+		if apiView.CodebaseIsArchived {
+			fmt.Printf("⚠️  Skipping %s (the codebase has been archived)\n", view.Path)
+			continue
+		}
+
+		engine := filesync.New(apiClient, view.ID, view.Path, logger)
+		engine.StatePath = filepath.Join(stateDir, view.ID+".uploaded.json")
+
+		wg.Add(1)
+		go func(view config.ViewConfig) {
+			defer wg.Done()
+			if err := engine.Run(ctx); err != nil {
+				logger.Printf("❌ Stopped uploading %s: %s", view.Path, err)
+			}
+		}(view)
+
+		fmt.Printf("👀 Uploading changes in %s\n", view.Path)
+	}
+
+	fmt.Println("Uploading local changes to Sturdy, changes made on Sturdy are not downloaded. Press Ctrl+C to stop")
+	wg.Wait()
+}
@@ -0,0 +1,338 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"getsturdy.com/client/cmd/sturdy/config"
+	"getsturdy.com/client/pkg/api"
+)
+
+func printWorkspaceHelpAndExit() {
+	fmt.Println("Manage the workspaces of the codebase in the current directory")
+	fmt.Println("")
+	fmt.Println("Usage: sturdy workspace <command> [--json]")
+	fmt.Println("")
+	fmt.Println("Available commands:")
+	fmt.Println("  list                      List workspaces, the open workspace is marked with *")
+	fmt.Println("  create [name]             Create a new workspace and open it (use --no-switch to keep the current one)")
+	fmt.Println("  switch <workspace>        Open a workspace in this directory")
+	fmt.Println("  archive [workspace]       Archive a workspace (defaults to the open workspace)")
+	fmt.Println("  rename <name>             Rename the open workspace (or the one given with --workspace)")
+	fmt.Println("")
+	fmt.Println("Workspaces can be referred to by their ID or their name")
+	os.Exit(1)
+}
+
+type workspaceOutput struct {
+	api.Workspace
+	Current bool `json:"current"`
+}
+
+func workspace(conf *config.Config, args []string, apiClient api.SturdyAPI) {
+	if len(args) < 1 {
+		printWorkspaceHelpAndExit()
+	}
+
+	view, viewInfo := requireView(conf, apiClient)
+	gqlClient := api.NewGraphQLClient(conf)
+
+	fs := flag.NewFlagSet("workspace "+args[0], flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Print the output as JSON")
+
+	switch args[0] {
+	case "list":
+		all := fs.Bool("all", false, "Include archived workspaces")
+		parseInterspersed(fs, args[1:])
+
+		list, err := gqlClient.ListWorkspaces(viewInfo.CodebaseID, *all)
+		if err != nil {
+			log.Fatalf("❌ %s\n", err)
+		}
+		current, err := gqlClient.GetViewWorkspace(view.ID)
+		if err != nil {
+			log.Fatalf("❌ %s\n", err)
+		}
+
+		out := make([]workspaceOutput, 0, len(list))
+		for _, ws := range list {
+			out = append(out, workspaceOutput{Workspace: ws, Current: current != nil && current.ID == ws.ID})
+		}
+
+		if *jsonOutput {
+			printJSON(out)
+			return
+		}
+		printWorkspaces(out)
+
+	case "create":
+		noSwitch := fs.Bool("no-switch", false, "Do not open the new workspace in this directory")
+		positional := parseInterspersed(fs, args[1:])
+		if len(positional) > 1 {
+			log.Fatalln("❌ Unexpected number of arguments, the name can be given as a single quoted argument")
+		}
+
+		ws, err := gqlClient.CreateWorkspace(viewInfo.CodebaseID)
+		if err != nil {
+			log.Fatalf("❌ %s\n", err)
+		}
+		if len(positional) == 1 {
+			if ws, err = gqlClient.UpdateWorkspace(ws.ID, &positional[0], nil); err != nil {
+				log.Fatalf("❌ %s\n", err)
+			}
+		}
+		if !*noSwitch {
+			if err := gqlClient.OpenWorkspaceOnView(view.ID, ws.ID); err != nil {
+				log.Fatalf("❌ %s\n", err)
+			}
+		}
+
+		if *jsonOutput {
+			printJSON(workspaceOutput{Workspace: ws, Current: !*noSwitch})
+			return
+		}
+		if *noSwitch {
+			fmt.Printf("✅ Created %s\n", workspaceDisplayName(ws))
+		} else {
+			fmt.Printf("✅ Created %s and opened it in %s\n", workspaceDisplayName(ws), view.Path)
+		}
+
+	case "switch":
+		positional := parseInterspersed(fs, args[1:])
+		if len(positional) != 1 {
+			log.Fatalln("❌ Unexpected number of arguments, usage: sturdy workspace switch <workspace>")
+		}
+
+		ws, err := findWorkspace(gqlClient, viewInfo.CodebaseID, positional[0])
+		if err != nil {
+			log.Fatalf("❌ %s\n", err)
+		}
+		if err := gqlClient.OpenWorkspaceOnView(view.ID, ws.ID); err != nil {
+			log.Fatalf("❌ %s\n", err)
+		}
+
+		if *jsonOutput {
+			printJSON(workspaceOutput{Workspace: ws, Current: true})
+			return
+		}
+		fmt.Printf("✅ Opened %s in %s\n", workspaceDisplayName(ws), view.Path)
+
+	case "archive":
+		positional := parseInterspersed(fs, args[1:])
+		if len(positional) > 1 {
+			log.Fatalln("❌ Unexpected number of arguments, usage: sturdy workspace archive [workspace]")
+		}
+
+		var idOrName string
+		if len(positional) == 1 {
+			idOrName = positional[0]
+		}
+		ws, err := targetWorkspace(gqlClient, view.ID, viewInfo.CodebaseID, idOrName)
+		if err != nil {
+			log.Fatalf("❌ %s\n", err)
+		}
+		if ws, err = gqlClient.ArchiveWorkspace(ws.ID); err != nil {
+			log.Fatalf("❌ %s\n", err)
+		}
+
+		if *jsonOutput {
+			printJSON(workspaceOutput{Workspace: ws})
+			return
+		}
+		fmt.Printf("✅ Archived %s\n", workspaceDisplayName(ws))
+
+	case "rename":
+		target := fs.String("workspace", "", "The workspace to rename (defaults to the open workspace)")
+		positional := parseInterspersed(fs, args[1:])
+		if len(positional) != 1 {
+			log.Fatalln("❌ Unexpected number of arguments, usage: sturdy workspace rename <name>")
+		}
+
+		ws, err := targetWorkspace(gqlClient, view.ID, viewInfo.CodebaseID, *target)
+		if err != nil {
+			log.Fatalf("❌ %s\n", err)
+		}
+		oldName := workspaceDisplayName(ws)
+		if ws, err = gqlClient.UpdateWorkspace(ws.ID, &positional[0], nil); err != nil {
+			log.Fatalf("❌ %s\n", err)
+		}
+
+		if *jsonOutput {
+			printJSON(workspaceOutput{Workspace: ws})
+			return
+		}
+		fmt.Printf("✅ Renamed %s to %s\n", oldName, workspaceDisplayName(ws))
+
+	default:
+		printWorkspaceHelpAndExit()
+	}
+}
+
+// requireView returns the view of the working directory together with its information from the API, and exits if
+// there is no such view
+func requireView(conf *config.Config, apiClient api.SturdyAPI) (config.ViewConfig, api.View) {
+	view, err := currentView(conf)
+	if err != nil {
+		log.Fatalf("❌ %s\n", err)
+	}
+	viewInfo, err := apiClient.GetView(view.ID)
+	if err != nil {
+		log.Fatalf("❌ %s\n", err)
+	}
+	return view, viewInfo
+}
+
+// currentView returns the view that the working directory is in. If the working directory is not in a view, and
+// there is only one view configured, that view is used.
+func currentView(conf *config.Config) (config.ViewConfig, error) {
+	if len(conf.Views) == 0 {
+		return config.ViewConfig{}, errors.New("no codebases are configured on this computer, run 'sturdy init' first")
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return config.ViewConfig{}, fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	if view, ok := viewForPath(wd, conf.Views); ok {
+		return view, nil
+	}
+	if len(conf.Views) == 1 {
+		return conf.Views[0], nil
+	}
+
+	paths := make([]string, 0, len(conf.Views))
+	for _, v := range conf.Views {
+		paths = append(paths, v.Path)
+	}
+	return config.ViewConfig{}, fmt.Errorf("%s is not inside a Sturdy directory, run the command from one of: %s", wd, strings.Join(paths, ", "))
+}
+
+// viewForPath returns the view that contains p
+func viewForPath(p string, views []config.ViewConfig) (config.ViewConfig, bool) {
+	p, _ = absPath(p)
+	for _, view := range views {
+		viewPath, err := absPath(view.Path)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(viewPath, p)
+		if err != nil {
+			continue
+		}
+		if rel != ".." && !strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+			return view, true
+		}
+	}
+	return config.ViewConfig{}, false
+}
+
+// targetWorkspace returns the workspace referred to by idOrName, or the workspace that is open on the view if idOrName is empty
+func targetWorkspace(gqlClient *api.GraphQLClient, viewID, codebaseID, idOrName string) (api.Workspace, error) {
+	if idOrName != "" {
+		return findWorkspace(gqlClient, codebaseID, idOrName)
+	}
+	ws, err := gqlClient.GetViewWorkspace(viewID)
+	if err != nil {
+		return api.Workspace{}, err
+	}
+	if ws == nil {
+		return api.Workspace{}, errors.New("no workspace is open in this directory")
+	}
+	return *ws, nil
+}
+
+func findWorkspace(gqlClient *api.GraphQLClient, codebaseID, idOrName string) (api.Workspace, error) {
+	list, err := gqlClient.ListWorkspaces(codebaseID, false)
+	if err != nil {
+		return api.Workspace{}, err
+	}
+	return matchWorkspace(list, idOrName)
+}
+
+// matchWorkspace finds a workspace by its ID, or by its name if the name is unique
+func matchWorkspace(list []api.Workspace, idOrName string) (api.Workspace, error) {
+	for _, ws := range list {
+		if ws.ID == idOrName {
+			return ws, nil
+		}
+	}
+
+	var matches []api.Workspace
+	for _, ws := range list {
+		if ws.Name == idOrName {
+			matches = append(matches, ws)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return api.Workspace{}, fmt.Errorf("could not find a workspace with the ID or name %q", idOrName)
+	case 1:
+		return matches[0], nil
+	default:
+		ids := make([]string, 0, len(matches))
+		for _, ws := range matches {
+			ids = append(ids, ws.ID)
+		}
+		return api.Workspace{}, fmt.Errorf("there are %d workspaces named %q, use the ID instead: %s", len(matches), idOrName, strings.Join(ids, ", "))
+	}
+}
+
+// parseInterspersed parses flags that are mixed with positional arguments, and returns the positional arguments
+func parseInterspersed(fs *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		// ExitOnError
+		_ = fs.Parse(args)
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func workspaceDisplayName(ws api.Workspace) string {
+	return fmt.Sprintf("%q (%s)", ws.Name, ws.ID)
+}
+
+func printWorkspaces(list []workspaceOutput) {
+	if len(list) == 0 {
+		fmt.Println("No workspaces found, create one with 'sturdy workspace create'")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\tNAME\tID\tAUTHOR\tLAST ACTIVITY")
+	for _, ws := range list {
+		marker := ""
+		if ws.Current {
+			marker = "*"
+		}
+		name := ws.Name
+		if ws.ArchivedAt != nil {
+			name += " (archived)"
+		}
+		lastActivity := time.Unix(int64(ws.LastActivityAt), 0).Format("2006-01-02 15:04")
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", marker, name, ws.ID, ws.Author.Name, lastActivity)
+	}
+	_ = w.Flush()
+}
+
+func printJSON(v interface{}) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		log.Fatalf("❌ Failed to encode output: %s\n", err)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"path"
+	"testing"
+
+	"getsturdy.com/client/cmd/sturdy/config"
+	"getsturdy.com/client/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestViewForPath(t *testing.T) {
+	tmpDir := t.TempDir()
+	views := []config.ViewConfig{
+		{ID: "a", Path: path.Join(tmpDir, "a")},
+		{ID: "b", Path: path.Join(tmpDir, "b")},
+	}
+
+	view, ok := viewForPath(path.Join(tmpDir, "a"), views)
+	assert.True(t, ok)
+	assert.Equal(t, "a", view.ID)
+
+	view, ok = viewForPath(path.Join(tmpDir, "b", "nested", "dir"), views)
+	assert.True(t, ok)
+	assert.Equal(t, "b", view.ID)
+
+	_, ok = viewForPath(tmpDir, views)
+	assert.False(t, ok)
+
+	_, ok = viewForPath(path.Join(tmpDir, "ab"), views)
+	assert.False(t, ok)
+}
+
+func TestMatchWorkspace(t *testing.T) {
+	list := []api.Workspace{
+		{ID: "1", Name: "feature"},
+		{ID: "2", Name: "bugfix"},
+		{ID: "3", Name: "bugfix"},
+		{ID: "4", Name: "1"},
+	}
+
+	ws, err := matchWorkspace(list, "feature")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", ws.ID)
+
+	// IDs take precedence over names
+	ws, err = matchWorkspace(list, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, "feature", ws.Name)
+
+	_, err = matchWorkspace(list, "bugfix")
+	assert.Error(t, err)
+
+	_, err = matchWorkspace(list, "missing")
+	assert.Error(t, err)
+}
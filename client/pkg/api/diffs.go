@@ -0,0 +1,49 @@
+package api
+
+import (
+	"fmt"
+)
+
+type Hunk struct {
+	HunkID string `json:"hunkID"`
+	Patch  string `json:"patch"`
+}
+
+type FileDiff struct {
+	OrigName      string `json:"origName"`
+	NewName       string `json:"newName"`
+	PreferredName string `json:"preferredName"`
+	IsDeleted     bool   `json:"isDeleted"`
+	IsNew         bool   `json:"isNew"`
+	IsMoved       bool   `json:"isMoved"`
+	IsLarge       bool   `json:"isLarge"`
+	IsHidden      bool   `json:"isHidden"`
+	Hunks         []Hunk `json:"hunks"`
+}
+
+func (g *GraphQLClient) WorkspaceDiffs(workspaceID string) ([]FileDiff, error) {
+	var res struct {
+		Workspace struct {
+			Diffs []FileDiff `json:"diffs"`
+		} `json:"workspace"`
+	}
+	err := g.Do(`query WorkspaceDiffs($id: ID!) {
+		workspace(id: $id) {
+			diffs {
+				origName
+				newName
+				preferredName
+				isDeleted
+				isNew
+				isMoved
+				isLarge
+				isHidden
+				hunks { hunkID patch }
+			}
+		}
+	}`, map[string]interface{}{"id": workspaceID}, &res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get diffs: %w", err)
+	}
+	return res.Workspace.Diffs, nil
+}
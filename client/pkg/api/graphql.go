@@ -0,0 +1,130 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"getsturdy.com/client/cmd/sturdy/config"
+)
+
+// Errors returned by the GraphQL API, see api/pkg/graphql/errors.
+var (
+	ErrNotFound        = errors.New("NotFoundError")
+	ErrBadRequest      = errors.New("BadRequestError")
+	ErrInternalServer  = errors.New("InternalServerError")
+	ErrForbidden       = errors.New("ForbiddenError")
+	ErrUnauthenticated = errors.New("UnauthenticatedError")
+	ErrNotImplemented  = errors.New("NotImplementedError")
+)
+
+type GraphQLError struct {
+	Message    string                 `json:"message"`
+	Path       []interface{}          `json:"path"`
+	Extensions map[string]interface{} `json:"extensions"`
+}
+
+func (e GraphQLError) Error() string {
+	if len(e.Extensions) == 0 {
+		return e.Message
+	}
+	keys := make([]string, 0, len(e.Extensions))
+	for k := range e.Extensions {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	details := make([]string, 0, len(keys))
+	for _, k := range keys {
+		details = append(details, fmt.Sprintf("%s: %v", k, e.Extensions[k]))
+	}
+	return fmt.Sprintf("%s (%s)", e.Message, strings.Join(details, ", "))
+}
+
+func (e GraphQLError) Is(target error) bool {
+	//nolint:errorlint
+	return target != nil && e.Message == target.Error()
+}
+
+// Reason returns the machine readable reason of the error, for example "UnhealthyWorkspace", if the API provided one.
+func (e GraphQLError) Reason() string {
+	reason, _ := e.Extensions["reason"].(string)
+	return reason
+}
+
+// Detail returns the human readable message of the error, if the API provided one, and the error type otherwise.
+func (e GraphQLError) Detail() string {
+	if msg, ok := e.Extensions["message"].(string); ok && msg != "" {
+		return msg
+	}
+	return e.Message
+}
+
+// AsGraphQLError returns the first GraphQL error in the chain of err.
+func AsGraphQLError(err error) (GraphQLError, bool) {
+	var gqlErrs GraphQLErrors
+	if errors.As(err, &gqlErrs) && len(gqlErrs) > 0 {
+		return gqlErrs[0], true
+	}
+	return GraphQLError{}, false
+}
+
+type GraphQLErrors []GraphQLError
+
+func (e GraphQLErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e GraphQLErrors) Is(target error) bool {
+	for _, err := range e {
+		if err.Is(target) {
+			return true
+		}
+	}
+	return false
+}
+
+type GraphQLClient struct {
+	host      string
+	authToken string
+}
+
+func NewGraphQLClient(c *config.Config) *GraphQLClient {
+	return &GraphQLClient{
+		host:      c.APIRemote,
+		authToken: c.Auth,
+	}
+}
+
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors GraphQLErrors   `json:"errors"`
+}
+
+// Do runs the query with the given variables, and decodes the data of the response into data.
+func (g *GraphQLClient) Do(query string, variables map[string]interface{}, data interface{}) error {
+	var res graphQLResponse
+	if err := Request(g.host, "POST", "/graphql", g.authToken, graphQLRequest{Query: query, Variables: variables}, &res); err != nil {
+		return err
+	}
+	if len(res.Errors) > 0 {
+		return res.Errors
+	}
+	if data == nil || len(res.Data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(res.Data, data); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
@@ -133,3 +133,31 @@ func (h *HttpApiClient) GetIgnores(viewID string) (GetIgnoresResponse, error) {
 	}
 	return res, nil
 }
+
+type FileChange struct {
+	Path       string `json:"path"`
+	Contents   []byte `json:"contents,omitempty"`
+	Executable bool   `json:"executable,omitempty"`
+	Deleted    bool   `json:"deleted,omitempty"`
+}
+
+// WriteFiles uploads changes of files in the view, it's used to sync views without mutagen
+func (h *HttpApiClient) WriteFiles(viewID string, files []FileChange) error {
+	type writeFilesRequest struct {
+		Files []FileChange `json:"files"`
+	}
+
+	var res struct{}
+
+	err := Request(
+		h.host,
+		"POST", fmt.Sprintf("/v3/views/%s/files", viewID),
+		h.authToken,
+		writeFilesRequest{Files: files},
+		&res,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upload files: %w", err)
+	}
+	return nil
+}
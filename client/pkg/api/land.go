@@ -0,0 +1,59 @@
+package api
+
+import (
+	"fmt"
+)
+
+// Reasons of the errors returned by LandWorkspaceChange
+const (
+	ReasonUnhealthyWorkspace   = "UnhealthyWorkspace"
+	ReasonStackedWorkspace     = "StackedWorkspace"
+	ReasonMissingOwnerApproval = "MissingOwnerApproval"
+	ReasonTooFewApprovals      = "TooFewApprovals"
+	ReasonRejected             = "Rejected"
+	ReasonUnresolvedComments   = "UnresolvedComments"
+	ReasonOutdatedWorkspace    = "OutdatedWorkspace"
+	ReasonRestrictedLanding    = "RestrictedLanding"
+)
+
+type WorkspaceStatus struct {
+	Type        string  `json:"type"`
+	Title       string  `json:"title"`
+	Description *string `json:"description"`
+	DetailsURL  *string `json:"detailsUrl"`
+	Stale       bool    `json:"stale"`
+}
+
+func (g *GraphQLClient) WorkspaceStatuses(workspaceID string) ([]WorkspaceStatus, error) {
+	var res struct {
+		Workspace struct {
+			Statuses []WorkspaceStatus `json:"statuses"`
+		} `json:"workspace"`
+	}
+	err := g.Do(`query WorkspaceStatuses($id: ID!) {
+		workspace(id: $id) {
+			statuses { type title description detailsUrl stale }
+		}
+	}`, map[string]interface{}{"id": workspaceID}, &res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statuses: %w", err)
+	}
+	return res.Workspace.Statuses, nil
+}
+
+// LandWorkspaceChange lands all changes in the workspace as a single change, using the draft description of the
+// workspace as the message. Use AsGraphQLError and the Reason* constants to find out why a workspace could not be landed.
+func (g *GraphQLClient) LandWorkspaceChange(workspaceID string) (Workspace, error) {
+	var res struct {
+		LandWorkspaceChange Workspace `json:"landWorkspaceChange"`
+	}
+	err := g.Do(`mutation LandWorkspaceChange($input: LandWorkspaceChangeInput!) {
+		landWorkspaceChange(input: $input) {`+workspaceFields+`}
+	}`, map[string]interface{}{
+		"input": map[string]interface{}{"workspaceID": workspaceID},
+	}, &res)
+	if err != nil {
+		return Workspace{}, fmt.Errorf("failed to land: %w", err)
+	}
+	return res.LandWorkspaceChange, nil
+}
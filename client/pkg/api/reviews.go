@@ -0,0 +1,83 @@
+package api
+
+import (
+	"fmt"
+)
+
+type ReviewGrade string
+
+const (
+	ReviewGradeApprove   ReviewGrade = "Approve"
+	ReviewGradeReject    ReviewGrade = "Reject"
+	ReviewGradeRequested ReviewGrade = "Requested"
+)
+
+type Review struct {
+	ID          string      `json:"id"`
+	Author      Author      `json:"author"`
+	Grade       ReviewGrade `json:"grade"`
+	CreatedAt   int         `json:"createdAt"`
+	RequestedBy *Author     `json:"requestedBy"`
+}
+
+const reviewFields = `
+	id
+	author { id name email }
+	grade
+	createdAt
+	requestedBy { id name email }
+`
+
+func (g *GraphQLClient) CodebaseMembers(codebaseID string) ([]Author, error) {
+	var res struct {
+		Codebase struct {
+			Members []Author `json:"members"`
+		} `json:"codebase"`
+	}
+	err := g.Do(`query CodebaseMembers($id: ID) {
+		codebase(id: $id) {
+			members { id name email }
+		}
+	}`, map[string]interface{}{"id": codebaseID}, &res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get codebase members: %w", err)
+	}
+	return res.Codebase.Members, nil
+}
+
+// CreateOrUpdateReview reviews the workspace as the authenticated user
+func (g *GraphQLClient) CreateOrUpdateReview(workspaceID string, grade ReviewGrade) (Review, error) {
+	var res struct {
+		CreateOrUpdateReview Review `json:"createOrUpdateReview"`
+	}
+	err := g.Do(`mutation CreateOrUpdateReview($input: CreateReviewInput!) {
+		createOrUpdateReview(input: $input) {`+reviewFields+`}
+	}`, map[string]interface{}{
+		"input": map[string]interface{}{
+			"workspaceID": workspaceID,
+			"grade":       grade,
+		},
+	}, &res)
+	if err != nil {
+		return Review{}, fmt.Errorf("failed to review: %w", err)
+	}
+	return res.CreateOrUpdateReview, nil
+}
+
+func (g *GraphQLClient) RequestReview(workspaceID, userID string) (Review, error) {
+	var res struct {
+		RequestReview Review `json:"requestReview"`
+	}
+	err := g.Do(`mutation RequestReview($input: RequestReviewInput!) {
+		requestReview(input: $input) {`+reviewFields+`}
+	}`, map[string]interface{}{
+		"input": map[string]interface{}{
+			"workspaceID": workspaceID,
+			"userID":      userID,
+		},
+	}, &res)
+	if err != nil {
+		return Review{}, fmt.Errorf("failed to request review: %w", err)
+	}
+	return res.RequestReview, nil
+}
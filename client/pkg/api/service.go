@@ -7,6 +7,10 @@ type SturdyAPI interface {
 	RenewAuth() (RenewAuthResponse, error)
 	GetUser() (GetUserResponse, error)
 	GetIgnores(viewID string) (GetIgnoresResponse, error)
+	WriteFiles(viewID string, files []FileChange) error
+	GetSyncStatus(viewID string) (RebaseStatusResponse, error)
+	StartSync(viewID, workspaceID string) (RebaseStatusResponse, error)
+	ResolveSync(viewID string, files []ResolveFile) (RebaseStatusResponse, error)
 }
 
 type View struct {
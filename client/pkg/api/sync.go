@@ -0,0 +1,86 @@
+package api
+
+import (
+	"fmt"
+)
+
+// The versions that a conflicting file can be resolved with
+const (
+	ResolveVersionWorkspace = "workspace"
+	ResolveVersionTrunk     = "trunk"
+	// ResolveVersionCustom resolves the file with its current contents in the view
+	ResolveVersionCustom = "custom"
+)
+
+type RebaseStatusResponse struct {
+	IsRebasing       bool              `json:"is_rebasing"`
+	HaveConflicts    bool              `json:"have_conflicts"`
+	ConflictingFiles []ConflictingFile `json:"conflicting_files"`
+	CanContinue      bool              `json:"can_continue"`
+}
+
+type ConflictingFile struct {
+	Path string `json:"path"`
+}
+
+type ResolveFile struct {
+	FilePath string `json:"file_path"`
+	Version  string `json:"version"`
+}
+
+func (h *HttpApiClient) GetSyncStatus(viewID string) (RebaseStatusResponse, error) {
+	var res RebaseStatusResponse
+
+	err := Request(
+		h.host,
+		"GET", fmt.Sprintf("/v3/rebase/%s", viewID),
+		h.authToken,
+		nil,
+		&res,
+	)
+	if err != nil {
+		return RebaseStatusResponse{}, fmt.Errorf("failed to get sync status: %w", err)
+	}
+	return res, nil
+}
+
+func (h *HttpApiClient) StartSync(viewID, workspaceID string) (RebaseStatusResponse, error) {
+	type startSyncRequest struct {
+		WorkspaceID string `json:"workspace_id"`
+	}
+
+	var res RebaseStatusResponse
+
+	err := Request(
+		h.host,
+		"POST", fmt.Sprintf("/v3/rebase/%s/start", viewID),
+		h.authToken,
+		startSyncRequest{WorkspaceID: workspaceID},
+		&res,
+	)
+	if err != nil {
+		return RebaseStatusResponse{}, fmt.Errorf("failed to start sync: %w", err)
+	}
+	return res, nil
+}
+
+// ResolveSync resolves all conflicting files and completes the sync
+func (h *HttpApiClient) ResolveSync(viewID string, files []ResolveFile) (RebaseStatusResponse, error) {
+	type resolveRequest struct {
+		Files []ResolveFile `json:"files"`
+	}
+
+	var res RebaseStatusResponse
+
+	err := Request(
+		h.host,
+		"POST", fmt.Sprintf("/v3/rebase/%s/resolve", viewID),
+		h.authToken,
+		resolveRequest{Files: files},
+		&res,
+	)
+	if err != nil {
+		return RebaseStatusResponse{}, fmt.Errorf("failed to resolve conflicts: %w", err)
+	}
+	return res, nil
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"fmt"
+)
+
+// ViewStatus is the sync status of a view, as reported to Sturdy by the sturdy-sync daemon of the view.
+type ViewStatus struct {
+	State         string  `json:"state"`
+	LastError     *string `json:"lastError"`
+	SturdyVersion string  `json:"sturdyVersion"`
+	UpdatedAt     int     `json:"updatedAt"`
+}
+
+// GetViewStatus returns the status of the view, or nil if the view has never reported a status.
+func (g *GraphQLClient) GetViewStatus(viewID string) (*ViewStatus, error) {
+	var res struct {
+		View *struct {
+			Status *ViewStatus `json:"status"`
+		} `json:"view"`
+	}
+	err := g.Do(`query ViewStatus($id: ID!) {
+		view(id: $id) {
+			status {
+				state
+				lastError
+				sturdyVersion
+				updatedAt
+			}
+		}
+	}`, map[string]interface{}{"id": viewID}, &res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get view status: %w", err)
+	}
+	if res.View == nil {
+		return nil, fmt.Errorf("failed to get view status: %w", ErrNotFound)
+	}
+	return res.View.Status, nil
+}
+
+// RepairView re-creates the repository of the view on the server, and restores the workspace that is open on it.
+func (g *GraphQLClient) RepairView(viewID string) error {
+	err := g.Do(`mutation RepairView($id: ID!) {
+		repairView(id: $id) { id }
+	}`, map[string]interface{}{"id": viewID}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to repair view: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"fmt"
+)
+
+type Author struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+}
+
+type Workspace struct {
+	ID               string `json:"id"`
+	Name             string `json:"name"`
+	Author           Author `json:"author"`
+	CreatedAt        int    `json:"createdAt"`
+	LastActivityAt   int    `json:"lastActivityAt"`
+	ArchivedAt       *int   `json:"archivedAt"`
+	DraftDescription string `json:"draftDescription"`
+}
+
+const workspaceFields = `
+	id
+	name
+	author { id name }
+	createdAt
+	lastActivityAt
+	archivedAt
+	draftDescription
+`
+
+func (g *GraphQLClient) ListWorkspaces(codebaseID string, includeArchived bool) ([]Workspace, error) {
+	var res struct {
+		Workspaces []Workspace `json:"workspaces"`
+	}
+	err := g.Do(`query Workspaces($codebaseID: ID!, $includeArchived: Boolean) {
+		workspaces(codebaseID: $codebaseID, includeArchived: $includeArchived) {`+workspaceFields+`}
+	}`, map[string]interface{}{
+		"codebaseID":      codebaseID,
+		"includeArchived": includeArchived,
+	}, &res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	return res.Workspaces, nil
+}
+
+// GetViewWorkspace returns the workspace that is currently open on the view, or nil if there is none.
+func (g *GraphQLClient) GetViewWorkspace(viewID string) (*Workspace, error) {
+	var res struct {
+		View *struct {
+			Workspace *Workspace `json:"workspace"`
+		} `json:"view"`
+	}
+	err := g.Do(`query ViewWorkspace($id: ID!) {
+		view(id: $id) {
+			workspace {`+workspaceFields+`}
+		}
+	}`, map[string]interface{}{"id": viewID}, &res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get view: %w", err)
+	}
+	if res.View == nil {
+		return nil, fmt.Errorf("failed to get view: %w", ErrNotFound)
+	}
+	return res.View.Workspace, nil
+}
+
+func (g *GraphQLClient) CreateWorkspace(codebaseID string) (Workspace, error) {
+	var res struct {
+		CreateWorkspace Workspace `json:"createWorkspace"`
+	}
+	err := g.Do(`mutation CreateWorkspace($input: CreateWorkspaceInput!) {
+		createWorkspace(input: $input) {`+workspaceFields+`}
+	}`, map[string]interface{}{
+		"input": map[string]interface{}{"codebaseID": codebaseID},
+	}, &res)
+	if err != nil {
+		return Workspace{}, fmt.Errorf("failed to create workspace: %w", err)
+	}
+	return res.CreateWorkspace, nil
+}
+
+func (g *GraphQLClient) OpenWorkspaceOnView(viewID, workspaceID string) error {
+	err := g.Do(`mutation OpenWorkspaceOnView($input: OpenWorkspaceOnViewInput!) {
+		openWorkspaceOnView(input: $input) { id }
+	}`, map[string]interface{}{
+		"input": map[string]interface{}{
+			"viewID":      viewID,
+			"workspaceID": workspaceID,
+		},
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to open workspace: %w", err)
+	}
+	return nil
+}
+
+func (g *GraphQLClient) ArchiveWorkspace(workspaceID string) (Workspace, error) {
+	var res struct {
+		ArchiveWorkspace Workspace `json:"archiveWorkspace"`
+	}
+	err := g.Do(`mutation ArchiveWorkspace($id: ID!) {
+		archiveWorkspace(id: $id) {`+workspaceFields+`}
+	}`, map[string]interface{}{"id": workspaceID}, &res)
+	if err != nil {
+		return Workspace{}, fmt.Errorf("failed to archive workspace: %w", err)
+	}
+	return res.ArchiveWorkspace, nil
+}
+
+// UpdateWorkspace updates the name and/or the draft description of the workspace, nil values are left unchanged.
+func (g *GraphQLClient) UpdateWorkspace(workspaceID string, name, draftDescription *string) (Workspace, error) {
+	input := map[string]interface{}{"id": workspaceID}
+	if name != nil {
+		input["name"] = *name
+	}
+	if draftDescription != nil {
+		input["draftDescription"] = *draftDescription
+	}
+	var res struct {
+		UpdateWorkspace Workspace `json:"updateWorkspace"`
+	}
+	err := g.Do(`mutation UpdateWorkspace($input: UpdateWorkspaceInput!) {
+		updateWorkspace(input: $input) {`+workspaceFields+`}
+	}`, map[string]interface{}{"input": input}, &res)
+	if err != nil {
+		return Workspace{}, fmt.Errorf("failed to update workspace: %w", err)
+	}
+	return res.UpdateWorkspace, nil
+}
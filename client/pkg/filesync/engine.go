@@ -0,0 +1,377 @@
+// Package filesync uploads views to Sturdy without the sturdy-sync (mutagen) daemon.
+//
+// Uploading is one-way: the directory of the view is watched for changes, and changed files are uploaded over HTTPS.
+// Changes made to the view on the server (for example when switching workspaces) are not downloaded, and are
+// overwritten if the same files are changed locally.
+package filesync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"getsturdy.com/client/pkg/api"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// retryDelay is how long to wait before retrying to upload changes that failed to upload
+const retryDelay = 5 * time.Second
+
+type API interface {
+	GetIgnores(viewID string) (api.GetIgnoresResponse, error)
+	WriteFiles(viewID string, files []api.FileChange) error
+}
+
+type Engine struct {
+	api    API
+	viewID string
+	root   string
+	logger *log.Logger
+
+	// StatePath is where the uploaded files are saved, to upload the changes that are made while the engine is not
+	// running when it starts. If it's empty, or if nothing has been saved yet, all files are uploaded when starting.
+	StatePath string
+	// Quiet is how long the directory has to be unchanged before changes are uploaded
+	Quiet time.Duration
+	// MaxDelay is the max time that changes are held back while files are still changing
+	MaxDelay time.Duration
+	// MaxBatchSize is the max number of bytes of file contents that is uploaded in a single request
+	MaxBatchSize int64
+	// MaxFileSize is the max size of a file, larger files are not uploaded
+	MaxFileSize int64
+}
+
+func New(client API, viewID, root string, logger *log.Logger) *Engine {
+	return &Engine{
+		api:    client,
+		viewID: viewID,
+		root:   root,
+		logger: logger,
+
+		Quiet:        200 * time.Millisecond,
+		MaxDelay:     5 * time.Second,
+		MaxBatchSize: 16 << 20,
+		MaxFileSize:  32 << 20,
+	}
+}
+
+// Run uploads changes made in the directory until ctx is cancelled.
+//
+// When starting, the files that have been added, changed or deleted since they were last uploaded are uploaded. After
+// that, the directory is watched for changes. Changes are batched, and uploaded once the directory has been quiet, or
+// when they have been held back for MaxDelay. Uploads that fail are retried.
+func (e *Engine) Run(ctx context.Context) error {
+	ignorer, err := e.ignorer()
+	if err != nil {
+		return err
+	}
+
+	synced := make(tree)
+	if e.StatePath != "" {
+		if synced, err = loadState(e.StatePath); err != nil {
+			e.logger.Printf("failed to load the uploaded files of %s, uploading all files: %s", e.root, err)
+			synced = make(tree)
+		}
+	}
+	forgetIgnored(synced, ignorer)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to watch %s: %w", e.root, err)
+	}
+	defer watcher.Close()
+
+	var (
+		// dirty are the slash separated paths that have changed since the last upload
+		dirty = make(map[string]struct{})
+		// rescan is set when all files have to be compared with the uploaded files, which is done when starting, and
+		// when the ignores have changed or events may have been missed
+		rescan        = true
+		reloadIgnores = false
+		pendingSince  time.Time
+		timer         = time.NewTimer(0)
+	)
+	defer timer.Stop()
+
+	schedule := func(d time.Duration) {
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(d)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-watcher.Events:
+			rel, err := filepath.Rel(e.root, event.Name)
+			if err != nil || rel == "." {
+				continue
+			}
+			dirty[filepath.ToSlash(rel)] = struct{}{}
+			if pendingSince.IsZero() {
+				pendingSince = time.Now()
+			}
+			// Wait for the directory to be quiet, to upload changes from builds, checkouts, etc. together
+			wait := e.Quiet
+			if remaining := e.MaxDelay - time.Since(pendingSince); remaining < wait {
+				wait = remaining
+			}
+			schedule(wait)
+			continue
+		case err := <-watcher.Errors:
+			// Events are dropped if too many of them are queued
+			e.logger.Printf("failed to watch %s, rescanning: %s", e.root, err)
+			rescan = true
+			schedule(e.Quiet)
+			continue
+		case <-timer.C:
+		}
+
+		if reloadIgnores {
+			if ignorer, err = e.ignorer(); err != nil {
+				e.logger.Printf("failed to update ignores, retrying: %s", err)
+				schedule(retryDelay)
+				continue
+			}
+			// Files that are ignored now are no longer tracked, and are not deleted from the view. Files that are not
+			// ignored anymore are uploaded by the rescan.
+			forgetIgnored(synced, ignorer)
+			e.saveState(synced)
+			reloadIgnores, rescan = false, true
+		}
+
+		var (
+			current          tree
+			changed, deleted []string
+		)
+		if rescan {
+			current, err = scanDir(e.root, ".", ignorer, e.watch(watcher))
+			changed, deleted = diffTrees(synced, current)
+		} else {
+			current, changed, deleted, err = e.collect(watcher, ignorer, synced, dirty)
+		}
+		if err != nil {
+			e.logger.Printf("failed to scan %s, retrying: %s", e.root, err)
+			schedule(retryDelay)
+			continue
+		}
+
+		if len(changed) > 0 || len(deleted) > 0 {
+			uploaded, err := e.upload(synced, current, changed, deleted)
+			if uploaded > 0 {
+				e.logger.Printf("⬆️  Uploaded %d %s in %s", uploaded, plural(uploaded, "change", "changes"), e.root)
+			}
+			e.saveState(synced)
+			if err != nil {
+				e.logger.Printf("failed to upload %s, retrying: %s", e.root, err)
+				schedule(retryDelay)
+				continue
+			}
+		}
+
+		dirty = make(map[string]struct{})
+		rescan = false
+		pendingSince = time.Time{}
+
+		if containsGitignore(changed) || containsGitignore(deleted) {
+			reloadIgnores = true
+			schedule(0)
+		}
+	}
+}
+
+// collect returns the files in the dirty paths that have been added, changed or deleted since they were uploaded,
+// and the info of the files that are added or changed. New directories are watched.
+func (e *Engine) collect(watcher *fsnotify.Watcher, ignorer *Ignorer, synced tree, dirty map[string]struct{}) (current tree, changed, deleted []string, err error) {
+	current = make(tree)
+	// The uploaded files in the dirty paths, the ones that are not in current anymore are deleted
+	candidates := make(map[string]struct{})
+
+	for p := range dirty {
+		if ignoredParent(ignorer, p) {
+			continue
+		}
+
+		info, err := os.Lstat(filepath.Join(e.root, filepath.FromSlash(p)))
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			// Removed, the uploaded files in it are deleted
+		case err != nil:
+			return nil, nil, nil, err
+		case ignorer.Ignored(p, info.IsDir()):
+			// Not uploaded, and replaces the uploaded file if there was one
+		case info.IsDir():
+			files, err := scanDir(e.root, p, ignorer, e.watch(watcher))
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			for f, fi := range files {
+				current[f] = fi
+			}
+		case info.Mode().IsRegular():
+			current[p] = newFileInfo(info)
+		default:
+			// Symlinks and other special files are not uploaded
+		}
+
+		for f := range synced {
+			if f == p || strings.HasPrefix(f, p+"/") {
+				candidates[f] = struct{}{}
+			}
+		}
+	}
+
+	for f, info := range current {
+		if old, ok := synced[f]; !ok || !old.equal(info) {
+			changed = append(changed, f)
+		}
+	}
+	for f := range candidates {
+		if _, ok := current[f]; !ok {
+			deleted = append(deleted, f)
+		}
+	}
+	sort.Strings(changed)
+	sort.Strings(deleted)
+	return current, changed, deleted, nil
+}
+
+// watch returns a function that watches a slash separated directory in the root
+func (e *Engine) watch(watcher *fsnotify.Watcher) func(dir string) error {
+	return func(dir string) error {
+		err := watcher.Add(filepath.Join(e.root, filepath.FromSlash(dir)))
+		// Directories can be removed before they are watched
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to watch %s: %w", dir, err)
+		}
+		return nil
+	}
+}
+
+func (e *Engine) saveState(synced tree) {
+	if e.StatePath == "" {
+		return
+	}
+	if err := saveState(e.StatePath, synced); err != nil {
+		e.logger.Printf("failed to save the uploaded files of %s: %s", e.root, err)
+	}
+}
+
+func (e *Engine) ignorer() (*Ignorer, error) {
+	ignores, err := e.api.GetIgnores(e.viewID)
+	if err != nil {
+		return nil, err
+	}
+	return NewIgnorer(append(ignores.Paths, DefaultIgnores...)), nil
+}
+
+// upload uploads the changes in batches, and updates synced with every batch that was uploaded. The number of changes
+// that were uploaded is returned.
+func (e *Engine) upload(synced, current tree, changed, deleted []string) (int, error) {
+	var (
+		batch     []api.FileChange
+		batchSize int64
+		uploaded  int
+	)
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := e.api.WriteFiles(e.viewID, batch); err != nil {
+			return err
+		}
+		for _, f := range batch {
+			if f.Deleted {
+				delete(synced, f.Path)
+			} else {
+				synced[f.Path] = current[f.Path]
+			}
+		}
+		uploaded += len(batch)
+		batch, batchSize = nil, 0
+		return nil
+	}
+
+	for _, p := range deleted {
+		batch = append(batch, api.FileChange{Path: p, Deleted: true})
+	}
+
+	for _, p := range changed {
+		info := current[p]
+		if info.size > e.MaxFileSize {
+			e.logger.Printf("⚠️  %s is too large to be uploaded (%d bytes)", path.Join(e.root, p), info.size)
+			synced[p] = info
+			continue
+		}
+
+		contents, err := os.ReadFile(filepath.Join(e.root, filepath.FromSlash(p)))
+		if errors.Is(err, fs.ErrNotExist) {
+			// Removed since it was changed, it's deleted with the next upload if it was uploaded before
+			continue
+		} else if err != nil {
+			return uploaded, fmt.Errorf("failed to read %s: %w", p, err)
+		}
+
+		if batchSize+int64(len(contents)) > e.MaxBatchSize {
+			if err := flush(); err != nil {
+				return uploaded, err
+			}
+		}
+		batch = append(batch, api.FileChange{Path: p, Contents: contents, Executable: info.executable})
+		batchSize += int64(len(contents))
+	}
+
+	if err := flush(); err != nil {
+		return uploaded, err
+	}
+	return uploaded, nil
+}
+
+func containsGitignore(paths []string) bool {
+	for _, p := range paths {
+		if path.Base(p) == ".gitignore" {
+			return true
+		}
+	}
+	return false
+}
+
+func ignoredParent(ignorer *Ignorer, p string) bool {
+	for dir := path.Dir(p); dir != "."; dir = path.Dir(dir) {
+		if ignorer.Ignored(dir, true) {
+			return true
+		}
+	}
+	return false
+}
+
+// forgetIgnored removes the files that are ignored from t
+func forgetIgnored(t tree, ignorer *Ignorer) {
+	for p := range t {
+		if ignorer.Ignored(p, false) || ignoredParent(ignorer, p) {
+			delete(t, p)
+		}
+	}
+}
+
+func plural(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
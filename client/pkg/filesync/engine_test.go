@@ -0,0 +1,163 @@
+package filesync
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"getsturdy.com/client/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAPI struct {
+	mu      sync.Mutex
+	ignores []string
+	batches [][]api.FileChange
+}
+
+func (f *fakeAPI) GetIgnores(viewID string) (api.GetIgnoresResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return api.GetIgnoresResponse{Paths: f.ignores}, nil
+}
+
+func (f *fakeAPI) WriteFiles(viewID string, files []api.FileChange) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.batches = append(f.batches, files)
+	return nil
+}
+
+func (f *fakeAPI) waitForBatches(t *testing.T, n int) [][]api.FileChange {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		f.mu.Lock()
+		if len(f.batches) >= n {
+			batches := f.batches
+			f.mu.Unlock()
+			return batches
+		}
+		f.mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d batches", n)
+	return nil
+}
+
+func TestEngine(t *testing.T) {
+	root := t.TempDir()
+	write := func(p, contents string) {
+		full := filepath.Join(root, filepath.FromSlash(p))
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0o755))
+		require.NoError(t, ioutil.WriteFile(full, []byte(contents), 0o644))
+	}
+
+	write("existing.txt", "existing")
+	write("removed.txt", "removed")
+	write("build/output", "output")
+
+	// existing.txt and removed.txt were uploaded when the engine last ran
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	uploaded, err := scan(root, NewIgnorer([]string{"build/"}))
+	require.NoError(t, err)
+	require.NoError(t, saveState(statePath, uploaded))
+
+	// changes made while the engine is not running are uploaded when it starts
+	require.NoError(t, os.Remove(filepath.Join(root, "removed.txt")))
+	write("offline.txt", "offline")
+
+	fake := &fakeAPI{ignores: []string{"build/"}}
+	engine := New(fake, "view-id", root, log.New(ioutil.Discard, "", 0))
+	engine.StatePath = statePath
+	engine.Quiet = 20 * time.Millisecond
+	engine.MaxDelay = 100 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error)
+	go func() { done <- engine.Run(ctx) }()
+
+	batches := fake.waitForBatches(t, 1)
+	assert.Equal(t, []api.FileChange{{Path: "removed.txt", Deleted: true}, {Path: "offline.txt", Contents: []byte("offline")}}, batches[0])
+
+	write("src/main.go", "package main")
+	write("build/other", "ignored")
+	batches = fake.waitForBatches(t, 2)
+	assert.Equal(t, []api.FileChange{{Path: "src/main.go", Contents: []byte("package main")}}, batches[1])
+
+	require.NoError(t, os.Remove(filepath.Join(root, "existing.txt")))
+	batches = fake.waitForBatches(t, 3)
+	assert.Equal(t, []api.FileChange{{Path: "existing.txt", Deleted: true}}, batches[2])
+
+	// the files in directories that are removed are deleted
+	write("lib/a.go", "package lib")
+	batches = fake.waitForBatches(t, 4)
+	assert.Equal(t, []api.FileChange{{Path: "lib/a.go", Contents: []byte("package lib")}}, batches[3])
+	require.NoError(t, os.RemoveAll(filepath.Join(root, "lib")))
+	batches = fake.waitForBatches(t, 5)
+	assert.Equal(t, []api.FileChange{{Path: "lib/a.go", Deleted: true}}, batches[4])
+
+	// files that become ignored are not deleted
+	fake.mu.Lock()
+	fake.ignores = []string{"build/", "src/"}
+	fake.mu.Unlock()
+	write(".gitignore", "build/\nsrc/\n")
+	batches = fake.waitForBatches(t, 6)
+	assert.Equal(t, []api.FileChange{{Path: ".gitignore", Contents: []byte("build/\nsrc/\n")}}, batches[5])
+
+	write("src/other.go", "package main")
+	write("new.txt", "new")
+	batches = fake.waitForBatches(t, 7)
+	assert.Equal(t, []api.FileChange{{Path: "new.txt", Contents: []byte("new")}}, batches[6])
+
+	cancel()
+	require.NoError(t, <-done)
+
+	// the uploaded files are saved
+	saved, err := loadState(statePath)
+	require.NoError(t, err)
+	current, err := scan(root, NewIgnorer([]string{"build/", "src/"}))
+	require.NoError(t, err)
+	changed, deleted := diffTrees(saved, current)
+	assert.Empty(t, changed)
+	assert.Empty(t, deleted)
+	assert.Len(t, saved, 3)
+}
+
+func TestEngineBatches(t *testing.T) {
+	root := t.TempDir()
+	fake := &fakeAPI{}
+	engine := New(fake, "view-id", root, log.New(ioutil.Discard, "", 0))
+	engine.MaxBatchSize = 10
+	engine.MaxFileSize = 20
+
+	for name, contents := range map[string]string{"a": "123456", "b": "123456", "c": "1234", "large": "123456789012345678901"} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(root, name), []byte(contents), 0o644))
+	}
+
+	current, err := scan(root, NewIgnorer(nil))
+	require.NoError(t, err)
+	synced := tree{"removed": fileInfo{}}
+	changed, deleted := diffTrees(synced, current)
+
+	uploaded, err := engine.upload(synced, current, changed, deleted)
+	require.NoError(t, err)
+	assert.Equal(t, 4, uploaded)
+	assert.Equal(t, [][]api.FileChange{
+		{{Path: "removed", Deleted: true}, {Path: "a", Contents: []byte("123456")}},
+		{{Path: "b", Contents: []byte("123456")}, {Path: "c", Contents: []byte("1234")}},
+	}, fake.batches)
+
+	// large files are skipped, but are not retried until they change
+	changed, deleted = diffTrees(synced, current)
+	assert.Empty(t, changed)
+	assert.Empty(t, deleted)
+}
@@ -0,0 +1,109 @@
+package filesync
+
+import (
+	"path"
+	"strings"
+)
+
+// DefaultIgnores are never synced, in addition to the ignores of the view
+var DefaultIgnores = []string{"node_modules", ".DS_Store", "*.swp"}
+
+type pattern struct {
+	glob     string
+	anchored bool
+	dirOnly  bool
+	negated  bool
+}
+
+// Ignorer matches paths against the ignore patterns returned by the API, which are based on the .gitignore files in
+// the view. The .git directory is always ignored.
+type Ignorer struct {
+	patterns []pattern
+}
+
+func NewIgnorer(patterns []string) *Ignorer {
+	i := &Ignorer{}
+	for _, raw := range patterns {
+		if p, ok := parsePattern(raw); ok {
+			i.patterns = append(i.patterns, p)
+		}
+	}
+	return i
+}
+
+// BadPatterns returns the patterns that are malformed, they never match any path
+func BadPatterns(patterns []string) []string {
+	var bad []string
+	for _, raw := range patterns {
+		p, ok := parsePattern(raw)
+		if !ok {
+			continue
+		}
+		if _, err := path.Match(p.glob, ""); err != nil {
+			bad = append(bad, raw)
+		}
+	}
+	return bad
+}
+
+// parsePattern parses a pattern in the .gitignore format, ok is false if the pattern is empty
+func parsePattern(raw string) (p pattern, ok bool) {
+	p.glob = strings.TrimSpace(raw)
+	if strings.HasPrefix(p.glob, "!") {
+		p.negated = true
+		p.glob = p.glob[1:]
+	}
+	if strings.HasSuffix(p.glob, "/") {
+		p.dirOnly = true
+		p.glob = strings.TrimRight(p.glob, "/")
+	}
+	if strings.HasPrefix(p.glob, "**/") {
+		p.glob = strings.TrimPrefix(p.glob, "**/")
+	} else if strings.Contains(p.glob, "/") {
+		// Like in .gitignore, patterns with a slash in them are relative to the root
+		p.anchored = true
+		p.glob = strings.TrimPrefix(p.glob, "/")
+	}
+	return p, p.glob != ""
+}
+
+// Ignored returns true if the file or directory at the slash separated path p, relative to the root, should not be
+// synced. The last matching pattern decides if the path is ignored, to support negated patterns.
+func (i *Ignorer) Ignored(p string, isDir bool) bool {
+	if p == ".git" || strings.HasPrefix(p, ".git/") {
+		return true
+	}
+
+	ignored := false
+	for _, pat := range i.patterns {
+		if pat.dirOnly && !isDir {
+			continue
+		}
+		if pat.matches(p) {
+			ignored = !pat.negated
+		}
+	}
+	return ignored
+}
+
+func (p pattern) matches(name string) bool {
+	if p.anchored {
+		ok, _ := path.Match(p.glob, name)
+		return ok
+	}
+	if !strings.Contains(p.glob, "/") {
+		ok, _ := path.Match(p.glob, path.Base(name))
+		return ok
+	}
+	// "**/a/b" matches a/b in any directory
+	for {
+		if ok, _ := path.Match(p.glob, name); ok {
+			return true
+		}
+		idx := strings.Index(name, "/")
+		if idx < 0 {
+			return false
+		}
+		name = name[idx+1:]
+	}
+}
@@ -0,0 +1,50 @@
+package filesync
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIgnorer(t *testing.T) {
+	ignorer := NewIgnorer([]string{
+		"*.log",
+		"!important.log",
+		"build/",
+		"/docs/generated",
+		"**/cache/tmp",
+		"vendor/*.zip",
+	})
+
+	cases := []struct {
+		path    string
+		isDir   bool
+		ignored bool
+	}{
+		{path: ".git", isDir: true, ignored: true},
+		{path: ".git/config", ignored: true},
+		{path: ".gitignore", ignored: false},
+		{path: "debug.log", ignored: true},
+		{path: "src/debug.log", ignored: true},
+		{path: "src/important.log", ignored: false},
+		{path: "build", isDir: true, ignored: true},
+		{path: "src/build", isDir: true, ignored: true},
+		{path: "build", isDir: false, ignored: false},
+		{path: "docs/generated", isDir: true, ignored: true},
+		{path: "src/docs/generated", isDir: true, ignored: false},
+		{path: "a/b/cache/tmp", isDir: true, ignored: true},
+		{path: "cache/tmp", isDir: true, ignored: true},
+		{path: "vendor/deps.zip", ignored: true},
+		{path: "src/vendor/deps.zip", ignored: false},
+		{path: "main.go", ignored: false},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.ignored, ignorer.Ignored(tc.path, tc.isDir), tc.path)
+	}
+}
+
+func TestBadPatterns(t *testing.T) {
+	bad := BadPatterns([]string{"*.log", "", "build/", "[abc", "!src/[z-a", "docs/*.md"})
+	assert.Equal(t, []string{"[abc", "!src/[z-a"}, bad)
+}
@@ -0,0 +1,107 @@
+package filesync
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+type fileInfo struct {
+	size       int64
+	modTime    time.Time
+	executable bool
+}
+
+func (f fileInfo) equal(o fileInfo) bool {
+	return f.size == o.size && f.modTime.Equal(o.modTime) && f.executable == o.executable
+}
+
+// tree maps the slash separated paths of all synced files in a directory to their info
+type tree map[string]fileInfo
+
+// scan lists all regular files in root that are not ignored
+func scan(root string, ignorer *Ignorer) (tree, error) {
+	return scanDir(root, ".", ignorer, nil)
+}
+
+// scanDir lists all regular files in the slash separated directory dir in root that are not ignored. onDir is called
+// with every directory that is not ignored, including dir.
+func scanDir(root, dir string, ignorer *Ignorer, onDir func(dir string) error) (tree, error) {
+	t := make(tree)
+	start := filepath.Join(root, filepath.FromSlash(dir))
+	err := filepath.WalkDir(start, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Files can be removed while walking
+			if errors.Is(err, fs.ErrNotExist) && p != root {
+				return nil
+			}
+			return err
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if rel != "." && ignorer.Ignored(rel, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if onDir != nil {
+				if err := onDir(rel); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		// Symlinks and other special files are not synced
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		t[rel] = newFileInfo(info)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func newFileInfo(info fs.FileInfo) fileInfo {
+	return fileInfo{
+		size:       info.Size(),
+		modTime:    info.ModTime(),
+		executable: info.Mode()&0o111 != 0,
+	}
+}
+
+// diffTrees returns the sorted paths that have been added or modified in b, and the sorted paths that are in a but
+// not in b
+func diffTrees(a, b tree) (changed, deleted []string) {
+	for p, info := range b {
+		if old, ok := a[p]; !ok || !old.equal(info) {
+			changed = append(changed, p)
+		}
+	}
+	for p := range a {
+		if _, ok := b[p]; !ok {
+			deleted = append(deleted, p)
+		}
+	}
+	sort.Strings(changed)
+	sort.Strings(deleted)
+	return changed, deleted
+}
@@ -0,0 +1,65 @@
+package filesync
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type savedFile struct {
+	Size       int64     `json:"size"`
+	ModTime    time.Time `json:"mod_time"`
+	Executable bool      `json:"executable"`
+}
+
+// loadState reads the files that were uploaded when the engine last ran. An empty tree is returned if there is no
+// saved state.
+func loadState(path string) (tree, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return make(tree), nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var files map[string]savedFile
+	if err := json.Unmarshal(data, &files); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	t := make(tree, len(files))
+	for p, f := range files {
+		t[p] = fileInfo{size: f.Size, modTime: f.ModTime, executable: f.Executable}
+	}
+	return t, nil
+}
+
+// saveState saves the files that have been uploaded, the file is replaced atomically
+func saveState(path string, t tree) error {
+	files := make(map[string]savedFile, len(t))
+	for p, info := range t {
+		files[p] = savedFile{Size: info.size, ModTime: info.modTime, Executable: info.executable}
+	}
+	data, err := json.Marshal(files)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}